@@ -13,20 +13,19 @@
 package alloydb
 
 import (
-	"net/http"
 	"strings"
 	"sync"
-	// For pprof
-	_ "net/http/pprof"
 
 	"github.com/juju/errors"
 	"github.com/ngaut/log"
+	"github.com/Dong-Chan/alloydb/column"
 	"github.com/Dong-Chan/alloydb/context"
 	"github.com/Dong-Chan/alloydb/domain"
 	"github.com/Dong-Chan/alloydb/expression"
 	"github.com/Dong-Chan/alloydb/expression/expressions"
 	"github.com/Dong-Chan/alloydb/field"
 	"github.com/Dong-Chan/alloydb/kv"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
 	"github.com/Dong-Chan/alloydb/parser"
 	"github.com/Dong-Chan/alloydb/rset"
 	"github.com/Dong-Chan/alloydb/sessionctx/variable"
@@ -77,8 +76,14 @@ var (
 
 // Compile is safe for concurrent use by multiple goroutines.
 func Compile(src string) ([]stmt.Statement, error) {
+	return CompileWithSQLMode(src, mysql.ModeNone)
+}
+
+// CompileWithSQLMode is like Compile, the lexical sql modes like ANSI_QUOTES in mode are used.
+func CompileWithSQLMode(src string, mode mysql.SQLMode) ([]stmt.Statement, error) {
 	log.Debug("compiling", src)
 	l := parser.NewLexer(src)
+	l.SetSQLMode(mode)
 	if parser.YYParse(l) != 0 {
 		log.Warnf("compiling %s, error: %v", src, l.Errors()[0])
 		return nil, errors.Trace(l.Errors()[0])
@@ -90,9 +95,15 @@ func Compile(src string) ([]stmt.Statement, error) {
 // CompilePrepare compiles prepared statement, allows placeholder as expr.
 // The return values are compiled statement, parameter list and error.
 func CompilePrepare(src string) (stmt.Statement, []*expressions.ParamMarker, error) {
+	return CompilePrepareWithSQLMode(src, mysql.ModeNone)
+}
+
+// CompilePrepareWithSQLMode is like CompilePrepare, the lexical sql modes like ANSI_QUOTES in mode are used.
+func CompilePrepareWithSQLMode(src string, mode mysql.SQLMode) (stmt.Statement, []*expressions.ParamMarker, error) {
 	log.Debug("compiling prepared", src)
 	l := parser.NewLexer(src)
 	l.SetPrepare()
+	l.SetSQLMode(mode)
 	if parser.YYParse(l) != 0 {
 		log.Errorf("compiling %s\n, error: %v", src, l.Errors()[0])
 		return nil, nil, errors.Trace(l.Errors()[0])
@@ -131,6 +142,15 @@ func runStmt(ctx context.Context, s stmt.Statement, args ...interface{}) (rset.R
 	var rs rset.Recordset
 	// before every execution, we must clear affectedrows.
 	variable.GetSessionVars(ctx).SetAffectedRows(0)
+	variable.GetSessionVars(ctx).ResetWarnings(keepWarnings(s))
+	if err = checkPrivileges(ctx, s); err != nil {
+		// Don't keep the transaction reading the privileges, the statement changes nothing.
+		if variable.IsAutocommit(ctx) {
+			ctx.FinishTxn(true)
+		}
+		variable.AppendError(ctx, err)
+		return nil, errors.Trace(err)
+	}
 	switch s.(type) {
 	case *stmts.PreparedStmt:
 		ps := s.(*stmts.PreparedStmt)
@@ -156,9 +176,24 @@ func runStmt(ctx context.Context, s stmt.Statement, args ...interface{}) (rset.R
 	if err == nil && (s.IsDDL() || variable.IsAutocommit(ctx)) {
 		err = ctx.FinishTxn(false)
 	}
+	if err != nil {
+		variable.AppendError(ctx, err)
+	}
 	return rs, errors.Trace(err)
 }
 
+// keepWarnings checks whether the warnings of the last statement should be kept for s,
+// so SHOW WARNINGS and SELECT @@warning_count can read them.
+func keepWarnings(s stmt.Statement) bool {
+	switch x := s.(type) {
+	case *stmts.ShowStmt:
+		return x.Target == stmt.ShowWarnings || x.Target == stmt.ShowErrors
+	case *stmts.SelectStmt:
+		return x.From == nil
+	}
+	return false
+}
+
 func runExecute(ctx context.Context, es *stmts.ExecuteStmt, args ...interface{}) (rset.Recordset, error) {
 	// TODO: if the args are passed by binary protocol, we should set execute args from arg parameters into es.
 	// Then call es.Exec(ctx)
@@ -182,7 +217,7 @@ func runPreparedStmt(ctx context.Context, ps *stmts.PreparedStmt) (rset.Recordse
 		return nil, nil
 	}
 	// compile SQLText
-	stmt, params, err := CompilePrepare(SQLText)
+	stmt, params, err := CompilePrepareWithSQLMode(SQLText, variable.GetSQLMode(ctx))
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -257,6 +292,8 @@ func init() {
 	RegisterLocalStore("boltdb", boltdb.Driver{})
 
 	table.TableFromMeta = tables.TableFromMeta
-
-	go http.ListenAndServe(":8888", nil)
+	column.SQLModeGetter = variable.GetSQLMode
+	column.WarningAppender = variable.AppendWarning
+	column.TimeZoneGetter = variable.GetTimeZone
+	variable.RegisterStatistics(processStats{})
 }
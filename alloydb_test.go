@@ -16,17 +16,27 @@ import (
 	"database/sql"
 	"flag"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"runtime"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/juju/errors"
 	"github.com/ngaut/log"
 	. "github.com/pingcap/check"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/domain"
 	"github.com/Dong-Chan/alloydb/kv"
 	mysql "github.com/Dong-Chan/alloydb/mysqldef"
 	"github.com/Dong-Chan/alloydb/rset"
+	"github.com/Dong-Chan/alloydb/sessionctx"
 	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/util/auth"
 )
 
 var store = flag.String("store", "memory", "registered store name, [memory, goleveldb, boltdb]")
@@ -617,3 +627,1357 @@ func match(c *C, row []interface{}, expected ...interface{}) {
 		c.Assert(got, Equals, need)
 	}
 }
+
+func (s *testSessionSuite) TestOrderByIndex(c *C) {
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists t")
+	mustExecSQL(c, se, "create table t (c1 int, c2 int, index idx_c1(c1))")
+	mustExecSQL(c, se, "insert t values (3, 30), (1, 10), (null, 0), (2, 20), (5, 50), (4, 40)")
+
+	rs := mustExecSQL(c, se, "select c1 as a, c2 from t order by a limit 3")
+	rows, err := rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 3)
+	match(c, rows[0], nil, 0)
+	match(c, rows[1], 1, 10)
+	match(c, rows[2], 2, 20)
+
+	rs = mustExecSQL(c, se, "select c2 from t where c1 > 1 order by c1 desc limit 2")
+	rows, err = rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 2)
+	match(c, rows[0], 50)
+	match(c, rows[1], 40)
+
+	// no index on c2, use top-N sort.
+	rs = mustExecSQL(c, se, "select c1 from t order by c2 desc limit 2, 2")
+	rows, err = rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 2)
+	match(c, rows[0], 3)
+	match(c, rows[1], 2)
+
+	// The descending index scan is split into chunks, the equal values cross the chunks.
+	mustExecSQL(c, se, "drop table if exists t")
+	mustExecSQL(c, se, "create table t (c1 int, c2 int, index idx_c1(c1))")
+	const n = 2500
+	for i := 0; i < n; i += 100 {
+		var values []string
+		for j := i; j < i+100; j++ {
+			values = append(values, fmt.Sprintf("(%d, %d)", j%700, j))
+		}
+		mustExecSQL(c, se, "insert t values "+strings.Join(values, ", "))
+	}
+
+	rs = mustExecSQL(c, se, "select c1, c2 from t where c1 >= 0 order by c1 desc")
+	rows, err = rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, n)
+	seen := map[int32]bool{}
+	for i, row := range rows {
+		if i > 0 {
+			c.Assert(row[0].(int32) <= rows[i-1][0].(int32), IsTrue)
+		}
+		c.Assert(row[1].(int32)%700, Equals, row[0].(int32))
+		seen[row[1].(int32)] = true
+	}
+	c.Assert(seen, HasLen, n)
+
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestSubQuery(c *C) {
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists t1")
+	mustExecSQL(c, se, "drop table if exists t2")
+	mustExecSQL(c, se, "create table t1 (id int, c int)")
+	mustExecSQL(c, se, "create table t2 (id int, c int)")
+	mustExecSQL(c, se, "insert t1 values (1, 10), (2, 20), (3, 30), (4, null)")
+	mustExecSQL(c, se, "insert t2 values (1, 10), (1, 11), (3, null), (5, 50)")
+
+	cases := []struct {
+		sql  string
+		rows [][]interface{}
+	}{
+		// correlated scalar subquery
+		{"select id, (select max(c) from t2 where t2.id = t1.id) from t1 order by id",
+			[][]interface{}{{1, 11}, {2, nil}, {3, nil}, {4, nil}}},
+		{"select id, (select t1.c + c from t2 where t2.id = t1.id and t2.c = 10) from t1 where id = 1",
+			[][]interface{}{{1, 20}}},
+		// exists
+		{"select id from t1 where exists (select * from t2 where t2.id = t1.id) order by id",
+			[][]interface{}{{1}, {3}}},
+		{"select id from t1 where not exists (select * from t2 where t1.id = t2.id) order by id",
+			[][]interface{}{{2}, {4}}},
+		{"select id from t1 where id > 1 and exists (select 1 from t2 where t2.id = t1.id and t2.c is null)",
+			[][]interface{}{{3}}},
+		{"select id from t1 where exists (select 1 from t2 where t2.c > t1.c) order by id",
+			[][]interface{}{{1}, {2}, {3}}},
+		{"select id from t1 where exists (select * from t2 where t2.id > 10)", nil},
+		// in
+		{"select id from t1 where c in (select c from t2) order by id",
+			[][]interface{}{{1}}},
+		{"select id from t1 where c not in (select c from t2) order by id", nil},
+		{"select id from t1 where c not in (select c from t2 where c is not null) order by id",
+			[][]interface{}{{2}, {3}}},
+		{"select id from t1 where c not in (select c from t2 where id > 10) order by id",
+			[][]interface{}{{1}, {2}, {3}, {4}}},
+		{"select id from t1 where id in (select id from t2 where t2.c = t1.c + 1) order by id",
+			[][]interface{}{{1}}},
+		{"select id from t1 where c not in (select c from t2 where t2.id = t1.id) order by id",
+			[][]interface{}{{2}}},
+		// any and all
+		{"select id from t1 where c > any (select c from t2 where c is not null) order by id",
+			[][]interface{}{{2}, {3}}},
+		{"select id from t1 where c = some (select c from t2) order by id",
+			[][]interface{}{{1}}},
+		{"select id from t1 where c >= all (select c from t2 where id = 1) order by id",
+			[][]interface{}{{2}, {3}}},
+		{"select id from t1 where c > all (select c from t2 where id > 10) order by id",
+			[][]interface{}{{1}, {2}, {3}, {4}}},
+		{"select id from t1 where c <> all (select c from t2 where t2.id = t1.id) order by id",
+			[][]interface{}{{2}, {4}}},
+	}
+	for _, ca := range cases {
+		rs := mustExecSQL(c, se, ca.sql)
+		rows, err := rs.Rows(-1, 0)
+		c.Assert(err, IsNil)
+		c.Assert(rows, HasLen, len(ca.rows), Commentf("%s", ca.sql))
+		for i, row := range rows {
+			match(c, row, ca.rows[i]...)
+		}
+	}
+
+	// The correlated exists is planned as semi join.
+	rs := mustExecSQL(c, se, "explain select id from t1 where not exists (select * from t2 where t2.id = t1.id)")
+	rows, err := rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	var plan []string
+	for _, row := range rows {
+		plan = append(plan, fmt.Sprintf("%v", row[0]))
+	}
+	c.Assert(strings.Join(plan, "\n"), Matches, "(?s).*Anti semi join on.*")
+
+	// Subquery returns more than 1 row.
+	rs = mustExecSQL(c, se, "select (select c from t2 where t2.id = t1.id) from t1")
+	_, err = rs.Rows(-1, 0)
+	c.Assert(err, NotNil)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErSubqueryNo1Row))
+
+	// Operand should contain 1 column.
+	rs = mustExecSQL(c, se, "select id from t1 where c = (select id, c from t2 where id = 5)")
+	_, err = rs.Rows(-1, 0)
+	c.Assert(err, NotNil)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErOperandColumns))
+
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestCommonTableExpr(c *C) {
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists emp")
+	mustExecSQL(c, se, "create table emp (id int, name varchar(20), manager int)")
+	mustExecSQL(c, se, `insert emp values (1, "a", null), (2, "b", 1), (3, "c", 1), (4, "d", 2), (5, "e", 4)`)
+
+	cases := []struct {
+		sql  string
+		rows [][]interface{}
+	}{
+		// referenced several times
+		{"with m as (select id, manager from emp where manager is not null) select m1.id, m2.id from m m1, m m2 where m1.manager = m2.id order by m1.id",
+			[][]interface{}{{4, 2}, {5, 4}}},
+		// column names and the CTEs defined before
+		{"with m (mid) as (select manager from emp), n (nid) as (select distinct mid from m where mid is not null) select count(*) from n",
+			[][]interface{}{{3}}},
+		// shadows the table
+		{"with emp as (select 1 as id) select * from emp", [][]interface{}{{1}}},
+		{"select count(*) from emp", [][]interface{}{{5}}},
+		// recursive
+		{"with recursive cnt (n) as (select 1 union all select n + 1 from cnt where n < 5) select n from cnt",
+			[][]interface{}{{1}, {2}, {3}, {4}, {5}}},
+		{"with recursive sub as (select id, name, 0 as depth from emp where id = 2 union all select emp.id, emp.name, sub.depth + 1 from sub, emp where emp.manager = sub.id) select name, depth from sub order by name",
+			[][]interface{}{{"b", 0}, {"d", 1}, {"e", 2}}},
+		// union distinct stops at the rows produced before
+		{"with recursive c (n) as (select 1 union select (n + 1) % 3 from c) select n from c order by n",
+			[][]interface{}{{0}, {1}, {2}}},
+		// used in subquery
+		{"with recursive up (id) as (select manager from emp where id = 5 union all select manager from emp, up where emp.id = up.id and manager is not null) select name from emp where id in (select id from up) order by name",
+			[][]interface{}{{"a"}, {"b"}, {"d"}}},
+	}
+	for _, ca := range cases {
+		rs := mustExecSQL(c, se, ca.sql)
+		rows, err := rs.Rows(-1, 0)
+		c.Assert(err, IsNil, Commentf("%s", ca.sql))
+		c.Assert(rows, HasLen, len(ca.rows), Commentf("%s", ca.sql))
+		for i, row := range rows {
+			match(c, row, ca.rows[i]...)
+		}
+	}
+
+	rs := mustExecSQL(c, se, "explain with recursive cnt (n) as (select 1 union all select n + 1 from cnt where n < 5) select n from cnt")
+	rows, err := rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	var plan []string
+	for _, row := range rows {
+		plan = append(plan, fmt.Sprintf("%v", row[0]))
+	}
+	c.Assert(strings.Join(plan, "\n"), Matches, "(?s).*Recursive CTE cnt.*Iterate all rows of CTE.*")
+
+	// update and delete
+	mustExecSQL(c, se, "with recursive sub (id) as (select 2 union all select emp.id from sub, emp where emp.manager = sub.id) update emp set name = 'x' where id in (select id from sub)")
+	rs = mustExecSQL(c, se, "select count(*) from emp where name = 'x'")
+	row, err := rs.FirstRow()
+	c.Assert(err, IsNil)
+	match(c, row, 3)
+
+	mustExecSQL(c, se, "with m as (select manager from emp) delete from emp where id not in (select manager from m where manager is not null)")
+	rs = mustExecSQL(c, se, "select id from emp order by id")
+	rows, err = rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 3)
+
+	// cte_max_recursion_depth
+	mustExecSQL(c, se, "set @@cte_max_recursion_depth = 10")
+	rs = mustExecSQL(c, se, "with recursive cnt (n) as (select 1 union all select n + 1 from cnt) select n from cnt")
+	_, err = rs.Rows(-1, 0)
+	c.Assert(err, NotNil)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErCteMaxRecursionDepth))
+	mustExecSQL(c, se, "set @@cte_max_recursion_depth = 1000")
+
+	errCases := []struct {
+		sql  string
+		code uint16
+	}{
+		{"with recursive c as (select n + 1 from c) select * from c", mysql.ErCteRecursiveRequiresUnion},
+		{"with recursive c (n) as (select n + 1 from c union all select 1) select * from c", mysql.ErCteRecursiveRequiresNonrecursiveFirst},
+		{"with c as (select 1), c as (select 2) select * from c", mysql.ErNonuniqTable},
+		{"with c (a, b) as (select 1) select * from c", mysql.ErViewWrongList},
+	}
+	for _, ca := range errCases {
+		_, err = exec(c, se, ca.sql)
+		c.Assert(err, NotNil, Commentf("%s", ca.sql))
+		c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, ca.code, Commentf("%s", ca.sql))
+	}
+
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestWindowFunc(c *C) {
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists sales")
+	mustExecSQL(c, se, "create table sales (id int, region varchar(10), amount int)")
+	mustExecSQL(c, se, `insert sales values (1, "e", 10), (2, "e", 20), (3, "e", 20), (4, "w", 5), (5, "w", 15)`)
+
+	cases := []struct {
+		sql  string
+		rows [][]interface{}
+	}{
+		{"select id, row_number() over (partition by region order by amount desc, id) from sales order by id",
+			[][]interface{}{{1, 3}, {2, 1}, {3, 2}, {4, 2}, {5, 1}}},
+		// named window
+		{"select id, rank() over w, dense_rank() over w from sales window w as (order by region) order by id",
+			[][]interface{}{{1, 1, 1}, {2, 1, 1}, {3, 1, 1}, {4, 4, 2}, {5, 4, 2}}},
+		{"select id, lag(amount) over w, lead(amount, 2, 0) over w from sales window w as (order by id) order by id",
+			[][]interface{}{{1, nil, 20}, {2, 10, 5}, {3, 20, 15}, {4, 20, 0}, {5, 5, 0}}},
+		{"select id, first_value(id) over (partition by region order by id), ntile(2) over (order by id) from sales order by id",
+			[][]interface{}{{1, 1, 1}, {2, 1, 1}, {3, 1, 1}, {4, 4, 2}, {5, 4, 2}}},
+		// frames
+		{"select id, sum(amount) over (order by id rows between 1 preceding and 1 following) from sales order by id",
+			[][]interface{}{{1, 30}, {2, 50}, {3, 45}, {4, 40}, {5, 20}}},
+		{"select id, sum(amount) over (order by amount) from sales order by id",
+			[][]interface{}{{1, 15}, {2, 70}, {3, 70}, {4, 5}, {5, 30}}},
+		{"select id, count(*) over (order by amount range between 5 preceding and current row) from sales order by id",
+			[][]interface{}{{1, 2}, {2, 3}, {3, 3}, {4, 1}, {5, 2}}},
+		{"select id, last_value(id) over (w rows between current row and unbounded following) from sales window w as (partition by region order by id) order by id",
+			[][]interface{}{{1, 3}, {2, 3}, {3, 3}, {4, 5}, {5, 5}}},
+		// with group by
+		{"select region, sum(amount), sum(sum(amount)) over (), rank() over (order by sum(amount) desc) from sales group by region order by region",
+			[][]interface{}{{"e", 50, 70, 1}, {"w", 20, 70, 2}}},
+		{"select region, count(*) - lag(count(*), 1, 0) over (order by region) from sales group by region order by region",
+			[][]interface{}{{"e", 3}, {"w", -1}}},
+		// in order by and expressions
+		{"select id from sales order by row_number() over (order by amount desc, id)",
+			[][]interface{}{{2}, {3}, {5}, {1}, {4}}},
+		{"select id, id * 10 + row_number() over (order by id) from sales order by id limit 2",
+			[][]interface{}{{1, 11}, {2, 22}}},
+	}
+	for _, ca := range cases {
+		rs := mustExecSQL(c, se, ca.sql)
+		rows, err := rs.Rows(-1, 0)
+		c.Assert(err, IsNil, Commentf("%s", ca.sql))
+		c.Assert(rows, HasLen, len(ca.rows), Commentf("%s", ca.sql))
+		for i, row := range rows {
+			match(c, row, ca.rows[i]...)
+		}
+	}
+
+	rs := mustExecSQL(c, se, "explain select id, row_number() over (order by id) from sales")
+	rows, err := rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	var plan []string
+	for _, row := range rows {
+		plan = append(plan, fmt.Sprintf("%v", row[0]))
+	}
+	c.Assert(strings.Join(plan, "\n"), Matches, "(?s).*Compute window functions row_number.*")
+
+	errCases := []struct {
+		sql  string
+		code uint16
+	}{
+		{"select id from sales where row_number() over () > 1", mysql.ErWindowInvalidWindowFuncUse},
+		{"select sum(row_number() over ()) from sales", mysql.ErWindowInvalidWindowFuncUse},
+		{"select id, row_number() over w from sales", mysql.ErWindowNoSuchWindow},
+		{"select id from sales window w as (), w as ()", mysql.ErWindowDuplicateName},
+		{"select id from sales window w1 as (w2), w2 as (w1)", mysql.ErWindowCircularityInWindowGraph},
+		{"select sum(amount) over (w partition by id) from sales window w as (order by id)", mysql.ErWindowNoChildPartitioning},
+		{"select row_number() over (w order by id) from sales window w as (order by amount)", mysql.ErWindowNoRedefineOrderBy},
+		{"select sum(amount) over (w) from sales window w as (order by id rows 1 preceding)", mysql.ErWindowNoInherentFrame},
+		{"select sum(amount) over (order by id rows between unbounded following and current row) from sales", mysql.ErWindowFrameStartIllegal},
+		{"select sum(amount) over (order by id rows 1.5 preceding) from sales", mysql.ErWindowFrameIllegal},
+		{"select sum(amount) over (order by id, amount range 1 preceding) from sales", mysql.ErWindowRangeFrameOrderType},
+	}
+	for _, ca := range errCases {
+		_, err = exec(c, se, ca.sql)
+		c.Assert(err, NotNil, Commentf("%s", ca.sql))
+		c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, ca.code, Commentf("%s", ca.sql))
+	}
+
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestSQLMode(c *C) {
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists t")
+	mustExecSQL(c, se, "create table t (a tinyint, b varchar(3), c date, d int)")
+
+	rs := mustExecSQL(c, se, "select @@sql_mode")
+	rows, err := rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	match(c, rows[0], "STRICT_TRANS_TABLES,NO_ENGINE_SUBSTITUTION")
+
+	// strict mode
+	errCases := []struct {
+		sql  string
+		code uint16
+	}{
+		{`insert t (b) values ("abcd")`, mysql.ErDataTooLong},
+		{"insert t (a) values (1000)", mysql.ErWarnDataOutOfRange},
+		{"set sql_mode = 'STRICT_TRANS_TABLES,NOT_A_MODE'", mysql.ErWrongValueForVar},
+	}
+	for _, ca := range errCases {
+		_, err = exec(c, se, ca.sql)
+		c.Assert(err, NotNil, Commentf("%s", ca.sql))
+		c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, ca.code, Commentf("%s", ca.sql))
+	}
+
+	// non-strict mode adjusts the invalid values.
+	mustExecSQL(c, se, "set sql_mode = ''")
+	mustExecSQL(c, se, `insert t values (1000, "abcd", "2015-13-45", "abc")`)
+	rs = mustExecSQL(c, se, "select a, b, c, d from t")
+	rows, err = rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	match(c, rows[0], 127, "abc", "0000-00-00", 0)
+	mustExecSQL(c, se, "delete from t")
+
+	// NO_ZERO_DATE
+	mustExecSQL(c, se, "set sql_mode = 'strict_all_tables,no_zero_date'")
+	rs = mustExecSQL(c, se, "select @@sql_mode")
+	rows, err = rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	match(c, rows[0], "STRICT_ALL_TABLES,NO_ZERO_DATE")
+	_, err = exec(c, se, `insert t (c) values ("0000-00-00")`)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErTruncatedWrongValue))
+
+	// ERROR_FOR_DIVISION_BY_ZERO
+	rs = mustExecSQL(c, se, "select 1 / 0")
+	rows, err = rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	match(c, rows[0], nil)
+	mustExecSQL(c, se, "set sql_mode = 'TRADITIONAL'")
+	rs = mustExecSQL(c, se, "select 1 / 0")
+	_, err = rs.Rows(-1, 0)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErDivisionByZero))
+
+	// ONLY_FULL_GROUP_BY
+	mustExecSQL(c, se, `insert t values (1, "x", "2015-01-01", 1), (2, "x", "2015-01-02", 2)`)
+	mustExecSQL(c, se, "set sql_mode = 'ONLY_FULL_GROUP_BY'")
+	rs = mustExecSQL(c, se, "select b, count(a) from t group by b")
+	rows, err = rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	match(c, rows[0], "x", 2)
+	_, err = exec(c, se, "select a, b from t group by b")
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErWrongFieldWithGroup))
+	_, err = exec(c, se, "select b, count(a) from t")
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErMixOfGroupFuncAndFields))
+
+	// lexical modes
+	mustExecSQL(c, se, "set sql_mode = 'ANSI_QUOTES,PIPES_AS_CONCAT,NO_BACKSLASH_ESCAPES'")
+	rs = mustExecSQL(c, se, `select "b" || 'y', 'a\b' from t where "a" = 1`)
+	rows, err = rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 1)
+	match(c, rows[0], "xy", `a\b`)
+
+	// prepared statements use the sql mode too.
+	rs = mustExecSQL(c, se, `select "b" || ? from t where "a" = ?`, "z", 2)
+	rows, err = rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	match(c, rows[0], "xz")
+
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestWarnings(c *C) {
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists t")
+	mustExecSQL(c, se, "create table t (a tinyint, b varchar(3), c int unique)")
+
+	queryRows := func(sql string) [][]interface{} {
+		rs := mustExecSQL(c, se, sql)
+		rows, err := rs.Rows(-1, 0)
+		c.Assert(err, IsNil)
+		return rows
+	}
+
+	// non-strict mode adjusts the invalid values with warnings.
+	mustExecSQL(c, se, "set sql_mode = ''")
+	mustExecSQL(c, se, `insert t values (1000, "abcd", 1)`)
+	rows := queryRows("show warnings")
+	c.Assert(rows, HasLen, 2)
+	match(c, rows[0], "Warning", mysql.ErWarnDataOutOfRange, "Out of range value for column 'a'")
+	match(c, rows[1], "Warning", mysql.WarnDataTruncated, "Data truncated for column 'b'")
+	match(c, queryRows("show count(*) warnings")[0], 2)
+	match(c, queryRows("select @@warning_count, @@session.error_count")[0], 2, 0)
+	c.Assert(queryRows("show errors"), HasLen, 0)
+
+	// A statement using tables clears the warnings.
+	queryRows("select * from t")
+	c.Assert(queryRows("show warnings"), HasLen, 0)
+
+	// max_error_count limits the warnings kept, but all of them are counted.
+	mustExecSQL(c, se, "set max_error_count = 1")
+	mustExecSQL(c, se, `insert t values (1000, "abcd", 2)`)
+	c.Assert(queryRows("show warnings"), HasLen, 1)
+	match(c, queryRows("show count(*) warnings")[0], 2)
+	mustExecSQL(c, se, "set max_error_count = 64")
+
+	// CAST appends warnings too.
+	match(c, queryRows("select cast('abc' as datetime)")[0], nil)
+	rows = queryRows("show warnings")
+	c.Assert(rows, HasLen, 1)
+	match(c, rows[0], "Warning", mysql.ErTruncatedWrongValue, "Truncated incorrect DATETIME value: 'abc'")
+
+	// INSERT IGNORE downgrades the errors to warnings in strict mode and skips the duplicate rows.
+	mustExecSQL(c, se, "set sql_mode = 'STRICT_ALL_TABLES'")
+	_, err := exec(c, se, `insert t values (1000, "x", 3)`)
+	c.Assert(err, NotNil)
+	mustExecSQL(c, se, `insert ignore t values (1000, "x", 3), (1, "y", 1)`)
+	rows = queryRows("show warnings")
+	c.Assert(rows, HasLen, 2)
+	match(c, rows[0], "Warning", mysql.ErWarnDataOutOfRange, "Out of range value for column 'a'")
+	match(c, rows[1], "Warning", mysql.ErDupEntry, "Duplicate entry '1' for key 'c'")
+	match(c, queryRows("select count(*) from t")[0], 3)
+
+	// UPDATE IGNORE skips the rows which would cause duplicate keys.
+	mustExecSQL(c, se, "update ignore t set c = 1 where c = 2")
+	rows = queryRows("show warnings")
+	c.Assert(rows, HasLen, 1)
+	match(c, rows[0], "Warning", mysql.ErDupEntry, "Duplicate entry '1' for key 'c'")
+	match(c, queryRows("select count(*) from t where c = 2")[0], 1)
+	mustExecSQL(c, se, "update ignore t set c = 4 where c = 2")
+	match(c, queryRows("select count(*) from t where c = 4")[0], 1)
+
+	// The failed statement is shown by SHOW ERRORS.
+	_, err = exec(c, se, `insert t values (1, "z", 1)`)
+	c.Assert(err, NotNil)
+	rows = queryRows("show errors")
+	c.Assert(rows, HasLen, 1)
+	c.Assert(rows[0][0], Equals, "Error")
+	match(c, queryRows("show count(*) errors")[0], 1)
+	match(c, queryRows("select @@error_count")[0], 1)
+
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestShow(c *C) {
+	// The processes are listed by the store, so the sessions of the other tests are not shown.
+	store := newStore(c, s.dbName+"_show")
+	se := newSession(c, store, s.dbName)
+	queryRows := func(sql string) [][]interface{} {
+		rs := mustExecSQL(c, se, sql)
+		rows, err := rs.Rows(-1, 0)
+		c.Assert(err, IsNil)
+		return rows
+	}
+	mustExecSQL(c, se, "drop table if exists t")
+	mustExecSQL(c, se, "create table t (id int primary key auto_increment, c varchar(10) not null default 'a\\'b', d int, unique key uc (c), index idx_d (d))")
+	mustExecSQL(c, se, "insert t (c, d) values ('x', 1), ('y', 2)")
+
+	createTable := "CREATE TABLE `t` (\n" +
+		"  `id` INT NOT NULL AUTO_INCREMENT,\n" +
+		"  `c` VARCHAR(10) NOT NULL DEFAULT 'a\\'b',\n" +
+		"  `d` INT DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  UNIQUE KEY `uc` (`c`),\n" +
+		"  KEY `idx_d` (`d`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8"
+	rows := queryRows("show create table t")
+	c.Assert(rows, HasLen, 1)
+	match(c, rows[0], "t", createTable)
+	// The generated statement creates the same table.
+	mustExecSQL(c, se, "drop table t")
+	mustExecSQL(c, se, createTable)
+	match(c, queryRows("show create table "+s.dbName+".t")[0], "t", createTable)
+	mustExecSQL(c, se, "insert t (c, d) values ('x', 1), ('y', 2)")
+	match(c, queryRows("show create database "+s.dbName)[0], s.dbName, "CREATE DATABASE `"+s.dbName+"` /*!40100 DEFAULT CHARACTER SET utf8 */")
+	rs := mustExecSQL(c, se, "show create table t_not_exists")
+	_, err := rs.Rows(-1, 0)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErNoSuchTable))
+
+	rows = queryRows("show index from t")
+	c.Assert(rows, HasLen, 3)
+	match(c, rows[0], "t", 0, "PRIMARY", 1, "id", "A", 0, nil, nil, "", "BTREE", "", "")
+	match(c, rows[1], "t", 0, "uc", 1, "c", "A", 0, nil, nil, "", "BTREE", "", "")
+	match(c, rows[2], "t", 1, "idx_d", 1, "d", "A", 0, nil, nil, "YES", "BTREE", "", "")
+	rows = queryRows("show keys from t where Non_unique = 0 and Key_name <> 'PRIMARY'")
+	c.Assert(rows, HasLen, 1)
+	c.Assert(rows[0][2], Equals, "uc")
+
+	rows = queryRows("show table status like 't'")
+	c.Assert(rows, HasLen, 1)
+	match(c, rows[0][:5], "t", "InnoDB", 10, "Compact", 2)
+	c.Assert(queryRows("show table status where `Rows` > 2"), HasLen, 0)
+
+	// The LIKE and WHERE clauses filter the rows.
+	match(c, queryRows("show databases like '"+s.dbName+"'")[0], s.dbName)
+	match(c, queryRows("show tables like 't'")[0], "t")
+	c.Assert(queryRows("show tables like 'x%'"), HasLen, 0)
+	c.Assert(queryRows("show columns from t like '_d'"), HasLen, 1)
+	match(c, queryRows("show character set where Charset = 'latin1'")[0], "latin1", "cp1252 West European", "latin1_swedish_ci", 1)
+	match(c, queryRows("show collation like 'utf8_bin'")[0], "utf8_bin", "utf8", 83, "", "Yes", 1)
+
+	mustExecSQL(c, se, "set @@session.max_error_count = 10")
+	match(c, queryRows("show variables like 'max_error_count'")[0], "max_error_count", "10")
+	match(c, queryRows("show global variables like 'max_error_count'")[0], "max_error_count", "64")
+	c.Assert(queryRows("show global variables where Variable_name = 'rand_seed2'"), HasLen, 0)
+	c.Assert(len(queryRows("show session variables like 'auto%'")), Greater, 1)
+
+	rows = queryRows("show status where Variable_name = 'Threads_connected'")
+	match(c, rows[0], "Threads_connected", "1")
+	c.Assert(queryRows("show global status like 'Uptime'"), HasLen, 1)
+
+	se1 := newSession(c, store, s.dbName)
+	rows = queryRows("show processlist")
+	c.Assert(rows, HasLen, 2)
+	match(c, rows[0][3:5], s.dbName, "Query")
+	c.Assert(rows[0][7], Equals, "show processlist")
+	match(c, rows[1][3:5], s.dbName, "Sleep")
+	c.Assert(rows[1][7], IsNil)
+	c.Assert(se1.Close(), IsNil)
+
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestKill(c *C) {
+	// The sessions are killed in a store of their own, so the process list of the other tests is not affected.
+	store := newStore(c, s.dbName+"_kill")
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists t")
+	mustExecSQL(c, se, "create table t (c int)")
+	for i := 0; i < 10; i++ {
+		mustExecSQL(c, se, "insert t values (1), (2), (3), (4), (5), (6), (7), (8), (9), (10)")
+	}
+	checkErr := func(err error, code uint16) {
+		c.Assert(err, NotNil)
+		c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, code, Commentf("%v", err))
+	}
+	// The cross join runs long enough to be killed.
+	const longQuery = "select count(*) from t a, t b, t c"
+
+	se1 := newSession(c, store, s.dbName)
+	sid := se1.(*session).sid
+	rs := mustExecSQL(c, se1, longQuery)
+	done := make(chan error, 1)
+	go func() {
+		_, err := rs.Rows(-1, 0)
+		done <- err
+	}()
+	for {
+		p, ok := sessionctx.GetDomain(se1.(context.Context)).GetProcess(sid)
+		c.Assert(ok, IsTrue)
+		if p.ProcessInfo().Command == "Query" {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	mustExecSQL(c, se, fmt.Sprintf("kill query %d", sid))
+	checkErr(<-done, mysql.ErQueryInterrupted)
+	// Only the statement is interrupted, the next statements run.
+	mustExecSQL(c, se1, "select * from t limit 1")
+
+	mustExecSQL(c, se1, "set @@max_execution_time = 1")
+	rs = mustExecSQL(c, se1, longQuery)
+	_, err := rs.Rows(-1, 0)
+	checkErr(err, mysql.ErQueryTimeout)
+	// The timer starts with the SELECT statement, it is not restarted when the rows are fetched.
+	mustExecSQL(c, se1, "set @@max_execution_time = 10")
+	rs = mustExecSQL(c, se1, "select * from t limit 1")
+	time.Sleep(50 * time.Millisecond)
+	_, err = rs.Rows(-1, 0)
+	checkErr(err, mysql.ErQueryTimeout)
+	// Only SELECT statements are limited.
+	mustExecSQL(c, se1, "set @@max_execution_time = 1")
+	mustExecSQL(c, se1, "create table t2 (c int)")
+	mustExecSQL(c, se1, "insert t2 select count(*) from t a, t b, t c")
+	mustExecSQL(c, se1, "set @@max_execution_time = 0")
+	mustExecSQL(c, se1, "select * from t limit 1")
+
+	_, err = exec(c, se, "kill 1000000")
+	checkErr(err, mysql.ErNoSuchThread)
+
+	// The sessions without the SUPER privilege only kill the sessions of their own user.
+	mustExecSQL(c, se, `CREATE USER 'killer'@'%' IDENTIFIED BY 'abc'`)
+	salt := []byte("01234567890123456789")
+	se2, err := CreateSession(store)
+	c.Assert(err, IsNil)
+	c.Assert(se2.Auth("killer", "10.0.0.1", auth.ScramblePassword(salt, "abc"), salt), IsNil)
+	_, err = exec(c, se2, fmt.Sprintf("kill %d", sid))
+	checkErr(err, mysql.ErKillDeniedError)
+	mustExecSQL(c, se2, fmt.Sprintf("kill query %d", se2.(*session).sid))
+
+	// KILL CONNECTION rolls back the transaction of the idle session.
+	mustExecSQL(c, se, "grant super on *.* to 'killer'@'%'")
+	mustExecSQL(c, se1, "begin")
+	mustExecSQL(c, se1, "insert t values (11)")
+	c.Assert(se1.(*session).txn, NotNil)
+	mustExecSQL(c, se2, fmt.Sprintf("kill connection %d", sid))
+	_, ok := sessionctx.GetDomain(se1.(context.Context)).GetProcess(sid)
+	c.Assert(ok, IsFalse)
+	c.Assert(se1.(*session).txn, IsNil)
+	_, err = exec(c, se1, "select * from t limit 1")
+	checkErr(err, mysql.ErQueryInterrupted)
+	_, err = exec(c, se1, "commit")
+	checkErr(err, mysql.ErQueryInterrupted)
+	rs = mustExecSQL(c, se, "select count(*) from t where c = 11")
+	match(c, mustRow(c, rs), 0)
+
+	// The session running a statement is killed when the statement is interrupted.
+	se3 := newSession(c, store, s.dbName)
+	rs = mustExecSQL(c, se3, longQuery)
+	go func() {
+		_, err := rs.Rows(-1, 0)
+		done <- err
+	}()
+	sid3 := se3.(*session).sid
+	for {
+		p, ok := sessionctx.GetDomain(se3.(context.Context)).GetProcess(sid3)
+		c.Assert(ok, IsTrue)
+		if p.ProcessInfo().Command == "Query" {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	mustExecSQL(c, se, fmt.Sprintf("kill %d", sid3))
+	checkErr(<-done, mysql.ErQueryInterrupted)
+	_, err = exec(c, se3, "select * from t limit 1")
+	checkErr(err, mysql.ErQueryInterrupted)
+	c.Assert(se3.Close(), IsNil)
+
+	c.Assert(se1.Close(), IsNil)
+	c.Assert(se2.Close(), IsNil)
+	mustExecSQL(c, se, `DROP USER 'killer'@'%'`)
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestSlowLog(c *C) {
+	// The slow log is enabled in a store of its own, so the other tests are not logged.
+	store := newStore(c, s.dbName+"_slow_log")
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists t")
+	mustExecSQL(c, se, "create table t (c int)")
+	mustExecSQL(c, se, "insert t values (1), (2), (3), (4), (5), (6), (7), (8), (9), (10)")
+	logFile := c.MkDir() + "/slow.log"
+	mustExecSQL(c, se, "set global slow_query_log = 'ON'")
+	mustExecSQL(c, se, fmt.Sprintf("set global slow_query_log_file = '%s'", logFile))
+	// Only the accounts with SUPER choose the file the server writes.
+	mustExecSQL(c, se, `CREATE USER 'slow'@'%' IDENTIFIED BY 'abc'`)
+	salt := []byte("01234567890123456789")
+	se2, err := CreateSession(store)
+	c.Assert(err, IsNil)
+	c.Assert(se2.Auth("slow", "10.0.0.1", auth.ScramblePassword(salt, "abc"), salt), IsNil)
+	_, err = exec(c, se2, "set global slow_query_log_file = '/tmp/x.log'")
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErSpecificAccessDeniedError))
+	c.Assert(se2.Close(), IsNil)
+
+	se1 := newSession(c, store, s.dbName)
+	mustExecSQL(c, se1, "set @@long_query_time = 0")
+	rs := mustExecSQL(c, se1, "select c from t where c > 8")
+	rows, err := rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 2)
+	mustExecSQL(c, se1, "set @@long_query_time = 10")
+	fetch := func(sql string) {
+		_, err := mustExecSQL(c, se1, sql).Rows(-1, 0)
+		c.Assert(err, IsNil)
+	}
+	fetch("select c from t where c = 1")
+
+	b, err := ioutil.ReadFile(logFile)
+	c.Assert(err, IsNil)
+	content := string(b)
+	c.Assert(strings.Count(content, "# Time: "), Equals, 2, Commentf(content))
+	c.Assert(content, Matches, `(?s).*# Query_time: \d+\.\d{6}  Rows_sent: 2  Rows_examined: 10  Succ: true\n.*`)
+	c.Assert(content, Matches, `(?s).*# Plan_digest: [0-9a-f]{64}\nselect c from t where c > \?;\n`)
+	c.Assert(strings.Contains(content, "where c = ?"), IsFalse)
+
+	// The statements differing only in literals have the same summary.
+	fetch("select c from t where c = 2")
+	var summary *domain.StmtSummary
+	for _, ss := range sessionctx.GetDomain(se1.(context.Context)).StmtSummaries() {
+		if ss.DigestText == "select c from t where c = ?" {
+			summary = ss
+		}
+	}
+	c.Assert(summary, NotNil)
+	c.Assert(summary.SchemaName, Equals, s.dbName)
+	c.Assert(summary.Count, Equals, int64(2))
+	c.Assert(summary.SumRowsSent, Equals, uint64(2))
+	c.Assert(summary.SumRowsExamined, Equals, uint64(20))
+
+	c.Assert(se1.Close(), IsNil)
+	mustExecSQL(c, se, `DROP USER 'slow'@'%'`)
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestMetrics(c *C) {
+	store := newStore(c, s.dbName+"_metrics")
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists t")
+	mustExecSQL(c, se, "create table t (id int auto_increment primary key, c int)")
+	mustExecSQL(c, se, "insert t (c) values (1), (2)")
+	_, err := mustExecSQL(c, se, "select * from t").Rows(-1, 0)
+	c.Assert(err, IsNil)
+	_, err = exec(c, se, "select nosuch from t")
+	c.Assert(err, NotNil)
+
+	w := httptest.NewRecorder()
+	http.DefaultServeMux.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	c.Assert(w.Code, Equals, http.StatusOK)
+	body := w.Body.String()
+	for _, name := range []string{
+		`alloydb_statements_total{type="Select"}`,
+		`alloydb_statements_total{type="InsertInto"}`,
+		`alloydb_statement_errors_total{type="Select"}`,
+		`alloydb_statement_duration_seconds_count{type="CreateTable"}`,
+		`alloydb_localstore_txns_total{result="commit"}`,
+		`alloydb_localstore_kv_duration_seconds_count{type="seek"}`,
+		`alloydb_autoid_allocations_total`,
+		`alloydb_infoschema_reload_duration_seconds_count`,
+	} {
+		c.Assert(strings.Contains(body, "\n"+name+" "), IsTrue, Commentf(name))
+	}
+
+	c.Assert(StartStatusServer("invalid address"), NotNil)
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestGlobalVars(c *C) {
+	// The global variables are set in a store of their own, so the other tests are not affected.
+	store := newStore(c, s.dbName+"_global_vars")
+	se := newSession(c, store, s.dbName)
+	queryRow := func(se Session, sql string) []interface{} {
+		rs := mustExecSQL(c, se, sql)
+		row, err := rs.FirstRow()
+		c.Assert(err, IsNil)
+		return row
+	}
+
+	mustExecSQL(c, se, "set global max_error_count = 10, @@global.sql_mode = 'ansi_quotes', global time_zone = '+08:00'")
+	// The session values don't change.
+	match(c, queryRow(se, "select @@max_error_count, @@global.max_error_count"), "64", "10")
+	match(c, queryRow(se, "select @@sql_mode, @@global.sql_mode"), "STRICT_TRANS_TABLES,NO_ENGINE_SUBSTITUTION", "ANSI_QUOTES")
+	c.Assert(variable.GetSysVar("max_error_count").Value, Equals, "64")
+
+	// A new session starts with the global values.
+	se1 := newSession(c, store, s.dbName)
+	match(c, queryRow(se1, "select @@max_error_count, @@sql_mode, @@time_zone"), "10", "ANSI_QUOTES", "+08:00")
+	c.Assert(variable.GetSQLMode(se1.(context.Context)), Equals, mysql.ModeANSIQuotes)
+
+	// A global only variable is read from the store.
+	mustExecSQL(c, se, "set global max_connections = 0")
+	c.Assert(variable.GetSessionVars(se.(context.Context)).WarningCount(), Equals, uint64(1))
+	match(c, queryRow(se1, "select @@max_connections, @@global.max_connections"), "1", "1")
+
+	// Nothing is kept if the transaction is rolled back.
+	mustExecSQL(c, se, "begin")
+	mustExecSQL(c, se, "set global max_error_count = 20")
+	mustExecSQL(c, se, "rollback")
+	match(c, queryRow(se1, "select @@global.max_error_count"), "10")
+
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestTimeZone(c *C) {
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists t")
+	mustExecSQL(c, se, "create table t (id int, ts timestamp, dt datetime, unique index idx_ts(ts))")
+
+	queryRows := func(sql string) [][]interface{} {
+		rs := mustExecSQL(c, se, sql)
+		rows, err := rs.Rows(-1, 0)
+		c.Assert(err, IsNil)
+		return rows
+	}
+
+	match(c, queryRows("select @@time_zone")[0], "SYSTEM")
+	_, err := exec(c, se, "set time_zone = 'Not/A_Zone'")
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErUnknownTimeZone))
+	_, err = exec(c, se, "set time_zone = '+15:00'")
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErUnknownTimeZone))
+
+	// timestamp is converted to the session time zone, datetime is not.
+	mustExecSQL(c, se, "set time_zone = '+00:00'")
+	mustExecSQL(c, se, `insert t values (1, "2015-01-01 00:00:00", "2015-01-01 00:00:00")`)
+	match(c, queryRows("select ts, dt from t")[0], "2015-01-01 00:00:00", "2015-01-01 00:00:00")
+	mustExecSQL(c, se, "set time_zone = '+08:00'")
+	match(c, queryRows("select ts, dt from t")[0], "2015-01-01 08:00:00", "2015-01-01 00:00:00")
+	mustExecSQL(c, se, "set time_zone = 'America/New_York'")
+	match(c, queryRows("select ts from t")[0], "2014-12-31 19:00:00")
+
+	// The index on timestamp is in UTC.
+	mustExecSQL(c, se, "set time_zone = '+08:00'")
+	match(c, queryRows(`select id from t where ts = "2015-01-01 08:00:00"`)[0], 1)
+	_, err = exec(c, se, `insert t values (2, "2015-01-01 08:00:00", null)`)
+	c.Assert(err, NotNil)
+	mustExecSQL(c, se, `insert t values (2, "2015-01-01 00:00:00", null)`)
+	mustExecSQL(c, se, "set time_zone = '+00:00'")
+	match(c, queryRows("select ts from t where id = 2")[0], "2014-12-31 16:00:00")
+
+	// CONVERT_TZ
+	match(c, queryRows("select convert_tz('2004-01-01 12:00:00', '+00:00', '+10:00')")[0], "2004-01-01 22:00:00")
+	match(c, queryRows("select convert_tz('2004-01-01 12:00:00', '+00:00', 'invalid')")[0], nil)
+
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestDateArith(c *C) {
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists t")
+	mustExecSQL(c, se, "create table t (id int, d date, dt datetime(3))")
+	mustExecSQL(c, se, `insert t values (1, "2008-01-31", "2008-12-31 23:59:59.5")`)
+
+	queryRows := func(sql string) [][]interface{} {
+		rs := mustExecSQL(c, se, sql)
+		rows, err := rs.Rows(-1, 0)
+		c.Assert(err, IsNil)
+		return rows
+	}
+
+	match(c, queryRows("select d + interval 1 month, dt + interval 1 second from t")[0], "2008-02-29", "2009-01-01 00:00:00.500")
+	match(c, queryRows("select date_sub(d, interval '1 1' day_hour), interval 1 year + d from t")[0], "2008-01-29 23:00:00", "2009-01-31")
+	match(c, queryRows("select adddate(d, 1), subdate(d, interval 1 week) from t")[0], "2008-02-01", "2008-01-24")
+	match(c, queryRows("select id from t where dt - interval 1 day > '2008-12-30'")[0], 1)
+	match(c, queryRows("select extract(year_month from dt), extract(microsecond from dt) from t")[0], 200812, 500000)
+	match(c, queryRows("select datediff(dt, d), timestampdiff(month, d, dt), timestampadd(day, 1, d) from t")[0], 335, 11, "2008-02-01")
+	match(c, queryRows("select date_format(dt, '%Y/%m/%d %T.%f'), last_day(d) from t")[0], "2008/12/31 23:59:59.500000", "2008-01-31")
+	match(c, queryRows("select str_to_date('May 1, 2013', '%M %d,%Y')")[0], "2013-05-01")
+
+	// The invalid dates are NULL with a warning.
+	match(c, queryRows("select date_add('2008-02-30', interval 1 day)")[0], nil)
+	match(c, queryRows("select @@warning_count")[0], 1)
+	match(c, queryRows("select date_add('9999-12-31', interval 1 day)")[0], nil)
+
+	mustExecSQL(c, se, "set time_zone = '+00:00'")
+	match(c, queryRows("select from_unixtime(1447430881), unix_timestamp('2015-11-13 16:08:01')")[0], "2015-11-13 16:08:01", 1447430881)
+
+	rows := queryRows("select curdate(), curtime(), current_date(), sysdate()")
+	c.Assert(rows[0], HasLen, 4)
+
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestStringFunctions(c *C) {
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists t")
+	mustExecSQL(c, se, "create table t (id int, c varchar(20), b varbinary(20))")
+	mustExecSQL(c, se, `insert t values (1, "日本語abc", "日本語abc")`)
+
+	queryRows := func(sql string) [][]interface{} {
+		rs := mustExecSQL(c, se, sql)
+		rows, err := rs.Rows(-1, 0)
+		c.Assert(err, IsNil)
+		return rows
+	}
+
+	// The string of the binary charset is handled byte by byte.
+	match(c, queryRows("select length(c), char_length(c), length(b), char_length(b) from t")[0], 12, 6, 12, 12)
+	match(c, queryRows("select upper(c), reverse(c), right(c, 4), locate('a', c) from t")[0], "日本語ABC", "cba語本日", "語abc", 4)
+	match(c, queryRows("select lpad(c, 8, '*'), insert(c, 2, 1, 'x'), hex(left(b, 1)) from t")[0], "**日本語abc", "日x語abc", "E6")
+
+	match(c, queryRows("select trim('  bar  '), trim(leading 'x' from 'xxbarxx'), trim(trailing from 'bar  ')")[0], "bar", "barxx", "bar")
+	match(c, queryRows("select replace('www.mysql.com', 'w', 'Ww'), substring_index('www.mysql.com', '.', -2)")[0], "WwWwWw.mysql.com", "mysql.com")
+	match(c, queryRows("select field('b', 'a', 'b'), find_in_set('b', 'a,b,c'), elt(2, 'a', 'b'), instr('foobar', 'bar')")[0], 2, 2, "b", 4)
+	match(c, queryRows("select ascii('2'), ord('2'), strcmp('a', 'b'), format(12332.123456, 4), concat('a', space(2), 'b')")[0], 50, 50, -1, "12,332.1235", "a  b")
+	match(c, queryRows("select id from t where lower(c) = '日本語abc'")[0], 1)
+
+	// The binary strings of BLOB columns work with the expressions on strings.
+	mustExecSQL(c, se, "drop table if exists t1")
+	mustExecSQL(c, se, "create table t1 (id int, b blob)")
+	mustExecSQL(c, se, "insert t1 values (1, 'hello'), (2, 'world')")
+	rows := queryRows("select id from t1 where b like 'he%'")
+	c.Assert(rows, HasLen, 1)
+	match(c, rows[0], 1)
+	rows = queryRows("select id from t1 where b in ('hello', 'abc')")
+	c.Assert(rows, HasLen, 1)
+	match(c, rows[0], 1)
+	rows = queryRows("select substring(b, 2, 2), id from t1 order by b desc")
+	c.Assert(rows, HasLen, 2)
+	c.Assert(rows[0][0], DeepEquals, []byte("or"))
+	c.Assert(rows[1][0], DeepEquals, []byte("el"))
+	match(c, queryRows("select count(*) from t1 where b > 'i' and b regexp '^w'")[0], 1)
+
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestMathFunctions(c *C) {
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists t")
+	mustExecSQL(c, se, "create table t (id int, d decimal(10, 3), f double)")
+	mustExecSQL(c, se, "insert t values (1, 12.345, 1.5), (2, -2.5, 2.5), (3, null, -0.5)")
+
+	queryRows := func(sql string) [][]interface{} {
+		rs := mustExecSQL(c, se, sql)
+		rows, err := rs.Rows(-1, 0)
+		c.Assert(err, IsNil)
+		return rows
+	}
+
+	// Decimal values are rounded exactly, half away from zero.
+	rows := queryRows("select round(d, 2), truncate(d, 1), ceil(d), floor(d), mod(d, 5), abs(d) from t order by id")
+	match(c, rows[0], "12.35", "12.3", "13", "12", "2.345", "12.345")
+	match(c, rows[1], "-2.50", "-2.5", "-2", "-3", "-2.5", "2.5")
+	match(c, rows[2], nil, nil, nil, nil, nil, nil)
+
+	match(c, queryRows("select round(1.5), round(2.5e0), round(123.456, -2), truncate(-1.999, 0), sign(-2.1), 10 mod 4")[0], 2, 2, 100, -1, -1, 2)
+	match(c, queryRows("select pow(2, 10), sqrt(-1), log(2, 8), conv('ff', 16, 10), bin(5), oct(8), bit_count(255)")[0], 1024, nil, 3, "255", "101", "10", 8)
+	match(c, queryRows("select md5('a'), sha1('a'), crc32('a'), length(uuid())")[0], "0cc175b9c0f1b6a831c399e269772661", "86f7e437faa5a7fce15d1ddcb9eaeaea377667b8", 3904355907, 36)
+
+	// RAND with a constant seed produces a repeatable sequence.
+	first := queryRows("select rand(1) from t")
+	c.Assert(first, HasLen, 3)
+	c.Assert(first[0][0], Not(Equals), first[1][0])
+	match(c, queryRows("select rand(1) from t")[1], first[1][0])
+
+	mustExecSQL(c, se, "create table b (g int, v bigint)")
+	mustExecSQL(c, se, "insert b values (1, 7), (1, 3), (1, null), (2, 5), (2, 6)")
+	rows = queryRows("select g, bit_and(v), bit_or(v), bit_xor(v) from b group by g order by g")
+	match(c, rows[0], 1, 3, 7, 4)
+	match(c, rows[1], 2, 4, 7, 3)
+
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestCollation(c *C) {
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists t")
+	mustExecSQL(c, se, "create table t (id int, a varchar(10), b varchar(10) collate utf8_bin, c char(10) character set latin1)")
+	mustExecSQL(c, se, "insert t values (1, 'abc', 'abc', 'x'), (2, 'ABC', 'ABC', 'Y'), (3, 'b', 'b', 'X'), (4, 'Äbc', 'a', 'y')")
+
+	queryRows := func(sql string) [][]interface{} {
+		rs := mustExecSQL(c, se, sql)
+		rows, err := rs.Rows(-1, 0)
+		c.Assert(err, IsNil)
+		return rows
+	}
+
+	// The default collation of string columns is case and accent insensitive.
+	c.Assert(queryRows("select id from t where a = 'abc'"), HasLen, 3)
+	c.Assert(queryRows("select id from t where a in ('ABC ')"), HasLen, 3)
+	c.Assert(queryRows("select id from t where a between 'AAA' and 'abd'"), HasLen, 3)
+	c.Assert(queryRows("select id from t where b = 'abc'"), HasLen, 1)
+	match(c, queryRows("select a from t order by a, id desc")[3], "b")
+	match(c, queryRows("select b from t order by b")[0], "ABC")
+	c.Assert(queryRows("select a, count(*) from t group by a"), HasLen, 2)
+	c.Assert(queryRows("select b, count(*) from t group by b"), HasLen, 4)
+	c.Assert(queryRows("select c from t group by c"), HasLen, 2)
+	c.Assert(queryRows("select distinct a from t"), HasLen, 2)
+	c.Assert(queryRows("select distinct b from t"), HasLen, 4)
+	c.Assert(queryRows("select distinct a as x, c from t"), HasLen, 4)
+	match(c, queryRows("select min(a), max(a), min(b), count(distinct a), count(distinct b) from t")[0], "abc", "b", "ABC", 2, 4)
+	match(c, queryRows("select count(distinct c), group_concat(distinct a) from t group by id > 10")[0], 2, "abc,b")
+
+	// The semi joins match the keys by the collation like = ANY.
+	mustExecSQL(c, se, "create table t2 (a varchar(10), b varchar(10) collate utf8_bin)")
+	mustExecSQL(c, se, "insert t2 values ('ABC', 'ABC'), ('B', 'B')")
+	for _, sql := range []string{
+		"select id from t where a = any (select a from t2) order by id",
+		"select id from t where a in (select a from t2) order by id",
+		"select id from t where exists (select 1 from t2 where t2.a = t.a) order by id",
+	} {
+		rows := queryRows(sql)
+		c.Assert(rows, HasLen, 4, Commentf("%s", sql))
+	}
+	rows := queryRows("select id from t where b in (select b from t2) order by id")
+	c.Assert(rows, HasLen, 1)
+	match(c, rows[0], 2)
+	rows = queryRows("select id from t where not exists (select 1 from t2 where t2.b = t.a) order by id")
+	c.Assert(rows, HasLen, 3)
+	match(c, rows[0], 1)
+	match(c, rows[1], 3)
+	match(c, rows[2], 4)
+
+	// A unique index on a case insensitive column rejects the values equal by the collation.
+	mustExecSQL(c, se, "create table u (a varchar(10) unique, b varchar(10) binary unique)")
+	mustExecSQL(c, se, "insert u values ('abc', 'abc')")
+	_, err := exec(c, se, "insert u values ('ABC', 'x')")
+	c.Assert(err, NotNil)
+	mustExecSQL(c, se, "insert u values ('x', 'ABC')")
+	match(c, queryRows("select b from u where a = 'ABC'")[0], "abc")
+	match(c, queryRows("select a from u where a > 'ABC'")[0], "x")
+	mustExecSQL(c, se, "delete from u where a = 'ABC'")
+	mustExecSQL(c, se, "insert u values ('ABC', 'abc')")
+
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestEnumSetBit(c *C) {
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists t")
+	mustExecSQL(c, se, "create table t (id int, e enum('b', 'a', 'c'), s set('x', 'y', 'z'), b bit(8))")
+	mustExecSQL(c, se, "create index ie on t (e)")
+	mustExecSQL(c, se, "insert t values (1, 'a', 'z,x', b'01000001'), (2, 3, 3, 66), (3, 'B', '', 0)")
+
+	queryRows := func(sql string) [][]interface{} {
+		rs := mustExecSQL(c, se, sql)
+		rows, err := rs.Rows(-1, 0)
+		c.Assert(err, IsNil)
+		return rows
+	}
+
+	match(c, queryRows("select e, s, b from t where id = 1")[0], "a", "x,z", "b'01000001'")
+	match(c, queryRows("select e, s, b from t where id = 2")[0], "c", "x,y", "b'01000010'")
+	match(c, queryRows("select e + 0, s + 0, b + 0 from t where id = 3")[0], 1, 0, 0)
+
+	// Enum and set values are compared by name with strings and by index with numbers,
+	// and ordered by index.
+	match(c, queryRows("select id from t where e = 'A'")[0], 1)
+	match(c, queryRows("select id from t where e = 3")[0], 2)
+	c.Assert(queryRows("select id from t where e > 'a'"), HasLen, 2)
+	c.Assert(queryRows("select id from t where e > 1"), HasLen, 2)
+	match(c, queryRows("select id from t order by e")[0], 3)
+	match(c, queryRows("select id from t order by s desc")[0], 1)
+	match(c, queryRows("select id from t where s = 'x,y'")[0], 2)
+	match(c, queryRows("select id from t where b = 'A'")[0], 1)
+	match(c, queryRows("select id from t where b = b'1000010'")[0], 2)
+	c.Assert(queryRows("select e, count(*) from t group by e"), HasLen, 3)
+
+	_, err := exec(c, se, "insert t values (4, 'd', '', 0)")
+	c.Assert(err, NotNil)
+	_, err = exec(c, se, "insert t values (4, 'b', 'w', 0)")
+	c.Assert(err, NotNil)
+	_, err = exec(c, se, "insert t values (4, 'b', '', 256)")
+	c.Assert(err, NotNil)
+	_, err = exec(c, se, "create table t1 (e enum('a', 'A'))")
+	c.Assert(err, NotNil)
+	_, err = exec(c, se, "create table t1 (b bit(65))")
+	c.Assert(err, NotNil)
+
+	rows := queryRows("show columns from t")
+	match(c, rows[1][:2], "e", "ENUM ('b','a','c')")
+	match(c, rows[2][:2], "s", "SET ('x','y','z')")
+	match(c, rows[3][:2], "b", "BIT (8)")
+
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestAuth(c *C) {
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+
+	// The root account without password is created when the store is bootstrapped.
+	salt := []byte("01234567890123456789")
+	c.Assert(se.Auth("root", "127.0.0.1", nil, salt), IsNil)
+	mustExecSQL(c, se, `CREATE USER 'test'@'%' IDENTIFIED BY 'abc', 'test'@'10.0.0.%' IDENTIFIED BY '123'`)
+
+	se1 := newSession(c, store, s.dbName)
+	c.Assert(se1.Auth("test", "10.0.0.1", auth.ScramblePassword(salt, "123"), salt), IsNil)
+	c.Assert(variable.GetSessionVars(se1.(context.Context)).User, Equals, "test@10.0.0.%")
+	c.Assert(se1.Auth("test", "10.0.1.1", auth.ScramblePassword(salt, "abc"), salt), IsNil)
+	c.Assert(variable.GetSessionVars(se1.(context.Context)).User, Equals, "test@%")
+
+	// The session user changes its own password.
+	mustExecSQL(c, se1, `SET PASSWORD = PASSWORD('def')`)
+	c.Assert(se1.Auth("test", "10.0.1.1", auth.ScramblePassword(salt, "def"), salt), IsNil)
+
+	tbl := []struct {
+		user string
+		host string
+		pwd  string
+	}{
+		{"test", "10.0.1.1", "abc"},
+		{"test", "10.0.0.1", "abc"},
+		{"test", "10.0.0.1", ""},
+		{"nobody", "10.0.0.1", ""},
+		{"root", "10.0.0.1", "abc"},
+	}
+	for _, t := range tbl {
+		err := se1.Auth(t.user, t.host, auth.ScramblePassword(salt, t.pwd), salt)
+		c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErAccessDeniedError), Commentf("%v", t))
+	}
+
+	mustExecSQL(c, se, `DROP USER 'test'@'%', 'test'@'10.0.0.%'`)
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestPrivileges(c *C) {
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists t")
+	mustExecSQL(c, se, "create table t (a int, b int, c int)")
+	mustExecSQL(c, se, "insert t values (1, 2, 3)")
+	mustExecSQL(c, se, `CREATE USER 'priv'@'%' IDENTIFIED BY 'abc'`)
+
+	salt := []byte("01234567890123456789")
+	se1, err := CreateSession(store)
+	c.Assert(err, IsNil)
+	c.Assert(se1.Auth("priv", "10.0.0.1", auth.ScramblePassword(salt, "abc"), salt), IsNil)
+	mustExecSQL(c, se1, "use "+s.dbName)
+
+	checkDenied := func(sql string, code uint16) {
+		_, err := exec(c, se1, sql)
+		c.Assert(err, NotNil, Commentf("%s", sql))
+		c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, code, Commentf("%s %v", sql, err))
+	}
+	queryRows := func(se Session, sql string) [][]interface{} {
+		rs := mustExecSQL(c, se, sql)
+		rows, err := rs.Rows(-1, 0)
+		c.Assert(err, IsNil)
+		return rows
+	}
+
+	checkDenied("select * from t", mysql.ErTableaccessDeniedError)
+	checkDenied("insert t values (4, 5, 6)", mysql.ErTableaccessDeniedError)
+	checkDenied("drop database "+s.dbName, mysql.ErDbaccessDeniedError)
+	checkDenied("create user 'x'", mysql.ErSpecificAccessDeniedError)
+	checkDenied("grant select on t to 'priv'", mysql.ErTableaccessDeniedError)
+	checkDenied("set global max_connections = 100", mysql.ErSpecificAccessDeniedError)
+	checkDenied("set @a = 1, @@global.max_connections = 100", mysql.ErSpecificAccessDeniedError)
+	checkDenied("drop index i on t", mysql.ErTableaccessDeniedError)
+	checkDenied("drop index i", mysql.ErSpecificAccessDeniedError)
+	mustExecSQL(c, se1, "set @a = 1, @@session.sql_mode = ''")
+	match(c, queryRows(se1, "show grants")[0], "GRANT USAGE ON *.* TO 'priv'@'%'")
+	// The tables without privileges are not shown.
+	checkDenied("show create table t", mysql.ErTableaccessDeniedError)
+	checkDenied("show columns from t", mysql.ErTableaccessDeniedError)
+	checkDenied("show index from "+s.dbName+".t", mysql.ErTableaccessDeniedError)
+	c.Assert(queryRows(se1, "show tables"), HasLen, 0)
+	c.Assert(queryRows(se1, "show table status"), HasLen, 0)
+	match(c, queryRows(se1, "select count(*) from information_schema.tables where table_schema = '"+s.dbName+"'")[0], 0)
+	match(c, queryRows(se1, "select count(*) from information_schema.columns where table_schema = '"+s.dbName+"'")[0], 0)
+
+	// Table privileges.
+	mustExecSQL(c, se, "grant select, insert on t to 'priv'@'%'")
+	match(c, queryRows(se1, "select a from t")[0], 1)
+	mustExecSQL(c, se1, "insert t values (4, 5, 6)")
+	checkDenied("update t set a = 1", mysql.ErTableaccessDeniedError)
+	checkDenied("delete from t", mysql.ErTableaccessDeniedError)
+	checkDenied("select * from t where a in (select a from t1)", mysql.ErTableaccessDeniedError)
+	checkDenied("explain select * from t1", mysql.ErTableaccessDeniedError)
+	mustExecSQL(c, se, "revoke insert on t from 'priv'@'%'")
+	checkDenied("insert t values (4, 5, 6)", mysql.ErTableaccessDeniedError)
+	match(c, queryRows(se1, "select count(*) from t")[0], 2)
+
+	// Prepared statements are checked when they are executed.
+	stmtID, _, _, err := se1.PrepareStmt("select a from t")
+	c.Assert(err, IsNil)
+	mustExecSQL(c, se, "revoke select on t from 'priv'@'%'")
+	_, err = se1.ExecutePreparedStmt(stmtID)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErTableaccessDeniedError))
+	c.Assert(se1.DropPreparedStmt(stmtID), IsNil)
+
+	// Column privileges.
+	mustExecSQL(c, se, "grant select (a, b), update (c) on t to 'priv'@'%'")
+	match(c, queryRows(se1, "select a, t.b from t where a = 1")[0], 1, 2)
+	match(c, queryRows(se1, "select count(*) from t")[0], 2)
+	checkDenied("select * from t", mysql.ErColumnaccessDeniedError)
+	checkDenied("select a from t where c = 3", mysql.ErColumnaccessDeniedError)
+	mustExecSQL(c, se1, "update t set c = 4 where a = 1")
+	checkDenied("update t set b = 4", mysql.ErColumnaccessDeniedError)
+	match(c, queryRows(se1, "show grants")[1], "GRANT SELECT (`a`, `b`), UPDATE (`c`) ON `"+s.dbName+"`.`t` TO 'priv'@'%'")
+	rows := queryRows(se, "select table_name, column_name, privilege_type from information_schema.column_privileges where grantee = \"'priv'@'%'\" order by column_name")
+	c.Assert(rows, HasLen, 3)
+	match(c, rows[2], "t", "c", "UPDATE")
+	// Revoking a table privilege revokes it on the columns too.
+	mustExecSQL(c, se, "revoke select on t from 'priv'@'%'")
+	checkDenied("select a from t", mysql.ErTableaccessDeniedError)
+	mustExecSQL(c, se1, "update t set c = 5")
+	// Only the columns with privileges are shown.
+	rows = queryRows(se1, "show columns from t")
+	c.Assert(rows, HasLen, 1)
+	match(c, rows[0], "c", "INT", "YES", "", nil, "")
+	rows = queryRows(se1, "select column_name from information_schema.columns where table_schema = '"+s.dbName+"'")
+	c.Assert(rows, HasLen, 1)
+	match(c, rows[0], "c")
+	c.Assert(queryRows(se1, "show tables"), HasLen, 1)
+	c.Assert(queryRows(se1, "show table status"), HasLen, 1)
+	c.Assert(queryRows(se1, "show create table t"), HasLen, 1)
+
+	// Database privileges.
+	mustExecSQL(c, se, "grant all on "+s.dbName+".* to 'priv'@'%' with grant option")
+	match(c, queryRows(se1, "select * from t where a = 1")[0], 1, 2, 5)
+	mustExecSQL(c, se1, "create table t2 (a int)")
+	mustExecSQL(c, se1, "drop table t2")
+	mustExecSQL(c, se1, "drop index i on t")
+	mustExecSQL(c, se1, "grant select on t to 'priv'@'%'")
+	rows = queryRows(se1, "show grants")
+	c.Assert(rows, HasLen, 3)
+	match(c, rows[1], "GRANT ALL PRIVILEGES ON `"+s.dbName+"`.* TO 'priv'@'%' WITH GRANT OPTION")
+	checkDenied("create database priv_db", mysql.ErDbaccessDeniedError)
+	match(c, queryRows(se, "select count(*) from information_schema.schema_privileges where grantee = \"'priv'@'%'\"")[0], 8)
+
+	// Global privileges.
+	mustExecSQL(c, se, "grant create, drop, super on *.* to 'priv'@'%'")
+	mustExecSQL(c, se1, "create database priv_db")
+	mustExecSQL(c, se1, "drop database priv_db")
+	mustExecSQL(c, se1, "set global max_connections = 151")
+	match(c, queryRows(se1, "show grants")[0], "GRANT CREATE, DROP, SUPER ON *.* TO 'priv'@'%'")
+	rows = queryRows(se1, "select privilege_type from information_schema.user_privileges")
+	c.Assert(rows, HasLen, 3)
+
+	// Dropping the account removes its privileges.
+	mustExecSQL(c, se, "drop user 'priv'@'%'")
+	match(c, queryRows(se, "select count(*) from mysql.db where User = 'priv'")[0], 0)
+	match(c, queryRows(se, "select count(*) from mysql.tables_priv where User = 'priv'")[0], 0)
+	match(c, queryRows(se, "select count(*) from mysql.columns_priv where User = 'priv'")[0], 0)
+	checkDenied("select * from t", mysql.ErTableaccessDeniedError)
+
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestJSON(c *C) {
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists t")
+	mustExecSQL(c, se, `create table t (id int, j json)`)
+	mustExecSQL(c, se, `insert t values (1, '{"a": 1, "b": [1, 2, {"c": "x"}]}'), (2, '[1, "2", true]'), (3, '"abc"'), (4, 3.5), (5, null)`)
+
+	queryRows := func(sql string) [][]interface{} {
+		rs := mustExecSQL(c, se, sql)
+		rows, err := rs.Rows(-1, 0)
+		c.Assert(err, IsNil)
+		return rows
+	}
+
+	match(c, queryRows("select j from t where id = 1")[0], `{"a": 1, "b": [1, 2, {"c": "x"}]}`)
+	match(c, queryRows("select j->'$.b[2].c', j->>'$.b[2].c', json_type(j) from t where id = 1")[0], `"x"`, "x", "OBJECT")
+	match(c, queryRows("select json_extract(j, '$[1]', '$[0]') from t where id = 2")[0], `["2", 1]`)
+	match(c, queryRows("select id from t where j->'$.a' = 1")[0], 1)
+	match(c, queryRows("select id from t where j = 'abc'")[0], 3)
+	match(c, queryRows("select id from t where j < 4")[0], 4)
+	match(c, queryRows("select id from t order by j desc")[0], 2)
+
+	mustExecSQL(c, se, `update t set j = json_set(j, '$.a', 2, '$.d', json_array(1, null)) where id = 1`)
+	match(c, queryRows("select j from t where id = 1")[0], `{"a": 2, "b": [1, 2, {"c": "x"}], "d": [1, null]}`)
+	mustExecSQL(c, se, `update t set j = json_remove(j, '$.b') where id = 1`)
+	match(c, queryRows("select j, json_contains(j, '2', '$.a') from t where id = 1")[0], `{"a": 2, "d": [1, null]}`, 1)
+
+	_, err := exec(c, se, `insert t values (6, '{"a": }')`)
+	c.Assert(err, NotNil)
+	_, err = exec(c, se, "create index ij on t (j)")
+	c.Assert(err, NotNil)
+
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestStreamAggregate(c *C) {
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists t")
+	mustExecSQL(c, se, "create table t (c1 int, c2 int, index idx_c1(c1))")
+	mustExecSQL(c, se, "insert t values (3, 1), (1, 1), (null, 2), (3, 2), (1, null), (null, 3), (2, 4)")
+
+	rs := mustExecSQL(c, se, "select c1, count(c2), sum(distinct c2), max(c2) from t group by c1")
+	rows, err := rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 4)
+	match(c, rows[0], nil, 2, 5, 3)
+	match(c, rows[1], 1, 1, 1, 1)
+	match(c, rows[2], 2, 1, 4, 4)
+	match(c, rows[3], 3, 2, 3, 2)
+
+	rs = mustExecSQL(c, se, "select c1 + 1, avg(c2) from t where c1 > 1 group by c1 having count(*) > 1")
+	rows, err = rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 1)
+	match(c, rows[0], 4, "1.5000")
+
+	rs = mustExecSQL(c, se, "select c1 from t group by c1 limit 2")
+	rows, err = rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 2)
+	match(c, rows[0], nil)
+	match(c, rows[1], 1)
+
+	rs = mustExecSQL(c, se, "select count(*), group_concat(c2) from t where c1 > 10 group by c1")
+	rows, err = rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 0)
+
+	mustExecSQL(c, se, s.dropDBSQL)
+}
+
+func (s *testSessionSuite) TestSpillToDisk(c *C) {
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists t")
+	mustExecSQL(c, se, "create table t (c1 int, c2 varchar(20), c3 decimal(10, 2), c4 decimal(10, 3))")
+	for i := 0; i < 50; i++ {
+		mustExecSQL(c, se, fmt.Sprintf("insert t values (%d, 'abc%d', %d.5, '%d.500')", i, i%10, i%5, i%5))
+	}
+	mustExecSQL(c, se, "set @@alloydb_mem_quota_query = 512")
+
+	rs := mustExecSQL(c, se, "select c1, c3 from t order by c3 desc, c1")
+	rows, err := rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 50)
+	match(c, rows[0], 4, "4.5")
+	match(c, rows[1], 9, "4.5")
+	match(c, rows[49], 45, "0.5")
+
+	rs = mustExecSQL(c, se, "select distinct c2 from t")
+	rows, err = rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 10)
+	match(c, rows[0], "abc0")
+	match(c, rows[9], "abc9")
+
+	rs = mustExecSQL(c, se, "select c3, count(*) from t group by c3 order by c3")
+	rows, err = rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 5)
+	match(c, rows[0], "0.5", 10)
+	match(c, rows[4], "4.5", 10)
+
+	// The equal decimals of different scales, like 1.5 and 1.500, are in the same group after spilled.
+	rs = mustExecSQL(c, se, "select count(*) from t group by if(c1 < 25, c3, c4), c2")
+	rows, err = rs.Rows(-1, 0)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 10)
+	for _, row := range rows {
+		match(c, row, 5)
+	}
+
+	mustExecSQL(c, se, s.dropDBSQL)
+}
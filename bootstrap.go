@@ -0,0 +1,228 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alloydb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/ngaut/log"
+	"github.com/Dong-Chan/alloydb/column"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/kv"
+	"github.com/Dong-Chan/alloydb/meta"
+	"github.com/Dong-Chan/alloydb/model"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/sessionctx"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+)
+
+// CreateUserTable is the SQL statement creates the account table in the system database.
+// The Password column holds the mysql_native_password hash, it is empty for an account without password.
+// The *_priv columns are the global privileges.
+const CreateUserTable = `CREATE TABLE IF NOT EXISTS mysql.user (
+	Host CHAR(64) NOT NULL DEFAULT '',
+	User CHAR(32) NOT NULL DEFAULT '',
+	Password CHAR(41) NOT NULL DEFAULT '',
+	Select_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	Insert_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	Update_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	Delete_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	Create_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	Drop_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	Grant_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	Index_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	Alter_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	Create_user_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	Super_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	PRIMARY KEY (Host, User));`
+
+// CreateDBPrivTable is the SQL statement creates the database level privilege table.
+const CreateDBPrivTable = `CREATE TABLE IF NOT EXISTS mysql.db (
+	Host CHAR(64) NOT NULL DEFAULT '',
+	DB CHAR(64) NOT NULL DEFAULT '',
+	User CHAR(32) NOT NULL DEFAULT '',
+	Select_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	Insert_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	Update_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	Delete_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	Create_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	Drop_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	Grant_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	Index_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	Alter_priv ENUM('N','Y') NOT NULL DEFAULT 'N',
+	PRIMARY KEY (Host, DB, User));`
+
+// CreateTablePrivTable is the SQL statement creates the table level privilege table.
+// Column_priv is the union of the privileges granted on the columns of the table.
+const CreateTablePrivTable = `CREATE TABLE IF NOT EXISTS mysql.tables_priv (
+	Host CHAR(64) NOT NULL DEFAULT '',
+	DB CHAR(64) NOT NULL DEFAULT '',
+	User CHAR(32) NOT NULL DEFAULT '',
+	Table_name CHAR(64) NOT NULL DEFAULT '',
+	Grantor CHAR(77) NOT NULL DEFAULT '',
+	Timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	Table_priv SET('Select','Insert','Update','Delete','Create','Drop','Grant','Index','Alter') NOT NULL DEFAULT '',
+	Column_priv SET('Select','Insert','Update') NOT NULL DEFAULT '',
+	PRIMARY KEY (Host, DB, User, Table_name));`
+
+// CreateColumnPrivTable is the SQL statement creates the column level privilege table.
+const CreateColumnPrivTable = `CREATE TABLE IF NOT EXISTS mysql.columns_priv (
+	Host CHAR(64) NOT NULL DEFAULT '',
+	DB CHAR(64) NOT NULL DEFAULT '',
+	User CHAR(32) NOT NULL DEFAULT '',
+	Table_name CHAR(64) NOT NULL DEFAULT '',
+	Column_name CHAR(64) NOT NULL DEFAULT '',
+	Timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	Column_priv SET('Select','Insert','Update') NOT NULL DEFAULT '',
+	PRIMARY KEY (Host, DB, User, Table_name, Column_name));`
+
+// CreateGlobalVariablesTable is the SQL statement creates the table of the global system variables.
+const CreateGlobalVariablesTable = `CREATE TABLE IF NOT EXISTS mysql.global_variables (
+	VARIABLE_NAME VARCHAR(64) NOT NULL PRIMARY KEY,
+	VARIABLE_VALUE VARCHAR(1024) DEFAULT NULL);`
+
+// CreateHelpTopicTable is the SQL statement creates the table of the help topics.
+const CreateHelpTopicTable = `CREATE TABLE IF NOT EXISTS mysql.help_topic (
+	help_topic_id INT NOT NULL PRIMARY KEY,
+	name CHAR(64) NOT NULL,
+	help_category_id SMALLINT NOT NULL,
+	description TEXT NOT NULL,
+	example TEXT NOT NULL,
+	url TEXT NOT NULL,
+	UNIQUE KEY name (name));`
+
+// bootstrapSteps are the steps to bring the system database of a store up to date, the version
+// of a store is the number of the steps done. Add a step at the end for every change of the system
+// database, a step must be idempotent as it may run again if the session is closed before the
+// version is saved, or if sessions of different servers bootstrap the same store at the same time.
+var bootstrapSteps = []func(Session) error{
+	bootstrapPrivilegeTables,
+	bootstrapGlobalVariables,
+	bootstrapHelpTopics,
+	bootstrapSuperPriv,
+}
+
+// currentBootstrapVersion is the version of the system database this binary expects.
+var currentBootstrapVersion = int64(len(bootstrapSteps))
+
+var (
+	// bootstrapMu serializes the bootstrap of the stores in this process.
+	bootstrapMu sync.Mutex
+	// bootstrapped records the UUID of the stores that are up to date, so other sessions skip reading the version.
+	bootstrapped = make(map[string]bool)
+)
+
+// bootstrap creates or upgrades the system database of the store of s, it runs the steps from the
+// version of the store to currentBootstrapVersion and saves the version after each step.
+// A store bootstrapped by a newer binary is left as it is.
+func bootstrap(s Session) error {
+	store := s.(*session).store
+	bootstrapMu.Lock()
+	defer bootstrapMu.Unlock()
+	if bootstrapped[store.UUID()] {
+		return nil
+	}
+
+	ver, err := getBootstrapVersion(store)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if ver > currentBootstrapVersion {
+		log.Warnf("bootstrap version %d of the store is newer than %d", ver, currentBootstrapVersion)
+	}
+	for ; ver < currentBootstrapVersion; ver++ {
+		log.Infof("bootstrap system database %s to version %d", mysql.SystemDB, ver+1)
+		if err = bootstrapSteps[ver](s); err != nil {
+			return errors.Trace(err)
+		}
+		err = kv.RunInNewTxn(store, true, func(txn kv.Transaction) error {
+			return errors.Trace(meta.SetBootstrapVersion(txn, ver+1))
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	bootstrapped[store.UUID()] = true
+	return nil
+}
+
+func getBootstrapVersion(store kv.Storage) (ver int64, err error) {
+	err = kv.RunInNewTxn(store, false, func(txn kv.Transaction) error {
+		ver, err = meta.GetBootstrapVersion(txn)
+		return errors.Trace(err)
+	})
+	return
+}
+
+func execBootstrapSQLs(s Session, sqls []string) error {
+	for _, sql := range sqls {
+		if _, err := s.Execute(sql); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// bootstrapPrivilegeTables creates the system database, the account and privilege tables, and the
+// root account without password which can connect from any host and has all privileges.
+func bootstrapPrivilegeTables(s Session) error {
+	return execBootstrapSQLs(s, []string{
+		"CREATE DATABASE IF NOT EXISTS " + mysql.SystemDB,
+		CreateDBPrivTable,
+		CreateTablePrivTable,
+		CreateColumnPrivTable,
+		CreateUserTable,
+		`INSERT IGNORE INTO mysql.user VALUES ("%", "root", "", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y")`,
+	})
+}
+
+// bootstrapGlobalVariables creates the table of the global system variables with their default values.
+func bootstrapGlobalVariables(s Session) error {
+	var values []string
+	for _, v := range variable.SysVars {
+		if v.Scope&variable.ScopeGlobal != 0 {
+			values = append(values, fmt.Sprintf("(%q, %q)", v.Name, v.Value))
+		}
+	}
+	sort.Strings(values)
+	return execBootstrapSQLs(s, []string{
+		CreateGlobalVariablesTable,
+		"INSERT IGNORE INTO mysql.global_variables VALUES " + strings.Join(values, ", "),
+	})
+}
+
+// bootstrapHelpTopics creates the table of the help topics.
+func bootstrapHelpTopics(s Session) error {
+	return execBootstrapSQLs(s, []string{CreateHelpTopicTable})
+}
+
+// bootstrapSuperPriv adds the Super_priv column to the account table of the stores bootstrapped without it,
+// the accounts which may create users get the SUPER privilege.
+func bootstrapSuperPriv(s Session) error {
+	t, err := sessionctx.GetDomain(s.(context.Context)).InfoSchema().TableByName(model.NewCIStr(mysql.SystemDB), model.NewCIStr("user"))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if column.FindCol(t.Cols(), "Super_priv") != nil {
+		return nil
+	}
+	return execBootstrapSQLs(s, []string{
+		"ALTER TABLE mysql.user ADD COLUMN Super_priv ENUM('N','Y') NOT NULL DEFAULT 'N'",
+		"UPDATE mysql.user SET Super_priv = Create_user_priv",
+	})
+}
@@ -0,0 +1,167 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alloydb
+
+import (
+	"strings"
+	"sync"
+
+	. "github.com/pingcap/check"
+	"github.com/Dong-Chan/alloydb/kv"
+	"github.com/Dong-Chan/alloydb/meta"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/rset"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+)
+
+var _ = Suite(&testBootstrapSuite{})
+
+type testBootstrapSuite struct {
+	dbName string
+}
+
+func (s *testBootstrapSuite) SetUpSuite(c *C) {
+	s.dbName = "test_bootstrap_db"
+}
+
+func (s *testBootstrapSuite) bootstrapVersion(c *C, store kv.Storage) int64 {
+	ver, err := getBootstrapVersion(store)
+	c.Assert(err, IsNil)
+	return ver
+}
+
+func mustRow(c *C, rs rset.Recordset) []interface{} {
+	row, err := rs.FirstRow()
+	c.Assert(err, IsNil)
+	return row
+}
+
+func (s *testBootstrapSuite) TestBootstrap(c *C) {
+	store := newStore(c, s.dbName)
+	defer store.Close()
+
+	// Sessions open the fresh store at the same time.
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			se, err := CreateSession(store)
+			c.Assert(err, IsNil)
+			se.Close()
+		}()
+	}
+	wg.Wait()
+	c.Assert(s.bootstrapVersion(c, store), Equals, currentBootstrapVersion)
+
+	se := newSession(c, store, s.dbName)
+	rs := mustExecSQL(c, se, `SELECT COUNT(*) FROM mysql.user WHERE User = "root"`)
+	match(c, mustRow(c, rs), 1)
+	rs = mustExecSQL(c, se, `SELECT VARIABLE_VALUE FROM mysql.global_variables WHERE VARIABLE_NAME = "max_connections"`)
+	match(c, mustRow(c, rs), variable.GetSysVar("max_connections").Value)
+	// Session only variables are not stored.
+	rs = mustExecSQL(c, se, `SELECT COUNT(*) FROM mysql.global_variables WHERE VARIABLE_NAME = "rand_seed2"`)
+	match(c, mustRow(c, rs), 0)
+	rs = mustExecSQL(c, se, `SELECT COUNT(*) FROM mysql.help_topic`)
+	match(c, mustRow(c, rs), 0)
+
+	// Steps are idempotent.
+	for _, step := range bootstrapSteps {
+		c.Assert(step(se), IsNil)
+	}
+	rs = mustExecSQL(c, se, `SELECT COUNT(*) FROM mysql.user WHERE User = "root"`)
+	match(c, mustRow(c, rs), 1)
+	mustExecSQL(c, se, "drop database "+s.dbName)
+}
+
+func (s *testBootstrapSuite) TestUpgrade(c *C) {
+	store := newStore(c, s.dbName+"_upgrade")
+	defer store.Close()
+	se := newSession(c, store, s.dbName)
+	c.Assert(s.bootstrapVersion(c, store), Equals, currentBootstrapVersion)
+
+	// A newer binary has one more step, it runs once when the store is opened.
+	oldSteps, oldVersion := bootstrapSteps, currentBootstrapVersion
+	defer func() {
+		bootstrapSteps, currentBootstrapVersion = oldSteps, oldVersion
+	}()
+	var runs int
+	bootstrapSteps = append(oldSteps[:len(oldSteps):len(oldSteps)], func(s Session) error {
+		runs++
+		return execBootstrapSQLs(s, []string{`INSERT IGNORE INTO mysql.help_topic VALUES (1, "HELP", 1, "", "", "")`})
+	})
+	currentBootstrapVersion++
+	bootstrapMu.Lock()
+	delete(bootstrapped, store.UUID())
+	bootstrapMu.Unlock()
+
+	for i := 0; i < 2; i++ {
+		se1, err := CreateSession(store)
+		c.Assert(err, IsNil)
+		se1.Close()
+	}
+	c.Assert(runs, Equals, 1)
+	c.Assert(s.bootstrapVersion(c, store), Equals, currentBootstrapVersion)
+	rs := mustExecSQL(c, se, `SELECT name FROM mysql.help_topic`)
+	match(c, mustRow(c, rs), "HELP")
+
+	// The version never goes back.
+	err := kv.RunInNewTxn(store, false, func(txn kv.Transaction) error {
+		return meta.SetBootstrapVersion(txn, 1)
+	})
+	c.Assert(err, IsNil)
+	c.Assert(s.bootstrapVersion(c, store), Equals, currentBootstrapVersion)
+	mustExecSQL(c, se, "drop database "+s.dbName)
+}
+
+func (s *testBootstrapSuite) TestUpgradeSuperPriv(c *C) {
+	store := newStore(c, s.dbName+"_super_priv")
+	defer store.Close()
+
+	// The store is bootstrapped by a binary whose account table has no Super_priv column.
+	oldSteps, oldVersion := bootstrapSteps, currentBootstrapVersion
+	defer func() {
+		bootstrapSteps, currentBootstrapVersion = oldSteps, oldVersion
+	}()
+	bootstrapSteps = []func(Session) error{func(s Session) error {
+		return execBootstrapSQLs(s, []string{
+			"CREATE DATABASE IF NOT EXISTS " + mysql.SystemDB,
+			CreateDBPrivTable,
+			CreateTablePrivTable,
+			CreateColumnPrivTable,
+			strings.Replace(CreateUserTable, "Super_priv ENUM('N','Y') NOT NULL DEFAULT 'N',", "", 1),
+			`INSERT IGNORE INTO mysql.user VALUES ("%", "root", "", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y")`,
+			`INSERT IGNORE INTO mysql.user VALUES ("%", "app", "", "Y", "N", "N", "N", "N", "N", "N", "N", "N", "N")`,
+		})
+	}, bootstrapGlobalVariables}
+	currentBootstrapVersion = 2
+	se := newSession(c, store, s.dbName)
+	c.Assert(s.bootstrapVersion(c, store), Equals, int64(2))
+
+	bootstrapSteps = append(bootstrapSteps, bootstrapSuperPriv)
+	currentBootstrapVersion = 3
+	bootstrapMu.Lock()
+	delete(bootstrapped, store.UUID())
+	bootstrapMu.Unlock()
+	se1, err := CreateSession(store)
+	c.Assert(err, IsNil)
+	se1.Close()
+	c.Assert(s.bootstrapVersion(c, store), Equals, int64(3))
+	rs := mustExecSQL(c, se, `SELECT Super_priv FROM mysql.user WHERE User = "root"`)
+	match(c, mustRow(c, rs), "Y")
+	rs = mustExecSQL(c, se, `SELECT Super_priv FROM mysql.user WHERE User = "app"`)
+	match(c, mustRow(c, rs), "N")
+	c.Assert(bootstrapSuperPriv(se), IsNil)
+	mustExecSQL(c, se, "drop database "+s.dbName)
+}
@@ -18,6 +18,7 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/Dong-Chan/alloydb/context"
@@ -49,7 +50,9 @@ type indexKey struct {
 
 func (c *Col) getTypeStr() string {
 	ans := []string{types.FieldTypeToStr(c.Tp, c.Charset)}
-	if c.Flen != -1 {
+	if c.Tp == mysql.TypeEnum || c.Tp == mysql.TypeSet {
+		ans = append(ans, types.ElemsToStr(c.Elems))
+	} else if c.Flen != -1 {
 		if c.Decimal == -1 {
 			ans = append(ans, fmt.Sprintf("(%d)", c.Flen))
 		} else {
@@ -123,26 +126,90 @@ func FindOnUpdateCols(cols []*Col) []*Col {
 	return rcols
 }
 
+// SQLModeGetter gets the sql_mode of the session bound to ctx.
+// Currently, it is assigned to variable.GetSQLMode in alloydb package's init function.
+// If it is nil, strict mode is used.
+var SQLModeGetter func(ctx context.Context) mysql.SQLMode
+
+func getSQLMode(ctx context.Context) mysql.SQLMode {
+	if SQLModeGetter == nil {
+		return mysql.ModeStrictTransTables
+	}
+	return SQLModeGetter(ctx)
+}
+
+// TimeZoneGetter gets the time zone of the session bound to ctx.
+// Currently, it is assigned to variable.GetTimeZone in alloydb package's init function.
+// If it is nil, the local time zone is used.
+var TimeZoneGetter func(ctx context.Context) *time.Location
+
+func getTimeZone(ctx context.Context) *time.Location {
+	if TimeZoneGetter == nil {
+		return time.Local
+	}
+	return TimeZoneGetter(ctx)
+}
+
+// WarningAppender appends a warning to the session bound to ctx.
+// Currently, it is assigned to variable.AppendWarning in alloydb package's init function.
+var WarningAppender func(ctx context.Context, err error)
+
+func appendWarning(ctx context.Context, err error) {
+	if WarningAppender != nil {
+		WarningAppender(ctx, err)
+	}
+}
+
+// newColumnError creates a mysql error with code for a value of the column.
+// The row number in the MySQL message is unknown here, so it is left out.
+func newColumnError(code uint16, args ...interface{}) error {
+	format := strings.TrimSuffix(mysql.MySQLErrName[code], " at row %ld")
+	return mysql.NewError(code, fmt.Sprintf(format, args...))
+}
+
 func newParseColError(err error, c *Col) error {
 	return errors.Errorf("parse err %v at column %s (type %s)", err, c.Name, types.FieldTypeToStr(c.Tp, c.Charset))
 }
 
 // CastValue casts a value based on column's type.
+// In strict mode, a value that is out of range or can't be converted to the column type is an error.
+// Otherwise the value is adjusted to the closest valid value, like MySQL does with a warning.
 func (c *Col) CastValue(ctx context.Context, val interface{}) (casted interface{}, err error) {
 	if val == nil {
 		return
 	}
+	mode := getSQLMode(ctx)
+	strict := mode.HasStrictMode()
 	switch c.Tp {
 	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeYear:
 		intVal, errCode := c.normalizeIntegerValue(val)
 		if errCode == errCodeType {
+			if _, ok := val.(string); ok && !strict {
+				// Invalid number string is cast to 0.
+				appendWarning(ctx, newColumnError(mysql.ErTruncatedWrongValueForField, "integer", val, c.Name.O))
+				return c.castIntegerValue(0, errCodeOK)
+			}
 			casted = intVal
 			err = c.TypeError(val)
 			return
 		}
-		return c.castIntegerValue(intVal, errCode)
+		casted, err = c.castIntegerValue(intVal, errCode)
+		if err != nil {
+			err = newColumnError(mysql.ErWarnDataOutOfRange, c.Name.O)
+			if !strict {
+				// Use the clipped value.
+				appendWarning(ctx, err)
+				err = nil
+			}
+		}
+		return
 	case mysql.TypeFloat, mysql.TypeDouble:
-		return c.castFloatValue(val)
+		casted, err = c.castFloatValue(val)
+		if _, ok := val.(string); ok && err != nil && !strict {
+			appendWarning(ctx, newColumnError(mysql.ErTruncatedWrongValueForField, "double", val, c.Name.O))
+			casted, err = c.castFloatValue(float64(0))
+		}
+		return
 	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
 		switch v := val.(type) {
 		case int64:
@@ -168,6 +235,22 @@ func (c *Col) CastValue(ctx context.Context, val interface{}) (casted interface{
 			}
 		default:
 			err = c.TypeError(val)
+			return
+		}
+		if err != nil {
+			if strict {
+				return
+			}
+			// Invalid date is cast to zero date.
+			appendWarning(ctx, newColumnError(mysql.WarnDataTruncated, c.Name.O))
+			casted, err = mysql.Time{Time: mysql.ZeroTime, Type: c.Tp, Fsp: c.Decimal}, nil
+		}
+		if t, ok := casted.(mysql.Time); ok {
+			err = mysql.CheckZeroDate(t, mode)
+			if c.Tp == mysql.TypeTimestamp {
+				// The date and clock of a timestamp value are in the time zone of the session.
+				casted = t.AtLocation(getTimeZone(ctx))
+			}
 		}
 	case mysql.TypeDuration:
 		switch v := val.(type) {
@@ -199,6 +282,8 @@ func (c *Col) CastValue(ctx context.Context, val interface{}) (casted interface{
 			strV = v.String()
 		case mysql.Duration:
 			strV = v.String()
+		case mysql.Bit:
+			strV = v.ToString()
 		case []byte:
 			if c.Charset == charset.CharsetBin {
 				casted = v
@@ -209,14 +294,29 @@ func (c *Col) CastValue(ctx context.Context, val interface{}) (casted interface{
 			strV = fmt.Sprintf("%v", val)
 		}
 		if (c.Flen != types.UnspecifiedLength) && (len(strV) > c.Flen) {
+			if strict {
+				err = newColumnError(mysql.ErDataTooLong, c.Name.O)
+				return
+			}
+			appendWarning(ctx, newColumnError(mysql.WarnDataTruncated, c.Name.O))
 			strV = strV[:c.Flen]
 		}
 		casted = strV
+		// The value of the binary charset is a binary string.
+		if c.Charset == charset.CharsetBin {
+			casted = []byte(strV)
+		}
 	case mysql.TypeDecimal, mysql.TypeNewDecimal:
 		switch v := val.(type) {
 		case string:
 			casted, err = mysql.ParseDecimal(v)
 			if err != nil {
+				if !strict {
+					// Invalid number string is cast to 0.
+					appendWarning(ctx, newColumnError(mysql.ErTruncatedWrongValueForField, "decimal", v, c.Name.O))
+					casted, err = mysql.NewDecimalFromInt(0, 0), nil
+					return
+				}
 				err = newParseColError(err, c)
 			}
 		case int8:
@@ -245,13 +345,185 @@ func (c *Col) CastValue(ctx context.Context, val interface{}) (casted interface{
 			casted = mysql.NewDecimalFromFloat(float64(v))
 		case mysql.Decimal:
 			casted = v
+		case mysql.Enum:
+			casted = mysql.NewDecimalFromUint(v.Value, 0)
+		case mysql.Set:
+			casted = mysql.NewDecimalFromUint(v.Value, 0)
+		case mysql.Bit:
+			casted = mysql.NewDecimalFromUint(v.Value, 0)
 		}
+	case mysql.TypeEnum:
+		casted, err = c.castEnumValue(ctx, val, strict)
+	case mysql.TypeSet:
+		casted, err = c.castSetValue(ctx, val, strict)
+	case mysql.TypeBit:
+		casted, err = c.castBitValue(ctx, val, strict)
+	case mysql.TypeJSON:
+		casted, err = c.castJSONValue(val)
 	default:
 		err = c.TypeError(val)
 	}
 	return
 }
 
+// enumLikeName returns the name to find the elements of an ENUM or SET column for val,
+// ok is false if val is a number.
+func enumLikeName(val interface{}) (name string, ok bool) {
+	switch v := val.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	case mysql.Enum:
+		return v.Name, true
+	case mysql.Set:
+		return v.Name, true
+	}
+	return "", false
+}
+
+// enumLikeNumber returns the number to find the elements of an ENUM or SET column for val.
+func (c *Col) enumLikeNumber(val interface{}) (uint64, error) {
+	switch v := val.(type) {
+	case uint64:
+		return v, nil
+	case int64:
+		if v < 0 {
+			return 0, errors.Errorf("number %v is out of range", val)
+		}
+		return uint64(v), nil
+	}
+	f, err := types.ToFloat64(val)
+	if err != nil {
+		return 0, c.TypeError(val)
+	}
+	f = types.RoundFloat(f)
+	if f < 0 || f > math.MaxUint64 {
+		return 0, errors.Errorf("number %v is out of range", val)
+	}
+	return uint64(f), nil
+}
+
+// castEnumValue casts val to an element of the ENUM column, by name for a string,
+// or by index number for a number. An invalid value is an error in strict mode,
+// otherwise it is cast to the special error value with a warning.
+func (c *Col) castEnumValue(ctx context.Context, val interface{}, strict bool) (interface{}, error) {
+	var (
+		e   mysql.Enum
+		err error
+	)
+	if name, ok := enumLikeName(val); ok {
+		e, err = mysql.ParseEnumName(c.Elems, name)
+	} else {
+		var n uint64
+		if n, err = c.enumLikeNumber(val); err == nil {
+			e, err = mysql.ParseEnumValue(c.Elems, n)
+		}
+	}
+	if err != nil {
+		if strict {
+			return nil, newColumnError(mysql.WarnDataTruncated, c.Name.O)
+		}
+		appendWarning(ctx, newColumnError(mysql.WarnDataTruncated, c.Name.O))
+		return mysql.Enum{}, nil
+	}
+	return e, nil
+}
+
+// castSetValue casts val to a set of the elements of the SET column, by comma separated names
+// for a string, or by the bits of a number. An invalid value is an error in strict mode,
+// otherwise it is cast to the empty set with a warning.
+func (c *Col) castSetValue(ctx context.Context, val interface{}, strict bool) (interface{}, error) {
+	var (
+		set mysql.Set
+		err error
+	)
+	if name, ok := enumLikeName(val); ok {
+		set, err = mysql.ParseSetName(c.Elems, name)
+	} else {
+		var n uint64
+		if n, err = c.enumLikeNumber(val); err == nil {
+			set, err = mysql.ParseSetValue(c.Elems, n)
+		}
+	}
+	if err != nil {
+		if strict {
+			return nil, newColumnError(mysql.WarnDataTruncated, c.Name.O)
+		}
+		appendWarning(ctx, newColumnError(mysql.WarnDataTruncated, c.Name.O))
+		return mysql.Set{}, nil
+	}
+	return set, nil
+}
+
+// castBitValue casts val to the bits of the BIT column. A string is taken as a binary string,
+// like MySQL does, so '1' is 0x31. A value that doesn't fit in the width is an error in strict mode,
+// otherwise it is cast to the maximum value with a warning.
+func (c *Col) castBitValue(ctx context.Context, val interface{}, strict bool) (interface{}, error) {
+	width := c.Flen
+	if width == types.UnspecifiedLength {
+		width = mysql.MinBitWidth
+	}
+	var (
+		b   mysql.Bit
+		err error
+	)
+	switch v := val.(type) {
+	case string:
+		b, err = mysql.BitFromString(v, width)
+	case []byte:
+		b, err = mysql.BitFromString(string(v), width)
+	case mysql.Bit:
+		b, err = mysql.NewBit(v.Value, width)
+	default:
+		var n uint64
+		if n, err = c.enumLikeNumber(val); err == nil {
+			b, err = mysql.NewBit(n, width)
+		}
+	}
+	if err != nil {
+		if strict {
+			return nil, newColumnError(mysql.ErDataTooLong, c.Name.O)
+		}
+		appendWarning(ctx, newColumnError(mysql.ErDataTooLong, c.Name.O))
+		var max uint64 = math.MaxUint64
+		if width < mysql.MaxBitWidth {
+			max = 1<<uint(width) - 1
+		}
+		return mysql.Bit{Value: max, Width: width}, nil
+	}
+	return b, nil
+}
+
+// castJSONValue casts val to a JSON value of the JSON column. A string is parsed as JSON text,
+// an invalid JSON text is an error even if strict mode is not enabled, like MySQL does.
+// Other values are cast to JSON scalars.
+func (c *Col) castJSONValue(val interface{}) (interface{}, error) {
+	var s string
+	switch v := val.(type) {
+	case mysql.JSON:
+		return v, nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		j, err := mysql.CreateJSON(val)
+		if err != nil {
+			return nil, c.TypeError(val)
+		}
+		return j, nil
+	}
+	j, err := mysql.ParseJSON(s)
+	if err != nil {
+		if e, ok := err.(*mysql.JSONSyntaxError); ok {
+			return nil, newColumnError(mysql.ErInvalidJSONText, e.Msg, e.Offset, c.Name.O)
+		}
+		return nil, newColumnError(mysql.ErInvalidJSONText, err.Error(), 0, c.Name.O)
+	}
+	return j, nil
+}
+
 // TypeError returns error for invalid value type.
 func (c *Col) TypeError(v interface{}) error {
 	return errors.Errorf("cannot use %v (type %T) in assignment to, or comparison with, column %s (type %s)",
@@ -354,6 +626,12 @@ func (c *Col) normalizeIntegerValue(x interface{}) (val int64, errCode int) {
 		val, errCode = c.normalizeIntegerFromFloat(v)
 	case string:
 		val, errCode = c.normalizeIntegerFromString(v)
+	case mysql.Enum:
+		val, errCode = c.normalizeIntegerFromUint(v.Value)
+	case mysql.Set:
+		val, errCode = c.normalizeIntegerFromUint(v.Value)
+	case mysql.Bit:
+		val, errCode = c.normalizeIntegerFromUint(v.Value)
 	default:
 		errCode = errCodeType
 	}
@@ -519,6 +797,12 @@ func (c *Col) castFloatValue(x interface{}) (casted interface{}, err error) {
 		fval = float64(v)
 	case float64:
 		fval = v
+	case mysql.Enum:
+		fval = v.ToNumber()
+	case mysql.Set:
+		fval = v.ToNumber()
+	case mysql.Bit:
+		fval = v.ToNumber()
 	case string:
 		v = strings.Trim(v, " \t\r\n")
 		fval, err = strconv.ParseFloat(v, 64)
@@ -571,7 +855,9 @@ const defaultPrivileges string = "select,insert,update,references"
 
 func (c *Col) getTypeDesc() string {
 	ans := []string{types.FieldTypeToStr(c.Tp, c.Charset)}
-	if c.Flen != -1 {
+	if c.Tp == mysql.TypeEnum || c.Tp == mysql.TypeSet {
+		ans = append(ans, types.ElemsToStr(c.Elems))
+	} else if c.Flen != -1 {
 		if c.Decimal == -1 {
 			ans = append(ans, fmt.Sprintf("(%d)", c.Flen))
 		} else {
@@ -17,10 +17,15 @@ import (
 	"fmt"
 	"math"
 	"testing"
+	"time"
 
+	"github.com/juju/errors"
 	. "github.com/pingcap/check"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/kv"
 	"github.com/Dong-Chan/alloydb/model"
 	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/util/charset"
 	"github.com/Dong-Chan/alloydb/util/types"
 )
 
@@ -204,6 +209,209 @@ func (s *testColumnSuite) TestCastValue(c *C) {
 	signedAccept(c, mysql.TypeNewDecimal, mysql.NewDecimalFromInt(-123, -5), "-0.00123")
 }
 
+func (s *testColumnSuite) TestCastValueSQLMode(c *C) {
+	defer func() {
+		SQLModeGetter = nil
+	}()
+
+	mode := mysql.ModeNone
+	SQLModeGetter = func(ctx context.Context) mysql.SQLMode {
+		return mode
+	}
+
+	// Out of range and invalid values are adjusted in non-strict mode.
+	signedAccept(c, mysql.TypeTiny, -129, "-128")
+	unsignedAccept(c, mysql.TypeLong, -1, "0")
+	signedAccept(c, mysql.TypeLong, "abc", "0")
+	signedAccept(c, mysql.TypeDouble, "abc", "0")
+	signedAccept(c, mysql.TypeNewDecimal, "abc", "0")
+	signedAccept(c, mysql.TypeDate, "2012-08-x", "0000-00-00")
+	signedDeny(c, mysql.TypeDuration, 0, "<nil>")
+
+	col := newCol("c")
+	col.Tp = mysql.TypeVarchar
+	col.Flen = 3
+	v, err := col.CastValue(nil, "abcd")
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, "abc")
+
+	mode = mysql.ModeStrictAllTables
+	_, err = col.CastValue(nil, "abcd")
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErDataTooLong))
+	signedDeny(c, mysql.TypeLong, "abc", "0")
+	signedAccept(c, mysql.TypeDate, "0000-00-00", "0000-00-00")
+
+	col.Tp = mysql.TypeTiny
+	_, err = col.CastValue(nil, 1000)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErWarnDataOutOfRange))
+	c.Assert(err.Error(), Matches, ".*Out of range value for column 'c'")
+
+	mode = mysql.ModeStrictAllTables | mysql.ModeNoZeroDate
+	signedDeny(c, mysql.TypeDate, "0000-00-00", "0000-00-00")
+	signedAccept(c, mysql.TypeDate, "2012-08-23", "2012-08-23")
+}
+
+func (s *testColumnSuite) TestCastValueWarnings(c *C) {
+	var warnings []error
+	SQLModeGetter = func(ctx context.Context) mysql.SQLMode {
+		return mysql.ModeNone
+	}
+	WarningAppender = func(ctx context.Context, err error) {
+		warnings = append(warnings, err)
+	}
+	defer func() {
+		SQLModeGetter = nil
+		WarningAppender = nil
+	}()
+
+	col := newCol("c")
+	col.Tp = mysql.TypeLong
+	_, err := col.CastValue(nil, "abc")
+	c.Assert(err, IsNil)
+	c.Assert(warnings, HasLen, 1)
+	c.Assert(errors.Cause(warnings[0]).(*mysql.SQLError).Code, Equals, uint16(mysql.ErTruncatedWrongValueForField))
+	c.Assert(warnings[0].Error(), Matches, ".*Incorrect integer value: 'abc' for column 'c'")
+
+	col.Tp = mysql.TypeTiny
+	_, err = col.CastValue(nil, 1000)
+	c.Assert(err, IsNil)
+	c.Assert(warnings, HasLen, 2)
+	c.Assert(errors.Cause(warnings[1]).(*mysql.SQLError).Code, Equals, uint16(mysql.ErWarnDataOutOfRange))
+
+	col.Tp = mysql.TypeVarchar
+	col.Flen = 3
+	_, err = col.CastValue(nil, "abcd")
+	c.Assert(err, IsNil)
+	c.Assert(warnings, HasLen, 3)
+	c.Assert(errors.Cause(warnings[2]).(*mysql.SQLError).Code, Equals, uint16(mysql.WarnDataTruncated))
+
+	// Valid values generate no warnings.
+	_, err = col.CastValue(nil, "abc")
+	c.Assert(err, IsNil)
+	c.Assert(warnings, HasLen, 3)
+}
+
+func (s *testColumnSuite) TestCastTimestamp(c *C) {
+	loc := time.FixedZone("+08:00", 8*3600)
+	TimeZoneGetter = func(ctx context.Context) *time.Location {
+		return loc
+	}
+	defer func() {
+		TimeZoneGetter = nil
+	}()
+
+	// The date and clock of a timestamp are in the time zone of the session.
+	col := newCol("c")
+	col.Tp = mysql.TypeTimestamp
+	v, err := col.CastValue(nil, "2015-01-01 10:00:00")
+	c.Assert(err, IsNil)
+	t := v.(mysql.Time)
+	c.Assert(t.String(), Equals, "2015-01-01 10:00:00")
+	c.Assert(t.Time.UTC().Hour(), Equals, 2)
+
+	// Datetime is not changed.
+	col.Tp = mysql.TypeDatetime
+	v, err = col.CastValue(nil, "2015-01-01 10:00:00")
+	c.Assert(err, IsNil)
+	c.Assert(v.(mysql.Time).Location(), Equals, time.Local)
+}
+
+func (s *testColumnSuite) TestCastBinary(c *C) {
+	// The value of the binary charset is a binary string.
+	col := newCol("c")
+	col.Tp = mysql.TypeVarchar
+	col.Flen = types.UnspecifiedLength
+	col.Charset = charset.CharsetBin
+	v, err := col.CastValue(nil, "abc")
+	c.Assert(err, IsNil)
+	c.Assert(v, DeepEquals, []byte("abc"))
+
+	col.Charset = "utf8"
+	v, err = col.CastValue(nil, []byte("abc"))
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, "abc")
+}
+
+func (s *testColumnSuite) TestCastEnumSetBit(c *C) {
+	mode := mysql.ModeNone
+	var warnings []error
+	SQLModeGetter = func(ctx context.Context) mysql.SQLMode {
+		return mode
+	}
+	WarningAppender = func(ctx context.Context, err error) {
+		warnings = append(warnings, err)
+	}
+	defer func() {
+		SQLModeGetter = nil
+		WarningAppender = nil
+	}()
+
+	col := newCol("c")
+	col.Tp = mysql.TypeEnum
+	col.Elems = []string{"a", "b"}
+	v, err := col.CastValue(nil, "B")
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, mysql.Enum{Name: "b", Value: 2})
+	v, err = col.CastValue(nil, 1)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, mysql.Enum{Name: "a", Value: 1})
+	v, err = col.CastValue(nil, "c")
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, mysql.Enum{})
+	c.Assert(warnings, HasLen, 1)
+
+	col.Tp = mysql.TypeSet
+	v, err = col.CastValue(nil, "b,a")
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, mysql.Set{Name: "a,b", Value: 3})
+	v, err = col.CastValue(nil, 2)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, mysql.Set{Name: "b", Value: 2})
+
+	col.Tp = mysql.TypeBit
+	col.Flen = 8
+	v, err = col.CastValue(nil, "A")
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, mysql.Bit{Value: 65, Width: 8})
+	v, err = col.CastValue(nil, 300)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, mysql.Bit{Value: 255, Width: 8})
+	c.Assert(warnings, HasLen, 2)
+
+	mode = mysql.ModeStrictAllTables
+	_, err = col.CastValue(nil, 300)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErDataTooLong))
+	col.Tp = mysql.TypeEnum
+	_, err = col.CastValue(nil, "c")
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.WarnDataTruncated))
+	col.Tp = mysql.TypeSet
+	_, err = col.CastValue(nil, 4)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.WarnDataTruncated))
+
+	// The element list is shown in the column type.
+	col.Tp = mysql.TypeEnum
+	col.Flen = types.UnspecifiedLength
+	c.Assert(NewColDesc(col).Type, Equals, "ENUM ('a','b')")
+}
+
+func (s *testColumnSuite) TestCastJSON(c *C) {
+	col := newCol("c")
+	col.Tp = mysql.TypeJSON
+	v, err := col.CastValue(nil, `{"a": [1, true]}`)
+	c.Assert(err, IsNil)
+	c.Assert(v.(mysql.JSON).String(), Equals, `{"a": [1, true]}`)
+	v, err = col.CastValue(nil, v)
+	c.Assert(err, IsNil)
+	c.Assert(v.(mysql.JSON).String(), Equals, `{"a": [1, true]}`)
+	v, err = col.CastValue(nil, 1)
+	c.Assert(err, IsNil)
+	c.Assert(v.(mysql.JSON).Type(), Equals, mysql.JSONTypeInteger)
+
+	// Invalid documents are rejected whatever the SQL mode is.
+	_, err = col.CastValue(nil, "{a}")
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErInvalidJSONText))
+}
+
 func (s *testColumnSuite) TestString(c *C) {
 	col := &Col{
 		model.ColumnInfo{
@@ -262,3 +470,28 @@ func newCol(name string) *Col {
 		},
 	}
 }
+
+func (s *testColumnSuite) TestSortKey(c *C) {
+	newCol := func(tp byte, collate string) *Col {
+		col := &Col{model.ColumnInfo{FieldType: *types.NewFieldType(tp)}}
+		col.Collate = collate
+		return col
+	}
+	ci := newCol(mysql.TypeVarchar, "utf8_general_ci")
+	bin := newCol(mysql.TypeVarchar, "utf8_bin")
+	num := newCol(mysql.TypeLong, charset.CharsetBin)
+
+	c.Assert(ci.SortKey("abc"), Equals, ci.SortKey("ABC "))
+	c.Assert(bin.SortKey("abc"), Not(Equals), bin.SortKey("ABC"))
+	c.Assert(bin.SortKey("abc"), Equals, bin.SortKey("abc "))
+	c.Assert(num.SortKey("abc"), Equals, "abc")
+	c.Assert(ci.SortKey(int64(1)), Equals, int64(1))
+	c.Assert(ci.SortKey(nil), IsNil)
+
+	x := kv.NewKVIndex("t", "idx", true)
+	c.Assert(NewCollatedIndex(x, []*Col{num}), Equals, x)
+	cx := NewCollatedIndex(x, []*Col{num, ci})
+	c.Assert(cx, Not(Equals), x)
+	c.Assert(BaseIndex(cx), Equals, x)
+	c.Assert(BaseIndex(x), Equals, x)
+}
@@ -0,0 +1,84 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package column
+
+import (
+	"github.com/Dong-Chan/alloydb/kv"
+	"github.com/Dong-Chan/alloydb/util/charset"
+)
+
+// SortKey returns the value used in the index for v. A string compared by a
+// non-binary collation is indexed by its sort key, so the strings equal by
+// the collation have the same index entry.
+func (c *Col) SortKey(v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok || charset.IsBinaryCollation(c.Collate) {
+		return v
+	}
+	return charset.GetCollator(c.Collate).Key(s)
+}
+
+// collatedIndex converts the indexed values to the sort keys of the column collations.
+type collatedIndex struct {
+	kv.Index
+	cols []*Col
+}
+
+// NewCollatedIndex wraps the index x of the columns cols, the string values
+// of the columns with non-binary collations are converted to sort keys before
+// they are written to or searched in x.
+// If all the columns use the binary collation, x is returned.
+func NewCollatedIndex(x kv.Index, cols []*Col) kv.Index {
+	for _, c := range cols {
+		if !charset.IsBinaryCollation(c.Collate) {
+			return &collatedIndex{Index: x, cols: cols}
+		}
+	}
+	return x
+}
+
+// BaseIndex returns the index wrapped by NewCollatedIndex, the values
+// used with it must be sort keys already.
+func BaseIndex(x kv.Index) kv.Index {
+	if ci, ok := x.(*collatedIndex); ok {
+		return ci.Index
+	}
+	return x
+}
+
+func (x *collatedIndex) sortKeys(vals []interface{}) []interface{} {
+	keys := make([]interface{}, len(vals))
+	for i, v := range vals {
+		if i < len(x.cols) {
+			v = x.cols[i].SortKey(v)
+		}
+		keys[i] = v
+	}
+	return keys
+}
+
+// Create implements kv.Index Create interface.
+func (x *collatedIndex) Create(txn kv.Transaction, indexedValues []interface{}, h int64) error {
+	return x.Index.Create(txn, x.sortKeys(indexedValues), h)
+}
+
+// Delete implements kv.Index Delete interface.
+func (x *collatedIndex) Delete(txn kv.Transaction, indexedValues []interface{}, h int64) error {
+	return x.Index.Delete(txn, x.sortKeys(indexedValues), h)
+}
+
+// Seek implements kv.Index Seek interface.
+func (x *collatedIndex) Seek(txn kv.Transaction, indexedValues []interface{}) (kv.IndexIterator, bool, error) {
+	return x.Index.Seek(txn, x.sortKeys(indexedValues))
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/Dong-Chan/alloydb/util/charset"
 	qerror "github.com/Dong-Chan/alloydb/util/errors"
 	"github.com/Dong-Chan/alloydb/util/errors2"
+	"github.com/Dong-Chan/alloydb/util/types"
 )
 
 // Pre-defined errors
@@ -222,16 +223,80 @@ func (d *ddl) buildColumnsAndConstraints(colDefs []*coldef.ColumnDef, constraint
 	return cols, constraints, nil
 }
 
-func (d *ddl) buildColumnAndConstraint(offset int, colDef *coldef.ColumnDef) (*column.Col, []*coldef.TableConstraint, error) {
-	// set charset
-	if len(colDef.Tp.Charset) == 0 {
-		switch colDef.Tp.Tp {
-		case mysql.TypeString, mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
-			colDef.Tp.Charset, colDef.Tp.Collate = getDefaultCharsetAndCollate()
-		default:
-			colDef.Tp.Charset = charset.CharsetBin
-			colDef.Tp.Collate = charset.CharsetBin
+// setCharsetAndCollate sets the charset and the collation of the column type tp.
+// A missing collation is the default collation of the charset, and a missing charset
+// is the charset of the collation. The BINARY attribute of a string type means the
+// binary collation of the charset.
+func setCharsetAndCollate(tp *types.FieldType) error {
+	switch tp.Tp {
+	case mysql.TypeString, mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob,
+		mysql.TypeEnum, mysql.TypeSet:
+	default:
+		tp.Charset = charset.CharsetBin
+		tp.Collate = charset.CharsetBin
+		return nil
+	}
+
+	tp.Collate = strings.ToLower(tp.Collate)
+	switch {
+	case tp.Charset == "" && tp.Collate == "":
+		tp.Charset, tp.Collate = getDefaultCharsetAndCollate()
+	case tp.Charset == "":
+		c, err := charset.GetCollationByName(tp.Collate)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		tp.Charset = c.CharsetName
+	case tp.Collate == "":
+		co, err := charset.GetDefaultCollation(tp.Charset)
+		if err != nil {
+			return errors.Trace(err)
 		}
+		tp.Collate = co
+	}
+	if tp.Charset != charset.CharsetBin && !charset.ValidCharsetAndCollation(tp.Charset, tp.Collate) {
+		return errors.Errorf("COLLATION '%s' is not valid for CHARACTER SET '%s'", tp.Collate, tp.Charset)
+	}
+	if mysql.HasBinaryFlag(tp.Flag) && tp.Charset != charset.CharsetBin {
+		tp.Collate = tp.Charset + "_bin"
+	}
+	return nil
+}
+
+// checkColumnType checks the element list of enum and set types and the width of bit type.
+func checkColumnType(name string, tp *types.FieldType) error {
+	switch tp.Tp {
+	case mysql.TypeEnum, mysql.TypeSet:
+		if tp.Tp == mysql.TypeSet && len(tp.Elems) > mysql.MaxSetElements {
+			return mysql.NewDefaultError(mysql.ErTooBigSet, name)
+		}
+		m := make(map[string]bool, len(tp.Elems))
+		for i, e := range tp.Elems {
+			e = strings.TrimRight(e, " ")
+			if tp.Tp == mysql.TypeSet && strings.Contains(e, ",") {
+				return errors.Errorf("Illegal set '%s' value found during parsing", e)
+			}
+			k := strings.ToLower(e)
+			if m[k] {
+				return mysql.NewDefaultError(mysql.ErDuplicatedValueInType, name, e, strings.ToUpper(types.TypeToStr(tp.Tp, false)))
+			}
+			m[k] = true
+			tp.Elems[i] = e
+		}
+	case mysql.TypeBit:
+		if tp.Flen < mysql.MinBitWidth || tp.Flen > mysql.MaxBitWidth {
+			return mysql.NewDefaultError(mysql.ErTooBigDisplaywidth, name, mysql.MaxBitWidth)
+		}
+	}
+	return nil
+}
+
+func (d *ddl) buildColumnAndConstraint(offset int, colDef *coldef.ColumnDef) (*column.Col, []*coldef.TableConstraint, error) {
+	if err := setCharsetAndCollate(colDef.Tp); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if err := checkColumnType(colDef.Name, colDef.Tp); err != nil {
+		return nil, nil, errors.Trace(err)
 	}
 	// convert colDef into col
 	col, cts, err := coldef.ColumnDefToCol(offset, colDef)
@@ -277,6 +342,9 @@ func (d *ddl) buildTableInfo(tableName model.CIStr, cols []*column.Col, constrai
 			if col == nil {
 				return nil, errors.Errorf("No such column: %v", key)
 			}
+			if col.Tp == mysql.TypeJSON {
+				return nil, errors.Trace(mysql.NewDefaultError(mysql.ErJSONUsedAsKey, col.Name.O))
+			}
 			indexColumns = append(indexColumns, &model.IndexColumn{
 				Name:   model.NewCIStr(key.ColumnName),
 				Offset: col.Offset,
@@ -483,6 +551,9 @@ func (d *ddl) CreateIndex(ctx context.Context, ti table.Ident, unique bool, inde
 		if col == nil {
 			return errors.Errorf("CREATE INDEX: column does not exist: %s", ic.ColumnName)
 		}
+		if col.Tp == mysql.TypeJSON {
+			return errors.Trace(mysql.NewDefaultError(mysql.ErJSONUsedAsKey, col.Name.O))
+		}
 		idxColumns = append(idxColumns, &model.IndexColumn{
 			Name:   col.Name,
 			Offset: col.Offset,
@@ -538,9 +609,13 @@ func (d *ddl) buildIndex(ctx context.Context, t table.Table, idxInfo *model.Inde
 		// TODO: v is timestamp ?
 		// fetch datas
 		cols := t.Cols()
-		var vals []interface{}
+		var (
+			vals    []interface{}
+			idxCols []*column.Col
+		)
 		for _, v := range idxInfo.Columns {
 			col := cols[v.Offset]
+			idxCols = append(idxCols, col)
 			k := t.RecordKey(h, col)
 			data, err := txn.Get([]byte(k))
 			if err != nil {
@@ -553,7 +628,7 @@ func (d *ddl) buildIndex(ctx context.Context, t table.Table, idxInfo *model.Inde
 			vals = append(vals, val)
 		}
 		// build index
-		kvX := kv.NewKVIndex(t.IndexPrefix(), idxInfo.Name.L, unique)
+		kvX := column.NewCollatedIndex(kv.NewKVIndex(t.IndexPrefix(), idxInfo.Name.L, unique), idxCols)
 		err = kvX.Create(txn, vals, h)
 		if err != nil {
 			return errors.Trace(err)
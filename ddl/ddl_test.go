@@ -129,6 +129,49 @@ func (ts *testSuite) TestT(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (ts *testSuite) TestColumnCollation(c *C) {
+	handle := infoschema.NewHandle(ts.store)
+	handle.Set(nil)
+	dd := ddl.NewDDL(ts.store, handle)
+	se, _ := alloydb.CreateSession(ts.store)
+	ctx := se.(context.Context)
+	tbIdent := table.Ident{
+		Schema: model.NewCIStr("test_collation"),
+		Name:   model.NewCIStr("t"),
+	}
+	err := dd.CreateSchema(ctx, tbIdent.Schema)
+	c.Assert(err, IsNil)
+
+	tbStmt := statement("create table t (a int, b varchar(10), c char(10) charset latin1, d text collate utf8_bin, e varchar(10) binary, f blob)").(*stmts.CreateTableStmt)
+	err = dd.CreateTable(ctx, tbIdent, tbStmt.Cols, tbStmt.Constraints)
+	c.Assert(err, IsNil)
+	tb, err := handle.Get().TableByName(tbIdent.Schema, tbIdent.Name)
+	c.Assert(err, IsNil)
+	expected := []struct {
+		charset string
+		collate string
+	}{
+		{"binary", "binary"},
+		{"utf8", "utf8_unicode_ci"},
+		{"latin1", "latin1_swedish_ci"},
+		{"utf8", "utf8_bin"},
+		{"utf8", "utf8_bin"},
+		{"binary", "binary"},
+	}
+	for i, col := range tb.Cols() {
+		c.Assert(col.Charset, Equals, expected[i].charset)
+		c.Assert(col.Collate, Equals, expected[i].collate)
+	}
+
+	tbIdent.Name = model.NewCIStr("t2")
+	tbStmt = statement("create table t2 (a varchar(10) charset latin1 collate utf8_bin)").(*stmts.CreateTableStmt)
+	err = dd.CreateTable(ctx, tbIdent, tbStmt.Cols, tbStmt.Constraints)
+	c.Assert(err, NotNil)
+
+	err = dd.DropSchema(ctx, tbIdent.Schema)
+	c.Assert(err, IsNil)
+}
+
 func statement(sql string) stmt.Statement {
 	lexer := parser.NewLexer(sql)
 	parser.YYParse(lexer)
@@ -23,6 +23,7 @@ import (
 	"github.com/Dong-Chan/alloydb/kv"
 	"github.com/Dong-Chan/alloydb/meta"
 	"github.com/Dong-Chan/alloydb/model"
+	"github.com/Dong-Chan/alloydb/privilege/privileges"
 	"github.com/Dong-Chan/alloydb/util"
 )
 
@@ -32,6 +33,10 @@ type Domain struct {
 	store      kv.Storage
 	infoHandle *infoschema.Handle
 	ddl        ddl.DDL
+	privHandle *privileges.Handle
+	processes  processList
+	// stmtSummaries is the statistics of the statements executed by the sessions of the domain.
+	stmtSummaries stmtSummaries
 }
 
 func (do *Domain) loadInfoSchema(txn kv.Transaction) (err error) {
@@ -62,6 +67,11 @@ func (do *Domain) DDL() ddl.DDL {
 	return do.ddl
 }
 
+// PrivilegeHandle gets the privilege cache from domain.
+func (do *Domain) PrivilegeHandle() *privileges.Handle {
+	return do.privHandle
+}
+
 // Store gets KV store from domain.
 func (do *Domain) Store() kv.Storage {
 	return do.store
@@ -72,9 +82,12 @@ func NewDomain(store kv.Storage) (d *Domain, err error) {
 	infoHandle := infoschema.NewHandle(store)
 	ddl := ddl.NewDDL(store, infoHandle)
 	d = &Domain{
-		store:      store,
-		infoHandle: infoHandle,
-		ddl:        ddl,
+		store:         store,
+		infoHandle:    infoHandle,
+		ddl:           ddl,
+		privHandle:    privileges.NewHandle(store),
+		processes:     processList{procs: make(map[int64]Process)},
+		stmtSummaries: stmtSummaries{summaries: make(map[stmtSummaryKey]*StmtSummary)},
 	}
 	err = kv.RunInNewTxn(d.store, false, d.loadInfoSchema)
 	if err != nil {
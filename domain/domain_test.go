@@ -14,7 +14,9 @@
 package domain
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/Dong-Chan/alloydb/model"
@@ -49,3 +51,75 @@ func (*testSuite) TestT(c *C) {
 	dom, err = NewDomain(store)
 	c.Assert(err, IsNil)
 }
+
+type testProcess struct {
+	info ProcessInfo
+}
+
+func (p *testProcess) ProcessInfo() *ProcessInfo {
+	return &p.info
+}
+
+func (p *testProcess) Kill(query bool) {
+	p.info.State = "killed"
+}
+
+func (*testSuite) TestProcessList(c *C) {
+	driver := localstore.Driver{goleveldb.MemoryDriver{}}
+	store, err := driver.Open("memory")
+	c.Assert(err, IsNil)
+	defer store.Close()
+
+	dom, err := NewDomain(store)
+	c.Assert(err, IsNil)
+	c.Assert(dom.ProcessList(), HasLen, 0)
+	dom.AddProcess(2, &testProcess{ProcessInfo{ID: 2, Command: "Sleep"}})
+	dom.AddProcess(1, &testProcess{ProcessInfo{ID: 1, Command: "Query", Info: "select 1"}})
+	infos := dom.ProcessList()
+	c.Assert(infos, HasLen, 2)
+	c.Assert(infos[0].ID, Equals, int64(1))
+	c.Assert(infos[0].Info, Equals, "select 1")
+	c.Assert(infos[1].Command, Equals, "Sleep")
+	p, ok := dom.GetProcess(2)
+	c.Assert(ok, IsTrue)
+	p.Kill(true)
+	c.Assert(p.ProcessInfo().State, Equals, "killed")
+	dom.RemoveProcess(1)
+	c.Assert(dom.ProcessList(), HasLen, 1)
+	_, ok = dom.GetProcess(1)
+	c.Assert(ok, IsFalse)
+}
+
+func (*testSuite) TestStmtSummaries(c *C) {
+	driver := localstore.Driver{goleveldb.MemoryDriver{}}
+	store, err := driver.Open("memory")
+	c.Assert(err, IsNil)
+	defer store.Close()
+
+	dom, err := NewDomain(store)
+	c.Assert(err, IsNil)
+	now := time.Now()
+	dom.AddStmtExec(&StmtExec{SchemaName: "test", Digest: "b", Latency: time.Second, RowsSent: 1, Time: now})
+	dom.AddStmtExec(&StmtExec{SchemaName: "test", Digest: "b", Latency: 3 * time.Second, RowsSent: 2, Failed: true, Time: now.Add(time.Second)})
+	dom.AddStmtExec(&StmtExec{SchemaName: "test", Digest: "a", Latency: time.Millisecond, Time: now})
+	summaries := dom.StmtSummaries()
+	c.Assert(summaries, HasLen, 2)
+	c.Assert(summaries[0].Digest, Equals, "a")
+	s := summaries[1]
+	c.Assert(s.Count, Equals, int64(2))
+	c.Assert(s.Errors, Equals, int64(1))
+	c.Assert(s.SumLatency, Equals, 4*time.Second)
+	c.Assert(s.MinLatency, Equals, time.Second)
+	c.Assert(s.MaxLatency, Equals, 3*time.Second)
+	c.Assert(s.SumRowsSent, Equals, uint64(3))
+	c.Assert(s.FirstSeen, Equals, now)
+	c.Assert(s.LastSeen, Equals, now.Add(time.Second))
+
+	// The least recently seen summary is evicted.
+	for i := 0; i < MaxStmtSummaries-1; i++ {
+		dom.AddStmtExec(&StmtExec{SchemaName: "test", Digest: fmt.Sprintf("c%d", i), Time: now.Add(time.Minute)})
+	}
+	summaries = dom.StmtSummaries()
+	c.Assert(summaries, HasLen, MaxStmtSummaries)
+	c.Assert(summaries[0].Digest, Equals, "b")
+}
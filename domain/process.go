@@ -0,0 +1,90 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ProcessInfo is the state of a session in the process list.
+type ProcessInfo struct {
+	ID   int64
+	User string
+	Host string
+	DB   string
+	// Command is Query while the session runs a statement, otherwise it is Sleep.
+	Command string
+	// Time is when the current command started.
+	Time  time.Time
+	State string
+	// Info is the text of the running statement.
+	Info string
+}
+
+// Process is a session in the process list.
+type Process interface {
+	// ProcessInfo returns the state of the session, it is called from the goroutines of other sessions.
+	ProcessInfo() *ProcessInfo
+	// Kill interrupts the running statement of the session, the session is closed too if query is false.
+	// It is called from the goroutines of other sessions.
+	Kill(query bool)
+}
+
+// processList is the sessions of a domain, keyed by the session ID.
+type processList struct {
+	mu    sync.RWMutex
+	procs map[int64]Process
+}
+
+// AddProcess adds the session p with id to the process list.
+func (do *Domain) AddProcess(id int64, p Process) {
+	do.processes.mu.Lock()
+	do.processes.procs[id] = p
+	do.processes.mu.Unlock()
+}
+
+// RemoveProcess removes the session id from the process list, it is called when the session is closed.
+func (do *Domain) RemoveProcess(id int64) {
+	do.processes.mu.Lock()
+	delete(do.processes.procs, id)
+	do.processes.mu.Unlock()
+}
+
+// GetProcess returns the session id in the process list.
+func (do *Domain) GetProcess(id int64) (Process, bool) {
+	do.processes.mu.RLock()
+	p, ok := do.processes.procs[id]
+	do.processes.mu.RUnlock()
+	return p, ok
+}
+
+// ProcessList returns the states of the sessions in the process list ordered by ID.
+func (do *Domain) ProcessList() []*ProcessInfo {
+	do.processes.mu.RLock()
+	infos := make([]*ProcessInfo, 0, len(do.processes.procs))
+	for _, p := range do.processes.procs {
+		infos = append(infos, p.ProcessInfo())
+	}
+	do.processes.mu.RUnlock()
+	sort.Sort(processInfos(infos))
+	return infos
+}
+
+type processInfos []*ProcessInfo
+
+func (p processInfos) Len() int           { return len(p) }
+func (p processInfos) Less(i, j int) bool { return p[i].ID < p[j].ID }
+func (p processInfos) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
@@ -0,0 +1,142 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// MaxStmtSummaries is the maximum number of the statement summaries of a domain,
+// the least recently seen summary is evicted for a new one.
+const MaxStmtSummaries = 1000
+
+// StmtExec is a finished execution of a statement.
+type StmtExec struct {
+	SchemaName string
+	// Digest is the hash of DigestText, the normalized text of the statement.
+	Digest     string
+	DigestText string
+	// PlanDigest is the hash of the shape of the plan, it is empty for the statements without plans.
+	PlanDigest   string
+	Latency      time.Duration
+	RowsSent     uint64
+	RowsExamined uint64
+	Failed       bool
+	// Time is when the statement is finished.
+	Time time.Time
+}
+
+// StmtSummary is the statistics of the executions of the statements with the same schema,
+// digest and plan digest.
+type StmtSummary struct {
+	SchemaName      string
+	Digest          string
+	DigestText      string
+	PlanDigest      string
+	Count           int64
+	Errors          int64
+	SumLatency      time.Duration
+	MinLatency      time.Duration
+	MaxLatency      time.Duration
+	SumRowsSent     uint64
+	SumRowsExamined uint64
+	FirstSeen       time.Time
+	LastSeen        time.Time
+}
+
+type stmtSummaryKey struct {
+	schemaName string
+	digest     string
+	planDigest string
+}
+
+// stmtSummaries is the statement summaries of a domain.
+type stmtSummaries struct {
+	mu        sync.Mutex
+	summaries map[stmtSummaryKey]*StmtSummary
+}
+
+// AddStmtExec adds e to the summary of its statement.
+func (do *Domain) AddStmtExec(e *StmtExec) {
+	key := stmtSummaryKey{schemaName: e.SchemaName, digest: e.Digest, planDigest: e.PlanDigest}
+	do.stmtSummaries.mu.Lock()
+	defer do.stmtSummaries.mu.Unlock()
+
+	summaries := do.stmtSummaries.summaries
+	s, ok := summaries[key]
+	if !ok {
+		if len(summaries) >= MaxStmtSummaries {
+			var oldest stmtSummaryKey
+			for k, v := range summaries {
+				if s == nil || v.LastSeen.Before(s.LastSeen) {
+					oldest, s = k, v
+				}
+			}
+			delete(summaries, oldest)
+		}
+		s = &StmtSummary{
+			SchemaName: e.SchemaName,
+			Digest:     e.Digest,
+			DigestText: e.DigestText,
+			PlanDigest: e.PlanDigest,
+			MinLatency: e.Latency,
+			FirstSeen:  e.Time,
+		}
+		summaries[key] = s
+	}
+
+	s.Count++
+	if e.Failed {
+		s.Errors++
+	}
+	s.SumLatency += e.Latency
+	if e.Latency < s.MinLatency {
+		s.MinLatency = e.Latency
+	}
+	if e.Latency > s.MaxLatency {
+		s.MaxLatency = e.Latency
+	}
+	s.SumRowsSent += e.RowsSent
+	s.SumRowsExamined += e.RowsExamined
+	s.LastSeen = e.Time
+}
+
+// StmtSummaries returns the copies of the statement summaries ordered by the schema and the digest.
+func (do *Domain) StmtSummaries() []*StmtSummary {
+	do.stmtSummaries.mu.Lock()
+	summaries := make([]*StmtSummary, 0, len(do.stmtSummaries.summaries))
+	for _, s := range do.stmtSummaries.summaries {
+		c := *s
+		summaries = append(summaries, &c)
+	}
+	do.stmtSummaries.mu.Unlock()
+	sort.Sort(byDigest(summaries))
+	return summaries
+}
+
+type byDigest []*StmtSummary
+
+func (s byDigest) Len() int      { return len(s) }
+func (s byDigest) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byDigest) Less(i, j int) bool {
+	if s[i].SchemaName != s[j].SchemaName {
+		return s[i].SchemaName < s[j].SchemaName
+	}
+	if s[i].Digest != s[j].Digest {
+		return s[i].Digest < s[j].Digest
+	}
+	return s[i].PlanDigest < s[j].PlanDigest
+}
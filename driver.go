@@ -438,6 +438,14 @@ func (r *driverRows) Next(dest []driver.Value) error {
 					dest[i] = v.String()
 				case mysql.Decimal:
 					dest[i] = v.String()
+				case mysql.Enum:
+					dest[i] = v.String()
+				case mysql.Set:
+					dest[i] = v.String()
+				case mysql.Bit:
+					dest[i] = []byte(v.ToString())
+				case mysql.JSON:
+					dest[i] = v.String()
 				default:
 					return errors.Errorf("unable to handle type %T", xi)
 				}
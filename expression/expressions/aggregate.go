@@ -0,0 +1,479 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/kv/memkv"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/parser/opcode"
+	"github.com/Dong-Chan/alloydb/util/charset"
+	"github.com/Dong-Chan/alloydb/util/types"
+)
+
+// AggregateFunc is the accumulator of an aggregate function for a group.
+// In complete mode, it is updated with the args of every row of the group,
+// and returns the final result.
+// The aggregation can also be split into two phases: accumulators in partial mode
+// are updated with a part of the rows, e.g. in storage, and their partial states
+// are merged into an accumulator in final mode, which returns the final result.
+type AggregateFunc interface {
+	// Update updates the state with the evaluated args of a row.
+	Update(args []interface{}) error
+	// Partial returns the partial state, which can be merged by the accumulator
+	// of the same aggregate function.
+	Partial() ([]interface{}, error)
+	// Merge merges the partial state returned by Partial.
+	Merge(partial []interface{}) error
+	// Result returns the final result.
+	Result() (interface{}, error)
+}
+
+// NewAggregateFunc creates an accumulator for the aggregate function call c.
+// collations are the collations of the args of c, see ArgCollations. MIN, MAX and
+// the distinct args compare the strings by them.
+func NewAggregateFunc(c *Call, collations []string) (AggregateFunc, error) {
+	return newAggregateFunc(c.F, c.Distinct, collations)
+}
+
+// ArgCollations returns the collations of the args of the call c, the collations of the
+// identifiers are retrieved with args like Collation.
+func ArgCollations(c *Call, args map[interface{}]interface{}) []string {
+	collations := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		collations[i] = Collation(arg, args)
+	}
+	return collations
+}
+
+// collatedArgs returns args with the strings replaced by the sort keys of their collations,
+// args is returned if no string has a collation.
+func collatedArgs(args []interface{}, collations []string) []interface{} {
+	var keys []interface{}
+	for i, v := range args {
+		str, ok := v.(string)
+		if !ok || i >= len(collations) || collations[i] == "" {
+			continue
+		}
+		if keys == nil {
+			keys = append([]interface{}(nil), args...)
+		}
+		keys[i] = charset.GetCollator(collations[i]).Key(str)
+	}
+	if keys == nil {
+		return args
+	}
+	return keys
+}
+
+func newAggregateFunc(name string, distinct bool, collations []string) (AggregateFunc, error) {
+	var f AggregateFunc
+	name = strings.ToLower(name)
+	switch name {
+	case "avg":
+		f = &avgFunc{}
+	case "bit_and":
+		f = &bitFunc{op: opcode.And, v: math.MaxUint64}
+	case "bit_or":
+		f = &bitFunc{op: opcode.Or}
+	case "bit_xor":
+		f = &bitFunc{op: opcode.Xor}
+	case "count":
+		f = &countFunc{}
+	case "group_concat":
+		f = &groupConcatFunc{}
+	case "max":
+		f = &maxMinFunc{isMax: true, collations: collations}
+	case "min":
+		f = &maxMinFunc{collations: collations}
+	case "sum":
+		f = &sumFunc{}
+	default:
+		return nil, errors.Errorf("unknown aggregate function %s", name)
+	}
+
+	switch name {
+	case "count", "sum", "avg", "group_concat":
+		// only these aggregate functions support distinct
+		if distinct {
+			// now we have to use memkv Temp, later may be use map directly
+			t, err := memkv.CreateTemp(true)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			f = &distinctFunc{AggregateFunc: f, values: t, collations: collations}
+		}
+	}
+	return f, nil
+}
+
+// CloseAggregateFunc releases the resources of the accumulator f, like the temporary store of
+// the distinct args, f can't be updated after it is closed.
+func CloseAggregateFunc(f AggregateFunc) error {
+	if c, ok := f.(io.Closer); ok {
+		return errors.Trace(c.Close())
+	}
+	return nil
+}
+
+// distinctFunc updates the accumulator with distinct args only.
+// The args are keyed by the sort keys of their collations, and the first args of
+// every key are saved as the value.
+// Its partial state is the distinct args, so it can be merged correctly.
+type distinctFunc struct {
+	AggregateFunc
+	values     memkv.Temp
+	collations []string
+}
+
+func (f *distinctFunc) Update(args []interface{}) error {
+	key := collatedArgs(args, f.collations)
+	v, err := f.values.Get(key)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if len(v) > 0 {
+		// we save a same value before
+		return nil
+	}
+
+	if err = f.values.Set(key, args); err != nil {
+		return errors.Trace(err)
+	}
+	return f.AggregateFunc.Update(args)
+}
+
+func (f *distinctFunc) Partial() ([]interface{}, error) {
+	var partial []interface{}
+	it, err := f.values.SeekFirst()
+	for err == nil {
+		var v []interface{}
+		if _, v, err = it.Next(); err == nil {
+			partial = append(partial, v)
+		}
+	}
+	return partial, types.EOFAsNil(err)
+}
+
+// Close implements the io.Closer interface, the temporary store of the distinct args is dropped.
+func (f *distinctFunc) Close() error {
+	if f.values == nil {
+		return nil
+	}
+	err := f.values.Drop()
+	f.values = nil
+	return errors.Trace(err)
+}
+
+func (f *distinctFunc) Merge(partial []interface{}) error {
+	for _, v := range partial {
+		args, ok := v.([]interface{})
+		if !ok {
+			return errors.Errorf("invalid partial distinct args %v(%T)", v, v)
+		}
+		if err := f.Update(args); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+type countFunc struct {
+	n int64
+}
+
+func (f *countFunc) Update(args []interface{}) error {
+	if args[0] != nil {
+		f.n++
+	}
+	return nil
+}
+
+func (f *countFunc) Partial() ([]interface{}, error) {
+	return []interface{}{f.n}, nil
+}
+
+func (f *countFunc) Merge(partial []interface{}) error {
+	n, ok := partial[0].(int64)
+	if !ok {
+		return errors.Errorf("invalid partial count %v(%T)", partial[0], partial[0])
+	}
+	f.n += n
+	return nil
+}
+
+func (f *countFunc) Result() (interface{}, error) {
+	return f.n, nil
+}
+
+func calculateSum(sum interface{}, v interface{}) (interface{}, error) {
+	// for avg and sum calculation
+	// avg and sum use decimal for integer and decimal type, use float for others
+	// see https://dev.mysql.com/doc/refman/5.7/en/group-by-functions.html
+	var (
+		data interface{}
+		err  error
+	)
+
+	switch y := v.(type) {
+	case int, uint, int8, uint8, int16, uint16, int32, uint32, int64, uint64:
+		data, err = mysql.ConvertToDecimal(v)
+	case mysql.Decimal:
+		data = y
+	default:
+		data, err = types.ToFloat64(v)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch x := sum.(type) {
+	case nil:
+		return data, nil
+	case float64:
+		return x + data.(float64), nil
+	case mysql.Decimal:
+		return x.Add(data.(mysql.Decimal)), nil
+	default:
+		return nil, errors.Errorf("invalid value %v(%T) for aggregate", x, x)
+	}
+}
+
+type sumFunc struct {
+	sum interface{}
+}
+
+func (f *sumFunc) Update(args []interface{}) (err error) {
+	if args[0] == nil {
+		return nil
+	}
+
+	f.sum, err = calculateSum(f.sum, args[0])
+	if err != nil {
+		return errors.Errorf("eval SUM aggregate err: %v", err)
+	}
+	return nil
+}
+
+func (f *sumFunc) Partial() ([]interface{}, error) {
+	return []interface{}{f.sum}, nil
+}
+
+func (f *sumFunc) Merge(partial []interface{}) error {
+	return f.Update(partial)
+}
+
+func (f *sumFunc) Result() (interface{}, error) {
+	return f.sum, nil
+}
+
+// avgFunc uses decimal for integer and decimal type, uses float for others.
+type avgFunc struct {
+	sum interface{}
+	n   uint64
+}
+
+func (f *avgFunc) Update(args []interface{}) (err error) {
+	if args[0] == nil {
+		return nil
+	}
+
+	f.sum, err = calculateSum(f.sum, args[0])
+	if err != nil {
+		return errors.Errorf("eval AVG aggregate err: %v", err)
+	}
+	f.n++
+	return nil
+}
+
+func (f *avgFunc) Partial() ([]interface{}, error) {
+	return []interface{}{f.sum, f.n}, nil
+}
+
+func (f *avgFunc) Merge(partial []interface{}) (err error) {
+	n, ok := partial[1].(uint64)
+	if !ok {
+		return errors.Errorf("invalid partial avg count %v(%T)", partial[1], partial[1])
+	}
+	if partial[0] == nil {
+		return nil
+	}
+
+	f.sum, err = calculateSum(f.sum, partial[0])
+	if err != nil {
+		return errors.Errorf("eval AVG aggregate err: %v", err)
+	}
+	f.n += n
+	return nil
+}
+
+func (f *avgFunc) Result() (interface{}, error) {
+	switch x := f.sum.(type) {
+	case nil:
+		return nil, nil
+	case float64:
+		return x / float64(f.n), nil
+	case mysql.Decimal:
+		return x.Div(mysql.NewDecimalFromUint(f.n, 0)), nil
+	default:
+		return nil, errors.Errorf("invalid value %v(%T) for aggregate", x, x)
+	}
+}
+
+// maxMinFunc compares the strings by the collation of the arg.
+type maxMinFunc struct {
+	v          interface{}
+	isMax      bool
+	collations []string
+}
+
+func (f *maxMinFunc) Update(args []interface{}) error {
+	y := args[0]
+	if y == nil {
+		return nil
+	}
+
+	// Notice: for max, `nil < non nil`, for min, `nil > non nil`
+	if f.v == nil {
+		f.v = y
+		return nil
+	}
+
+	var n int
+	x, ok1 := f.v.(string)
+	s, ok2 := y.(string)
+	if ok1 && ok2 && len(f.collations) > 0 && f.collations[0] != "" {
+		n = charset.GetCollator(f.collations[0]).Compare(x, s)
+	} else {
+		n = types.Compare(f.v, y)
+	}
+	if (f.isMax && n < 0) || (!f.isMax && n > 0) {
+		f.v = y
+	}
+	return nil
+}
+
+func (f *maxMinFunc) Partial() ([]interface{}, error) {
+	return []interface{}{f.v}, nil
+}
+
+func (f *maxMinFunc) Merge(partial []interface{}) error {
+	return f.Update(partial)
+}
+
+func (f *maxMinFunc) Result() (interface{}, error) {
+	return f.v, nil
+}
+
+// TODO: the real group_concat is very complex, here we just support the simplest one.
+type groupConcatFunc struct {
+	buf bytes.Buffer
+	// notNull is true once any row is concatenated.
+	notNull bool
+}
+
+func (f *groupConcatFunc) Update(args []interface{}) error {
+	for _, v := range args {
+		if v == nil {
+			// if any is nil, we will not concat
+			return nil
+		}
+	}
+
+	if f.notNull {
+		// now use comma separator
+		f.buf.WriteString(",")
+	}
+	f.notNull = true
+
+	for _, v := range args {
+		f.buf.WriteString(fmt.Sprintf("%v", v))
+	}
+
+	// TODO: if total length is greater than global var group_concat_max_len, truncate it.
+	return nil
+}
+
+func (f *groupConcatFunc) Partial() ([]interface{}, error) {
+	v, err := f.Result()
+	return []interface{}{v}, err
+}
+
+func (f *groupConcatFunc) Merge(partial []interface{}) error {
+	return f.Update(partial)
+}
+
+func (f *groupConcatFunc) Result() (interface{}, error) {
+	if !f.notNull {
+		return nil, nil
+	}
+	return f.buf.String(), nil
+}
+
+// bitFunc is the accumulator of BIT_AND, BIT_OR and BIT_XOR, the result is an unsigned integer.
+type bitFunc struct {
+	op opcode.Op
+	v  uint64
+}
+
+func (f *bitFunc) Update(args []interface{}) error {
+	if args[0] == nil {
+		return nil
+	}
+
+	x, err := numericArg(args[0])
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var n uint64
+	if u, ok := x.(uint64); ok {
+		n = u
+	} else {
+		i, err := types.ToInt64(x)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		n = uint64(i)
+	}
+
+	switch f.op {
+	case opcode.And:
+		f.v &= n
+	case opcode.Or:
+		f.v |= n
+	case opcode.Xor:
+		f.v ^= n
+	}
+	return nil
+}
+
+func (f *bitFunc) Partial() ([]interface{}, error) {
+	return []interface{}{f.v}, nil
+}
+
+func (f *bitFunc) Merge(partial []interface{}) error {
+	return f.Update(partial)
+}
+
+func (f *bitFunc) Result() (interface{}, error) {
+	return f.v, nil
+}
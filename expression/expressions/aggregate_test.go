@@ -0,0 +1,137 @@
+// 
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	"math"
+
+	. "github.com/pingcap/check"
+	"github.com/Dong-Chan/alloydb/expression"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+)
+
+var _ = Suite(&testAggregateSuite{})
+
+type testAggregateSuite struct {
+}
+
+func (s *testAggregateSuite) TestAggregateFunc(c *C) {
+	tbl := []struct {
+		F        string
+		Args     [][]interface{}
+		Distinct bool
+		Ret      interface{}
+	}{
+		{"avg", [][]interface{}{{1}, {2}, {nil}, {3}, {2}}, false, "2.0000"},
+		{"avg", [][]interface{}{{1}, {2}, {nil}, {3}, {2}}, true, "2.0000"},
+		{"avg", [][]interface{}{{1.0}, {2.0}}, false, 1.5},
+		{"avg", [][]interface{}{{nil}}, false, nil},
+		{"bit_and", [][]interface{}{{7}, {nil}, {int64(3)}, {uint64(6)}}, false, uint64(2)},
+		{"bit_and", nil, false, uint64(math.MaxUint64)},
+		{"bit_or", [][]interface{}{{1}, {nil}, {int64(4)}, {"8"}}, false, uint64(13)},
+		{"bit_or", nil, false, uint64(0)},
+		{"bit_xor", [][]interface{}{{5}, {3}, {nil}, {int64(-1)}}, false, uint64(math.MaxUint64 - 6)},
+		{"count", [][]interface{}{{1}, {1}, {nil}, {2}}, false, int64(3)},
+		{"count", [][]interface{}{{1}, {1}, {nil}, {2}}, true, int64(2)},
+		{"count", nil, false, int64(0)},
+		{"group_concat", [][]interface{}{{1, "a"}, {2, nil}, {1, "a"}}, false, "1a,1a"},
+		{"group_concat", [][]interface{}{{1, "a"}, {2, nil}, {1, "a"}}, true, "1a"},
+		{"group_concat", [][]interface{}{{nil}}, false, nil},
+		{"max", [][]interface{}{{1}, {3}, {nil}, {2}}, false, 3},
+		{"max", [][]interface{}{{nil}}, false, nil},
+		{"min", [][]interface{}{{2}, {nil}, {1}, {3}}, false, 1},
+		{"min", nil, false, nil},
+		{"sum", [][]interface{}{{1}, {1}, {mysql.NewDecimalFromInt(2, 0)}}, false, "4"},
+		{"sum", [][]interface{}{{1}, {1}, {2}}, true, "3"},
+		{"sum", [][]interface{}{{1.5}, {2.5}}, false, 4.0},
+		{"sum", [][]interface{}{{nil}}, true, nil},
+	}
+
+	check := func(v interface{}, ret interface{}) {
+		if d, ok := v.(mysql.Decimal); ok {
+			c.Assert(d.String(), Equals, ret)
+			return
+		}
+		c.Assert(v, Equals, ret)
+	}
+
+	for _, t := range tbl {
+		e, err := NewCall(t.F, []expression.Expression{Value{nil}}, t.Distinct)
+		c.Assert(err, IsNil)
+
+		// complete mode.
+		f, err := NewAggregateFunc(e.(*Call), nil)
+		c.Assert(err, IsNil)
+		for _, args := range t.Args {
+			c.Assert(f.Update(args), IsNil)
+		}
+		v, err := f.Result()
+		c.Assert(err, IsNil)
+		check(v, t.Ret)
+
+		// partial and final mode, every row is aggregated by a partial accumulator.
+		final, err := NewAggregateFunc(e.(*Call), nil)
+		c.Assert(err, IsNil)
+		for _, args := range t.Args {
+			partial, err := NewAggregateFunc(e.(*Call), nil)
+			c.Assert(err, IsNil)
+			c.Assert(partial.Update(args), IsNil)
+			state, err := partial.Partial()
+			c.Assert(err, IsNil)
+			c.Assert(final.Merge(state), IsNil)
+		}
+		v, err = final.Result()
+		c.Assert(err, IsNil)
+		check(v, t.Ret)
+	}
+
+	_, err := newAggregateFunc("abs", false, nil)
+	c.Assert(err, NotNil)
+
+	f, err := newAggregateFunc("count", false, nil)
+	c.Assert(err, IsNil)
+	c.Assert(f.Merge([]interface{}{"abc"}), NotNil)
+	c.Assert(CloseAggregateFunc(f), IsNil)
+
+	// The temporary store of the distinct args is dropped when the accumulator is closed.
+	f, err = newAggregateFunc("count", true, nil)
+	c.Assert(err, IsNil)
+	c.Assert(f.Update([]interface{}{1}), IsNil)
+	c.Assert(CloseAggregateFunc(f), IsNil)
+	c.Assert(f.(*distinctFunc).values, IsNil)
+	c.Assert(CloseAggregateFunc(f), IsNil)
+
+	// The strings are compared by the collations of the args.
+	ci := []string{"utf8_general_ci"}
+	f, err = newAggregateFunc("min", false, ci)
+	c.Assert(err, IsNil)
+	c.Assert(f.Update([]interface{}{"Abd"}), IsNil)
+	c.Assert(f.Update([]interface{}{"abc"}), IsNil)
+	v, err := f.Result()
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, "abc")
+
+	f, err = newAggregateFunc("group_concat", true, ci)
+	c.Assert(err, IsNil)
+	c.Assert(f.Update([]interface{}{"abc"}), IsNil)
+	c.Assert(f.Update([]interface{}{"ABC"}), IsNil)
+	c.Assert(f.Update([]interface{}{"b"}), IsNil)
+	state, err := f.Partial()
+	c.Assert(err, IsNil)
+	c.Assert(state, DeepEquals, []interface{}{[]interface{}{"abc"}, []interface{}{"b"}})
+	v, err = f.Result()
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, "abc,b")
+	c.Assert(CloseAggregateFunc(f), IsNil)
+}
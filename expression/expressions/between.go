@@ -88,16 +88,17 @@ func (b *Between) Eval(ctx context.Context, args map[interface{}]interface{}) (i
 
 	var l, r expression.Expression
 	op := opcode.AndAnd
+	val := newCollatedValue(v, b.Expr, args)
 
 	if b.Not {
 		// v < lv || v > rv
 		op = opcode.OrOr
-		l = NewBinaryOperation(opcode.LT, Value{v}, Value{lv})
-		r = NewBinaryOperation(opcode.GT, Value{v}, Value{rv})
+		l = NewBinaryOperation(opcode.LT, val, Value{lv})
+		r = NewBinaryOperation(opcode.GT, val, Value{rv})
 	} else {
 		// v >= lv && v <= rv
-		l = NewBinaryOperation(opcode.GE, Value{v}, Value{lv})
-		r = NewBinaryOperation(opcode.LE, Value{v}, Value{rv})
+		l = NewBinaryOperation(opcode.GE, val, Value{lv})
+		r = NewBinaryOperation(opcode.LE, val, Value{rv})
 	}
 
 	ret := NewBinaryOperation(op, l, r)
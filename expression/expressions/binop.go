@@ -22,6 +22,8 @@ import (
 	"github.com/Dong-Chan/alloydb/expression"
 	mysql "github.com/Dong-Chan/alloydb/mysqldef"
 	"github.com/Dong-Chan/alloydb/parser/opcode"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/util/charset"
 	"github.com/Dong-Chan/alloydb/util/types"
 )
 
@@ -131,7 +133,8 @@ func (o *BinaryOperation) traceErr(err error) error {
 		return nil
 	}
 
-	return errors.Errorf("eval %s err: %v", o, err)
+	// Keep the cause, so the SQL error code of the operands is not lost.
+	return errors.Annotatef(err, "eval %s err", o)
 }
 
 // Eval implements the Expression Eval interface.
@@ -365,6 +368,15 @@ func compareStringFloat(s string, a float64) (int, error) {
 
 // See https://dev.mysql.com/doc/refman/5.7/en/type-conversion.html
 func evalCompare(a interface{}, b interface{}) (int, error) {
+	// A value is converted to JSON to be compared with a JSON value.
+	if _, ok := a.(mysql.JSON); ok {
+		return compareJSON(a, b)
+	}
+	if _, ok := b.(mysql.JSON); ok {
+		n, err := compareJSON(b, a)
+		return -n, err
+	}
+
 	// TODO: support compare time type with other types
 	switch x := a.(type) {
 	case float64:
@@ -445,6 +457,28 @@ func evalCompare(a interface{}, b interface{}) (int, error) {
 	return 0, errors.Errorf("invalid compare type %T cmp %T", a, b)
 }
 
+// compareJSON compares the JSON value a with b.
+// See https://dev.mysql.com/doc/refman/5.7/en/json.html#json-comparison
+func compareJSON(a interface{}, b interface{}) (int, error) {
+	y, err := mysql.CreateJSON(b)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return mysql.CompareJSON(a.(mysql.JSON), y), nil
+}
+
+// evalCollatedCompare is like evalCompare, but the strings are compared by the collation if it is not empty.
+func evalCollatedCompare(a interface{}, b interface{}, collation string) (int, error) {
+	if collation != "" {
+		x, ok1 := a.(string)
+		y, ok2 := b.(string)
+		if ok1 && ok2 {
+			return charset.GetCollator(collation).Compare(x, y), nil
+		}
+	}
+	return evalCompare(a, b)
+}
+
 // operator: >=, >, <=, <, !=, <>, = <=>, etc.
 // see https://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html
 func (o *BinaryOperation) evalComparisonOp(ctx context.Context, args map[interface{}]interface{}) (interface{}, error) {
@@ -459,7 +493,7 @@ func (o *BinaryOperation) evalComparisonOp(ctx context.Context, args map[interfa
 		return nil, nil
 	}
 
-	n, err := evalCompare(a, b)
+	n, err := evalCollatedCompare(a, b, CompareCollation(o.L, o.R, args))
 	if err != nil {
 		return nil, o.traceErr(err)
 	}
@@ -734,12 +768,10 @@ func (o *BinaryOperation) evalMod(a interface{}, b interface{}) (interface{}, er
 	case mysql.Decimal:
 		switch y := b.(type) {
 		case mysql.Decimal:
-			xf, _ := x.Float64()
-			yf, _ := y.Float64()
-			if yf == 0 {
+			if y.Cmp(mysql.ZeroDecimal) == 0 {
 				return nil, nil
 			}
-			return math.Mod(xf, yf), nil
+			return x.Mod(y), nil
 		}
 	}
 
@@ -799,6 +831,7 @@ func (o *BinaryOperation) evalArithmeticOp(ctx context.Context, args map[interfa
 	}
 
 	// TODO: support logic division DIV
+	var v interface{}
 	switch o.Op {
 	case opcode.Plus:
 		return o.evalPlus(a, b)
@@ -807,12 +840,29 @@ func (o *BinaryOperation) evalArithmeticOp(ctx context.Context, args map[interfa
 	case opcode.Mul:
 		return o.evalMul(a, b)
 	case opcode.Div:
-		return o.evalDiv(a, b)
+		v, err = o.evalDiv(a, b)
 	case opcode.Mod:
-		return o.evalMod(a, b)
+		v, err = o.evalMod(a, b)
 	case opcode.IntDiv:
-		return o.evalIntDiv(a, b)
+		v, err = o.evalIntDiv(a, b)
 	default:
 		return nil, o.errorf("invalid op %v in arithmetic operation", o.Op)
 	}
+
+	if v == nil && err == nil {
+		// The operands are not NULL, so NULL result means division by zero.
+		return nil, o.divisionByZero(ctx)
+	}
+	return v, err
+}
+
+// divisionByZero returns an error if ERROR_FOR_DIVISION_BY_ZERO and strict mode are both set,
+// otherwise the result of division by zero is NULL.
+// TODO: MySQL only returns the error in INSERT and UPDATE, we return it in all statements.
+func (o *BinaryOperation) divisionByZero(ctx context.Context) error {
+	mode := variable.GetSQLMode(ctx)
+	if mode.Has(mysql.ModeErrorForDivisionByZero) && mode.HasStrictMode() {
+		return errors.Trace(mysql.NewDefaultError(mysql.ErDivisionByZero))
+	}
+	return nil
 }
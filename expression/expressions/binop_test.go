@@ -14,14 +14,15 @@
 package expressions
 
 import (
-	"errors"
 	"time"
 
+	"github.com/juju/errors"
 	. "github.com/pingcap/check"
 	"github.com/Dong-Chan/alloydb/expression"
 	"github.com/Dong-Chan/alloydb/model"
 	mysql "github.com/Dong-Chan/alloydb/mysqldef"
 	"github.com/Dong-Chan/alloydb/parser/opcode"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
 	"github.com/Dong-Chan/alloydb/util/types"
 )
 
@@ -562,3 +563,40 @@ func (s *testBinOpSuite) TestNumericOp(c *C) {
 	_, err = expr.Eval(nil, nil)
 	c.Assert(err, NotNil)
 }
+
+func (s *testBinOpSuite) TestDivisionByZero(c *C) {
+	ctx := newMockCtx()
+	variable.BindSessionVars(ctx)
+	vars := variable.GetSessionVars(ctx)
+
+	tbl := []struct {
+		lhs interface{}
+		op  opcode.Op
+		rhs interface{}
+	}{
+		{1, opcode.Div, 0},
+		{1.5, opcode.Div, 0},
+		{1, opcode.IntDiv, 0},
+		{10, opcode.Mod, uint64(0)},
+	}
+
+	for _, t := range tbl {
+		expr := NewBinaryOperation(t.op, Value{t.lhs}, Value{t.rhs})
+
+		vars.SetSQLMode(mysql.ModeErrorForDivisionByZero)
+		v, err := expr.Eval(ctx, nil)
+		c.Assert(err, IsNil)
+		c.Assert(v, IsNil)
+
+		vars.SetSQLMode(mysql.ModeErrorForDivisionByZero | mysql.ModeStrictTransTables)
+		_, err = expr.Eval(ctx, nil)
+		c.Assert(err, NotNil)
+		c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErDivisionByZero))
+	}
+
+	// NULL operand is not division by zero.
+	expr := NewBinaryOperation(opcode.Div, Value{nil}, Value{0})
+	v, err := expr.Eval(ctx, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v, IsNil)
+}
@@ -17,10 +17,10 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/juju/errors"
 	"github.com/Dong-Chan/alloydb/context"
 	"github.com/Dong-Chan/alloydb/sessionctx/db"
 	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/juju/errors"
 )
 
 // Builin functions entry key with name conflict with keywords.
@@ -28,8 +28,15 @@ const (
 	// BuiltinFuncDatabase is the keyword for Database function.
 	BuiltinFuncDatabase = "database"
 	// BuiltinFuncIf is the keyword for If function.
-	BuiltinFuncIf   = "if"
+	BuiltinFuncIf = "if"
+	// BuiltinFuncInsert is the keyword for Insert function.
+	BuiltinFuncInsert = "insert"
+	// BuiltinFuncLeft is the keyword for Left function.
 	BuiltinFuncLeft = "left"
+	// BuiltinFuncMod is the keyword for Mod function.
+	BuiltinFuncMod = "mod"
+	// BuiltinFuncRight is the keyword for Right function.
+	BuiltinFuncRight = "right"
 )
 
 var builtin = map[string]struct {
@@ -44,10 +51,51 @@ var builtin = map[string]struct {
 	"coalesce":          {builtinCoalesce, 1, -1, true, false},
 
 	// math functions
-	"abs": {builtinAbs, 1, 1, true, false},
+	"abs":          {builtinAbs, 1, 1, true, false},
+	"acos":         {builtinAcos, 1, 1, true, false},
+	"asin":         {builtinAsin, 1, 1, true, false},
+	"atan":         {builtinAtan, 1, 2, true, false},
+	"atan2":        {builtinAtan2, 2, 2, true, false},
+	"bin":          {builtinBin, 1, 1, true, false},
+	"bit_count":    {builtinBitCount, 1, 1, true, false},
+	"ceil":         {builtinCeil, 1, 1, true, false},
+	"ceiling":      {builtinCeil, 1, 1, true, false},
+	"conv":         {builtinConv, 3, 3, true, false},
+	"cos":          {builtinCos, 1, 1, true, false},
+	"cot":          {builtinCot, 1, 1, true, false},
+	"crc32":        {builtinCRC32, 1, 1, true, false},
+	"degrees":      {builtinDegrees, 1, 1, true, false},
+	"exp":          {builtinExp, 1, 1, true, false},
+	"floor":        {builtinFloor, 1, 1, true, false},
+	"ln":           {builtinLn, 1, 1, true, false},
+	"log":          {builtinLog, 1, 2, true, false},
+	"log10":        {builtinLog10, 1, 1, true, false},
+	"log2":         {builtinLog2, 1, 1, true, false},
+	BuiltinFuncMod: {builtinMod, 2, 2, true, false},
+	"oct":          {builtinOct, 1, 1, true, false},
+	"pi":           {builtinPI, 0, 0, true, false},
+	"pow":          {builtinPow, 2, 2, true, false},
+	"power":        {builtinPow, 2, 2, true, false},
+	"radians":      {builtinRadians, 1, 1, true, false},
+	"rand":         {builtinRand, 0, 1, false, false},
+	"round":        {builtinRound, 1, 2, true, false},
+	"sign":         {builtinSign, 1, 1, true, false},
+	"sin":          {builtinSin, 1, 1, true, false},
+	"sqrt":         {builtinSqrt, 1, 1, true, false},
+	"tan":          {builtinTan, 1, 1, true, false},
+	"truncate":     {builtinTruncate, 2, 2, true, false},
+
+	// encryption functions
+	"md5":  {builtinMD5, 1, 1, true, false},
+	"sha":  {builtinSHA1, 1, 1, true, false},
+	"sha1": {builtinSHA1, 1, 1, true, false},
+	"sha2": {builtinSHA2, 2, 2, true, false},
 
 	// group by functions
 	"avg":          {builtinAvg, 1, 1, false, true},
+	"bit_and":      {builtinBitAnd, 1, 1, false, true},
+	"bit_or":       {builtinBitOr, 1, 1, false, true},
+	"bit_xor":      {builtinBitXor, 1, 1, false, true},
 	"count":        {builtinCount, 1, 1, false, true},
 	"group_concat": {builtinGroupConcat, 1, -1, false, true},
 	"max":          {builtinMax, 1, 1, false, true},
@@ -55,22 +103,37 @@ var builtin = map[string]struct {
 	"sum":          {builtinSum, 1, 1, false, true},
 
 	// time functions
-	"date":        {builtinDate, 8, 8, true, false},
-	"day":         {builtinDay, 1, 1, true, false},
-	"dayofmonth":  {builtinDayOfMonth, 1, 1, true, false},
-	"dayofweek":   {builtinDayOfWeek, 1, 1, true, false},
-	"dayofyear":   {builtinDayOfYear, 1, 1, true, false},
-	"hour":        {builtinHour, 1, 1, true, false},
-	"microsecond": {builtinMicroSecond, 1, 1, true, false},
-	"minute":      {builtinMinute, 1, 1, true, false},
-	"month":       {builtinMonth, 1, 1, true, false},
-	"now":         {builtinNow, 0, 1, false, false},
-	"second":      {builtinSecond, 1, 1, true, false},
-	"week":        {builtinWeek, 1, 2, true, false},
-	"weekday":     {builtinWeekDay, 1, 1, true, false},
-	"weekofyear":  {builtinWeekOfYear, 1, 1, true, false},
-	"year":        {builtinYear, 1, 1, true, false},
-	"yearweek":    {builtinYearWeek, 1, 2, true, false},
+	"convert_tz":     {builtinConvertTz, 3, 3, true, false},
+	"curdate":        {builtinCurrentDate, 0, 0, false, false},
+	"current_date":   {builtinCurrentDate, 0, 0, false, false},
+	"current_time":   {builtinCurrentTime, 0, 1, false, false},
+	"curtime":        {builtinCurrentTime, 0, 1, false, false},
+	"date":           {builtinDate, 8, 8, true, false},
+	"date_format":    {builtinDateFormat, 2, 2, true, false},
+	"datediff":       {builtinDateDiff, 2, 2, true, false},
+	"day":            {builtinDay, 1, 1, true, false},
+	"dayofmonth":     {builtinDayOfMonth, 1, 1, true, false},
+	"dayofweek":      {builtinDayOfWeek, 1, 1, true, false},
+	"dayofyear":      {builtinDayOfYear, 1, 1, true, false},
+	"from_unixtime":  {builtinFromUnixTime, 1, 2, true, false},
+	"hour":           {builtinHour, 1, 1, true, false},
+	"last_day":       {builtinLastDay, 1, 1, true, false},
+	"microsecond":    {builtinMicroSecond, 1, 1, true, false},
+	"minute":         {builtinMinute, 1, 1, true, false},
+	"month":          {builtinMonth, 1, 1, true, false},
+	"now":            {builtinNow, 0, 1, false, false},
+	"second":         {builtinSecond, 1, 1, true, false},
+	"str_to_date":    {builtinStrToDate, 2, 2, true, false},
+	"sysdate":        {builtinSysDate, 0, 1, false, false},
+	"timestampadd":   {builtinTimestampAdd, 3, 3, true, false},
+	"timestampdiff":  {builtinTimestampDiff, 3, 3, true, false},
+	"unix_timestamp": {builtinUnixTimestamp, 0, 1, false, false},
+	"utc_timestamp":  {builtinUTCTimestamp, 0, 1, false, false},
+	"week":           {builtinWeek, 1, 2, true, false},
+	"weekday":        {builtinWeekDay, 1, 1, true, false},
+	"weekofyear":     {builtinWeekOfYear, 1, 1, true, false},
+	"year":           {builtinYear, 1, 1, true, false},
+	"yearweek":       {builtinYearWeek, 1, 2, true, false},
 
 	// control functions
 	BuiltinFuncIf: {builtinIf, 3, 3, true, false},
@@ -78,14 +141,57 @@ var builtin = map[string]struct {
 	"nullif":      {builtinNullIf, 2, 2, true, false},
 
 	// string functions
-	"concat":        {builtinConcat, 1, -1, true, false},
-	"concat_ws":     {builtinConcatWS, 2, -1, true, false},
-	BuiltinFuncLeft: {builtinLeft, 2, 2, true, false},
-	"length":        {builtinLength, 1, 1, true, false},
-	"repeat":        {builtinRepeat, 2, 2, true, false},
+	"ascii":            {builtinASCII, 1, 1, true, false},
+	"char_length":      {builtinCharLength, 1, 1, true, false},
+	"character_length": {builtinCharLength, 1, 1, true, false},
+	"concat":           {builtinConcat, 1, -1, true, false},
+	"concat_ws":        {builtinConcatWS, 2, -1, true, false},
+	"elt":              {builtinElt, 2, -1, true, false},
+	"field":            {builtinField, 2, -1, true, false},
+	"find_in_set":      {builtinFindInSet, 2, 2, true, false},
+	"format":           {builtinFormat, 2, 3, true, false},
+	"hex":              {builtinHex, 1, 1, true, false},
+	BuiltinFuncInsert:  {builtinInsert, 4, 4, true, false},
+	"instr":            {builtinInstr, 2, 2, true, false},
+	"lcase":            {builtinLower, 1, 1, true, false},
+	BuiltinFuncLeft:    {builtinLeft, 2, 2, true, false},
+	"length":           {builtinLength, 1, 1, true, false},
+	"locate":           {builtinLocate, 2, 3, true, false},
+	"lower":            {builtinLower, 1, 1, true, false},
+	"lpad":             {builtinLpad, 3, 3, true, false},
+	"ltrim":            {builtinLTrim, 1, 1, true, false},
+	"ord":              {builtinOrd, 1, 1, true, false},
+	"repeat":           {builtinRepeat, 2, 2, true, false},
+	"replace":          {builtinReplace, 3, 3, true, false},
+	"reverse":          {builtinReverse, 1, 1, true, false},
+	BuiltinFuncRight:   {builtinRight, 2, 2, true, false},
+	"rpad":             {builtinRpad, 3, 3, true, false},
+	"rtrim":            {builtinRTrim, 1, 1, true, false},
+	"space":            {builtinSpace, 1, 1, true, false},
+	"strcmp":           {builtinStrcmp, 2, 2, true, false},
+	"substring_index":  {builtinSubstringIndex, 3, 3, true, false},
+	"ucase":            {builtinUpper, 1, 1, true, false},
+	"unhex":            {builtinUnHex, 1, 1, true, false},
+	"upper":            {builtinUpper, 1, 1, true, false},
+
+	// json functions
+	"json_array":    {builtinJSONArray, 0, -1, true, false},
+	"json_contains": {builtinJSONContains, 2, 3, true, false},
+	"json_extract":  {builtinJSONExtract, 2, -1, true, false},
+	"json_insert":   {builtinJSONInsert, 3, -1, true, false},
+	"json_object":   {builtinJSONObject, 0, -1, true, false},
+	"json_remove":   {builtinJSONRemove, 2, -1, true, false},
+	"json_replace":  {builtinJSONReplace, 3, -1, true, false},
+	"json_set":      {builtinJSONSet, 3, -1, true, false},
+	"json_type":     {builtinJSONType, 1, 1, true, false},
+	"json_unquote":  {builtinJSONUnquote, 1, 1, true, false},
+	"json_valid":    {builtinJSONValid, 1, 1, true, false},
 
 	// information functions
 	"found_rows": {builtinFoundRows, 0, 0, false, false},
+
+	// miscellaneous functions
+	"uuid": {builtinUUID, 0, 0, false, false},
 }
 
 func badNArgs(min int, s string, args []interface{}) error {
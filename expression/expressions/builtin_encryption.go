@@ -0,0 +1,98 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"hash/crc32"
+
+	"github.com/Dong-Chan/alloydb/util/types"
+	"github.com/juju/errors"
+	"github.com/twinj/uuid"
+)
+
+// see https://dev.mysql.com/doc/refman/5.7/en/encryption-functions.html
+
+// hashString returns the hexadecimal digest of the argument using h.
+func hashString(arg interface{}, h hash.Hash) (interface{}, error) {
+	if arg == nil {
+		return nil, nil
+	}
+	str, _, err := stringArg(arg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	h.Write([]byte(str))
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/encryption-functions.html#function_md5
+func builtinMD5(args []interface{}, ctx map[interface{}]interface{}) (v interface{}, err error) {
+	return hashString(args[0], md5.New())
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/encryption-functions.html#function_sha1
+func builtinSHA1(args []interface{}, ctx map[interface{}]interface{}) (v interface{}, err error) {
+	return hashString(args[0], sha1.New())
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/encryption-functions.html#function_sha2
+func builtinSHA2(args []interface{}, ctx map[interface{}]interface{}) (v interface{}, err error) {
+	if args[1] == nil {
+		return nil, nil
+	}
+	hashLength, err := types.ToInt64(args[1])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var h hash.Hash
+	switch hashLength {
+	case 0, 256:
+		h = sha256.New()
+	case 224:
+		h = sha256.New224()
+	case 384:
+		h = sha512.New384()
+	case 512:
+		h = sha512.New()
+	default:
+		// The hash length is not supported.
+		return nil, nil
+	}
+	return hashString(args[0], h)
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_crc32
+func builtinCRC32(args []interface{}, ctx map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	str, _, err := stringArg(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return int64(crc32.ChecksumIEEE([]byte(str))), nil
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/miscellaneous-functions.html#function_uuid
+func builtinUUID(args []interface{}, ctx map[interface{}]interface{}) (v interface{}, err error) {
+	// MySQL generates a version 1 UUID.
+	return uuid.NewV1().String(), nil
+}
@@ -0,0 +1,57 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	"regexp"
+
+	. "github.com/pingcap/check"
+)
+
+func (s *testBuiltinSuite) TestEncryptionFuncs(c *C) {
+	tbl := []struct {
+		F    string
+		Args []interface{}
+		Ret  interface{}
+	}{
+		{"md5", []interface{}{"testing"}, "ae2b1fca515949e5d54fb22b8ed95575"},
+		{"md5", []interface{}{[]byte("testing")}, "ae2b1fca515949e5d54fb22b8ed95575"},
+		{"md5", []interface{}{nil}, nil},
+		{"sha1", []interface{}{"abc"}, "a9993e364706816aba3e25717850c26c9cd0d89d"},
+		{"sha", []interface{}{nil}, nil},
+		{"sha2", []interface{}{"abc", int64(0)}, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{"sha2", []interface{}{"abc", int64(224)}, "23097d223405d8228642a477bda255b32aadbce4bda0b3f7e36c9da7"},
+		{"sha2", []interface{}{"abc", int64(100)}, nil},
+		{"sha2", []interface{}{"abc", nil}, nil},
+		{"crc32", []interface{}{"MySQL"}, int64(3259397556)},
+		{"crc32", []interface{}{nil}, nil},
+	}
+
+	for _, t := range tbl {
+		v, err := builtin[t.F].f(t.Args, nil)
+		c.Assert(err, IsNil)
+		c.Assert(v, Equals, t.Ret, Commentf("%s%v", t.F, t.Args))
+	}
+
+	v, err := builtin["sha2"].f([]interface{}{"abc", int64(512)}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v, HasLen, 128)
+
+	v, err = builtinUUID(nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(regexp.MustCompile("^[0-9a-f]{8}-[0-9a-f]{4}-1[0-9a-f]{3}-[0-9a-f]{4}-[0-9a-f]{12}$").MatchString(v.(string)), IsTrue, Commentf("%v", v))
+	v2, err := builtinUUID(nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v2, Not(Equals), v)
+}
@@ -0,0 +1,291 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/util/types"
+	"github.com/juju/errors"
+)
+
+// See https://dev.mysql.com/doc/refman/5.7/en/json-functions.html
+
+// jsonArg converts the argument i of the function fn to a JSON document,
+// a string is parsed as JSON text.
+func jsonArg(args []interface{}, i int, fn string) (mysql.JSON, error) {
+	var s string
+	switch x := args[i].(type) {
+	case mysql.JSON:
+		return x, nil
+	case string:
+		s = x
+	case []byte:
+		s = string(x)
+	default:
+		return mysql.JSON{}, mysql.NewDefaultError(mysql.ErInvalidTypeForJSON, i+1, fn)
+	}
+	j, err := mysql.ParseJSON(s)
+	if err != nil {
+		if e, ok := err.(*mysql.JSONSyntaxError); ok {
+			return mysql.JSON{}, mysql.NewDefaultError(mysql.ErInvalidJSONTextInParam, i+1, fn, e.Msg, e.Offset)
+		}
+		return mysql.JSON{}, errors.Trace(err)
+	}
+	return j, nil
+}
+
+// jsonPathArgs converts the arguments to JSON paths.
+func jsonPathArgs(args []interface{}) ([]mysql.JSONPath, error) {
+	paths := make([]mysql.JSONPath, 0, len(args))
+	for _, arg := range args {
+		s, err := types.ToString(arg)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		p, err := mysql.ParseJSONPath(s)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
+
+// jsonValueArg converts a value argument of the functions which create or modify JSON documents,
+// a string is a JSON string, not parsed as JSON text.
+func jsonValueArg(arg interface{}) (mysql.JSON, error) {
+	if b, ok := arg.(bool); ok {
+		// The comparison result is a number in MySQL.
+		if b {
+			arg = int64(1)
+		} else {
+			arg = int64(0)
+		}
+	}
+	j, err := mysql.CreateJSON(arg)
+	return j, errors.Trace(err)
+}
+
+func hasNullArg(args []interface{}) bool {
+	for _, arg := range args {
+		if arg == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/json-search-functions.html#function_json-extract
+func builtinJSONExtract(args []interface{}, _ map[interface{}]interface{}) (interface{}, error) {
+	if hasNullArg(args) {
+		return nil, nil
+	}
+	j, err := jsonArg(args, 0, "json_extract")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	paths, err := jsonPathArgs(args[1:])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ret, found := j.Extract(paths)
+	if !found {
+		return nil, nil
+	}
+	return ret, nil
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-unquote
+func builtinJSONUnquote(args []interface{}, _ map[interface{}]interface{}) (interface{}, error) {
+	switch x := args[0].(type) {
+	case nil:
+		return nil, nil
+	case mysql.JSON:
+		return x.Unquote(), nil
+	}
+	s, err := types.ToString(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s, nil
+	}
+	j, err := jsonArg([]interface{}{s}, 0, "json_unquote")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return j.Unquote(), nil
+}
+
+func jsonModify(args []interface{}, fn string, tp mysql.JSONModifyType) (interface{}, error) {
+	if len(args)%2 != 1 {
+		return nil, mysql.NewDefaultError(mysql.ErWrongParamcountToNativeFct, fn)
+	}
+	if args[0] == nil {
+		return nil, nil
+	}
+	j, err := jsonArg(args, 0, fn)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var (
+		paths  []mysql.JSONPath
+		values []mysql.JSON
+	)
+	for i := 1; i < len(args); i += 2 {
+		if args[i] == nil {
+			return nil, nil
+		}
+		p, err := jsonPathArgs(args[i : i+1])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		v, err := jsonValueArg(args[i+1])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		paths, values = append(paths, p[0]), append(values, v)
+	}
+	ret, err := j.Modify(paths, values, tp)
+	return ret, errors.Trace(err)
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-set
+func builtinJSONSet(args []interface{}, _ map[interface{}]interface{}) (interface{}, error) {
+	return jsonModify(args, "json_set", mysql.JSONModifySet)
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-insert
+func builtinJSONInsert(args []interface{}, _ map[interface{}]interface{}) (interface{}, error) {
+	return jsonModify(args, "json_insert", mysql.JSONModifyInsert)
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-replace
+func builtinJSONReplace(args []interface{}, _ map[interface{}]interface{}) (interface{}, error) {
+	return jsonModify(args, "json_replace", mysql.JSONModifyReplace)
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-remove
+func builtinJSONRemove(args []interface{}, _ map[interface{}]interface{}) (interface{}, error) {
+	if hasNullArg(args) {
+		return nil, nil
+	}
+	j, err := jsonArg(args, 0, "json_remove")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	paths, err := jsonPathArgs(args[1:])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ret, err := j.Remove(paths)
+	return ret, errors.Trace(err)
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/json-search-functions.html#function_json-contains
+func builtinJSONContains(args []interface{}, _ map[interface{}]interface{}) (interface{}, error) {
+	if hasNullArg(args) {
+		return nil, nil
+	}
+	target, err := jsonArg(args, 0, "json_contains")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	candidate, err := jsonArg(args, 1, "json_contains")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(args) == 3 {
+		paths, err := jsonPathArgs(args[2:])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if paths[0].HasWildcard() {
+			return nil, mysql.NewDefaultError(mysql.ErInvalidJSONPathWildcard)
+		}
+		var found bool
+		if target, found = target.Extract(paths); !found {
+			return nil, nil
+		}
+	}
+	if target.Contains(candidate) {
+		return int64(1), nil
+	}
+	return int64(0), nil
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/json-creation-functions.html#function_json-array
+func builtinJSONArray(args []interface{}, _ map[interface{}]interface{}) (interface{}, error) {
+	elems := make([]mysql.JSON, len(args))
+	for i, arg := range args {
+		j, err := jsonValueArg(arg)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		elems[i] = j
+	}
+	return mysql.CreateJSONArray(elems), nil
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/json-creation-functions.html#function_json-object
+func builtinJSONObject(args []interface{}, _ map[interface{}]interface{}) (interface{}, error) {
+	if len(args)%2 != 0 {
+		return nil, mysql.NewDefaultError(mysql.ErWrongParamcountToNativeFct, "json_object")
+	}
+	keys := make([]string, 0, len(args)/2)
+	values := make([]mysql.JSON, 0, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		if args[i] == nil {
+			return nil, errors.New("JSON documents may not contain NULL member names.")
+		}
+		k, err := types.ToString(args[i])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		v, err := jsonValueArg(args[i+1])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		keys, values = append(keys, k), append(values, v)
+	}
+	return mysql.CreateJSONObject(keys, values), nil
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/json-attribute-functions.html#function_json-type
+func builtinJSONType(args []interface{}, _ map[interface{}]interface{}) (interface{}, error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	j, err := jsonArg(args, 0, "json_type")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return j.Type(), nil
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/json-attribute-functions.html#function_json-valid
+func builtinJSONValid(args []interface{}, _ map[interface{}]interface{}) (interface{}, error) {
+	switch x := args[0].(type) {
+	case nil:
+		return nil, nil
+	case mysql.JSON:
+		return int64(1), nil
+	case string, []byte:
+		if _, err := jsonArg([]interface{}{x}, 0, "json_valid"); err != nil {
+			return int64(0), nil
+		}
+		return int64(1), nil
+	}
+	return int64(0), nil
+}
@@ -0,0 +1,90 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/juju/errors"
+	. "github.com/pingcap/check"
+)
+
+func (s *testBuiltinSuite) TestJSONFuncs(c *C) {
+	doc := `{"a": [1, {"b": "x"}], "c": true}`
+	tbl := []struct {
+		F    string
+		Args []interface{}
+		Ret  interface{}
+	}{
+		{"json_extract", []interface{}{doc, "$.a[1].b"}, `"x"`},
+		{"json_extract", []interface{}{doc, "$.a[*]"}, `[1, {"b": "x"}]`},
+		{"json_extract", []interface{}{doc, "$.c", "$.a[0]"}, `[true, 1]`},
+		{"json_extract", []interface{}{doc, "$**.b"}, `["x"]`},
+		{"json_extract", []interface{}{doc, "$.d"}, nil},
+		{"json_extract", []interface{}{nil, "$.d"}, nil},
+		{"json_unquote", []interface{}{`"a\tb"`}, "a\tb"},
+		{"json_unquote", []interface{}{"abc"}, "abc"},
+		{"json_set", []interface{}{doc, "$.c", int64(1), "$.d", "y"}, `{"a": [1, {"b": "x"}], "c": 1, "d": "y"}`},
+		{"json_insert", []interface{}{doc, "$.c", int64(1), "$.a[5]", 2.5}, `{"a": [1, {"b": "x"}, 2.5], "c": true}`},
+		{"json_replace", []interface{}{doc, "$.c", nil, "$.d", int64(1)}, `{"a": [1, {"b": "x"}], "c": null}`},
+		{"json_set", []interface{}{doc, nil, int64(1)}, nil},
+		{"json_remove", []interface{}{doc, "$.a[0]", "$.c"}, `{"a": [{"b": "x"}]}`},
+		{"json_contains", []interface{}{doc, `{"c": true}`}, int64(1)},
+		{"json_contains", []interface{}{doc, `[1]`, "$.a"}, int64(1)},
+		{"json_contains", []interface{}{doc, `2`, "$.a"}, int64(0)},
+		{"json_contains", []interface{}{doc, `2`, "$.d"}, nil},
+		{"json_array", []interface{}{int64(1), "a", nil, false}, `[1, "a", null, 0]`},
+		{"json_array", []interface{}{}, `[]`},
+		{"json_object", []interface{}{"k", 1.5, "a", int64(1)}, `{"a": 1, "k": 1.5}`},
+		{"json_type", []interface{}{`[1]`}, "ARRAY"},
+		{"json_type", []interface{}{`18446744073709551615`}, "UNSIGNED INTEGER"},
+		{"json_type", []interface{}{nil}, nil},
+		{"json_valid", []interface{}{`{"a": 1}`}, int64(1)},
+		{"json_valid", []interface{}{`{"a": }`}, int64(0)},
+		{"json_valid", []interface{}{int64(1)}, int64(0)},
+	}
+
+	for _, t := range tbl {
+		v, err := builtin[t.F].f(t.Args, nil)
+		c.Assert(err, IsNil, Commentf("%s%v", t.F, t.Args))
+		if j, ok := v.(mysql.JSON); ok {
+			v = j.String()
+		}
+		c.Assert(v, Equals, t.Ret, Commentf("%s%v", t.F, t.Args))
+	}
+
+	errTbl := []struct {
+		F    string
+		Args []interface{}
+		Code uint16
+	}{
+		{"json_extract", []interface{}{`{"a": }`, "$"}, mysql.ErInvalidJSONTextInParam},
+		{"json_extract", []interface{}{int64(1), "$"}, mysql.ErInvalidTypeForJSON},
+		{"json_extract", []interface{}{doc, "$."}, mysql.ErInvalidJSONPath},
+		{"json_set", []interface{}{doc, "$.a[*]", int64(1)}, mysql.ErInvalidJSONPathWildcard},
+		{"json_set", []interface{}{doc, "$.a"}, mysql.ErWrongParamcountToNativeFct},
+		{"json_object", []interface{}{"a"}, mysql.ErWrongParamcountToNativeFct},
+	}
+
+	for _, t := range errTbl {
+		_, err := builtin[t.F].f(t.Args, nil)
+		c.Assert(err, NotNil, Commentf("%s%v", t.F, t.Args))
+		code := errors.Cause(err).(*mysql.SQLError).Code
+		c.Assert(code, Equals, t.Code, Commentf("%s%v", t.F, t.Args))
+	}
+
+	_, err := builtin["json_remove"].f([]interface{}{doc, "$"}, nil)
+	c.Assert(err, NotNil)
+	_, err = builtin["json_object"].f([]interface{}{nil, int64(1)}, nil)
+	c.Assert(err, NotNil)
+}
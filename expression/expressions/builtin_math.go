@@ -14,13 +14,77 @@
 package expressions
 
 import (
+	"fmt"
 	"math"
+	"math/bits"
+	"math/rand"
+	"strconv"
+	"strings"
 
+	"github.com/Dong-Chan/alloydb/context"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/parser/opcode"
 	"github.com/Dong-Chan/alloydb/util/types"
+	"github.com/juju/errors"
 )
 
 // see https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html
 
+// maxRoundDigits is the max number of fractional digits for ROUND and TRUNCATE.
+const maxRoundDigits = 30
+
+// numericArg converts a function argument to int64, uint64, float64 or mysql.Decimal,
+// strings are converted to float64 like MySQL does.
+func numericArg(arg interface{}) (interface{}, error) {
+	switch x := arg.(type) {
+	case int64, uint64, float64, mysql.Decimal:
+		return x, nil
+	case bool, int, int8, int16, int32:
+		return types.ToInt64(x)
+	case uint, uint8, uint16, uint32:
+		v, err := types.ToInt64(x)
+		return uint64(v), err
+	case float32:
+		return float64(x), nil
+	case string:
+		return types.StrToFloat(x)
+	case []byte:
+		return types.StrToFloat(string(x))
+	case mysql.Time:
+		return x.ToNumber(), nil
+	case mysql.Duration:
+		return x.ToNumber(), nil
+	default:
+		return nil, errors.Errorf("invalid numeric argument %v(type %T)", arg, arg)
+	}
+}
+
+// floatArg converts a function argument to float64.
+func floatArg(arg interface{}) (float64, error) {
+	v, err := numericArg(arg)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return types.ToFloat64(v)
+}
+
+// floatResult checks the result of a float function, the infinite value is out of range
+// and NaN means the result is not defined so NULL is returned.
+func floatResult(f float64, name string, args []interface{}) (interface{}, error) {
+	if math.IsNaN(f) {
+		return nil, nil
+	}
+	if math.IsInf(f, 0) {
+		a := make([]string, len(args))
+		for i, v := range args {
+			a[i] = fmt.Sprintf("%v", v)
+		}
+		expr := fmt.Sprintf("%s(%s)", name, strings.Join(a, ","))
+		return nil, errors.Trace(mysql.NewDefaultError(mysql.ErDataOutOfRange, "DOUBLE", expr))
+	}
+	return f, nil
+}
+
 func builtinAbs(args []interface{}, ctx map[interface{}]interface{}) (v interface{}, err error) {
 	switch x := args[0].(type) {
 	case nil:
@@ -36,6 +100,8 @@ func builtinAbs(args []interface{}, ctx map[interface{}]interface{}) (v interfac
 
 		// TODO: handle overflow if x is MinInt64
 		return -v, nil
+	case mysql.Decimal:
+		return x.Abs(), nil
 	default:
 		// we will try to convert other types to float
 		// TODO: if time has no precision, it will be a integer
@@ -43,3 +109,414 @@ func builtinAbs(args []interface{}, ctx map[interface{}]interface{}) (v interfac
 		return math.Abs(f), err
 	}
 }
+
+// roundDigitsArg gets the number of fractional digits argument of ROUND and TRUNCATE.
+func roundDigitsArg(args []interface{}) (d int64, isNull bool, err error) {
+	if len(args) < 2 {
+		return 0, false, nil
+	}
+	if args[1] == nil {
+		return 0, true, nil
+	}
+	d, err = types.ToInt64(args[1])
+	if d > maxRoundDigits {
+		d = maxRoundDigits
+	}
+	return d, false, errors.Trace(err)
+}
+
+// roundUint rounds or truncates the integer part of v to the nearest 10^(-d), d must be negative.
+func roundUint(v uint64, d int64, truncate bool) uint64 {
+	// 10^20 is greater than the max uint64.
+	if d < -19 {
+		return 0
+	}
+	p := uint64(1)
+	for i := d; i < 0; i++ {
+		p *= 10
+	}
+	q, r := v/p, v%p
+	if !truncate && r >= p-r {
+		q++
+	}
+	// TODO: handle overflow
+	return q * p
+}
+
+func roundInt(v int64, d int64, truncate bool) int64 {
+	if v < 0 {
+		return -int64(roundUint(uint64(-v), d, truncate))
+	}
+	return int64(roundUint(uint64(v), d, truncate))
+}
+
+func roundNumber(arg interface{}, d int64, truncate bool) (interface{}, error) {
+	x, err := numericArg(arg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	switch v := x.(type) {
+	case int64:
+		if d >= 0 {
+			return v, nil
+		}
+		return roundInt(v, d, truncate), nil
+	case uint64:
+		if d >= 0 {
+			return v, nil
+		}
+		return roundUint(v, d, truncate), nil
+	case mysql.Decimal:
+		if !truncate {
+			return v.Round(int32(d)), nil
+		}
+		if d >= 0 {
+			return v.Truncate(int32(d)), nil
+		}
+		// Decimal Truncate doesn't support negative precision,
+		// we subtract the remainder of 10^(-d) instead.
+		rem := v.Mod(mysql.NewDecimalFromInt(1, int32(-d)))
+		return v.Sub(rem).Truncate(0), nil
+	default:
+		f := x.(float64)
+		pow := math.Pow10(int(d))
+		if pow == 0 {
+			return float64(0), nil
+		}
+		var r float64
+		if truncate {
+			r = math.Trunc(f*pow) / pow
+		} else {
+			r = types.RoundFloat(f*pow) / pow
+		}
+		if math.IsNaN(r) || math.IsInf(r, 0) {
+			// f*pow overflows, f has no more digits to round.
+			return f, nil
+		}
+		return r, nil
+	}
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_round
+func builtinRound(args []interface{}, ctx map[interface{}]interface{}) (v interface{}, err error) {
+	d, isNull, err := roundDigitsArg(args)
+	if err != nil || isNull || args[0] == nil {
+		return nil, errors.Trace(err)
+	}
+	return roundNumber(args[0], d, false)
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_truncate
+func builtinTruncate(args []interface{}, ctx map[interface{}]interface{}) (v interface{}, err error) {
+	d, isNull, err := roundDigitsArg(args)
+	if err != nil || isNull || args[0] == nil {
+		return nil, errors.Trace(err)
+	}
+	return roundNumber(args[0], d, true)
+}
+
+func ceilFloor(arg interface{}, isCeil bool) (interface{}, error) {
+	if arg == nil {
+		return nil, nil
+	}
+	x, err := numericArg(arg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	switch v := x.(type) {
+	case mysql.Decimal:
+		if v.Exponent() >= 0 {
+			// v is an integer already.
+			return v.Truncate(0), nil
+		}
+		if isCeil {
+			return v.Ceil(), nil
+		}
+		return v.Floor(), nil
+	case float64:
+		if isCeil {
+			return math.Ceil(v), nil
+		}
+		return math.Floor(v), nil
+	default:
+		return v, nil
+	}
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_ceiling
+func builtinCeil(args []interface{}, ctx map[interface{}]interface{}) (v interface{}, err error) {
+	return ceilFloor(args[0], true)
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_floor
+func builtinFloor(args []interface{}, ctx map[interface{}]interface{}) (v interface{}, err error) {
+	return ceilFloor(args[0], false)
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_mod
+func builtinMod(args []interface{}, data map[interface{}]interface{}) (v interface{}, err error) {
+	// MOD(N, M) is the same as N % M.
+	ctx, _ := data[ExprEvalArgCtx].(context.Context)
+	expr := NewBinaryOperation(opcode.Mod, Value{args[0]}, Value{args[1]})
+	return expr.Eval(ctx, data)
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_sign
+func builtinSign(args []interface{}, ctx map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	x, err := numericArg(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	switch v := x.(type) {
+	case int64:
+		return int64(types.CompareInt64(v, 0)), nil
+	case uint64:
+		return int64(types.CompareUint64(v, 0)), nil
+	case mysql.Decimal:
+		return int64(v.Cmp(mysql.ZeroDecimal)), nil
+	default:
+		return int64(types.CompareFloat64(x.(float64), 0)), nil
+	}
+}
+
+// floatFunc returns a builtin function which calls f with the float64 value of all arguments.
+func floatFunc(name string, f func(x []float64) float64) func([]interface{}, map[interface{}]interface{}) (interface{}, error) {
+	return func(args []interface{}, ctx map[interface{}]interface{}) (interface{}, error) {
+		x := make([]float64, len(args))
+		for i, arg := range args {
+			if arg == nil {
+				return nil, nil
+			}
+			v, err := floatArg(arg)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			x[i] = v
+		}
+		return floatResult(f(x), name, args)
+	}
+}
+
+var (
+	builtinPow = floatFunc("pow", func(x []float64) float64 {
+		return math.Pow(x[0], x[1])
+	})
+	builtinSqrt = floatFunc("sqrt", func(x []float64) float64 {
+		// NaN for negative value, so the result is NULL.
+		return math.Sqrt(x[0])
+	})
+	builtinExp = floatFunc("exp", func(x []float64) float64 {
+		return math.Exp(x[0])
+	})
+	builtinLn = floatFunc("ln", func(x []float64) float64 {
+		return logarithm(x[0])
+	})
+	builtinLog = floatFunc("log", func(x []float64) float64 {
+		if len(x) == 1 {
+			return logarithm(x[0])
+		}
+		// LOG(B, X) is LN(X) / LN(B), the base must be greater than 0 and not equal to 1.
+		if x[0] == 1 {
+			return math.NaN()
+		}
+		return logarithm(x[1]) / logarithm(x[0])
+	})
+	builtinLog2 = floatFunc("log2", func(x []float64) float64 {
+		return logarithm(x[0]) / math.Ln2
+	})
+	builtinLog10 = floatFunc("log10", func(x []float64) float64 {
+		return logarithm(x[0]) / math.Ln10
+	})
+	builtinSin = floatFunc("sin", func(x []float64) float64 {
+		return math.Sin(x[0])
+	})
+	builtinCos = floatFunc("cos", func(x []float64) float64 {
+		return math.Cos(x[0])
+	})
+	builtinTan = floatFunc("tan", func(x []float64) float64 {
+		return math.Tan(x[0])
+	})
+	builtinCot = floatFunc("cot", func(x []float64) float64 {
+		// COT(0) is infinite, which is out of range.
+		return 1 / math.Tan(x[0])
+	})
+	builtinAsin = floatFunc("asin", func(x []float64) float64 {
+		return math.Asin(x[0])
+	})
+	builtinAcos = floatFunc("acos", func(x []float64) float64 {
+		return math.Acos(x[0])
+	})
+	builtinAtan = floatFunc("atan", func(x []float64) float64 {
+		if len(x) == 1 {
+			return math.Atan(x[0])
+		}
+		return math.Atan2(x[0], x[1])
+	})
+	builtinAtan2 = floatFunc("atan2", func(x []float64) float64 {
+		return math.Atan2(x[0], x[1])
+	})
+	builtinDegrees = floatFunc("degrees", func(x []float64) float64 {
+		return x[0] * 180 / math.Pi
+	})
+	builtinRadians = floatFunc("radians", func(x []float64) float64 {
+		return x[0] * math.Pi / 180
+	})
+)
+
+// logarithm returns the natural logarithm of x, or NaN if x is not greater than 0.
+func logarithm(x float64) float64 {
+	if x <= 0 {
+		return math.NaN()
+	}
+	return math.Log(x)
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_pi
+func builtinPI(args []interface{}, ctx map[interface{}]interface{}) (v interface{}, err error) {
+	return math.Pi, nil
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_rand
+func builtinRand(args []interface{}, ctx map[interface{}]interface{}) (v interface{}, err error) {
+	if len(args) == 0 {
+		return rand.Float64(), nil
+	}
+
+	var seed int64
+	if args[0] != nil {
+		if seed, err = types.ToInt64(args[0]); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	// With a constant seed, RAND(N) produces a repeatable sequence of values in a statement,
+	// otherwise the generator is seeded with the argument for every row.
+	c, ok := ctx[ExprEvalFn].(*Call)
+	if !ok || !c.Args[0].IsStatic() {
+		return rand.New(rand.NewSource(seed)).Float64(), nil
+	}
+	if c.rand == nil {
+		c.rand = rand.New(rand.NewSource(seed))
+	}
+	return c.rand.Float64(), nil
+}
+
+// digitValue returns the value of digit c in base 36, or 36 if c is not a digit.
+func digitValue(c byte) int64 {
+	switch {
+	case c >= '0' && c <= '9':
+		return int64(c - '0')
+	case c >= 'a' && c <= 'z':
+		return int64(c-'a') + 10
+	case c >= 'A' && c <= 'Z':
+		return int64(c-'A') + 10
+	default:
+		return 36
+	}
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_conv
+func builtinConv(args []interface{}, ctx map[interface{}]interface{}) (v interface{}, err error) {
+	for _, arg := range args {
+		if arg == nil {
+			return nil, nil
+		}
+	}
+	str, err := types.ToString(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	fromBase, err := types.ToInt64(args[1])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	toBase, err := types.ToInt64(args[2])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// A negative base means the number is signed, otherwise it is unsigned.
+	signed := false
+	if fromBase < 0 {
+		fromBase, signed = -fromBase, true
+	}
+	if toBase < 0 {
+		toBase, signed = -toBase, true
+	}
+	if fromBase < 2 || fromBase > 36 || toBase < 2 || toBase > 36 {
+		return nil, nil
+	}
+
+	str = strings.TrimSpace(str)
+	negative := false
+	if strings.HasPrefix(str, "-") {
+		negative, str = true, str[1:]
+	}
+	// Only the leading valid digits are used.
+	end := 0
+	for end < len(str) && digitValue(str[end]) < fromBase {
+		end++
+	}
+	var n uint64
+	if end > 0 {
+		n, err = strconv.ParseUint(str[:end], int(fromBase), 64)
+		if err != nil {
+			// The value overflows.
+			n = math.MaxUint64
+		}
+	}
+
+	if signed && !negative && int64(n) < 0 {
+		n, negative = uint64(-int64(n)), true
+	}
+	if negative && !signed {
+		// The two's complement of the value.
+		n, negative = -n, false
+	}
+
+	s := strings.ToUpper(strconv.FormatUint(n, int(toBase)))
+	if negative && n != 0 {
+		s = "-" + s
+	}
+	return s, nil
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_bin
+func builtinBin(args []interface{}, ctx map[interface{}]interface{}) (v interface{}, err error) {
+	return builtinConv([]interface{}{args[0], 10, 2}, ctx)
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_oct
+func builtinOct(args []interface{}, ctx map[interface{}]interface{}) (v interface{}, err error) {
+	return builtinConv([]interface{}{args[0], 10, 8}, ctx)
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/bit-functions.html#function_bit-count
+func builtinBitCount(args []interface{}, ctx map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	x, err := numericArg(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var n uint64
+	switch v := x.(type) {
+	case uint64:
+		n = v
+	default:
+		i, err := types.ToInt64(v)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		n = uint64(i)
+	}
+	return int64(bits.OnesCount64(n)), nil
+}
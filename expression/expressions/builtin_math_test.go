@@ -14,6 +14,11 @@
 package expressions
 
 import (
+	"math"
+
+	"github.com/Dong-Chan/alloydb/expression"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/juju/errors"
 	. "github.com/pingcap/check"
 )
 
@@ -36,3 +41,182 @@ func (s *testBuiltinSuite) TestAbs(c *C) {
 		c.Assert(v, DeepEquals, t.Ret)
 	}
 }
+
+func (s *testBuiltinSuite) TestRoundAndTruncate(c *C) {
+	d := func(str string) mysql.Decimal {
+		v, err := mysql.ParseDecimal(str)
+		c.Assert(err, IsNil)
+		return v
+	}
+
+	tbl := []struct {
+		F    string
+		Args []interface{}
+		Ret  interface{}
+	}{
+		{"round", []interface{}{nil}, nil},
+		{"round", []interface{}{int64(1), nil}, nil},
+		{"round", []interface{}{int64(-15)}, int64(-15)},
+		{"round", []interface{}{int64(1251), int64(-2)}, int64(1300)},
+		{"round", []interface{}{int64(-1250), int64(-2)}, int64(-1300)},
+		{"round", []interface{}{uint64(1249), int64(-2)}, uint64(1200)},
+		{"round", []interface{}{int64(1249), int64(-20)}, int64(0)},
+		{"round", []interface{}{d("2.5")}, "3"},
+		{"round", []interface{}{d("-2.5")}, "-3"},
+		{"round", []interface{}{d("1.298"), int64(1)}, "1.3"},
+		{"round", []interface{}{d("1.298"), int64(4)}, "1.2980"},
+		{"round", []interface{}{d("23.298"), int64(-1)}, "20"},
+		{"round", []interface{}{float64(2.5)}, float64(2)},
+		{"round", []interface{}{float64(-1.58)}, float64(-2)},
+		{"round", []interface{}{float64(1.235), int64(1)}, float64(1.2)},
+		{"round", []interface{}{"1.58"}, float64(2)},
+		{"truncate", []interface{}{int64(1299), int64(-2)}, int64(1200)},
+		{"truncate", []interface{}{int64(-1299), int64(-2)}, int64(-1200)},
+		{"truncate", []interface{}{d("1.223"), int64(1)}, "1.2"},
+		{"truncate", []interface{}{d("1.999"), int64(0)}, "1"},
+		{"truncate", []interface{}{d("-1.999"), int64(1)}, "-1.9"},
+		{"truncate", []interface{}{d("122.5"), int64(-2)}, "100"},
+		{"truncate", []interface{}{d("-122.5"), int64(-2)}, "-100"},
+		{"truncate", []interface{}{float64(1.999), int64(1)}, float64(1.9)},
+		{"truncate", []interface{}{float64(-1.999), int64(0)}, float64(-1)},
+		{"ceil", []interface{}{nil}, nil},
+		{"ceil", []interface{}{int64(3)}, int64(3)},
+		{"ceil", []interface{}{d("1.23")}, "2"},
+		{"ceil", []interface{}{d("-1.23")}, "-1"},
+		{"ceil", []interface{}{mysql.NewDecimalFromInt(12, 1)}, "120"},
+		{"ceil", []interface{}{float64(-1.23)}, float64(-1)},
+		{"floor", []interface{}{d("1.23")}, "1"},
+		{"floor", []interface{}{d("-1.23")}, "-2"},
+		{"floor", []interface{}{float64(1.23)}, float64(1)},
+		{"floor", []interface{}{uint64(7)}, uint64(7)},
+	}
+
+	for _, t := range tbl {
+		v, err := builtin[t.F].f(t.Args, nil)
+		c.Assert(err, IsNil)
+		if x, ok := v.(mysql.Decimal); ok {
+			c.Assert(x.String(), Equals, t.Ret, Commentf("%s%v", t.F, t.Args))
+			continue
+		}
+		c.Assert(v, DeepEquals, t.Ret, Commentf("%s%v", t.F, t.Args))
+	}
+}
+
+func (s *testBuiltinSuite) TestMathFuncs(c *C) {
+	d := func(str string) mysql.Decimal {
+		v, err := mysql.ParseDecimal(str)
+		c.Assert(err, IsNil)
+		return v
+	}
+
+	tbl := []struct {
+		F    string
+		Args []interface{}
+		Ret  interface{}
+	}{
+		{"abs", []interface{}{d("-1.50")}, "1.50"},
+		{"mod", []interface{}{int64(234), int64(10)}, int64(4)},
+		{"mod", []interface{}{d("34.5"), int64(3)}, "1.5"},
+		{"mod", []interface{}{int64(3), int64(0)}, nil},
+		{"mod", []interface{}{nil, int64(3)}, nil},
+		{"sign", []interface{}{int64(-32)}, int64(-1)},
+		{"sign", []interface{}{uint64(0)}, int64(0)},
+		{"sign", []interface{}{d("0.01")}, int64(1)},
+		{"sign", []interface{}{float64(-0.5)}, int64(-1)},
+		{"pow", []interface{}{int64(2), int64(-2)}, float64(0.25)},
+		{"pow", []interface{}{nil, int64(2)}, nil},
+		{"pow", []interface{}{float64(-8), float64(1) / 3}, nil},
+		{"sqrt", []interface{}{int64(4)}, float64(2)},
+		{"sqrt", []interface{}{int64(-16)}, nil},
+		{"exp", []interface{}{int64(0)}, float64(1)},
+		{"ln", []interface{}{int64(1)}, float64(0)},
+		{"ln", []interface{}{int64(0)}, nil},
+		{"log", []interface{}{int64(-2)}, nil},
+		{"log", []interface{}{int64(2), int64(65536)}, float64(16)},
+		{"log", []interface{}{int64(1), int64(100)}, nil},
+		{"log2", []interface{}{int64(65536)}, float64(16)},
+		{"log10", []interface{}{int64(100)}, float64(2)},
+		{"log10", []interface{}{int64(-100)}, nil},
+		{"pi", []interface{}{}, math.Pi},
+		{"sin", []interface{}{int64(0)}, float64(0)},
+		{"cos", []interface{}{int64(0)}, float64(1)},
+		{"tan", []interface{}{int64(0)}, float64(0)},
+		{"asin", []interface{}{int64(2)}, nil},
+		{"acos", []interface{}{int64(1)}, float64(0)},
+		{"atan", []interface{}{int64(0)}, float64(0)},
+		{"atan", []interface{}{int64(1), int64(0)}, math.Pi / 2},
+		{"atan2", []interface{}{int64(-1), int64(0)}, -math.Pi / 2},
+		{"degrees", []interface{}{math.Pi}, float64(180)},
+		{"radians", []interface{}{int64(90)}, math.Pi / 2},
+		{"conv", []interface{}{"a", int64(16), int64(2)}, "1010"},
+		{"conv", []interface{}{"6E", int64(18), int64(8)}, "172"},
+		{"conv", []interface{}{int64(-17), int64(10), int64(-18)}, "-H"},
+		{"conv", []interface{}{"-1", int64(10), int64(16)}, "FFFFFFFFFFFFFFFF"},
+		{"conv", []interface{}{"12abc", int64(10), int64(10)}, "12"},
+		{"conv", []interface{}{"10", int64(1), int64(10)}, nil},
+		{"conv", []interface{}{nil, int64(10), int64(10)}, nil},
+		{"bin", []interface{}{int64(12)}, "1100"},
+		{"oct", []interface{}{int64(12)}, "14"},
+		{"bit_count", []interface{}{int64(29)}, int64(4)},
+		{"bit_count", []interface{}{int64(-1)}, int64(64)},
+		{"bit_count", []interface{}{nil}, nil},
+	}
+
+	for _, t := range tbl {
+		v, err := builtin[t.F].f(t.Args, nil)
+		c.Assert(err, IsNil, Commentf("%s%v", t.F, t.Args))
+		if x, ok := v.(mysql.Decimal); ok {
+			c.Assert(x.String(), Equals, t.Ret, Commentf("%s%v", t.F, t.Args))
+			continue
+		}
+		c.Assert(v, DeepEquals, t.Ret, Commentf("%s%v", t.F, t.Args))
+	}
+
+	// The infinite result is out of range.
+	for _, t := range []struct {
+		F    string
+		Args []interface{}
+	}{
+		{"exp", []interface{}{int64(1000)}},
+		{"pow", []interface{}{int64(10), int64(400)}},
+		{"cot", []interface{}{int64(0)}},
+	} {
+		_, err := builtin[t.F].f(t.Args, nil)
+		c.Assert(err, NotNil)
+		c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErDataOutOfRange))
+	}
+
+	_, err := builtinSqrt([]interface{}{"abc"}, nil)
+	c.Assert(err, NotNil)
+}
+
+func (s *testBuiltinSuite) TestRand(c *C) {
+	v, err := builtinRand(nil, nil)
+	c.Assert(err, IsNil)
+	f := v.(float64)
+	c.Assert(f >= 0 && f < 1, IsTrue)
+
+	// The same seed produces the same value.
+	v1, err := builtinRand([]interface{}{int64(3)}, nil)
+	c.Assert(err, IsNil)
+	v2, err := builtinRand([]interface{}{int64(3)}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v1, Equals, v2)
+
+	// A constant seed produces a repeatable sequence for a call expression.
+	call, err := NewCall("rand", []expression.Expression{Value{int64(3)}}, false)
+	c.Assert(err, IsNil)
+	m := map[interface{}]interface{}{}
+	first, err := call.Eval(nil, m)
+	c.Assert(err, IsNil)
+	c.Assert(first, Equals, v1)
+	second, err := call.Eval(nil, map[interface{}]interface{}{})
+	c.Assert(err, IsNil)
+	c.Assert(second, Not(Equals), first)
+
+	clone, err := call.Clone()
+	c.Assert(err, IsNil)
+	v, err = clone.Eval(nil, map[interface{}]interface{}{})
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, first)
+}
@@ -14,8 +14,12 @@
 package expressions
 
 import (
+	"encoding/hex"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/juju/errors"
 	"github.com/Dong-Chan/alloydb/util/types"
@@ -23,12 +27,69 @@ import (
 
 // https://dev.mysql.com/doc/refman/5.7/en/string-functions.html
 
+// stringArg converts the argument of a string function to a string. The binary string ([]byte), which
+// is the value of the binary charset, is handled byte by byte, other strings are handled character by character.
+func stringArg(arg interface{}) (str string, binary bool, err error) {
+	if b, ok := arg.([]byte); ok {
+		return string(b), true, nil
+	}
+	str, err = types.ToString(arg)
+	return str, false, errors.Trace(err)
+}
+
+// stringValue returns the string of v if v is a string or a binary string, the binary string
+// is used as the string of its bytes.
+func stringValue(v interface{}) (str string, ok bool) {
+	switch x := v.(type) {
+	case string:
+		return x, true
+	case []byte:
+		return string(x), true
+	}
+	return "", false
+}
+
+// toChars splits str into the characters, every byte is a character of a binary string.
+func toChars(str string, binary bool) []rune {
+	if !binary {
+		return []rune(str)
+	}
+	chars := make([]rune, len(str))
+	for i := 0; i < len(str); i++ {
+		chars[i] = rune(str[i])
+	}
+	return chars
+}
+
+// fromChars joins the characters to a string, or a binary string if binary is set.
+func fromChars(chars []rune, binary bool) interface{} {
+	if !binary {
+		return string(chars)
+	}
+	b := make([]byte, len(chars))
+	for i, c := range chars {
+		b[i] = byte(c)
+	}
+	return b
+}
+
+// stringResult returns str as a binary string if binary is set.
+func stringResult(str string, binary bool) interface{} {
+	if binary {
+		return []byte(str)
+	}
+	return str
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_length
 func builtinLength(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
 	switch x := args[0].(type) {
 	case nil:
 		return nil, nil
 	case string:
 		return int64(len(x)), nil
+	case []byte:
+		return int64(len(x)), nil
 	default:
 		return nil, invArg(x, "length")
 	}
@@ -79,8 +140,14 @@ func builtinConcatWS(args []interface{}, ctx map[interface{}]interface{}) (v int
 
 // See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_left
 func builtinLeft(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
-	str, ok := args[0].(string)
-	if !ok {
+	var chars []rune
+	binary := false
+	switch x := args[0].(type) {
+	case string:
+		chars = toChars(x, false)
+	case []byte:
+		chars, binary = toChars(string(x), true), true
+	default:
 		return nil, errors.Errorf("BuiltinLeft invalid args, need string but get %T", args[0])
 	}
 	// TODO: deal with other types
@@ -91,10 +158,10 @@ func builtinLeft(args []interface{}, _ map[interface{}]interface{}) (v interface
 	l := int(length)
 	if l < 0 {
 		l = 0
-	} else if l > len(str) {
-		l = len(str)
+	} else if l > len(chars) {
+		l = len(chars)
 	}
-	return str[:l], nil
+	return fromChars(chars[:l], binary), nil
 }
 
 // See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_repeat
@@ -112,3 +179,559 @@ func builtinRepeat(args []interface{}, ctx map[interface{}]interface{}) (v inter
 	}
 	return strings.Repeat(ch, num), nil
 }
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_char-length
+func builtinCharLength(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	str, binary, err := stringArg(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return int64(len(toChars(str, binary))), nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_lower
+func builtinLower(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	str, binary, err := stringArg(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// LOWER is ineffective for the binary strings.
+	if binary {
+		return []byte(str), nil
+	}
+	return strings.ToLower(str), nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_upper
+func builtinUpper(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	str, binary, err := stringArg(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// UPPER is ineffective for the binary strings.
+	if binary {
+		return []byte(str), nil
+	}
+	return strings.ToUpper(str), nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_ltrim
+func builtinLTrim(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	str, binary, err := stringArg(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return stringResult(strings.TrimLeft(str, " "), binary), nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_rtrim
+func builtinRTrim(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	str, binary, err := stringArg(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return stringResult(strings.TrimRight(str, " "), binary), nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_replace
+func builtinReplace(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	strs := make([]string, len(args))
+	binary := false
+	for i, arg := range args {
+		if arg == nil {
+			return nil, nil
+		}
+		var b bool
+		if strs[i], b, err = stringArg(arg); err != nil {
+			return nil, errors.Trace(err)
+		}
+		binary = binary || b
+	}
+	if strs[1] == "" {
+		return stringResult(strs[0], binary), nil
+	}
+	return stringResult(strings.Replace(strs[0], strs[1], strs[2], -1), binary), nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_right
+func builtinRight(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil || args[1] == nil {
+		return nil, nil
+	}
+	str, binary, err := stringArg(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	length, err := types.ToInt64(args[1])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	chars := toChars(str, binary)
+	l := int(length)
+	if l < 0 {
+		l = 0
+	} else if l > len(chars) {
+		l = len(chars)
+	}
+	return fromChars(chars[len(chars)-l:], binary), nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_lpad
+func builtinLpad(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	return pad(args, true)
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_rpad
+func builtinRpad(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	return pad(args, false)
+}
+
+// pad pads the string to the length with the pad string on the left or the right,
+// the string is shortened to the length if it is longer.
+func pad(args []interface{}, left bool) (interface{}, error) {
+	for _, arg := range args {
+		if arg == nil {
+			return nil, nil
+		}
+	}
+	str, binary, err := stringArg(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	length, err := types.ToInt64(args[1])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	padStr, padBinary, err := stringArg(args[2])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	binary = binary || padBinary
+
+	chars, padChars := toChars(str, binary), toChars(padStr, binary)
+	l := int(length)
+	if l < 0 || (l > len(chars) && len(padChars) == 0) {
+		return nil, nil
+	}
+	if l <= len(chars) {
+		return fromChars(chars[:l], binary), nil
+	}
+
+	padding := make([]rune, 0, l-len(chars))
+	for len(padding) < l-len(chars) {
+		padding = append(padding, padChars[len(padding)%len(padChars)])
+	}
+	if left {
+		return fromChars(append(padding, chars...), binary), nil
+	}
+	return fromChars(append(chars, padding...), binary), nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_reverse
+func builtinReverse(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	str, binary, err := stringArg(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	chars := toChars(str, binary)
+	for i, j := 0, len(chars)-1; i < j; i, j = i+1, j-1 {
+		chars[i], chars[j] = chars[j], chars[i]
+	}
+	return fromChars(chars, binary), nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_locate
+func builtinLocate(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	for _, arg := range args {
+		if arg == nil {
+			return nil, nil
+		}
+	}
+	pos := int64(1)
+	if len(args) == 3 {
+		if pos, err = types.ToInt64(args[2]); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return locate(args[0], args[1], pos)
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_instr
+func builtinInstr(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil || args[1] == nil {
+		return nil, nil
+	}
+	return locate(args[1], args[0], 1)
+}
+
+// locate returns the position of the first occurrence of substr in str starting at pos,
+// the positions are counted in characters from 1, 0 is returned if substr is not found.
+func locate(substrArg, strArg interface{}, pos int64) (interface{}, error) {
+	substr, subBinary, err := stringArg(substrArg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	str, binary, err := stringArg(strArg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	binary = binary || subBinary
+
+	chars, subChars := toChars(str, binary), toChars(substr, binary)
+	if pos < 1 || pos > int64(len(chars))+1 {
+		return int64(0), nil
+	}
+	for i := int(pos) - 1; i+len(subChars) <= len(chars); i++ {
+		if string(chars[i:i+len(subChars)]) == string(subChars) {
+			return int64(i + 1), nil
+		}
+	}
+	return int64(0), nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_substring-index
+func builtinSubstringIndex(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	for _, arg := range args {
+		if arg == nil {
+			return nil, nil
+		}
+	}
+	str, binary, err := stringArg(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	delim, delimBinary, err := stringArg(args[1])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	count, err := types.ToInt64(args[2])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	binary = binary || delimBinary
+
+	if len(delim) == 0 || count == 0 {
+		return stringResult("", binary), nil
+	}
+	parts := strings.Split(str, delim)
+	if count > 0 {
+		if int(count) < len(parts) {
+			parts = parts[:count]
+		}
+	} else if int(-count) < len(parts) {
+		parts = parts[len(parts)+int(count):]
+	}
+	return stringResult(strings.Join(parts, delim), binary), nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_field
+func builtinField(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil {
+		return int64(0), nil
+	}
+
+	// The arguments are compared as strings if all of them are strings, otherwise as numbers.
+	allString := true
+	for _, arg := range args {
+		switch arg.(type) {
+		case nil, string, []byte:
+		default:
+			allString = false
+		}
+	}
+
+	for i, arg := range args[1:] {
+		if arg == nil {
+			continue
+		}
+		if allString {
+			x, _, err := stringArg(args[0])
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			y, _, err := stringArg(arg)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if x == y {
+				return int64(i + 1), nil
+			}
+			continue
+		}
+
+		x, err := types.ToFloat64(args[0])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		y, err := types.ToFloat64(arg)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if x == y {
+			return int64(i + 1), nil
+		}
+	}
+	return int64(0), nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_find-in-set
+func builtinFindInSet(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil || args[1] == nil {
+		return nil, nil
+	}
+	str, _, err := stringArg(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	strList, _, err := stringArg(args[1])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Like MySQL, the string containing a comma is never found.
+	if strList == "" || strings.Contains(str, ",") {
+		return int64(0), nil
+	}
+	for i, s := range strings.Split(strList, ",") {
+		if s == str {
+			return int64(i + 1), nil
+		}
+	}
+	return int64(0), nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_elt
+func builtinElt(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	n, err := types.ToInt64(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n < 1 || n >= int64(len(args)) || args[n] == nil {
+		return nil, nil
+	}
+	if b, ok := args[n].([]byte); ok {
+		return b, nil
+	}
+	str, err := types.ToString(args[n])
+	return str, errors.Trace(err)
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_hex
+func builtinHex(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	switch x := args[0].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return strings.ToUpper(hex.EncodeToString([]byte(x))), nil
+	case []byte:
+		return strings.ToUpper(hex.EncodeToString(x)), nil
+	case uint64:
+		return strings.ToUpper(strconv.FormatUint(x, 16)), nil
+	}
+
+	// A number is rounded to an integer and its hexadecimal is of the 64-bit two's complement.
+	f, err := types.ToFloat64(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	n := int64(math.Floor(f + 0.5))
+	if f < 0 {
+		n = -int64(math.Floor(-f + 0.5))
+	}
+	return strings.ToUpper(strconv.FormatUint(uint64(n), 16)), nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_unhex
+func builtinUnHex(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	str, _, err := stringArg(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(str)%2 == 1 {
+		str = "0" + str
+	}
+	// The result is a binary string, the invalid hexadecimal digit is NULL.
+	b, err := hex.DecodeString(str)
+	if err != nil {
+		return nil, nil
+	}
+	return b, nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_ascii
+func builtinASCII(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	str, _, err := stringArg(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(str) == 0 {
+		return int64(0), nil
+	}
+	return int64(str[0]), nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_ord
+func builtinOrd(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	str, binary, err := stringArg(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(str) == 0 {
+		return int64(0), nil
+	}
+	if binary {
+		return int64(str[0]), nil
+	}
+
+	// The code of a multibyte character is calculated from the bytes of its encoding.
+	_, size := utf8.DecodeRuneInString(str)
+	var n int64
+	for i := 0; i < size; i++ {
+		n = n*256 + int64(str[i])
+	}
+	return n, nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_space
+func builtinSpace(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	n, err := types.ToInt64(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n < 1 {
+		return "", nil
+	}
+	return strings.Repeat(" ", int(n)), nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-comparison-functions.html#function_strcmp
+func builtinStrcmp(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil || args[1] == nil {
+		return nil, nil
+	}
+	x, _, err := stringArg(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	y, _, err := stringArg(args[1])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return int64(types.CompareString(x, y)), nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_format
+func builtinFormat(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	if args[0] == nil || args[1] == nil {
+		return nil, nil
+	}
+	// TODO: support the locale argument, en_US is used now.
+	d, err := types.ToDecimal(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	places, err := types.ToInt64(args[1])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if places < 0 {
+		places = 0
+	} else if places > 30 {
+		places = 30
+	}
+
+	str := d.StringFixed(int32(places))
+	sign := ""
+	if strings.HasPrefix(str, "-") {
+		sign, str = "-", str[1:]
+	}
+	intPart, fracPart := str, ""
+	if i := strings.Index(str, "."); i >= 0 {
+		intPart, fracPart = str[:i], str[i:]
+	}
+
+	var buf []byte
+	for i := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, intPart[i])
+	}
+	return sign + string(buf) + fracPart, nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_insert
+func builtinInsert(args []interface{}, _ map[interface{}]interface{}) (v interface{}, err error) {
+	for _, arg := range args {
+		if arg == nil {
+			return nil, nil
+		}
+	}
+	str, binary, err := stringArg(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	pos, err := types.ToInt64(args[1])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	length, err := types.ToInt64(args[2])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	newStr, newBinary, err := stringArg(args[3])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	binary = binary || newBinary
+
+	// The string is returned as it is if pos is out of it,
+	// the rest of the string is replaced if length is out of it.
+	chars := toChars(str, binary)
+	if pos < 1 || pos > int64(len(chars)) {
+		return stringResult(str, binary), nil
+	}
+	end := int64(len(chars))
+	if length >= 0 && pos-1+length < end {
+		end = pos - 1 + length
+	}
+	result := append([]rune{}, chars[:pos-1]...)
+	result = append(result, toChars(newStr, binary)...)
+	result = append(result, chars[end:]...)
+	return fromChars(result, binary), nil
+}
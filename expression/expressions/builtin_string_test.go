@@ -120,3 +120,97 @@ func (s *testBuiltinSuite) TestRepeat(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(v, Equals, "")
 }
+
+func (s *testBuiltinSuite) TestStringFuncs(c *C) {
+	tbl := []struct {
+		F      func([]interface{}, map[interface{}]interface{}) (interface{}, error)
+		Args   []interface{}
+		Expect interface{}
+	}{
+		{builtinLength, []interface{}{"日本"}, int64(6)},
+		{builtinLength, []interface{}{[]byte("abc")}, int64(3)},
+		{builtinCharLength, []interface{}{"日本"}, int64(2)},
+		{builtinCharLength, []interface{}{[]byte("日本")}, int64(6)},
+		{builtinCharLength, []interface{}{nil}, nil},
+		{builtinLeft, []interface{}{"日本語", int64(2)}, "日本"},
+		{builtinLeft, []interface{}{[]byte("日本語"), int64(2)}, []byte("日本語")[:2]},
+		{builtinRight, []interface{}{"foobarbar", int64(4)}, "rbar"},
+		{builtinRight, []interface{}{"日本語", int64(1)}, "語"},
+		{builtinRight, []interface{}{"abc", int64(-1)}, ""},
+		{builtinLower, []interface{}{"QuadRatically"}, "quadratically"},
+		{builtinLower, []interface{}{[]byte("ABC")}, []byte("ABC")},
+		{builtinUpper, []interface{}{"Hej"}, "HEJ"},
+		{builtinUpper, []interface{}{nil}, nil},
+		{builtinLTrim, []interface{}{"  barbar "}, "barbar "},
+		{builtinRTrim, []interface{}{" barbar   "}, " barbar"},
+		{builtinReplace, []interface{}{"www.mysql.com", "w", "Ww"}, "WwWwWw.mysql.com"},
+		{builtinReplace, []interface{}{"abc", "", "x"}, "abc"},
+		{builtinReplace, []interface{}{"abc", nil, "x"}, nil},
+		{builtinLpad, []interface{}{"hi", int64(4), "??"}, "??hi"},
+		{builtinLpad, []interface{}{"hi", int64(1), "??"}, "h"},
+		{builtinLpad, []interface{}{"日本", int64(5), "語"}, "語語語日本"},
+		{builtinLpad, []interface{}{"hi", int64(5), ""}, nil},
+		{builtinRpad, []interface{}{"hi", int64(5), "?"}, "hi???"},
+		{builtinRpad, []interface{}{"hi", int64(-1), "?"}, nil},
+		{builtinReverse, []interface{}{"abc日本"}, "本日cba"},
+		{builtinReverse, []interface{}{[]byte("ab")}, []byte("ba")},
+		{builtinLocate, []interface{}{"bar", "foobarbar"}, int64(4)},
+		{builtinLocate, []interface{}{"xbar", "foobar"}, int64(0)},
+		{builtinLocate, []interface{}{"bar", "foobarbar", int64(5)}, int64(7)},
+		{builtinLocate, []interface{}{"語", "日本語"}, int64(3)},
+		{builtinLocate, []interface{}{"", "abc", int64(4)}, int64(4)},
+		{builtinLocate, []interface{}{"a", nil}, nil},
+		{builtinInstr, []interface{}{"foobarbar", "bar"}, int64(4)},
+		{builtinInstr, []interface{}{"xbar", "foobar"}, int64(0)},
+		{builtinSubstringIndex, []interface{}{"www.mysql.com", ".", int64(2)}, "www.mysql"},
+		{builtinSubstringIndex, []interface{}{"www.mysql.com", ".", int64(-2)}, "mysql.com"},
+		{builtinSubstringIndex, []interface{}{"www.mysql.com", ".", int64(5)}, "www.mysql.com"},
+		{builtinSubstringIndex, []interface{}{"www.mysql.com", ".", int64(0)}, ""},
+		{builtinField, []interface{}{"Bb", "Aa", "Bb", "Cc", "Dd", "Ff"}, int64(2)},
+		{builtinField, []interface{}{"Gg", "Aa", "Bb"}, int64(0)},
+		{builtinField, []interface{}{int64(2), "1", "2.0"}, int64(2)},
+		{builtinField, []interface{}{nil, "a", nil}, int64(0)},
+		{builtinFindInSet, []interface{}{"b", "a,b,c,d"}, int64(2)},
+		{builtinFindInSet, []interface{}{"a,b", "a,b,c,d"}, int64(0)},
+		{builtinFindInSet, []interface{}{"e", ""}, int64(0)},
+		{builtinFindInSet, []interface{}{nil, "a"}, nil},
+		{builtinElt, []interface{}{int64(1), "Aa", "Bb"}, "Aa"},
+		{builtinElt, []interface{}{int64(3), "Aa", "Bb"}, nil},
+		{builtinElt, []interface{}{int64(2), "Aa", int64(5)}, "5"},
+		{builtinHex, []interface{}{"abc"}, "616263"},
+		{builtinHex, []interface{}{int64(255)}, "FF"},
+		{builtinHex, []interface{}{int64(-1)}, "FFFFFFFFFFFFFFFF"},
+		{builtinHex, []interface{}{1.5}, "2"},
+		{builtinUnHex, []interface{}{"4D7953514C"}, []byte("MySQL")},
+		{builtinUnHex, []interface{}{"GG"}, nil},
+		{builtinUnHex, []interface{}{"F"}, []byte{0x0f}},
+		{builtinASCII, []interface{}{"2"}, int64(50)},
+		{builtinASCII, []interface{}{""}, int64(0)},
+		{builtinASCII, []interface{}{int64(2)}, int64(50)},
+		{builtinOrd, []interface{}{"2"}, int64(50)},
+		{builtinOrd, []interface{}{"é"}, int64(0xc3a9)},
+		{builtinOrd, []interface{}{[]byte("é")}, int64(0xc3)},
+		{builtinSpace, []interface{}{int64(3)}, "   "},
+		{builtinSpace, []interface{}{int64(-3)}, ""},
+		{builtinStrcmp, []interface{}{"text", "text2"}, int64(-1)},
+		{builtinStrcmp, []interface{}{"text2", "text"}, int64(1)},
+		{builtinStrcmp, []interface{}{"text", "text"}, int64(0)},
+		{builtinStrcmp, []interface{}{nil, "text"}, nil},
+		{builtinFormat, []interface{}{12332.123456, int64(4)}, "12,332.1235"},
+		{builtinFormat, []interface{}{12332.1, int64(4)}, "12,332.1000"},
+		{builtinFormat, []interface{}{12332.2, int64(0)}, "12,332"},
+		{builtinFormat, []interface{}{"-1234567.891", int64(2)}, "-1,234,567.89"},
+		{builtinFormat, []interface{}{int64(100), int64(-1)}, "100"},
+		{builtinInsert, []interface{}{"Quadratic", int64(3), int64(4), "What"}, "QuWhattic"},
+		{builtinInsert, []interface{}{"Quadratic", int64(-1), int64(4), "What"}, "Quadratic"},
+		{builtinInsert, []interface{}{"Quadratic", int64(3), int64(100), "What"}, "QuWhat"},
+		{builtinInsert, []interface{}{"日本語", int64(2), int64(1), "x"}, "日x語"},
+		{builtinInsert, []interface{}{"Quadratic", nil, int64(4), "What"}, nil},
+	}
+
+	for _, t := range tbl {
+		v, err := t.F(t.Args, nil)
+		c.Assert(err, IsNil, Commentf("%v", t.Args))
+		c.Assert(v, DeepEquals, t.Expect, Commentf("%v", t.Args))
+	}
+}
@@ -14,10 +14,14 @@
 package expressions
 
 import (
+	"math"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/context"
 	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
 	"github.com/Dong-Chan/alloydb/util/types"
 )
 
@@ -137,7 +141,24 @@ func builtinMonth(args []interface{}, ctx map[interface{}]interface{}) (interfac
 	return int64(t.Month()), nil
 }
 
+// See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_now
 func builtinNow(args []interface{}, ctx map[interface{}]interface{}) (interface{}, error) {
+	return currentTime(args, getTimeZone(ctx))
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_utc-timestamp
+func builtinUTCTimestamp(args []interface{}, ctx map[interface{}]interface{}) (interface{}, error) {
+	return currentTime(args, time.UTC)
+}
+
+// getTimeZone gets the time zone of the session evaluating a builtin function.
+func getTimeZone(data map[interface{}]interface{}) *time.Location {
+	ctx, _ := data[ExprEvalArgCtx].(context.Context)
+	return variable.GetTimeZone(ctx)
+}
+
+// currentTime returns the current time in loc, args may have the fractional seconds precision.
+func currentTime(args []interface{}, loc *time.Location) (interface{}, error) {
 	fsp := int64(0)
 	if len(args) == 1 {
 		var err error
@@ -153,7 +174,7 @@ func builtinNow(args []interface{}, ctx map[interface{}]interface{}) (interface{
 	}
 
 	t := mysql.Time{
-		Time: time.Now(),
+		Time: time.Now().In(loc),
 		Type: mysql.TypeDatetime,
 		// set unspecified for later round
 		Fsp: mysql.UnspecifiedFsp,
@@ -293,3 +314,322 @@ func builtinYearWeek(args []interface{}, ctx map[interface{}]interface{}) (inter
 	year, week := t.ISOWeek()
 	return int64(year*100 + week), nil
 }
+
+// See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_convert-tz
+func builtinConvertTz(args []interface{}, ctx map[interface{}]interface{}) (interface{}, error) {
+	for _, arg := range args {
+		if arg == nil {
+			return nil, nil
+		}
+	}
+
+	// Like MySQL, the invalid arguments return NULL.
+	v, err := convertToTime(args[0], mysql.TypeDatetime)
+	if err != nil || v == nil {
+		return nil, nil
+	}
+	t := v.(mysql.Time)
+	if x, ok := args[0].(mysql.Time); ok {
+		t.Fsp = x.Fsp
+	} else if t.Time.Nanosecond() == 0 {
+		t.Fsp = mysql.DefaultFsp
+	}
+
+	var locs [2]*time.Location
+	for i, arg := range args[1:] {
+		s, err := types.ToString(arg)
+		if err != nil {
+			return nil, nil
+		}
+		if locs[i], err = mysql.ParseTimeZone(s); err != nil {
+			return nil, nil
+		}
+	}
+
+	if t.IsZero() {
+		return t, nil
+	}
+	t = t.AtLocation(locs[0])
+	t.Time = t.Time.In(locs[1])
+	return t, nil
+}
+
+// convertToTimeArg converts the argument of a date function to a time. Like MySQL, a string or a number
+// without the time part is a date, otherwise it is a datetime with the fractional seconds precision of it.
+func convertToTimeArg(arg interface{}) (mysql.Time, error) {
+	switch x := arg.(type) {
+	case mysql.Time:
+		return x, nil
+	case mysql.Duration:
+		return x.ConvertToTime(mysql.TypeDatetime)
+	case string:
+		s := strings.TrimSpace(x)
+		if len(s) <= 10 && !strings.ContainsAny(s, ": ") {
+			return mysql.ParseDate(s)
+		}
+		return mysql.ParseTime(s, mysql.TypeDatetime, fracDigits(s))
+	case int64, uint64, int:
+		n, err := types.ToInt64(x)
+		if err != nil {
+			return mysql.Time{}, errors.Trace(err)
+		}
+		// YYYYMMDD or YYMMDD is a date.
+		if n < 100000000 {
+			return mysql.ParseDateFromNum(n)
+		}
+		return mysql.ParseDatetimeFromNum(n)
+	}
+
+	v, err := convertToTime(arg, mysql.TypeDatetime)
+	if err != nil {
+		return mysql.Time{}, errors.Trace(err)
+	}
+	return v.(mysql.Time), nil
+}
+
+// fracDigits returns the number of the fractional seconds digits of a datetime string.
+func fracDigits(s string) int {
+	i := strings.LastIndex(s, ".")
+	if i < 0 || !strings.Contains(s, ":") || i < strings.LastIndex(s, ":") {
+		return mysql.DefaultFsp
+	}
+	n := len(s) - i - 1
+	if n > mysql.MaxFsp {
+		n = mysql.MaxFsp
+	}
+	return n
+}
+
+// timeArgs converts the arguments of a date function to times, any invalid one is NULL with a warning.
+func timeArgs(args []interface{}, data map[interface{}]interface{}) ([]mysql.Time, bool) {
+	ts := make([]mysql.Time, len(args))
+	for i, arg := range args {
+		if arg == nil {
+			return nil, false
+		}
+		t, err := convertToTimeArg(arg)
+		if err != nil || t.IsZero() {
+			ctx, _ := data[ExprEvalArgCtx].(context.Context)
+			variable.AppendWarning(ctx, mysql.NewDefaultError(mysql.ErTruncatedWrongValue, "datetime", arg))
+			return nil, false
+		}
+		ts[i] = t
+	}
+	return ts, true
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_datediff
+func builtinDateDiff(args []interface{}, ctx map[interface{}]interface{}) (interface{}, error) {
+	ts, ok := timeArgs(args, ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	// Only the date parts are used.
+	t1, t2 := ts[0].AtLocation(time.UTC).Time, ts[1].AtLocation(time.UTC).Time
+	t1 = time.Date(t1.Year(), t1.Month(), t1.Day(), 0, 0, 0, 0, time.UTC)
+	t2 = time.Date(t2.Year(), t2.Month(), t2.Day(), 0, 0, 0, 0, time.UTC)
+	return int64(t1.Sub(t2) / (24 * time.Hour)), nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_timestampdiff
+func builtinTimestampDiff(args []interface{}, ctx map[interface{}]interface{}) (interface{}, error) {
+	unit, ok := args[0].(string)
+	if !ok {
+		return nil, errors.Errorf("invalid time unit %v", args[0])
+	}
+	ts, ok := timeArgs(args[1:], ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	v, err := mysql.TimestampDiff(unit, ts[0], ts[1])
+	return v, errors.Trace(err)
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_timestampadd
+func builtinTimestampAdd(args []interface{}, ctx map[interface{}]interface{}) (interface{}, error) {
+	unit, ok := args[0].(string)
+	if !ok {
+		return nil, errors.Errorf("invalid time unit %v", args[0])
+	}
+	if !mysql.IsSimpleTimeUnit(unit) {
+		return nil, errors.Errorf("invalid time unit %s for TIMESTAMPADD", unit)
+	}
+	if args[1] == nil {
+		return nil, nil
+	}
+	ts, ok := timeArgs(args[2:], ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	s, err := types.ToString(args[1])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	iv, err := mysql.ParseInterval(s, unit)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	v, err := ts[0].AddInterval(iv)
+	if err != nil {
+		c, _ := ctx[ExprEvalArgCtx].(context.Context)
+		variable.AppendWarning(c, mysql.NewDefaultError(mysql.ErDatetimeFunctionOverflow, "datetime"))
+		return nil, nil
+	}
+	return v, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-format
+func builtinDateFormat(args []interface{}, ctx map[interface{}]interface{}) (interface{}, error) {
+	if args[1] == nil {
+		return nil, nil
+	}
+	ts, ok := timeArgs(args[:1], ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	layout, err := types.ToString(args[1])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ts[0].DateFormat(layout), nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_str-to-date
+func builtinStrToDate(args []interface{}, ctx map[interface{}]interface{}) (interface{}, error) {
+	if args[0] == nil || args[1] == nil {
+		return nil, nil
+	}
+	str, err := types.ToString(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	layout, err := types.ToString(args[1])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	v, err := mysql.StrToDate(str, layout)
+	if err != nil {
+		c, _ := ctx[ExprEvalArgCtx].(context.Context)
+		variable.AppendWarning(c, mysql.NewDefaultError(mysql.ErWrongValueForType, "datetime", str, "str_to_date"))
+		return nil, nil
+	}
+	return v, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_from-unixtime
+func builtinFromUnixTime(args []interface{}, ctx map[interface{}]interface{}) (interface{}, error) {
+	for _, arg := range args {
+		if arg == nil {
+			return nil, nil
+		}
+	}
+
+	d, err := types.ToDecimal(args[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Like MySQL, the timestamp out of the range of TIMESTAMP is NULL.
+	if d.Cmp(mysql.NewDecimalFromInt(0, 0)) < 0 || d.Cmp(mysql.NewDecimalFromInt(math.MaxInt32, 0)) > 0 {
+		return nil, nil
+	}
+
+	fsp := mysql.DefaultFsp
+	if s := d.String(); strings.Contains(s, ".") {
+		fsp = len(s) - strings.Index(s, ".") - 1
+		if fsp > mysql.MaxFsp {
+			fsp = mysql.MaxFsp
+		}
+	}
+	micro := d.Mul(mysql.NewDecimalFromInt(1000000, 0)).Round(0).IntPart()
+	t := mysql.Time{
+		Time: time.Unix(micro/1000000, micro%1000000*1000).In(getTimeZone(ctx)),
+		Type: mysql.TypeDatetime,
+		Fsp:  mysql.UnspecifiedFsp,
+	}
+	t, err = t.RoundFrac(fsp)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	if len(args) == 1 {
+		return t, nil
+	}
+	layout, err := types.ToString(args[1])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return t.DateFormat(layout), nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_unix-timestamp
+func builtinUnixTimestamp(args []interface{}, ctx map[interface{}]interface{}) (interface{}, error) {
+	if len(args) == 0 {
+		return time.Now().Unix(), nil
+	}
+	ts, ok := timeArgs(args, ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	// The datetime is in the time zone of the session, a timestamp is an instant already.
+	t := ts[0]
+	if t.Type != mysql.TypeTimestamp {
+		t = t.AtLocation(getTimeZone(ctx))
+	}
+	// Like MySQL, the time out of the range of TIMESTAMP is 0.
+	sec := t.Unix()
+	if sec < 0 || sec > math.MaxInt32 {
+		return int64(0), nil
+	}
+	if t.Type == mysql.TypeDate || t.Fsp <= 0 {
+		return sec, nil
+	}
+
+	frac := int64(t.Nanosecond()) / int64(math.Pow10(9-t.Fsp))
+	return mysql.NewDecimalFromInt(sec*int64(math.Pow10(t.Fsp))+frac, -int32(t.Fsp)), nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_last-day
+func builtinLastDay(args []interface{}, ctx map[interface{}]interface{}) (interface{}, error) {
+	ts, ok := timeArgs(args, ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	t := ts[0].Time
+	return mysql.Time{
+		Time: time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()),
+		Type: mysql.TypeDate,
+		Fsp:  mysql.DefaultFsp,
+	}, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_curdate
+func builtinCurrentDate(args []interface{}, ctx map[interface{}]interface{}) (interface{}, error) {
+	t := time.Now().In(getTimeZone(ctx))
+	return mysql.Time{
+		Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()),
+		Type: mysql.TypeDate,
+		Fsp:  mysql.DefaultFsp,
+	}, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_curtime
+func builtinCurrentTime(args []interface{}, ctx map[interface{}]interface{}) (interface{}, error) {
+	v, err := currentTime(args, getTimeZone(ctx))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return v.(mysql.Time).ConvertToDuration()
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_sysdate
+func builtinSysDate(args []interface{}, ctx map[interface{}]interface{}) (interface{}, error) {
+	// SYSDATE is the time at which it executes, the same as NOW here.
+	return builtinNow(args, ctx)
+}
@@ -15,9 +15,12 @@ package expressions
 
 import (
 	"strings"
+	"time"
 
 	. "github.com/pingcap/check"
 	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/util/mock"
 )
 
 func (s *testBuiltinSuite) TestDate(c *C) {
@@ -246,4 +249,200 @@ func (s *testBuiltinSuite) TestNow(c *C) {
 
 	_, err = builtinNow([]interface{}{-2}, nil)
 	c.Assert(err, NotNil)
+
+	// now is in the time zone of the session, utc_timestamp is in UTC.
+	ctx := mock.NewContext()
+	variable.BindSessionVars(ctx)
+	loc, err := mysql.ParseTimeZone("+14:00")
+	c.Assert(err, IsNil)
+	variable.GetSessionVars(ctx).SetTimeZone("+14:00", loc)
+	data := map[interface{}]interface{}{ExprEvalArgCtx: ctx}
+
+	v, err = builtinNow(nil, data)
+	c.Assert(err, IsNil)
+	_, offset := v.(mysql.Time).Zone()
+	c.Assert(offset, Equals, 14*3600)
+
+	v, err = builtinUTCTimestamp(nil, data)
+	c.Assert(err, IsNil)
+	c.Assert(v.(mysql.Time).Location(), Equals, time.UTC)
+}
+
+func (s *testBuiltinSuite) TestConvertTz(c *C) {
+	tbl := []struct {
+		Input  []interface{}
+		Expect interface{}
+	}{
+		{[]interface{}{"2004-01-01 12:00:00", "+00:00", "+10:00"}, "2004-01-01 22:00:00"},
+		{[]interface{}{"2004-01-01 12:00:00.5", "+10:00", "-05:30"}, "2003-12-31 20:30:00.500000"},
+		{[]interface{}{"2004-01-01 12:00:00", "UTC", "Asia/Shanghai"}, "2004-01-01 20:00:00"},
+		{[]interface{}{"2004-01-01 12:00:00", "+00:00", "Not/A_Zone"}, nil},
+		{[]interface{}{"not a time", "+00:00", "+10:00"}, nil},
+		{[]interface{}{nil, "+00:00", "+10:00"}, nil},
+		{[]interface{}{"2004-01-01 12:00:00", nil, "+10:00"}, nil},
+	}
+
+	for _, t := range tbl {
+		v, err := builtinConvertTz(t.Input, nil)
+		c.Assert(err, IsNil)
+		if t.Expect == nil {
+			c.Assert(v, IsNil, Commentf("%v", t.Input))
+			continue
+		}
+		c.Assert(v.(mysql.Time).String(), Equals, t.Expect, Commentf("%v", t.Input))
+	}
+}
+
+func (s *testBuiltinSuite) TestDateDiff(c *C) {
+	tbl := []struct {
+		Input  []interface{}
+		Expect interface{}
+	}{
+		{[]interface{}{"2007-12-31 23:59:59", "2007-12-30"}, int64(1)},
+		{[]interface{}{"2010-11-30 23:59:59", "2010-12-31"}, int64(-31)},
+		{[]interface{}{int64(20100101), "2009-01-01"}, int64(365)},
+		{[]interface{}{"2010-02-30", "2010-01-01"}, nil},
+		{[]interface{}{nil, "2010-01-01"}, nil},
+	}
+	for _, t := range tbl {
+		v, err := builtinDateDiff(t.Input, nil)
+		c.Assert(err, IsNil)
+		c.Assert(v, DeepEquals, t.Expect, Commentf("%v", t.Input))
+	}
+}
+
+func (s *testBuiltinSuite) TestTimestampDiffAndAdd(c *C) {
+	v, err := builtinTimestampDiff([]interface{}{"MONTH", "2003-02-01", "2003-05-01"}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, int64(3))
+
+	v, err = builtinTimestampDiff([]interface{}{"MINUTE", "2003-02-01", "2003-05-01 12:05:55"}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, int64(128885))
+
+	v, err = builtinTimestampDiff([]interface{}{"DAY", nil, "2003-05-01"}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v, IsNil)
+
+	v, err = builtinTimestampAdd([]interface{}{"MINUTE", 1, "2003-01-02"}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v.(mysql.Time).String(), Equals, "2003-01-02 00:01:00")
+
+	v, err = builtinTimestampAdd([]interface{}{"WEEK", 1, "2003-01-02"}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v.(mysql.Time).String(), Equals, "2003-01-09")
+
+	_, err = builtinTimestampAdd([]interface{}{"DAY_HOUR", 1, "2003-01-02"}, nil)
+	c.Assert(err, NotNil)
+}
+
+func (s *testBuiltinSuite) TestDateFormat(c *C) {
+	v, err := builtinDateFormat([]interface{}{"2009-10-04 22:23:00", "%W %M %Y %H:%i:%s"}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, "Sunday October 2009 22:23:00")
+
+	v, err = builtinDateFormat([]interface{}{"1999-01-01", "%X %V"}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, "1998 52")
+
+	v, err = builtinDateFormat([]interface{}{nil, "%Y"}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v, IsNil)
+}
+
+func (s *testBuiltinSuite) TestStrToDate(c *C) {
+	v, err := builtinStrToDate([]interface{}{"01,5,2013", "%d,%m,%Y"}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v.(mysql.Time).String(), Equals, "2013-05-01")
+
+	v, err = builtinStrToDate([]interface{}{"09:30:17 PM", "%r"}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v.(mysql.Duration).String(), Equals, "21:30:17")
+
+	v, err = builtinStrToDate([]interface{}{"2013-02-30", "%Y-%m-%d"}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v, IsNil)
+}
+
+func (s *testBuiltinSuite) TestUnixTime(c *C) {
+	ctx := mock.NewContext()
+	variable.BindSessionVars(ctx)
+	loc, err := mysql.ParseTimeZone("+08:00")
+	c.Assert(err, IsNil)
+	variable.GetSessionVars(ctx).SetTimeZone("+08:00", loc)
+	data := map[interface{}]interface{}{ExprEvalArgCtx: ctx}
+
+	v, err := builtinFromUnixTime([]interface{}{int64(1447430881)}, data)
+	c.Assert(err, IsNil)
+	c.Assert(v.(mysql.Time).String(), Equals, "2015-11-14 00:08:01")
+
+	v, err = builtinFromUnixTime([]interface{}{"1447430881.5"}, data)
+	c.Assert(err, IsNil)
+	c.Assert(v.(mysql.Time).String(), Equals, "2015-11-14 00:08:01.5")
+
+	v, err = builtinFromUnixTime([]interface{}{int64(1447430881), "%Y %D %M %h:%i:%s %x"}, data)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, "2015 14th November 12:08:01 2015")
+
+	v, err = builtinFromUnixTime([]interface{}{int64(-1)}, data)
+	c.Assert(err, IsNil)
+	c.Assert(v, IsNil)
+
+	v, err = builtinUnixTimestamp([]interface{}{"2015-11-14 00:08:01"}, data)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, int64(1447430881))
+
+	v, err = builtinUnixTimestamp([]interface{}{"2015-11-14 00:08:01.125"}, data)
+	c.Assert(err, IsNil)
+	c.Assert(v.(mysql.Decimal).String(), Equals, "1447430881.125")
+
+	v, err = builtinUnixTimestamp([]interface{}{"1969-12-31"}, data)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, int64(0))
+
+	v, err = builtinUnixTimestamp(nil, data)
+	c.Assert(err, IsNil)
+	c.Assert(v.(int64), Greater, int64(0))
+}
+
+func (s *testBuiltinSuite) TestLastDay(c *C) {
+	tbl := []struct {
+		Input  interface{}
+		Expect interface{}
+	}{
+		{"2003-02-05", "2003-02-28"},
+		{"2004-02-05", "2004-02-29"},
+		{"2004-01-01 01:01:01", "2004-01-31"},
+		{"2003-03-32", nil},
+		{nil, nil},
+	}
+	for _, t := range tbl {
+		v, err := builtinLastDay([]interface{}{t.Input}, nil)
+		c.Assert(err, IsNil)
+		if t.Expect == nil {
+			c.Assert(v, IsNil)
+			continue
+		}
+		c.Assert(v.(mysql.Time).String(), Equals, t.Expect)
+	}
+}
+
+func (s *testBuiltinSuite) TestCurrentDateAndTime(c *C) {
+	v, err := builtinCurrentDate(nil, nil)
+	c.Assert(err, IsNil)
+	t := v.(mysql.Time)
+	c.Assert(t.Type, Equals, mysql.TypeDate)
+	c.Assert(t.Hour(), Equals, 0)
+
+	v, err = builtinCurrentTime(nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(v.(mysql.Duration).String(), "."), IsFalse)
+
+	v, err = builtinCurrentTime([]interface{}{3}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v.(mysql.Duration).Fsp, Equals, 3)
+
+	v, err = builtinSysDate(nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v.(mysql.Time).Type, Equals, mysql.TypeDatetime)
 }
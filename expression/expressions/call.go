@@ -15,6 +15,7 @@ package expressions
 
 import (
 	"fmt"
+	"math/rand"
 	"strings"
 
 	"github.com/juju/errors"
@@ -35,6 +36,9 @@ type Call struct {
 	// Distinct only affetcts sum, avg, count, group_concat,
 	// so we can ignore it in other functions
 	Distinct bool
+	// rand is the random number generator of RAND(N) with a constant seed,
+	// it is created at the first evaluation.
+	rand *rand.Rand
 }
 
 // NewCall creates a Call expression with function name f, function args arg and
@@ -19,6 +19,7 @@ import (
 	"github.com/Dong-Chan/alloydb/context"
 	"github.com/Dong-Chan/alloydb/expression"
 	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
 	"github.com/Dong-Chan/alloydb/util/types"
 )
 
@@ -51,17 +52,17 @@ func (f *FunctionCast) IsStatic() bool {
 
 // String implements the Expression String interface.
 func (f *FunctionCast) String() string {
-	tpStr := ""
+	return fmt.Sprintf("CAST(%s AS %s)", f.Expr.String(), f.typeString())
+}
+
+func (f *FunctionCast) typeString() string {
 	if f.Tp.Tp == mysql.TypeLonglong {
 		if mysql.HasUnsignedFlag(f.Tp.Flag) {
-			tpStr = "UNSIGNED"
-		} else {
-			tpStr = "SIGNED"
+			return "UNSIGNED"
 		}
-	} else {
-		tpStr = f.Tp.String()
+		return "SIGNED"
 	}
-	return fmt.Sprintf("CAST(%s AS %s)", f.Expr.String(), tpStr)
+	return f.Tp.String()
 }
 
 // Eval implements the Expression Eval interface.
@@ -77,7 +78,29 @@ func (f *FunctionCast) Eval(ctx context.Context, args map[interface{}]interface{
 	}
 	nv, err := types.Convert(value, f.Tp)
 	if err != nil {
-		return nil, err
+		// Like MySQL, the value which can't be converted is NULL with a warning.
+		variable.AppendWarning(ctx, mysql.NewDefaultError(mysql.ErTruncatedWrongValue, f.typeString(), value))
+		return nil, nil
+	}
+
+	if isTruncated(value, nv) {
+		variable.AppendWarning(ctx, mysql.NewDefaultError(mysql.ErTruncatedWrongValue, f.typeString(), value))
 	}
 	return nv, nil
 }
+
+// isTruncated checks whether the string value is truncated to the converted value.
+func isTruncated(value, converted interface{}) bool {
+	var n int
+	switch x := converted.(type) {
+	case string:
+		n = len(x)
+	case []byte:
+		n = len(x)
+	default:
+		return false
+	}
+
+	s, err := types.ToString(value)
+	return err == nil && len(s) > n
+}
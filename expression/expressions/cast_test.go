@@ -18,7 +18,9 @@ import (
 
 	. "github.com/pingcap/check"
 	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
 	"github.com/Dong-Chan/alloydb/util/charset"
+	"github.com/Dong-Chan/alloydb/util/mock"
 	"github.com/Dong-Chan/alloydb/util/types"
 )
 
@@ -75,3 +77,36 @@ func (s *testCastSuite) TestCast(c *C) {
 	_, err = expr.Eval(nil, nil)
 	c.Assert(err, NotNil)
 }
+
+func (s *testCastSuite) TestCastWarnings(c *C) {
+	ctx := mock.NewContext()
+	variable.BindSessionVars(ctx)
+	sessionVars := variable.GetSessionVars(ctx)
+
+	f := types.NewFieldType(mysql.TypeDatetime)
+	expr := &FunctionCast{
+		Expr: Value{"abc"},
+		Tp:   f,
+	}
+
+	// The value which can't be converted is NULL with a warning.
+	v, err := expr.Eval(ctx, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v, IsNil)
+	c.Assert(sessionVars.Warnings(), HasLen, 1)
+	c.Assert(sessionVars.Warnings()[0].Code, Equals, uint16(mysql.ErTruncatedWrongValue))
+
+	// Truncating a string generates a warning.
+	f.Tp = mysql.TypeString
+	f.Flen = 2
+	v, err = expr.Eval(ctx, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, "ab")
+	c.Assert(sessionVars.Warnings(), HasLen, 2)
+	c.Assert(sessionVars.Warnings()[1].Message, Equals, "Truncated incorrect CHAR (2) value: 'abc'")
+
+	f.Flen = 3
+	_, err = expr.Eval(ctx, nil)
+	c.Assert(err, IsNil)
+	c.Assert(sessionVars.Warnings(), HasLen, 2)
+}
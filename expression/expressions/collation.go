@@ -0,0 +1,91 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/util/charset"
+)
+
+// Collation returns the collation of the expression e, which is the collation of the column
+// for an identifier, or "" if the collation is unknown.
+// The collation of an identifier is retrieved with the function saved by ExprEvalCollationFunc in args.
+// TODO: use collation_connection for the string literals.
+func Collation(e expression.Expression, args map[interface{}]interface{}) string {
+	switch x := e.(type) {
+	case *Ident:
+		f, ok := args[ExprEvalCollationFunc].(func(string) string)
+		if !ok {
+			return ""
+		}
+		return f(x.L)
+	case *PExpr:
+		return Collation(x.Expr, args)
+	case collatedValue:
+		return x.collation
+	case *Call:
+		// The string functions like UPPER and CONCAT return the collation of the arguments.
+		for _, arg := range x.Args {
+			if c := Collation(arg, args); c != "" {
+				return c
+			}
+		}
+	case *FunctionTrim:
+		return Collation(x.Str, args)
+	case *FunctionSubstring:
+		return Collation(x.StrExpr, args)
+	}
+	return ""
+}
+
+// SortKey returns the value to sort or group rows by the expression e with value v,
+// a string is converted to the sort key of the collation of e.
+func SortKey(e expression.Expression, args map[interface{}]interface{}, v interface{}) interface{} {
+	str, ok := v.(string)
+	if !ok {
+		return v
+	}
+	c := Collation(e, args)
+	if c == "" {
+		return v
+	}
+	return charset.GetCollator(c).Key(str)
+}
+
+// collatedValue is a value evaluated from an expression with a collation,
+// it is used to compare the value again with the collation, like IN and BETWEEN.
+type collatedValue struct {
+	Value
+	collation string
+}
+
+// newCollatedValue returns a collatedValue for v evaluated from e,
+// or a Value if the collation of e is unknown.
+func newCollatedValue(v interface{}, e expression.Expression, args map[interface{}]interface{}) expression.Expression {
+	c := Collation(e, args)
+	if c == "" {
+		return Value{v}
+	}
+	return collatedValue{Value: Value{v}, collation: c}
+}
+
+// CompareCollation returns the collation to compare the operands a and b,
+// the collation of the left operand is used if both have collations.
+// TODO: check the coercibility of the collations.
+func CompareCollation(a, b expression.Expression, args map[interface{}]interface{}) string {
+	if c := Collation(a, args); c != "" {
+		return c
+	}
+	return Collation(b, args)
+}
@@ -0,0 +1,75 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/model"
+	"github.com/Dong-Chan/alloydb/parser/opcode"
+	"github.com/Dong-Chan/alloydb/util/types"
+)
+
+var _ = Suite(&testCollationSuite{})
+
+type testCollationSuite struct {
+}
+
+func (s *testCollationSuite) TestCollation(c *C) {
+	collations := map[string]string{"ci": "utf8_general_ci", "cs": "utf8_bin"}
+	values := map[string]interface{}{"ci": "abc", "cs": "abc"}
+	m := map[interface{}]interface{}{
+		ExprEvalIdentFunc: func(name string) (interface{}, error) {
+			return values[name], nil
+		},
+		ExprEvalCollationFunc: func(name string) string {
+			return collations[name]
+		},
+	}
+	ci := &Ident{model.NewCIStr("ci")}
+	cs := &Ident{model.NewCIStr("cs")}
+
+	c.Assert(Collation(ci, m), Equals, "utf8_general_ci")
+	c.Assert(Collation(&PExpr{Expr: cs}, m), Equals, "utf8_bin")
+	c.Assert(Collation(&Call{F: "upper", Args: []expression.Expression{ci}}, m), Equals, "utf8_general_ci")
+	c.Assert(Collation(Value{"abc"}, m), Equals, "")
+	c.Assert(Collation(ci, nil), Equals, "")
+
+	c.Assert(SortKey(ci, m, "ABC"), Equals, SortKey(ci, m, "abc"))
+	c.Assert(SortKey(cs, m, "ABC"), Not(Equals), SortKey(cs, m, "abc"))
+	c.Assert(SortKey(Value{"abc"}, m, "abc"), Equals, "abc")
+	c.Assert(SortKey(ci, m, int64(1)), Equals, int64(1))
+
+	table := []struct {
+		Expr   expression.Expression
+		Result bool
+	}{
+		{NewBinaryOperation(opcode.EQ, ci, Value{"ABC"}), true},
+		{NewBinaryOperation(opcode.EQ, Value{"ABC"}, ci), true},
+		{NewBinaryOperation(opcode.EQ, cs, Value{"ABC"}), false},
+		{NewBinaryOperation(opcode.LT, ci, Value{"ABD"}), true},
+		{NewBinaryOperation(opcode.EQ, ci, cs), true},
+		{&PatternIn{Expr: ci, List: []expression.Expression{Value{"x"}, Value{"ABC"}}}, true},
+		{&PatternIn{Expr: cs, List: []expression.Expression{Value{"x"}, Value{"ABC"}}}, false},
+		{&Between{Expr: ci, Left: Value{"ABA"}, Right: Value{"ABD"}}, true},
+		{&Between{Expr: cs, Left: Value{"ABA"}, Right: Value{"ABD"}}, false},
+	}
+	for _, t := range table {
+		v, err := t.Expr.Eval(nil, m)
+		c.Assert(err, IsNil)
+		b, err := types.ToBool(v)
+		c.Assert(err, IsNil)
+		c.Assert(b == 1, Equals, t.Result, Commentf("%s", t.Expr))
+	}
+}
@@ -0,0 +1,122 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/parser/opcode"
+	"github.com/Dong-Chan/alloydb/util/types"
+)
+
+var (
+	_ expression.Expression = (*CompareSubQuery)(nil)
+)
+
+// CompareSubQuery is the expression for "expr cmp ANY (select ...)" or "expr cmp ALL (select ...)",
+// SOME is an alias for ANY.
+// See: https://dev.mysql.com/doc/refman/5.7/en/any-in-some-subqueries.html
+type CompareSubQuery struct {
+	// L is the left expression to be compared.
+	L expression.Expression
+	// Op is the comparison opcode.
+	Op opcode.Op
+	// R is the sub query for the right expressions.
+	R *SubQuery
+	// All is true, the expression is "expr cmp ALL (select ...)".
+	All bool
+}
+
+// Clone implements the Expression Clone interface.
+func (cs *CompareSubQuery) Clone() (expression.Expression, error) {
+	l, err := cs.L.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := cs.R.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	return &CompareSubQuery{L: l, Op: cs.Op, R: r.(*SubQuery), All: cs.All}, nil
+}
+
+// IsStatic implements the Expression IsStatic interface, always returns false.
+func (cs *CompareSubQuery) IsStatic() bool {
+	return false
+}
+
+// String implements the Expression String interface.
+func (cs *CompareSubQuery) String() string {
+	anyOrAll := "ANY"
+	if cs.All {
+		anyOrAll = "ALL"
+	}
+
+	return fmt.Sprintf("%s %s %s %s", cs.L, cs.Op, anyOrAll, cs.R)
+}
+
+// Eval implements the Expression Eval interface.
+// For ALL, the result is true if the comparisons with all the rows are true or there is no row,
+// for ANY, the result is true if the comparison with any row is true.
+// Otherwise, the result is NULL if any comparison is NULL.
+func (cs *CompareSubQuery) Eval(ctx context.Context, args map[interface{}]interface{}) (v interface{}, err error) {
+	lv, err := cs.L.Eval(ctx, args)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var rows [][]interface{}
+	if cs.R.Value != nil {
+		rows = [][]interface{}{{cs.R.Value}}
+	} else if rows, err = cs.R.EvalRows(ctx, args, -1, 1); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	hasNull := false
+	l := newCollatedValue(lv, cs.L, args)
+	for _, row := range rows {
+		b := &BinaryOperation{Op: cs.Op, L: l, R: Value{row[0]}}
+		v, err := b.Eval(ctx, args)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+
+		if v == nil {
+			hasNull = true
+			continue
+		}
+
+		x, err := types.ToBool(v)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+
+		if cs.All && x == 0 {
+			return false, nil
+		}
+		if !cs.All && x == 1 {
+			return true, nil
+		}
+	}
+
+	if hasNull {
+		return nil, nil
+	}
+	return cs.All, nil
+}
@@ -0,0 +1,87 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	"github.com/juju/errors"
+	. "github.com/pingcap/check"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/parser/opcode"
+)
+
+var _ = Suite(&testCompareSubQuerySuite{})
+
+type testCompareSubQuerySuite struct {
+}
+
+func (s *testCompareSubQuerySuite) TestCompareSubQuery(c *C) {
+	tbl := []struct {
+		lhs    interface{}
+		op     opcode.Op
+		all    bool
+		rows   [][]interface{}
+		result interface{}
+	}{
+		// ANY
+		{int64(1), opcode.EQ, false, [][]interface{}{{int64(1)}, {int64(2)}}, true},
+		{int64(3), opcode.EQ, false, [][]interface{}{{int64(1)}, {int64(2)}}, false},
+		{int64(3), opcode.GT, false, [][]interface{}{{int64(1)}, {int64(4)}}, true},
+		{int64(0), opcode.GT, false, [][]interface{}{{int64(1)}, {int64(4)}}, false},
+		{int64(3), opcode.EQ, false, [][]interface{}{{int64(1)}, {nil}}, nil},
+		{int64(1), opcode.EQ, false, [][]interface{}{{int64(1)}, {nil}}, true},
+		{int64(1), opcode.EQ, false, nil, false},
+		{nil, opcode.EQ, false, [][]interface{}{{int64(1)}}, nil},
+		{nil, opcode.EQ, false, nil, false},
+		// ALL
+		{int64(3), opcode.GT, true, [][]interface{}{{int64(1)}, {int64(2)}}, true},
+		{int64(2), opcode.GT, true, [][]interface{}{{int64(1)}, {int64(2)}}, false},
+		{int64(2), opcode.NE, true, [][]interface{}{{int64(1)}, {int64(3)}}, true},
+		{int64(3), opcode.GT, true, [][]interface{}{{int64(1)}, {nil}}, nil},
+		{int64(0), opcode.GT, true, [][]interface{}{{int64(1)}, {nil}}, false},
+		{int64(1), opcode.EQ, true, nil, true},
+		{nil, opcode.EQ, true, [][]interface{}{{int64(1)}}, nil},
+		{nil, opcode.EQ, true, nil, true},
+	}
+
+	for _, t := range tbl {
+		ms := newMockStatement()
+		ms.SetFieldOffset(1)
+		ms.rset.rows = t.rows
+		e := &CompareSubQuery{L: Value{t.lhs}, Op: t.op, R: &SubQuery{Stmt: ms}, All: t.all}
+
+		v, err := e.Eval(nil, nil)
+		c.Assert(err, IsNil)
+		c.Assert(v, Equals, t.result, Commentf("%v %v", e, t.rows))
+	}
+
+	ms := newMockStatement()
+	e := &CompareSubQuery{L: Value{1}, Op: opcode.EQ, R: &SubQuery{Stmt: ms}}
+	c.Assert(e.IsStatic(), IsFalse)
+
+	str := e.String()
+	c.Assert(len(str), Greater, 0)
+
+	// operand should contain 1 column
+	_, err := e.Eval(nil, nil)
+	c.Assert(err, NotNil)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErOperandColumns))
+
+	ec, err := e.Clone()
+	c.Assert(err, IsNil)
+
+	ms.SetFieldOffset(1)
+	v, err := ec.Eval(nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v, IsTrue)
+}
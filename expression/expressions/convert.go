@@ -65,14 +65,14 @@ func (f *FunctionConvert) Eval(ctx context.Context, args map[interface{}]interfa
 	if value == nil {
 		return nil, nil
 	}
-	str, ok := value.(string)
+	str, ok := stringValue(value)
 	if !ok {
 		return nil, nil
 	}
 	if strings.ToLower(f.Charset) == "ascii" {
-		return value, nil
+		return str, nil
 	} else if strings.ToLower(f.Charset) == "utf8mb4" {
-		return value, nil
+		return str, nil
 	}
 	target, err := iconv.ConvertString(str, "utf-8", f.Charset)
 	if err != nil {
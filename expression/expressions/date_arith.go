@@ -0,0 +1,113 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/expression"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/util/types"
+)
+
+// DateArithType is the type of the date arithmetic, adding or subtracting an interval.
+type DateArithType byte
+
+const (
+	// DateAdd is to add an interval to a date, like DATE_ADD and ADDDATE.
+	DateAdd DateArithType = iota + 1
+	// DateSub is to subtract an interval from a date, like DATE_SUB and SUBDATE.
+	DateSub
+)
+
+// FunctionDateArith is the date arithmetic, like DATE_ADD(date, INTERVAL expr unit) and date + INTERVAL expr unit.
+// See: https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-add
+type FunctionDateArith struct {
+	Op       DateArithType
+	Date     expression.Expression
+	Interval expression.Expression
+	// Unit is the upper case time unit of the interval, like DAY or DAY_HOUR.
+	Unit string
+}
+
+// Clone implements the Expression Clone interface.
+func (f *FunctionDateArith) Clone() (expression.Expression, error) {
+	date, err := f.Date.Clone()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	interval, err := f.Interval.Clone()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &FunctionDateArith{Op: f.Op, Date: date, Interval: interval, Unit: f.Unit}, nil
+}
+
+// IsStatic implements the Expression IsStatic interface.
+func (f *FunctionDateArith) IsStatic() bool {
+	return f.Date.IsStatic() && f.Interval.IsStatic()
+}
+
+// String implements the Expression String interface.
+func (f *FunctionDateArith) String() string {
+	name := "DATE_ADD"
+	if f.Op == DateSub {
+		name = "DATE_SUB"
+	}
+	return fmt.Sprintf("%s(%s, INTERVAL %s %s)", name, f.Date, f.Interval, f.Unit)
+}
+
+// Eval implements the Expression Eval interface.
+func (f *FunctionDateArith) Eval(ctx context.Context, args map[interface{}]interface{}) (interface{}, error) {
+	date, err := f.Date.Eval(ctx, args)
+	if err != nil || date == nil {
+		return nil, errors.Trace(err)
+	}
+	interval, err := f.Interval.Eval(ctx, args)
+	if err != nil || interval == nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Like MySQL, the invalid date or interval is NULL with a warning.
+	t, err := convertToTimeArg(date)
+	if err != nil {
+		variable.AppendWarning(ctx, mysql.NewDefaultError(mysql.ErTruncatedWrongValue, "datetime", date))
+		return nil, nil
+	}
+	s, err := types.ToString(interval)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	iv, err := mysql.ParseInterval(s, f.Unit)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if f.Op == DateSub {
+		iv = iv.Neg()
+	}
+
+	v, err := t.AddInterval(iv)
+	if err != nil {
+		if t.IsZero() {
+			variable.AppendWarning(ctx, mysql.NewDefaultError(mysql.ErTruncatedWrongValue, "datetime", date))
+		} else {
+			variable.AppendWarning(ctx, mysql.NewDefaultError(mysql.ErDatetimeFunctionOverflow, "datetime"))
+		}
+		return nil, nil
+	}
+	return v, nil
+}
@@ -0,0 +1,85 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	. "github.com/pingcap/check"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/util/mock"
+)
+
+var _ = Suite(&testDateArithSuite{})
+
+type testDateArithSuite struct {
+}
+
+func (s *testDateArithSuite) TestDateArith(c *C) {
+	tbl := []struct {
+		Op       DateArithType
+		Date     interface{}
+		Interval interface{}
+		Unit     string
+		Expect   interface{}
+	}{
+		{DateAdd, "2008-01-02", 31, "DAY", "2008-02-02"},
+		{DateAdd, "2008-01-31", "1", "MONTH", "2008-02-29"},
+		{DateAdd, "2008-01-02", 1, "HOUR", "2008-01-02 01:00:00"},
+		{DateAdd, "2008-12-31 23:59:59", "1:1", "MINUTE_SECOND", "2009-01-01 00:01:00"},
+		{DateAdd, "2008-12-31 23:59:59.1", 1, "SECOND", "2009-01-01 00:00:00.1"},
+		{DateAdd, "2008-12-31", "1.000001", "SECOND_MICROSECOND", "2008-12-31 00:00:01.000001"},
+		{DateAdd, int64(20081231), 1, "DAY", "2009-01-01"},
+		{DateSub, "2008-01-02", 31, "DAY", "2007-12-02"},
+		{DateSub, "2005-01-01 00:00:00", "1 1:1:1", "DAY_SECOND", "2004-12-30 22:58:59"},
+		{DateAdd, nil, 1, "DAY", nil},
+		{DateAdd, "2008-01-02", nil, "DAY", nil},
+		{DateAdd, "2008-02-30", 1, "DAY", nil},
+		{DateAdd, "0000-00-00", 1, "DAY", nil},
+		{DateAdd, "9999-12-31", 1, "DAY", nil},
+	}
+
+	for _, t := range tbl {
+		e := &FunctionDateArith{
+			Op:       t.Op,
+			Date:     Value{Val: t.Date},
+			Interval: Value{Val: t.Interval},
+			Unit:     t.Unit,
+		}
+		c.Assert(e.IsStatic(), IsTrue)
+
+		ec, err := e.Clone()
+		c.Assert(err, IsNil)
+		c.Assert(ec.String(), Equals, e.String())
+
+		v, err := e.Eval(nil, nil)
+		c.Assert(err, IsNil)
+		if t.Expect == nil {
+			c.Assert(v, IsNil, Commentf("%s", e))
+			continue
+		}
+		c.Assert(v.(mysql.Time).String(), Equals, t.Expect, Commentf("%s", e))
+	}
+
+	e := &FunctionDateArith{Op: DateSub, Date: Value{Val: "2008-01-02"}, Interval: Value{Val: 1}, Unit: "DAY"}
+	c.Assert(e.String(), Equals, `DATE_SUB("2008-01-02", INTERVAL 1 DAY)`)
+
+	// The invalid date is NULL with a warning.
+	ctx := mock.NewContext()
+	variable.BindSessionVars(ctx)
+	e = &FunctionDateArith{Op: DateAdd, Date: Value{Val: "2008-13-01"}, Interval: Value{Val: 1}, Unit: "DAY"}
+	v, err := e.Eval(ctx, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v, IsNil)
+	c.Assert(variable.GetSessionVars(ctx).WarningCount(), Equals, uint64(1))
+}
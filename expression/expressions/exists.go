@@ -0,0 +1,67 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/expression"
+)
+
+var (
+	_ expression.Expression = (*ExistsSubQuery)(nil)
+)
+
+// ExistsSubQuery is the expression for "exists (select ...)".
+// See: https://dev.mysql.com/doc/refman/5.7/en/exists-and-not-exists-subqueries.html
+type ExistsSubQuery struct {
+	// Sel is the sub query.
+	Sel *SubQuery
+}
+
+// Clone implements the Expression Clone interface.
+func (es *ExistsSubQuery) Clone() (expression.Expression, error) {
+	sel, err := es.Sel.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExistsSubQuery{Sel: sel.(*SubQuery)}, nil
+}
+
+// IsStatic implements the Expression IsStatic interface, always returns false.
+func (es *ExistsSubQuery) IsStatic() bool {
+	return false
+}
+
+// String implements the Expression String interface.
+func (es *ExistsSubQuery) String() string {
+	return fmt.Sprintf("EXISTS %s", es.Sel)
+}
+
+// Eval implements the Expression Eval interface.
+func (es *ExistsSubQuery) Eval(ctx context.Context, args map[interface{}]interface{}) (v interface{}, err error) {
+	if es.Sel.Value != nil {
+		return true, nil
+	}
+
+	rows, err := es.Sel.EvalRows(ctx, args, 1, 0)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return len(rows) > 0, nil
+}
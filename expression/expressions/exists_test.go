@@ -0,0 +1,52 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/Dong-Chan/alloydb/parser/opcode"
+)
+
+var _ = Suite(&testExistsSubQuerySuite{})
+
+type testExistsSubQuerySuite struct {
+}
+
+func (s *testExistsSubQuerySuite) TestExistsSubQuery(c *C) {
+	ms := newMockStatement()
+	e := &ExistsSubQuery{Sel: &SubQuery{Stmt: ms}}
+
+	c.Assert(e.IsStatic(), IsFalse)
+
+	str := e.String()
+	c.Assert(len(str), Greater, 0)
+
+	// any column number is ok
+	v, err := e.Eval(nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v, IsTrue)
+
+	ec, err := e.Clone()
+	c.Assert(err, IsNil)
+
+	ms.rset.rows = nil
+	v, err = ec.Eval(nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v, IsFalse)
+
+	// NOT EXISTS
+	v, err = NewUnaryOperation(opcode.Not, ec).Eval(nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, int8(1))
+}
@@ -0,0 +1,95 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/expression"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+)
+
+// FunctionExtract is the EXTRACT(unit FROM date) function.
+// See: https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_extract
+type FunctionExtract struct {
+	// Unit is the upper case time unit, like DAY or DAY_HOUR.
+	Unit string
+	Date expression.Expression
+}
+
+// Clone implements the Expression Clone interface.
+func (f *FunctionExtract) Clone() (expression.Expression, error) {
+	date, err := f.Date.Clone()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &FunctionExtract{Unit: f.Unit, Date: date}, nil
+}
+
+// IsStatic implements the Expression IsStatic interface.
+func (f *FunctionExtract) IsStatic() bool {
+	return f.Date.IsStatic()
+}
+
+// String implements the Expression String interface.
+func (f *FunctionExtract) String() string {
+	return fmt.Sprintf("EXTRACT(%s FROM %s)", f.Unit, f.Date)
+}
+
+// Eval implements the Expression Eval interface.
+func (f *FunctionExtract) Eval(ctx context.Context, args map[interface{}]interface{}) (interface{}, error) {
+	date, err := f.Date.Eval(ctx, args)
+	if err != nil || date == nil {
+		return nil, errors.Trace(err)
+	}
+
+	// The time parts can be extracted from a time, like EXTRACT(HOUR FROM '10:11:12').
+	if d, ok := extractDuration(date, f.Unit); ok {
+		v, err := d.ExtractTimeNum(f.Unit)
+		return v, errors.Trace(err)
+	}
+
+	t, err := convertToTimeArg(date)
+	if err != nil {
+		variable.AppendWarning(ctx, mysql.NewDefaultError(mysql.ErTruncatedWrongValue, "datetime", date))
+		return nil, nil
+	}
+	v, err := t.ExtractTimeNum(f.Unit)
+	return v, errors.Trace(err)
+}
+
+// extractDuration returns the time of a time value or a string without the date part if unit has only the time parts.
+func extractDuration(date interface{}, unit string) (mysql.Duration, bool) {
+	switch unit {
+	case "YEAR", "QUARTER", "MONTH", "WEEK", "DAY", "YEAR_MONTH",
+		"DAY_HOUR", "DAY_MINUTE", "DAY_SECOND", "DAY_MICROSECOND":
+		return mysql.Duration{}, false
+	}
+
+	switch x := date.(type) {
+	case mysql.Duration:
+		return x, true
+	case string:
+		if strings.ContainsAny(strings.TrimPrefix(x, "-"), "- ") || !strings.Contains(x, ":") {
+			return mysql.Duration{}, false
+		}
+		d, err := mysql.ParseDuration(x, mysql.MaxFsp)
+		return d, err == nil
+	}
+	return mysql.Duration{}, false
+}
@@ -0,0 +1,57 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testExtractSuite{})
+
+type testExtractSuite struct {
+}
+
+func (s *testExtractSuite) TestExtract(c *C) {
+	tbl := []struct {
+		Unit   string
+		Date   interface{}
+		Expect interface{}
+	}{
+		{"YEAR", "2009-07-02", int64(2009)},
+		{"YEAR_MONTH", "2009-07-02 01:02:03", int64(200907)},
+		{"DAY_MINUTE", "2009-07-02 01:02:03", int64(20102)},
+		{"MICROSECOND", "2003-01-02 10:30:00.000123", int64(123)},
+		{"QUARTER", int64(20090702), int64(3)},
+		{"HOUR", "10:11:12", int64(10)},
+		{"MINUTE_SECOND", "-10:11:12", int64(-1112)},
+		{"MINUTE", nil, nil},
+		{"DAY", "not a date", nil},
+	}
+
+	for _, t := range tbl {
+		e := &FunctionExtract{Unit: t.Unit, Date: Value{Val: t.Date}}
+		c.Assert(e.IsStatic(), IsTrue)
+
+		ec, err := e.Clone()
+		c.Assert(err, IsNil)
+		c.Assert(ec.String(), Equals, e.String())
+
+		v, err := e.Eval(nil, nil)
+		c.Assert(err, IsNil)
+		c.Assert(v, DeepEquals, t.Expect, Commentf("%s", e))
+	}
+
+	e := &FunctionExtract{Unit: "DAY", Date: Value{Val: "2009-07-02"}}
+	c.Assert(e.String(), Equals, `EXTRACT(DAY FROM "2009-07-02")`)
+}
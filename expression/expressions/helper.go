@@ -30,6 +30,7 @@ import (
 	mysql "github.com/Dong-Chan/alloydb/mysqldef"
 	"github.com/Dong-Chan/alloydb/parser/opcode"
 	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/stmt"
 	"github.com/Dong-Chan/alloydb/util/types"
 )
 
@@ -48,10 +49,10 @@ const (
 	ExprEvalIdentFunc = "$identFunc"
 	// ExprEvalPositionFunc is the key saving a Position expresion.
 	ExprEvalPositionFunc = "$positionFunc"
-	// ExprAggDistinct is the key saving a distinct aggregate.
-	ExprAggDistinct = "$aggDistinct"
 	// ExprEvalValuesFunc is the key saving a function to retrieve value for column name.
 	ExprEvalValuesFunc = "$valuesFunc"
+	// ExprEvalCollationFunc is the key saving a function to retrieve collation for identifier name.
+	ExprEvalCollationFunc = "$collationFunc"
 )
 
 var (
@@ -140,11 +141,13 @@ func MentionedAggregateFuncs(e expression.Expression) []expression.Expression {
 func mentionedAggregateFuncs(e expression.Expression, m *[]expression.Expression) {
 	switch x := e.(type) {
 	case Value, *Value, *Variable,
-		*Default, *Ident, *SubQuery, *Position:
+		*Default, *Ident, *SubQuery, *ExistsSubQuery, *Position:
 		// nop
 	case *BinaryOperation:
 		mentionedAggregateFuncs(x.L, m)
 		mentionedAggregateFuncs(x.R, m)
+	case *CompareSubQuery:
+		mentionedAggregateFuncs(x.L, m)
 	case *Call:
 		f, ok := builtin[strings.ToLower(x.F)]
 		if !ok {
@@ -189,6 +192,16 @@ func mentionedAggregateFuncs(e expression.Expression, m *[]expression.Expression
 		if x.Expr != nil {
 			mentionedAggregateFuncs(x.Expr, m)
 		}
+	case *FunctionTrim:
+		mentionedAggregateFuncs(x.Str, m)
+		if x.RemStr != nil {
+			mentionedAggregateFuncs(x.RemStr, m)
+		}
+	case *FunctionDateArith:
+		mentionedAggregateFuncs(x.Date, m)
+		mentionedAggregateFuncs(x.Interval, m)
+	case *FunctionExtract:
+		mentionedAggregateFuncs(x.Date, m)
 	case *FunctionSubstring:
 		if x.StrExpr != nil {
 			mentionedAggregateFuncs(x.StrExpr, m)
@@ -218,6 +231,12 @@ func mentionedAggregateFuncs(e expression.Expression, m *[]expression.Expression
 		mentionedAggregateFuncs(x.Expr, m)
 		mentionedAggregateFuncs(x.Left, m)
 		mentionedAggregateFuncs(x.Right, m)
+	case *WindowFuncExpr:
+		// the window function itself is not an aggregate function even if it is sum, count, etc,
+		// but its arguments and window may contain aggregate functions, like sum(count(*)) over ().
+		for _, e := range x.exprs() {
+			mentionedAggregateFuncs(e, m)
+		}
 	default:
 		log.Errorf("Unknown Expression: %T", e)
 	}
@@ -229,17 +248,24 @@ func ContainAggregateFunc(e expression.Expression) bool {
 	return len(m) > 0
 }
 
-func mentionedColumns(e expression.Expression, m map[string]bool, names *[]string) {
+func mentionedColumns(e expression.Expression, m map[string]bool, names *[]string, skipAgg bool) {
 	switch x := e.(type) {
 	case Value, *Value, *Variable,
-		*Default, *SubQuery, *Position:
+		*Default, *SubQuery, *ExistsSubQuery, *Position:
 		// nop
 	case *BinaryOperation:
-		mentionedColumns(x.L, m, names)
-		mentionedColumns(x.R, m, names)
+		mentionedColumns(x.L, m, names, skipAgg)
+		mentionedColumns(x.R, m, names, skipAgg)
+	case *CompareSubQuery:
+		mentionedColumns(x.L, m, names, skipAgg)
 	case *Call:
+		if skipAgg {
+			if f, ok := builtin[strings.ToLower(x.F)]; ok && f.isAggregate {
+				return
+			}
+		}
 		for _, e := range x.Args {
-			mentionedColumns(e, m, names)
+			mentionedColumns(e, m, names, skipAgg)
 		}
 	case *Ident:
 		name := x.L
@@ -248,60 +274,74 @@ func mentionedColumns(e expression.Expression, m map[string]bool, names *[]strin
 			*names = append(*names, name)
 		}
 	case *IsNull:
-		mentionedColumns(x.Expr, m, names)
+		mentionedColumns(x.Expr, m, names, skipAgg)
 	case *PExpr:
-		mentionedColumns(x.Expr, m, names)
+		mentionedColumns(x.Expr, m, names, skipAgg)
 	case *PatternIn:
-		mentionedColumns(x.Expr, m, names)
+		mentionedColumns(x.Expr, m, names, skipAgg)
 		for _, e := range x.List {
-			mentionedColumns(e, m, names)
+			mentionedColumns(e, m, names, skipAgg)
 		}
 	case *PatternLike:
-		mentionedColumns(x.Expr, m, names)
-		mentionedColumns(x.Pattern, m, names)
+		mentionedColumns(x.Expr, m, names, skipAgg)
+		mentionedColumns(x.Pattern, m, names, skipAgg)
 	case *UnaryOperation:
-		mentionedColumns(x.V, m, names)
+		mentionedColumns(x.V, m, names, skipAgg)
 	case *ParamMarker:
 		if x.Expr != nil {
-			mentionedColumns(x.Expr, m, names)
+			mentionedColumns(x.Expr, m, names, skipAgg)
 		}
 	case *FunctionCast:
 		if x.Expr != nil {
-			mentionedColumns(x.Expr, m, names)
+			mentionedColumns(x.Expr, m, names, skipAgg)
 		}
 	case *FunctionConvert:
 		if x.Expr != nil {
-			mentionedColumns(x.Expr, m, names)
-		}
+			mentionedColumns(x.Expr, m, names, skipAgg)
+		}
+	case *FunctionTrim:
+		mentionedColumns(x.Str, m, names, skipAgg)
+		if x.RemStr != nil {
+			mentionedColumns(x.RemStr, m, names, skipAgg)
+		}
+	case *FunctionDateArith:
+		mentionedColumns(x.Date, m, names, skipAgg)
+		mentionedColumns(x.Interval, m, names, skipAgg)
+	case *FunctionExtract:
+		mentionedColumns(x.Date, m, names, skipAgg)
 	case *FunctionSubstring:
 		if x.StrExpr != nil {
-			mentionedColumns(x.StrExpr, m, names)
+			mentionedColumns(x.StrExpr, m, names, skipAgg)
 		}
 		if x.Pos != nil {
-			mentionedColumns(x.Pos, m, names)
+			mentionedColumns(x.Pos, m, names, skipAgg)
 		}
 		if x.Len != nil {
-			mentionedColumns(x.Len, m, names)
+			mentionedColumns(x.Len, m, names, skipAgg)
 		}
 	case *FunctionCase:
 		if x.Value != nil {
-			mentionedColumns(x.Value, m, names)
+			mentionedColumns(x.Value, m, names, skipAgg)
 		}
 		for _, w := range x.WhenClauses {
-			mentionedColumns(w, m, names)
+			mentionedColumns(w, m, names, skipAgg)
 		}
 		if x.ElseClause != nil {
-			mentionedColumns(x.ElseClause, m, names)
+			mentionedColumns(x.ElseClause, m, names, skipAgg)
 		}
 	case *WhenClause:
-		mentionedColumns(x.Expr, m, names)
-		mentionedColumns(x.Result, m, names)
+		mentionedColumns(x.Expr, m, names, skipAgg)
+		mentionedColumns(x.Result, m, names, skipAgg)
 	case *IsTruth:
-		mentionedColumns(x.Expr, m, names)
+		mentionedColumns(x.Expr, m, names, skipAgg)
 	case *Between:
-		mentionedColumns(x.Expr, m, names)
-		mentionedColumns(x.Left, m, names)
-		mentionedColumns(x.Right, m, names)
+		mentionedColumns(x.Expr, m, names, skipAgg)
+		mentionedColumns(x.Left, m, names, skipAgg)
+		mentionedColumns(x.Right, m, names, skipAgg)
+	case *WindowFuncExpr:
+		for _, e := range x.exprs() {
+			mentionedColumns(e, m, names, skipAgg)
+		}
 	default:
 		log.Errorf("Unknown Expression: %T", e)
 	}
@@ -311,10 +351,229 @@ func mentionedColumns(e expression.Expression, m map[string]bool, names *[]strin
 func MentionedColumns(e expression.Expression) []string {
 	var names []string
 	m := make(map[string]bool)
-	mentionedColumns(e, m, &names)
+	mentionedColumns(e, m, &names, false)
+	return names
+}
+
+// MentionedColumnsOutsideAggregate returns a list of names for Ident expression,
+// the Ident expressions in the arguments of aggregate functions are skipped.
+func MentionedColumnsOutsideAggregate(e expression.Expression) []string {
+	var names []string
+	m := make(map[string]bool)
+	mentionedColumns(e, m, &names, true)
 	return names
 }
 
+// ContainSubQuery checks whether expression e contains a subquery, like "c in (select ...)" or others.
+func ContainSubQuery(e expression.Expression) bool {
+	switch x := e.(type) {
+	case *SubQuery, *ExistsSubQuery, *CompareSubQuery:
+		return true
+	case *BinaryOperation:
+		return ContainSubQuery(x.L) || ContainSubQuery(x.R)
+	case *Call:
+		return containSubQuery(x.Args)
+	case *IsNull:
+		return ContainSubQuery(x.Expr)
+	case *PExpr:
+		return ContainSubQuery(x.Expr)
+	case *PatternIn:
+		return x.Sel != nil || ContainSubQuery(x.Expr) || containSubQuery(x.List)
+	case *PatternLike:
+		return ContainSubQuery(x.Expr) || ContainSubQuery(x.Pattern)
+	case *PatternRegexp:
+		return ContainSubQuery(x.Expr) || ContainSubQuery(x.Pattern)
+	case *UnaryOperation:
+		return ContainSubQuery(x.V)
+	case *ParamMarker:
+		return x.Expr != nil && ContainSubQuery(x.Expr)
+	case *FunctionCast:
+		return x.Expr != nil && ContainSubQuery(x.Expr)
+	case *FunctionConvert:
+		return x.Expr != nil && ContainSubQuery(x.Expr)
+	case *FunctionTrim:
+		return containSubQuery([]expression.Expression{x.Str, x.RemStr})
+	case *FunctionDateArith:
+		return ContainSubQuery(x.Date) || ContainSubQuery(x.Interval)
+	case *FunctionExtract:
+		return ContainSubQuery(x.Date)
+	case *FunctionSubstring:
+		return containSubQuery([]expression.Expression{x.StrExpr, x.Pos, x.Len})
+	case *FunctionCase:
+		if x.Value != nil && ContainSubQuery(x.Value) {
+			return true
+		}
+		for _, w := range x.WhenClauses {
+			if ContainSubQuery(w) {
+				return true
+			}
+		}
+		return x.ElseClause != nil && ContainSubQuery(x.ElseClause)
+	case *WhenClause:
+		return ContainSubQuery(x.Expr) || ContainSubQuery(x.Result)
+	case *IsTruth:
+		return ContainSubQuery(x.Expr)
+	case *Between:
+		return ContainSubQuery(x.Expr) || ContainSubQuery(x.Left) || ContainSubQuery(x.Right)
+	case *WindowFuncExpr:
+		return containSubQuery(x.exprs())
+	}
+	return false
+}
+
+func containSubQuery(list []expression.Expression) bool {
+	for _, e := range list {
+		if e != nil && ContainSubQuery(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// MentionedWindowFuncs returns the window functions in expression e.
+func MentionedWindowFuncs(e expression.Expression) []*WindowFuncExpr {
+	var m []*WindowFuncExpr
+	mentionedWindowFuncs(e, &m)
+	return m
+}
+
+func mentionedWindowFuncs(e expression.Expression, m *[]*WindowFuncExpr) {
+	var list []expression.Expression
+	switch x := e.(type) {
+	case *WindowFuncExpr:
+		*m = append(*m, x)
+		return
+	case *BinaryOperation:
+		list = []expression.Expression{x.L, x.R}
+	case *CompareSubQuery:
+		list = []expression.Expression{x.L}
+	case *Call:
+		list = x.Args
+	case *IsNull:
+		list = []expression.Expression{x.Expr}
+	case *PExpr:
+		list = []expression.Expression{x.Expr}
+	case *PatternIn:
+		list = append([]expression.Expression{x.Expr}, x.List...)
+	case *PatternLike:
+		list = []expression.Expression{x.Expr, x.Pattern}
+	case *PatternRegexp:
+		list = []expression.Expression{x.Expr, x.Pattern}
+	case *UnaryOperation:
+		list = []expression.Expression{x.V}
+	case *ParamMarker:
+		list = []expression.Expression{x.Expr}
+	case *FunctionCast:
+		list = []expression.Expression{x.Expr}
+	case *FunctionConvert:
+		list = []expression.Expression{x.Expr}
+	case *FunctionTrim:
+		list = []expression.Expression{x.Str, x.RemStr}
+	case *FunctionDateArith:
+		list = []expression.Expression{x.Date, x.Interval}
+	case *FunctionExtract:
+		list = []expression.Expression{x.Date}
+	case *FunctionSubstring:
+		list = []expression.Expression{x.StrExpr, x.Pos, x.Len}
+	case *FunctionCase:
+		list = []expression.Expression{x.Value, x.ElseClause}
+		for _, w := range x.WhenClauses {
+			list = append(list, w)
+		}
+	case *WhenClause:
+		list = []expression.Expression{x.Expr, x.Result}
+	case *IsTruth:
+		list = []expression.Expression{x.Expr}
+	case *Between:
+		list = []expression.Expression{x.Expr, x.Left, x.Right}
+	}
+
+	for _, e := range list {
+		if e != nil {
+			mentionedWindowFuncs(e, m)
+		}
+	}
+}
+
+// ContainWindowFunc checks whether expression e contains a window function, like row_number() over ().
+func ContainWindowFunc(e expression.Expression) bool {
+	return len(MentionedWindowFuncs(e)) > 0
+}
+
+// MentionedSubQueries returns the statements of the subqueries in expression e, the subqueries nested in them are not included.
+func MentionedSubQueries(e expression.Expression) []stmt.Statement {
+	var m []stmt.Statement
+	mentionedSubQueries(e, &m)
+	return m
+}
+
+func mentionedSubQueries(e expression.Expression, m *[]stmt.Statement) {
+	var list []expression.Expression
+	switch x := e.(type) {
+	case *SubQuery:
+		if x != nil && x.Stmt != nil {
+			*m = append(*m, x.Stmt)
+		}
+		return
+	case *ExistsSubQuery:
+		list = []expression.Expression{x.Sel}
+	case *CompareSubQuery:
+		list = []expression.Expression{x.L, x.R}
+	case *BinaryOperation:
+		list = []expression.Expression{x.L, x.R}
+	case *Call:
+		list = x.Args
+	case *IsNull:
+		list = []expression.Expression{x.Expr}
+	case *PExpr:
+		list = []expression.Expression{x.Expr}
+	case *PatternIn:
+		if s, ok := x.Sel.(stmt.Statement); ok {
+			*m = append(*m, s)
+		}
+		list = append([]expression.Expression{x.Expr}, x.List...)
+	case *PatternLike:
+		list = []expression.Expression{x.Expr, x.Pattern}
+	case *PatternRegexp:
+		list = []expression.Expression{x.Expr, x.Pattern}
+	case *UnaryOperation:
+		list = []expression.Expression{x.V}
+	case *ParamMarker:
+		list = []expression.Expression{x.Expr}
+	case *FunctionCast:
+		list = []expression.Expression{x.Expr}
+	case *FunctionConvert:
+		list = []expression.Expression{x.Expr}
+	case *FunctionTrim:
+		list = []expression.Expression{x.Str, x.RemStr}
+	case *FunctionDateArith:
+		list = []expression.Expression{x.Date, x.Interval}
+	case *FunctionExtract:
+		list = []expression.Expression{x.Date}
+	case *FunctionSubstring:
+		list = []expression.Expression{x.StrExpr, x.Pos, x.Len}
+	case *FunctionCase:
+		list = []expression.Expression{x.Value, x.ElseClause}
+		for _, w := range x.WhenClauses {
+			list = append(list, w)
+		}
+	case *WhenClause:
+		list = []expression.Expression{x.Expr, x.Result}
+	case *IsTruth:
+		list = []expression.Expression{x.Expr}
+	case *Between:
+		list = []expression.Expression{x.Expr, x.Left, x.Right}
+	case *WindowFuncExpr:
+		list = x.exprs()
+	}
+
+	for _, e := range list {
+		if e != nil {
+			mentionedSubQueries(e, m)
+		}
+	}
+}
+
 func staticExpr(e expression.Expression) (expression.Expression, error) {
 	if e.IsStatic() {
 		v, err := e.Eval(nil, nil)
@@ -348,7 +607,7 @@ func IsCurrentTimeExpr(e expression.Expression) bool {
 }
 
 func getSystemTimestamp(ctx context.Context) (time.Time, error) {
-	value := time.Now()
+	value := time.Now().In(variable.GetTimeZone(ctx))
 
 	if ctx == nil {
 		return value, nil
@@ -367,7 +626,7 @@ func getSystemTimestamp(ctx context.Context) (time.Time, error) {
 				return value, nil
 			}
 
-			return time.Unix(timestamp, 0), nil
+			return time.Unix(timestamp, 0).In(value.Location()), nil
 		}
 	}
 
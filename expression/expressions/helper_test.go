@@ -79,6 +79,33 @@ func (s *testHelperSuite) TestMentionedColumns(c *C) {
 	}
 }
 
+func (s *testHelperSuite) TestMentionedSubQueries(c *C) {
+	v := Value{}
+	ms := newMockStatement()
+	sq := &SubQuery{Stmt: ms}
+	tbl := []struct {
+		Expr   expression.Expression
+		Expect int
+	}{
+		{Value{1}, 0},
+		{&BinaryOperation{L: v, R: v}, 0},
+		{sq, 1},
+		{&SubQuery{Value: 1}, 0},
+		{&BinaryOperation{L: sq, R: &ExistsSubQuery{Sel: sq}}, 2},
+		{&CompareSubQuery{L: sq, R: sq}, 2},
+		{&Call{F: "abs", Args: []expression.Expression{sq}}, 1},
+		{&PatternIn{Expr: v, Sel: ms}, 1},
+		{&PatternIn{Expr: sq, List: []expression.Expression{sq}}, 2},
+		{&FunctionCase{Value: v, WhenClauses: []*WhenClause{&WhenClause{Expr: sq, Result: v}}, ElseClause: sq}, 2},
+		{&Between{Expr: v, Left: sq, Right: v}, 1},
+	}
+
+	for _, t := range tbl {
+		ret := MentionedSubQueries(t.Expr)
+		c.Assert(ret, HasLen, t.Expect)
+	}
+}
+
 func (s *testHelperSuite) TestBase(c *C) {
 	e1 := Value{1}
 	e2 := &PExpr{Expr: e1}
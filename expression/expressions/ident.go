@@ -60,13 +60,23 @@ func (i *Ident) Eval(ctx context.Context, args map[interface{}]interface{}) (v i
 
 	if f, ok := args[ExprEvalIdentFunc]; ok {
 		if got, ok := f.(func(string) (interface{}, error)); ok {
-			return got(i.L)
+			if v, err = got(i.L); err == nil {
+				return v, nil
+			}
+			// The column may belong to the outer query of a correlated subquery.
+			if ov, ok := OuterIdentValue(ctx, i.L); ok {
+				return ov, nil
+			}
+			return nil, err
 		}
 	}
 
 	// defer func() { log.Errorf("Ident %q -> %v %v", i.S, v, err) }()
 	v, ok := args[i.L]
 	if !ok {
+		if ov, ok := OuterIdentValue(ctx, i.L); ok {
+			return ov, nil
+		}
 		err = errors.Errorf("unknown field %s %v", i.O, args)
 	}
 	return
@@ -99,8 +99,9 @@ func (n *PatternIn) String() string {
 func (n *PatternIn) evalInList(ctx context.Context, args map[interface{}]interface{},
 	in interface{}, list []expression.Expression) (interface{}, error) {
 	hasNull := false
+	lhs := newCollatedValue(in, n.Expr, args)
 	for _, v := range list {
-		b := NewBinaryOperation(opcode.EQ, Value{in}, v)
+		b := NewBinaryOperation(opcode.EQ, lhs, v)
 
 		eVal, err := b.Eval(ctx, args)
 		if err != nil {
@@ -149,27 +150,31 @@ func (n *PatternIn) Eval(ctx context.Context, args map[interface{}]interface{})
 	var res []expression.Expression
 	if ev, ok := args[n]; !ok {
 		// select not yet evaluated
-		r, err := n.Sel.Plan(ctx)
+		correlated, err := execSubQuery(ctx, args, func() error {
+			r, err := n.Sel.Plan(ctx)
+			if err != nil {
+				return err
+			}
+
+			if g, e := len(r.GetFields()), 1; g != e {
+				return errors.Errorf("IN (%s): mismatched field count, have %d, need %d", n.Sel, g, e)
+			}
+
+			res = make([]expression.Expression, 0)
+			// evaluate select and save its result for later in expression check
+			return r.Do(ctx, func(id interface{}, data []interface{}) (more bool, err error) {
+				res = append(res, Value{data[0]})
+				return true, nil
+			})
+		})
 		if err != nil {
 			return nil, err
 		}
 
-		if g, e := len(r.GetFields()), 1; g != e {
-			return false, errors.Errorf("IN (%s): mismatched field count, have %d, need %d", n.Sel, g, e)
-		}
-
-		res = make([]expression.Expression, 0)
-		// evaluate select and save its result for later in expression check
-		err = r.Do(ctx, func(id interface{}, data []interface{}) (more bool, err error) {
-			res = append(res, Value{data[0]})
+		// the result of a correlated select depends on the outer row, so it can't be saved.
+		if !correlated {
 			args[n] = res
-			return true, nil
-		})
-		if err != nil {
-			return nil, err
 		}
-
-		args[n] = res
 	} else {
 		res = ev.([]expression.Expression)
 	}
@@ -83,7 +83,7 @@ func (p *PatternLike) Eval(ctx context.Context, args map[interface{}]interface{}
 	if expr == nil {
 		return nil, nil
 	}
-	sexpr, ok := expr.(string)
+	sexpr, ok := stringValue(expr)
 	if !ok {
 		return nil, errors.Errorf("non-string expression.Expression in LIKE: %v (Value of type %T)", expr, expr)
 	}
@@ -97,7 +97,7 @@ func (p *PatternLike) Eval(ctx context.Context, args map[interface{}]interface{}
 		if pattern == nil {
 			return nil, nil
 		}
-		spattern, ok := pattern.(string)
+		spattern, ok := stringValue(pattern)
 		if !ok {
 			return nil, errors.Errorf("non-string pattern in LIKE: %v (Value of type %T)", pattern, pattern)
 		}
@@ -82,6 +82,12 @@ func (*testLikeSuite) TestEval(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(val, IsFalse)
 
+	// A binary string is matched as the string of its bytes.
+	binPattern := &PatternLike{Expr: &Value{Val: []byte("hello")}, Pattern: &Value{Val: "he%"}}
+	val, err = binPattern.Eval(nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(val, IsTrue)
+
 	pattern.Pattern = mockExpr{isStatic: false, err: errors.Errorf("test error")}
 	_, err = pattern.Clone()
 	c.Assert(err, NotNil)
@@ -89,7 +89,7 @@ func (p *PatternRegexp) Eval(ctx context.Context, args map[interface{}]interface
 			return nil, nil
 		}
 
-		sexpr, ok = expr.(string)
+		sexpr, ok = stringValue(expr)
 		if !ok {
 			return nil, errors.Errorf("non-string expression.Expression in LIKE: %v (Value of type %T)", expr, expr)
 		}
@@ -111,7 +111,7 @@ func (p *PatternRegexp) Eval(ctx context.Context, args map[interface{}]interface
 			return nil, nil
 		}
 
-		spattern, ok := pattern.(string)
+		spattern, ok := stringValue(pattern)
 		if !ok {
 			return nil, errors.Errorf("non-string pattern in LIKE: %v (Value of type %T)", pattern, pattern)
 		}
@@ -17,8 +17,10 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/juju/errors"
 	"github.com/Dong-Chan/alloydb/context"
 	"github.com/Dong-Chan/alloydb/expression"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
 	"github.com/Dong-Chan/alloydb/stmt"
 )
 
@@ -29,7 +31,7 @@ var _ expression.Expression = (*SubQuery)(nil)
 type SubQuery struct {
 	// Stmt is the sub select statement.
 	Stmt stmt.Statement
-	// Value holds the sub select result.
+	// Value holds a constant result of the sub select, if it is not nil, Stmt is not executed.
 	Value interface{}
 }
 
@@ -41,23 +43,64 @@ func (sq *SubQuery) Clone() (expression.Expression, error) {
 }
 
 // Eval implements the Expression Eval interface.
+// The subquery must return one column and at most one row.
 func (sq *SubQuery) Eval(ctx context.Context, args map[interface{}]interface{}) (v interface{}, err error) {
 	if sq.Value != nil {
 		return sq.Value, nil
 	}
-	rs, err := sq.Stmt.Exec(ctx)
+
+	// Get 2 rows to check whether the subquery returns more than 1 row.
+	rows, err := sq.EvalRows(ctx, args, 2, 1)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	switch len(rows) {
+	case 0:
+		return nil, nil
+	case 1:
+		return rows[0][0], nil
+	default:
+		return nil, errors.Trace(mysql.NewDefaultError(mysql.ErSubqueryNo1Row))
+	}
+}
+
+// EvalRows executes the subquery and returns at most limit rows, all rows are returned if limit < 0.
+// If columns > 0, the subquery must return the number of columns.
+// The subquery is executed with the current row of the outer query in args, so it can reference
+// the outer columns. If it doesn't, the rows are saved in args and it is executed only once.
+func (sq *SubQuery) EvalRows(ctx context.Context, args map[interface{}]interface{}, limit int, columns int) ([][]interface{}, error) {
+	if v, ok := args[sq]; ok {
+		return v.([][]interface{}), nil
+	}
+
+	var rows [][]interface{}
+	correlated, err := execSubQuery(ctx, args, func() error {
+		rs, err := sq.Stmt.Exec(ctx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		fields, err := rs.Fields()
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		if columns > 0 && len(fields) != columns {
+			return errors.Trace(mysql.NewDefaultError(mysql.ErOperandColumns, columns))
+		}
+
+		rows, err = rs.Rows(limit, 0)
+		return errors.Trace(err)
+	})
 	if err != nil {
-		return nil, err
+		return nil, errors.Trace(err)
 	}
-	// TODO: check row/column number
-	// Output all the data and let the outer caller check the row/column number
-	// This simple implementation is used to pass tpc-c
-	rows, err := rs.Rows(1, 0)
-	if err != nil || len(rows) == 0 || len(rows[0]) == 0 {
-		return nil, err
+
+	if !correlated && args != nil {
+		args[sq] = rows
 	}
-	sq.Value = rows[0][0]
-	return sq.Value, nil
+	return rows, nil
 }
 
 // IsStatic implements the Expression IsStatic interface, always returns false.
@@ -73,3 +116,72 @@ func (sq *SubQuery) String() string {
 	}
 	return ""
 }
+
+// outerQueryKeyType is a dummy type to avoid naming collision in context.
+type outerQueryKeyType int
+
+// define a Stringer function for debugging and pretty printting
+func (k outerQueryKeyType) String() string {
+	return "outer_query"
+}
+
+const outerQueryKey outerQueryKeyType = 0
+
+// outerQuery is the query enclosing the subquery being executed,
+// the subquery resolves the columns not found in its own tables with it.
+type outerQuery struct {
+	// identFunc retrieves the column value of the current outer row.
+	identFunc func(string) (interface{}, error)
+	// parent is the outer query of the outer query, if it is a subquery too.
+	parent *outerQuery
+	// correlated is true if the subquery references any column of the outer query.
+	correlated bool
+}
+
+// execSubQuery calls f to execute a subquery in ctx with the outer query row of args.
+// It returns whether the subquery references the outer query.
+func execSubQuery(ctx context.Context, args map[interface{}]interface{}, f func() error) (bool, error) {
+	if ctx == nil {
+		return false, f()
+	}
+
+	q := &outerQuery{}
+	q.parent, _ = ctx.Value(outerQueryKey).(*outerQuery)
+	if fn, ok := args[ExprEvalIdentFunc]; ok {
+		q.identFunc, _ = fn.(func(string) (interface{}, error))
+	}
+
+	ctx.SetValue(outerQueryKey, q)
+	defer func() {
+		if q.parent != nil {
+			ctx.SetValue(outerQueryKey, q.parent)
+		} else {
+			ctx.ClearValue(outerQueryKey)
+		}
+	}()
+
+	err := f()
+	return q.correlated, err
+}
+
+// OuterIdentValue returns the value of column name in the outer queries of the subquery
+// being executed in ctx, the nearest outer query is checked first.
+// It returns false if the name is not found.
+func OuterIdentValue(ctx context.Context, name string) (interface{}, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	q, _ := ctx.Value(outerQueryKey).(*outerQuery)
+	for ; q != nil; q = q.parent {
+		// All the subqueries between the reference and the outer query are correlated.
+		q.correlated = true
+		if q.identFunc == nil {
+			continue
+		}
+		if v, err := q.identFunc(name); err == nil {
+			return v, true
+		}
+	}
+	return nil, false
+}
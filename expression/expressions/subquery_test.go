@@ -14,7 +14,10 @@
 package expressions
 
 import (
+	"github.com/juju/errors"
 	. "github.com/pingcap/check"
+	"github.com/Dong-Chan/alloydb/model"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
 )
 
 var _ = Suite(&testSubQuerySuite{})
@@ -43,12 +46,105 @@ func (s *testSubQuerySuite) TestSubQuery(c *C) {
 	c.Assert(ok, IsTrue)
 
 	e2.Value = nil
-	e2.Stmt = newMockStatement()
+	ms := newMockStatement()
+	e2.Stmt = ms
 
+	// operand should contain 1 column
+	_, err = e2.Eval(nil, nil)
+	c.Assert(err, NotNil)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErOperandColumns))
+
+	// subquery returns more than 1 row
+	ms.SetFieldOffset(1)
+	_, err = e2.Eval(nil, nil)
+	c.Assert(err, NotNil)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErSubqueryNo1Row))
+
+	ms.rset.rows = ms.rset.rows[:1]
 	vv, err := e2.Eval(nil, nil)
 	c.Assert(err, IsNil)
 	c.Assert(vv, Equals, 1)
 
+	// empty result is NULL
+	ms.rset.rows = nil
+	vv, err = e2.Eval(nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(vv, IsNil)
+
 	str = e2.String()
 	c.Assert(len(str), Greater, 0)
 }
+
+func (s *testSubQuerySuite) TestSubQueryCache(c *C) {
+	ms := newMockStatement()
+	ms.SetFieldOffset(1)
+	ms.rset.rows = ms.rset.rows[:1]
+	e := &SubQuery{Stmt: ms}
+
+	// The uncorrelated result is saved in args.
+	m := map[interface{}]interface{}{}
+	v, err := e.Eval(newMockCtx(), m)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, 1)
+
+	ms.rset.rows = [][]interface{}{{2, 2}}
+	v, err = e.Eval(newMockCtx(), m)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, 1)
+
+	v, err = e.Eval(newMockCtx(), map[interface{}]interface{}{})
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, 2)
+}
+
+func (s *testSubQuerySuite) TestOuterIdentValue(c *C) {
+	ctx := newMockCtx()
+	_, ok := OuterIdentValue(ctx, "c1")
+	c.Assert(ok, IsFalse)
+
+	outer := map[interface{}]interface{}{
+		ExprEvalIdentFunc: func(name string) (interface{}, error) {
+			if name == "c1" {
+				return 1, nil
+			}
+			return nil, errors.Errorf("unknown field %s", name)
+		},
+	}
+	inner := map[interface{}]interface{}{
+		ExprEvalIdentFunc: func(name string) (interface{}, error) {
+			if name == "c2" {
+				return 2, nil
+			}
+			return nil, errors.Errorf("unknown field %s", name)
+		},
+	}
+
+	var vals []interface{}
+	correlated, err := execSubQuery(ctx, outer, func() error {
+		for _, name := range []string{"c1", "c2"} {
+			v, err := (&Ident{model.NewCIStr(name)}).Eval(ctx, inner)
+			if err != nil {
+				return err
+			}
+			vals = append(vals, v)
+		}
+		_, err := (&Ident{model.NewCIStr("c3")}).Eval(ctx, inner)
+		c.Assert(err, NotNil)
+		return nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(correlated, IsTrue)
+	c.Assert(vals, DeepEquals, []interface{}{1, 2})
+
+	// The outer query is cleared after executing.
+	_, ok = OuterIdentValue(ctx, "c1")
+	c.Assert(ok, IsFalse)
+
+	correlated, err = execSubQuery(ctx, outer, func() error {
+		v, err := (&Ident{model.NewCIStr("c2")}).Eval(ctx, inner)
+		c.Assert(v, Equals, 2)
+		return err
+	})
+	c.Assert(err, IsNil)
+	c.Assert(correlated, IsFalse)
+}
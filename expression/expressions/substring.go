@@ -62,7 +62,7 @@ func (f *FunctionSubstring) Eval(ctx context.Context, args map[interface{}]inter
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	str, ok := fs.(string)
+	str, ok := stringValue(fs)
 	if !ok {
 		return nil, errors.Errorf("Substring invalid args, need string but get %T", fs)
 	}
@@ -109,5 +109,9 @@ func (f *FunctionSubstring) Eval(ctx context.Context, args map[interface{}]inter
 	if end > len(str) {
 		end = len(str)
 	}
+	// The substring of a binary string is a binary string.
+	if _, ok := fs.([]byte); ok {
+		return []byte(str[pos:end]), nil
+	}
 	return str[pos:end], nil
 }
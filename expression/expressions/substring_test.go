@@ -66,6 +66,17 @@ func (s *testSubstringSuite) TestSubstring(c *C) {
 		c.Assert(ok, Equals, true)
 		c.Assert(s, Equals, s1)
 	}
+
+	// The substring of a binary string is a binary string.
+	f := FunctionSubstring{
+		StrExpr: &Value{Val: []byte("Sakila")},
+		Pos:     &Value{Val: int64(-5)},
+		Len:     &Value{Val: int64(3)},
+	}
+	r, err := f.Eval(nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(r, DeepEquals, []byte("aki"))
+
 	errTbl := []struct {
 		str    interface{}
 		pos    interface{}
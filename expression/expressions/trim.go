@@ -0,0 +1,129 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/expression"
+)
+
+// TrimDirectionType is the type for trim direction.
+type TrimDirectionType int
+
+const (
+	// TrimBothDefault trims from both direction by default.
+	TrimBothDefault TrimDirectionType = iota
+	// TrimBoth trims from both direction with explicit notation.
+	TrimBoth
+	// TrimLeading trims from left.
+	TrimLeading
+	// TrimTrailing trims from right.
+	TrimTrailing
+)
+
+// FunctionTrim removes the leading and/or trailing remstr prefixes or suffixes from a string.
+// See: https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_trim
+type FunctionTrim struct {
+	Str expression.Expression
+	// RemStr is the string to remove, it is a space if nil.
+	RemStr    expression.Expression
+	Direction TrimDirectionType
+}
+
+// Clone implements the Expression Clone interface.
+func (f *FunctionTrim) Clone() (expression.Expression, error) {
+	str, err := f.Str.Clone()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	nf := &FunctionTrim{Str: str, Direction: f.Direction}
+	if f.RemStr != nil {
+		if nf.RemStr, err = f.RemStr.Clone(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return nf, nil
+}
+
+// IsStatic implements the Expression IsStatic interface.
+func (f *FunctionTrim) IsStatic() bool {
+	return f.Str.IsStatic() && (f.RemStr == nil || f.RemStr.IsStatic())
+}
+
+// String implements the Expression String interface.
+func (f *FunctionTrim) String() string {
+	var direction string
+	switch f.Direction {
+	case TrimBoth:
+		direction = "BOTH"
+	case TrimLeading:
+		direction = "LEADING"
+	case TrimTrailing:
+		direction = "TRAILING"
+	}
+
+	switch {
+	case direction == "" && f.RemStr == nil:
+		return fmt.Sprintf("TRIM(%s)", f.Str)
+	case direction == "":
+		return fmt.Sprintf("TRIM(%s FROM %s)", f.RemStr, f.Str)
+	case f.RemStr == nil:
+		return fmt.Sprintf("TRIM(%s FROM %s)", direction, f.Str)
+	}
+	return fmt.Sprintf("TRIM(%s %s FROM %s)", direction, f.RemStr, f.Str)
+}
+
+// Eval implements the Expression Eval interface.
+func (f *FunctionTrim) Eval(ctx context.Context, args map[interface{}]interface{}) (interface{}, error) {
+	v, err := f.Str.Eval(ctx, args)
+	if err != nil || v == nil {
+		return nil, errors.Trace(err)
+	}
+	str, binary, err := stringArg(v)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	remStr := " "
+	if f.RemStr != nil {
+		v, err = f.RemStr.Eval(ctx, args)
+		if err != nil || v == nil {
+			return nil, errors.Trace(err)
+		}
+		var remBinary bool
+		if remStr, remBinary, err = stringArg(v); err != nil {
+			return nil, errors.Trace(err)
+		}
+		binary = binary || remBinary
+	}
+
+	// The whole remStr is removed repeatedly, not the characters of it.
+	if len(remStr) > 0 {
+		if f.Direction != TrimTrailing {
+			for strings.HasPrefix(str, remStr) {
+				str = str[len(remStr):]
+			}
+		}
+		if f.Direction != TrimLeading {
+			for strings.HasSuffix(str, remStr) {
+				str = str[:len(str)-len(remStr)]
+			}
+		}
+	}
+	return stringResult(str, binary), nil
+}
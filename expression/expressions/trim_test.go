@@ -0,0 +1,63 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testTrimSuite{})
+
+type testTrimSuite struct {
+}
+
+func (s *testTrimSuite) TestTrim(c *C) {
+	tbl := []struct {
+		Str       interface{}
+		RemStr    interface{}
+		Direction TrimDirectionType
+		Result    interface{}
+		String    string
+	}{
+		{"  bar   ", nil, TrimBothDefault, "bar", `TRIM("  bar   ")`},
+		{"xxxbarxxx", "x", TrimLeading, "barxxx", `TRIM(LEADING "x" FROM "xxxbarxxx")`},
+		{"xxxbarxxx", "x", TrimBoth, "bar", `TRIM(BOTH "x" FROM "xxxbarxxx")`},
+		{"barxxyz", "xyz", TrimTrailing, "barx", `TRIM(TRAILING "xyz" FROM "barxxyz")`},
+		{"xxxbarxxx", "x", TrimBothDefault, "bar", `TRIM("x" FROM "xxxbarxxx")`},
+		{"  bar   ", nil, TrimTrailing, "  bar", `TRIM(TRAILING FROM "  bar   ")`},
+		{[]byte("xbarx"), "x", TrimBoth, []byte("bar"), ""},
+		{"bar", "", TrimBoth, "bar", ""},
+		{nil, "x", TrimBoth, nil, ""},
+		{"xbarx", nil, TrimBoth, "xbarx", ""},
+	}
+
+	for _, t := range tbl {
+		f := &FunctionTrim{Str: Value{Val: t.Str}, Direction: t.Direction}
+		if t.RemStr != nil {
+			f.RemStr = Value{Val: t.RemStr}
+		}
+		c.Assert(f.IsStatic(), IsTrue)
+		if t.String != "" {
+			c.Assert(f.String(), Equals, t.String)
+		}
+
+		fc, err := f.Clone()
+		c.Assert(err, IsNil)
+		c.Assert(fc.String(), Equals, f.String())
+
+		v, err := fc.Eval(nil, nil)
+		c.Assert(err, IsNil)
+		c.Assert(v, DeepEquals, t.Result)
+	}
+}
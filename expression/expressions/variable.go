@@ -77,12 +77,20 @@ func (v *Variable) Eval(ctx context.Context, args map[interface{}]interface{}) (
 		return nil, errors.Errorf("Unknown system variable '%s'", name)
 	}
 
-	if !v.IsGlobal {
+	// warning_count and error_count are counted by the session for the last statement.
+	switch name {
+	case variable.WarningCount:
+		return int64(sessionVars.WarningCount()), nil
+	case variable.ErrorCount:
+		return int64(sessionVars.ErrorCount()), nil
+	}
+
+	if !v.IsGlobal && sysVar.Scope&variable.ScopeSession != 0 {
 		if value, ok := sessionVars.Systems[name]; ok {
 			return value, nil
 		}
 	}
 
-	// TODO: select global sys var from kv store, now we only get it from memory
-	return sysVar.Value, nil
+	value, err := variable.GetGlobalSysVar(ctx, name)
+	return value, errors.Trace(err)
 }
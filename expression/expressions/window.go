@@ -0,0 +1,377 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/expression"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/util/types"
+)
+
+var (
+	_ expression.Expression = (*WindowFuncExpr)(nil)
+)
+
+// windowFuncs are the functions which can only be used as window functions.
+// The aggregate functions can be used as window functions too.
+var windowFuncs = map[string]struct {
+	minArgs int
+	maxArgs int
+}{
+	"row_number":  {0, 0},
+	"rank":        {0, 0},
+	"dense_rank":  {0, 0},
+	"ntile":       {1, 1},
+	"lag":         {1, 3},
+	"lead":        {1, 3},
+	"first_value": {1, 1},
+	"last_value":  {1, 1},
+}
+
+// WindowOrderByItem is an item of the ORDER BY clause of a window.
+type WindowOrderByItem struct {
+	Expr expression.Expression
+	Asc  bool
+}
+
+// FrameUnit is the unit of a window frame.
+type FrameUnit int
+
+// Window frame units.
+const (
+	// FrameRows defines the frame by the row positions relative to the current row.
+	FrameRows FrameUnit = iota + 1
+	// FrameRange defines the frame by the ORDER BY values relative to the value of the current row.
+	FrameRange
+)
+
+// FrameBoundType is the type of a window frame bound.
+type FrameBoundType int
+
+// Window frame bound types.
+const (
+	UnboundedPreceding FrameBoundType = iota + 1
+	Preceding
+	CurrentRow
+	Following
+	UnboundedFollowing
+)
+
+// FrameBound is the start or end of a window frame, like "1 PRECEDING".
+type FrameBound struct {
+	Type FrameBoundType
+	// Expr is the offset of Preceding and Following.
+	Expr expression.Expression
+}
+
+// Offset returns the offset of Preceding and Following, it must be a non-negative number.
+func (b FrameBound) Offset() (float64, error) {
+	v, err := b.Expr.Eval(nil, nil)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	switch v.(type) {
+	case int64, uint64, float64, mysql.Decimal:
+	default:
+		return 0, errors.Errorf("invalid frame offset %v", v)
+	}
+
+	n, err := types.ToFloat64(v)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if n < 0 {
+		return 0, errors.Errorf("invalid frame offset %v", v)
+	}
+	return n, nil
+}
+
+// String implements fmt.Stringer interface.
+func (b FrameBound) String() string {
+	switch b.Type {
+	case UnboundedPreceding:
+		return "UNBOUNDED PRECEDING"
+	case Preceding:
+		return fmt.Sprintf("%s PRECEDING", b.Expr)
+	case CurrentRow:
+		return "CURRENT ROW"
+	case Following:
+		return fmt.Sprintf("%s FOLLOWING", b.Expr)
+	case UnboundedFollowing:
+		return "UNBOUNDED FOLLOWING"
+	}
+	return ""
+}
+
+// WindowFrame is the frame of a window, the rows in the partition used by the window function
+// for the current row.
+type WindowFrame struct {
+	Unit  FrameUnit
+	Start FrameBound
+	End   FrameBound
+}
+
+// String implements fmt.Stringer interface.
+func (f *WindowFrame) String() string {
+	unit := "ROWS"
+	if f.Unit == FrameRange {
+		unit = "RANGE"
+	}
+	return fmt.Sprintf("%s BETWEEN %s AND %s", unit, f.Start, f.End)
+}
+
+// WindowSpec is the window of window functions, like "(w PARTITION BY c1 ORDER BY c2 ROWS 1 PRECEDING)".
+// See: https://dev.mysql.com/doc/refman/8.0/en/window-functions-named-windows.html
+type WindowSpec struct {
+	// Name is the name defined by the WINDOW clause, it is empty for the window in OVER.
+	Name string
+	// Ref is the name of the window which this window is based on.
+	Ref         string
+	PartitionBy []expression.Expression
+	OrderBy     []*WindowOrderByItem
+	Frame       *WindowFrame
+}
+
+// Clone clones the window spec.
+func (s *WindowSpec) Clone() (*WindowSpec, error) {
+	partitionBy, err := cloneExpressionList(s.PartitionBy)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	n := &WindowSpec{Name: s.Name, Ref: s.Ref, PartitionBy: partitionBy}
+	for _, item := range s.OrderBy {
+		expr, err := item.Expr.Clone()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		n.OrderBy = append(n.OrderBy, &WindowOrderByItem{Expr: expr, Asc: item.Asc})
+	}
+	if s.Frame != nil {
+		frame := *s.Frame
+		n.Frame = &frame
+	}
+	return n, nil
+}
+
+// String implements fmt.Stringer interface.
+func (s *WindowSpec) String() string {
+	var a []string
+	if s.Ref != "" {
+		a = append(a, s.Ref)
+	}
+	if len(s.PartitionBy) > 0 {
+		by := make([]string, len(s.PartitionBy))
+		for i, e := range s.PartitionBy {
+			by[i] = e.String()
+		}
+		a = append(a, "PARTITION BY "+strings.Join(by, ", "))
+	}
+	if len(s.OrderBy) > 0 {
+		by := make([]string, len(s.OrderBy))
+		for i, item := range s.OrderBy {
+			if item.Asc {
+				by[i] = item.Expr.String()
+			} else {
+				by[i] = item.Expr.String() + " DESC"
+			}
+		}
+		a = append(a, "ORDER BY "+strings.Join(by, ", "))
+	}
+	if s.Frame != nil {
+		a = append(a, s.Frame.String())
+	}
+	return "(" + strings.Join(a, " ") + ")"
+}
+
+// Resolve merges the window with the named window it is based on.
+// The window can not define PARTITION BY, and can not define ORDER BY if the named window defines it,
+// the named window can not define the frame.
+func (s *WindowSpec) Resolve(windows map[string]*WindowSpec) error {
+	return s.resolve(windows, map[*WindowSpec]bool{})
+}
+
+func (s *WindowSpec) resolve(windows map[string]*WindowSpec, visiting map[*WindowSpec]bool) error {
+	if s.Ref == "" {
+		return nil
+	}
+
+	ref, ok := windows[strings.ToLower(s.Ref)]
+	if !ok {
+		return errors.Trace(mysql.NewDefaultError(mysql.ErWindowNoSuchWindow, s.Ref))
+	}
+	if visiting[s] {
+		return errors.Trace(mysql.NewDefaultError(mysql.ErWindowCircularityInWindowGraph))
+	}
+	visiting[s] = true
+	if err := ref.resolve(windows, visiting); err != nil {
+		return errors.Trace(err)
+	}
+
+	if len(s.PartitionBy) > 0 {
+		return errors.Trace(mysql.NewDefaultError(mysql.ErWindowNoChildPartitioning))
+	}
+	if ref.Frame != nil {
+		return errors.Trace(mysql.NewDefaultError(mysql.ErWindowNoInherentFrame, s.Ref))
+	}
+	if len(s.OrderBy) > 0 && len(ref.OrderBy) > 0 {
+		return errors.Trace(mysql.NewDefaultError(mysql.ErWindowNoRedefineOrderBy, s.displayName(), s.Ref))
+	}
+
+	// the expressions of the window may be replaced later, so we must not share them with the named window.
+	s.PartitionBy = append([]expression.Expression(nil), ref.PartitionBy...)
+	if len(s.OrderBy) == 0 {
+		for _, item := range ref.OrderBy {
+			s.OrderBy = append(s.OrderBy, &WindowOrderByItem{Expr: item.Expr, Asc: item.Asc})
+		}
+	}
+	s.Ref = ""
+	return nil
+}
+
+// Check checks the frame of the window.
+func (s *WindowSpec) Check() error {
+	f := s.Frame
+	if f == nil {
+		return nil
+	}
+
+	if f.Start.Type == UnboundedFollowing {
+		return errors.Trace(mysql.NewDefaultError(mysql.ErWindowFrameStartIllegal, s.displayName()))
+	}
+	if f.End.Type == UnboundedPreceding {
+		return errors.Trace(mysql.NewDefaultError(mysql.ErWindowFrameEndIllegal, s.displayName()))
+	}
+
+	hasOffset := false
+	for _, b := range []FrameBound{f.Start, f.End} {
+		if b.Expr == nil {
+			continue
+		}
+
+		hasOffset = true
+		v, err := b.Offset()
+		if err != nil || (f.Unit == FrameRows && v != float64(int64(v))) {
+			return errors.Trace(mysql.NewDefaultError(mysql.ErWindowFrameIllegal, s.displayName()))
+		}
+	}
+
+	if f.Unit == FrameRange && hasOffset && len(s.OrderBy) != 1 {
+		return errors.Trace(mysql.NewDefaultError(mysql.ErWindowRangeFrameOrderType, s.displayName()))
+	}
+	return nil
+}
+
+func (s *WindowSpec) displayName() string {
+	if s.Name == "" {
+		return "<unnamed window>"
+	}
+	return s.Name
+}
+
+// WindowFuncExpr is a window function call, like "row_number() OVER (PARTITION BY c1 ORDER BY c2)".
+// Its value is computed for every row with the rows of the window, and it is saved in the eval args
+// with the expression as the key.
+// See: https://dev.mysql.com/doc/refman/8.0/en/window-functions.html
+type WindowFuncExpr struct {
+	// F is the function name.
+	F    string
+	Args []expression.Expression
+	Spec *WindowSpec
+}
+
+// NewWindowFunc creates a window function call with function name f, function args and the window.
+func NewWindowFunc(f string, args []expression.Expression, spec *WindowSpec) (*WindowFuncExpr, error) {
+	name := strings.ToLower(f)
+	min, max := -1, -1
+	if x, ok := windowFuncs[name]; ok {
+		min, max = x.minArgs, x.maxArgs
+	} else if x, ok := builtin[name]; ok && x.isAggregate {
+		min, max = x.minArgs, x.maxArgs
+	} else {
+		return nil, errors.Errorf("%s is not a window function", f)
+	}
+
+	if g := len(args); g < min || (max != -1 && g > max) {
+		a := []interface{}{}
+		for _, v := range args {
+			a = append(a, v)
+		}
+		return nil, badNArgs(min, f, a)
+	}
+
+	return &WindowFuncExpr{F: f, Args: args, Spec: spec}, nil
+}
+
+// exprs returns the expressions in the args and the window.
+func (w *WindowFuncExpr) exprs() []expression.Expression {
+	list := append([]expression.Expression{}, w.Args...)
+	list = append(list, w.Spec.PartitionBy...)
+	for _, item := range w.Spec.OrderBy {
+		list = append(list, item.Expr)
+	}
+	return list
+}
+
+// IsAggregate returns whether the window function is an aggregate function.
+func (w *WindowFuncExpr) IsAggregate() bool {
+	_, ok := windowFuncs[strings.ToLower(w.F)]
+	return !ok
+}
+
+// Clone implements the Expression Clone interface.
+func (w *WindowFuncExpr) Clone() (expression.Expression, error) {
+	args, err := cloneExpressionList(w.Args)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	spec, err := w.Spec.Clone()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &WindowFuncExpr{F: w.F, Args: args, Spec: spec}, nil
+}
+
+// IsStatic implements the Expression IsStatic interface, always returns false.
+func (w *WindowFuncExpr) IsStatic() bool {
+	return false
+}
+
+// String implements the Expression String interface.
+func (w *WindowFuncExpr) String() string {
+	a := make([]string, len(w.Args))
+	for i, v := range w.Args {
+		a[i] = v.String()
+	}
+
+	over := w.Spec.String()
+	if w.Spec.Ref != "" && len(w.Spec.PartitionBy) == 0 && len(w.Spec.OrderBy) == 0 && w.Spec.Frame == nil {
+		over = w.Spec.Ref
+	}
+	return fmt.Sprintf("%s(%s) OVER %s", w.F, strings.Join(a, ", "), over)
+}
+
+// Eval implements the Expression Eval interface.
+// The value is computed by the window plan after the other fields of the row are evaluated,
+// before that it is NULL.
+func (w *WindowFuncExpr) Eval(ctx context.Context, args map[interface{}]interface{}) (v interface{}, err error) {
+	return args[w], nil
+}
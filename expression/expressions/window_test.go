@@ -0,0 +1,168 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expressions
+
+import (
+	"github.com/juju/errors"
+	. "github.com/pingcap/check"
+	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/model"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/parser/opcode"
+)
+
+var _ = Suite(&testWindowSuite{})
+
+type testWindowSuite struct {
+}
+
+func (s *testWindowSuite) TestWindowFunc(c *C) {
+	id := &Ident{CIStr: model.NewCIStr("id")}
+	spec := &WindowSpec{
+		PartitionBy: []expression.Expression{&Ident{CIStr: model.NewCIStr("c")}},
+		OrderBy:     []*WindowOrderByItem{{Expr: id, Asc: false}},
+		Frame: &WindowFrame{
+			Unit:  FrameRows,
+			Start: FrameBound{Type: Preceding, Expr: Value{int64(1)}},
+			End:   FrameBound{Type: UnboundedFollowing},
+		},
+	}
+	e, err := NewWindowFunc("sum", []expression.Expression{id}, spec)
+	c.Assert(err, IsNil)
+	c.Assert(e.IsStatic(), IsFalse)
+	c.Assert(e.IsAggregate(), IsTrue)
+	c.Assert(e.String(), Equals, "sum(id) OVER (PARTITION BY c ORDER BY id DESC ROWS BETWEEN 1 PRECEDING AND UNBOUNDED FOLLOWING)")
+
+	ec, err := e.Clone()
+	c.Assert(err, IsNil)
+	c.Assert(ec.String(), Equals, e.String())
+	c.Assert(ec.(*WindowFuncExpr).Spec.Frame, Not(Equals), spec.Frame)
+
+	// the value is computed by the window plan.
+	v, err := e.Eval(nil, map[interface{}]interface{}{})
+	c.Assert(err, IsNil)
+	c.Assert(v, IsNil)
+	v, err = e.Eval(nil, map[interface{}]interface{}{e: int64(1)})
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, int64(1))
+
+	e, err = NewWindowFunc("row_number", nil, &WindowSpec{Ref: "w"})
+	c.Assert(err, IsNil)
+	c.Assert(e.IsAggregate(), IsFalse)
+	c.Assert(e.String(), Equals, "row_number() OVER w")
+
+	_, err = NewWindowFunc("row_number", []expression.Expression{id}, &WindowSpec{})
+	c.Assert(err, NotNil)
+	_, err = NewWindowFunc("lag", nil, &WindowSpec{})
+	c.Assert(err, NotNil)
+	_, err = NewWindowFunc("abs", []expression.Expression{id}, &WindowSpec{})
+	c.Assert(err, NotNil)
+}
+
+func (s *testWindowSuite) TestMentionedWindowFuncs(c *C) {
+	e, err := NewWindowFunc("rank", nil, &WindowSpec{})
+	c.Assert(err, IsNil)
+
+	expr := NewBinaryOperation(opcode.Plus, &PExpr{Expr: e}, Value{1})
+	c.Assert(MentionedWindowFuncs(expr), DeepEquals, []*WindowFuncExpr{e})
+	c.Assert(ContainWindowFunc(expr), IsTrue)
+	c.Assert(ContainWindowFunc(Value{1}), IsFalse)
+
+	// the aggregate functions in window functions are not window functions.
+	count, err := NewCall("count", []expression.Expression{Value{TypeStar("*")}}, false)
+	c.Assert(err, IsNil)
+	e, err = NewWindowFunc("sum", []expression.Expression{count}, &WindowSpec{})
+	c.Assert(err, IsNil)
+	c.Assert(MentionedAggregateFuncs(e), DeepEquals, []expression.Expression{count})
+	c.Assert(ContainAggregateFunc(e), IsTrue)
+}
+
+func (s *testWindowSuite) TestWindowSpec(c *C) {
+	id := &Ident{CIStr: model.NewCIStr("id")}
+	windows := map[string]*WindowSpec{
+		"w1": {Name: "w1", PartitionBy: []expression.Expression{id}},
+		"w2": {Name: "w2", Ref: "W1", OrderBy: []*WindowOrderByItem{{Expr: id, Asc: true}}},
+		"w3": {Name: "w3", Ref: "w4"},
+		"w4": {Name: "w4", Ref: "w3"},
+		"w5": {Name: "w5", Frame: &WindowFrame{Unit: FrameRows, Start: FrameBound{Type: CurrentRow}}},
+	}
+
+	spec := &WindowSpec{Ref: "w2"}
+	c.Assert(spec.Resolve(windows), IsNil)
+	c.Assert(spec.Ref, Equals, "")
+	c.Assert(spec.PartitionBy, DeepEquals, []expression.Expression{id})
+	c.Assert(spec.OrderBy, HasLen, 1)
+	// the order by items are copied.
+	c.Assert(spec.OrderBy[0], Not(Equals), windows["w2"].OrderBy[0])
+
+	cases := []struct {
+		spec *WindowSpec
+		code uint16
+	}{
+		{&WindowSpec{Ref: "w"}, mysql.ErWindowNoSuchWindow},
+		{&WindowSpec{Ref: "w3"}, mysql.ErWindowCircularityInWindowGraph},
+		{&WindowSpec{Ref: "w1", PartitionBy: []expression.Expression{id}}, mysql.ErWindowNoChildPartitioning},
+		{&WindowSpec{Ref: "w5"}, mysql.ErWindowNoInherentFrame},
+		{&WindowSpec{Ref: "w2", OrderBy: []*WindowOrderByItem{{Expr: id}}}, mysql.ErWindowNoRedefineOrderBy},
+	}
+	for _, ca := range cases {
+		err := ca.spec.Resolve(windows)
+		c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, ca.code)
+	}
+}
+
+func (s *testWindowSuite) TestWindowFrame(c *C) {
+	id := &Ident{CIStr: model.NewCIStr("id")}
+	byID := []*WindowOrderByItem{{Expr: id, Asc: true}}
+	bound := func(tp FrameBoundType, v interface{}) FrameBound {
+		if v == nil {
+			return FrameBound{Type: tp}
+		}
+		return FrameBound{Type: tp, Expr: Value{v}}
+	}
+
+	c.Assert((&WindowSpec{}).Check(), IsNil)
+
+	cases := []struct {
+		unit       FrameUnit
+		start, end FrameBound
+		orderBy    []*WindowOrderByItem
+		code       uint16
+	}{
+		{FrameRows, bound(Preceding, int64(2)), bound(Following, uint64(1)), nil, 0},
+		{FrameRange, bound(Preceding, 1.5), bound(CurrentRow, nil), byID, 0},
+		{FrameRange, bound(UnboundedPreceding, nil), bound(UnboundedFollowing, nil), nil, 0},
+		{FrameRows, bound(UnboundedFollowing, nil), bound(UnboundedFollowing, nil), nil, mysql.ErWindowFrameStartIllegal},
+		{FrameRows, bound(CurrentRow, nil), bound(UnboundedPreceding, nil), nil, mysql.ErWindowFrameEndIllegal},
+		{FrameRows, bound(Preceding, 1.5), bound(CurrentRow, nil), nil, mysql.ErWindowFrameIllegal},
+		{FrameRows, bound(Preceding, "1"), bound(CurrentRow, nil), nil, mysql.ErWindowFrameIllegal},
+		{FrameRange, bound(Preceding, int64(-1)), bound(CurrentRow, nil), byID, mysql.ErWindowFrameIllegal},
+		{FrameRange, bound(Preceding, int64(1)), bound(CurrentRow, nil), nil, mysql.ErWindowRangeFrameOrderType},
+	}
+	for _, ca := range cases {
+		spec := &WindowSpec{OrderBy: ca.orderBy, Frame: &WindowFrame{Unit: ca.unit, Start: ca.start, End: ca.end}}
+		err := spec.Check()
+		if ca.code == 0 {
+			c.Assert(err, IsNil, Commentf("%s", spec))
+			continue
+		}
+		c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, ca.code, Commentf("%s", spec))
+	}
+
+	v, err := bound(Following, uint64(3)).Offset()
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, float64(3))
+	c.Assert(bound(Preceding, int64(3)).String(), Equals, "3 PRECEDING")
+	c.Assert(bound(CurrentRow, nil).String(), Equals, "CURRENT ROW")
+}
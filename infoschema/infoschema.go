@@ -16,12 +16,14 @@ package infoschema
 import (
 	"encoding/json"
 	"sync/atomic"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/Dong-Chan/alloydb/kv"
 	"github.com/Dong-Chan/alloydb/meta/autoid"
 	"github.com/Dong-Chan/alloydb/model"
 	"github.com/Dong-Chan/alloydb/table"
+	"github.com/Dong-Chan/alloydb/util/metrics"
 )
 
 // InfoSchema is the interface used to retrieve the schema information.
@@ -185,6 +187,14 @@ type Handle struct {
 	store kv.Storage
 }
 
+var reloadDuration = metrics.NewHistogram("alloydb_infoschema_reload_duration_seconds",
+	"The time of building the information schema from the schema infos, it is done when the schemas are loaded or changed.",
+	metrics.DefBuckets)
+
+func init() {
+	metrics.MustRegister(reloadDuration)
+}
+
 // NewHandle creates a new Handle.
 func NewHandle(store kv.Storage) *Handle {
 	return &Handle{
@@ -194,6 +204,7 @@ func NewHandle(store kv.Storage) *Handle {
 
 // Set sets DBInfo to information schema.
 func (h *Handle) Set(newInfo []*model.DBInfo) {
+	defer reloadDuration.ObserveSince(time.Now())
 	info := &infoSchema{
 		schemaNameToID: map[string]int64{},
 		tableNameToID:  map[tableName]int64{},
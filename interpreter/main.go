@@ -31,9 +31,10 @@ import (
 )
 
 var (
-	logLevel = flag.String("L", "error", "log level")
-	store    = flag.String("store", "goleveldb", "the name for the registered storage, e.g. memory, goleveldb, boltdb")
-	dbPath   = flag.String("dbpath", "test", "db path")
+	logLevel   = flag.String("L", "error", "log level")
+	store      = flag.String("store", "goleveldb", "the name for the registered storage, e.g. memory, goleveldb, boltdb")
+	dbPath     = flag.String("dbpath", "test", "db path")
+	statusAddr = flag.String("status", ":8888", "the address of the status server serving metrics and pprof, disabled if empty")
 
 	line        *liner.State
 	historyPath = "/tmp/tidb_interpreter"
@@ -143,6 +144,12 @@ func main() {
 	// support for signal notify
 	runtime.GOMAXPROCS(runtime.NumCPU())
 
+	if len(*statusAddr) > 0 {
+		if err := alloydb.StartStatusServer(*statusAddr); err != nil {
+			log.Error(errors.ErrorStack(err))
+		}
+	}
+
 	line = liner.NewLiner()
 	defer line.Close()
 
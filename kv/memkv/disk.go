@@ -0,0 +1,281 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memkv
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/util/codec"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+// diskTemp is a Temp saved in a temporary goleveldb directory,
+// it is used when the data is too large to be kept in memory.
+type diskTemp struct {
+	asc bool
+	dir string
+	db  *leveldb.DB
+}
+
+// CreateDiskTemp returns a new empty kv saved in a temporary directory,
+// the directory is removed when the kv is dropped.
+func CreateDiskTemp(asc bool) (_ Temp, err error) {
+	dir, err := ioutil.TempDir("", "alloydb-temp")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	db, err := leveldb.OpenFile(dir, &opt.Options{ErrorIfExist: true})
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, errors.Trace(err)
+	}
+
+	return &diskTemp{
+		asc: asc,
+		dir: dir,
+		db:  db,
+	}, nil
+}
+
+// encodeDiskKey encodes k into an ordered key.
+// Like saving a row into a table, the types not supported by codec are flattened first,
+// and the numbers are normalized, so the values equal by types.Collators, like 1, 1.0 and
+// 1.00 of different types, have the same key, and the keys are ordered by their values.
+func encodeDiskKey(k []interface{}) ([]byte, error) {
+	flat := make([]interface{}, len(k))
+	for i, v := range k {
+		var num string
+		switch x := v.(type) {
+		case int:
+			num = strconv.FormatInt(int64(x), 10)
+		case int8:
+			num = strconv.FormatInt(int64(x), 10)
+		case int16:
+			num = strconv.FormatInt(int64(x), 10)
+		case int32:
+			num = strconv.FormatInt(int64(x), 10)
+		case int64:
+			num = strconv.FormatInt(x, 10)
+		case uint:
+			num = strconv.FormatUint(uint64(x), 10)
+		case uint8:
+			num = strconv.FormatUint(uint64(x), 10)
+		case uint16:
+			num = strconv.FormatUint(uint64(x), 10)
+		case uint32:
+			num = strconv.FormatUint(uint64(x), 10)
+		case uint64:
+			num = strconv.FormatUint(x, 10)
+		case float32:
+			num = strconv.FormatFloat(float64(x), 'f', -1, 32)
+		case float64:
+			num = strconv.FormatFloat(x, 'f', -1, 64)
+		case mysql.Decimal:
+			// All the digits are kept, String rounds the value to the fractional digits for display.
+			var places int32
+			if exp := x.Exponent(); exp < 0 {
+				places = -exp
+			}
+			num = x.StringFixed(places)
+		case mysql.Duration:
+			num = strconv.FormatInt(int64(x.Duration), 10)
+		case mysql.Time:
+			// The values of different fsp are equal if their times are equal.
+			x.Fsp = mysql.MaxFsp
+			if x.Type == mysql.TypeDate {
+				x.Type = mysql.TypeDatetime
+			}
+			flat[i] = x
+			continue
+		default:
+			flat[i] = v
+			continue
+		}
+		b, err := encodeDiskNumber(num)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		flat[i] = b
+	}
+	return codec.EncodeKey(flat...)
+}
+
+// The flags of the encoded numbers.
+const (
+	diskNumberNegative byte = iota + 1
+	diskNumberZero
+	diskNumberPositive
+)
+
+// encodeDiskNumber encodes the decimal string num like "-12.50" into bytes ordered by the value of num.
+// The number is normalized as 0.d1d2...dn * 10^exp, d1 is not 0 and dn is not 0. The positive numbers
+// are encoded as the exponent and the digits, the negative numbers are encoded as the negated exponent
+// and the complemented digits with a terminator, so a longer magnitude is ordered before.
+func encodeDiskNumber(num string) ([]byte, error) {
+	neg := strings.HasPrefix(num, "-")
+	digits := strings.TrimLeft(num, "+-")
+	exp := len(digits)
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		exp = i
+		digits = digits[:i] + digits[i+1:]
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return nil, errors.Errorf("invalid number %s", num)
+		}
+	}
+	n := len(digits)
+	digits = strings.TrimLeft(digits, "0")
+	exp -= n - len(digits)
+	digits = strings.TrimRight(digits, "0")
+	if len(digits) == 0 {
+		return []byte{diskNumberZero}, nil
+	}
+
+	if !neg {
+		b := codec.EncodeInt([]byte{diskNumberPositive}, int64(exp))
+		return append(b, digits...), nil
+	}
+	b := codec.EncodeInt([]byte{diskNumberNegative}, -int64(exp))
+	for i := 0; i < len(digits); i++ {
+		b = append(b, ^digits[i])
+	}
+	return append(b, 0xFF), nil
+}
+
+// The value saved is the encoded key length, key and value,
+// so the original key can be returned by the iterator.
+func encodeDiskValue(k, v []interface{}) ([]byte, error) {
+	b, err := codec.EncodeValue(nil, len(k))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if b, err = codec.EncodeValue(b, k...); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return codec.EncodeValue(b, v...)
+}
+
+func decodeDiskValue(b []byte) (k, v []interface{}, err error) {
+	vals, err := codec.DecodeValue(b)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if len(vals) == 0 {
+		return nil, nil, errors.Errorf("invalid encoded temp value")
+	}
+	n, ok := vals[0].(int64)
+	if !ok || n < 0 || int(n) > len(vals)-1 {
+		return nil, nil, errors.Errorf("invalid encoded temp value")
+	}
+	return vals[1 : n+1], vals[n+1:], nil
+}
+
+func (t *diskTemp) Get(k []interface{}) (v []interface{}, err error) {
+	key, err := encodeDiskKey(k)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	b, err := t.db.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	_, v, err = decodeDiskValue(b)
+	return v, errors.Trace(err)
+}
+
+func (t *diskTemp) Drop() (err error) {
+	err = t.db.Close()
+	if rerr := os.RemoveAll(t.dir); rerr != nil && err == nil {
+		err = rerr
+	}
+	return errors.Trace(err)
+}
+
+func (t *diskTemp) Set(k, v []interface{}) (err error) {
+	key, err := encodeDiskKey(k)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	b, err := encodeDiskValue(k, v)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	return errors.Trace(t.db.Put(key, b, nil))
+}
+
+func (t *diskTemp) SeekFirst() (e btreeIterator, err error) {
+	it := t.db.NewIterator(nil, nil)
+	var ok bool
+	if t.asc {
+		ok = it.First()
+	} else {
+		ok = it.Last()
+	}
+	if !ok {
+		err = it.Error()
+		it.Release()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return nil, io.EOF
+	}
+
+	return &diskIterator{it: it, asc: t.asc, valid: true}, nil
+}
+
+type diskIterator struct {
+	it    iterator.Iterator
+	asc   bool
+	valid bool
+}
+
+// Next returns the current key and value, and moves to the next item.
+// io.EOF is returned at the end, and the iterator is released.
+func (e *diskIterator) Next() (k, v []interface{}, err error) {
+	if !e.valid {
+		return nil, nil, io.EOF
+	}
+
+	k, v, err = decodeDiskValue(e.it.Value())
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	if e.asc {
+		e.valid = e.it.Next()
+	} else {
+		e.valid = e.it.Prev()
+	}
+	if !e.valid {
+		err = e.it.Error()
+		e.it.Release()
+	}
+	return k, v, errors.Trace(err)
+}
@@ -42,6 +42,22 @@ func CreateTemp(asc bool) (_ Temp, err error) {
 	}, nil
 }
 
+// CreateTempWithQuota returns a new empty kv which is kept in memory until
+// the approximate size of the data exceeds quota bytes, then all the data is
+// moved to a temporary kv on disk. If quota <= 0, the kv is always in memory.
+func CreateTempWithQuota(asc bool, quota int64) (_ Temp, err error) {
+	mem, err := CreateTemp(asc)
+	if err != nil || quota <= 0 {
+		return mem, err
+	}
+
+	return &spillTemp{
+		asc:   asc,
+		quota: quota,
+		mem:   mem.(*memTemp),
+	}, nil
+}
+
 func (t *memTemp) Get(k []interface{}) (v []interface{}, err error) {
 	v, _ = t.tree.Get(k)
 	return
@@ -66,3 +82,74 @@ func (t *memTemp) SeekFirst() (e btreeIterator, err error) {
 
 	return it, nil
 }
+
+// spillTemp saves data in a memTemp first, and spills it to a diskTemp when the quota is exceeded.
+type spillTemp struct {
+	asc   bool
+	quota int64
+	// size is the approximate memory size of the data set in mem.
+	size int64
+	mem  *memTemp
+	disk Temp
+}
+
+func (t *spillTemp) Get(k []interface{}) (v []interface{}, err error) {
+	if t.disk != nil {
+		return t.disk.Get(k)
+	}
+	return t.mem.Get(k)
+}
+
+func (t *spillTemp) Drop() (err error) {
+	if t.disk != nil {
+		return t.disk.Drop()
+	}
+	return t.mem.Drop()
+}
+
+func (t *spillTemp) Set(k, v []interface{}) (err error) {
+	if t.disk != nil {
+		return t.disk.Set(k, v)
+	}
+
+	if err = t.mem.Set(k, v); err != nil {
+		return err
+	}
+
+	// Overwriting an existing key is counted again, so the size may be larger than the real one.
+	t.size += types.EstimateSize(k) + types.EstimateSize(v)
+	if t.size <= t.quota {
+		return nil
+	}
+	return t.spill()
+}
+
+// spill moves all the data in memory to disk.
+func (t *spillTemp) spill() (err error) {
+	disk, err := CreateDiskTemp(t.asc)
+	if err != nil {
+		return err
+	}
+
+	it, err := t.mem.SeekFirst()
+	for err == nil {
+		var k, v []interface{}
+		if k, v, err = it.Next(); err == nil {
+			err = disk.Set(k, v)
+		}
+	}
+	if err = types.EOFAsNil(err); err != nil {
+		disk.Drop()
+		return err
+	}
+
+	t.disk, t.mem, t.size = disk, nil, 0
+	return nil
+}
+
+func (t *spillTemp) SeekFirst() (e btreeIterator, err error) {
+	if t.disk != nil {
+		return t.disk.SeekFirst()
+	}
+	return t.mem.SeekFirst()
+}
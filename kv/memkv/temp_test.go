@@ -14,9 +14,11 @@
 package memkv
 
 import (
+	"io"
 	"testing"
 
 	. "github.com/pingcap/check"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
 )
 
 func TestT(t *testing.T) {
@@ -79,3 +81,129 @@ func (*testTempSuite) TestTemp(c *C) {
 	err = kv.Drop()
 	c.Assert(err, IsNil)
 }
+
+func (*testTempSuite) TestDiskTemp(c *C) {
+	for _, asc := range []bool{true, false} {
+		kv, err := CreateDiskTemp(asc)
+		c.Assert(err, IsNil)
+
+		// Seek in an empty kv
+		_, err = kv.SeekFirst()
+		c.Assert(err, NotNil)
+
+		// Set can't accept invalid type
+		err = kv.Set([]interface{}{1}, []interface{}{InvalidMySQLType{}})
+		c.Assert(err, NotNil)
+
+		for i := 0; i < 10; i++ {
+			err = kv.Set([]interface{}{int64(i)}, []interface{}{string(rune('a'+i)), mysql.NewDecimalFromInt(int64(i), 0)})
+			c.Assert(err, IsNil)
+		}
+
+		v, err := kv.Get([]interface{}{int64(3)})
+		c.Assert(err, IsNil)
+		c.Assert(v, HasLen, 2)
+		c.Assert(v[0], Equals, "d")
+		c.Assert(v[1].(mysql.Decimal).String(), Equals, "3")
+
+		v, err = kv.Get([]interface{}{int64(10)})
+		c.Assert(err, IsNil)
+		c.Assert(v, HasLen, 0)
+
+		iter, err := kv.SeekFirst()
+		c.Assert(err, IsNil)
+		for i := 0; i < 10; i++ {
+			expect := int64(i)
+			if !asc {
+				expect = int64(9 - i)
+			}
+			k, _, err := iter.Next()
+			c.Assert(err, IsNil)
+			c.Assert(k, DeepEquals, []interface{}{expect})
+		}
+		_, _, err = iter.Next()
+		c.Assert(err, Equals, io.EOF)
+
+		err = kv.Drop()
+		c.Assert(err, IsNil)
+	}
+}
+
+func (*testTempSuite) TestTempWithQuota(c *C) {
+	kv, err := CreateTempWithQuota(true, 0)
+	c.Assert(err, IsNil)
+	_, ok := kv.(*memTemp)
+	c.Assert(ok, IsTrue)
+
+	kv, err = CreateTempWithQuota(true, 256)
+	c.Assert(err, IsNil)
+	t := kv.(*spillTemp)
+
+	for i := 0; i < 10; i++ {
+		err = kv.Set([]interface{}{int64(i)}, []interface{}{"hello"})
+		c.Assert(err, IsNil)
+	}
+	c.Assert(t.disk, NotNil)
+	c.Assert(t.mem, IsNil)
+
+	// Set an existing key after spilling.
+	err = kv.Set([]interface{}{int64(0)}, []interface{}{"world"})
+	c.Assert(err, IsNil)
+
+	for i := 0; i < 10; i++ {
+		expect := "hello"
+		if i == 0 {
+			expect = "world"
+		}
+		v, err := kv.Get([]interface{}{int64(i)})
+		c.Assert(err, IsNil)
+		c.Assert(v, DeepEquals, []interface{}{expect})
+	}
+
+	iter, err := kv.SeekFirst()
+	c.Assert(err, IsNil)
+	for i := 0; i < 10; i++ {
+		k, _, err := iter.Next()
+		c.Assert(err, IsNil)
+		c.Assert(k, DeepEquals, []interface{}{int64(i)})
+	}
+
+	err = kv.Drop()
+	c.Assert(err, IsNil)
+}
+
+func (*testTempSuite) TestDiskTempKeys(c *C) {
+	kv, err := CreateDiskTemp(true)
+	c.Assert(err, IsNil)
+	defer kv.Drop()
+
+	decimal := func(s string) mysql.Decimal {
+		d, err := mysql.ParseDecimal(s)
+		c.Assert(err, IsNil)
+		return d
+	}
+	// The equal numbers of different types and scales have the same key.
+	for _, k := range []interface{}{int64(1), decimal("1.0"), decimal("1.00"), uint64(1), float64(1)} {
+		c.Assert(kv.Set([]interface{}{k}, []interface{}{k}), IsNil)
+	}
+	v, err := kv.Get([]interface{}{decimal("1")})
+	c.Assert(err, IsNil)
+	c.Assert(v, DeepEquals, []interface{}{float64(1)})
+
+	// The keys are ordered by the values of the numbers.
+	keys := []interface{}{decimal("-100.5"), int64(-10), decimal("-1.25"), float64(-1.2), decimal("0.00"),
+		decimal("0.05"), float64(0.5), decimal("1.00"), uint64(2), mysql.NewDecimalFromInt(1, 1), decimal("10.01"),
+		float64(1e20)}
+	for i := len(keys) - 1; i >= 0; i-- {
+		c.Assert(kv.Set([]interface{}{keys[i]}, []interface{}{int64(i)}), IsNil)
+	}
+	iter, err := kv.SeekFirst()
+	c.Assert(err, IsNil)
+	for i := range keys {
+		_, v, err := iter.Next()
+		c.Assert(err, IsNil)
+		c.Assert(v, DeepEquals, []interface{}{int64(i)}, Commentf("%v", keys[i]))
+	}
+	_, _, err = iter.Next()
+	c.Assert(err, Equals, io.EOF)
+}
@@ -20,12 +20,24 @@ import (
 	"github.com/ngaut/log"
 	"github.com/Dong-Chan/alloydb/kv"
 	"github.com/Dong-Chan/alloydb/meta"
+	"github.com/Dong-Chan/alloydb/util/metrics"
 )
 
 const (
 	step = 1000
 )
 
+var (
+	allocCounter = metrics.NewCounter("alloydb_autoid_allocations_total",
+		"The number of the allocated auto increment IDs.")
+	allocBatchCounter = metrics.NewCounter("alloydb_autoid_batch_allocations_total",
+		"The number of the batches of auto increment IDs allocated from the store.")
+)
+
+func init() {
+	metrics.MustRegister(allocCounter, allocBatchCounter)
+}
+
 // Allocator is an auto increment id generator.
 // Just keep id unique actually.
 type Allocator interface {
@@ -63,9 +75,11 @@ func (alloc *allocator) Alloc(tableID int64) (int64, error) {
 		if err != nil {
 			return 0, errors.Trace(err)
 		}
+		allocBatchCounter.Inc()
 	}
 
 	alloc.base++
+	allocCounter.Inc()
 	log.Infof("Alloc id %d, table ID:%d, from %p, store ID:%s", alloc.base, tableID, alloc, alloc.store.UUID())
 	return alloc.base, nil
 }
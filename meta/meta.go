@@ -15,6 +15,7 @@ package meta
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/juju/errors"
 	"github.com/ngaut/log"
@@ -30,6 +31,10 @@ const (
 
 var (
 	nextGlobalIDPrefix = []byte("mNextGlobalID")
+	// privilegeVersionKey is increased whenever the privilege tables in the system database change.
+	privilegeVersionKey = []byte("mPrivilegeVersion")
+	// bootstrapVersionKey is the version of the system database, it is set after each bootstrap step.
+	bootstrapVersionKey = []byte("mBootstrapVersion")
 )
 
 // GenID adds step to the value for key and returns the sum.
@@ -77,3 +82,51 @@ func GenGlobalID(store kv.Storage) (ID int64, err error) {
 
 	return
 }
+
+// getVersion gets the int value for key, it is 0 if key does not exist.
+func getVersion(txn kv.Transaction, key []byte) (int64, error) {
+	v, err := txn.Get(key)
+	if kv.IsErrNotFound(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	ver, err := strconv.ParseInt(string(v), 10, 64)
+	return ver, errors.Trace(err)
+}
+
+// GetPrivilegeVersion gets the version of the privilege tables, it is 0 if they have never changed.
+func GetPrivilegeVersion(txn kv.Transaction) (int64, error) {
+	ver, err := getVersion(txn, privilegeVersionKey)
+	return ver, errors.Trace(err)
+}
+
+// IncPrivilegeVersion increases the version of the privilege tables, it is called in the
+// transaction that changes the tables, so the cached privileges are reloaded after it is committed.
+func IncPrivilegeVersion(txn kv.Transaction) (int64, error) {
+	ver, err := GenID(txn, privilegeVersionKey, 1)
+	return ver, errors.Trace(err)
+}
+
+// GetBootstrapVersion gets the version of the system database, it is 0 if the store has never been bootstrapped.
+func GetBootstrapVersion(txn kv.Transaction) (int64, error) {
+	ver, err := getVersion(txn, bootstrapVersionKey)
+	return ver, errors.Trace(err)
+}
+
+// SetBootstrapVersion sets the version of the system database to ver if it is newer than the stored one.
+// The version never goes back, so a slower session finishing an old step does not undo a newer one.
+func SetBootstrapVersion(txn kv.Transaction, ver int64) error {
+	if err := txn.LockKeys(bootstrapVersionKey); err != nil {
+		return errors.Trace(err)
+	}
+	old, err := getVersion(txn, bootstrapVersionKey)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if ver <= old {
+		return nil
+	}
+	return errors.Trace(txn.Set(bootstrapVersionKey, []byte(strconv.FormatInt(ver, 10))))
+}
@@ -65,4 +65,27 @@ func (*testSuite) TestT(c *C) {
 	id, err = meta.GenGlobalID(store)
 	c.Assert(err, IsNil)
 	c.Assert(id, Equals, int64(2))
+
+	// For privilege version
+	ver, err := meta.GetPrivilegeVersion(txn)
+	c.Assert(err, IsNil)
+	c.Assert(ver, Equals, int64(0))
+	ver, err = meta.IncPrivilegeVersion(txn)
+	c.Assert(err, IsNil)
+	c.Assert(ver, Equals, int64(1))
+	ver, err = meta.GetPrivilegeVersion(txn)
+	c.Assert(err, IsNil)
+	c.Assert(ver, Equals, int64(1))
+
+	// For bootstrap version
+	ver, err = meta.GetBootstrapVersion(txn)
+	c.Assert(err, IsNil)
+	c.Assert(ver, Equals, int64(0))
+	err = meta.SetBootstrapVersion(txn, 2)
+	c.Assert(err, IsNil)
+	err = meta.SetBootstrapVersion(txn, 1)
+	c.Assert(err, IsNil)
+	ver, err = meta.GetBootstrapVersion(txn)
+	c.Assert(err, IsNil)
+	c.Assert(ver, Equals, int64(2))
 }
@@ -0,0 +1,69 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alloydb
+
+import (
+	"net"
+	"net/http"
+	// For pprof
+	_ "net/http/pprof"
+	"reflect"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/ngaut/log"
+	"github.com/Dong-Chan/alloydb/stmt"
+	"github.com/Dong-Chan/alloydb/util/metrics"
+)
+
+var (
+	stmtCounter = metrics.NewCounterVec("alloydb_statements_total",
+		"The number of the executed statements by type.", "type")
+	stmtErrorCounter = metrics.NewCounterVec("alloydb_statement_errors_total",
+		"The number of the failed statements by type.", "type")
+	stmtDuration = metrics.NewHistogramVec("alloydb_statement_duration_seconds",
+		"The latency of the statements by type, the time of fetching the rows is included.", metrics.DefBuckets, "type")
+)
+
+func init() {
+	metrics.MustRegister(stmtCounter, stmtErrorCounter, stmtDuration)
+	http.Handle("/metrics", metrics.Handler())
+}
+
+// stmtType returns the name of the type of s without the Stmt suffix, like Select.
+func stmtType(s stmt.Statement) string {
+	if s == nil {
+		return "Unknown"
+	}
+	t := reflect.TypeOf(s)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return strings.TrimSuffix(t.Name(), "Stmt")
+}
+
+// StartStatusServer starts the HTTP server of the status of the engine on addr, it serves
+// the metrics in the Prometheus text format on /metrics and the profiles of pprof on /debug/pprof/.
+func StartStatusServer(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	go func() {
+		if err := http.Serve(l, nil); err != nil {
+			log.Errorf("status server on %s stopped: %v", addr, err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,101 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/juju/errors"
+)
+
+// Bit is the value of the MySQL BIT type and the bit-value literals.
+// See https://dev.mysql.com/doc/refman/5.7/en/bit-type.html
+type Bit struct {
+	Value uint64
+	// Width is the number of the bits.
+	Width int
+}
+
+const (
+	// MinBitWidth is the minimum width of the BIT type.
+	MinBitWidth = 1
+	// MaxBitWidth is the maximum width of the BIT type.
+	MaxBitWidth = 64
+)
+
+// String implements fmt.Stringer interface, it returns the bits like b'0101'.
+func (b Bit) String() string {
+	if b.Width == 0 {
+		return "b''"
+	}
+	return fmt.Sprintf("b'%0*b'", b.Width, b.Value)
+}
+
+// ToNumber returns the value of the bits.
+func (b Bit) ToNumber() float64 {
+	return float64(b.Value)
+}
+
+// ToString returns the binary string of the bits, the bytes are in big endian order.
+func (b Bit) ToString() string {
+	n := (b.Width + 7) / 8
+	buf := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		buf[i] = byte(b.Value)
+		b.Value >>= 8
+	}
+	return string(buf)
+}
+
+// NewBit returns the Bit of width bits with value v.
+func NewBit(v uint64, width int) (Bit, error) {
+	if width < MinBitWidth || width > MaxBitWidth {
+		return Bit{}, errors.Errorf("invalid bit width %d", width)
+	}
+	if width < MaxBitWidth && v >= 1<<uint(width) {
+		return Bit{}, errors.Errorf("bit value %d is too large for width %d", v, width)
+	}
+	return Bit{Value: v, Width: width}, nil
+}
+
+// ParseBit parses the binary digits s, like "0101", to a Bit.
+// If width is less than 1, the width is the number of the digits.
+func ParseBit(s string, width int) (Bit, error) {
+	if width < MinBitWidth {
+		width = len(s)
+	}
+	if width == 0 {
+		// b'' is an empty binary string.
+		return Bit{}, nil
+	}
+	v, err := strconv.ParseUint(s, 2, 64)
+	if err != nil {
+		return Bit{}, errors.Trace(err)
+	}
+	return NewBit(v, width)
+}
+
+// BitFromString returns the Bit of width bits with the binary string s,
+// the first byte of s is the most significant byte.
+func BitFromString(s string, width int) (Bit, error) {
+	if len(s) > 8 {
+		return Bit{}, errors.Errorf("binary string %q is longer than 8 bytes", s)
+	}
+	var v uint64
+	for i := 0; i < len(s); i++ {
+		v = v<<8 | uint64(s[i])
+	}
+	return NewBit(v, width)
+}
@@ -0,0 +1,76 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testBitSuite{})
+
+type testBitSuite struct {
+}
+
+func (s *testBitSuite) TestParseBit(c *C) {
+	tbl := []struct {
+		Input    string
+		Width    int
+		Expected string
+		Value    uint64
+		Str      string
+	}{
+		{"0101", -1, "b'0101'", 5, "\x05"},
+		{"0101", 10, "b'0000000101'", 5, "\x00\x05"},
+		{"01000001", -1, "b'01000001'", 65, "A"},
+		{"", -1, "b''", 0, ""},
+	}
+
+	for _, t := range tbl {
+		b, err := ParseBit(t.Input, t.Width)
+		c.Assert(err, IsNil)
+		c.Assert(b.String(), Equals, t.Expected)
+		c.Assert(b.ToNumber(), Equals, float64(t.Value))
+		c.Assert(b.ToString(), Equals, t.Str)
+	}
+
+	errTbl := []struct {
+		Input string
+		Width int
+	}{
+		{"012", -1},
+		{"111", 2},
+		{"1", 65},
+	}
+
+	for _, t := range errTbl {
+		_, err := ParseBit(t.Input, t.Width)
+		c.Assert(err, NotNil, Commentf("%s", t.Input))
+	}
+}
+
+func (s *testBitSuite) TestBitFromString(c *C) {
+	b, err := BitFromString("AB", 16)
+	c.Assert(err, IsNil)
+	c.Assert(b.Value, Equals, uint64(0x4142))
+	c.Assert(b.ToString(), Equals, "AB")
+
+	_, err = BitFromString("AB", 8)
+	c.Assert(err, NotNil)
+	_, err = BitFromString("123456789", 64)
+	c.Assert(err, NotNil)
+
+	b, err = NewBit(1<<63, 64)
+	c.Assert(err, IsNil)
+	c.Assert(b.ToNumber(), Equals, float64(1<<63))
+}
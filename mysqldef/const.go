@@ -20,6 +20,24 @@ const (
 	ServerVersion      string = "5.5.31-cm-1.0"
 )
 
+// System database and tables informations.
+const (
+	// SystemDB is the name of the database holding the system tables.
+	SystemDB = "mysql"
+	// UserTable is the table of user accounts and their global privileges.
+	UserTable = "user"
+	// DBTable is the table of database level privileges.
+	DBTable = "db"
+	// TablePrivTable is the table of table level privileges.
+	TablePrivTable = "tables_priv"
+	// ColumnPrivTable is the table of column level privileges.
+	ColumnPrivTable = "columns_priv"
+	// GlobalVariablesTable is the table of global system variables.
+	GlobalVariablesTable = "global_variables"
+	// HelpTopicTable is the table of help topics.
+	HelpTopicTable = "help_topic"
+)
+
 // Header informations.
 const (
 	OKHeader          byte = 0x00
@@ -350,6 +350,21 @@ func (d Decimal) Div(d2 Decimal) Decimal {
 	return ret
 }
 
+// Mod returns d % d2, the remainder has the same sign as d.
+// d2 must not be zero.
+func (d Decimal) Mod(d2 Decimal) Decimal {
+	baseExp := min(d.exp, d2.exp)
+	rd := d.rescale(baseExp)
+	rd2 := d2.rescale(baseExp)
+
+	d3Value := new(big.Int).Rem(rd.value, rd2.value)
+	return Decimal{
+		value:      d3Value,
+		exp:        baseExp,
+		fracDigits: fracDigitsPlus(d.fracDigits, d2.fracDigits),
+	}
+}
+
 // Cmp compares the numbers represented by d and d2, and returns:
 //
 //     -1 if d <  d2
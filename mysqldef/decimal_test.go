@@ -1,4 +1,4 @@
-// 
+//
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -644,6 +644,39 @@ func TestDecimal_Div(t *testing.T) {
 	}
 }
 
+func TestDecimal_Mod(t *testing.T) {
+	type Inp struct {
+		a string
+		b string
+	}
+
+	inputs := map[Inp]string{
+		Inp{"3", "2"}:             "1",
+		Inp{"-3", "2"}:            "-1",
+		Inp{"3", "-2"}:            "1",
+		Inp{"5.5", "2"}:           "1.5",
+		Inp{"-5.5", "2"}:          "-1.5",
+		Inp{"10.3", "0.25"}:       "0.05",
+		Inp{"1234567890123", "7"}: "1",
+	}
+
+	for inp, expected := range inputs {
+		num, err := ParseDecimal(inp.a)
+		if err != nil {
+			t.FailNow()
+		}
+		denom, err := ParseDecimal(inp.b)
+		if err != nil {
+			t.FailNow()
+		}
+		got := num.Mod(denom)
+		if got.String() != expected {
+			t.Errorf("expected %s when %v mod %v, got %v",
+				expected, num, denom, got)
+		}
+	}
+}
+
 func TestDecimal_Overflow(t *testing.T) {
 	if !didPanic(func() { NewDecimalFromInt(1, math.MinInt32).Mul(NewDecimalFromInt(1, math.MinInt32)) }) {
 		t.Fatalf("should have gotten an overflow panic")
@@ -0,0 +1,66 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// Enum is the value of the MySQL ENUM type, the name of an element and its index number,
+// which starts from 1. The empty string with the number 0 is the special error value,
+// it is saved instead of an invalid value if strict mode is not enabled.
+// See https://dev.mysql.com/doc/refman/5.7/en/enum.html
+type Enum struct {
+	Name  string
+	Value uint64
+}
+
+// String implements fmt.Stringer interface.
+func (e Enum) String() string {
+	return e.Name
+}
+
+// ToNumber returns the index number of the enum.
+func (e Enum) ToNumber() float64 {
+	return float64(e.Value)
+}
+
+// ParseEnumName returns the element of elems with the name, the trailing spaces are ignored
+// and the letters are compared case insensitively. If no element has the name,
+// the name is parsed as an index number.
+func ParseEnumName(elems []string, name string) (Enum, error) {
+	name = strings.TrimRight(name, " ")
+	for i, n := range elems {
+		if strings.EqualFold(n, name) {
+			return Enum{Name: n, Value: uint64(i) + 1}, nil
+		}
+	}
+
+	// name may be an index number.
+	if num, err := strconv.ParseUint(name, 0, 64); err == nil {
+		return ParseEnumValue(elems, num)
+	}
+	return Enum{}, errors.Errorf("item %s is not in enum %v", name, elems)
+}
+
+// ParseEnumValue returns the element of elems with the index number.
+func ParseEnumValue(elems []string, number uint64) (Enum, error) {
+	if number == 0 || number > uint64(len(elems)) {
+		return Enum{}, errors.Errorf("number %d overflow enum boundary [1, %d]", number, len(elems))
+	}
+	return Enum{Name: elems[number-1], Value: number}, nil
+}
@@ -0,0 +1,56 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testEnumSuite{})
+
+type testEnumSuite struct {
+}
+
+func (s *testEnumSuite) TestEnum(c *C) {
+	elems := []string{"a", "b", "c"}
+	tbl := []struct {
+		Name     string
+		Expected string
+		Value    uint64
+	}{
+		{"a", "a", 1},
+		{"B ", "b", 2},
+		{"3", "c", 3},
+	}
+
+	for _, t := range tbl {
+		e, err := ParseEnumName(elems, t.Name)
+		c.Assert(err, IsNil)
+		c.Assert(e.String(), Equals, t.Expected)
+		c.Assert(e.ToNumber(), Equals, float64(t.Value))
+
+		e, err = ParseEnumValue(elems, t.Value)
+		c.Assert(err, IsNil)
+		c.Assert(e.String(), Equals, t.Expected)
+	}
+
+	errTbl := []string{"d", "0", "4", ""}
+	for _, t := range errTbl {
+		_, err := ParseEnumName(elems, t)
+		c.Assert(err, NotNil, Commentf("%s", t))
+	}
+
+	_, err := ParseEnumValue(elems, 0)
+	c.Assert(err, NotNil)
+}
@@ -882,4 +882,30 @@ const (
 	ErMustChangePasswordLogin                                      = 1862
 	ErRowInWrongPartition                                          = 1863
 	ErErrorLast                                                    = 1863
+
+	// Error codes introduced by MySQL 5.7.
+	ErQueryTimeout             = 3024
+	ErInvalidJSONText          = 3140
+	ErInvalidJSONTextInParam   = 3141
+	ErInvalidJSONPath          = 3143
+	ErInvalidTypeForJSON       = 3146
+	ErInvalidJSONPathWildcard  = 3149
+	ErJSONUsedAsKey            = 3152
+	ErInvalidJSONPathArrayCell = 3165
+
+	// Error codes introduced by MySQL 8.0.
+	ErCteRecursiveRequiresUnion             = 3573
+	ErCteRecursiveRequiresNonrecursiveFirst = 3574
+	ErWindowNoSuchWindow                    = 3579
+	ErWindowCircularityInWindowGraph        = 3580
+	ErWindowNoChildPartitioning             = 3581
+	ErWindowNoInherentFrame                 = 3582
+	ErWindowNoRedefineOrderBy               = 3583
+	ErWindowFrameStartIllegal               = 3584
+	ErWindowFrameEndIllegal                 = 3585
+	ErWindowFrameIllegal                    = 3586
+	ErWindowRangeFrameOrderType             = 3587
+	ErWindowDuplicateName                   = 3591
+	ErWindowInvalidWindowFuncUse            = 3593
+	ErCteMaxRecursionDepth                  = 3636
 )
@@ -109,8 +109,8 @@ var MySQLErrName = map[uint16]string{
 	ErCantDropFieldOrKey:                       "Can't DROP '%-.192s'; check that column/key exists",
 	ErInsertInfo:                               "Records: %ld  Duplicates: %ld  Warnings: %ld",
 	ErUpdateTableUsed:                          "You can't specify target table '%-.192s' for update in FROM clause",
-	ErNoSuchThread:                             "Unknown thread id: %lu",
-	ErKillDeniedError:                          "You are not owner of thread %lu",
+	ErNoSuchThread:                             "Unknown thread id: %d",
+	ErKillDeniedError:                          "You are not owner of thread %d",
 	ErNoTablesUsed:                             "No tables used",
 	ErTooBigSet:                                "Too many strings for column %-.192s and SET",
 	ErNoUniqueLogfile:                          "Can't generate a unique log-filename %-.200s.(1-999)\n",
@@ -879,4 +879,26 @@ var MySQLErrName = map[uint16]string{
 	ErAlterOperationNotSupportedReasonNotNull:               "cannot silently convert NULL values, as required in this SQLMODE",
 	ErMustChangePasswordLogin:                               "Your password has expired. To log in you must change it using a client that supports expired passwords.",
 	ErRowInWrongPartition:                                   "Found a row in wrong partition %s",
+	ErQueryTimeout:                                          "Query execution was interrupted, maximum statement execution time exceeded",
+	ErInvalidJSONText:                                       "Invalid JSON text: \"%s\" at position %d in value for column '%s'.",
+	ErInvalidJSONTextInParam:                                "Invalid JSON text in argument %d to function %s: \"%s\" at position %d.",
+	ErInvalidJSONPath:                                       "Invalid JSON path expression. The error is around character position %d.",
+	ErInvalidTypeForJSON:                                    "Invalid data type for JSON data in argument %d to function %s; a JSON string or JSON type is required.",
+	ErInvalidJSONPathWildcard:                               "In this situation, path expressions may not contain the * and ** tokens.",
+	ErJSONUsedAsKey:                                         "JSON column '%-.192s' cannot be used in key specification.",
+	ErInvalidJSONPathArrayCell:                              "A path expression is not a path to a cell in an array.",
+	ErCteRecursiveRequiresUnion:                             "Recursive Common Table Expression '%s' should contain a UNION",
+	ErCteRecursiveRequiresNonrecursiveFirst:                 "Recursive Common Table Expression '%s' should have one or more non-recursive query blocks followed by one or more recursive ones",
+	ErWindowNoSuchWindow:                                    "Window name '%s' is not defined.",
+	ErWindowCircularityInWindowGraph:                        "There is a circularity in the window dependency graph.",
+	ErWindowNoChildPartitioning:                             "A window which depends on another cannot define partitioning.",
+	ErWindowNoInherentFrame:                                 "Window '%s' has a frame definition, so cannot be referenced by another window.",
+	ErWindowNoRedefineOrderBy:                               "Window '%s' cannot inherit '%s' since both contain an ORDER BY clause.",
+	ErWindowFrameStartIllegal:                               "Window '%s': frame start cannot be UNBOUNDED FOLLOWING.",
+	ErWindowFrameEndIllegal:                                 "Window '%s': frame end cannot be UNBOUNDED PRECEDING.",
+	ErWindowFrameIllegal:                                    "Window '%s': frame start or end is negative, NULL or of non-integral type",
+	ErWindowRangeFrameOrderType:                             "Window '%s' with RANGE N PRECEDING/FOLLOWING frame requires exactly one ORDER BY expression, of numeric or temporal type",
+	ErWindowDuplicateName:                                   "Window '%s' is defined twice.",
+	ErWindowInvalidWindowFuncUse:                            "You cannot use the window function '%s' in this context.'",
+	ErCteMaxRecursionDepth:                                  "Recursive query aborted after %d iterations. Try increasing @@cte_max_recursion_depth to a larger value.",
 }
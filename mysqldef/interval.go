@@ -0,0 +1,396 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// Time units of the fields of an interval, the compound units like DAY_HOUR
+// consist of the fields from the first unit to the second one in this order.
+var timeUnitFields = []string{"YEAR", "MONTH", "DAY", "HOUR", "MINUTE", "SECOND", "MICROSECOND"}
+
+// compoundTimeUnits is the set of compound units, like DAY_HOUR.
+// See: https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-add
+var compoundTimeUnits = map[string]bool{
+	"SECOND_MICROSECOND": true,
+	"MINUTE_MICROSECOND": true,
+	"MINUTE_SECOND":      true,
+	"HOUR_MICROSECOND":   true,
+	"HOUR_SECOND":        true,
+	"HOUR_MINUTE":        true,
+	"DAY_MICROSECOND":    true,
+	"DAY_SECOND":         true,
+	"DAY_MINUTE":         true,
+	"DAY_HOUR":           true,
+	"YEAR_MONTH":         true,
+}
+
+// IsTimeUnit checks whether unit is a valid time unit of INTERVAL, EXTRACT and TIMESTAMPDIFF, like DAY or DAY_HOUR.
+func IsTimeUnit(unit string) bool {
+	unit = strings.ToUpper(unit)
+	return IsSimpleTimeUnit(unit) || compoundTimeUnits[unit]
+}
+
+// IsSimpleTimeUnit checks whether unit is a time unit which is not a compound one, like DAY or WEEK.
+func IsSimpleTimeUnit(unit string) bool {
+	switch strings.ToUpper(unit) {
+	case "WEEK", "QUARTER":
+		return true
+	}
+	return timeUnitIndex(unit) >= 0
+}
+
+func timeUnitIndex(unit string) int {
+	unit = strings.ToUpper(unit)
+	for i, u := range timeUnitFields {
+		if u == unit {
+			return i
+		}
+	}
+	return -1
+}
+
+// timeUnitRange returns the indexes of the first and the last fields of a simple or compound unit in timeUnitFields.
+func timeUnitRange(unit string) (int, int) {
+	unit = strings.ToUpper(unit)
+	if compoundTimeUnits[unit] {
+		seps := strings.SplitN(unit, "_", 2)
+		return timeUnitIndex(seps[0]), timeUnitIndex(seps[1])
+	}
+	i := timeUnitIndex(unit)
+	return i, i
+}
+
+// Interval is the value of INTERVAL expr unit.
+// See: https://dev.mysql.com/doc/refman/5.7/en/expressions.html#temporal-intervals
+type Interval struct {
+	// Months includes the years.
+	Months int64
+	Days   int64
+	// Duration includes the hours, minutes, seconds and microseconds.
+	Duration time.Duration
+	// Fsp is the fractional seconds precision of the interval.
+	Fsp int
+	// HasTime is set if the unit has hours, minutes, seconds or microseconds.
+	HasTime bool
+}
+
+// Neg returns the negative interval.
+func (iv Interval) Neg() Interval {
+	iv.Months, iv.Days, iv.Duration = -iv.Months, -iv.Days, -iv.Duration
+	return iv
+}
+
+// ParseInterval parses the interval of str in unit.
+// For a simple unit, str is a number, it is rounded to an integer except for SECOND.
+// For a compound unit, str is like 'DAYS HOURS:MINUTES', any punctuation can be the delimiter,
+// and if it has fewer parts than the unit, the leftmost parts are assumed to be left out.
+func ParseInterval(str string, unit string) (Interval, error) {
+	var iv Interval
+	unit = strings.ToUpper(unit)
+	if !IsTimeUnit(unit) {
+		return iv, errors.Errorf("invalid time unit %s", unit)
+	}
+
+	str = strings.TrimSpace(str)
+	switch unit {
+	case "WEEK", "QUARTER":
+		n, err := parseIntervalNum(str)
+		if err != nil {
+			return iv, errors.Trace(err)
+		}
+		if unit == "WEEK" {
+			iv.Days = int64(math.Floor(n+0.5)) * 7
+		} else {
+			iv.Months = int64(math.Floor(n+0.5)) * 3
+		}
+		return iv, nil
+	case "SECOND":
+		n, err := parseIntervalNum(str)
+		if err != nil {
+			return iv, errors.Trace(err)
+		}
+		micro := int64(math.Floor(math.Abs(n)*1e6 + 0.5))
+		if n < 0 {
+			micro = -micro
+		}
+		iv.Duration = time.Duration(micro) * time.Microsecond
+		iv.HasTime = true
+		if micro%1e6 != 0 {
+			iv.Fsp = MaxFsp
+		}
+		return iv, nil
+	}
+
+	first, last := timeUnitRange(unit)
+	fields := make([]int64, last-first+1)
+	if first == last {
+		n, err := parseIntervalNum(str)
+		if err != nil {
+			return iv, errors.Trace(err)
+		}
+		fields[0] = int64(math.Floor(math.Abs(n) + 0.5))
+		if n < 0 {
+			fields[0] = -fields[0]
+		}
+	} else {
+		neg := strings.HasPrefix(str, "-")
+		nums, err := splitIntervalNums(str)
+		if err != nil {
+			return iv, errors.Trace(err)
+		}
+		if len(nums) > len(fields) {
+			return iv, errors.Errorf("invalid interval value %s for %s", str, unit)
+		}
+		// The leftmost parts are left out.
+		copy(fields[len(fields)-len(nums):], nums)
+		if neg {
+			for i := range fields {
+				fields[i] = -fields[i]
+			}
+		}
+	}
+
+	for i, n := range fields {
+		switch timeUnitFields[first+i] {
+		case "YEAR":
+			iv.Months += n * 12
+		case "MONTH":
+			iv.Months += n
+		case "DAY":
+			iv.Days += n
+		case "HOUR":
+			iv.Duration += time.Duration(n) * time.Hour
+		case "MINUTE":
+			iv.Duration += time.Duration(n) * time.Minute
+		case "SECOND":
+			iv.Duration += time.Duration(n) * time.Second
+		case "MICROSECOND":
+			iv.Duration += time.Duration(n) * time.Microsecond
+			iv.Fsp = MaxFsp
+		}
+	}
+	iv.HasTime = last >= timeUnitIndex("HOUR")
+	return iv, nil
+}
+
+// parseIntervalNum parses the number at the beginning of str, like MySQL, 0 is used if there is no number.
+func parseIntervalNum(str string) (float64, error) {
+	end := 0
+	for end < len(str) && strings.IndexByte("+-.0123456789eE", str[end]) >= 0 {
+		end++
+	}
+	for end > 0 {
+		n, err := strconv.ParseFloat(str[:end], 64)
+		if err == nil {
+			return n, nil
+		}
+		end--
+	}
+	return 0, nil
+}
+
+// splitIntervalNums splits the numbers in str delimited by any non-digit characters.
+func splitIntervalNums(str string) ([]int64, error) {
+	var nums []int64
+	fields := strings.FieldsFunc(str, func(c rune) bool {
+		return c < '0' || c > '9'
+	})
+	for _, s := range fields {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		nums = append(nums, n)
+	}
+	return nums, nil
+}
+
+// AddInterval adds the interval to the time. Like MySQL, if the day of the result is out of
+// the range of the result month, it is the last day of the month, e.g, 2010-01-31 + 1 month is 2010-02-28.
+// The date type result is a datetime if the interval has time parts.
+func (t Time) AddInterval(iv Interval) (Time, error) {
+	if t.IsZero() {
+		return t, errors.Trace(ErrInvalidTimeFormat)
+	}
+
+	tm := t.Time
+	if iv.Months != 0 {
+		year, month, day := tm.Date()
+		hour, minute, second := tm.Clock()
+		months := int64(year)*12 + int64(month) - 1 + iv.Months
+		if months < 0 {
+			return t, errors.Errorf("datetime overflow")
+		}
+		year, month = int(months/12), time.Month(months%12+1)
+		if n := daysInMonth(year, month); day > n {
+			day = n
+		}
+		tm = time.Date(year, month, day, hour, minute, second, tm.Nanosecond(), tm.Location())
+	}
+	tm = tm.AddDate(0, 0, int(iv.Days)).Add(iv.Duration)
+
+	if tm.Year() < 1 || tm.Year() > 9999 {
+		return t, errors.Errorf("datetime overflow")
+	}
+
+	t.Time = tm
+	if t.Type == TypeTimestamp || (t.Type == TypeDate && iv.HasTime) {
+		t.Type = TypeDatetime
+	}
+	if t.Type != TypeDate && iv.Fsp > t.Fsp {
+		t.Fsp = iv.Fsp
+	}
+	return t, nil
+}
+
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// ExtractTimeNum extracts the part of unit from the time, like EXTRACT(unit FROM t).
+// A compound unit is the concatenation of the parts, e.g, DAY_MINUTE of 2009-07-02 13:02:03 is 21302.
+func (t Time) ExtractTimeNum(unit string) (int64, error) {
+	unit = strings.ToUpper(unit)
+	if !IsTimeUnit(unit) {
+		return 0, errors.Errorf("invalid time unit %s", unit)
+	}
+
+	if t.IsZero() {
+		return 0, nil
+	}
+
+	switch unit {
+	case "WEEK":
+		return int64(t.Week(0)), nil
+	case "QUARTER":
+		return int64((t.Month()-1)/3 + 1), nil
+	}
+
+	first, last := timeUnitRange(unit)
+	var n int64
+	for i := first; i <= last; i++ {
+		switch timeUnitFields[i] {
+		case "YEAR":
+			n = int64(t.Year())
+		case "MONTH":
+			n = n*100 + int64(t.Month())
+		case "DAY":
+			n = n*100 + int64(t.Day())
+		case "HOUR":
+			n = n*100 + int64(t.Hour())
+		case "MINUTE":
+			n = n*100 + int64(t.Minute())
+		case "SECOND":
+			n = n*100 + int64(t.Second())
+		case "MICROSECOND":
+			n = n*1000000 + int64(t.Nanosecond()/1000)
+		}
+	}
+	return n, nil
+}
+
+// ExtractTimeNum extracts the part of unit from the duration, like EXTRACT(unit FROM d),
+// unit must have only the time parts, like HOUR or MINUTE_SECOND.
+func (d Duration) ExtractTimeNum(unit string) (int64, error) {
+	unit = strings.ToUpper(unit)
+	first, last := timeUnitRange(unit)
+	if !IsTimeUnit(unit) || first < timeUnitIndex("HOUR") {
+		return 0, errors.Errorf("invalid time unit %s for time", unit)
+	}
+
+	sign, hour, minute, second, frac := splitDuration(d.Duration)
+	var n int64
+	for i := first; i <= last; i++ {
+		switch timeUnitFields[i] {
+		case "HOUR":
+			n = int64(hour)
+		case "MINUTE":
+			n = n*100 + int64(minute)
+		case "SECOND":
+			n = n*100 + int64(second)
+		case "MICROSECOND":
+			n = n*1000000 + int64(frac)
+		}
+	}
+	return int64(sign) * n, nil
+}
+
+// TimestampDiff returns t2 - t1 in unit, like TIMESTAMPDIFF(unit, t1, t2), unit must be a simple unit.
+// For MONTH, QUARTER and YEAR, only the complete months are counted.
+func TimestampDiff(unit string, t1, t2 Time) (int64, error) {
+	unit = strings.ToUpper(unit)
+	switch unit {
+	case "MONTH", "QUARTER", "YEAR":
+		months := monthDiff(t1.Time, t2.Time)
+		switch unit {
+		case "QUARTER":
+			return months / 3, nil
+		case "YEAR":
+			return months / 12, nil
+		}
+		return months, nil
+	}
+
+	// Compare the wall clocks in the same location.
+	d := t2.AtLocation(time.UTC).Sub(t1.AtLocation(time.UTC).Time)
+	switch unit {
+	case "MICROSECOND":
+		return int64(d / time.Microsecond), nil
+	case "SECOND":
+		return int64(d / time.Second), nil
+	case "MINUTE":
+		return int64(d / time.Minute), nil
+	case "HOUR":
+		return int64(d / time.Hour), nil
+	case "DAY":
+		return int64(d / (24 * time.Hour)), nil
+	case "WEEK":
+		return int64(d / (7 * 24 * time.Hour)), nil
+	}
+	return 0, errors.Errorf("invalid time unit %s for TIMESTAMPDIFF", unit)
+}
+
+// monthDiff returns the number of complete months from t1 to t2.
+func monthDiff(t1, t2 time.Time) int64 {
+	neg := false
+	if t2.Before(t1) {
+		t1, t2 = t2, t1
+		neg = true
+	}
+
+	y1, m1, _ := t1.Date()
+	y2, m2, _ := t2.Date()
+	months := int64(y2-y1)*12 + int64(m2-m1)
+	// The last month is not complete if the rest of t2 is before the one of t1.
+	if months > 0 && restOfMonth(t2).Before(restOfMonth(t1)) {
+		months--
+	}
+	if neg {
+		return -months
+	}
+	return months
+}
+
+// restOfMonth returns the day and clock of t in a fixed month for comparison.
+func restOfMonth(t time.Time) time.Time {
+	hour, minute, second := t.Clock()
+	return time.Date(2000, 1, t.Day(), hour, minute, second, t.Nanosecond(), time.UTC)
+}
@@ -0,0 +1,181 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testIntervalSuite{})
+
+type testIntervalSuite struct {
+}
+
+func (s *testIntervalSuite) TestParseInterval(c *C) {
+	table := []struct {
+		Str      string
+		Unit     string
+		Months   int64
+		Days     int64
+		Duration time.Duration
+		Fsp      int
+	}{
+		{"1", "year", 12, 0, 0, 0},
+		{"2", "QUARTER", 6, 0, 0, 0},
+		{"-3", "MONTH", -3, 0, 0, 0},
+		{"1.5", "DAY", 0, 2, 0, 0},
+		{"2", "WEEK", 0, 14, 0, 0},
+		{"10", "HOUR", 0, 0, 10 * time.Hour, 0},
+		{"1.5", "SECOND", 0, 0, 1500 * time.Millisecond, 6},
+		{"5", "MICROSECOND", 0, 0, 5 * time.Microsecond, 6},
+		{"1-2", "YEAR_MONTH", 14, 0, 0, 0},
+		{"1 2", "DAY_HOUR", 0, 1, 2 * time.Hour, 0},
+		{"-1 1:1:1", "DAY_SECOND", 0, -1, -(time.Hour + time.Minute + time.Second), 0},
+		{"1:1", "DAY_SECOND", 0, 0, time.Minute + time.Second, 0},
+		{"1.000002", "SECOND_MICROSECOND", 0, 0, time.Second + 2*time.Microsecond, 6},
+		{"abc", "DAY", 0, 0, 0, 0},
+	}
+
+	for _, t := range table {
+		iv, err := ParseInterval(t.Str, t.Unit)
+		c.Assert(err, IsNil, Commentf("%s %s", t.Str, t.Unit))
+		c.Assert(iv.Months, Equals, t.Months, Commentf("%s %s", t.Str, t.Unit))
+		c.Assert(iv.Days, Equals, t.Days, Commentf("%s %s", t.Str, t.Unit))
+		c.Assert(iv.Duration, Equals, t.Duration, Commentf("%s %s", t.Str, t.Unit))
+		c.Assert(iv.Fsp, Equals, t.Fsp, Commentf("%s %s", t.Str, t.Unit))
+	}
+
+	_, err := ParseInterval("1", "DAYS")
+	c.Assert(err, NotNil)
+	_, err = ParseInterval("1 2 3", "DAY_HOUR")
+	c.Assert(err, NotNil)
+}
+
+func (s *testIntervalSuite) TestAddInterval(c *C) {
+	table := []struct {
+		Input  string
+		Tp     byte
+		Str    string
+		Unit   string
+		Expect string
+	}{
+		{"2010-01-31", TypeDate, "1", "MONTH", "2010-02-28"},
+		{"2012-01-31", TypeDate, "1", "MONTH", "2012-02-29"},
+		{"2012-02-29", TypeDate, "1", "YEAR", "2013-02-28"},
+		{"2010-03-31", TypeDate, "-1", "QUARTER", "2009-12-31"},
+		{"2010-12-31", TypeDate, "1", "DAY", "2011-01-01"},
+		{"2010-12-31", TypeDate, "1", "HOUR", "2010-12-31 01:00:00"},
+		{"2010-12-31 23:59:59", TypeDatetime, "1", "SECOND", "2011-01-01 00:00:00"},
+		{"2010-12-31 23:59:59", TypeDatetime, "1.5", "SECOND", "2011-01-01 00:00:00.500000"},
+		{"2010-12-31 23:59:59", TypeDatetime, "1 1:1:1", "DAY_SECOND", "2011-01-02 01:01:00"},
+		{"2010-12-31 23:59:59", TypeTimestamp, "-1", "WEEK", "2010-12-24 23:59:59"},
+	}
+
+	for _, t := range table {
+		tm, err := ParseTime(t.Input, t.Tp, DefaultFsp)
+		c.Assert(err, IsNil)
+		iv, err := ParseInterval(t.Str, t.Unit)
+		c.Assert(err, IsNil)
+		v, err := tm.AddInterval(iv)
+		c.Assert(err, IsNil)
+		c.Assert(v.String(), Equals, t.Expect, Commentf("%s + %s %s", t.Input, t.Str, t.Unit))
+	}
+
+	tm, err := ParseDate("9999-12-31")
+	c.Assert(err, IsNil)
+	_, err = tm.AddInterval(Interval{Days: 1})
+	c.Assert(err, NotNil)
+
+	_, err = Time{Time: ZeroTime, Type: TypeDate}.AddInterval(Interval{Days: 1})
+	c.Assert(err, NotNil)
+}
+
+func (s *testIntervalSuite) TestExtractTimeNum(c *C) {
+	tm, err := ParseTime("2009-07-02 13:02:03.000123", TypeDatetime, MaxFsp)
+	c.Assert(err, IsNil)
+
+	table := []struct {
+		Unit   string
+		Expect int64
+	}{
+		{"YEAR", 2009},
+		{"QUARTER", 3},
+		{"MONTH", 7},
+		{"WEEK", 26},
+		{"DAY", 2},
+		{"HOUR", 13},
+		{"MINUTE", 2},
+		{"SECOND", 3},
+		{"MICROSECOND", 123},
+		{"YEAR_MONTH", 200907},
+		{"DAY_HOUR", 213},
+		{"DAY_MINUTE", 21302},
+		{"DAY_SECOND", 2130203},
+		{"HOUR_MINUTE", 1302},
+		{"MINUTE_SECOND", 203},
+		{"SECOND_MICROSECOND", 3000123},
+	}
+	for _, t := range table {
+		v, err := tm.ExtractTimeNum(t.Unit)
+		c.Assert(err, IsNil)
+		c.Assert(v, Equals, t.Expect, Commentf("%s", t.Unit))
+	}
+
+	_, err = tm.ExtractTimeNum("DAYS")
+	c.Assert(err, NotNil)
+
+	d, err := ParseDuration("-25:02:03.5", MaxFsp)
+	c.Assert(err, IsNil)
+	v, err := d.ExtractTimeNum("HOUR")
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, int64(-25))
+	v, err = d.ExtractTimeNum("hour_microsecond")
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, int64(-250203500000))
+	_, err = d.ExtractTimeNum("DAY_HOUR")
+	c.Assert(err, NotNil)
+}
+
+func (s *testIntervalSuite) TestTimestampDiff(c *C) {
+	table := []struct {
+		T1     string
+		T2     string
+		Unit   string
+		Expect int64
+	}{
+		{"2003-02-01", "2003-05-01", "MONTH", 3},
+		{"2003-02-01", "2003-05-01 12:05:55", "MONTH", 3},
+		{"2003-02-02", "2003-05-01", "MONTH", 2},
+		{"2003-05-01", "2003-02-02", "MONTH", -2},
+		{"2002-05-01", "2001-01-01", "YEAR", -1},
+		{"2003-01-01", "2003-12-31", "QUARTER", 3},
+		{"2003-02-01", "2003-05-01 12:05:55", "MINUTE", 128885},
+		{"2003-02-01", "2003-02-08", "WEEK", 1},
+		{"2003-02-01 00:00:00", "2003-02-01 00:00:01.5", "MICROSECOND", 1500000},
+	}
+	for _, t := range table {
+		t1, err := ParseTime(t.T1, TypeDatetime, MaxFsp)
+		c.Assert(err, IsNil)
+		t2, err := ParseTime(t.T2, TypeDatetime, MaxFsp)
+		c.Assert(err, IsNil)
+		v, err := TimestampDiff(t.Unit, t1, t2)
+		c.Assert(err, IsNil)
+		c.Assert(v, Equals, t.Expect, Commentf("%s %s %s", t.Unit, t.T1, t.T2))
+	}
+
+	_, err := TimestampDiff("DAY_HOUR", Time{}, Time{})
+	c.Assert(err, NotNil)
+}
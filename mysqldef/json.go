@@ -0,0 +1,518 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/juju/errors"
+)
+
+// JSON is the value of the MySQL JSON type, a JSON document.
+// The document is a tree of the Go values nil (the JSON null literal), bool, int64, uint64, float64,
+// string, []interface{} for arrays and map[string]interface{} for objects.
+// A JSON value is never modified, the functions which change a document return a new one.
+// See https://dev.mysql.com/doc/refman/5.7/en/json.html
+type JSON struct {
+	v interface{}
+}
+
+// JSON type names returned by JSON_TYPE.
+const (
+	JSONTypeNull     = "NULL"
+	JSONTypeBoolean  = "BOOLEAN"
+	JSONTypeInteger  = "INTEGER"
+	JSONTypeUnsigned = "UNSIGNED INTEGER"
+	JSONTypeDouble   = "DOUBLE"
+	JSONTypeString   = "STRING"
+	JSONTypeArray    = "ARRAY"
+	JSONTypeObject   = "OBJECT"
+)
+
+// JSONSyntaxError is the error of an invalid JSON text.
+type JSONSyntaxError struct {
+	Msg string
+	// Offset is the position in the text where the error is found.
+	Offset int64
+}
+
+// Error implements error interface.
+func (e *JSONSyntaxError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Offset)
+}
+
+// ParseJSON parses the JSON text s, a *JSONSyntaxError is returned if s is invalid.
+func ParseJSON(s string) (JSON, error) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		if err == io.EOF {
+			return JSON{}, &JSONSyntaxError{Msg: "The document is empty."}
+		}
+		if e, ok := err.(*json.SyntaxError); ok {
+			return JSON{}, &JSONSyntaxError{Msg: e.Error(), Offset: e.Offset}
+		}
+		return JSON{}, &JSONSyntaxError{Msg: err.Error(), Offset: dec.InputOffset()}
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return JSON{}, &JSONSyntaxError{Msg: "The document root must not be followed by other values.", Offset: dec.InputOffset()}
+	}
+	v, err := fromJSONNumber(v)
+	if err != nil {
+		return JSON{}, errors.Trace(err)
+	}
+	return JSON{v: v}, nil
+}
+
+// fromJSONNumber converts the json.Number values in v to int64, uint64 or float64.
+func fromJSONNumber(v interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case json.Number:
+		s := string(x)
+		if !strings.ContainsAny(s, ".eE") {
+			if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return n, nil
+			}
+			if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+				return n, nil
+			}
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, errors.Errorf("number %s is out of range", s)
+		}
+		return f, nil
+	case []interface{}:
+		for i, e := range x {
+			e, err := fromJSONNumber(e)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			x[i] = e
+		}
+	case map[string]interface{}:
+		for k, e := range x {
+			e, err := fromJSONNumber(e)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			x[k] = e
+		}
+	}
+	return v, nil
+}
+
+// CreateJSON returns the JSON scalar of the SQL value v, a JSON value is returned as it is.
+// A string is a JSON string, it is not parsed as JSON text.
+func CreateJSON(v interface{}) (JSON, error) {
+	switch x := v.(type) {
+	case JSON:
+		return x, nil
+	case nil:
+		return JSON{}, nil
+	case bool:
+		return JSON{v: x}, nil
+	case int:
+		return JSON{v: int64(x)}, nil
+	case int8:
+		return JSON{v: int64(x)}, nil
+	case int16:
+		return JSON{v: int64(x)}, nil
+	case int32:
+		return JSON{v: int64(x)}, nil
+	case int64:
+		return JSON{v: x}, nil
+	case uint:
+		return JSON{v: uint64(x)}, nil
+	case uint8:
+		return JSON{v: uint64(x)}, nil
+	case uint16:
+		return JSON{v: uint64(x)}, nil
+	case uint32:
+		return JSON{v: uint64(x)}, nil
+	case uint64:
+		return JSON{v: x}, nil
+	case float32:
+		return JSON{v: float64(x)}, nil
+	case float64:
+		return JSON{v: x}, nil
+	case Decimal:
+		f, _ := x.Float64()
+		return JSON{v: f}, nil
+	case string:
+		return JSON{v: x}, nil
+	case []byte:
+		return JSON{v: string(x)}, nil
+	case Time:
+		return JSON{v: x.String()}, nil
+	case Duration:
+		return JSON{v: x.String()}, nil
+	case Enum:
+		return JSON{v: x.Name}, nil
+	case Set:
+		return JSON{v: x.Name}, nil
+	case Bit:
+		return JSON{v: x.Value}, nil
+	}
+	return JSON{}, errors.Errorf("cannot convert %v (type %T) to JSON", v, v)
+}
+
+// CreateJSONArray returns the JSON array of the elements.
+func CreateJSONArray(elems []JSON) JSON {
+	a := make([]interface{}, len(elems))
+	for i, e := range elems {
+		a[i] = e.v
+	}
+	return JSON{v: a}
+}
+
+// CreateJSONObject returns the JSON object of the keys and the values,
+// a key given more than once has the last value.
+func CreateJSONObject(keys []string, values []JSON) JSON {
+	m := make(map[string]interface{}, len(keys))
+	for i, k := range keys {
+		m[k] = values[i].v
+	}
+	return JSON{v: m}
+}
+
+// Type returns the type name of the JSON value, like JSON_TYPE does.
+func (j JSON) Type() string {
+	switch j.v.(type) {
+	case nil:
+		return JSONTypeNull
+	case bool:
+		return JSONTypeBoolean
+	case int64:
+		return JSONTypeInteger
+	case uint64:
+		return JSONTypeUnsigned
+	case float64:
+		return JSONTypeDouble
+	case string:
+		return JSONTypeString
+	case []interface{}:
+		return JSONTypeArray
+	default:
+		return JSONTypeObject
+	}
+}
+
+// IsNull returns whether the JSON value is the JSON null literal.
+func (j JSON) IsNull() bool {
+	return j.v == nil
+}
+
+// String implements fmt.Stringer interface, it returns the JSON text like MySQL does,
+// the object members are sorted by the keys.
+func (j JSON) String() string {
+	var buf bytes.Buffer
+	writeJSON(&buf, j.v)
+	return buf.String()
+}
+
+// Unquote returns the string of a JSON string without quotes and escapes,
+// or the JSON text of other values.
+func (j JSON) Unquote() string {
+	if s, ok := j.v.(string); ok {
+		return s
+	}
+	return j.String()
+}
+
+// ToNumber returns the number of a JSON scalar, true is 1, and a string is parsed as a number.
+// ok is false for arrays, objects, the null literal and the strings which are not numbers.
+func (j JSON) ToNumber() (f float64, ok bool) {
+	switch x := j.v.(type) {
+	case bool:
+		if x {
+			return 1, true
+		}
+		return 0, true
+	case int64:
+		return float64(x), true
+	case uint64:
+		return float64(x), true
+	case float64:
+		return x, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// sortedKeys returns the keys of the object m in the order of MySQL,
+// the shorter keys are first and the keys of the same length are sorted by bytes.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Sort(jsonKeys(keys))
+	return keys
+}
+
+type jsonKeys []string
+
+func (k jsonKeys) Len() int      { return len(k) }
+func (k jsonKeys) Swap(i, j int) { k[i], k[j] = k[j], k[i] }
+func (k jsonKeys) Less(i, j int) bool {
+	if len(k[i]) != len(k[j]) {
+		return len(k[i]) < len(k[j])
+	}
+	return k[i] < k[j]
+}
+
+func writeJSON(buf *bytes.Buffer, v interface{}) {
+	switch x := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(x))
+	case int64:
+		buf.WriteString(strconv.FormatInt(x, 10))
+	case uint64:
+		buf.WriteString(strconv.FormatUint(x, 10))
+	case float64:
+		buf.WriteString(formatJSONFloat(x))
+	case string:
+		writeJSONString(buf, x)
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, e := range x {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeJSON(buf, e)
+		}
+		buf.WriteByte(']')
+	case map[string]interface{}:
+		buf.WriteByte('{')
+		for i, k := range sortedKeys(x) {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeJSONString(buf, k)
+			buf.WriteString(": ")
+			writeJSON(buf, x[k])
+		}
+		buf.WriteByte('}')
+	}
+}
+
+// formatJSONFloat formats a double like MySQL, a number without fraction and exponent has ".0".
+func formatJSONFloat(f float64) string {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	s = strings.Replace(s, "e+", "e", 1)
+	if !strings.ContainsAny(s, ".e") && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		s += ".0"
+	}
+	return s
+}
+
+// writeJSONString writes the quoted JSON string s, the quotes, backslashes and control
+// characters are escaped.
+func writeJSONString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				fmt.Fprintf(buf, `\u%04x`, c)
+			} else {
+				buf.WriteString(s[i : i+size])
+			}
+			i += size
+			continue
+		}
+		switch c {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if c < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, c)
+			} else {
+				buf.WriteByte(c)
+			}
+		}
+		i++
+	}
+	buf.WriteByte('"')
+}
+
+// JSON type precedences for comparison, the values of a type with higher precedence are greater.
+const (
+	jsonPrecedenceNull = iota
+	jsonPrecedenceNumber
+	jsonPrecedenceString
+	jsonPrecedenceObject
+	jsonPrecedenceArray
+	jsonPrecedenceBoolean
+)
+
+func jsonPrecedence(v interface{}) int {
+	switch v.(type) {
+	case nil:
+		return jsonPrecedenceNull
+	case int64, uint64, float64:
+		return jsonPrecedenceNumber
+	case string:
+		return jsonPrecedenceString
+	case map[string]interface{}:
+		return jsonPrecedenceObject
+	case []interface{}:
+		return jsonPrecedenceArray
+	default:
+		return jsonPrecedenceBoolean
+	}
+}
+
+// CompareJSON compares the JSON values a and b like MySQL. The values of different types
+// are ordered by the types: BOOLEAN, ARRAY, OBJECT, STRING, INTEGER and DOUBLE, NULL from the greatest.
+// Numbers are compared by value, strings by bytes, arrays by the elements in order.
+// Objects are equal if they have the same members, otherwise they are ordered by the
+// sorted keys and then the values.
+// See https://dev.mysql.com/doc/refman/5.7/en/json.html#json-comparison
+func CompareJSON(a, b JSON) int {
+	return compareJSONValue(a.v, b.v)
+}
+
+func compareJSONValue(a, b interface{}) int {
+	pa, pb := jsonPrecedence(a), jsonPrecedence(b)
+	if pa != pb {
+		return compareInt(pa, pb)
+	}
+
+	switch x := a.(type) {
+	case nil:
+		return 0
+	case bool:
+		y := b.(bool)
+		if x == y {
+			return 0
+		}
+		if !x {
+			return -1
+		}
+		return 1
+	case int64, uint64, float64:
+		return compareJSONNumber(a, b)
+	case string:
+		return strings.Compare(x, b.(string))
+	case []interface{}:
+		y := b.([]interface{})
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c := compareJSONValue(x[i], y[i]); c != 0 {
+				return c
+			}
+		}
+		return compareInt(len(x), len(y))
+	case map[string]interface{}:
+		y := b.(map[string]interface{})
+		kx, ky := sortedKeys(x), sortedKeys(y)
+		for i := 0; i < len(kx) && i < len(ky); i++ {
+			if kx[i] != ky[i] {
+				if jsonKeys([]string{kx[i], ky[i]}).Less(0, 1) {
+					return -1
+				}
+				return 1
+			}
+		}
+		if c := compareInt(len(kx), len(ky)); c != 0 {
+			return c
+		}
+		for _, k := range kx {
+			if c := compareJSONValue(x[k], y[k]); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+	return 0
+}
+
+func compareInt(a, b int) int {
+	return compareOrder(a < b, a > b)
+}
+
+// compareJSONNumber compares two JSON numbers, the integers are compared exactly.
+func compareJSONNumber(a, b interface{}) int {
+	switch x := a.(type) {
+	case int64:
+		switch y := b.(type) {
+		case int64:
+			return compareOrder(x < y, x > y)
+		case uint64:
+			if x < 0 || uint64(x) < y {
+				return -1
+			} else if uint64(x) > y {
+				return 1
+			}
+			return 0
+		}
+	case uint64:
+		switch y := b.(type) {
+		case uint64:
+			return compareOrder(x < y, x > y)
+		case int64:
+			return -compareJSONNumber(y, x)
+		}
+	}
+	fa, fb := jsonNumberToFloat(a), jsonNumberToFloat(b)
+	return compareOrder(fa < fb, fa > fb)
+}
+
+func compareOrder(less, greater bool) int {
+	if less {
+		return -1
+	} else if greater {
+		return 1
+	}
+	return 0
+}
+
+func jsonNumberToFloat(v interface{}) float64 {
+	switch x := v.(type) {
+	case int64:
+		return float64(x)
+	case uint64:
+		return float64(x)
+	case float64:
+		return x
+	}
+	return 0
+}
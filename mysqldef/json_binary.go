@@ -0,0 +1,188 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/juju/errors"
+)
+
+// The type codes of the JSON binary encoding. A value is encoded as its type code, followed by
+// nothing for the literals, 8 bytes in big endian order for the numbers, the length in uvarint and
+// the bytes for a string, the number of elements in uvarint and the elements for an array,
+// or the number of members in uvarint and the members sorted by the keys for an object,
+// where a member is encoded as a string key followed by the value.
+const (
+	jsonCodeNull byte = iota
+	jsonCodeTrue
+	jsonCodeFalse
+	jsonCodeInt64
+	jsonCodeUint64
+	jsonCodeDouble
+	jsonCodeString
+	jsonCodeArray
+	jsonCodeObject
+)
+
+var errInvalidJSONBinary = errors.New("invalid JSON binary data")
+
+// MarshalBinary implements encoding.BinaryMarshaler interface.
+func (j JSON) MarshalBinary() ([]byte, error) {
+	return appendJSONBinary(nil, j.v), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface.
+func (j *JSON) UnmarshalBinary(data []byte) error {
+	v, rest, err := decodeJSONBinary(data)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(rest) != 0 {
+		return errors.Trace(errInvalidJSONBinary)
+	}
+	j.v = v
+	return nil
+}
+
+func appendUvarint(b []byte, n uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], n)]...)
+}
+
+func appendUint64(b []byte, n uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], n)
+	return append(b, buf[:]...)
+}
+
+func appendJSONBinary(b []byte, v interface{}) []byte {
+	switch x := v.(type) {
+	case nil:
+		b = append(b, jsonCodeNull)
+	case bool:
+		if x {
+			b = append(b, jsonCodeTrue)
+		} else {
+			b = append(b, jsonCodeFalse)
+		}
+	case int64:
+		b = appendUint64(append(b, jsonCodeInt64), uint64(x))
+	case uint64:
+		b = appendUint64(append(b, jsonCodeUint64), x)
+	case float64:
+		b = appendUint64(append(b, jsonCodeDouble), math.Float64bits(x))
+	case string:
+		b = appendUvarint(append(b, jsonCodeString), uint64(len(x)))
+		b = append(b, x...)
+	case []interface{}:
+		b = appendUvarint(append(b, jsonCodeArray), uint64(len(x)))
+		for _, e := range x {
+			b = appendJSONBinary(b, e)
+		}
+	case map[string]interface{}:
+		b = appendUvarint(append(b, jsonCodeObject), uint64(len(x)))
+		for _, k := range sortedKeys(x) {
+			b = appendUvarint(b, uint64(len(k)))
+			b = append(b, k...)
+			b = appendJSONBinary(b, x[k])
+		}
+	}
+	return b
+}
+
+func decodeUvarint(b []byte) (uint64, []byte, error) {
+	n, size := binary.Uvarint(b)
+	if size <= 0 {
+		return 0, nil, errors.Trace(errInvalidJSONBinary)
+	}
+	return n, b[size:], nil
+}
+
+func decodeJSONString(b []byte) (string, []byte, error) {
+	n, b, err := decodeUvarint(b)
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	if uint64(len(b)) < n {
+		return "", nil, errors.Trace(errInvalidJSONBinary)
+	}
+	return string(b[:n]), b[n:], nil
+}
+
+func decodeJSONBinary(b []byte) (interface{}, []byte, error) {
+	if len(b) == 0 {
+		return nil, nil, errors.Trace(errInvalidJSONBinary)
+	}
+	code, b := b[0], b[1:]
+	switch code {
+	case jsonCodeNull:
+		return nil, b, nil
+	case jsonCodeTrue:
+		return true, b, nil
+	case jsonCodeFalse:
+		return false, b, nil
+	case jsonCodeInt64, jsonCodeUint64, jsonCodeDouble:
+		if len(b) < 8 {
+			return nil, nil, errors.Trace(errInvalidJSONBinary)
+		}
+		n := binary.BigEndian.Uint64(b)
+		switch code {
+		case jsonCodeInt64:
+			return int64(n), b[8:], nil
+		case jsonCodeUint64:
+			return n, b[8:], nil
+		default:
+			return math.Float64frombits(n), b[8:], nil
+		}
+	case jsonCodeString:
+		return decodeJSONString(b)
+	case jsonCodeArray:
+		n, b, err := decodeUvarint(b)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		a := make([]interface{}, 0, n)
+		for i := uint64(0); i < n; i++ {
+			var e interface{}
+			if e, b, err = decodeJSONBinary(b); err != nil {
+				return nil, nil, errors.Trace(err)
+			}
+			a = append(a, e)
+		}
+		return a, b, nil
+	case jsonCodeObject:
+		n, b, err := decodeUvarint(b)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		m := make(map[string]interface{}, n)
+		for i := uint64(0); i < n; i++ {
+			var (
+				k string
+				e interface{}
+			)
+			if k, b, err = decodeJSONString(b); err != nil {
+				return nil, nil, errors.Trace(err)
+			}
+			if e, b, err = decodeJSONBinary(b); err != nil {
+				return nil, nil, errors.Trace(err)
+			}
+			m[k] = e
+		}
+		return m, b, nil
+	}
+	return nil, nil, errors.Trace(errInvalidJSONBinary)
+}
@@ -0,0 +1,52 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	. "github.com/pingcap/check"
+)
+
+func (s *testJSONSuite) TestJSONBinary(c *C) {
+	tbl := []string{
+		`null`,
+		`true`,
+		`false`,
+		`-9223372036854775808`,
+		`18446744073709551615`,
+		`-1.5`,
+		`""`,
+		`"abc"`,
+		`[]`,
+		`[1, "a", [null, {}]]`,
+		`{"a": {"b": [1.5, true]}, "ab": "x", "": 1}`,
+	}
+
+	for _, t := range tbl {
+		j := mustParseJSON(c, t)
+		b, err := j.MarshalBinary()
+		c.Assert(err, IsNil)
+		var j2 JSON
+		err = j2.UnmarshalBinary(b)
+		c.Assert(err, IsNil)
+		c.Assert(j2, DeepEquals, j, Commentf("%s", t))
+
+		// Truncated data is invalid.
+		err = j2.UnmarshalBinary(b[:len(b)-1])
+		c.Assert(err, NotNil, Commentf("%s", t))
+	}
+
+	var j JSON
+	c.Assert(j.UnmarshalBinary([]byte{0xFF}), NotNil)
+	c.Assert(j.UnmarshalBinary([]byte{jsonCodeNull, jsonCodeNull}), NotNil)
+}
@@ -0,0 +1,428 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/juju/errors"
+)
+
+type jsonPathLegType byte
+
+const (
+	jsonPathLegKey jsonPathLegType = iota
+	jsonPathLegIndex
+	jsonPathLegDoubleWildcard
+)
+
+// jsonPathWildcardIndex is the index of the [*] leg.
+const jsonPathWildcardIndex = -1
+
+type jsonPathLeg struct {
+	tp jsonPathLegType
+	// key is the member name of a key leg, "" with wildcard set for .*
+	key      string
+	wildcard bool
+	// index is the array index of an index leg, or jsonPathWildcardIndex for [*].
+	index int
+}
+
+// JSONPath is a JSON path expression, like $.a[1].
+// See https://dev.mysql.com/doc/refman/5.7/en/json-path-syntax.html
+type JSONPath struct {
+	legs []jsonPathLeg
+	text string
+}
+
+// ParseJSONPath parses the JSON path expression s.
+func ParseJSONPath(s string) (JSONPath, error) {
+	p := JSONPath{text: s}
+	i := skipSpaces(s, 0)
+	if i >= len(s) || s[i] != '$' {
+		return p, NewDefaultError(ErInvalidJSONPath, i)
+	}
+	i++
+	for {
+		i = skipSpaces(s, i)
+		if i >= len(s) {
+			break
+		}
+		var (
+			leg jsonPathLeg
+			ok  bool
+		)
+		switch s[i] {
+		case '.':
+			leg, i, ok = parseJSONPathKey(s, skipSpaces(s, i+1))
+		case '[':
+			leg, i, ok = parseJSONPathIndex(s, skipSpaces(s, i+1))
+		case '*':
+			if i+1 < len(s) && s[i+1] == '*' {
+				leg, i, ok = jsonPathLeg{tp: jsonPathLegDoubleWildcard}, i+2, true
+			}
+		}
+		if !ok {
+			return p, NewDefaultError(ErInvalidJSONPath, i)
+		}
+		p.legs = append(p.legs, leg)
+	}
+	if n := len(p.legs); n > 0 && p.legs[n-1].tp == jsonPathLegDoubleWildcard {
+		// ** must be followed by another leg.
+		return p, NewDefaultError(ErInvalidJSONPath, len(s))
+	}
+	return p, nil
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && unicode.IsSpace(rune(s[i])) {
+		i++
+	}
+	return i
+}
+
+// parseJSONPathKey parses the member name after '.', a wildcard, a quoted string or an identifier.
+func parseJSONPathKey(s string, i int) (jsonPathLeg, int, bool) {
+	leg := jsonPathLeg{tp: jsonPathLegKey}
+	if i >= len(s) {
+		return leg, i, false
+	}
+	switch {
+	case s[i] == '*':
+		leg.wildcard = true
+		return leg, i + 1, true
+	case s[i] == '"':
+		end := i + 1
+		for ; end < len(s) && s[end] != '"'; end++ {
+			if s[end] == '\\' {
+				end++
+			}
+		}
+		if end >= len(s) {
+			return leg, i, false
+		}
+		if err := json.Unmarshal([]byte(s[i:end+1]), &leg.key); err != nil {
+			return leg, i, false
+		}
+		return leg, end + 1, true
+	}
+	end := i
+	for end < len(s) {
+		c := rune(s[end])
+		if c != '_' && c != '$' && !unicode.IsLetter(c) && !unicode.IsDigit(c) && c < 0x80 {
+			break
+		}
+		end++
+	}
+	if end == i || unicode.IsDigit(rune(s[i])) {
+		return leg, i, false
+	}
+	leg.key = s[i:end]
+	return leg, end, true
+}
+
+// parseJSONPathIndex parses the array index or the wildcard after '['.
+func parseJSONPathIndex(s string, i int) (jsonPathLeg, int, bool) {
+	leg := jsonPathLeg{tp: jsonPathLegIndex}
+	end := strings.IndexByte(s[i:], ']')
+	if end < 0 {
+		return leg, i, false
+	}
+	str := strings.TrimSpace(s[i : i+end])
+	if str == "*" {
+		leg.index = jsonPathWildcardIndex
+		return leg, i + end + 1, true
+	}
+	n, err := strconv.ParseUint(str, 10, 31)
+	if err != nil {
+		return leg, i, false
+	}
+	leg.index = int(n)
+	return leg, i + end + 1, true
+}
+
+// String implements fmt.Stringer interface.
+func (p JSONPath) String() string {
+	return p.text
+}
+
+// HasWildcard returns whether the path has the * or ** tokens, so it may match more than one value.
+func (p JSONPath) HasWildcard() bool {
+	for _, leg := range p.legs {
+		if leg.wildcard || leg.tp == jsonPathLegDoubleWildcard || (leg.tp == jsonPathLegIndex && leg.index == jsonPathWildcardIndex) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRoot returns whether the path is $.
+func (p JSONPath) IsRoot() bool {
+	return len(p.legs) == 0
+}
+
+// Extract returns the values matched by the paths, like JSON_EXTRACT does. If there is only one path
+// without wildcards, the value it matches is returned, otherwise the matched values are wrapped in an array.
+// found is false if no value is matched.
+func (j JSON) Extract(paths []JSONPath) (ret JSON, found bool) {
+	var matched []interface{}
+	for _, p := range paths {
+		matched = extractJSON(j.v, p.legs, matched)
+	}
+	if len(matched) == 0 {
+		return JSON{}, false
+	}
+	if len(paths) == 1 && !paths[0].HasWildcard() {
+		return JSON{v: matched[0]}, true
+	}
+	return JSON{v: matched}, true
+}
+
+func extractJSON(v interface{}, legs []jsonPathLeg, ret []interface{}) []interface{} {
+	if len(legs) == 0 {
+		return append(ret, v)
+	}
+	leg, rest := legs[0], legs[1:]
+	switch leg.tp {
+	case jsonPathLegKey:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return ret
+		}
+		if !leg.wildcard {
+			if e, ok := m[leg.key]; ok {
+				ret = extractJSON(e, rest, ret)
+			}
+			return ret
+		}
+		for _, k := range sortedKeys(m) {
+			ret = extractJSON(m[k], rest, ret)
+		}
+	case jsonPathLegIndex:
+		a, ok := v.([]interface{})
+		if !ok {
+			// A scalar or an object is taken as an array of one element.
+			if leg.index == 0 || leg.index == jsonPathWildcardIndex {
+				ret = extractJSON(v, rest, ret)
+			}
+			return ret
+		}
+		if leg.index == jsonPathWildcardIndex {
+			for _, e := range a {
+				ret = extractJSON(e, rest, ret)
+			}
+		} else if leg.index < len(a) {
+			ret = extractJSON(a[leg.index], rest, ret)
+		}
+	case jsonPathLegDoubleWildcard:
+		// ** matches the value and all the values in it.
+		ret = extractJSON(v, rest, ret)
+		switch x := v.(type) {
+		case []interface{}:
+			for _, e := range x {
+				ret = extractJSON(e, legs, ret)
+			}
+		case map[string]interface{}:
+			for _, k := range sortedKeys(x) {
+				ret = extractJSON(x[k], legs, ret)
+			}
+		}
+	}
+	return ret
+}
+
+// JSONModifyType is the way to modify a JSON document by a path.
+type JSONModifyType byte
+
+const (
+	// JSONModifySet sets the value if the path exists, or adds it if not, like JSON_SET.
+	JSONModifySet JSONModifyType = iota
+	// JSONModifyInsert adds the value if the path doesn't exist, like JSON_INSERT.
+	JSONModifyInsert
+	// JSONModifyReplace sets the value if the path exists, like JSON_REPLACE.
+	JSONModifyReplace
+)
+
+// Modify returns the document with the values at the paths set, inserted or replaced
+// one by one. A path which may match more than one value is an error.
+func (j JSON) Modify(paths []JSONPath, values []JSON, tp JSONModifyType) (JSON, error) {
+	v := j.v
+	for i, p := range paths {
+		if p.HasWildcard() {
+			return j, NewDefaultError(ErInvalidJSONPathWildcard)
+		}
+		v = modifyJSON(v, p.legs, values[i].v, tp)
+	}
+	return JSON{v: v}, nil
+}
+
+// modifyJSON returns v with the value at legs changed to nv, the changed arrays and objects are copied.
+func modifyJSON(v interface{}, legs []jsonPathLeg, nv interface{}, tp JSONModifyType) interface{} {
+	if len(legs) == 0 {
+		if tp == JSONModifyInsert {
+			return v
+		}
+		return nv
+	}
+	leg, rest := legs[0], legs[1:]
+	switch leg.tp {
+	case jsonPathLegKey:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		e, ok := m[leg.key]
+		if !ok {
+			if len(rest) > 0 || tp == JSONModifyReplace {
+				return v
+			}
+			e = nv
+		} else {
+			e = modifyJSON(e, rest, nv, tp)
+		}
+		cm := make(map[string]interface{}, len(m)+1)
+		for k, x := range m {
+			cm[k] = x
+		}
+		cm[leg.key] = e
+		return cm
+	case jsonPathLegIndex:
+		a, ok := v.([]interface{})
+		if !ok {
+			// A scalar or an object is taken as an array of one element,
+			// appending to it makes an array.
+			if leg.index == 0 {
+				return modifyJSON(v, rest, nv, tp)
+			}
+			if len(rest) > 0 || tp == JSONModifyReplace {
+				return v
+			}
+			return []interface{}{v, nv}
+		}
+		ca := make([]interface{}, len(a), len(a)+1)
+		copy(ca, a)
+		if leg.index < len(a) {
+			ca[leg.index] = modifyJSON(a[leg.index], rest, nv, tp)
+			return ca
+		}
+		if len(rest) > 0 || tp == JSONModifyReplace {
+			return v
+		}
+		return append(ca, nv)
+	}
+	return v
+}
+
+// Remove returns the document with the values at the paths removed one by one.
+// The path must not be $ or have wildcards.
+func (j JSON) Remove(paths []JSONPath) (JSON, error) {
+	v := j.v
+	for _, p := range paths {
+		if p.HasWildcard() {
+			return j, NewDefaultError(ErInvalidJSONPathWildcard)
+		}
+		if p.IsRoot() {
+			return j, errors.New("The path expression '$' is not allowed in this context.")
+		}
+		v = removeJSON(v, p.legs)
+	}
+	return JSON{v: v}, nil
+}
+
+func removeJSON(v interface{}, legs []jsonPathLeg) interface{} {
+	leg, rest := legs[0], legs[1:]
+	switch leg.tp {
+	case jsonPathLegKey:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		e, ok := m[leg.key]
+		if !ok {
+			return v
+		}
+		cm := make(map[string]interface{}, len(m))
+		for k, x := range m {
+			cm[k] = x
+		}
+		if len(rest) == 0 {
+			delete(cm, leg.key)
+		} else {
+			cm[leg.key] = removeJSON(e, rest)
+		}
+		return cm
+	case jsonPathLegIndex:
+		a, ok := v.([]interface{})
+		if !ok || leg.index >= len(a) {
+			return v
+		}
+		if len(rest) == 0 {
+			ca := make([]interface{}, 0, len(a)-1)
+			ca = append(ca, a[:leg.index]...)
+			return append(ca, a[leg.index+1:]...)
+		}
+		ca := make([]interface{}, len(a))
+		copy(ca, a)
+		ca[leg.index] = removeJSON(a[leg.index], rest)
+		return ca
+	}
+	return v
+}
+
+// Contains returns whether the document contains the candidate, like JSON_CONTAINS does.
+// A scalar contains an equal scalar, an array contains the candidate if an element contains it,
+// or every element of the candidate array, and an object contains the candidate object
+// if it has all the keys of the candidate and contains the values of them.
+func (j JSON) Contains(candidate JSON) bool {
+	return containsJSON(j.v, candidate.v)
+}
+
+func containsJSON(target, candidate interface{}) bool {
+	switch x := target.(type) {
+	case []interface{}:
+		if y, ok := candidate.([]interface{}); ok {
+			for _, e := range y {
+				if !containsJSON(x, e) {
+					return false
+				}
+			}
+			return true
+		}
+		for _, e := range x {
+			if containsJSON(e, candidate) {
+				return true
+			}
+		}
+		return false
+	case map[string]interface{}:
+		y, ok := candidate.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, e := range y {
+			te, ok := x[k]
+			if !ok || !containsJSON(te, e) {
+				return false
+			}
+		}
+		return true
+	}
+	if jsonPrecedence(target) != jsonPrecedence(candidate) {
+		return false
+	}
+	return compareJSONValue(target, candidate) == 0
+}
@@ -0,0 +1,166 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	. "github.com/pingcap/check"
+)
+
+func mustParseJSONPaths(c *C, strs ...string) []JSONPath {
+	paths := make([]JSONPath, len(strs))
+	for i, s := range strs {
+		p, err := ParseJSONPath(s)
+		c.Assert(err, IsNil, Commentf("%s", s))
+		paths[i] = p
+	}
+	return paths
+}
+
+func (s *testJSONSuite) TestParseJSONPath(c *C) {
+	tbl := []struct {
+		Input    string
+		Wildcard bool
+	}{
+		{`$`, false},
+		{` $ . a [ 1 ] `, false},
+		{`$."a b"[0].c_1$`, false},
+		{`$.é`, false},
+		{`$.*`, true},
+		{`$[*]`, true},
+		{`$**.a`, true},
+	}
+
+	for _, t := range tbl {
+		p := mustParseJSONPaths(c, t.Input)[0]
+		c.Assert(p.HasWildcard(), Equals, t.Wildcard)
+		c.Assert(p.String(), Equals, t.Input)
+	}
+	c.Assert(mustParseJSONPaths(c, `$`)[0].IsRoot(), IsTrue)
+
+	errTbl := []string{``, `a`, `$.`, `$.1a`, `$[a]`, `$[-1]`, `$[1`, `$**`, `$."a`, `$a`}
+	for _, t := range errTbl {
+		_, err := ParseJSONPath(t)
+		c.Assert(err, NotNil, Commentf("%s", t))
+	}
+}
+
+func (s *testJSONSuite) TestExtract(c *C) {
+	j := mustParseJSON(c, `{"a": [1, {"b": 2}], "c": {"b": 3}, "a b": 4}`)
+	tbl := []struct {
+		Paths  []string
+		Expect string
+	}{
+		{[]string{`$`}, j.String()},
+		{[]string{`$.a[1].b`}, `2`},
+		{[]string{`$."a b"`}, `4`},
+		{[]string{`$.c[0].b`}, `3`},
+		{[]string{`$.c[1]`}, ``},
+		{[]string{`$.x`}, ``},
+		{[]string{`$.a[5]`}, ``},
+		{[]string{`$.a[0].b`}, ``},
+		{[]string{`$.a[*]`}, `[1, {"b": 2}]`},
+		{[]string{`$.*.b`}, `[3]`},
+		{[]string{`$**.b`}, `[2, 3]`},
+		{[]string{`$.a[0]`, `$.x`, `$.c.b`}, `[1, 3]`},
+	}
+
+	for _, t := range tbl {
+		ret, found := j.Extract(mustParseJSONPaths(c, t.Paths...))
+		c.Assert(found, Equals, t.Expect != "", Commentf("%v", t.Paths))
+		if found {
+			c.Assert(ret.String(), Equals, t.Expect, Commentf("%v", t.Paths))
+		}
+	}
+}
+
+func (s *testJSONSuite) TestModify(c *C) {
+	j := mustParseJSON(c, `{"a": [1, 2], "b": 3}`)
+	tbl := []struct {
+		Path   string
+		Value  string
+		Set    string
+		Insert string
+		Remove string
+	}{
+		{`$`, `1`, `1`, j.String(), ``},
+		{`$.b`, `4`, `{"a": [1, 2], "b": 4}`, j.String(), `{"a": [1, 2]}`},
+		{`$.c`, `4`, `{"a": [1, 2], "b": 3, "c": 4}`, `{"a": [1, 2], "b": 3, "c": 4}`, j.String()},
+		{`$.a[1]`, `4`, `{"a": [1, 4], "b": 3}`, j.String(), `{"a": [1], "b": 3}`},
+		{`$.a[5]`, `4`, `{"a": [1, 2, 4], "b": 3}`, `{"a": [1, 2, 4], "b": 3}`, j.String()},
+		{`$.b[0]`, `4`, `{"a": [1, 2], "b": 4}`, j.String(), j.String()},
+		{`$.b[1]`, `4`, `{"a": [1, 2], "b": [3, 4]}`, `{"a": [1, 2], "b": [3, 4]}`, j.String()},
+		{`$.x.y`, `4`, j.String(), j.String(), j.String()},
+		{`$.b.y`, `4`, j.String(), j.String(), j.String()},
+	}
+
+	for _, t := range tbl {
+		paths := mustParseJSONPaths(c, t.Path)
+		values := []JSON{mustParseJSON(c, t.Value)}
+		ret, err := j.Modify(paths, values, JSONModifySet)
+		c.Assert(err, IsNil)
+		c.Assert(ret.String(), Equals, t.Set, Commentf("set %s", t.Path))
+		ret, err = j.Modify(paths, values, JSONModifyInsert)
+		c.Assert(err, IsNil)
+		c.Assert(ret.String(), Equals, t.Insert, Commentf("insert %s", t.Path))
+
+		ret, err = j.Modify(paths, values, JSONModifyReplace)
+		c.Assert(err, IsNil)
+		expect := t.Set
+		if t.Insert != j.String() {
+			// The path doesn't exist.
+			expect = j.String()
+		}
+		c.Assert(ret.String(), Equals, expect, Commentf("replace %s", t.Path))
+
+		if t.Remove != "" {
+			ret, err = j.Remove(paths)
+			c.Assert(err, IsNil)
+			c.Assert(ret.String(), Equals, t.Remove, Commentf("remove %s", t.Path))
+		}
+	}
+
+	// The document is not changed.
+	c.Assert(j.String(), Equals, `{"a": [1, 2], "b": 3}`)
+
+	_, err := j.Modify(mustParseJSONPaths(c, `$.a[*]`), []JSON{{}}, JSONModifySet)
+	c.Assert(err, NotNil)
+	_, err = j.Remove(mustParseJSONPaths(c, `$`))
+	c.Assert(err, NotNil)
+	_, err = j.Remove(mustParseJSONPaths(c, `$**.a`))
+	c.Assert(err, NotNil)
+}
+
+func (s *testJSONSuite) TestContains(c *C) {
+	tbl := []struct {
+		Target    string
+		Candidate string
+		Expect    bool
+	}{
+		{`1`, `1.0`, true},
+		{`1`, `"1"`, false},
+		{`[1, [2, 3]]`, `2`, true},
+		{`[1, [2, 3]]`, `[1, 3]`, true},
+		{`[1, 2]`, `[1, 4]`, false},
+		{`1`, `[1]`, false},
+		{`{"a": 1, "b": [1, 2]}`, `{"b": [2]}`, true},
+		{`{"a": 1, "b": [1, 2]}`, `{"a": 1, "c": 1}`, false},
+		{`{"a": 1}`, `1`, false},
+		{`[{"a": 1}]`, `{"a": 1}`, true},
+	}
+
+	for _, t := range tbl {
+		ret := mustParseJSON(c, t.Target).Contains(mustParseJSON(c, t.Candidate))
+		c.Assert(ret, Equals, t.Expect, Commentf("%s %s", t.Target, t.Candidate))
+	}
+}
@@ -0,0 +1,151 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testJSONSuite{})
+
+type testJSONSuite struct {
+}
+
+func mustParseJSON(c *C, s string) JSON {
+	j, err := ParseJSON(s)
+	c.Assert(err, IsNil, Commentf("%s", s))
+	return j
+}
+
+func (s *testJSONSuite) TestParseJSON(c *C) {
+	tbl := []struct {
+		Input  string
+		Output string
+		Type   string
+	}{
+		{`null`, `null`, JSONTypeNull},
+		{` true `, `true`, JSONTypeBoolean},
+		{`-12`, `-12`, JSONTypeInteger},
+		{`18446744073709551615`, `18446744073709551615`, JSONTypeUnsigned},
+		{`3.0`, `3.0`, JSONTypeDouble},
+		{`1e20`, `1e20`, JSONTypeDouble},
+		{`"a\"é\n"`, `"a\"é\n"`, JSONTypeString},
+		{`[1,"a" ,[]]`, `[1, "a", []]`, JSONTypeArray},
+		{`{"bb":1,"a":{},"c":2}`, `{"a": {}, "c": 2, "bb": 1}`, JSONTypeObject},
+	}
+
+	for _, t := range tbl {
+		j := mustParseJSON(c, t.Input)
+		c.Assert(j.String(), Equals, t.Output)
+		c.Assert(j.Type(), Equals, t.Type)
+	}
+
+	errTbl := []string{``, `{"a": }`, `[1] 2`, `'a'`, `1e400`}
+	for _, t := range errTbl {
+		_, err := ParseJSON(t)
+		c.Assert(err, NotNil, Commentf("%s", t))
+	}
+
+	_, err := ParseJSON(`[1, }`)
+	c.Assert(err.(*JSONSyntaxError).Offset, Equals, int64(5))
+}
+
+func (s *testJSONSuite) TestCreateJSON(c *C) {
+	tbl := []struct {
+		Input  interface{}
+		Output string
+	}{
+		{nil, `null`},
+		{int8(-1), `-1`},
+		{uint16(1), `1`},
+		{float32(1.5), `1.5`},
+		{NewDecimalFromInt(25, -1), `2.5`},
+		{"[1]", `"[1]"`},
+		{[]byte("a"), `"a"`},
+		{Enum{Name: "a", Value: 1}, `"a"`},
+		{mustParseJSON(c, `[1]`), `[1]`},
+	}
+
+	for _, t := range tbl {
+		j, err := CreateJSON(t.Input)
+		c.Assert(err, IsNil)
+		c.Assert(j.String(), Equals, t.Output)
+	}
+
+	_, err := CreateJSON(struct{}{})
+	c.Assert(err, NotNil)
+
+	a := CreateJSONArray([]JSON{mustParseJSON(c, `1`), {}})
+	c.Assert(a.String(), Equals, `[1, null]`)
+	o := CreateJSONObject([]string{"a", "b", "a"}, []JSON{mustParseJSON(c, `1`), {}, mustParseJSON(c, `2`)})
+	c.Assert(o.String(), Equals, `{"a": 2, "b": null}`)
+}
+
+func (s *testJSONSuite) TestJSONConvert(c *C) {
+	j := mustParseJSON(c, `"a\tb"`)
+	c.Assert(j.Unquote(), Equals, "a\tb")
+	c.Assert(mustParseJSON(c, `[1]`).Unquote(), Equals, `[1]`)
+	c.Assert(mustParseJSON(c, `null`).IsNull(), IsTrue)
+
+	tbl := []struct {
+		Input  string
+		Number float64
+		OK     bool
+	}{
+		{`true`, 1, true},
+		{`-2`, -2, true},
+		{`1.5`, 1.5, true},
+		{`" 12 "`, 12, true},
+		{`"a"`, 0, false},
+		{`[1]`, 0, false},
+	}
+
+	for _, t := range tbl {
+		f, ok := mustParseJSON(c, t.Input).ToNumber()
+		c.Assert(ok, Equals, t.OK, Commentf("%s", t.Input))
+		c.Assert(f, Equals, t.Number, Commentf("%s", t.Input))
+	}
+}
+
+func (s *testJSONSuite) TestCompareJSON(c *C) {
+	tbl := []struct {
+		A      string
+		B      string
+		Expect int
+	}{
+		{`null`, `1`, -1},
+		{`1`, `1.0`, 0},
+		{`-1`, `18446744073709551615`, -1},
+		{`9223372036854775807`, `9223372036854775806`, 1},
+		{`2.5`, `2`, 1},
+		{`"a"`, `"b"`, -1},
+		{`"a"`, `100`, 1},
+		{`{}`, `"a"`, 1},
+		{`[]`, `{}`, 1},
+		{`false`, `[1]`, 1},
+		{`false`, `true`, -1},
+		{`[1, 2]`, `[1, 2, 0]`, -1},
+		{`[1, 3]`, `[1, 2, 0]`, 1},
+		{`{"a": 1, "b": 2}`, `{"b": 2, "a": 1}`, 0},
+		{`{"a": 1}`, `{"a": 2}`, -1},
+		{`{"a": 1}`, `{"b": 1}`, -1},
+		{`{"a": 1}`, `{"a": 1, "b": 1}`, -1},
+	}
+
+	for _, t := range tbl {
+		a, b := mustParseJSON(c, t.A), mustParseJSON(c, t.B)
+		c.Assert(CompareJSON(a, b), Equals, t.Expect, Commentf("%s %s", t.A, t.B))
+		c.Assert(CompareJSON(b, a), Equals, -t.Expect, Commentf("%s %s", t.B, t.A))
+	}
+}
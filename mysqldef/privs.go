@@ -0,0 +1,128 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	"strings"
+)
+
+// PrivilegeType is a set of privileges of an account.
+// See https://dev.mysql.com/doc/refman/5.7/en/privileges-provided.html
+type PrivilegeType uint32
+
+// consts for privileges.
+const (
+	SelectPriv PrivilegeType = 1 << iota
+	InsertPriv
+	UpdatePriv
+	DeletePriv
+	CreatePriv
+	DropPriv
+	GrantPriv
+	IndexPriv
+	AlterPriv
+	CreateUserPriv
+	SuperPriv
+
+	// AllGlobalPrivs is ALL PRIVILEGES granted on *.*, GRANT OPTION is not included.
+	AllGlobalPrivs = SelectPriv | InsertPriv | UpdatePriv | DeletePriv | CreatePriv | DropPriv |
+		IndexPriv | AlterPriv | CreateUserPriv | SuperPriv
+	// AllDBPrivs is ALL PRIVILEGES granted on db.*.
+	AllDBPrivs = SelectPriv | InsertPriv | UpdatePriv | DeletePriv | CreatePriv | DropPriv |
+		IndexPriv | AlterPriv
+	// AllTablePrivs is ALL PRIVILEGES granted on db.tbl.
+	AllTablePrivs = AllDBPrivs
+	// AllColumnPrivs is the privileges can be granted on columns.
+	AllColumnPrivs = SelectPriv | InsertPriv | UpdatePriv
+)
+
+// privilegeInfos lists the privileges in the order they are shown.
+var privilegeInfos = []struct {
+	priv PrivilegeType
+	// name is the privilege name in GRANT.
+	name string
+	// setName is the value in the SET columns of mysql.tables_priv and mysql.columns_priv.
+	setName string
+	// column is the column name in mysql.user and mysql.db.
+	column string
+}{
+	{SelectPriv, "SELECT", "Select", "Select_priv"},
+	{InsertPriv, "INSERT", "Insert", "Insert_priv"},
+	{UpdatePriv, "UPDATE", "Update", "Update_priv"},
+	{DeletePriv, "DELETE", "Delete", "Delete_priv"},
+	{CreatePriv, "CREATE", "Create", "Create_priv"},
+	{DropPriv, "DROP", "Drop", "Drop_priv"},
+	{GrantPriv, "GRANT OPTION", "Grant", "Grant_priv"},
+	{IndexPriv, "INDEX", "Index", "Index_priv"},
+	{AlterPriv, "ALTER", "Alter", "Alter_priv"},
+	{CreateUserPriv, "CREATE USER", "", "Create_user_priv"},
+	{SuperPriv, "SUPER", "", "Super_priv"},
+}
+
+// Privileges returns the single privileges in p.
+func (p PrivilegeType) Privileges() []PrivilegeType {
+	var privs []PrivilegeType
+	for _, info := range privilegeInfos {
+		if p&info.priv != 0 {
+			privs = append(privs, info.priv)
+		}
+	}
+	return privs
+}
+
+// String returns the names of the privileges in p like "SELECT, INSERT".
+func (p PrivilegeType) String() string {
+	var names []string
+	for _, info := range privilegeInfos {
+		if p&info.priv != 0 {
+			names = append(names, info.name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// Column returns the column of the single privilege p in mysql.user and mysql.db.
+func (p PrivilegeType) Column() string {
+	for _, info := range privilegeInfos {
+		if p == info.priv {
+			return info.column
+		}
+	}
+	return ""
+}
+
+// SetString returns p as the value of the SET columns in mysql.tables_priv and mysql.columns_priv,
+// like "Select,Insert".
+func (p PrivilegeType) SetString() string {
+	var names []string
+	for _, info := range privilegeInfos {
+		if p&info.priv != 0 && len(info.setName) > 0 {
+			names = append(names, info.setName)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// PrivilegesFromSetString parses the value of the SET columns in mysql.tables_priv and mysql.columns_priv.
+func PrivilegesFromSetString(s string) PrivilegeType {
+	var p PrivilegeType
+	for _, name := range strings.Split(s, ",") {
+		for _, info := range privilegeInfos {
+			if len(info.setName) > 0 && strings.EqualFold(name, info.setName) {
+				p |= info.priv
+			}
+		}
+	}
+	return p
+}
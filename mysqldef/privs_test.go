@@ -0,0 +1,40 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testPrivsSuite{})
+
+type testPrivsSuite struct {
+}
+
+func (s *testPrivsSuite) TestPrivilegeType(c *C) {
+	p := SelectPriv | InsertPriv | GrantPriv
+	c.Assert(p.String(), Equals, "SELECT, INSERT, GRANT OPTION")
+	c.Assert(p.Privileges(), DeepEquals, []PrivilegeType{SelectPriv, InsertPriv, GrantPriv})
+	c.Assert(p.SetString(), Equals, "Select,Insert,Grant")
+	c.Assert(PrivilegesFromSetString("Select,Insert,Grant"), Equals, p)
+	c.Assert(PrivilegesFromSetString(""), Equals, PrivilegeType(0))
+
+	c.Assert(CreateUserPriv.Column(), Equals, "Create_user_priv")
+	c.Assert(IndexPriv.Column(), Equals, "Index_priv")
+	c.Assert(p.Column(), Equals, "")
+	c.Assert(CreateUserPriv.SetString(), Equals, "")
+	c.Assert(AllGlobalPrivs&GrantPriv, Equals, PrivilegeType(0))
+	c.Assert(AllDBPrivs|CreateUserPriv|SuperPriv, Equals, AllGlobalPrivs)
+	c.Assert(SuperPriv.Column(), Equals, "Super_priv")
+}
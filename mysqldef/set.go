@@ -0,0 +1,87 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// Set is the value of the MySQL SET type, the names of the members joined by commas,
+// in the order of the elements, and a number whose bit i is set if element i is a member.
+// See https://dev.mysql.com/doc/refman/5.7/en/set.html
+type Set struct {
+	Name  string
+	Value uint64
+}
+
+// MaxSetElements is the maximum number of the elements of a SET column.
+const MaxSetElements = 64
+
+// String implements fmt.Stringer interface.
+func (s Set) String() string {
+	return s.Name
+}
+
+// ToNumber returns the number of the set.
+func (s Set) ToNumber() float64 {
+	return float64(s.Value)
+}
+
+// ParseSetName returns the set of elems with the comma separated member names, the trailing spaces
+// are ignored and the letters are compared case insensitively. A member can be given more than once.
+// If a member is not in elems, the name is parsed as a number.
+func ParseSetName(elems []string, name string) (Set, error) {
+	name = strings.TrimRight(name, " ")
+	if name == "" {
+		return Set{}, nil
+	}
+
+	var value uint64
+	for _, item := range strings.Split(name, ",") {
+		found := false
+		for i, n := range elems {
+			if strings.EqualFold(n, item) {
+				value |= 1 << uint(i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			// name may be a number.
+			if num, err := strconv.ParseUint(name, 0, 64); err == nil {
+				return ParseSetValue(elems, num)
+			}
+			return Set{}, errors.Errorf("item %s is not in set %v", item, elems)
+		}
+	}
+	return ParseSetValue(elems, value)
+}
+
+// ParseSetValue returns the set of elems with the number.
+func ParseSetValue(elems []string, number uint64) (Set, error) {
+	if len(elems) < MaxSetElements && number >= 1<<uint(len(elems)) {
+		return Set{}, errors.Errorf("number %d overflow set boundary [0, %d)", number, uint64(1)<<uint(len(elems)))
+	}
+
+	var names []string
+	for i, n := range elems {
+		if number&(1<<uint(i)) != 0 {
+			names = append(names, n)
+		}
+	}
+	return Set{Name: strings.Join(names, ","), Value: number}, nil
+}
@@ -0,0 +1,58 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testSetSuite{})
+
+type testSetSuite struct {
+}
+
+func (s *testSetSuite) TestSet(c *C) {
+	elems := []string{"a", "b", "c", "d"}
+	tbl := []struct {
+		Name     string
+		Expected string
+		Value    uint64
+	}{
+		{"", "", 0},
+		{"a", "a", 1},
+		{"c,A", "a,c", 5},
+		{"b,b,d ", "b,d", 10},
+		{"15", "a,b,c,d", 15},
+	}
+
+	for _, t := range tbl {
+		e, err := ParseSetName(elems, t.Name)
+		c.Assert(err, IsNil)
+		c.Assert(e.String(), Equals, t.Expected)
+		c.Assert(e.ToNumber(), Equals, float64(t.Value))
+
+		e, err = ParseSetValue(elems, t.Value)
+		c.Assert(err, IsNil)
+		c.Assert(e.String(), Equals, t.Expected)
+	}
+
+	errTbl := []string{"e", "a,e", "16"}
+	for _, t := range errTbl {
+		_, err := ParseSetName(elems, t)
+		c.Assert(err, NotNil, Commentf("%s", t))
+	}
+
+	_, err := ParseSetValue(elems, 16)
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,180 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// SQLMode is the type for MySQL sql_mode.
+// See https://dev.mysql.com/doc/refman/5.7/en/sql-mode.html
+type SQLMode int
+
+// consts for sql modes.
+const (
+	ModeRealAsFloat SQLMode = 1 << iota
+	ModePipesAsConcat
+	ModeANSIQuotes
+	ModeIgnoreSpace
+	ModeNotUsed
+	ModeOnlyFullGroupBy
+	ModeNoUnsignedSubtraction
+	ModeNoDirInCreate
+	ModePostgreSQL
+	ModeOracle
+	ModeMsSQL
+	ModeDb2
+	ModeMaxdb
+	ModeNoKeyOptions
+	ModeNoTableOptions
+	ModeNoFieldOptions
+	ModeMySQL323
+	ModeMySQL40
+	ModeANSI
+	ModeNoAutoValueOnZero
+	ModeNoBackslashEscapes
+	ModeStrictTransTables
+	ModeStrictAllTables
+	ModeNoZeroInDate
+	ModeNoZeroDate
+	ModeInvalidDates
+	ModeErrorForDivisionByZero
+	ModeTraditional
+	ModeNoAutoCreateUser
+	ModeHighNotPrecedence
+	ModeNoEngineSubstitution
+	ModePadCharToFullLength
+
+	ModeNone SQLMode = 0
+)
+
+// sqlModeNames lists the modes in bit order, it is used to format a SQLMode.
+var sqlModeNames = []struct {
+	name string
+	mode SQLMode
+}{
+	{"REAL_AS_FLOAT", ModeRealAsFloat},
+	{"PIPES_AS_CONCAT", ModePipesAsConcat},
+	{"ANSI_QUOTES", ModeANSIQuotes},
+	{"IGNORE_SPACE", ModeIgnoreSpace},
+	{"NOT_USED", ModeNotUsed},
+	{"ONLY_FULL_GROUP_BY", ModeOnlyFullGroupBy},
+	{"NO_UNSIGNED_SUBTRACTION", ModeNoUnsignedSubtraction},
+	{"NO_DIR_IN_CREATE", ModeNoDirInCreate},
+	{"POSTGRESQL", ModePostgreSQL},
+	{"ORACLE", ModeOracle},
+	{"MSSQL", ModeMsSQL},
+	{"DB2", ModeDb2},
+	{"MAXDB", ModeMaxdb},
+	{"NO_KEY_OPTIONS", ModeNoKeyOptions},
+	{"NO_TABLE_OPTIONS", ModeNoTableOptions},
+	{"NO_FIELD_OPTIONS", ModeNoFieldOptions},
+	{"MYSQL323", ModeMySQL323},
+	{"MYSQL40", ModeMySQL40},
+	{"ANSI", ModeANSI},
+	{"NO_AUTO_VALUE_ON_ZERO", ModeNoAutoValueOnZero},
+	{"NO_BACKSLASH_ESCAPES", ModeNoBackslashEscapes},
+	{"STRICT_TRANS_TABLES", ModeStrictTransTables},
+	{"STRICT_ALL_TABLES", ModeStrictAllTables},
+	{"NO_ZERO_IN_DATE", ModeNoZeroInDate},
+	{"NO_ZERO_DATE", ModeNoZeroDate},
+	{"INVALID_DATES", ModeInvalidDates},
+	{"ERROR_FOR_DIVISION_BY_ZERO", ModeErrorForDivisionByZero},
+	{"TRADITIONAL", ModeTraditional},
+	{"NO_AUTO_CREATE_USER", ModeNoAutoCreateUser},
+	{"HIGH_NOT_PRECEDENCE", ModeHighNotPrecedence},
+	{"NO_ENGINE_SUBSTITUTION", ModeNoEngineSubstitution},
+	{"PAD_CHAR_TO_FULL_LENGTH", ModePadCharToFullLength},
+}
+
+// combinedModes maps the combination modes to the modes they turn on.
+var combinedModes = map[SQLMode]SQLMode{
+	ModeANSI: ModeRealAsFloat | ModePipesAsConcat | ModeANSIQuotes | ModeIgnoreSpace | ModeOnlyFullGroupBy,
+	ModeTraditional: ModeStrictTransTables | ModeStrictAllTables | ModeNoZeroInDate | ModeNoZeroDate |
+		ModeErrorForDivisionByZero | ModeNoAutoCreateUser | ModeNoEngineSubstitution,
+}
+
+// GetSQLMode parses a comma separated sql_mode string like "STRICT_TRANS_TABLES,ANSI_QUOTES".
+// The names are case insensitive, combination modes like ANSI and TRADITIONAL are expanded.
+func GetSQLMode(s string) (SQLMode, error) {
+	mode := ModeNone
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		m, ok := modeByName(name)
+		if !ok {
+			return ModeNone, errors.Errorf("invalid sql_mode %s", name)
+		}
+
+		mode |= m | combinedModes[m]
+	}
+
+	return mode, nil
+}
+
+func modeByName(name string) (SQLMode, bool) {
+	for _, v := range sqlModeNames {
+		if v.name == name {
+			return v.mode, true
+		}
+	}
+
+	return ModeNone, false
+}
+
+// String returns the comma separated mode names in the form sql_mode variable shows.
+func (m SQLMode) String() string {
+	var names []string
+	for _, v := range sqlModeNames {
+		if m&v.mode != 0 {
+			names = append(names, v.name)
+		}
+	}
+
+	return strings.Join(names, ",")
+}
+
+// Has returns whether all the bits of mode are set in m.
+func (m SQLMode) Has(mode SQLMode) bool {
+	return m&mode == mode
+}
+
+// HasStrictMode returns whether STRICT_TRANS_TABLES or STRICT_ALL_TABLES is set.
+func (m SQLMode) HasStrictMode() bool {
+	return m&(ModeStrictTransTables|ModeStrictAllTables) != 0
+}
+
+// CheckZeroDate returns an error if t is a zero date that is not allowed under mode.
+// Zero dates are rejected only when both NO_ZERO_DATE and a strict mode are set,
+// otherwise MySQL accepts them, maybe with a warning.
+func CheckZeroDate(t Time, mode SQLMode) error {
+	if !t.IsZero() || !mode.Has(ModeNoZeroDate) || !mode.HasStrictMode() {
+		return nil
+	}
+
+	tp := "datetime"
+	switch t.Type {
+	case TypeDate:
+		tp = "date"
+	case TypeTimestamp:
+		tp = "timestamp"
+	}
+
+	return NewDefaultError(ErTruncatedWrongValue, tp, t.String())
+}
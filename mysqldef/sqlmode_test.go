@@ -0,0 +1,75 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testSQLModeSuite{})
+
+type testSQLModeSuite struct {
+}
+
+func (s *testSQLModeSuite) TestGetSQLMode(c *C) {
+	c.Assert(ModeRealAsFloat, Equals, SQLMode(1))
+	c.Assert(ModeNoEngineSubstitution, Equals, SQLMode(1<<30))
+
+	tbl := []struct {
+		str    string
+		mode   SQLMode
+		result string
+	}{
+		{"", ModeNone, ""},
+		{"strict_trans_tables", ModeStrictTransTables, "STRICT_TRANS_TABLES"},
+		{" STRICT_TRANS_TABLES , no_engine_substitution", ModeStrictTransTables | ModeNoEngineSubstitution, "STRICT_TRANS_TABLES,NO_ENGINE_SUBSTITUTION"},
+		{"ANSI", ModeANSI | ModeRealAsFloat | ModePipesAsConcat | ModeANSIQuotes | ModeIgnoreSpace | ModeOnlyFullGroupBy,
+			"REAL_AS_FLOAT,PIPES_AS_CONCAT,ANSI_QUOTES,IGNORE_SPACE,ONLY_FULL_GROUP_BY,ANSI"},
+		{"TRADITIONAL", ModeTraditional | ModeStrictTransTables | ModeStrictAllTables | ModeNoZeroInDate | ModeNoZeroDate |
+			ModeErrorForDivisionByZero | ModeNoAutoCreateUser | ModeNoEngineSubstitution,
+			"STRICT_TRANS_TABLES,STRICT_ALL_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,TRADITIONAL,NO_AUTO_CREATE_USER,NO_ENGINE_SUBSTITUTION"},
+	}
+
+	for _, t := range tbl {
+		mode, err := GetSQLMode(t.str)
+		c.Assert(err, IsNil)
+		c.Assert(mode, Equals, t.mode)
+		c.Assert(mode.String(), Equals, t.result)
+	}
+
+	_, err := GetSQLMode("STRICT_TRANS_TABLES,NOT_A_MODE")
+	c.Assert(err, NotNil)
+}
+
+func (s *testSQLModeSuite) TestHasMode(c *C) {
+	mode := ModeStrictAllTables | ModeNoZeroDate
+	c.Assert(mode.HasStrictMode(), IsTrue)
+	c.Assert(mode.Has(ModeNoZeroDate), IsTrue)
+	c.Assert(mode.Has(ModeNoZeroDate|ModeANSIQuotes), IsFalse)
+	c.Assert(ModeNoZeroDate.HasStrictMode(), IsFalse)
+}
+
+func (s *testSQLModeSuite) TestCheckZeroDate(c *C) {
+	zero := Time{Time: ZeroTime, Type: TypeDate}
+	c.Assert(CheckZeroDate(zero, ModeNone), IsNil)
+	c.Assert(CheckZeroDate(zero, ModeNoZeroDate), IsNil)
+
+	err := CheckZeroDate(zero, ModeNoZeroDate|ModeStrictTransTables)
+	c.Assert(err, NotNil)
+	c.Assert(err.(*SQLError).Code, Equals, uint16(ErTruncatedWrongValue))
+
+	t, err := ParseDate("2015-12-31")
+	c.Assert(err, IsNil)
+	c.Assert(CheckZeroDate(t, ModeNoZeroDate|ModeStrictTransTables), IsNil)
+}
@@ -246,4 +246,10 @@ var MySQLState = map[uint16]string{
 	ErAlterOperationNotSupported:          "0A000",
 	ErAlterOperationNotSupportedReason:    "0A000",
 	ErDupUnknownInIndex:                   "23000",
+	ErInvalidJSONText:                     "22032",
+	ErInvalidJSONTextInParam:              "22032",
+	ErInvalidJSONPath:                     "42000",
+	ErInvalidTypeForJSON:                  "22032",
+	ErInvalidJSONPathWildcard:             "42000",
+	ErInvalidJSONPathArrayCell:            "42000",
 }
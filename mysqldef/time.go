@@ -129,6 +129,19 @@ func (t Time) String() string {
 	return t.Time.Format(tfStr)
 }
 
+// AtLocation returns the time with the same date and clock as t in location loc,
+// so the instant may be changed. The zero time is returned as it is.
+func (t Time) AtLocation(loc *time.Location) Time {
+	if t.IsZero() {
+		return t
+	}
+
+	year, month, day := t.Time.Date()
+	hour, minute, second := t.Time.Clock()
+	t.Time = time.Date(year, month, day, hour, minute, second, t.Time.Nanosecond(), loc)
+	return t
+}
+
 // IsZero returns a boolean indicating whether the time is equal to ZeroTime.
 func (t Time) IsZero() bool {
 	return t.Time.Equal(ZeroTime)
@@ -759,6 +772,11 @@ func checkTime(year int, month int, day int, hour int, minute int, second int, f
 		return errors.Trace(ErrInvalidTimeFormat)
 	}
 
+	// Like MySQL, the day must be in the month, e.g, 2015-02-30 is invalid.
+	if day > daysInMonth(year, time.Month(month)) {
+		return errors.Trace(ErrInvalidTimeFormat)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,418 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// Week modes, see: https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_week
+const (
+	weekMondayFirst  = 1
+	weekYear         = 2
+	weekFirstWeekday = 4
+)
+
+// weekMode converts the mode of WEEK to the behaviour flags.
+func weekMode(mode int) int {
+	mode &= 7
+	if mode&weekMondayFirst == 0 {
+		mode ^= weekFirstWeekday
+	}
+	return mode
+}
+
+// Week returns the week number of t in mode like WEEK(t, mode).
+func (t Time) Week(mode int) int {
+	_, week := calcWeek(t.Time, weekMode(mode))
+	return week
+}
+
+// YearWeek returns the year and the week number of t in mode like YEARWEEK(t, mode).
+func (t Time) YearWeek(mode int) (int, int) {
+	return calcWeek(t.Time, weekMode(mode)|weekYear)
+}
+
+// calcWeek calculates the year and week number like MySQL's calc_week.
+func calcWeek(t time.Time, behaviour int) (int, int) {
+	mondayFirst := behaviour&weekMondayFirst != 0
+	weekYearFlag := behaviour&weekYear != 0
+	firstWeekday := behaviour&weekFirstWeekday != 0
+
+	year, month, day := t.Date()
+	dayNr := daysFromEpoch(year, month, day)
+	firstDayNr := daysFromEpoch(year, time.January, 1)
+	weekday := calcWeekday(year, time.January, 1, !mondayFirst)
+
+	if month == time.January && day <= 7-weekday {
+		if !weekYearFlag && ((firstWeekday && weekday != 0) || (!firstWeekday && weekday >= 4)) {
+			return year, 0
+		}
+		weekYearFlag = true
+		year--
+		days := daysInYear(year)
+		firstDayNr -= days
+		weekday = (weekday + 53*7 - days) % 7
+	}
+
+	var days int
+	if (firstWeekday && weekday != 0) || (!firstWeekday && weekday >= 4) {
+		days = dayNr - (firstDayNr + (7 - weekday))
+	} else {
+		days = dayNr - (firstDayNr - weekday)
+	}
+
+	if weekYearFlag && days >= 52*7 {
+		weekday = (weekday + daysInYear(year)) % 7
+		if (!firstWeekday && weekday < 4) || (firstWeekday && weekday == 0) {
+			return year + 1, 1
+		}
+	}
+	return year, days/7 + 1
+}
+
+func daysFromEpoch(year int, month time.Month, day int) int {
+	return int(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
+
+// calcWeekday returns the weekday, 0 is Sunday if sundayFirst, otherwise 0 is Monday.
+func calcWeekday(year int, month time.Month, day int, sundayFirst bool) int {
+	wd := int(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday())
+	if sundayFirst {
+		return wd
+	}
+	return (wd + 6) % 7
+}
+
+func daysInYear(year int) int {
+	if (year%4 == 0 && year%100 != 0) || year%400 == 0 {
+		return 366
+	}
+	return 365
+}
+
+var abbrevMonthNames = []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
+
+// DateFormat formats t like DATE_FORMAT(t, layout).
+// See: https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-format
+func (t Time) DateFormat(layout string) string {
+	var buf []byte
+	tm := t.Time
+	year, month, day := tm.Date()
+	hour, minute, second := tm.Clock()
+	if t.IsZero() {
+		year, month, day, hour, minute, second = 0, 0, 0, 0, 0, 0
+	}
+
+	for i := 0; i < len(layout); i++ {
+		c := layout[i]
+		if c != '%' || i == len(layout)-1 {
+			buf = append(buf, c)
+			continue
+		}
+		i++
+		switch layout[i] {
+		case 'a':
+			buf = append(buf, tm.Weekday().String()[:3]...)
+		case 'b':
+			if month > 0 {
+				buf = append(buf, abbrevMonthNames[month-1]...)
+			}
+		case 'c':
+			buf = strconv.AppendInt(buf, int64(month), 10)
+		case 'D':
+			buf = append(buf, strconv.Itoa(day)+daySuffix(day)...)
+		case 'd':
+			buf = append(buf, fmt.Sprintf("%02d", day)...)
+		case 'e':
+			buf = strconv.AppendInt(buf, int64(day), 10)
+		case 'f':
+			buf = append(buf, fmt.Sprintf("%06d", tm.Nanosecond()/1000)...)
+		case 'H':
+			buf = append(buf, fmt.Sprintf("%02d", hour)...)
+		case 'h', 'I':
+			buf = append(buf, fmt.Sprintf("%02d", hour12(hour))...)
+		case 'i':
+			buf = append(buf, fmt.Sprintf("%02d", minute)...)
+		case 'j':
+			buf = append(buf, fmt.Sprintf("%03d", tm.YearDay())...)
+		case 'k':
+			buf = strconv.AppendInt(buf, int64(hour), 10)
+		case 'l':
+			buf = strconv.AppendInt(buf, int64(hour12(hour)), 10)
+		case 'M':
+			if month > 0 {
+				buf = append(buf, month.String()...)
+			}
+		case 'm':
+			buf = append(buf, fmt.Sprintf("%02d", month)...)
+		case 'p':
+			buf = append(buf, ampm(hour)...)
+		case 'r':
+			buf = append(buf, fmt.Sprintf("%02d:%02d:%02d %s", hour12(hour), minute, second, ampm(hour))...)
+		case 'S', 's':
+			buf = append(buf, fmt.Sprintf("%02d", second)...)
+		case 'T':
+			buf = append(buf, fmt.Sprintf("%02d:%02d:%02d", hour, minute, second)...)
+		case 'U':
+			_, w := calcWeek(tm, weekFirstWeekday)
+			buf = append(buf, fmt.Sprintf("%02d", w)...)
+		case 'u':
+			_, w := calcWeek(tm, weekMondayFirst)
+			buf = append(buf, fmt.Sprintf("%02d", w)...)
+		case 'V':
+			_, w := calcWeek(tm, weekYear|weekFirstWeekday)
+			buf = append(buf, fmt.Sprintf("%02d", w)...)
+		case 'v':
+			_, w := calcWeek(tm, weekYear|weekMondayFirst)
+			buf = append(buf, fmt.Sprintf("%02d", w)...)
+		case 'W':
+			buf = append(buf, tm.Weekday().String()...)
+		case 'w':
+			buf = strconv.AppendInt(buf, int64(tm.Weekday()), 10)
+		case 'X':
+			y, _ := calcWeek(tm, weekYear|weekFirstWeekday)
+			buf = append(buf, fmt.Sprintf("%04d", y)...)
+		case 'x':
+			y, _ := calcWeek(tm, weekYear|weekMondayFirst)
+			buf = append(buf, fmt.Sprintf("%04d", y)...)
+		case 'Y':
+			buf = append(buf, fmt.Sprintf("%04d", year)...)
+		case 'y':
+			buf = append(buf, fmt.Sprintf("%02d", year%100)...)
+		default:
+			// %% and the unknown specifiers are the characters themselves.
+			buf = append(buf, layout[i])
+		}
+	}
+	return string(buf)
+}
+
+func daySuffix(day int) string {
+	if day/10 == 1 {
+		return "th"
+	}
+	switch day % 10 {
+	case 1:
+		return "st"
+	case 2:
+		return "nd"
+	case 3:
+		return "rd"
+	}
+	return "th"
+}
+
+func hour12(hour int) int {
+	if hour%12 == 0 {
+		return 12
+	}
+	return hour % 12
+}
+
+func ampm(hour int) string {
+	if hour < 12 {
+		return "AM"
+	}
+	return "PM"
+}
+
+// StrToDate parses str with layout like STR_TO_DATE(str, layout). The result is a Duration
+// if layout has only time parts, a date Time if layout has only date parts, otherwise a datetime Time.
+// See: https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_str-to-date
+func StrToDate(str string, layout string) (interface{}, error) {
+	var (
+		year, month, day              int
+		hour, minute, second, micro   int
+		pm, hasAMPM, hasDate, hasTime bool
+	)
+
+	str = strings.TrimSpace(str)
+	for i := 0; i < len(layout); i++ {
+		c := layout[i]
+		if c != '%' || i == len(layout)-1 {
+			if c == ' ' {
+				str = strings.TrimLeft(str, " ")
+				continue
+			}
+			if len(str) == 0 || str[0] != c {
+				return nil, errors.Errorf("%s doesn't match format %s", str, layout)
+			}
+			str = str[1:]
+			continue
+		}
+
+		i++
+		var err error
+		switch layout[i] {
+		case 'Y':
+			year, str, err = scanNum(str, 4)
+			hasDate = true
+		case 'y':
+			year, str, err = scanNum(str, 2)
+			year = adjustYear(year)
+			hasDate = true
+		case 'm', 'c':
+			month, str, err = scanNum(str, 2)
+			hasDate = true
+		case 'd', 'e':
+			day, str, err = scanNum(str, 2)
+			hasDate = true
+		case 'M':
+			month, str, err = scanMonthName(str, false)
+			hasDate = true
+		case 'b':
+			month, str, err = scanMonthName(str, true)
+			hasDate = true
+		case 'H', 'k':
+			hour, str, err = scanNum(str, 2)
+			hasTime = true
+		case 'h', 'I', 'l':
+			hour, str, err = scanNum(str, 2)
+			hasAMPM, hasTime = true, true
+		case 'i':
+			minute, str, err = scanNum(str, 2)
+			hasTime = true
+		case 'S', 's':
+			second, str, err = scanNum(str, 2)
+			hasTime = true
+		case 'f':
+			var n int
+			start := len(str)
+			n, str, err = scanNum(str, 6)
+			// The digits are the fraction, e.g, 5 is 500000 microseconds.
+			for digits := start - len(str); digits < 6; digits++ {
+				n *= 10
+			}
+			micro = n
+			hasTime = true
+		case 'p':
+			if len(str) < 2 {
+				return nil, errors.Errorf("invalid AM/PM %s", str)
+			}
+			switch strings.ToUpper(str[:2]) {
+			case "AM":
+			case "PM":
+				pm = true
+			default:
+				return nil, errors.Errorf("invalid AM/PM %s", str)
+			}
+			str = str[2:]
+		case 'T':
+			var v interface{}
+			if v, err = StrToDate(prefix(str, 8), "%H:%i:%s"); err == nil {
+				d := v.(Duration)
+				hour, minute, second = d.Hour(), d.Minute(), d.Second()
+				str = str[len(prefix(str, 8)):]
+			}
+			hasTime = true
+		case 'r':
+			var v interface{}
+			if v, err = StrToDate(prefix(str, 11), "%h:%i:%s %p"); err == nil {
+				d := v.(Duration)
+				hour, minute, second = d.Hour(), d.Minute(), d.Second()
+				str = str[len(prefix(str, 11)):]
+			}
+			hasTime = true
+		default:
+			// %% and the unknown specifiers match the characters themselves.
+			if len(str) == 0 || str[0] != layout[i] {
+				return nil, errors.Errorf("%s doesn't match format %s", str, layout)
+			}
+			str = str[1:]
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	if hasAMPM {
+		if hour < 1 || hour > 12 {
+			return nil, errors.Errorf("invalid hour %d", hour)
+		}
+		hour %= 12
+		if pm {
+			hour += 12
+		}
+	}
+
+	if !hasDate {
+		if hour > 23 || minute > 59 || second > 59 {
+			return nil, errors.Errorf("invalid time %02d:%02d:%02d", hour, minute, second)
+		}
+		d := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute +
+			time.Duration(second)*time.Second + time.Duration(micro)*time.Microsecond
+		fsp := DefaultFsp
+		if micro > 0 {
+			fsp = MaxFsp
+		}
+		return Duration{Duration: d, Fsp: fsp}, nil
+	}
+
+	tp := TypeDatetime
+	if !hasTime {
+		tp = TypeDate
+	}
+	tm, err := newTime(year, month, day, hour, minute, second, micro)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	t := Time{Time: tm, Type: tp, Fsp: DefaultFsp}
+	if micro > 0 {
+		t.Fsp = MaxFsp
+	}
+	return t, nil
+}
+
+// scanNum scans a number of at most n digits at the beginning of str.
+func scanNum(str string, n int) (int, string, error) {
+	// Like MySQL, the spaces before a number are skipped.
+	str = strings.TrimLeft(str, " ")
+	end := 0
+	for end < len(str) && end < n && str[end] >= '0' && str[end] <= '9' {
+		end++
+	}
+	if end == 0 {
+		return 0, str, errors.Errorf("expect a number at %s", str)
+	}
+	v, err := strconv.Atoi(str[:end])
+	return v, str[end:], errors.Trace(err)
+}
+
+// scanMonthName scans the full or abbreviated month name at the beginning of str.
+func scanMonthName(str string, abbrev bool) (int, string, error) {
+	for i := time.January; i <= time.December; i++ {
+		name := i.String()
+		if abbrev {
+			name = name[:3]
+		}
+		if len(str) >= len(name) && strings.EqualFold(str[:len(name)], name) {
+			return int(i), str[len(name):], nil
+		}
+	}
+	return 0, str, errors.Errorf("expect a month name at %s", str)
+}
+
+// prefix returns at most the first n bytes of str.
+func prefix(str string, n int) string {
+	if len(str) < n {
+		return str
+	}
+	return str[:n]
+}
@@ -0,0 +1,117 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testTimeFormatSuite{})
+
+type testTimeFormatSuite struct {
+}
+
+func (s *testTimeFormatSuite) TestWeek(c *C) {
+	table := []struct {
+		Input  string
+		Mode   int
+		Expect int
+	}{
+		{"2008-02-20", 0, 7},
+		{"2008-02-20", 1, 8},
+		{"2008-12-31", 0, 52},
+		{"2008-12-31", 1, 53},
+		{"2000-01-01", 0, 0},
+		{"2000-01-01", 2, 52},
+		{"2000-01-01", 3, 52},
+		{"2000-01-01", 5, 0},
+		{"2000-01-01", 7, 52},
+	}
+	for _, t := range table {
+		tm, err := ParseDate(t.Input)
+		c.Assert(err, IsNil)
+		c.Assert(tm.Week(t.Mode), Equals, t.Expect, Commentf("%s %d", t.Input, t.Mode))
+	}
+
+	tm, err := ParseDate("2000-01-01")
+	c.Assert(err, IsNil)
+	year, week := tm.YearWeek(0)
+	c.Assert(year, Equals, 1999)
+	c.Assert(week, Equals, 52)
+}
+
+func (s *testTimeFormatSuite) TestDateFormat(c *C) {
+	tm, err := ParseTime("2009-10-04 22:23:00.000012", TypeDatetime, MaxFsp)
+	c.Assert(err, IsNil)
+
+	table := []struct {
+		Layout string
+		Expect string
+	}{
+		{"%W %M %Y", "Sunday October 2009"},
+		{"%H:%i:%s", "22:23:00"},
+		{"%D %y %a %d %m %b %j", "4th 09 Sun 04 10 Oct 277"},
+		{"%H %k %I %r %T %S %w", "22 22 10 10:23:00 PM 22:23:00 00 0"},
+		{"%X %V", "2009 40"},
+		{"%x %v %U %u", "2009 40 40 40"},
+		{"%e %c %l %p %f", "4 10 10 PM 000012"},
+		{"%% %q", "% q"},
+	}
+	for _, t := range table {
+		c.Assert(tm.DateFormat(t.Layout), Equals, t.Expect, Commentf("%s", t.Layout))
+	}
+
+	tm, err = ParseDate("1999-01-01")
+	c.Assert(err, IsNil)
+	c.Assert(tm.DateFormat("%X %V"), Equals, "1998 52")
+	c.Assert(tm.DateFormat("%D"), Equals, "1st")
+}
+
+func (s *testTimeFormatSuite) TestStrToDate(c *C) {
+	table := []struct {
+		Input  string
+		Layout string
+		Expect string
+	}{
+		{"01,5,2013", "%d,%m,%Y", "2013-05-01"},
+		{"May 1, 2013", "%M %d,%Y", "2013-05-01"},
+		{"a09:30:17", "a%h:%i:%s", "09:30:17"},
+		{"09:30:17 PM", "%r", "21:30:17"},
+		{"12:00:00 AM", "%h:%i:%s %p", "00:00:00"},
+		{"2013-05-01 10:11:12.5", "%Y-%m-%d %H:%i:%s.%f", "2013-05-01 10:11:12.500000"},
+		{"15-Jan-98", "%d-%b-%y", "1998-01-15"},
+		{"20130501", "%Y%m%d", "2013-05-01"},
+	}
+	for _, t := range table {
+		v, err := StrToDate(t.Input, t.Layout)
+		c.Assert(err, IsNil, Commentf("%s %s", t.Input, t.Layout))
+		c.Assert(v.(interface {
+			String() string
+		}).String(), Equals, t.Expect, Commentf("%s %s", t.Input, t.Layout))
+	}
+
+	errs := []struct {
+		Input  string
+		Layout string
+	}{
+		{"a09:30:17", "%h:%i:%s"},
+		{"2013-02-30", "%Y-%m-%d"},
+		{"13:00:00 PM", "%h:%i:%s %p"},
+		{"May", "%Y"},
+	}
+	for _, t := range errs {
+		_, err := StrToDate(t.Input, t.Layout)
+		c.Assert(err, NotNil, Commentf("%s %s", t.Input, t.Layout))
+	}
+}
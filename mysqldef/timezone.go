@@ -0,0 +1,94 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SystemTimeZone is the time_zone value for the time zone of the server.
+const SystemTimeZone = "SYSTEM"
+
+var timeZones = struct {
+	sync.RWMutex
+	m map[string]*time.Location
+}{m: make(map[string]*time.Location)}
+
+// ParseTimeZone parses a MySQL time zone value, which may be SYSTEM, an offset from UTC
+// like +08:00 and -05:30, or a named zone in the tz database like Asia/Shanghai.
+// See: https://dev.mysql.com/doc/refman/5.7/en/time-zone-support.html
+func ParseTimeZone(s string) (*time.Location, error) {
+	s = strings.TrimSpace(s)
+	if strings.EqualFold(s, SystemTimeZone) {
+		return time.Local, nil
+	}
+
+	if loc, ok := parseTimeZoneOffset(s); ok {
+		return loc, nil
+	}
+
+	// The named locations are loaded from the tz database, cache them.
+	timeZones.RLock()
+	loc, ok := timeZones.m[s]
+	timeZones.RUnlock()
+	if ok {
+		return loc, nil
+	}
+
+	// The tz database has no zone named Local, it means time.Local for LoadLocation.
+	if s == "" || strings.EqualFold(s, "local") {
+		return nil, NewDefaultError(ErUnknownTimeZone, s)
+	}
+	loc, err := time.LoadLocation(s)
+	if err != nil {
+		return nil, NewDefaultError(ErUnknownTimeZone, s)
+	}
+
+	timeZones.Lock()
+	timeZones.m[s] = loc
+	timeZones.Unlock()
+	return loc, nil
+}
+
+// parseTimeZoneOffset parses the offset like +08:00, the valid range is -13:59 to +14:00.
+func parseTimeZoneOffset(s string) (*time.Location, bool) {
+	if len(s) < 4 || (s[0] != '+' && s[0] != '-') {
+		return nil, false
+	}
+
+	seps := strings.Split(s[1:], ":")
+	if len(seps) != 2 || len(seps[1]) != 2 {
+		return nil, false
+	}
+	hour, err := strconv.Atoi(seps[0])
+	if err != nil || hour < 0 {
+		return nil, false
+	}
+	minute, err := strconv.Atoi(seps[1])
+	if err != nil || minute < 0 || minute > 59 {
+		return nil, false
+	}
+
+	offset := hour*3600 + minute*60
+	if s[0] == '-' {
+		offset = -offset
+	}
+	if offset <= -14*3600 || offset > 14*3600 {
+		return nil, false
+	}
+	return time.FixedZone(s, offset), true
+}
@@ -0,0 +1,73 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqldef
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testTimeZoneSuite{})
+
+type testTimeZoneSuite struct {
+}
+
+func (s *testTimeZoneSuite) TestParseTimeZone(c *C) {
+	loc, err := ParseTimeZone("system")
+	c.Assert(err, IsNil)
+	c.Assert(loc, Equals, time.Local)
+
+	offsets := []struct {
+		Input  string
+		Offset int
+	}{
+		{"+08:00", 8 * 3600},
+		{"-05:30", -(5*3600 + 30*60)},
+		{"+00:00", 0},
+		{"+14:00", 14 * 3600},
+		{"-13:59", -(13*3600 + 59*60)},
+	}
+	for _, t := range offsets {
+		loc, err = ParseTimeZone(t.Input)
+		c.Assert(err, IsNil, Commentf("%s", t.Input))
+		_, offset := time.Date(2015, 1, 1, 0, 0, 0, 0, loc).Zone()
+		c.Assert(offset, Equals, t.Offset, Commentf("%s", t.Input))
+	}
+
+	loc, err = ParseTimeZone("UTC")
+	c.Assert(err, IsNil)
+	c.Assert(loc.String(), Equals, "UTC")
+
+	errs := []string{"", "local", "+15:00", "-14:00", "+08:60", "+8", "08:00", "Not/A_Zone"}
+	for _, t := range errs {
+		_, err = ParseTimeZone(t)
+		c.Assert(err, NotNil, Commentf("%s", t))
+		c.Assert(errors.Cause(err).(*SQLError).Code, Equals, uint16(ErUnknownTimeZone), Commentf("%s", t))
+	}
+}
+
+func (s *testTimeZoneSuite) TestAtLocation(c *C) {
+	t, err := ParseTimestamp("2015-01-01 10:00:00")
+	c.Assert(err, IsNil)
+
+	loc := time.FixedZone("+08:00", 8*3600)
+	t2 := t.AtLocation(loc)
+	c.Assert(t2.String(), Equals, "2015-01-01 10:00:00")
+	c.Assert(t2.Time.UTC().Hour(), Equals, 2)
+
+	zero := Time{Time: ZeroTime, Type: TypeTimestamp}
+	c.Assert(zero.AtLocation(loc).IsZero(), IsTrue)
+}
@@ -36,7 +36,8 @@ const (
 
 // MySQL type informations.
 const (
-	TypeNewDecimal byte = iota + 0xf6
+	TypeJSON byte = iota + 0xf5
+	TypeNewDecimal
 	TypeEnum
 	TypeSet
 	TypeTinyBlob
@@ -104,7 +104,12 @@ func getDefaultValue(c *ConstraintOpt, tp byte, fsp int) (interface{}, error) {
 		return value, nil
 	}
 
-	return expressions.FastEval(c.Evalue), nil
+	value := expressions.FastEval(c.Evalue)
+	// Bit literal is stored as its number in the table meta.
+	if v, ok := value.(mysql.Bit); ok {
+		return v.Value, nil
+	}
+	return value, nil
 }
 
 func removeOnUpdateNowFlag(c *column.Col) {
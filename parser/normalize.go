@@ -0,0 +1,76 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Normalize returns the text of sql with the literals replaced by "?", the keywords in lower case
+// and the tokens separated by a single space, the comments are removed. A list of literals like
+// the one of "IN (1, 2, 3)" is replaced by "(...)", so the statements differing only in their
+// literals have the same normalized text.
+func Normalize(sql string) string {
+	l := NewLexer(sql)
+	var (
+		lval yySymType
+		toks []string
+	)
+	for {
+		tok := l.Lex(&lval)
+		if tok == 0 || tok == unicode.ReplacementChar {
+			break
+		}
+		switch tok {
+		case intLit, floatLit, stringLit, bitLit, imaginaryLit:
+			toks = append(toks, "?")
+		case identifier:
+			toks = append(toks, string(l.val))
+		default:
+			toks = append(toks, strings.ToLower(string(l.val)))
+		}
+	}
+	toks = collapseLiteralLists(toks)
+	for len(toks) > 0 && toks[len(toks)-1] == ";" {
+		toks = toks[:len(toks)-1]
+	}
+
+	text := strings.Join(toks, " ")
+	return strings.NewReplacer(" ,", ",", "( ", "(", " )", ")").Replace(text)
+}
+
+// collapseLiteralLists replaces the parenthesized lists of more than one literal by "(...)".
+func collapseLiteralLists(toks []string) []string {
+	var res []string
+	for i := 0; i < len(toks); i++ {
+		if toks[i] != "(" {
+			res = append(res, toks[i])
+			continue
+		}
+		j, n := i+1, 0
+		for ; j < len(toks) && (toks[j] == "?" || toks[j] == ","); j++ {
+			if toks[j] == "?" {
+				n++
+			}
+		}
+		if n > 1 && j < len(toks) && toks[j] == ")" {
+			res = append(res, "(...)")
+			i = j
+			continue
+		}
+		res = append(res, toks[i])
+	}
+	return res
+}
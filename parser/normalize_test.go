@@ -0,0 +1,36 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	. "github.com/pingcap/check"
+)
+
+func (s *testParserSuite) TestNormalize(c *C) {
+	table := []struct {
+		src    string
+		expect string
+	}{
+		{"SELECT * FROM t WHERE a = 1", "select * from t where a = ?"},
+		{"select  *\nfrom t where a = 'x' and b > 1.5 /* comment */ -- comment", "select * from t where a = ? and b > ?"},
+		{"select `A`, B from T where c in (1, 2, 3) and d in (4);", "select `A`, B from T where c in (...) and d in (?)"},
+		{"insert t values (1, 'a'), (2, 'b')", "insert t values (...), (...)"},
+		{"select count(*), sum(c) from t limit 10, 20", "select count (*), sum (c) from t limit ?, ?"},
+		{"select * from t where a = ? and b = b'01'", "select * from t where a = ? and b = ?"},
+		{"select @a, @@autocommit, null, true", "select @a, @@autocommit, null, true"},
+	}
+	for _, t := range table {
+		c.Assert(Normalize(t.src), Equals, t.expect, Commentf("%s", t.src))
+	}
+}
@@ -44,20 +44,25 @@ import (
 	/*yy:token "%di"    */	imaginaryLit	"imaginary literal"
 	/*yy:token "%d"     */	intLit          "integer literal"
 	/*yy:token "\"%c\"" */	stringLit       "string literal"
+	/*yy:token "b'%d'"  */	bitLit          "bit literal"
 
 
 	add		"ADD"
+	addDate		"ADDDATE"
 	after		"AFTER"
 	all 		"ALL"
 	alter		"ALTER"
+	analyze		"ANALYZE"
 	and		"AND"
 	andand		"&&"
+	any 		"ANY"
 	andnot		"&^"
 	as		"AS"
 	asc		"ASC"
 	autoIncrement	"AUTO_INCREMENT"
 	begin		"BEGIN"
 	between		"BETWEEN"
+	both		"BOTH"
 	by		"BY"
 	byteType	"BYTE"
 	caseKwd		"CASE"
@@ -65,15 +70,20 @@ import (
 	character	"CHARACTER"
 	charsetKwd	"CHARSET"
 	collation	"COLLATE"
+	collationKwd	"COLLATION"
 	column		"COLUMN"
 	columns		"COLUMNS"
 	commit		"COMMIT"
+	connection	"CONNECTION"
 	constraint	"CONSTRAINT"
 	convert		"CONVERT"
 	create		"CREATE"
 	cross 		"CROSS"
+	current		"CURRENT"
 	database	"DATABASE"
 	databases	"DATABASES"
+	dateAdd		"DATE_ADD"
+	dateSub		"DATE_SUB"
 	deallocate	"DEALLOCATE"
 	defaultKwd	"DEFAULT"
 	delayed		"DELAYED"
@@ -90,33 +100,48 @@ import (
 	end		"END"
 	engine		"ENGINE"
 	engines		"ENGINES"
+	enum		"ENUM"
 	eq		"="
+	errorsKwd	"ERRORS"
 	execute		"EXECUTE"
 	exists		"EXISTS"
 	explain		"EXPLAIN"
+	extract		"EXTRACT"
 	falseKwd	"false"
 	first		"FIRST"
 	foreign		"FOREIGN"
 	forKwd		"FOR"
+	following	"FOLLOWING"
+	format		"FORMAT"
 	from		"FROM"
 	full		"FULL"
 	fulltext	"FULLTEXT"
 	ge		">="
 	global		"GLOBAL"
+	grant		"GRANT"
+	grants		"GRANTS"
 	group		"GROUP"
 	having		"HAVING"
 	highPriority	"HIGH_PRIORITY"
+	identified	"IDENTIFIED"
 	ignore		"IGNORE"
 	ifKwd		"IF"
 	in		"IN"
 	index		"INDEX"
+	indexes		"INDEXES"
 	inner 		"INNER"
 	insert		"INSERT"
+	interval	"INTERVAL"
 	into		"INTO"
 	is		"IS"
 	join		"JOIN"
+	jsonExtract	"->"
+	jsonUnquoteExtract	"->>"
 	key		"KEY"
+	keys		"KEYS"
+	kill		"KILL"
 	le		"<="
+	leading		"LEADING"
 	left		"LEFT"
 	like		"LIKE"
 	limit		"LIMIT"
@@ -133,20 +158,32 @@ import (
 	null		"NULL"
 	offset		"OFFSET"
 	on		"ON"
+	option		"OPTION"
 	or		"OR"
 	order		"ORDER"
 	oror		"||"
 	outer		"OUTER"
+	over		"OVER"
+	partition	"PARTITION"
 	password	"PASSWORD"
+	preceding	"PRECEDING"
 	placeholder	"PLACEHOLDER"
 	prepare		"PREPARE"
+	privileges	"PRIVILEGES"
+	processlist	"PROCESSLIST"
 	primary		"PRIMARY"
+	query		"QUERY"
 	quick		"QUICK"
+	rangeKwd	"RANGE"
+	recursive	"RECURSIVE"
 	references	"REFERENCES"
 	regexp		"REGEXP"
+	revoke		"REVOKE"
 	right		"RIGHT"
 	rlike		"RLIKE"
 	rollback	"ROLLBACK"
+	row		"ROW"
+	rows		"ROWS"
 	rsh		">>"
 	runeType	"rune"
 	schema		"SCHEMA"
@@ -157,29 +194,44 @@ import (
 	share		"SHARE"
 	show		"SHOW"
 	signed		"SIGNED"
+	some		"SOME"
 	start		"START"
+	status		"STATUS"
 	stringType	"string"
+	subDate		"SUBDATE"
 	substring	"SUBSTRING"
+	super		"SUPER"
 	sysVar		"SYS_VAR"
 	tableKwd	"TABLE"
 	tables		"TABLES"
 	then		"THEN"
+	timestampAdd	"TIMESTAMPADD"
+	timestampDiff	"TIMESTAMPDIFF"
+	to		"TO"
+	trailing	"TRAILING"
 	transaction	"TRANSACTION"
+	trim		"TRIM"
 	trueKwd		"true"
 	truncate	"TRUNCATE"
+	usage		"USAGE"
 	unknown 	"UNKNOWN"
 	union		"UNION"
+	unbounded	"UNBOUNDED"
 	unique		"UNIQUE"
 	unsigned	"UNSIGNED"
 	update		"UPDATE"
 	use		"USE"
 	using		"USING"
+	user		"USER"
 	userVar		"USER_VAR"
 	value		"VALUE"
 	values		"VALUES"
+	variables	"VARIABLES"
 	warnings	"WARNINGS"
 	when		"WHEN"
 	where		"WHERE"
+	window		"WINDOW"
+	with		"WITH"
 	xor 		"XOR"
 	zerofill	"ZEROFILL"
 	
@@ -223,6 +275,7 @@ import (
 	textType	"TEXT"
 	mediumtextType	"MEDIUMTEXT"
 	longtextType	"LONGTEXT"
+	jsonType	"JSON"
 	
 	int16Type	"int16"
 	int24Type	"int24"
@@ -244,13 +297,16 @@ import (
 
 %type   <item>
 	AggAllOpt		"All option in aggregate function"
+	AnyOrAll		"Any or All for subquery"
 	AlterTableStmt		"Alter table statement"
+	AlterUserStmt		"Alter user statement"
 	AlterSpecification	"Alter table specification"
 	AlterSpecificationList	"Alter table specification list"
 	AsOpt			"as optional"
 	Assignment		"assignment"
 	AssignmentList		"assignment list"
 	AssignmentList1		"assignment list optional trailing comma"
+	AuthOption		"User authentication option"
 	AuthString		"Password string value"
 	BeginTransactionStmt	"BEGIN TRANSACTION statement"
 	CastType		"Cast function target type"
@@ -265,6 +321,10 @@ import (
 	ColumnSetValueList	"insert statement set value by column name list"
 	CommaOpt		"optional comma"
 	CommitStmt		"COMMIT statement"
+	CommonTableExpr		"common table expression"
+	CommonTableExprList	"common table expression list"
+	CommonTableExprColumnsOpt	"optional column name list of common table expression"
+	CompareOp		"Compare opcode"
 	Constraint		"column value constraint"
 	ConstraintElem		"table define constraint element"
 	ConstraintKeywordOpt	"Constraint Keyword or empty"
@@ -277,8 +337,10 @@ import (
 	CreateSpecification	"CREATE Database specification"
 	CreateSpecificationList	"CREATE Database specification list"
 	CreateTableStmt		"CREATE TABLE statement"
+	CreateUserStmt		"CREATE User statement"
 	CrossOpt		"Cross join option"
 	DBName			"Database Name"
+	DatabasesKwd		"DATABASES or SCHEMAS"
 	DeallocateSym		"Deallocate or drop"
 	DeallocateStmt		"Deallocate prepared statement"
 	Default			"DEFAULT clause"
@@ -290,11 +352,13 @@ import (
 	DropDatabaseStmt	"DROP DATABASE statement"
 	DropIndexStmt		"DROP INDEX statement"
 	DropTableStmt		"DROP TABLE statement"
+	DropUserStmt		"DROP USER statement"
 	EmptyStmt		"empty statement"
 	EqOpt			"= or empty"
 	EscapedTableRef 	"escaped table reference"
 	ExecuteStmt		"Execute statement"
 	ExplainSym		"EXPLAIN or DESCRIBE or DESC"
+	ExplainFormatOpt	"EXPLAIN FORMAT option"
 	ExplainStmt		"EXPLAIN statement"
 	Expression		"expression"
 	ExpressionList		"expression list"
@@ -305,19 +369,21 @@ import (
 	Field			"field expression"
 	Field1			"field expression optional AS clause"
 	FieldList		"field expression list"
-	ForUserOpt		"Set password for user option"
 	FromClause		"From clause"
 	Function		"function expr"
 	FunctionCall		"function call post part"
 	FunctionCallArgList	"function call optional argument list"
+	GrantStmt		"GRANT statement"
 	GroupByClause		"GROUP BY clause"
 	GroupByList		"GROUP BY list"
 	HavingClause		"HAVING clause"
+	GlobalScope		"The scope of variables"
 	IfExists		"If Exists"
 	IfNotExists		"If Not Exists"
 	IgnoreOptional		"IGNORE or empty"
 	IndexColName		"Index column name"
 	IndexColNameList	"List of index column name"
+	IndexKwd		"INDEX, INDEXES or KEYS"
 	IndexName		"index name"
 	IndexType		"index type"
 	InsertIntoStmt		"INSERT INTO statement"
@@ -326,6 +392,8 @@ import (
 	JoinTable 		"join table"
 	JoinType		"join type"
 	KeyOrIndex		"{KEY|INDEX}"
+	KillStmt		"KILL statement"
+	KillType		"KILL QUERY, KILL CONNECTION or KILL"
 	LimitClause		"LIMIT clause"
 	Literal			"literal value"
 	logAnd			"logical and operator"
@@ -341,6 +409,7 @@ import (
 	OptInteger		"Optional Integer keyword"
 	Order			"ORDER BY clause optional collation specification"
 	OrderBy			"ORDER BY clause"
+	OverClause		"OVER clause of window function"
 	OrderByItem		"ORDER BY list item"
 	OrderByOptional		"Optional ORDER BY clause optional"
 	OrderByList 		"ORDER BY list"
@@ -349,13 +418,19 @@ import (
 	QuickOptional		"QUICK or empty"
 	PasswordOpt		"Password option"
 	ColumnPosition		"Column position [First|After ColumnName]"
+	PrivElem		"Privilege element"
+	PrivElemList		"Privilege element list"
+	PrivLevel		"Privilege scope"
+	PrivType		"Privilege type"
 	PreparedStmt		"PreparedStmt"
 	PrepareSQL		"Prepare statement sql string"
 	PrimaryExpression	"primary expression"
 	PrimaryFactor		"primary expression factor"
 	Priority		"insert statement priority"
+	RecursiveOpt		"optional RECURSIVE"
 	ReferDef		"Reference definition"
 	RegexpSym		"REGEXP or RLIKE"
+	RevokeStmt		"REVOKE statement"
 	RollbackStmt		"ROLLBACK statement"
 	SelectLockOpt		"FOR UPDATE or LOCK IN SHARE MODE,"
 	SelectStmt		"SELECT statement"
@@ -369,6 +444,7 @@ import (
 	SelectStmtOrder		"SELECT statement optional ORDER BY clause"
 	SetStmt			"Set variable statement"
 	ShowStmt		"Show engines/databases/tables/columns/warnings statement"
+	ShowLikeOrWhereOpt	"Show like or where clause option"
 	ShowDatabaseNameOpt	"Show tables/columns statement database name option"
 	ShowTableIdentOpt	"Show columns statement table name option"
 	SignedLiteral		"Literal or NumLiteral with sign"
@@ -388,11 +464,17 @@ import (
 	TableOpts		"create table option list"
 	TableRef 		"table reference"
 	TableRefs 		"table references"
+	TimeUnit		"time unit"
+	TrimDirection		"Trim string direction"
 	TruncateTableStmt	"TRANSACTION TABLE statement"
 	UnionOpt		"Union Option(empty/ALL/DISTINCT)"
 	UnionStmt		"Union statement"
 	UpdateStmt		"UPDATE statement"
 	Username		"Username"
+	UsernameList		"Username list"
+	UsernamePart		"Username or host name part"
+	UserSpec		"Username and auth option"
+	UserSpecList		"Username and auth option list"
 	UserVariable		"User defined variable name"
 	UserVariableList	"User defined variable name list"
 	UseStmt			"USE statement"
@@ -402,6 +484,19 @@ import (
 	Variable		"User or system variable"
 	WhereClause		"WHERE clause"
 	WhereClauseOptional	"Optinal WHERE clause"
+	WindowClauseOptional	"optional WINDOW clause"
+	WindowDefinition	"named window definition"
+	WindowDefinitionList	"named window definition list"
+	WithGrantOptionOpt	"With Grant Option opt"
+	WindowFrameBound	"window frame bound"
+	WindowFrameExtent	"window frame extent"
+	WindowFrameOpt		"optional window frame"
+	WindowFrameUnit		"window frame unit"
+	WindowNameOpt		"optional window name"
+	WindowPartitionByOpt	"optional window PARTITION BY clause"
+	WindowSpec		"window specification"
+	WithClause		"WITH clause"
+	WithStmt		"statement with a WITH clause"
 
 	Identifier		"identifier or unreserved keyword"
 	UnReservedKeyword	"MySQL unreserved keywords"
@@ -422,6 +517,7 @@ import (
 	StringType		"String types"
 	BlobType		"Blob types"
 	TextType		"Text types"
+	TextStringList		"Text string list"
 
 	DateAndTimeType		"Date and Time types"
 
@@ -443,6 +539,9 @@ import (
 
 %token	tableRefPriority
 
+/* "||" is scanned as pipes instead of oror if PIPES_AS_CONCAT sql mode is set. */
+%token	pipes
+
 %left   join inner cross left right full
 /* A dummy token to force the priority of TableRef production in a join. */
 %left   tableRefPriority
@@ -457,6 +556,7 @@ import (
 %left 	'-' '+'
 %left 	'*' '/' '%' div mod
 %left 	'^'
+%left 	pipes
 %left 	'~' neg
 %right 	not
 
@@ -483,6 +583,18 @@ AlterTableStmt:
 		}
 	}
 
+/*******************************************************************
+ * See: https://dev.mysql.com/doc/refman/5.7/en/alter-user.html
+ *******************************************************************/
+AlterUserStmt:
+	"ALTER" "USER" IfExists UserSpecList
+	{
+		$$ = &stmts.AlterUserStmt{
+			IfExists: $3.(bool),
+			Specs:    $4.([]*stmts.UserSpecification),
+		}
+	}
+
 AlterSpecification:
 	TableOpts
 	{
@@ -1157,6 +1269,51 @@ DeleteFromStmt:
 	}
 	
 
+/*******************************************************************
+ * See: https://dev.mysql.com/doc/refman/5.7/en/create-user.html
+ *******************************************************************/
+CreateUserStmt:
+	"CREATE" "USER" IfNotExists UserSpecList
+	{
+		$$ = &stmts.CreateUserStmt{
+			IfNotExists: $3.(bool),
+			Specs:       $4.([]*stmts.UserSpecification),
+		}
+	}
+
+UserSpec:
+	Username AuthOption
+	{
+		x := &stmts.UserSpecification{User: $1.(string)}
+		if $2 != nil {
+			x.AuthOpt = $2.(*stmts.AuthOption)
+		}
+		$$ = x
+	}
+
+UserSpecList:
+	UserSpec
+	{
+		$$ = []*stmts.UserSpecification{$1.(*stmts.UserSpecification)}
+	}
+|	UserSpecList ',' UserSpec
+	{
+		$$ = append($1.([]*stmts.UserSpecification), $3.(*stmts.UserSpecification))
+	}
+
+AuthOption:
+	{
+		$$ = nil
+	}
+|	"IDENTIFIED" "BY" AuthString
+	{
+		$$ = &stmts.AuthOption{AuthString: $3.(string), ByAuthString: true}
+	}
+|	"IDENTIFIED" "BY" "PASSWORD" stringLit
+	{
+		$$ = &stmts.AuthOption{HashString: $4.(string)}
+	}
+
 DropDatabaseStmt:
 	"DROP" "DATABASE" IfExists Identifier
 	{
@@ -1166,11 +1323,21 @@ DropDatabaseStmt:
 		}
 	}
 
+DropUserStmt:
+	"DROP" "USER" IfExists UsernameList
+	{
+		$$ = &stmts.DropUserStmt{IfExists: $3.(bool), Users: $4.([]string)}
+	}
+
 DropIndexStmt:
 	"DROP" "INDEX" IfExists Identifier
 	{
 		$$ = &stmts.DropIndexStmt{IfExists: $3.(bool), IndexName: $4.(string)}
 	}
+|	"DROP" "INDEX" IfExists Identifier "ON" TableIdent
+	{
+		$$ = &stmts.DropIndexStmt{IfExists: $3.(bool), IndexName: $4.(string), TableIdent: $6.(table.Ident)}
+	}
 
 DropTableStmt:
 	"DROP" "TABLE" TableIdentList
@@ -1228,6 +1395,31 @@ ExplainStmt:
 	{
 		$$ = &stmts.ExplainStmt{S:$2.(stmt.Statement)}
 	}
+|	ExplainSym "FORMAT" eq Identifier Statement
+	{
+		if $5 == nil {
+			yylex.(*lexer).err("EXPLAIN FORMAT requires a statement")
+			return 1
+		}
+		$$ = &stmts.ExplainStmt{S: $5.(stmt.Statement), Format: $4.(string)}
+	}
+|	ExplainSym "ANALYZE" ExplainFormatOpt Statement
+	{
+		if $4 == nil {
+			yylex.(*lexer).err("EXPLAIN ANALYZE requires a statement")
+			return 1
+		}
+		$$ = &stmts.ExplainStmt{S: $4.(stmt.Statement), Analyze: true, Format: $3.(string)}
+	}
+
+ExplainFormatOpt:
+	{
+		$$ = ""
+	}
+|	"FORMAT" eq Identifier
+	{
+		$$ = $3.(string)
+	}
 
 LengthNum:
 	NUM
@@ -1316,35 +1508,61 @@ Factor:
 	{
 		$$ = &expressions.IsNull{Expr: $1.(expression.Expression), Not: $3.(bool)}
 	}
-|	Factor ">=" Factor1 %prec eq
+|	Factor CompareOp Factor1 %prec eq
 	{
-		$$ = expressions.NewBinaryOperation(opcode.GE, $1.(expression.Expression), $3.(expression.Expression))
+		$$ = expressions.NewBinaryOperation($2.(opcode.Op), $1.(expression.Expression), $3.(expression.Expression))
 	}
-|	Factor '>' Factor1 %prec eq
+|	Factor CompareOp AnyOrAll SubSelect %prec eq
 	{
-		$$ = expressions.NewBinaryOperation(opcode.GT, $1.(expression.Expression), $3.(expression.Expression))
+		$$ = &expressions.CompareSubQuery{L: $1.(expression.Expression), Op: $2.(opcode.Op), All: $3.(bool), R: $4.(*expressions.SubQuery)}
 	}
-|	Factor "<=" Factor1 %prec eq
+|	Factor1
+
+CompareOp:
+	">="
 	{
-		$$ = expressions.NewBinaryOperation(opcode.LE, $1.(expression.Expression), $3.(expression.Expression))
+		$$ = opcode.GE
 	}
-|	Factor '<' Factor1 %prec eq
+|	'>'
 	{
-		$$ = expressions.NewBinaryOperation(opcode.LT, $1.(expression.Expression), $3.(expression.Expression))
+		$$ = opcode.GT
 	}
-|	Factor "!=" Factor1 %prec eq
+|	"<="
 	{
-		$$ = expressions.NewBinaryOperation(opcode.NE, $1.(expression.Expression), $3.(expression.Expression))
+		$$ = opcode.LE
 	}
-|	Factor "<>" Factor1 %prec eq
+|	'<'
 	{
-		$$ = expressions.NewBinaryOperation(opcode.NE, $1.(expression.Expression), $3.(expression.Expression))
+		$$ = opcode.LT
 	}
-|	Factor "=" Factor1 %prec eq
+|	"!="
 	{
-		$$ = expressions.NewBinaryOperation(opcode.EQ, $1.(expression.Expression), $3.(expression.Expression))
+		$$ = opcode.NE
+	}
+|	"<>"
+	{
+		$$ = opcode.NE
+	}
+|	"="
+	{
+		$$ = opcode.EQ
+	}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/any-in-some-subqueries.html
+// See: https://dev.mysql.com/doc/refman/5.7/en/all-subqueries.html
+AnyOrAll:
+	"ANY"
+	{
+		$$ = false
+	}
+|	"SOME"
+	{
+		$$ = false
+	}
+|	"ALL"
+	{
+		$$ = true
 	}
-|	Factor1
 
 Factor1:
 	PrimaryFactor NotOpt "IN" '(' ExpressionList ')'
@@ -1448,6 +1666,137 @@ GroupByList:
 		$$ = append($1.([]expression.Expression), $3.(expression.Expression))
 	}
 
+// See: https://dev.mysql.com/doc/refman/8.0/en/window-functions-usage.html
+OverClause:
+	"OVER" Identifier
+	{
+		$$ = &expressions.WindowSpec{Ref: $2.(string)}
+	}
+|	"OVER" '(' WindowSpec ')'
+	{
+		$$ = $3
+	}
+
+WindowSpec:
+	WindowNameOpt WindowPartitionByOpt OrderByOptional WindowFrameOpt
+	{
+		spec := &expressions.WindowSpec{
+			Ref:		$1.(string),
+			PartitionBy:	$2.([]expression.Expression),
+		}
+		if $3 != nil {
+			for _, item := range $3.(*rsets.OrderByRset).By {
+				spec.OrderBy = append(spec.OrderBy, &expressions.WindowOrderByItem{Expr: item.Expr, Asc: item.Asc})
+			}
+		}
+		if $4 != nil {
+			spec.Frame = $4.(*expressions.WindowFrame)
+		}
+		$$ = spec
+	}
+
+WindowNameOpt:
+	{
+		$$ = ""
+	}
+|	Identifier
+
+WindowPartitionByOpt:
+	{
+		$$ = []expression.Expression(nil)
+	}
+|	"PARTITION" "BY" ExpressionList
+	{
+		$$ = $3
+	}
+
+WindowFrameOpt:
+	{
+		$$ = nil
+	}
+|	WindowFrameUnit WindowFrameExtent
+	{
+		f := $2.(*expressions.WindowFrame)
+		f.Unit = $1.(expressions.FrameUnit)
+		$$ = f
+	}
+
+WindowFrameUnit:
+	"ROWS"
+	{
+		$$ = expressions.FrameRows
+	}
+|	"RANGE"
+	{
+		$$ = expressions.FrameRange
+	}
+
+WindowFrameExtent:
+	WindowFrameBound
+	{
+		$$ = &expressions.WindowFrame{
+			Start:	$1.(expressions.FrameBound),
+			End:	expressions.FrameBound{Type: expressions.CurrentRow},
+		}
+	}
+|	"BETWEEN" WindowFrameBound "AND" WindowFrameBound
+	{
+		$$ = &expressions.WindowFrame{
+			Start:	$2.(expressions.FrameBound),
+			End:	$4.(expressions.FrameBound),
+		}
+	}
+
+WindowFrameBound:
+	"UNBOUNDED" "PRECEDING"
+	{
+		$$ = expressions.FrameBound{Type: expressions.UnboundedPreceding}
+	}
+|	"UNBOUNDED" "FOLLOWING"
+	{
+		$$ = expressions.FrameBound{Type: expressions.UnboundedFollowing}
+	}
+|	"CURRENT" "ROW"
+	{
+		$$ = expressions.FrameBound{Type: expressions.CurrentRow}
+	}
+|	NumLiteral "PRECEDING"
+	{
+		$$ = expressions.FrameBound{Type: expressions.Preceding, Expr: expressions.Value{$1}}
+	}
+|	NumLiteral "FOLLOWING"
+	{
+		$$ = expressions.FrameBound{Type: expressions.Following, Expr: expressions.Value{$1}}
+	}
+
+// See: https://dev.mysql.com/doc/refman/8.0/en/window-functions-named-windows.html
+WindowClauseOptional:
+	{
+		$$ = []*expressions.WindowSpec(nil)
+	}
+|	"WINDOW" WindowDefinitionList
+	{
+		$$ = $2
+	}
+
+WindowDefinitionList:
+	WindowDefinition
+	{
+		$$ = []*expressions.WindowSpec{$1.(*expressions.WindowSpec)}
+	}
+|	WindowDefinitionList ',' WindowDefinition
+	{
+		$$ = append($1.([]*expressions.WindowSpec), $3.(*expressions.WindowSpec))
+	}
+
+WindowDefinition:
+	Identifier "AS" '(' WindowSpec ')'
+	{
+		spec := $4.(*expressions.WindowSpec)
+		spec.Name = $1.(string)
+		$$ = spec
+	}
+
 HavingClause:
 	{
 		$$ = nil
@@ -1509,11 +1858,38 @@ Identifier:
 // TODO: Add Data Type UnReserved Keywords
 UnReservedKeyword:
 	"AUTO_INCREMENT" | "BEGIN" | "BIT" | "BOOL" | "BOOLEAN" | "CHARSET" | "COLUMN" | "COLUMNS" | "DATE" | "DATETIME"
-|	"ENGINE" | "FULL" | "LOCAL" | "NAMES" | "OFFSET" | "PASSWORD" | "QUICK" | "ROLLBACK" | "SESSION" | "GLOBAL" 
-|	"TABLES"| "TEXT" | "TIME" | "TIMESTAMP" | "TRANSACTION" | "TRUNCATE" | "VALUE" | "WARNINGS" | "YEAR" | "NOW"
-|	"SUBSTRING"
+|	"ENGINE" | "ENUM" | "FULL" | "LOCAL" | "NAMES" | "OFFSET" | "PASSWORD" | "QUICK" | "ROLLBACK" | "SESSION" | "GLOBAL" 
+|	"TABLES"| "TEXT" | "JSON" | "TIME" | "TIMESTAMP" | "TRANSACTION" | "TRUNCATE" | "VALUE" | "WARNINGS" | "YEAR" | "NOW"
+|	"SUBSTRING" | "CURRENT" | "FOLLOWING" | "PRECEDING" | "UNBOUNDED" | "ERRORS" | "USER" | "IDENTIFIED"
+|	"GRANTS" | "PRIVILEGES" | "COLLATION" | "INDEXES" | "PROCESSLIST" | "STATUS" | "VARIABLES"
+|	"CONNECTION" | "QUERY" | "FORMAT" | "ENGINES" | "SUPER"
 
 
+/************************************************************************************
+ *
+ *  Kill Statement
+ *  See: https://dev.mysql.com/doc/refman/5.7/en/kill.html
+ *
+ **********************************************************************************/
+KillStmt:
+	"KILL" KillType LengthNum
+	{
+		$$ = &stmts.KillStmt{Query: $2.(bool), ConnectionID: $3.(uint64)}
+	}
+
+KillType:
+	{
+		$$ = false
+	}
+|	"CONNECTION"
+	{
+		$$ = false
+	}
+|	"QUERY"
+	{
+		$$ = true
+	}
+
 /************************************************************************************
  *
  *  Insert Statments
@@ -1525,6 +1901,7 @@ InsertIntoStmt:
 	{
 		x := $6.(*stmts.InsertIntoStmt)
 		x.Priority = $2.(int)
+		x.Ignore = $3.(bool)
 		x.TableIdent = $5.(table.Ident)
 		if $7 != nil {
 			x.OnDuplicate = $7.([]expressions.Assignment)
@@ -1645,6 +2022,7 @@ Literal:
 |	imaginaryLit
 |	intLit
 |	stringLit
+|	bitLit
 
 Operand:
 	Literal
@@ -1655,6 +2033,29 @@ Operand:
 	{
 		$$ = &expressions.Ident{model.NewCIStr($1.(string))}
 	}
+|	QualifiedIdent "->" stringLit
+	{
+		// col->path is JSON_EXTRACT(col, path).
+		args := []expression.Expression{&expressions.Ident{model.NewCIStr($1.(string))}, expressions.Value{$3}}
+		var err error
+		if $$, err = expressions.NewCall("json_extract", args, false); err != nil {
+			yylex.(*lexer).err("%v", err)
+			return 1
+		}
+	}
+|	QualifiedIdent "->>" stringLit
+	{
+		// col->>path is JSON_UNQUOTE(JSON_EXTRACT(col, path)).
+		args := []expression.Expression{&expressions.Ident{model.NewCIStr($1.(string))}, expressions.Value{$3}}
+		x, err := expressions.NewCall("json_extract", args, false)
+		if err == nil {
+			$$, err = expressions.NewCall("json_unquote", []expression.Expression{x}, false)
+		}
+		if err != nil {
+			yylex.(*lexer).err("%v", err)
+			return 1
+		}
+	}
 |	'(' Expression ')'
 	{
 		$$ = &expressions.PExpr{Expr: expressions.Expr($2)}
@@ -1731,6 +2132,10 @@ PrimaryExpression:
 	Operand
 |	Function
 |	SubSelect
+|	"EXISTS" SubSelect
+	{
+		$$ = &expressions.ExistsSubQuery{Sel: $2.(*expressions.SubQuery)}
+	}
 |	'!' PrimaryExpression %prec neg
 	{
 		$$ = expressions.NewUnaryOperation(opcode.Not, $2.(expression.Expression))
@@ -1765,6 +2170,27 @@ Function:
 			return 1
 		}
 	}
+|	PrimaryExpression FunctionCall OverClause
+	{
+		x := yylex.(*lexer)
+		f, ok := $1.(*expressions.Ident)
+		if !ok {
+			x.err("expected identifier or qualified identifier")
+			return 1
+		}
+
+		args := $2.([]interface{})
+		if args[0].(bool) {
+			x.err("DISTINCT is not supported in window function %s", f.O)
+			return 1
+		}
+
+		var err error
+		if $$, err = expressions.NewWindowFunc(f.O, args[1].([]expression.Expression), $3.(*expressions.WindowSpec)); err != nil {
+			x.err("%v", err)
+			return 1
+		}
+	}
 |	FunctionNameConflict FunctionCall
 	{
 		x := yylex.(*lexer)
@@ -1838,6 +2264,141 @@ Function:
 			Len: $7.(expression.Expression),
 		}	
 	}
+|	"DATE_ADD" '(' Expression ',' "INTERVAL" Expression TimeUnit ')'
+	{
+		// See: https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-add
+		$$ = &expressions.FunctionDateArith{
+			Op: expressions.DateAdd,
+			Date: $3.(expression.Expression),
+			Interval: $6.(expression.Expression),
+			Unit: $7.(string),
+		}
+	}
+|	"DATE_SUB" '(' Expression ',' "INTERVAL" Expression TimeUnit ')'
+	{
+		$$ = &expressions.FunctionDateArith{
+			Op: expressions.DateSub,
+			Date: $3.(expression.Expression),
+			Interval: $6.(expression.Expression),
+			Unit: $7.(string),
+		}
+	}
+|	"ADDDATE" '(' Expression ',' "INTERVAL" Expression TimeUnit ')'
+	{
+		$$ = &expressions.FunctionDateArith{
+			Op: expressions.DateAdd,
+			Date: $3.(expression.Expression),
+			Interval: $6.(expression.Expression),
+			Unit: $7.(string),
+		}
+	}
+|	"ADDDATE" '(' Expression ',' Expression ')'
+	{
+		// The second argument is the number of days.
+		$$ = &expressions.FunctionDateArith{
+			Op: expressions.DateAdd,
+			Date: $3.(expression.Expression),
+			Interval: $5.(expression.Expression),
+			Unit: "DAY",
+		}
+	}
+|	"SUBDATE" '(' Expression ',' "INTERVAL" Expression TimeUnit ')'
+	{
+		$$ = &expressions.FunctionDateArith{
+			Op: expressions.DateSub,
+			Date: $3.(expression.Expression),
+			Interval: $6.(expression.Expression),
+			Unit: $7.(string),
+		}
+	}
+|	"SUBDATE" '(' Expression ',' Expression ')'
+	{
+		$$ = &expressions.FunctionDateArith{
+			Op: expressions.DateSub,
+			Date: $3.(expression.Expression),
+			Interval: $5.(expression.Expression),
+			Unit: "DAY",
+		}
+	}
+|	"EXTRACT" '(' TimeUnit "FROM" Expression ')'
+	{
+		// See: https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_extract
+		$$ = &expressions.FunctionExtract{
+			Unit: $3.(string),
+			Date: $5.(expression.Expression),
+		}
+	}
+|	"TIMESTAMPADD" '(' TimeUnit ',' Expression ',' Expression ')'
+	{
+		args := []expression.Expression{expressions.Value{$3}, $5.(expression.Expression), $7.(expression.Expression)}
+		var err error
+		if $$, err = expressions.NewCall("timestampadd", args, false); err != nil {
+			yylex.(*lexer).err("%v", err)
+			return 1
+		}
+	}
+|	"TIMESTAMPDIFF" '(' TimeUnit ',' Expression ',' Expression ')'
+	{
+		args := []expression.Expression{expressions.Value{$3}, $5.(expression.Expression), $7.(expression.Expression)}
+		var err error
+		if $$, err = expressions.NewCall("timestampdiff", args, false); err != nil {
+			yylex.(*lexer).err("%v", err)
+			return 1
+		}
+	}
+|	"TRIM" '(' Expression ')'
+	{
+		$$ = &expressions.FunctionTrim{
+			Str: $3.(expression.Expression),
+		}
+	}
+|	"TRIM" '(' Expression "FROM" Expression ')'
+	{
+		$$ = &expressions.FunctionTrim{
+			Str: $5.(expression.Expression),
+			RemStr: $3.(expression.Expression),
+		}
+	}
+|	"TRIM" '(' TrimDirection "FROM" Expression ')'
+	{
+		$$ = &expressions.FunctionTrim{
+			Str: $5.(expression.Expression),
+			Direction: $3.(expressions.TrimDirectionType),
+		}
+	}
+|	"TRIM" '(' TrimDirection Expression "FROM" Expression ')'
+	{
+		$$ = &expressions.FunctionTrim{
+			Str: $6.(expression.Expression),
+			RemStr: $4.(expression.Expression),
+			Direction: $3.(expressions.TrimDirectionType),
+		}
+	}
+
+TrimDirection:
+	"BOTH"
+	{
+		$$ = expressions.TrimBoth
+	}
+|	"LEADING"
+	{
+		$$ = expressions.TrimLeading
+	}
+|	"TRAILING"
+	{
+		$$ = expressions.TrimTrailing
+	}
+
+TimeUnit:
+	Identifier
+	{
+		unit := strings.ToUpper($1.(string))
+		if !mysql.IsTimeUnit(unit) {
+			yylex.(*lexer).err("unknown time unit %s", $1)
+			return 1
+		}
+		$$ = unit
+	}
 
 
 ExpressionOpt:
@@ -1948,6 +2509,18 @@ FunctionNameConflict:
 	{
 		$$ = expressions.BuiltinFuncLeft
 	}
+|	"RIGHT"
+	{
+		$$ = expressions.BuiltinFuncRight
+	}
+|	"INSERT"
+	{
+		$$ = expressions.BuiltinFuncInsert
+	}
+|	"MOD"
+	{
+		$$ = expressions.BuiltinFuncMod
+	}
 
 PrimaryFactor:
 	PrimaryFactor '|' PrimaryFactor %prec '|'
@@ -1974,6 +2547,33 @@ PrimaryFactor:
 	{
 		$$ = expressions.NewBinaryOperation(opcode.Minus, $1.(expression.Expression), $3.(expression.Expression))
 	}
+|	PrimaryFactor '+' "INTERVAL" Expression TimeUnit %prec '+'
+	{
+		$$ = &expressions.FunctionDateArith{
+			Op: expressions.DateAdd,
+			Date: $1.(expression.Expression),
+			Interval: $4.(expression.Expression),
+			Unit: $5.(string),
+		}
+	}
+|	PrimaryFactor '-' "INTERVAL" Expression TimeUnit %prec '-'
+	{
+		$$ = &expressions.FunctionDateArith{
+			Op: expressions.DateSub,
+			Date: $1.(expression.Expression),
+			Interval: $4.(expression.Expression),
+			Unit: $5.(string),
+		}
+	}
+|	"INTERVAL" Expression TimeUnit '+' PrimaryFactor %prec '+'
+	{
+		$$ = &expressions.FunctionDateArith{
+			Op: expressions.DateAdd,
+			Date: $5.(expression.Expression),
+			Interval: $2.(expression.Expression),
+			Unit: $3.(string),
+		}
+	}
 |	PrimaryFactor '*' PrimaryFactor %prec '*'
 	{
 		$$ = expressions.NewBinaryOperation(opcode.Mul, $1.(expression.Expression), $3.(expression.Expression))
@@ -1998,6 +2598,15 @@ PrimaryFactor:
 	{
 		$$ = expressions.NewBinaryOperation(opcode.Xor, $1.(expression.Expression), $3.(expression.Expression))
 	}
+|	PrimaryFactor pipes PrimaryFactor %prec pipes
+	{
+		var err error
+		args := []expression.Expression{$1.(expression.Expression), $3.(expression.Expression)}
+		if $$, err = expressions.NewCall("concat", args, false); err != nil {
+			yylex.(*lexer).err("%v", err)
+			return 1
+		}
+	}
 |	PrimaryExpression
 
 
@@ -2180,14 +2789,15 @@ SelectStmt:
 		}
 	}
 |	"SELECT" SelectStmtOpts SelectStmtFieldList "FROM" 
-	FromClause SelectStmtWhere SelectStmtGroup HavingClause SelectStmtOrder
+	FromClause SelectStmtWhere SelectStmtGroup HavingClause WindowClauseOptional SelectStmtOrder
 	SelectStmtLimit SelectLockOpt
 	{
 		st := &stmts.SelectStmt{
 			Distinct:	$2.(bool),
 			Fields:		$3.([]*field.Field),
 			From:		$5.(*rsets.JoinRset),
-			Lock:		$11.(coldef.LockType),
+			Windows:	$9.([]*expressions.WindowSpec),
+			Lock:		$12.(coldef.LockType),
 		}
 
 		if $6 != nil {
@@ -2202,12 +2812,12 @@ SelectStmt:
 			st.Having = $8.(*rsets.HavingRset)
 		}
 
-		if $9 != nil {
-			st.OrderBy = $9.(*rsets.OrderByRset)
+		if $10 != nil {
+			st.OrderBy = $10.(*rsets.OrderByRset)
 		}
 
-		if $10 != nil {
-			ay := $10.([]interface{})
+		if $11 != nil {
+			ay := $11.([]interface{})
 			st.Limit = ay[0].(*rsets.LimitRset)
 			st.Offset = ay[1].(*rsets.OffsetRset)
 		}
@@ -2465,9 +3075,13 @@ SetStmt:
 	{
 		$$ = &stmts.SetCharsetStmt{Charset: $3.(string)} 
 	}
-|	"SET" "PASSWORD" ForUserOpt eq PasswordOpt
+|	"SET" "PASSWORD" eq PasswordOpt
 	{
-		$$ = &stmts.SetPwdStmt{User: $3.(string), Password: $5.(string)} 
+		$$ = &stmts.SetPwdStmt{Password: $4.(string)}
+	}
+|	"SET" "PASSWORD" "FOR" Username eq PasswordOpt
+	{
+		$$ = &stmts.SetPwdStmt{User: $4.(string), Password: $6.(string)}
 	}
 
 VariableAssignment:
@@ -2553,76 +3167,377 @@ UserVariable:
 	}
 
 
-ForUserOpt:
+/* The account is represented as user@host, the host is '%' if it is not specified. */
+Username:
+	UsernamePart
 	{
-		$$ = "" 
+		$$ = $1.(string) + "@%"
 	}
-|	"FOR" Username
+|	UsernamePart '@' UsernamePart
 	{
-		$$ = $2.(string)
+		$$ = $1.(string) + "@" + $3.(string)
+	}
+|	UsernamePart "USER_VAR"
+	{
+		$$ = $1.(string) + $2.(string)
 	}
 
-Username:
+UsernamePart:
 	Identifier
 	{
 		$$ = $1.(string)
 	}
+|	stringLit
+	{
+		$$ = $1.(string)
+	}
+
+UsernameList:
+	Username
+	{
+		$$ = []string{$1.(string)}
+	}
+|	UsernameList ',' Username
+	{
+		$$ = append($1.([]string), $3.(string))
+	}
 
 PasswordOpt:
-	"PASSWORD" '(' AuthString ')' 
+	stringLit
+	{
+		$$ = $1.(string)
+	}
+|	"PASSWORD" '(' AuthString ')' 
 	{
 		$$ = $3.(string)
 	}
 
 AuthString:
-	Identifier
+	stringLit
 	{
 		$$ = $1.(string)
 	}
 
 /****************************Show Statement*******************************/
+/*******************************************************************
+ * See: https://dev.mysql.com/doc/refman/5.7/en/grant.html
+ *******************************************************************/
+GrantStmt:
+	"GRANT" PrivElemList "ON" PrivLevel "TO" UserSpecList WithGrantOptionOpt
+	{
+		$$ = &stmts.GrantStmt{
+			Privs:     $2.([]*stmts.PrivElem),
+			Level:     $4.(*stmts.GrantLevel),
+			Users:     $6.([]*stmts.UserSpecification),
+			WithGrant: $7.(bool),
+		}
+	}
+
+WithGrantOptionOpt:
+	{
+		$$ = false
+	}
+|	"WITH" "GRANT" "OPTION"
+	{
+		$$ = true
+	}
+
+PrivElem:
+	PrivType
+	{
+		$$ = $1.(*stmts.PrivElem)
+	}
+|	PrivType '(' ColumnNameList ')'
+	{
+		x := $1.(*stmts.PrivElem)
+		x.Cols = $3.([]string)
+		$$ = x
+	}
+
+PrivElemList:
+	PrivElem
+	{
+		$$ = []*stmts.PrivElem{$1.(*stmts.PrivElem)}
+	}
+|	PrivElemList ',' PrivElem
+	{
+		$$ = append($1.([]*stmts.PrivElem), $3.(*stmts.PrivElem))
+	}
+
+PrivType:
+	"ALL"
+	{
+		$$ = &stmts.PrivElem{All: true}
+	}
+|	"ALL" "PRIVILEGES"
+	{
+		$$ = &stmts.PrivElem{All: true}
+	}
+|	"ALTER"
+	{
+		$$ = &stmts.PrivElem{Priv: mysql.AlterPriv}
+	}
+|	"CREATE"
+	{
+		$$ = &stmts.PrivElem{Priv: mysql.CreatePriv}
+	}
+|	"CREATE" "USER"
+	{
+		$$ = &stmts.PrivElem{Priv: mysql.CreateUserPriv}
+	}
+|	"DELETE"
+	{
+		$$ = &stmts.PrivElem{Priv: mysql.DeletePriv}
+	}
+|	"DROP"
+	{
+		$$ = &stmts.PrivElem{Priv: mysql.DropPriv}
+	}
+|	"GRANT" "OPTION"
+	{
+		$$ = &stmts.PrivElem{Priv: mysql.GrantPriv}
+	}
+|	"INDEX"
+	{
+		$$ = &stmts.PrivElem{Priv: mysql.IndexPriv}
+	}
+|	"INSERT"
+	{
+		$$ = &stmts.PrivElem{Priv: mysql.InsertPriv}
+	}
+|	"SELECT"
+	{
+		$$ = &stmts.PrivElem{Priv: mysql.SelectPriv}
+	}
+|	"SUPER"
+	{
+		$$ = &stmts.PrivElem{Priv: mysql.SuperPriv}
+	}
+|	"UPDATE"
+	{
+		$$ = &stmts.PrivElem{Priv: mysql.UpdatePriv}
+	}
+|	"USAGE"
+	{
+		$$ = &stmts.PrivElem{}
+	}
+
+PrivLevel:
+	'*'
+	{
+		$$ = &stmts.GrantLevel{Level: stmts.GrantLevelDB}
+	}
+|	'*' '.' '*'
+	{
+		$$ = &stmts.GrantLevel{Level: stmts.GrantLevelGlobal}
+	}
+|	Identifier '.' '*'
+	{
+		$$ = &stmts.GrantLevel{Level: stmts.GrantLevelDB, DBName: $1.(string)}
+	}
+|	Identifier '.' Identifier
+	{
+		$$ = &stmts.GrantLevel{Level: stmts.GrantLevelTable, DBName: $1.(string), TableName: $3.(string)}
+	}
+|	Identifier
+	{
+		$$ = &stmts.GrantLevel{Level: stmts.GrantLevelTable, TableName: $1.(string)}
+	}
+
+/*******************************************************************
+ * See: https://dev.mysql.com/doc/refman/5.7/en/revoke.html
+ *******************************************************************/
+RevokeStmt:
+	"REVOKE" PrivElemList "ON" PrivLevel "FROM" UsernameList
+	{
+		$$ = &stmts.RevokeStmt{
+			Privs: $2.([]*stmts.PrivElem),
+			Level: $4.(*stmts.GrantLevel),
+			Users: $6.([]string),
+		}
+	}
+
 ShowStmt:
 	"SHOW" "ENGINES"
 	{
 		$$ = &stmts.ShowStmt{Target: stmt.ShowEngines}
 	}
-|	"SHOW" "DATABASES"
+|	"SHOW" DatabasesKwd ShowLikeOrWhereOpt
 	{
-		$$ = &stmts.ShowStmt{Target: stmt.ShowDatabases}
+		s := &stmts.ShowStmt{Target: stmt.ShowDatabases}
+		if x, ok := $3.(*expressions.PatternLike); ok {
+			s.Pattern = x
+		} else if $3 != nil {
+			s.Where = $3.(expression.Expression)
+		}
+		$$ = s
 	}
-|	"SHOW" "SCHEMAS"
+|	"SHOW" "CHARACTER" "SET" ShowLikeOrWhereOpt
 	{
-		$$ = &stmts.ShowStmt{Target: stmt.ShowDatabases}
+		s := &stmts.ShowStmt{Target: stmt.ShowCharset}
+		if x, ok := $4.(*expressions.PatternLike); ok {
+			s.Pattern = x
+		} else if $4 != nil {
+			s.Where = $4.(expression.Expression)
+		}
+		$$ = s
 	}
-|	"SHOW" "CHARACTER" "SET"
+|	"SHOW" "COLLATION" ShowLikeOrWhereOpt
 	{
-		$$ = &stmts.ShowStmt{Target: stmt.ShowCharset}
+		s := &stmts.ShowStmt{Target: stmt.ShowCollation}
+		if x, ok := $3.(*expressions.PatternLike); ok {
+			s.Pattern = x
+		} else if $3 != nil {
+			s.Where = $3.(expression.Expression)
+		}
+		$$ = s
 	}
-|	"SHOW" "TABLES" ShowDatabaseNameOpt
+|	"SHOW" "TABLES" ShowDatabaseNameOpt ShowLikeOrWhereOpt
 	{
-		$$ = &stmts.ShowStmt{
-			Target: stmt.ShowTables,
-			DBName: $3.(string)}
+		s := &stmts.ShowStmt{Target: stmt.ShowTables, DBName: $3.(string)}
+		if x, ok := $4.(*expressions.PatternLike); ok {
+			s.Pattern = x
+		} else if $4 != nil {
+			s.Where = $4.(expression.Expression)
+		}
+		$$ = s
 	}
-|	"SHOW" "TABLES" ShowDatabaseNameOpt
+|	"SHOW" "TABLE" "STATUS" ShowDatabaseNameOpt ShowLikeOrWhereOpt
 	{
-		$$ = &stmts.ShowStmt{
-			Target: stmt.ShowTables,
-			DBName: $3.(string)}
+		s := &stmts.ShowStmt{Target: stmt.ShowTableStatus, DBName: $4.(string)}
+		if x, ok := $5.(*expressions.PatternLike); ok {
+			s.Pattern = x
+		} else if $5 != nil {
+			s.Where = $5.(expression.Expression)
+		}
+		$$ = s
 	}
-|	"SHOW" OptFull "COLUMNS" ShowTableIdentOpt ShowDatabaseNameOpt
+|	"SHOW" OptFull "COLUMNS" ShowTableIdentOpt ShowDatabaseNameOpt ShowLikeOrWhereOpt
 	{
-		$$ = &stmts.ShowStmt{
+		s := &stmts.ShowStmt{
 			Target:     stmt.ShowColumns,
 			TableIdent: $4.(table.Ident),
 			DBName:     $5.(string),
-			Full:	    $2.(bool),	
+			Full:       $2.(bool),
+		}
+		if x, ok := $6.(*expressions.PatternLike); ok {
+			s.Pattern = x
+		} else if $6 != nil {
+			s.Where = $6.(expression.Expression)
 		}
+		$$ = s
+	}
+|	"SHOW" IndexKwd ShowTableIdentOpt ShowDatabaseNameOpt WhereClauseOptional
+	{
+		s := &stmts.ShowStmt{
+			Target:     stmt.ShowIndex,
+			TableIdent: $3.(table.Ident),
+			DBName:     $4.(string),
+		}
+		if $5 != nil {
+			s.Where = $5.(*rsets.WhereRset).Expr
+		}
+		$$ = s
+	}
+|	"SHOW" "CREATE" "TABLE" TableIdent
+	{
+		$$ = &stmts.ShowStmt{Target: stmt.ShowCreateTable, TableIdent: $4.(table.Ident)}
+	}
+|	"SHOW" CreateDatabase IfNotExists DBName
+	{
+		$$ = &stmts.ShowStmt{Target: stmt.ShowCreateDatabase, DBName: $4.(string)}
+	}
+|	"SHOW" GlobalScope "VARIABLES" ShowLikeOrWhereOpt
+	{
+		s := &stmts.ShowStmt{Target: stmt.ShowVariables, GlobalScope: $2.(bool)}
+		if x, ok := $4.(*expressions.PatternLike); ok {
+			s.Pattern = x
+		} else if $4 != nil {
+			s.Where = $4.(expression.Expression)
+		}
+		$$ = s
+	}
+|	"SHOW" GlobalScope "STATUS" ShowLikeOrWhereOpt
+	{
+		s := &stmts.ShowStmt{Target: stmt.ShowStatus, GlobalScope: $2.(bool)}
+		if x, ok := $4.(*expressions.PatternLike); ok {
+			s.Pattern = x
+		} else if $4 != nil {
+			s.Where = $4.(expression.Expression)
+		}
+		$$ = s
+	}
+|	"SHOW" OptFull "PROCESSLIST"
+	{
+		$$ = &stmts.ShowStmt{Target: stmt.ShowProcessList, Full: $2.(bool)}
+	}
+|	"SHOW" "GRANTS"
+	{
+		$$ = &stmts.ShowStmt{Target: stmt.ShowGrants}
+	}
+|	"SHOW" "GRANTS" "FOR" Username
+	{
+		$$ = &stmts.ShowStmt{Target: stmt.ShowGrants, User: $4.(string)}
 	}
 |	"SHOW" "WARNINGS"
 	{
 		$$ = &stmts.ShowStmt{Target: stmt.ShowWarnings}
 	}
+|	"SHOW" "ERRORS"
+	{
+		$$ = &stmts.ShowStmt{Target: stmt.ShowErrors}
+	}
+|	"SHOW" identifier '(' '*' ')' "WARNINGS"
+	{
+		if !strings.EqualFold($2.(string), "count") {
+			yylex.(*lexer).err("syntax error, expected COUNT(*) WARNINGS")
+			return 1
+		}
+		$$ = &stmts.ShowStmt{Target: stmt.ShowWarnings, CountWarnings: true}
+	}
+|	"SHOW" identifier '(' '*' ')' "ERRORS"
+	{
+		if !strings.EqualFold($2.(string), "count") {
+			yylex.(*lexer).err("syntax error, expected COUNT(*) ERRORS")
+			return 1
+		}
+		$$ = &stmts.ShowStmt{Target: stmt.ShowErrors, CountWarnings: true}
+	}
+
+DatabasesKwd:
+	"DATABASES"
+|	"SCHEMAS"
+
+IndexKwd:
+	"INDEX"
+|	"INDEXES"
+|	"KEYS"
+
+GlobalScope:
+	{
+		$$ = false
+	}
+|	"GLOBAL"
+	{
+		$$ = true
+	}
+|	"SESSION"
+	{
+		$$ = false
+	}
+
+ShowLikeOrWhereOpt:
+	{
+		$$ = nil
+	}
+|	"LIKE" PrimaryExpression
+	{
+		$$ = &expressions.PatternLike{Pattern: $2.(expression.Expression)}
+	}
+|	"WHERE" Expression
+	{
+		$$ = $2.(expression.Expression)
+	}
 
 OptFull:
 	{
@@ -2659,6 +3574,7 @@ ShowTableIdentOpt:
 Statement:
 	EmptyStmt
 |	AlterTableStmt
+|	AlterUserStmt
 |	BeginTransactionStmt
 |	CommitStmt
 |	DeallocateStmt
@@ -2668,12 +3584,17 @@ Statement:
 |	CreateDatabaseStmt
 |	CreateIndexStmt
 |	CreateTableStmt
+|	CreateUserStmt
 |	DoStmt
 |	DropDatabaseStmt
 |	DropIndexStmt
 |	DropTableStmt
+|	DropUserStmt
+|	GrantStmt
 |	InsertIntoStmt
+|	KillStmt
 |	PreparedStmt
+|	RevokeStmt
 |	RollbackStmt
 |	SelectStmt
 |	SetStmt
@@ -2682,6 +3603,7 @@ Statement:
 |	UnionStmt
 |	UpdateStmt
 |	UseStmt
+|	WithStmt
 
 StatementList:
 	Statement
@@ -2807,6 +3729,10 @@ Type:
 	{
 		$$ = $1
 	}
+|	"JSON"
+	{
+		$$ = types.NewFieldType(mysql.TypeJSON)
+	}
 |	"duration"
 	{
 		x := types.NewFieldType($1.(byte))
@@ -2895,6 +3821,9 @@ NumericType:
 	{
 		x := types.NewFieldType($1.(byte))
 		x.Flen = $2.(int)
+		if x.Flen == types.UnspecifiedLength {
+			x.Flen = mysql.MinBitWidth
+		}
 		$$ = x
 	}
 
@@ -2970,21 +3899,25 @@ BitValueType:
 	}
 
 StringType:
-	"CHAR" FieldLen OptBinary
+	"CHAR" FieldLen OptBinary OptCharset OptCollate
 	{
 		x := types.NewFieldType(mysql.TypeString)
 		x.Flen = $2.(int)
 		if $3.(bool) {
 			x.Flag |= mysql.BinaryFlag
 		}
+		x.Charset = $4.(string)
+		x.Collate = $5.(string)
 		$$ = x
 	}
-|	"CHAR" OptBinary
+|	"CHAR" OptBinary OptCharset OptCollate
 	{
 		x := types.NewFieldType(mysql.TypeString)
 		if $2.(bool) {
 			x.Flag |= mysql.BinaryFlag
 		}
+		x.Charset = $3.(string)
+		x.Collate = $4.(string)
 		$$ = x
 	}
 |	"VARCHAR" FieldLen OptBinary OptCharset OptCollate
@@ -3028,6 +3961,32 @@ StringType:
 		x.Collate = $4.(string)
 		$$ = x
 	}
+|	"ENUM" '(' TextStringList ')' OptCharset OptCollate
+	{
+		x := types.NewFieldType(mysql.TypeEnum)
+		x.Elems = $3.([]string)
+		x.Charset = $5.(string)
+		x.Collate = $6.(string)
+		$$ = x
+	}
+|	"SET" '(' TextStringList ')' OptCharset OptCollate
+	{
+		x := types.NewFieldType(mysql.TypeSet)
+		x.Elems = $3.([]string)
+		x.Charset = $5.(string)
+		x.Collate = $6.(string)
+		$$ = x
+	}
+
+TextStringList:
+	stringLit
+	{
+		$$ = []string{$1.(string)}
+	}
+|	TextStringList ',' stringLit
+	{
+		$$ = append($1.([]string), $3.(string))
+	}
 
 BlobType:
 	"TINYBLOB"
@@ -3248,6 +4207,7 @@ UpdateStmt:
 		}
 		st := &stmts.UpdateStmt{
 			LowPriority:    $2.(bool),
+			Ignore:         $3.(bool),
 			TableIdent:     $4.(table.Ident),
 			List:           $6.([]expressions.Assignment), 
 			Where:          expr} 
@@ -3289,6 +4249,92 @@ WhereClauseOptional:
 		$$ = $1
 	}
 
+WithStmt:
+	WithClause SelectStmt
+	{
+		st := $2.(*stmts.SelectStmt)
+		st.With = $1.(*stmts.WithClause)
+		$$ = st
+	}
+|	WithClause UnionStmt
+	{
+		st := $2.(*stmts.UnionStmt)
+		st.With = $1.(*stmts.WithClause)
+		$$ = st
+	}
+|	WithClause UpdateStmt
+	{
+		st := $2.(*stmts.UpdateStmt)
+		st.With = $1.(*stmts.WithClause)
+		$$ = st
+	}
+|	WithClause DeleteFromStmt
+	{
+		st := $2.(*stmts.DeleteStmt)
+		st.With = $1.(*stmts.WithClause)
+		$$ = st
+	}
+
+// See: https://dev.mysql.com/doc/refman/8.0/en/with.html
+WithClause:
+	"WITH" RecursiveOpt CommonTableExprList
+	{
+		$$ = &stmts.WithClause{
+			Recursive:	$2.(bool),
+			CTEs:		$3.([]*stmts.CommonTableExpr),
+		}
+	}
+
+RecursiveOpt:
+	{
+		$$ = false
+	}
+|	"RECURSIVE"
+	{
+		$$ = true
+	}
+
+CommonTableExprList:
+	CommonTableExpr
+	{
+		$$ = []*stmts.CommonTableExpr{$1.(*stmts.CommonTableExpr)}
+	}
+|	CommonTableExprList ',' CommonTableExpr
+	{
+		$$ = append($1.([]*stmts.CommonTableExpr), $3.(*stmts.CommonTableExpr))
+	}
+
+CommonTableExpr:
+	Identifier CommonTableExprColumnsOpt "AS" '(' SelectStmt ')'
+	{
+		s := $5.(stmt.Statement)
+		s.SetText(yylex.(*lexer).src[yyS[yypt - 1].col-1:yyS[yypt].col-1])
+		$$ = &stmts.CommonTableExpr{
+			Name:		model.NewCIStr($1.(string)),
+			ColNames:	$2.([]string),
+			Query:		s,
+		}
+	}
+|	Identifier CommonTableExprColumnsOpt "AS" '(' UnionStmt ')'
+	{
+		s := $5.(stmt.Statement)
+		s.SetText(yylex.(*lexer).src[yyS[yypt - 1].col-1:yyS[yypt].col-1])
+		$$ = &stmts.CommonTableExpr{
+			Name:		model.NewCIStr($1.(string)),
+			ColNames:	$2.([]string),
+			Query:		s,
+		}
+	}
+
+CommonTableExprColumnsOpt:
+	{
+		$$ = []string(nil)
+	}
+|	'(' ColumnNameList ')'
+	{
+		$$ = $2.([]string)
+	}
+
 SetOpt:
 	{
 	}
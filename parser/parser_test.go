@@ -18,6 +18,8 @@ import (
 	"testing"
 
 	. "github.com/pingcap/check"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/stmt/stmts"
 )
 
 func TestT(t *testing.T) {
@@ -49,6 +51,14 @@ func (s *testParserSuite) TestParser0(c *C) {
 		{"CREATE TABLE foo (a bigint unsigned, b bool);", true},
 		{"CREATE TABLE foo (a TINYINT, b SMALLINT) CREATE TABLE bar (x INT, y int64)", false},
 		{"CREATE TABLE foo (a int, b float); CREATE TABLE bar (x double, y float)", true},
+		{"CREATE TABLE foo (a char(10) character set latin1 collate latin1_bin, b char binary charset utf8)", true},
+		{"CREATE TABLE foo (a enum('x', 'y') charset utf8, b set('x', 'y'), c bit, d bit(8) default b'0101')", true},
+		{"CREATE TABLE foo (a enum())", false},
+		{"CREATE TABLE foo (a json, json int)", true},
+		{"SELECT a->'$.b', t.a->>'$.c[0]' FROM t WHERE a->'$.d' > 1", true},
+		{"SELECT a->b FROM t", false},
+		{"SELECT b'0101', B'', 0b11, b'012'", false},
+		{"SELECT b'0101', B'', 0b11", true},
 		{"INSERT INTO foo VALUES (1234)", true},
 		{"INSERT INTO foo VALUES (1234, 5678)", true},
 		// 15
@@ -198,6 +208,38 @@ func (s *testParserSuite) TestParser0(c *C) {
 		// global system variables
 		{"SET GLOBAL autocommit = 1", true},
 		{"SET @@global.autocommit = 1", true},
+		// set password
+		{"SET PASSWORD = 'password'", true},
+		{"SET PASSWORD FOR 'root'@'localhost' = PASSWORD('password')", true},
+		{"SET PASSWORD FOR root@localhost = 'password'", true},
+		{"SET PASSWORD FOR root = password", false},
+
+		// account management
+		{"CREATE USER 'root'@'localhost' IDENTIFIED BY 'x', test@'%', `u`", true},
+		{"CREATE USER IF NOT EXISTS root IDENTIFIED BY PASSWORD '*23AE809DDACAF96AF0FD78ED04B6A265E05AA257'", true},
+		{"CREATE USER root IDENTIFIED BY PASSWORD", false},
+		{"ALTER USER IF EXISTS root@localhost IDENTIFIED BY 'x', test", true},
+		{"DROP USER IF EXISTS 'root'@'localhost', test", true},
+		{"DROP USER", false},
+
+		// For grant and revoke statements.
+		{"GRANT ALL ON *.* TO 'root'@'%' WITH GRANT OPTION", true},
+		{"GRANT ALL PRIVILEGES ON test.* TO root", true},
+		{"GRANT SELECT (a, b), INSERT, CREATE USER ON test.t TO 'u'@'localhost' IDENTIFIED BY 'x', v", true},
+		{"GRANT USAGE ON * TO u", true},
+		{"GRANT GRANT OPTION ON t TO u", true},
+		{"GRANT SUPER ON *.* TO u", true},
+		{"DROP INDEX IF EXISTS i ON t", true},
+		{"DROP INDEX i ON", false},
+		{"select super from t", true},
+		{"GRANT SELECT ON t", false},
+		{"REVOKE SELECT, UPDATE (a) ON test.* FROM 'u'@'%', v", true},
+		{"REVOKE ALL ON *.* FROM u", true},
+		{"REVOKE SELECT ON t TO u", false},
+		{"SHOW GRANTS", true},
+		{"SHOW GRANTS FOR 'root'@'localhost'", true},
+		{"SHOW GRANTS FOR", false},
+		{"CREATE TABLE user (user int, identified int)", true},
 
 		// qualified select
 		{"SELECT a.b.c FROM t", true},
@@ -232,6 +274,39 @@ func (s *testParserSuite) TestParser0(c *C) {
 		{"select * from t1 right join t2 on t1.id = t2.id left join t3 on t3.id = t2.id", true},
 		{"select * from t1 right join t2 on t1.id = t2.id left join t3", false},
 
+		// subquery
+		{"select (select c from t2 where t2.id = t1.id) from t1", true},
+		{"select * from t1 where exists (select * from t2 where t2.id = t1.id)", true},
+		{"select * from t1 where not exists (select * from t2)", true},
+		{"select * from t1 where exists select * from t2", false},
+		{"select * from t1 where c > any (select c from t2)", true},
+		{"select * from t1 where c = some (select c from t2)", true},
+		{"select * from t1 where c <> all (select c from t2)", true},
+		{"select * from t1 where c >= all (1, 2)", false},
+		{"select * from t1 where c in (select c from t2)", true},
+
+		// common table expression
+		{"with t as (select 1) select * from t", true},
+		{"with t (a, b) as (select 1, 2), s as (select a from t) select * from s", true},
+		{"with recursive t (n) as (select 1 union all select n + 1 from t where n < 10) select * from t", true},
+		{"with t as (select 1) select * from t union select 2", true},
+		{"with t as (select id from t2) update t1 set c = 1 where id in (select id from t)", true},
+		{"with t as (select id from t2) delete from t1 where id in (select id from t)", true},
+		{"with t as select 1 select * from t", false},
+		{"with recursive select 1", false},
+
+		// window function
+		{"select row_number() over () from t", true},
+		{"select rank() over (partition by c1 order by c2 desc) from t", true},
+		{"select sum(c) over (order by id rows between 1 preceding and current row) from t", true},
+		{"select sum(c) over (order by id range between unbounded preceding and 1.5 following) from t", true},
+		{"select avg(c) over (order by id rows unbounded preceding) from t", true},
+		{"select lag(c, 1, 0) over w, lead(c) over (w order by id) from t window w as (partition by c1), w2 as (w)", true},
+		{"select c from t group by c having c > 1 window w as (order by c) order by c limit 1", true},
+		{"select count(distinct c) over () from t", false},
+		{"select sum(c) over (rows between 1 preceding) from t", false},
+		{"select current, following, preceding, unbounded from t", true},
+
 		// For default value
 		{"CREATE TABLE sbtest (id INTEGER UNSIGNED NOT NULL AUTO_INCREMENT, k integer UNSIGNED DEFAULT '0' NOT NULL, c char(120) DEFAULT '' NOT NULL, pad char(60) DEFAULT '' NOT NULL, PRIMARY KEY  (id) )", true},
 
@@ -245,6 +320,57 @@ func (s *testParserSuite) TestParser0(c *C) {
 
 		// For show character set
 		{"show character set;", true},
+		{"show character set like 'utf8%'", true},
+		{"show collation where Charset = 'utf8'", true},
+		{"show databases like 'test%'", true},
+		{"show schemas where `Database` = 'test'", true},
+		{"show tables from test like 't%'", true},
+		{"show table status from test where `Rows` > 0", true},
+		{"show columns from t like 'c%'", true},
+		{"show create table t", true},
+		{"show create table test.t", true},
+		{"show create database test", true},
+		{"show create schema if not exists test", true},
+		{"show index from t", true},
+		{"show indexes in t from test", true},
+		{"show keys from test.t where Key_name = 'PRIMARY'", true},
+		{"show index from t like 'c'", false},
+		{"show variables like 'auto%'", true},
+		{"show global variables where Variable_name = 'autocommit'", true},
+		{"show session status", true},
+		{"show processlist", true},
+		{"show full processlist", true},
+		{"select status, variables, processlist, indexes, collation, charset from t", true},
+		{"show engines", true},
+		{"select engine, support from information_schema.engines", true},
+
+		// For kill
+		{"kill 1", true},
+		{"kill query 1", true},
+		{"kill connection 1", true},
+		{"kill query", false},
+		{"kill 'a'", false},
+		{"select query, connection from t", true},
+
+		// For explain
+		{"explain select * from t", true},
+		{"desc t c", true},
+		{"explain analyze select * from t", true},
+		{"explain format = json select * from t", true},
+		{"explain analyze format = traditional select * from t", true},
+		{"explain format = json t", false},
+		{"explain format = json", false},
+		{"explain format c", true},
+		{"select format from t", true},
+
+		// For show warnings and errors
+		{"show warnings", true},
+		{"show errors", true},
+		{"show count(*) warnings", true},
+		{"show count(*) errors", true},
+		{"show sum(*) warnings", false},
+		{"select errors from t", true},
+		{"update ignore t set a = 1", true},
 		// For on duplicate key update
 		{"INSERT INTO t (a,b,c) VALUES (1,2,3),(4,5,6) ON DUPLICATE KEY UPDATE c=VALUES(a)+VALUES(b);", true},
 		{"INSERT IGNORE INTO t (a,b,c) VALUES (1,2,3),(4,5,6) ON DUPLICATE KEY UPDATE c=VALUES(a)+VALUES(b);", true},
@@ -257,6 +383,26 @@ func (s *testParserSuite) TestParser0(c *C) {
 		{"SELECT SUBSTRING('Quadratically' FROM 5);", true},
 		{"SELECT SUBSTRING('Quadratically' FROM 5 FOR 3);", true},
 
+		// For string functions
+		{"SELECT TRIM('  bar   '), TRIM('x' FROM 'xxxbarxxx'), TRIM(LEADING 'x' FROM 'xxxbarxxx');", true},
+		{"SELECT TRIM(BOTH FROM '  bar  '), TRIM(TRAILING 'xyz' FROM 'barxxyz');", true},
+		{"SELECT TRIM(LEADING 'x' 'xxxbarxxx');", false},
+		{"SELECT RIGHT('foobarbar', 4), INSERT('Quadratic', 3, 4, 'What'), REPLACE('abc', 'b', 'x');", true},
+		{"SELECT MOD(234, 10), 253 MOD 7, TRUNCATE(1.223, 1), ROUND(-1.58), CONV('a', 16, 2), SHA2('abc', 224), UUID();", true},
+
+		// For date arithmetic and time functions
+		{"SELECT DATE_ADD('2008-01-02', INTERVAL 31 DAY);", true},
+		{"SELECT DATE_SUB('2008-01-02', INTERVAL '1 1:1' DAY_MINUTE);", true},
+		{"SELECT ADDDATE('2008-01-02', INTERVAL 1 year), ADDDATE('2008-01-02', 31);", true},
+		{"SELECT SUBDATE('2008-01-02', INTERVAL 1 MONTH), SUBDATE('2008-01-02', 31);", true},
+		{"SELECT '2008-12-31 23:59:59' + INTERVAL 1 SECOND, '2005-01-01' - INTERVAL 1 SECOND;", true},
+		{"SELECT INTERVAL 1 DAY + '2008-12-31';", true},
+		{"SELECT '2008-12-31' + INTERVAL 1 DAYS;", false},
+		{"SELECT DATE_ADD('2008-01-02', 31);", false},
+		{"SELECT EXTRACT(YEAR_MONTH FROM '2009-07-02 01:02:03');", true},
+		{"SELECT EXTRACT(DAYS FROM '2009-07-02');", false},
+		{"SELECT TIMESTAMPDIFF(MONTH, '2003-02-01', '2003-05-01'), TIMESTAMPADD(WEEK, 1, '2003-01-02');", true},
+
 		// For delete statement
 		{"DELETE t1, t2 FROM t1 INNER JOIN t2 INNER JOIN t3 WHERE t1.id=t2.id AND t2.id=t3.id;", true},
 		{"DELETE FROM t1, t2 USING t1 INNER JOIN t2 INNER JOIN t3 WHERE t1.id=t2.id AND t2.id=t3.id;", true},
@@ -297,3 +443,37 @@ func (s *testParserSuite) TestParser0(c *C) {
 	c.Assert(ok, Equals, true)
 	c.Assert(len(l.Stmts()), Equals, 2)
 }
+
+func (s *testParserSuite) TestSQLModeLexer(c *C) {
+	table := []struct {
+		src  string
+		mode mysql.SQLMode
+		ok   bool
+		expr string
+	}{
+		{`SELECT "a" FROM t;`, mysql.ModeNone, true, `"a"`},
+		{`SELECT "a" FROM t;`, mysql.ModeANSIQuotes, true, "a"},
+		{`SELECT "a""b" FROM t;`, mysql.ModeANSIQuotes, true, "a\"b"},
+		{`SELECT "a`, mysql.ModeANSIQuotes, false, ""},
+		{`SELECT 'a' || 'b';`, mysql.ModeNone, true, `"a" || "b"`},
+		{`SELECT 'a' || 'b';`, mysql.ModePipesAsConcat, true, `concat("a", "b")`},
+		{`SELECT 'a' || 'b' = 'ab' OR 1;`, mysql.ModePipesAsConcat, true, `concat("a", "b") = "ab" || 1`},
+		{`SELECT 'a\nb';`, mysql.ModeNone, true, `"a\nb"`},
+		{`SELECT 'a\nb';`, mysql.ModeNoBackslashEscapes, true, `"a\\nb"`},
+		{`SELECT 'c:\';`, mysql.ModeNoBackslashEscapes, true, `"c:\\"`},
+		{`SELECT "it's", 'it''s';`, mysql.ModeNoBackslashEscapes, true, `"it's"`},
+	}
+
+	for _, t := range table {
+		l := NewLexer(t.src)
+		l.SetSQLMode(t.mode)
+		ok := yyParse(l) == 0
+		c.Assert(ok, Equals, t.ok, Commentf("%s", t.src))
+		if !ok {
+			continue
+		}
+
+		sel := l.Stmts()[0].(*stmts.SelectStmt)
+		c.Assert(sel.Fields[0].Expr.String(), Equals, t.expr, Commentf("%s", t.src))
+	}
+}
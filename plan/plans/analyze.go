@@ -0,0 +1,240 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plans
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/plan"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/util/format"
+)
+
+var _ plan.Plan = (*AnalyzePlan)(nil)
+
+// AnalyzePlan decorates a plan for EXPLAIN ANALYZE, it records how many times the plan is executed,
+// the rows it outputs, the time it takes and the reads of the KV store.
+type AnalyzePlan struct {
+	plan.Plan
+	// Children are the decorated source plans.
+	Children []*AnalyzePlan
+
+	// Loops is the number of times the plan is executed.
+	Loops int64
+	// Rows is the number of rows the plan outputs.
+	Rows int64
+	// Time is the wall time of the plan and its sources, the time spent by the consumers
+	// of the rows is excluded.
+	Time time.Duration
+	// KVReads is the number of the reads of the KV store by the plan and its sources.
+	KVReads uint64
+
+	// analyzed is set when the plan is executed, Explain writes the statistics then.
+	analyzed bool
+}
+
+var planType = reflect.TypeOf((*plan.Plan)(nil)).Elem()
+
+// Analyze decorates p and its sources with AnalyzePlan. The sources are the exported fields
+// of type plan.Plan or []plan.Plan of the plans, they are replaced by the decorated plans.
+func Analyze(p plan.Plan) *AnalyzePlan {
+	return analyzePlan(p, map[plan.Plan]*AnalyzePlan{})
+}
+
+func analyzePlan(p plan.Plan, decorated map[plan.Plan]*AnalyzePlan) *AnalyzePlan {
+	if a, ok := decorated[p]; ok {
+		return a
+	}
+	a := &AnalyzePlan{Plan: p}
+	decorated[p] = a
+
+	for _, f := range sources(p) {
+		c := analyzePlan(f.Interface().(plan.Plan), decorated)
+		f.Set(reflect.ValueOf(c))
+		a.Children = append(a.Children, c)
+	}
+	return a
+}
+
+// sources returns the non-nil exported fields of type plan.Plan or the elements of the exported fields
+// of type []plan.Plan of p, they are the source plans of p.
+func sources(p plan.Plan) []reflect.Value {
+	v := reflect.ValueOf(p)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	v = v.Elem()
+	var srcs []reflect.Value
+	add := func(f reflect.Value) {
+		if !f.IsNil() {
+			srcs = append(srcs, f)
+		}
+	}
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if !f.CanSet() {
+			continue
+		}
+		switch {
+		case f.Type() == planType:
+			add(f)
+		case f.Kind() == reflect.Slice && f.Type().Elem() == planType:
+			for j := 0; j < f.Len(); j++ {
+				add(f.Index(j))
+			}
+		}
+	}
+	return srcs
+}
+
+// Do implements the plan.Plan Do interface, the statistics are recorded.
+func (r *AnalyzePlan) Do(ctx context.Context, f plan.RowIterFunc) error {
+	var (
+		start         = time.Now()
+		reads         = variable.GetKVReads(ctx)
+		consumerTime  time.Duration
+		consumerReads uint64
+	)
+	r.Loops++
+	err := r.Plan.Do(ctx, func(rid interface{}, data []interface{}) (bool, error) {
+		r.Rows++
+		t, n := time.Now(), variable.GetKVReads(ctx)
+		more, err := f(rid, data)
+		consumerTime += time.Since(t)
+		consumerReads += variable.GetKVReads(ctx) - n
+		return more, err
+	})
+	r.Time += time.Since(start) - consumerTime
+	r.KVReads += variable.GetKVReads(ctx) - reads - consumerReads
+	return err
+}
+
+// RowsIn returns the number of rows the sources of the plan output.
+func (r *AnalyzePlan) RowsIn() int64 {
+	var n int64
+	for _, c := range r.Children {
+		n += c.Rows
+	}
+	return n
+}
+
+func (r *AnalyzePlan) setAnalyzed() {
+	r.analyzed = true
+	for _, c := range r.Children {
+		c.setAnalyzed()
+	}
+}
+
+// Explain implements the plan.Plan Explain interface. After the plan is executed,
+// a line of the statistics is added to the end of the explanation of the plan.
+func (r *AnalyzePlan) Explain(w format.Formatter) {
+	if !r.analyzed {
+		r.Plan.Explain(w)
+		return
+	}
+
+	// The explanation of the plan is indented in buf, so the indentation of its
+	// sources is kept when buf is written to w.
+	var buf bytes.Buffer
+	r.Plan.Explain(format.IndentFormatter(&buf, "│   "))
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) == 1 && len(lines[0]) == 0 {
+		lines = nil
+	}
+
+	stats := fmt.Sprintf("└Actual loops %d, rows in %d, rows out %d, time %v, kv reads %d",
+		r.Loops, r.RowsIn(), r.Rows, r.Time, r.KVReads)
+	last := len(lines) - 1
+	switch {
+	case last < 0 || strings.HasPrefix(lines[last], "└Actual "):
+		// The plan writes nothing of its own, the statistics are in a block of its own.
+		lines = append(lines, "┌"+r.Name(), stats)
+	case strings.HasPrefix(lines[last], "└"):
+		lines[last] = "│" + strings.TrimPrefix(lines[last], "└")
+		lines = append(lines, stats)
+	default:
+		lines = append(lines, stats)
+	}
+	for _, l := range lines {
+		w.Format("%s\n", l)
+	}
+}
+
+// Name returns the type name of the decorated plan.
+func (r *AnalyzePlan) Name() string {
+	t := reflect.TypeOf(r.Plan)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// ExplainNode is a plan in the output of EXPLAIN FORMAT=JSON.
+type ExplainNode struct {
+	Name string `json:"name"`
+	// Info is the explanation of the plan without its sources.
+	Info     []string       `json:"info,omitempty"`
+	Actual   *ExplainActual `json:"actual,omitempty"`
+	Children []*ExplainNode `json:"children,omitempty"`
+}
+
+// ExplainActual is the statistics of a plan executed by EXPLAIN ANALYZE FORMAT=JSON.
+type ExplainActual struct {
+	Loops   int64   `json:"loops"`
+	RowsIn  int64   `json:"rows_in"`
+	RowsOut int64   `json:"rows_out"`
+	TimeMs  float64 `json:"time_ms"`
+	KVReads uint64  `json:"kv_reads"`
+}
+
+// ExplainNode returns the tree of r for EXPLAIN FORMAT=JSON, the statistics are included
+// if withStats is true.
+func (r *AnalyzePlan) ExplainNode(withStats bool) *ExplainNode {
+	// The explanations of the sources are removed from the explanation of the plan.
+	info := explainText(r)
+	for _, c := range r.Children {
+		info = strings.Replace(info, explainText(c), "", 1)
+	}
+
+	n := &ExplainNode{Name: r.Name()}
+	for _, l := range strings.Split(info, "\n") {
+		if l = strings.TrimSpace(strings.TrimLeft(l, "┌│└ ")); len(l) > 0 {
+			n.Info = append(n.Info, l)
+		}
+	}
+	if withStats {
+		n.Actual = &ExplainActual{
+			Loops:   r.Loops,
+			RowsIn:  r.RowsIn(),
+			RowsOut: r.Rows,
+			TimeMs:  float64(r.Time) / float64(time.Millisecond),
+			KVReads: r.KVReads,
+		}
+	}
+	for _, c := range r.Children {
+		n.Children = append(n.Children, c.ExplainNode(withStats))
+	}
+	return n
+}
+
+func explainText(p plan.Plan) string {
+	var buf bytes.Buffer
+	p.Explain(format.IndentFormatter(&buf, ""))
+	return buf.String()
+}
@@ -0,0 +1,76 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plans_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	. "github.com/pingcap/check"
+	"github.com/Dong-Chan/alloydb"
+	"github.com/Dong-Chan/alloydb/plan/plans"
+)
+
+type testAnalyzeSuite struct{}
+
+var _ = Suite(&testAnalyzeSuite{})
+
+func (t *testAnalyzeSuite) TestAnalyze(c *C) {
+	testDB, err := sql.Open(alloydb.DriverName, alloydb.EngineGoLevelDBMemory+"test_analyze")
+	c.Assert(err, IsNil)
+	mustExec(c, testDB, "create table t1 (id int)")
+	mustExec(c, testDB, "create table t2 (id int, key i_id(id))")
+	mustExec(c, testDB, "insert into t1 values (1), (2), (3)")
+	mustExec(c, testDB, "insert into t2 values (1), (2)")
+
+	// Every plan has a line of its statistics, the plans writing nothing have a block of their own.
+	lines := strings.Split(mustExplain(c, testDB, "explain analyze select * from t1"), "\n")
+	c.Assert(lines[0], Equals, `┌Iterate all rows of table "t1"`)
+	c.Assert(lines[1], Equals, `│Output field names ["id"]`)
+	c.Assert(lines[2], Matches, `└Actual loops 1, rows in 0, rows out 3, time .*, kv reads [1-9][0-9]*`)
+	c.Assert(lines[3], Equals, "┌JoinPlan")
+	c.Assert(lines[4], Matches, `└Actual loops 1, rows in 3, rows out 3, .*`)
+
+	// The inner table of the join is read once for every row of the outer table.
+	s := mustExplain(c, testDB, "explain analyze select * from t1 left join t2 on t1.id = t2.id order by t1.id limit 2")
+	c.Assert(s, Matches, `(?s).*Iterate all rows of table "t2"\n.*\n└Actual loops 3, rows in 0, rows out 6, .*`)
+	c.Assert(s, Matches, `(?s).*Limit 2 records\n.*\n└Actual loops 1, rows in 2, rows out 2, .*`)
+
+	var node plans.ExplainNode
+	s = mustExplain(c, testDB, "explain format = json select count(*) from t2 where id > 1")
+	c.Assert(json.Unmarshal([]byte(s), &node), IsNil)
+	c.Assert(node.Name, Equals, "SelectFinalPlan")
+	c.Assert(node.Actual, IsNil)
+	for len(node.Children) > 0 {
+		node = *node.Children[0]
+	}
+	c.Assert(node.Name, Equals, "indexPlan")
+	c.Assert(node.Info[0], Equals, `Iterate rows of table "t2" using index "i_id" where id in (1,+inf]`)
+
+	s = mustExplain(c, testDB, "explain analyze format = json select count(*) from t2 where id > 1")
+	c.Assert(json.Unmarshal([]byte(s), &node), IsNil)
+	c.Assert(node.Actual.RowsOut, Equals, int64(1))
+	for len(node.Children) > 0 {
+		node = *node.Children[0]
+	}
+	c.Assert(*node.Actual, DeepEquals, plans.ExplainActual{Loops: 1, RowsOut: 1, TimeMs: node.Actual.TimeMs, KVReads: node.Actual.KVReads})
+	c.Assert(node.Actual.KVReads, Greater, uint64(0))
+
+	// The plans can be analyzed without a session.
+	p := plans.Analyze(&testTablePlan{[]*testRowData{{1, []interface{}{10}}, {2, []interface{}{20}}}, []string{"id"}})
+	c.Assert(p.Do(nil, func(id interface{}, data []interface{}) (bool, error) { return true, nil }), IsNil)
+	c.Assert(p.Rows, Equals, int64(2))
+	c.Assert(p.KVReads, Equals, uint64(0))
+}
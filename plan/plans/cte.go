@@ -0,0 +1,367 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plans
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/field"
+	"github.com/Dong-Chan/alloydb/kv/memkv"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/plan"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/util/format"
+	"github.com/Dong-Chan/alloydb/util/types"
+)
+
+var (
+	_ plan.Plan = (*CTEPlan)(nil)
+	_ plan.Plan = (*WithPlan)(nil)
+)
+
+// CTE is a common table expression defined in a WITH clause.
+// Its rows are saved in a temporary table when it is referenced the first time,
+// then all the references read the temporary table, so it is evaluated only once.
+// For a recursive CTE, Seeds are evaluated first, then Recursives are evaluated
+// repeatedly with the rows produced by the last iteration, until no new row is produced.
+// See: https://dev.mysql.com/doc/refman/8.0/en/with.html
+type CTE struct {
+	Name   string
+	Fields []*field.ResultField
+	// Seeds are the non-recursive query blocks.
+	Seeds []plan.Plan
+	// Recursives are the query blocks referencing the CTE itself.
+	Recursives []plan.Plan
+	// Distinct is true if the query blocks are combined with UNION DISTINCT,
+	// the duplicated rows are discarded.
+	Distinct bool
+	// Referenced is set when the CTE is resolved as a table,
+	// it is used to find the recursive query blocks when planning.
+	Referenced bool
+
+	rows memkv.Temp
+	// working is the rows produced by the last iteration, the recursive references read them.
+	working   [][]interface{}
+	recursing bool
+}
+
+// explain writes the plans of the query blocks of the CTE.
+func (c *CTE) explain(w format.Formatter) {
+	for _, p := range c.Seeds {
+		p.Explain(w)
+	}
+	for _, p := range c.Recursives {
+		p.Explain(w)
+	}
+	if len(c.Recursives) > 0 {
+		w.Format("┌Recursive CTE %s\n", c.Name)
+	} else {
+		w.Format("┌CTE %s\n", c.Name)
+	}
+	w.Format("└Output field names %v\n", field.RFQNames(c.Fields))
+}
+
+func (c *CTE) materialize(ctx context.Context) (err error) {
+	if c.rows != nil {
+		return nil
+	}
+
+	quota := variable.GetMemQuotaQuery(ctx)
+	rows, err := memkv.CreateTempWithQuota(true, quota)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var seen memkv.Temp
+	if c.Distinct {
+		if seen, err = memkv.CreateTempWithQuota(true, quota); err != nil {
+			rows.Drop()
+			return errors.Trace(err)
+		}
+		defer func() {
+			if derr := seen.Drop(); derr != nil && err == nil {
+				err = derr
+			}
+		}()
+	}
+
+	var (
+		n    int64
+		next [][]interface{}
+		// rfs is set after the first query block produced a row, the fields types are infered by it,
+		// the rows of the other query blocks are casted to them.
+		rfs []*field.ResultField
+	)
+	add := func(in []interface{}, cast bool) (err error) {
+		if cast {
+			for i := range in {
+				if in[i], err = rfs[i].Col.CastValue(ctx, in[i]); err != nil {
+					return err
+				}
+			}
+		}
+
+		if seen != nil {
+			v, err := seen.Get(in)
+			if err != nil {
+				return err
+			}
+			if len(v) > 0 {
+				return nil
+			}
+			if err = seen.Set(in, []interface{}{true}); err != nil {
+				return err
+			}
+		}
+
+		if err := rows.Set([]interface{}{n}, in); err != nil {
+			return err
+		}
+		n++
+		if len(c.Recursives) > 0 {
+			next = append(next, in)
+		}
+		return nil
+	}
+
+	doPlan := func(p plan.Plan) error {
+		if len(p.GetFields()) != len(c.Fields) {
+			return errors.Trace(mysql.NewDefaultError(mysql.ErWrongNumberOfColumnsInSelect))
+		}
+		cast := rfs != nil
+		return p.Do(ctx, func(id interface{}, in []interface{}) (bool, error) {
+			if err := add(in, cast); err != nil {
+				return false, err
+			}
+			if rfs == nil {
+				rfs = p.GetFields()
+			}
+			return true, nil
+		})
+	}
+
+	defer func() {
+		if err != nil {
+			rows.Drop()
+			return
+		}
+		c.rows = rows
+	}()
+
+	for _, p := range c.Seeds {
+		if err = doPlan(p); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	c.recursing = true
+	defer func() {
+		c.recursing = false
+		c.working = nil
+	}()
+
+	maxDepth := variable.GetCTEMaxRecursionDepth(ctx)
+	for depth := int64(1); len(next) > 0; depth++ {
+		if depth > maxDepth {
+			return errors.Trace(mysql.NewDefaultError(mysql.ErCteMaxRecursionDepth, depth))
+		}
+
+		c.working, next = next, nil
+		for _, p := range c.Recursives {
+			if err = doPlan(p); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *CTE) reset() error {
+	if c.rows == nil {
+		return nil
+	}
+	err := c.rows.Drop()
+	c.rows = nil
+	return err
+}
+
+// CTEPlan iterates the rows of a CTE, it is the plan for a reference to the CTE in FROM.
+type CTEPlan struct {
+	CTE    *CTE
+	Fields []*field.ResultField
+}
+
+// NewCTEPlan creates a plan to read the rows of cte, the table name of the fields is the CTE name.
+func NewCTEPlan(cte *CTE) *CTEPlan {
+	fields := make([]*field.ResultField, 0, len(cte.Fields))
+	for _, f := range cte.Fields {
+		nf := f.Clone()
+		nf.TableName = cte.Name
+		fields = append(fields, nf)
+	}
+	return &CTEPlan{CTE: cte, Fields: fields}
+}
+
+// Explain implements plan.Plan Explain interface.
+func (r *CTEPlan) Explain(w format.Formatter) {
+	w.Format("┌Iterate all rows of CTE %q\n└Output field names %v\n", r.CTE.Name, field.RFQNames(r.Fields))
+}
+
+// Filter implements plan.Plan Filter interface.
+func (r *CTEPlan) Filter(ctx context.Context, expr expression.Expression) (plan.Plan, bool, error) {
+	return r, false, nil
+}
+
+// GetFields implements plan.Plan GetFields interface.
+func (r *CTEPlan) GetFields() []*field.ResultField {
+	return r.Fields
+}
+
+// Do implements plan.Plan Do interface.
+// A recursive reference reads the rows produced by the last iteration of the CTE,
+// the others read all the rows of the CTE.
+func (r *CTEPlan) Do(ctx context.Context, f plan.RowIterFunc) (err error) {
+	if r.CTE.recursing {
+		for _, row := range r.CTE.working {
+			if more, err := f(nil, row); !more || err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err = r.CTE.materialize(ctx); err != nil {
+		return errors.Trace(err)
+	}
+
+	it, err := r.CTE.rows.SeekFirst()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var (
+		more bool
+		row  []interface{}
+	)
+	for {
+		if _, row, err = it.Next(); err != nil {
+			break
+		}
+		if more, err = f(nil, row); !more || err != nil {
+			break
+		}
+	}
+	return types.EOFAsNil(err)
+}
+
+// cteScopeKeyType is a dummy type to avoid naming collision in context.
+type cteScopeKeyType int
+
+// define a Stringer function for debugging and pretty printting
+func (k cteScopeKeyType) String() string {
+	return "cte_scope"
+}
+
+const cteScopeKey cteScopeKeyType = 0
+
+// CTEScope is the CTEs defined in a WITH clause, they can be referenced by name as tables
+// in the statement and its subqueries.
+type CTEScope struct {
+	CTEs   []*CTE
+	parent *CTEScope
+}
+
+// Bind binds the scope to ctx, its CTEs shadow the tables and the CTEs of the outer scopes with the same name.
+// The returned function restores the outer scope.
+func (s *CTEScope) Bind(ctx context.Context) (unbind func()) {
+	s.parent, _ = ctx.Value(cteScopeKey).(*CTEScope)
+	ctx.SetValue(cteScopeKey, s)
+	return func() {
+		if s.parent != nil {
+			ctx.SetValue(cteScopeKey, s.parent)
+		} else {
+			ctx.ClearValue(cteScopeKey)
+		}
+	}
+}
+
+// Close drops the rows of the CTEs, they are evaluated again if the CTEs are referenced later.
+func (s *CTEScope) Close() error {
+	var err error
+	for _, c := range s.CTEs {
+		if rerr := c.reset(); rerr != nil && err == nil {
+			err = rerr
+		}
+	}
+	return err
+}
+
+// LookupCTE returns the CTE with name in the scopes bound to ctx, the nearest scope is checked first.
+// It returns nil if the name is not found.
+func LookupCTE(ctx context.Context, name string) *CTE {
+	if ctx == nil {
+		return nil
+	}
+
+	for s, _ := ctx.Value(cteScopeKey).(*CTEScope); s != nil; s = s.parent {
+		for _, c := range s.CTEs {
+			if strings.EqualFold(c.Name, name) {
+				c.Referenced = true
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// WithPlan is the plan of a statement with a WITH clause,
+// the CTEs are available when Src is executing.
+type WithPlan struct {
+	Src   plan.Plan
+	Scope *CTEScope
+}
+
+// Explain implements plan.Plan Explain interface.
+func (r *WithPlan) Explain(w format.Formatter) {
+	for _, c := range r.Scope.CTEs {
+		c.explain(w)
+	}
+	r.Src.Explain(w)
+}
+
+// Filter implements plan.Plan Filter interface.
+func (r *WithPlan) Filter(ctx context.Context, expr expression.Expression) (plan.Plan, bool, error) {
+	return r, false, nil
+}
+
+// GetFields implements plan.Plan GetFields interface.
+func (r *WithPlan) GetFields() []*field.ResultField {
+	return r.Src.GetFields()
+}
+
+// Do implements plan.Plan Do interface.
+func (r *WithPlan) Do(ctx context.Context, f plan.RowIterFunc) (err error) {
+	unbind := r.Scope.Bind(ctx)
+	defer func() {
+		unbind()
+		if cerr := r.Scope.Close(); cerr != nil && err == nil {
+			err = errors.Trace(cerr)
+		}
+	}()
+
+	return r.Src.Do(ctx, f)
+}
@@ -0,0 +1,132 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plans
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/field"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/plan"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/util/format"
+	"github.com/Dong-Chan/alloydb/util/mock"
+	"github.com/juju/errors"
+)
+
+type testCTESuite struct{}
+
+var _ = Suite(&testCTESuite{})
+
+// testCTEStepPlan outputs n + 1 for every row n of Src less than Max.
+type testCTEStepPlan struct {
+	Src plan.Plan
+	Max int64
+}
+
+func (p *testCTEStepPlan) Do(ctx context.Context, f plan.RowIterFunc) error {
+	return p.Src.Do(ctx, func(id interface{}, in []interface{}) (bool, error) {
+		n := in[0].(int64)
+		if n >= p.Max {
+			return true, nil
+		}
+		return f(nil, []interface{}{n + 1})
+	})
+}
+
+func (p *testCTEStepPlan) Explain(w format.Formatter) {}
+
+func (p *testCTEStepPlan) GetFields() []*field.ResultField {
+	return p.Src.GetFields()
+}
+
+func (p *testCTEStepPlan) Filter(ctx context.Context, expr expression.Expression) (plan.Plan, bool, error) {
+	return p, false, nil
+}
+
+// testCTESeedPlan is Src with the fields of bigint type,
+// the rows of the recursive query blocks are casted to the fields.
+type testCTESeedPlan struct {
+	plan.Plan
+}
+
+func (p *testCTESeedPlan) GetFields() []*field.ResultField {
+	fields := p.Plan.GetFields()
+	for _, f := range fields {
+		f.Tp = mysql.TypeLonglong
+	}
+	return fields
+}
+
+func (t *testCTESuite) TestCTE(c *C) {
+	src := &testTablePlan{[]*testRowData{
+		&testRowData{1, []interface{}{int64(1)}},
+		&testRowData{2, []interface{}{int64(2)}},
+	}, []string{"n"}}
+
+	cte := &CTE{Name: "cte", Fields: src.GetFields(), Seeds: []plan.Plan{src}}
+	r := NewCTEPlan(cte)
+	c.Assert(r.GetFields()[0].TableName, Equals, "cte")
+
+	ctx := mock.NewContext()
+	scope := &CTEScope{CTEs: []*CTE{cte}}
+	unbind := scope.Bind(ctx)
+	c.Assert(LookupCTE(ctx, "CTE"), Equals, cte)
+	c.Assert(LookupCTE(ctx, "t"), IsNil)
+
+	// The rows are saved by the first reference.
+	var rows []interface{}
+	err := r.Do(ctx, func(id interface{}, in []interface{}) (bool, error) {
+		rows = append(rows, in[0])
+		return true, nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(cte.rows, NotNil)
+	c.Assert(rows, DeepEquals, []interface{}{int64(1), int64(2)})
+
+	c.Assert(scope.Close(), IsNil)
+	c.Assert(cte.rows, IsNil)
+	unbind()
+	c.Assert(LookupCTE(ctx, "cte"), IsNil)
+}
+
+func (t *testCTESuite) TestRecursiveCTE(c *C) {
+	src := &testTablePlan{[]*testRowData{
+		&testRowData{1, []interface{}{int64(1)}},
+	}, []string{"n"}}
+	seed := &testCTESeedPlan{src}
+
+	cte := &CTE{Name: "cte", Fields: seed.GetFields(), Seeds: []plan.Plan{seed}}
+	cte.Recursives = []plan.Plan{&testCTEStepPlan{Src: NewCTEPlan(cte), Max: 5}}
+	p := &WithPlan{Src: NewCTEPlan(cte), Scope: &CTEScope{CTEs: []*CTE{cte}}}
+
+	ctx := mock.NewContext()
+	var rows []interface{}
+	err := p.Do(ctx, func(id interface{}, in []interface{}) (bool, error) {
+		rows = append(rows, in[0])
+		return true, nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(rows, DeepEquals, []interface{}{int64(1), int64(2), int64(3), int64(4), int64(5)})
+	c.Assert(cte.rows, IsNil)
+
+	variable.BindSessionVars(ctx)
+	variable.GetSessionVars(ctx).Systems[variable.CTEMaxRecursionDepth] = "3"
+	err = p.Do(ctx, func(id interface{}, in []interface{}) (bool, error) {
+		return true, nil
+	})
+	c.Assert(err, NotNil)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErCteMaxRecursionDepth))
+}
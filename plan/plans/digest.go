@@ -0,0 +1,56 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plans
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/Dong-Chan/alloydb/plan"
+)
+
+// PlanDigest returns the digest of the shape of p, which are the types of p and its sources and
+// the tables and indices read by them. The plans of the statements differing only in literals
+// usually have the same digest.
+func PlanDigest(p plan.Plan) string {
+	h := sha256.New()
+	writePlanShape(h, p, map[plan.Plan]bool{})
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func writePlanShape(w io.Writer, p plan.Plan, written map[plan.Plan]bool) {
+	if written[p] {
+		return
+	}
+	written[p] = true
+
+	switch x := p.(type) {
+	case *AnalyzePlan:
+		writePlanShape(w, x.Plan, written)
+		return
+	case *TableDefaultPlan:
+		fmt.Fprintf(w, "%T(%s)", x, x.T.TableName())
+	case *indexPlan:
+		fmt.Fprintf(w, "%T(%s.%s)", x, x.src.TableName(), x.idxName)
+	default:
+		fmt.Fprintf(w, "%T", x)
+	}
+	srcs := sources(p)
+	fmt.Fprintf(w, "[%d]", len(srcs))
+	for _, f := range srcs {
+		writePlanShape(w, f.Interface().(plan.Plan), written)
+	}
+}
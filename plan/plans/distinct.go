@@ -16,8 +16,11 @@ package plans
 import (
 	"github.com/Dong-Chan/alloydb/context"
 	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/expression/expressions"
+	"github.com/Dong-Chan/alloydb/field"
 	"github.com/Dong-Chan/alloydb/kv/memkv"
 	"github.com/Dong-Chan/alloydb/plan"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
 	"github.com/Dong-Chan/alloydb/util/format"
 	"github.com/Dong-Chan/alloydb/util/types"
 )
@@ -44,9 +47,14 @@ func (r *DistinctDefaultPlan) Filter(ctx context.Context, expr expression.Expres
 }
 
 // Do : Distinct plan use an in-memory temp table for storing items that has same
-// key, the value in temp table is an array of record handles.
+// key, the value in temp table is an array of record handles. The strings in the
+// key are the sort keys of their collations, like the keys of GROUP BY.
+// The distinct rows are saved in another temp table with their sequence numbers
+// as keys to keep the order of them, both tables are spilled to disk if they
+// exceed the memory quota of the statement.
 func (r *DistinctDefaultPlan) Do(ctx context.Context, f plan.RowIterFunc) (err error) {
-	t, err := memkv.CreateTemp(true)
+	quota := variable.GetMemQuotaQuery(ctx)
+	t, err := memkv.CreateTempWithQuota(true, quota)
 	if err != nil {
 		return
 	}
@@ -57,10 +65,28 @@ func (r *DistinctDefaultPlan) Do(ctx context.Context, f plan.RowIterFunc) (err e
 		}
 	}()
 
-	var rows [][]interface{}
+	rows, err := memkv.CreateTempWithQuota(true, quota)
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if derr := rows.Drop(); derr != nil && err == nil {
+			err = derr
+		}
+	}()
+
+	m := map[interface{}]interface{}{}
+	m[expressions.ExprEvalCollationFunc] = func(name string) string {
+		return getIdentCollation(name, r.ResultFields, field.DefaultFieldFlag)
+	}
+	var n int64
 	if err = r.Src.Do(ctx, func(id interface{}, in []interface{}) (bool, error) {
 		// get distinct key
-		key := in[0:r.HiddenFieldOffset]
+		key := make([]interface{}, r.HiddenFieldOffset)
+		for i, v := range in[0:r.HiddenFieldOffset] {
+			key[i] = expressions.SortKey(r.Fields[i].Expr, m, v)
+		}
 		v, err := t.Get(key)
 		if err != nil {
 			return false, err
@@ -68,19 +94,33 @@ func (r *DistinctDefaultPlan) Do(ctx context.Context, f plan.RowIterFunc) (err e
 
 		if len(v) == 0 {
 			// no group for key, save data for this group
-			rows = append(rows, in)
+			if err := rows.Set([]interface{}{n}, in); err != nil {
+				return false, err
+			}
+			n++
 			if err := t.Set(key, []interface{}{true}); err != nil {
 				return false, err
 			}
 		}
 
 		return true, nil
-	}); err != nil {
+	}); err != nil || n == 0 {
 		return
 	}
 
-	var more bool
-	for _, row := range rows {
+	it, err := rows.SeekFirst()
+	if err != nil {
+		return
+	}
+
+	var (
+		more bool
+		row  []interface{}
+	)
+	for {
+		if _, row, err = it.Next(); err != nil {
+			break
+		}
 		if more, err = f(nil, row); !more || err != nil {
 			break
 		}
@@ -20,9 +20,13 @@ import (
 	. "github.com/pingcap/check"
 	"github.com/Dong-Chan/alloydb/context"
 	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/expression/expressions"
 	"github.com/Dong-Chan/alloydb/field"
+	"github.com/Dong-Chan/alloydb/model"
 	"github.com/Dong-Chan/alloydb/plan"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
 	"github.com/Dong-Chan/alloydb/util/format"
+	"github.com/Dong-Chan/alloydb/util/mock"
 )
 
 func TestT(t *testing.T) {
@@ -72,6 +76,19 @@ func (p *testTablePlan) Filter(ctx context.Context, expr expression.Expression)
 	return p, false, nil
 }
 
+// newIdentSelectList returns the select list of the fields of p.
+func newIdentSelectList(p plan.Plan) *SelectList {
+	sl := &SelectList{ResultFields: p.GetFields()}
+	for _, rf := range sl.ResultFields {
+		sl.Fields = append(sl.Fields, &field.Field{
+			Expr: &expressions.Ident{CIStr: model.NewCIStr(rf.Name)},
+			Name: rf.Name,
+		})
+	}
+	sl.HiddenFieldOffset = len(sl.Fields)
+	return sl
+}
+
 type testDistinctSuit struct{}
 
 var _ = Suite(&testDistinctSuit{})
@@ -80,10 +97,8 @@ func (t *testDistinctSuit) TestDistinct(c *C) {
 	tblPlan := &testTablePlan{distinctTestData, []string{"id", "name"}}
 
 	p := DistinctDefaultPlan{
-		SelectList: &SelectList{
-			HiddenFieldOffset: len(tblPlan.GetFields()),
-		},
-		Src: tblPlan,
+		SelectList: newIdentSelectList(tblPlan),
+		Src:        tblPlan,
 	}
 
 	r := map[int][]interface{}{}
@@ -101,3 +116,33 @@ func (t *testDistinctSuit) TestDistinct(c *C) {
 
 	c.Assert(reflect.DeepEqual(r, expected), Equals, true)
 }
+
+func (t *testDistinctSuit) TestDistinctSpill(c *C) {
+	var rows []*testRowData
+	for i := 0; i < 100; i++ {
+		rows = append(rows, &testRowData{int64(i), []interface{}{int64(i % 30), "hello"}})
+	}
+	tblPlan := &testTablePlan{rows, []string{"id", "name"}}
+
+	p := DistinctDefaultPlan{
+		SelectList: newIdentSelectList(tblPlan),
+		Src:        tblPlan,
+	}
+
+	ctx := mock.NewContext()
+	variable.BindSessionVars(ctx)
+	variable.GetSessionVars(ctx).Systems[variable.MemQuotaQuery] = "1024"
+
+	// The distinct rows are returned in the order of the source.
+	var ids []int64
+	err := p.Do(ctx, func(id interface{}, data []interface{}) (bool, error) {
+		c.Assert(data[1], Equals, "hello")
+		ids = append(ids, data[0].(int64))
+		return true, nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(ids, HasLen, 30)
+	for i, id := range ids {
+		c.Assert(id, Equals, int64(i))
+	}
+}
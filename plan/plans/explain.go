@@ -15,7 +15,10 @@ package plans
 
 import (
 	"bytes"
+	"encoding/json"
+	"strings"
 
+	"github.com/juju/errors"
 	"github.com/Dong-Chan/alloydb/context"
 	"github.com/Dong-Chan/alloydb/expression"
 	"github.com/Dong-Chan/alloydb/field"
@@ -30,17 +33,65 @@ var _ plan.Plan = (*ExplainDefaultPlan)(nil)
 // infomations.
 type ExplainDefaultPlan struct {
 	S stmt.Statement
+	// Analyze is set for EXPLAIN ANALYZE, S is a plan.Planner which is executed,
+	// and the statistics of every plan are explained.
+	Analyze bool
+	// JSON is set for EXPLAIN FORMAT=JSON, S is a plan.Planner, and its plan
+	// is explained as a JSON document in a single row.
+	JSON bool
 }
 
 // Do returns explain result lines.
 func (r *ExplainDefaultPlan) Do(ctx context.Context, f plan.RowIterFunc) error {
+	if r.Analyze || r.JSON {
+		return r.doPlan(ctx, f)
+	}
+
 	var buf bytes.Buffer
 	switch x := r.S.(type) {
 	default:
 		w := format.IndentFormatter(&buf, "│   ")
 		x.Explain(ctx, w)
 	}
+	return explainLines(&buf, f)
+}
+
+// doPlan explains the plan of S, the plan is executed first for EXPLAIN ANALYZE.
+func (r *ExplainDefaultPlan) doPlan(ctx context.Context, f plan.RowIterFunc) error {
+	p, err := r.S.(plan.Planner).Plan(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	a := Analyze(p)
+	if r.Analyze {
+		err = a.Do(ctx, func(id interface{}, data []interface{}) (bool, error) {
+			return true, nil
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	if r.JSON {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err = enc.Encode(a.ExplainNode(r.Analyze)); err != nil {
+			return errors.Trace(err)
+		}
+		_, err = f(nil, []interface{}{strings.TrimSuffix(buf.String(), "\n")})
+		return err
+	}
+
+	var buf bytes.Buffer
+	a.setAnalyzed()
+	a.Explain(format.IndentFormatter(&buf, "│   "))
+	return explainLines(&buf, f)
+}
 
+// explainLines outputs every line of buf as a row.
+func explainLines(buf *bytes.Buffer, f plan.RowIterFunc) error {
 	a := bytes.Split(buf.Bytes(), []byte{'\n'})
 	for _, v := range a[:len(a)-1] {
 		if more, err := f(nil, []interface{}{string(v)}); !more || err != nil {
@@ -56,6 +56,9 @@ func (r *SelectFieldsDefaultPlan) Filter(ctx context.Context, expr expression.Ex
 func (r *SelectFieldsDefaultPlan) Do(ctx context.Context, f plan.RowIterFunc) error {
 	fields := r.Src.GetFields()
 	m := map[interface{}]interface{}{}
+	m[expressions.ExprEvalCollationFunc] = func(name string) string {
+		return getIdentCollation(name, fields, field.DefaultFieldFlag)
+	}
 	return r.Src.Do(ctx, func(rid interface{}, in []interface{}) (bool, error) {
 		m[expressions.ExprEvalIdentFunc] = func(name string) (interface{}, error) {
 			return getIdentValue(name, fields, in, field.DefaultFieldFlag)
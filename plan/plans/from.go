@@ -25,6 +25,7 @@ import (
 	"github.com/Dong-Chan/alloydb/kv"
 	"github.com/Dong-Chan/alloydb/parser/opcode"
 	"github.com/Dong-Chan/alloydb/plan"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
 	"github.com/Dong-Chan/alloydb/table"
 	"github.com/Dong-Chan/alloydb/util"
 	"github.com/Dong-Chan/alloydb/util/format"
@@ -117,7 +118,7 @@ func (r *TableDefaultPlan) filterBinOp(ctx context.Context, x *expressions.Binar
 	}
 
 	ix := t.FindIndexByColName(cn)
-	if ix == nil { // Column cn has no index.
+	if ix == nil || !canRangeIndex(c) { // Column cn has no usable index.
 		return r, false, nil
 	}
 
@@ -135,8 +136,8 @@ func (r *TableDefaultPlan) filterBinOp(ctx context.Context, x *expressions.Binar
 		src:     t,
 		colName: cn,
 		idxName: ix.Name.O,
-		idx:     ix.X,
-		spans:   toSpans(x.Op, rval),
+		idx:     column.BaseIndex(ix.X),
+		spans:   toSpans(x.Op, c.SortKey(rval)),
 	}, true, nil
 }
 
@@ -166,7 +167,7 @@ func (r *TableDefaultPlan) filterIdent(ctx context.Context, x *expressions.Ident
 			src:     t,
 			colName: x.L,
 			idxName: ix.Name.L,
-			idx:     ix.X,
+			idx:     column.BaseIndex(ix.X),
 			spans:   spans,
 		}, true, nil
 	}
@@ -201,7 +202,7 @@ func (r *TableDefaultPlan) filterIsNull(ctx context.Context, x *expressions.IsNu
 		src:     t,
 		colName: cn,
 		idxName: ix.Name.L,
-		idx:     ix.X,
+		idx:     column.BaseIndex(ix.X),
 		spans:   spans,
 	}, true, nil
 }
@@ -271,7 +272,10 @@ func (r *TableDefaultPlan) Do(ctx context.Context, f plan.RowIterFunc) error {
 		// r2_col1 -> r2 col1 value
 		// r2_col2 -> r2 col2 value
 		// ...
-		var err error
+		if err = variable.CheckKilled(ctx); err != nil {
+			return err
+		}
+		variable.AddRowsExamined(ctx)
 		rowKey := it.Key()
 		h, err := util.DecodeHandleFromRowKey(rowKey)
 		if err != nil {
@@ -15,6 +15,8 @@ package plans
 
 import (
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/Dong-Chan/alloydb/context"
@@ -23,6 +25,8 @@ import (
 	"github.com/Dong-Chan/alloydb/field"
 	"github.com/Dong-Chan/alloydb/kv/memkv"
 	"github.com/Dong-Chan/alloydb/plan"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/util/codec"
 	"github.com/Dong-Chan/alloydb/util/format"
 	"github.com/Dong-Chan/alloydb/util/types"
 )
@@ -33,10 +37,16 @@ var (
 
 // GroupByDefaultPlan handles GROUP BY statement, GroupByDefaultPlan uses an
 // in-memory table to aggregate values.
+// If the groups exceed the memory quota of the statement, their states are
+// saved to disk, and the states of every group are merged at last.
+// If Streamed is true, the rows of Src are ordered by the group by items, so
+// the rows of a group are adjacent, and the group can be output once all its
+// rows are aggregated, without the in-memory table.
 type GroupByDefaultPlan struct {
 	*SelectList
-	Src plan.Plan
-	By  []expression.Expression
+	Src      plan.Plan
+	By       []expression.Expression
+	Streamed bool
 }
 
 // Explain implements plan.Plan Explain interface.
@@ -55,6 +65,9 @@ func (r *GroupByDefaultPlan) Explain(w format.Formatter) {
 		for _, v := range r.By {
 			w.Format(" %s,", v)
 		}
+		if r.Streamed {
+			w.Format("\n│Aggregate the ordered rows group by group")
+		}
 	}
 	w.Format("\n└Output field names %v\n", field.RFQNames(r.ResultFields))
 }
@@ -64,10 +77,23 @@ func (r *GroupByDefaultPlan) Filter(ctx context.Context, expr expression.Express
 	return r, false, nil
 }
 
+// aggregateCall is an aggregate function call mentioned in the field of r.Fields[Index].
+type aggregateCall struct {
+	*expressions.Call
+	Index int
+	// Collations are the collations of the args of the call.
+	Collations []string
+}
+
 type groupRow struct {
+	// Key is the group key.
+	Key []interface{}
 	Row []interface{}
-	// for one group by row, we should have a map to save aggregate value
-	Args map[interface{}]interface{}
+	// In is the first source row of the group, used to evaluate the expressions
+	// out of aggregate functions in aggregate fields.
+	In []interface{}
+	// Aggs are the accumulators of the aggregate calls.
+	Aggs []expressions.AggregateFunc
 }
 
 // Do implements plan.Plan Do interface.
@@ -83,6 +109,11 @@ type groupRow struct {
 // MKB114    1          Erica
 // refs: http://stackoverflow.com/questions/2421388/using-group-by-on-multiple-columns
 func (r *GroupByDefaultPlan) Do(ctx context.Context, f plan.RowIterFunc) (err error) {
+	calls := r.aggregateCalls()
+	if r.Streamed {
+		return r.doStreamed(ctx, calls, f)
+	}
+
 	// TODO: now we have to use this to save group key -> row index
 	// later we will serialize group by items into a string key and then use a map instead.
 	t, err := memkv.CreateTemp(true)
@@ -90,27 +121,77 @@ func (r *GroupByDefaultPlan) Do(ctx context.Context, f plan.RowIterFunc) (err er
 		return err
 	}
 
+	// save output group by result
+	var (
+		outRows []*groupRow
+		quota   = variable.GetMemQuotaQuery(ctx)
+		size    int64
+		// spilled saves the states of the groups spilled to disk, see spillGroups.
+		spilled memkv.Temp
+		seq     int64
+	)
 	defer func() {
-		if derr := t.Drop(); derr != nil && err == nil {
+		for _, row := range outRows {
+			if cerr := row.close(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+		if spilled == nil {
+			return
+		}
+		if derr := spilled.Drop(); derr != nil && err == nil {
 			err = derr
 		}
 	}()
 
-	k := make([]interface{}, len(r.By))
+	// The accumulators of DISTINCT and GROUP_CONCAT grow with the rows of the group.
+	var growing bool
+	for _, call := range calls {
+		growing = growing || call.Distinct || strings.EqualFold(call.F, "group_concat")
+	}
 
-	// save output group by result
-	var outRows []*groupRow
+	// spill saves the states of the groups in memory to disk and releases them.
+	spill := func() error {
+		if spilled == nil {
+			var err error
+			if spilled, err = memkv.CreateDiskTemp(true); err != nil {
+				return err
+			}
+		}
+		if err := spillGroups(spilled, outRows, seq); err != nil {
+			return err
+		}
+
+		var err error
+		for _, row := range outRows {
+			if cerr := row.close(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+		outRows = nil
+		if err != nil {
+			return err
+		}
+		if t, err = memkv.CreateTemp(true); err != nil {
+			return err
+		}
+		size = 0
+		seq++
+		return nil
+	}
 
 	err = r.Src.Do(ctx, func(rid interface{}, in []interface{}) (more bool, err error) {
+		if err := variable.CheckKilled(ctx); err != nil {
+			return false, err
+		}
 		out := make([]interface{}, len(r.Fields))
 
-		// TODO: later order by will use the same mechanism, so we may use another plan to do this
-		m := map[interface{}]interface{}{}
 		// must first eval none aggregate fields, because alias group by will use this.
-		if err := r.evalNoneAggFields(ctx, out, m, in); err != nil {
+		if err := r.evalNoneAggFields(ctx, out, in); err != nil {
 			return false, err
 		}
 
+		k := make([]interface{}, len(r.By))
 		if err := r.evalGroupKey(ctx, k, out, in); err != nil {
 			return false, err
 		}
@@ -121,28 +202,36 @@ func (r *GroupByDefaultPlan) Do(ctx context.Context, f plan.RowIterFunc) (err er
 			return false, err
 		}
 
-		index := 0
+		var row *groupRow
 		if len(v) == 0 {
 			// no group for key, save data for this group
-			index = len(outRows)
-			outRows = append(outRows, &groupRow{Row: out, Args: m})
+			if row, err = newGroupRow(out, in, calls); err != nil {
+				return false, err
+			}
+			row.Key = k
 
-			if err := t.Set(k, []interface{}{index}); err != nil {
+			if err := t.Set(k, []interface{}{int64(len(outRows))}); err != nil {
 				return false, err
 			}
+			outRows = append(outRows, row)
+			size += types.EstimateSize(k) + types.EstimateSize(out) + types.EstimateSize(in) +
+				groupAggSize*int64(len(calls))
 		} else {
 			// we have already saved data in the group by key, use this
-			index = v[0].(int)
-
-			// we will use this context args to evaluate aggregate fields.
-			m = outRows[index].Args
+			row = outRows[v[0].(int64)]
+			if growing {
+				size += types.EstimateSize(in)
+			}
 		}
 
-		// eval aggregate fields
-		if err := r.evalAggFields(ctx, out, m, in); err != nil {
+		// update the accumulators of the group
+		if err := r.updateAggs(ctx, row, calls, out, in); err != nil {
 			return false, err
 		}
 
+		if quota > 0 && size > quota {
+			return true, spill()
+		}
 		return true, nil
 	})
 
@@ -150,16 +239,17 @@ func (r *GroupByDefaultPlan) Do(ctx context.Context, f plan.RowIterFunc) (err er
 		return err
 	}
 
-	if len(outRows) == 0 {
-		// empty table
-		out, err := r.evalEmptyTable(ctx)
-		if err != nil || out == nil {
-			return err
+	if spilled != nil {
+		if len(outRows) > 0 {
+			if err = spill(); err != nil {
+				return err
+			}
 		}
+		return r.doSpilled(ctx, spilled, calls, f)
+	}
 
-		_, err = f(nil, out)
-
-		return err
+	if len(outRows) == 0 {
+		return r.doEmptyTable(ctx, f)
 	}
 
 	// we don't consider implicit GROUP BY sorting now.
@@ -170,7 +260,7 @@ func (r *GroupByDefaultPlan) Do(ctx context.Context, f plan.RowIterFunc) (err er
 	var more bool
 	for _, row := range outRows {
 		// eval aggregate done
-		if err := r.evalAggDone(ctx, row.Row, row.Args); err != nil {
+		if err := r.evalAggDone(ctx, row, calls); err != nil {
 			return err
 		}
 		if more, err = f(nil, row.Row); !more || err != nil {
@@ -181,6 +271,341 @@ func (r *GroupByDefaultPlan) Do(ctx context.Context, f plan.RowIterFunc) (err er
 	return types.EOFAsNil(err)
 }
 
+// groupAggSize is the approximate memory size of an accumulator in bytes.
+const groupAggSize = 64
+
+// spillGroups saves the states of the groups to the temporary kv on disk, the key
+// is the group key followed by seq, so the states of a group saved by different spills
+// are adjacent, and are merged by doSpilled.
+func spillGroups(spilled memkv.Temp, rows []*groupRow, seq int64) error {
+	for _, row := range rows {
+		v, err := row.state()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		k := make([]interface{}, 0, len(row.Key)+1)
+		k = append(append(k, row.Key...), seq)
+		if err = spilled.Set(k, v); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// doSpilled merges the states of every group saved by spillGroups, and outputs
+// the groups in the order of the group keys.
+func (r *GroupByDefaultPlan) doSpilled(ctx context.Context, spilled memkv.Temp, calls []*aggregateCall,
+	f plan.RowIterFunc) (err error) {
+	var (
+		row    *groupRow
+		rowKey []interface{}
+	)
+	defer func() {
+		if row == nil {
+			return
+		}
+		if cerr := row.close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	it, err := spilled.SeekFirst()
+	for err == nil {
+		if err = variable.CheckKilled(ctx); err != nil {
+			return err
+		}
+
+		var k, v []interface{}
+		if k, v, err = it.Next(); err != nil {
+			break
+		}
+		k = k[:len(k)-1]
+
+		if row != nil && types.Collators[true](rowKey, k) != 0 {
+			// all the states of the group are merged.
+			if err = r.evalAggDone(ctx, row, calls); err != nil {
+				return err
+			}
+			var more bool
+			if more, err = f(nil, row.Row); !more || err != nil {
+				return types.EOFAsNil(err)
+			}
+			if err = row.close(); err != nil {
+				return err
+			}
+			row = nil
+		}
+
+		if row == nil {
+			if row, err = newGroupState(v, calls); err != nil {
+				return err
+			}
+			rowKey = k
+		} else if err = mergeGroupState(row, v); err != nil {
+			return err
+		}
+	}
+	if err != io.EOF {
+		return err
+	}
+
+	if row == nil {
+		return nil
+	}
+	if err = r.evalAggDone(ctx, row, calls); err != nil {
+		return err
+	}
+	_, err = f(nil, row.Row)
+	return types.EOFAsNil(err)
+}
+
+// doStreamed aggregates the ordered rows, a group is output once a row of
+// the next group arrives.
+func (r *GroupByDefaultPlan) doStreamed(ctx context.Context, calls []*aggregateCall, f plan.RowIterFunc) (err error) {
+	var (
+		row    *groupRow
+		rowKey []interface{}
+		more   = true
+	)
+	defer func() {
+		if row == nil {
+			return
+		}
+		if cerr := row.close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+	err = r.Src.Do(ctx, func(rid interface{}, in []interface{}) (bool, error) {
+		if err := variable.CheckKilled(ctx); err != nil {
+			return false, err
+		}
+		out := make([]interface{}, len(r.Fields))
+		if err := r.evalNoneAggFields(ctx, out, in); err != nil {
+			return false, err
+		}
+
+		k := make([]interface{}, len(r.By))
+		if err := r.evalGroupKey(ctx, k, out, in); err != nil {
+			return false, err
+		}
+
+		var err error
+		if row != nil && types.Collators[true](rowKey, k) != 0 {
+			// all the rows of the group are aggregated.
+			if err = r.evalAggDone(ctx, row, calls); err != nil {
+				return false, err
+			}
+			if more, err = f(nil, row.Row); !more || err != nil {
+				return false, err
+			}
+			if err = row.close(); err != nil {
+				return false, err
+			}
+			row = nil
+		}
+
+		if row == nil {
+			if row, err = newGroupRow(out, in, calls); err != nil {
+				return false, err
+			}
+			rowKey = k
+		}
+
+		return true, r.updateAggs(ctx, row, calls, out, in)
+	})
+	if err != nil || !more {
+		return types.EOFAsNil(err)
+	}
+
+	if row == nil {
+		return r.doEmptyTable(ctx, f)
+	}
+
+	if err = r.evalAggDone(ctx, row, calls); err != nil {
+		return err
+	}
+	_, err = f(nil, row.Row)
+	return types.EOFAsNil(err)
+}
+
+func (r *GroupByDefaultPlan) doEmptyTable(ctx context.Context, f plan.RowIterFunc) error {
+	// empty table
+	out, err := r.evalEmptyTable(ctx)
+	if err != nil || out == nil {
+		return err
+	}
+
+	_, err = f(nil, out)
+	return err
+}
+
+// aggregateCalls returns all the aggregate function calls in the aggregate fields.
+func (r *GroupByDefaultPlan) aggregateCalls() []*aggregateCall {
+	var calls []*aggregateCall
+	m := map[interface{}]interface{}{expressions.ExprEvalCollationFunc: r.identCollation}
+	for i := range r.Fields {
+		if _, ok := r.AggFields[i]; !ok {
+			continue
+		}
+
+		// we must evaluate aggregate function only, e.g, select col1 + count(*) in (count(*)),
+		// we cannot evaluate it directly here, because col1 + count(*) returns nil before AggDone phase,
+		// so we don't evaluate count(*) in In expression, and will get an invalid data in AggDone phase for it.
+		// mention all aggregate functions
+		for _, agg := range expressions.MentionedAggregateFuncs(r.Fields[i].Expr) {
+			call := agg.(*expressions.Call)
+			calls = append(calls, &aggregateCall{Call: call, Index: i, Collations: expressions.ArgCollations(call, m)})
+		}
+	}
+	return calls
+}
+
+// close releases the resources of the accumulators of the group.
+func (row *groupRow) close() error {
+	var err error
+	for _, agg := range row.Aggs {
+		if cerr := expressions.CloseAggregateFunc(agg); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// state returns the state of the group, which is the output row, the first source row
+// and the partial states of the accumulators, all flattened into a value list.
+// The lists in the partial states, like the distinct args, are encoded as bytes.
+func (row *groupRow) state() ([]interface{}, error) {
+	v := make([]interface{}, 0, 2+len(row.Row)+len(row.In)+len(row.Aggs))
+	v = append(append(v, int64(len(row.Row))), row.Row...)
+	v = append(append(v, int64(len(row.In))), row.In...)
+	for _, agg := range row.Aggs {
+		partial, err := agg.Partial()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		v = append(v, int64(len(partial)))
+		for _, p := range partial {
+			list, ok := p.([]interface{})
+			if !ok {
+				v = append(v, false, p)
+				continue
+			}
+			b, err := codec.EncodeValue(nil, list...)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			v = append(v, true, b)
+		}
+	}
+	return v, nil
+}
+
+// newGroupState creates a group row with the state v returned by groupRow.state.
+func newGroupState(v []interface{}, calls []*aggregateCall) (*groupRow, error) {
+	out, in, partials, err := decodeGroupState(v, len(calls))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	row, err := newGroupRow(out, in, calls)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = row.merge(partials); err != nil {
+		row.close()
+		return nil, errors.Trace(err)
+	}
+	return row, nil
+}
+
+// mergeGroupState merges the accumulators of the state v returned by groupRow.state into row.
+func mergeGroupState(row *groupRow, v []interface{}) error {
+	_, _, partials, err := decodeGroupState(v, len(row.Aggs))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(row.merge(partials))
+}
+
+func (row *groupRow) merge(partials [][]interface{}) error {
+	for i, agg := range row.Aggs {
+		if err := agg.Merge(partials[i]); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func decodeGroupState(v []interface{}, aggs int) (out, in []interface{}, partials [][]interface{}, err error) {
+	next := func() (interface{}, error) {
+		if len(v) == 0 {
+			return nil, errors.Errorf("invalid group state")
+		}
+		val := v[0]
+		v = v[1:]
+		return val, nil
+	}
+	list := func() ([]interface{}, error) {
+		val, err := next()
+		if err != nil {
+			return nil, err
+		}
+		n, ok := val.(int64)
+		if !ok || n < 0 || n > int64(len(v)) {
+			return nil, errors.Errorf("invalid group state")
+		}
+		vals := v[:n]
+		v = v[n:]
+		return vals, nil
+	}
+
+	if out, err = list(); err != nil {
+		return nil, nil, nil, err
+	}
+	if in, err = list(); err != nil {
+		return nil, nil, nil, err
+	}
+	partials = make([][]interface{}, aggs)
+	for i := range partials {
+		val, err := next()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		n, ok := val.(int64)
+		if !ok || n < 0 || 2*n > int64(len(v)) {
+			return nil, nil, nil, errors.Errorf("invalid group state")
+		}
+		partials[i] = make([]interface{}, n)
+		for j := range partials[i] {
+			isList, p := v[0].(bool), v[1]
+			v = v[2:]
+			if b, ok := p.([]byte); ok && isList {
+				if p, err = codec.DecodeValue(b); err != nil {
+					return nil, nil, nil, err
+				}
+			}
+			partials[i][j] = p
+		}
+	}
+	return out, in, partials, nil
+}
+
+func newGroupRow(out []interface{}, in []interface{}, calls []*aggregateCall) (*groupRow, error) {
+	row := &groupRow{
+		Row:  out,
+		In:   in,
+		Aggs: make([]expressions.AggregateFunc, len(calls)),
+	}
+
+	var err error
+	for i, call := range calls {
+		if row.Aggs[i], err = expressions.NewAggregateFunc(call.Call, call.Collations); err != nil {
+			row.close()
+			return nil, err
+		}
+	}
+	return row, nil
+}
+
 func (r *GroupByDefaultPlan) evalGroupKey(ctx context.Context, k []interface{}, outRow []interface{}, in []interface{}) error {
 	// group by items can not contain aggregate field, so we can eval them safely.
 	m := map[interface{}]interface{}{}
@@ -199,13 +624,15 @@ func (r *GroupByDefaultPlan) evalGroupKey(ctx context.Context, k []interface{},
 		return outRow[position-1], nil
 	}
 
-	// Eval group by result
+	m[expressions.ExprEvalCollationFunc] = r.identCollation
+
+	// Eval group by result, strings are grouped by the sort keys of their collations.
 	for i, v := range r.By {
 		val, err := v.Eval(ctx, m)
 		if err != nil {
 			return err
 		}
-		k[i] = val
+		k[i] = expressions.SortKey(v, m, val)
 	}
 	return nil
 }
@@ -219,11 +646,26 @@ func (r *GroupByDefaultPlan) getFieldValueByName(name string, out []interface{})
 	return nil, errors.Errorf("unknown field %s", name)
 }
 
-func (r *GroupByDefaultPlan) evalNoneAggFields(ctx context.Context, out []interface{},
-	m map[interface{}]interface{}, in []interface{}) error {
+// identCollation returns the collation of the source field or the select field name refers to.
+func (r *GroupByDefaultPlan) identCollation(name string) string {
+	if indices := field.GetResultFieldIndex(name, r.Src.GetFields(), field.DefaultFieldFlag); len(indices) > 0 {
+		return r.Src.GetFields()[indices[0]].Collate
+	}
+
+	for i, f := range r.Fields[0:r.HiddenFieldOffset] {
+		if name == f.Name {
+			return r.ResultFields[i].Collate
+		}
+	}
+	return ""
+}
+
+func (r *GroupByDefaultPlan) evalNoneAggFields(ctx context.Context, out []interface{}, in []interface{}) error {
+	m := map[interface{}]interface{}{}
 	m[expressions.ExprEvalIdentFunc] = func(name string) (interface{}, error) {
 		return getIdentValue(name, r.Src.GetFields(), in, field.DefaultFieldFlag)
 	}
+	m[expressions.ExprEvalCollationFunc] = r.identCollation
 
 	var err error
 	// Eval none aggregate field results in ctx
@@ -238,11 +680,13 @@ func (r *GroupByDefaultPlan) evalNoneAggFields(ctx context.Context, out []interf
 	return nil
 }
 
-func (r *GroupByDefaultPlan) evalAggFields(ctx context.Context, out []interface{},
-	m map[interface{}]interface{}, in []interface{}) error {
-	// Eval aggregate field results in ctx
-	for i := range r.AggFields {
-		if i < r.HiddenFieldOffset {
+// updateAggs evaluates the args of the aggregate calls with the source row in,
+// and updates the accumulators of the group with them.
+func (r *GroupByDefaultPlan) updateAggs(ctx context.Context, row *groupRow, calls []*aggregateCall,
+	out []interface{}, in []interface{}) error {
+	m := map[interface{}]interface{}{}
+	for i, call := range calls {
+		if call.Index < r.HiddenFieldOffset {
 			m[expressions.ExprEvalIdentFunc] = func(name string) (interface{}, error) {
 				return getIdentValue(name, r.Src.GetFields(), in, field.DefaultFieldFlag)
 			}
@@ -264,30 +708,46 @@ func (r *GroupByDefaultPlan) evalAggFields(ctx context.Context, out []interface{
 			}
 		}
 
-		// we must evaluate aggregate function only, e.g, select col1 + count(*) in (count(*)),
-		// we cannot evaluate it directly here, because col1 + count(*) returns nil before AggDone phase,
-		// so we don't evaluate count(*) in In expression, and will get an invalid data in AggDone phase for it.
-		// mention all aggregate functions
-		// TODO: optimize, we can get these aggregate functions only once and reuse
-		aggs := expressions.MentionedAggregateFuncs(r.Fields[i].Expr)
-		for _, agg := range aggs {
-			if _, err := agg.Eval(ctx, m); err != nil {
+		args := make([]interface{}, len(call.Args))
+		for j, arg := range call.Args {
+			v, err := arg.Eval(ctx, m)
+			if err != nil {
 				return err
 			}
+			args[j] = v
+		}
+
+		if err := row.Aggs[i].Update(args); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-func (r *GroupByDefaultPlan) evalAggDone(ctx context.Context, out []interface{},
-	m map[interface{}]interface{}) error {
+// evalAggDone evaluates the aggregate fields with the results of the accumulators.
+func (r *GroupByDefaultPlan) evalAggDone(ctx context.Context, row *groupRow, calls []*aggregateCall) error {
+	m := map[interface{}]interface{}{}
 	m[expressions.ExprAggDone] = true
+	m[expressions.ExprEvalIdentFunc] = func(name string) (interface{}, error) {
+		v, err := getIdentValue(name, r.Src.GetFields(), row.In, field.DefaultFieldFlag)
+		if err == nil {
+			return v, nil
+		}
+
+		return r.getFieldValueByName(name, row.Row)
+	}
+	m[expressions.ExprEvalCollationFunc] = r.identCollation
+
+	// the aggregate builtin functions return the result of the accumulator saved with the call.
+	for i, call := range calls {
+		m[call.Call] = row.Aggs[i]
+	}
 
 	var err error
 	// Eval aggregate field results done in ctx
 	for i := range r.AggFields {
-		if out[i], err = r.Fields[i].Expr.Eval(ctx, m); err != nil {
+		if row.Row[i], err = r.Fields[i].Expr.Eval(ctx, m); err != nil {
 			return err
 		}
 	}
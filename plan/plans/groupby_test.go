@@ -19,6 +19,9 @@ import (
 	"github.com/Dong-Chan/alloydb/expression/expressions"
 	"github.com/Dong-Chan/alloydb/field"
 	"github.com/Dong-Chan/alloydb/model"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/util/mock"
 )
 
 type testGroupBySuite struct{}
@@ -93,3 +96,146 @@ func (t *testGroupBySuite) TestGroupBy(c *C) {
 		return true, nil
 	})
 }
+
+func (t *testGroupBySuite) TestGroupByStreamed(c *C) {
+	tblPlan := &testTablePlan{groupByTestData, []string{"id", "name"}}
+	sl := &SelectList{
+		Fields: []*field.Field{
+			&field.Field{
+				Expr: &expressions.Ident{
+					CIStr: model.NewCIStr("id"),
+				},
+			},
+			&field.Field{
+				Expr: &expressions.Call{
+					F: "count",
+					Args: []expression.Expression{
+						&expressions.Ident{
+							CIStr: model.NewCIStr("name"),
+						},
+					},
+				},
+			},
+			&field.Field{
+				Expr: &expressions.Call{
+					F: "group_concat",
+					Args: []expression.Expression{
+						&expressions.Ident{
+							CIStr: model.NewCIStr("name"),
+						},
+					},
+				},
+			},
+		},
+		AggFields: map[int]struct{}{1: struct{}{}, 2: struct{}{}},
+	}
+
+	groupbyPlan := &GroupByDefaultPlan{
+		SelectList: sl,
+		Src:        tblPlan,
+		By: []expression.Expression{
+			&expressions.Ident{
+				CIStr: model.NewCIStr("id"),
+			},
+		},
+		Streamed: true,
+	}
+
+	var ret [][]interface{}
+	err := groupbyPlan.Do(nil, func(id interface{}, data []interface{}) (bool, error) {
+		ret = append(ret, data)
+		return true, nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(ret, DeepEquals, [][]interface{}{
+		{10, int64(3), "10,20,30"},
+		{40, int64(1), "40"},
+		{60, int64(1), "60"},
+	})
+
+	// stop after the first group.
+	ret = nil
+	err = groupbyPlan.Do(nil, func(id interface{}, data []interface{}) (bool, error) {
+		ret = append(ret, data)
+		return false, nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(ret, HasLen, 1)
+
+	// test empty
+	tblPlan.rows = []*testRowData{}
+	groupbyPlan.By = nil
+	ret = nil
+	err = groupbyPlan.Do(nil, func(id interface{}, data []interface{}) (bool, error) {
+		ret = append(ret, data)
+		return true, nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(ret, DeepEquals, [][]interface{}{{nil, int64(0), nil}})
+}
+
+func (t *testGroupBySuite) TestGroupBySpill(c *C) {
+	var rows []*testRowData
+	for i := 0; i < 100; i++ {
+		rows = append(rows, &testRowData{int64(i), []interface{}{int64(i % 30), int64(i % 3)}})
+	}
+	tblPlan := &testTablePlan{rows, []string{"id", "name"}}
+	name := &expressions.Ident{CIStr: model.NewCIStr("name")}
+	sl := &SelectList{
+		Fields: []*field.Field{
+			&field.Field{
+				Expr: &expressions.Ident{
+					CIStr: model.NewCIStr("id"),
+				},
+			},
+			&field.Field{
+				Expr: &expressions.Call{
+					F:        "count",
+					Args:     []expression.Expression{name},
+					Distinct: true,
+				},
+			},
+			&field.Field{
+				Expr: &expressions.Call{
+					F:    "sum",
+					Args: []expression.Expression{name},
+				},
+			},
+		},
+		AggFields: map[int]struct{}{1: struct{}{}, 2: struct{}{}},
+	}
+
+	groupbyPlan := &GroupByDefaultPlan{
+		SelectList: sl,
+		Src:        tblPlan,
+		By: []expression.Expression{
+			&expressions.Ident{
+				CIStr: model.NewCIStr("id"),
+			},
+		},
+	}
+
+	ctx := mock.NewContext()
+	variable.BindSessionVars(ctx)
+	variable.GetSessionVars(ctx).Systems[variable.MemQuotaQuery] = "1024"
+
+	// The groups are spilled many times, and their states are merged in the order of the keys.
+	var ret [][]interface{}
+	err := groupbyPlan.Do(ctx, func(id interface{}, data []interface{}) (bool, error) {
+		ret = append(ret, data)
+		return true, nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(ret, HasLen, 30)
+	for i, data := range ret {
+		names := map[int64]bool{}
+		var sum int64
+		for j := i; j < 100; j += 30 {
+			names[int64(j%3)] = true
+			sum += int64(j % 3)
+		}
+		c.Assert(data[0], Equals, int64(i))
+		c.Assert(data[1], Equals, int64(len(names)))
+		c.Assert(data[2], DeepEquals, mysql.NewDecimalFromInt(sum, 0))
+	}
+}
@@ -53,6 +53,9 @@ func (r *HavingPlan) GetFields() []*field.ResultField {
 // It scans rows over SrcPlan and check if it meets all conditions in Expr.
 func (r *HavingPlan) Do(ctx context.Context, f plan.RowIterFunc) (err error) {
 	m := map[interface{}]interface{}{}
+	m[expressions.ExprEvalCollationFunc] = func(name string) string {
+		return getIdentCollation(name, r.Src.GetFields(), field.CheckFieldFlag)
+	}
 
 	return r.Src.Do(ctx, func(rid interface{}, in []interface{}) (more bool, err error) {
 		m[expressions.ExprEvalIdentFunc] = func(name string) (interface{}, error) {
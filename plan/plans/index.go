@@ -14,6 +14,9 @@
 package plans
 
 import (
+	"io"
+
+	"github.com/juju/errors"
 	"github.com/Dong-Chan/alloydb/column"
 	"github.com/Dong-Chan/alloydb/context"
 	"github.com/Dong-Chan/alloydb/expression"
@@ -22,6 +25,7 @@ import (
 	"github.com/Dong-Chan/alloydb/kv"
 	"github.com/Dong-Chan/alloydb/parser/opcode"
 	"github.com/Dong-Chan/alloydb/plan"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
 	"github.com/Dong-Chan/alloydb/table"
 	"github.com/Dong-Chan/alloydb/util/format"
 	"github.com/Dong-Chan/alloydb/util/types"
@@ -120,8 +124,9 @@ type indexPlan struct {
 	src     table.Table
 	colName string
 	idxName string
-	idx     kv.Index
+	idx     kv.Index     // the index without collation conversion, it is searched with the sort keys in spans.
 	spans   []*indexSpan // multiple spans are ordered by their values and without overlapping.
+	desc    bool         // iterate spans from the high value to the low value.
 }
 
 // comparison function that takes minNotNullVal and maxVal into account.
@@ -154,7 +159,9 @@ func indexCompare(a interface{}, b interface{}) int {
 	return types.Compare(a, b)
 }
 
-func (r *indexPlan) doSpan(ctx context.Context, txn kv.Transaction, span *indexSpan, f plan.RowIterFunc) error {
+// iterSpan iterates the index entries in a span from the lower bound to the upper bound
+// and calls f with the indexed values and the row handle of each entry.
+func (r *indexPlan) iterSpan(txn kv.Transaction, span *indexSpan, f func(k []interface{}, h int64) (bool, error)) error {
 	seekVal := span.lowVal
 	if span.lowVal == minNotNullVal {
 		seekVal = []byte{}
@@ -181,27 +188,133 @@ func (r *indexPlan) doSpan(ctx context.Context, txn kv.Transaction, span *indexS
 		if cmp < 0 || (cmp == 0 && span.highExclude) {
 			return nil
 		}
-		data, err := r.src.Row(ctx, h)
-		if err != nil {
+
+		if more, err := f(k, h); err != nil || !more {
 			return err
 		}
+	}
+}
 
-		if more, err := f(h, data); err != nil || !more {
-			return err
+// doSpan scans a span from the lower bound to the upper bound,
+// more is false if f stops the iteration.
+func (r *indexPlan) doSpan(ctx context.Context, txn kv.Transaction, span *indexSpan, f plan.RowIterFunc) (more bool, err error) {
+	more = true
+	err = r.iterSpan(txn, span, func(k []interface{}, h int64) (bool, error) {
+		more, err = r.doRow(ctx, h, f)
+		return more, err
+	})
+	return more, err
+}
+
+// doRow fetches the row of handle h and calls f with it.
+func (r *indexPlan) doRow(ctx context.Context, h int64, f plan.RowIterFunc) (bool, error) {
+	if err := variable.CheckKilled(ctx); err != nil {
+		return false, err
+	}
+	variable.AddRowsExamined(ctx)
+	data, err := r.src.Row(ctx, h)
+	if err != nil {
+		return false, err
+	}
+	return f(h, data)
+}
+
+// descChunkSize is the max number of the handles doSpanDesc keeps in memory.
+var descChunkSize = 1024
+
+// indexEntry is the position of an entry in the index.
+type indexEntry struct {
+	vals []interface{}
+	h    int64
+}
+
+// doSpanDesc scans a span from the upper bound to the lower bound.
+// The kv layer can only seek forward, so the span is scanned forward first, the
+// first entry of every chunk of descChunkSize entries is saved, and the handles of
+// the last chunk are kept. The chunks are output from the last to the first, the
+// handles of a chunk are collected by seeking to its first entry again, so only
+// a chunk of handles is kept in memory at a time.
+func (r *indexPlan) doSpanDesc(ctx context.Context, txn kv.Transaction, span *indexSpan, f plan.RowIterFunc) (bool, error) {
+	var (
+		chunks  []indexEntry
+		handles []int64
+	)
+	err := r.iterSpan(txn, span, func(k []interface{}, h int64) (bool, error) {
+		if err := variable.CheckKilled(ctx); err != nil {
+			return false, err
+		}
+		if len(handles) == descChunkSize {
+			handles = handles[:0]
+		}
+		if len(handles) == 0 {
+			chunks = append(chunks, indexEntry{vals: k, h: h})
 		}
+		handles = append(handles, h)
+		return true, nil
+	})
+	if err != nil {
+		return false, err
 	}
+
+	for i := len(chunks) - 1; i >= 0; i-- {
+		if i < len(chunks)-1 {
+			if handles, err = r.chunkHandles(txn, chunks[i]); err != nil {
+				return false, err
+			}
+		}
+		for j := len(handles) - 1; j >= 0; j-- {
+			if more, err := r.doRow(ctx, handles[j], f); err != nil || !more {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+// chunkHandles returns the handles of the descChunkSize entries from the entry first.
+func (r *indexPlan) chunkHandles(txn kv.Transaction, first indexEntry) ([]int64, error) {
+	it, _, err := r.idx.Seek(txn, first.vals)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	handles := make([]int64, 0, descChunkSize)
+	for len(handles) < descChunkSize {
+		_, h, err := it.Next()
+		if err == io.EOF {
+			return nil, errors.Errorf("index entry %v of handle %d not found", first.vals, first.h)
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// The entries of the same values before the first entry are skipped.
+		if len(handles) > 0 || h == first.h {
+			handles = append(handles, h)
+		}
+	}
+	return handles, nil
 }
 
 // Do implements plan.Plan Do interface.
-// It scans a span from the lower bound to upper bound.
+// It scans a span from the lower bound to upper bound, or from the upper bound
+// to the lower bound if the plan is used for a descending order.
 func (r *indexPlan) Do(ctx context.Context, f plan.RowIterFunc) error {
 	txn, err := ctx.GetTxn(false)
 	if err != nil {
 		return err
 	}
+	if r.desc {
+		for i := len(r.spans) - 1; i >= 0; i-- {
+			more, err := r.doSpanDesc(ctx, txn, r.spans[i], f)
+			if err != nil || !more {
+				return err
+			}
+		}
+		return nil
+	}
 	for _, span := range r.spans {
-		err := r.doSpan(ctx, txn, span, f)
-		if err != nil {
+		more, err := r.doSpan(ctx, txn, span, f)
+		if err != nil || !more {
 			return err
 		}
 	}
@@ -222,6 +335,9 @@ func (r *indexPlan) Explain(w format.Formatter) {
 		}
 		w.Format("%s%v,%v%s ", open, span.lowVal, span.highVal, close)
 	}
+	if r.desc {
+		w.Format("in descending order")
+	}
 	w.Format("\n└Output field names %v\n", field.RFQNames(r.GetFields()))
 }
 
@@ -245,14 +361,14 @@ func (r *indexPlan) Filter(ctx context.Context, expr expression.Expression) (pla
 		}
 
 		col := column.FindCol(r.src.Cols(), cname)
-		if col == nil {
+		if col == nil || !canRangeIndex(col) {
 			break
 		}
 
 		if val, err = col.CastValue(ctx, val); err != nil {
 			return nil, false, err
 		}
-		r.spans = filterSpans(r.spans, toSpans(x.Op, val))
+		r.spans = filterSpans(r.spans, toSpans(x.Op, col.SortKey(val)))
 		return r, true, nil
 	case *expressions.Ident:
 		if r.colName != x.L {
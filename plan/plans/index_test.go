@@ -221,3 +221,53 @@ func (p *testIndexSuit) TestIndexPlan(c *C) {
 func (p *testIndexSuit) TearDownSuite(c *C) {
 	p.txn.Commit()
 }
+
+func (p *testIndexSuit) TestIndexOrder(c *C) {
+	fields := []*field.ResultField{
+		field.ColToResultField(p.cols[0], "t"),
+		field.ColToResultField(p.cols[1], "t"),
+	}
+	pln := &plans.TableDefaultPlan{
+		T:      p.tbl,
+		Fields: fields,
+	}
+
+	// no index on column name.
+	np, ok := plans.UseIndexOrder(pln, "name", true)
+	c.Assert(ok, IsFalse)
+	c.Assert(np, Equals, pln)
+
+	np, ok = plans.UseIndexOrder(pln, "t.id", false)
+	c.Assert(ok, IsTrue)
+
+	var ids []int64
+	err := np.Do(p, func(id interface{}, data []interface{}) (bool, error) {
+		ids = append(ids, data[0].(int64))
+		return len(ids) < 3, nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(ids, DeepEquals, []int64{90, 80, 70})
+
+	// filtered index plan keeps the order.
+	expr := &expressions.BinaryOperation{
+		Op: opcode.LT,
+		L: &expressions.Ident{
+			CIStr: model.NewCIStr("id"),
+		},
+		R: expressions.Value{
+			Val: 30,
+		},
+	}
+	np, _, err = pln.Filter(p, expr)
+	c.Assert(err, IsNil)
+	np, ok = plans.UseIndexOrder(&plans.SelectLockPlan{Src: np}, "id", true)
+	c.Assert(ok, IsTrue)
+
+	ids = ids[:0]
+	err = np.Do(p, func(id interface{}, data []interface{}) (bool, error) {
+		ids = append(ids, data[0].(int64))
+		return true, nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(ids, DeepEquals, []int64{0, 10, 20})
+}
@@ -17,17 +17,27 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/Dong-Chan/alloydb/column"
 	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/domain"
 	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/expression/expressions"
 	"github.com/Dong-Chan/alloydb/field"
 	"github.com/Dong-Chan/alloydb/infoschema"
+	"github.com/Dong-Chan/alloydb/kv"
 	"github.com/Dong-Chan/alloydb/model"
 	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/parser/opcode"
 	"github.com/Dong-Chan/alloydb/plan"
+	"github.com/Dong-Chan/alloydb/privilege/privileges"
 	"github.com/Dong-Chan/alloydb/sessionctx"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/table"
+	"github.com/Dong-Chan/alloydb/util"
+	"github.com/Dong-Chan/alloydb/util/auth"
 	"github.com/Dong-Chan/alloydb/util/charset"
 	"github.com/Dong-Chan/alloydb/util/format"
 	"github.com/Dong-Chan/alloydb/util/types"
@@ -39,6 +49,13 @@ var _ = (*InfoSchemaPlan)(nil)
 // MySQL.
 type InfoSchemaPlan struct {
 	TableName string
+
+	// filters are the conditions pushed down by Filter, the rows are checked by them in Do.
+	filters []expression.Expression
+	// schemaName and tableName are the names required by the filters in lower case,
+	// only the schemas and tables with them are scanned. Empty means any name.
+	schemaName string
+	tableName  string
 }
 
 var (
@@ -48,6 +65,24 @@ var (
 	statisticsFields     = buildResultFieldsForStatistics()
 	characterSetsFields  = buildResultFieldsForCharacterSets()
 	characterSetsRecords = buildCharacterSetsRecords()
+	userPrivilegesFields   = buildResultFieldsForPrivileges(tableUserPrivileges)
+	schemaPrivilegesFields = buildResultFieldsForPrivileges(tableSchemaPrivileges)
+	tablePrivilegesFields  = buildResultFieldsForPrivileges(tableTablePrivileges)
+	columnPrivilegesFields = buildResultFieldsForPrivileges(tableColumnPrivileges)
+)
+
+var (
+	collationsFields                         = buildResultFieldsForCollations()
+	collationCharacterSetApplicabilityFields = buildResultFieldsForCollationCharacterSetApplicability()
+	keyColumnUsageFields                     = buildResultFieldsForKeyColumnUsage()
+	tableConstraintsFields                   = buildResultFieldsForTableConstraints()
+	referentialConstraintsFields             = buildResultFieldsForReferentialConstraints()
+	enginesFields                            = buildResultFieldsForEngines()
+	globalVariablesFields                    = buildResultFieldsForVariables(tableGlobalVariables)
+	sessionVariablesFields                   = buildResultFieldsForVariables(tableSessionVariables)
+	processListFields                        = buildResultFieldsForProcessList()
+	filesFields                              = buildResultFieldsForFiles()
+	statementsSummaryFields                  = buildResultFieldsForStatementsSummary()
 )
 
 const (
@@ -57,6 +92,23 @@ const (
 	tableStatistics    = "STATISTICS"
 	tableCharacterSets = "CHARACTER_SETS"
 	catalogVal         = "def"
+
+	tableUserPrivileges   = "USER_PRIVILEGES"
+	tableSchemaPrivileges = "SCHEMA_PRIVILEGES"
+	tableTablePrivileges  = "TABLE_PRIVILEGES"
+	tableColumnPrivileges = "COLUMN_PRIVILEGES"
+
+	tableCollations                         = "COLLATIONS"
+	tableCollationCharacterSetApplicability = "COLLATION_CHARACTER_SET_APPLICABILITY"
+	tableKeyColumnUsage                     = "KEY_COLUMN_USAGE"
+	tableTableConstraints                   = "TABLE_CONSTRAINTS"
+	tableReferentialConstraints             = "REFERENTIAL_CONSTRAINTS"
+	tableEngines                            = "ENGINES"
+	tableGlobalVariables                    = "GLOBAL_VARIABLES"
+	tableSessionVariables                   = "SESSION_VARIABLES"
+	tableProcessList                        = "PROCESSLIST"
+	tableFiles                              = "FILES"
+	tableStatementsSummary                  = "EVENTS_STATEMENTS_SUMMARY_BY_DIGEST"
 )
 
 // NewInfoSchemaPlan returns new InfoSchemaPlan instance, and checks if the
@@ -68,6 +120,11 @@ func NewInfoSchemaPlan(tableName string) (isp *InfoSchemaPlan, err error) {
 	case tableColumns:
 	case tableStatistics:
 	case tableCharacterSets:
+	case tableUserPrivileges, tableSchemaPrivileges, tableTablePrivileges, tableColumnPrivileges:
+	case tableCollations, tableCollationCharacterSetApplicability:
+	case tableKeyColumnUsage, tableTableConstraints, tableReferentialConstraints:
+	case tableEngines, tableGlobalVariables, tableSessionVariables, tableProcessList, tableFiles:
+	case tableStatementsSummary:
 	default:
 		return nil, errors.Errorf("table INFORMATION_SCHEMA.%s does not exist", tableName)
 	}
@@ -133,31 +190,77 @@ func buildResultFieldsForTables() (rfs []*field.ResultField) {
 	return
 }
 
-func (isp *InfoSchemaPlan) doTables(schemas []*model.DBInfo, iterFunc plan.RowIterFunc) error {
+// tableStats scans the keys of tbl in txn, it returns the number of rows, and the bytes of the
+// records and the indices.
+func tableStats(txn kv.Transaction, tbl table.Table) (rows, dataLength, indexLength uint64, err error) {
+	err = util.ScanMetaWithPrefix(txn, tbl.KeyPrefix(), func(key []byte, value []byte) bool {
+		dataLength += uint64(len(key) + len(value))
+		// Every row has a key without column ID besides the keys of the columns.
+		if vals, err1 := kv.DecodeValue(key); err1 == nil && len(vals) == 2 {
+			rows++
+		}
+		return true
+	})
+	if err != nil {
+		return 0, 0, 0, errors.Trace(err)
+	}
+	err = util.ScanMetaWithPrefix(txn, tbl.IndexPrefix(), func(key []byte, value []byte) bool {
+		indexLength += uint64(len(key) + len(value))
+		return true
+	})
+	return rows, dataLength, indexLength, errors.Trace(err)
+}
+
+// doTables returns a row for every table the session has privileges on, TABLE_ROWS, AVG_ROW_LENGTH,
+// DATA_LENGTH and INDEX_LENGTH are counted from the keys of the table in the current transaction.
+func (isp *InfoSchemaPlan) doTables(ctx context.Context, is infoschema.InfoSchema, schemas []*model.DBInfo, iterFunc plan.RowIterFunc) error {
+	txn, err := ctx.GetTxn(false)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	filter, err := newPrivFilter(ctx, is)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	for _, schema := range schemas {
 		for _, table := range schema.Tables {
+			if !filter.tableVisible(schema.Name.L, table.Name.L) {
+				continue
+			}
+			tbl, err := is.TableByName(schema.Name, table.Name)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			rows, dataLength, indexLength, err := tableStats(txn, tbl)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			var avgRowLength uint64
+			if rows > 0 {
+				avgRowLength = dataLength / rows
+			}
 			record := []interface{}{
-				catalogVal,          // TABLE_CATALOG
-				schema.Name.O,       // TABLE_SCHEMA
-				table.Name.O,        // TABLE_NAME
-				"BASE_TABLE",        // TABLE_TYPE
-				"InnoDB",            // ENGINE
-				uint64(10),          // VERSION
-				"Compact",           // ROW_FORMAT
-				uint64(0),           // TABLE_ROWS
-				uint64(0),           // AVG_ROW_LENGTH
-				uint64(16384),       // DATA_LENGTH
-				uint64(0),           // MAX_DATA_LENGTH
-				uint64(0),           // INDEX_LENGTH
-				uint64(0),           // DATA_FREE
-				nil,                 // AUTO_INCREMENT
-				nil,                 // CREATE_TIME
-				nil,                 // UPDATE_TIME
-				nil,                 // CHECK_TIME
-				"latin1_swedish_ci", // TABLE_COLLATION
-				nil,                 // CHECKSUM
-				"",                  // CREATE_OPTIONS
-				"",                  // TABLE_COMMENT
+				catalogVal,            // TABLE_CATALOG
+				schema.Name.O,         // TABLE_SCHEMA
+				table.Name.O,          // TABLE_NAME
+				"BASE_TABLE",          // TABLE_TYPE
+				"InnoDB",              // ENGINE
+				uint64(10),            // VERSION
+				"Compact",             // ROW_FORMAT
+				rows,                  // TABLE_ROWS
+				avgRowLength,          // AVG_ROW_LENGTH
+				dataLength,            // DATA_LENGTH
+				uint64(0),             // MAX_DATA_LENGTH
+				indexLength,           // INDEX_LENGTH
+				uint64(0),             // DATA_FREE
+				nil,                   // AUTO_INCREMENT
+				nil,                   // CREATE_TIME
+				nil,                   // UPDATE_TIME
+				nil,                   // CHECK_TIME
+				tableCollation(table), // TABLE_COLLATION
+				nil,                   // CHECKSUM
+				"",                    // CREATE_OPTIONS
+				"",                    // TABLE_COMMENT
 			}
 			if more, err := iterFunc(0, record); !more || err != nil {
 				return err
@@ -195,10 +298,18 @@ func buildResultFieldsForColumns() (rfs []*field.ResultField) {
 	return
 }
 
-func (isp *InfoSchemaPlan) doColumns(schemas []*model.DBInfo, iterFunc plan.RowIterFunc) error {
+// doColumns returns a row for every column the session has privileges on.
+func (isp *InfoSchemaPlan) doColumns(ctx context.Context, is infoschema.InfoSchema, schemas []*model.DBInfo, iterFunc plan.RowIterFunc) error {
+	filter, err := newPrivFilter(ctx, is)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	for _, schema := range schemas {
 		for _, table := range schema.Tables {
 			for i, col := range table.Columns {
+				if !filter.columnVisible(schema.Name.L, table.Name.L, col.Name.L) {
+					continue
+				}
 				colLen := col.Flen
 				if colLen == types.UnspecifiedLength {
 					colLen = mysql.GetDefaultFieldLength(col.Tp)
@@ -214,6 +325,11 @@ func (isp *InfoSchemaPlan) doColumns(schemas []*model.DBInfo, iterFunc plan.RowI
 				if columnDesc.DefaultValue != nil {
 					columnDefault = fmt.Sprintf("%v", columnDesc.DefaultValue)
 				}
+				// The types without a charset and the binary strings have no charset and collation.
+				var charsetName, collationName interface{}
+				if len(col.Charset) > 0 && col.Charset != charset.CharsetBin {
+					charsetName, collationName = col.Charset, col.Collate
+				}
 				record := []interface{}{
 					catalogVal,                                                 // TABLE_CATALOG
 					schema.Name.O,                                              // TABLE_SCHEMA
@@ -228,8 +344,8 @@ func (isp *InfoSchemaPlan) doColumns(schemas []*model.DBInfo, iterFunc plan.RowI
 					decimal,                           // NUMERIC_PRECISION
 					0,                                 // NUMERIC_SCALE
 					0,                                 // DATETIME_PRECISION
-					col.Charset,                       // CHARACTER_SET_NAME
-					col.Collate,                       // COLLATION_NAME
+					charsetName,                       // CHARACTER_SET_NAME
+					collationName,                     // COLLATION_NAME
 					columnType,                        // COLUMN_TYPE
 					columnDesc.Key,                    // COLUMN_KEY
 					columnDesc.Extra,                  // EXTRA
@@ -340,22 +456,625 @@ func (isp *InfoSchemaPlan) doCharacterSets(iterFunc plan.RowIterFunc) error {
 	return nil
 }
 
+func buildResultFieldsForPrivileges(tbName string) (rfs []*field.ResultField) {
+	rfs = append(rfs, buildResultField(tbName, "GRANTEE", mysql.TypeVarchar, 81))
+	rfs = append(rfs, buildResultField(tbName, "TABLE_CATALOG", mysql.TypeVarchar, 512))
+	if tbName != tableUserPrivileges {
+		rfs = append(rfs, buildResultField(tbName, "TABLE_SCHEMA", mysql.TypeVarchar, 64))
+	}
+	if tbName == tableTablePrivileges || tbName == tableColumnPrivileges {
+		rfs = append(rfs, buildResultField(tbName, "TABLE_NAME", mysql.TypeVarchar, 64))
+	}
+	if tbName == tableColumnPrivileges {
+		rfs = append(rfs, buildResultField(tbName, "COLUMN_NAME", mysql.TypeVarchar, 64))
+	}
+	rfs = append(rfs, buildResultField(tbName, "PRIVILEGE_TYPE", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "IS_GRANTABLE", mysql.TypeVarchar, 3))
+	return rfs
+}
+
+// restrictedAccount returns the account of the session bound to ctx in the name and host pattern form,
+// if the session can only see the information of its own account, that is it has no SELECT privilege
+// on the system database. The name is empty if the session can see all the accounts.
+func restrictedAccount(ctx context.Context, privs *privileges.MySQLPrivilege) (name, pattern string) {
+	account := variable.GetSessionVars(ctx).User
+	if len(account) == 0 {
+		return "", ""
+	}
+	i := strings.LastIndex(account, "@")
+	name, pattern = account[:i], account[i+1:]
+	if privs.RequestVerification(name, pattern, mysql.SystemDB, "", mysql.SelectPriv) {
+		return "", ""
+	}
+	return name, pattern
+}
+
+// privFilter checks whether the tables and columns are shown to a session, they are shown if the session
+// has any privilege on them. The sessions without a user see all, and information_schema is shown to all.
+type privFilter struct {
+	privs *privileges.MySQLPrivilege
+	user  string
+	host  string
+}
+
+// newPrivFilter returns the privFilter of the session bound to ctx.
+func newPrivFilter(ctx context.Context, is infoschema.InfoSchema) (*privFilter, error) {
+	account := variable.GetSessionVars(ctx).User
+	if len(account) == 0 {
+		return &privFilter{}, nil
+	}
+	privs, err := sessionctx.GetDomain(ctx).PrivilegeHandle().Get(ctx, is)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	i := strings.LastIndex(account, "@")
+	return &privFilter{privs: privs, user: account[:i], host: account[i+1:]}, nil
+}
+
+func (f *privFilter) tableVisible(db, table string) bool {
+	return f.privs == nil || strings.EqualFold(db, infoschema.Name) || f.privs.TableVisible(f.user, f.host, db, table)
+}
+
+func (f *privFilter) columnVisible(db, table, column string) bool {
+	return f.privs == nil || strings.EqualFold(db, infoschema.Name) || f.privs.ColumnVisible(f.user, f.host, db, table, column)
+}
+
+// doPrivileges returns a row for every privilege of the accounts on the level of the table, the accounts
+// without global privileges have a USAGE row in USER_PRIVILEGES. The sessions without the SELECT privilege
+// on the system database only see the privileges of their own accounts.
+func (isp *InfoSchemaPlan) doPrivileges(ctx context.Context, is infoschema.InfoSchema, iterFunc plan.RowIterFunc) error {
+	privs, err := sessionctx.GetDomain(ctx).PrivilegeHandle().Get(ctx, is)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	visible := func(user, host string) bool { return true }
+	if name, pattern := restrictedAccount(ctx, privs); len(name) > 0 {
+		visible = func(user, host string) bool {
+			return user == name && strings.EqualFold(host, pattern)
+		}
+	}
+
+	var records [][]interface{}
+	add := func(user, host string, p, grantable mysql.PrivilegeType, names ...interface{}) {
+		if !visible(user, host) {
+			return
+		}
+		isGrantable := "NO"
+		if grantable&mysql.GrantPriv != 0 {
+			isGrantable = "YES"
+		}
+		grantee := auth.FormatUser(user, host)
+		for _, priv := range (p &^ mysql.GrantPriv).Privileges() {
+			record := append([]interface{}{grantee, catalogVal}, names...)
+			records = append(records, append(record, priv.String(), isGrantable))
+		}
+		if p&^mysql.GrantPriv == 0 && isp.TableName == tableUserPrivileges {
+			records = append(records, []interface{}{grantee, catalogVal, "USAGE", isGrantable})
+		}
+	}
+	switch isp.TableName {
+	case tableUserPrivileges:
+		for _, r := range privs.User {
+			add(r.User, r.Host, r.Privs, r.Privs)
+		}
+	case tableSchemaPrivileges:
+		for _, r := range privs.DB {
+			add(r.User, r.Host, r.Privs, r.Privs, r.DB)
+		}
+	case tableTablePrivileges:
+		for _, r := range privs.TablesPriv {
+			add(r.User, r.Host, r.TablePriv, r.TablePriv, r.DB, r.TableName)
+		}
+	case tableColumnPrivileges:
+		for _, r := range privs.ColumnsPriv {
+			grantable := privs.TablePrivs(r.User, r.Host, r.DB, r.TableName)
+			add(r.User, r.Host, r.ColumnPriv, grantable, r.DB, r.TableName, r.ColumnName)
+		}
+	}
+	for _, record := range records {
+		if more, err := iterFunc(0, record); !more || err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func buildResultFieldsForCollations() (rfs []*field.ResultField) {
+	tbName := tableCollations
+	rfs = append(rfs, buildResultField(tbName, "COLLATION_NAME", mysql.TypeVarchar, 32))
+	rfs = append(rfs, buildResultField(tbName, "CHARACTER_SET_NAME", mysql.TypeVarchar, 32))
+	rfs = append(rfs, buildResultField(tbName, "ID", mysql.TypeLonglong, 11))
+	rfs = append(rfs, buildResultField(tbName, "IS_DEFAULT", mysql.TypeVarchar, 3))
+	rfs = append(rfs, buildResultField(tbName, "IS_COMPILED", mysql.TypeVarchar, 3))
+	rfs = append(rfs, buildResultField(tbName, "SORTLEN", mysql.TypeLonglong, 3))
+	return rfs
+}
+
+// supportedCollations returns the collations of the charsets in CHARACTER_SETS.
+func supportedCollations() []*charset.Collation {
+	var collations []*charset.Collation
+	for _, c := range charset.GetCollations() {
+		for _, record := range characterSetsRecords {
+			if record[0] == c.CharsetName {
+				collations = append(collations, c)
+				break
+			}
+		}
+	}
+	return collations
+}
+
+func (isp *InfoSchemaPlan) doCollations(iterFunc plan.RowIterFunc) error {
+	for _, c := range supportedCollations() {
+		isDefault := ""
+		if c.IsDefault {
+			isDefault = "Yes"
+		}
+		record := []interface{}{
+			c.Name,        // COLLATION_NAME
+			c.CharsetName, // CHARACTER_SET_NAME
+			c.ID,          // ID
+			isDefault,     // IS_DEFAULT
+			"Yes",         // IS_COMPILED
+			1,             // SORTLEN
+		}
+		if more, err := iterFunc(0, record); !more || err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func buildResultFieldsForCollationCharacterSetApplicability() (rfs []*field.ResultField) {
+	tbName := tableCollationCharacterSetApplicability
+	rfs = append(rfs, buildResultField(tbName, "COLLATION_NAME", mysql.TypeVarchar, 32))
+	rfs = append(rfs, buildResultField(tbName, "CHARACTER_SET_NAME", mysql.TypeVarchar, 32))
+	return rfs
+}
+
+func (isp *InfoSchemaPlan) doCollationCharacterSetApplicability(iterFunc plan.RowIterFunc) error {
+	for _, c := range supportedCollations() {
+		if more, err := iterFunc(0, []interface{}{c.Name, c.CharsetName}); !more || err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func buildResultFieldsForKeyColumnUsage() (rfs []*field.ResultField) {
+	tbName := tableKeyColumnUsage
+	rfs = append(rfs, buildResultField(tbName, "CONSTRAINT_CATALOG", mysql.TypeVarchar, 512))
+	rfs = append(rfs, buildResultField(tbName, "CONSTRAINT_SCHEMA", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "CONSTRAINT_NAME", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "TABLE_CATALOG", mysql.TypeVarchar, 512))
+	rfs = append(rfs, buildResultField(tbName, "TABLE_SCHEMA", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "TABLE_NAME", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "COLUMN_NAME", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "ORDINAL_POSITION", mysql.TypeLonglong, 10))
+	rfs = append(rfs, buildResultField(tbName, "POSITION_IN_UNIQUE_CONSTRAINT", mysql.TypeLonglong, 10))
+	rfs = append(rfs, buildResultField(tbName, "REFERENCED_TABLE_SCHEMA", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "REFERENCED_TABLE_NAME", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "REFERENCED_COLUMN_NAME", mysql.TypeVarchar, 64))
+	return rfs
+}
+
+// doKeyColumnUsage returns a row for every column of the primary keys and the unique indices,
+// there are no foreign keys.
+func (isp *InfoSchemaPlan) doKeyColumnUsage(schemas []*model.DBInfo, iterFunc plan.RowIterFunc) error {
+	for _, schema := range schemas {
+		for _, table := range schema.Tables {
+			for _, index := range constraintIndices(table) {
+				for i, key := range index.Columns {
+					record := []interface{}{
+						catalogVal,    // CONSTRAINT_CATALOG
+						schema.Name.O, // CONSTRAINT_SCHEMA
+						index.Name.O,  // CONSTRAINT_NAME
+						catalogVal,    // TABLE_CATALOG
+						schema.Name.O, // TABLE_SCHEMA
+						table.Name.O,  // TABLE_NAME
+						key.Name.O,    // COLUMN_NAME
+						i + 1,         // ORDINAL_POSITION
+						nil,           // POSITION_IN_UNIQUE_CONSTRAINT
+						nil,           // REFERENCED_TABLE_SCHEMA
+						nil,           // REFERENCED_TABLE_NAME
+						nil,           // REFERENCED_COLUMN_NAME
+					}
+					if more, err := iterFunc(0, record); !more || err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// constraintIndices returns the primary key and the unique indices of the table.
+func constraintIndices(table *model.TableInfo) []*model.IndexInfo {
+	var indices []*model.IndexInfo
+	for _, index := range orderedIndices(table) {
+		if index.Primary || index.Unique {
+			indices = append(indices, index)
+		}
+	}
+	return indices
+}
+
+func buildResultFieldsForTableConstraints() (rfs []*field.ResultField) {
+	tbName := tableTableConstraints
+	rfs = append(rfs, buildResultField(tbName, "CONSTRAINT_CATALOG", mysql.TypeVarchar, 512))
+	rfs = append(rfs, buildResultField(tbName, "CONSTRAINT_SCHEMA", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "CONSTRAINT_NAME", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "TABLE_SCHEMA", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "TABLE_NAME", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "CONSTRAINT_TYPE", mysql.TypeVarchar, 64))
+	return rfs
+}
+
+func (isp *InfoSchemaPlan) doTableConstraints(schemas []*model.DBInfo, iterFunc plan.RowIterFunc) error {
+	for _, schema := range schemas {
+		for _, table := range schema.Tables {
+			for _, index := range constraintIndices(table) {
+				tp := "UNIQUE"
+				if index.Primary {
+					tp = "PRIMARY KEY"
+				}
+				record := []interface{}{
+					catalogVal,    // CONSTRAINT_CATALOG
+					schema.Name.O, // CONSTRAINT_SCHEMA
+					index.Name.O,  // CONSTRAINT_NAME
+					schema.Name.O, // TABLE_SCHEMA
+					table.Name.O,  // TABLE_NAME
+					tp,            // CONSTRAINT_TYPE
+				}
+				if more, err := iterFunc(0, record); !more || err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func buildResultFieldsForReferentialConstraints() (rfs []*field.ResultField) {
+	tbName := tableReferentialConstraints
+	rfs = append(rfs, buildResultField(tbName, "CONSTRAINT_CATALOG", mysql.TypeVarchar, 512))
+	rfs = append(rfs, buildResultField(tbName, "CONSTRAINT_SCHEMA", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "CONSTRAINT_NAME", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "UNIQUE_CONSTRAINT_CATALOG", mysql.TypeVarchar, 512))
+	rfs = append(rfs, buildResultField(tbName, "UNIQUE_CONSTRAINT_SCHEMA", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "UNIQUE_CONSTRAINT_NAME", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "MATCH_OPTION", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "UPDATE_RULE", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "DELETE_RULE", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "TABLE_NAME", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "REFERENCED_TABLE_NAME", mysql.TypeVarchar, 64))
+	return rfs
+}
+
+func buildResultFieldsForEngines() (rfs []*field.ResultField) {
+	tbName := tableEngines
+	rfs = append(rfs, buildResultField(tbName, "ENGINE", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "SUPPORT", mysql.TypeVarchar, 8))
+	rfs = append(rfs, buildResultField(tbName, "COMMENT", mysql.TypeVarchar, 80))
+	rfs = append(rfs, buildResultField(tbName, "TRANSACTIONS", mysql.TypeVarchar, 3))
+	rfs = append(rfs, buildResultField(tbName, "XA", mysql.TypeVarchar, 3))
+	rfs = append(rfs, buildResultField(tbName, "SAVEPOINTS", mysql.TypeVarchar, 3))
+	return rfs
+}
+
+// doEngines returns the engine reported for all the tables.
+func (isp *InfoSchemaPlan) doEngines(iterFunc plan.RowIterFunc) error {
+	record := []interface{}{"InnoDB", "DEFAULT", "Supports transactions, row-level locking, and foreign keys", "YES", "NO", "NO"}
+	_, err := iterFunc(0, record)
+	return err
+}
+
+func buildResultFieldsForVariables(tbName string) (rfs []*field.ResultField) {
+	rfs = append(rfs, buildResultField(tbName, "VARIABLE_NAME", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "VARIABLE_VALUE", mysql.TypeVarchar, 1024))
+	return rfs
+}
+
+// sysVarValues returns the names and the global or the session values of the system variables ordered by name.
+// The values of the session variables not set in the session are the global values.
+func sysVarValues(ctx context.Context, session bool) (names, values []string, err error) {
+	var globals map[string]string
+	if accessor := variable.GetGlobalVarAccessor(ctx); accessor != nil {
+		if globals, err = accessor.GetAllSysVars(ctx); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	sessionVars := variable.GetSessionVars(ctx)
+	for name, sysVar := range variable.SysVars {
+		if !session && sysVar.Scope == variable.ScopeSession {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sysVar := variable.SysVars[name]
+		value, ok := globals[name]
+		if !ok || sysVar.Scope == variable.ScopeNone {
+			value = sysVar.Value
+		}
+		if session && sysVar.Scope != variable.ScopeGlobal {
+			if v, ok := sessionVars.Systems[name]; ok {
+				value = v
+			}
+		}
+		values = append(values, value)
+	}
+	return names, values, nil
+}
+
+func (isp *InfoSchemaPlan) doVariables(ctx context.Context, iterFunc plan.RowIterFunc) error {
+	names, values, err := sysVarValues(ctx, isp.TableName == tableSessionVariables)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for i, name := range names {
+		if more, err := iterFunc(0, []interface{}{strings.ToUpper(name), values[i]}); !more || err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func buildResultFieldsForProcessList() (rfs []*field.ResultField) {
+	tbName := tableProcessList
+	rfs = append(rfs, buildResultField(tbName, "ID", mysql.TypeLonglong, 21))
+	rfs = append(rfs, buildResultField(tbName, "USER", mysql.TypeVarchar, 16))
+	rfs = append(rfs, buildResultField(tbName, "HOST", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "DB", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "COMMAND", mysql.TypeVarchar, 16))
+	rfs = append(rfs, buildResultField(tbName, "TIME", mysql.TypeLonglong, 7))
+	rfs = append(rfs, buildResultField(tbName, "STATE", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "INFO", mysql.TypeBlob, 196606))
+	return rfs
+}
+
+// visibleProcesses returns the sessions of the domain, the sessions without the SELECT privilege
+// on the system database only see the sessions of their own user.
+func visibleProcesses(ctx context.Context, is infoschema.InfoSchema) ([]*domain.ProcessInfo, error) {
+	do := sessionctx.GetDomain(ctx)
+	privs, err := do.PrivilegeHandle().Get(ctx, is)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	name, _ := restrictedAccount(ctx, privs)
+	var processes []*domain.ProcessInfo
+	for _, p := range do.ProcessList() {
+		if len(name) == 0 || p.User == name {
+			processes = append(processes, p)
+		}
+	}
+	return processes, nil
+}
+
+func (isp *InfoSchemaPlan) doProcessList(ctx context.Context, is infoschema.InfoSchema, iterFunc plan.RowIterFunc) error {
+	processes, err := visibleProcesses(ctx, is)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, p := range processes {
+		var dbName, info interface{}
+		if len(p.DB) > 0 {
+			dbName = p.DB
+		}
+		if len(p.Info) > 0 {
+			info = p.Info
+		}
+		record := []interface{}{
+			p.ID,                                    // ID
+			p.User,                                  // USER
+			p.Host,                                  // HOST
+			dbName,                                  // DB
+			p.Command,                               // COMMAND
+			int64(time.Since(p.Time) / time.Second), // TIME
+			p.State,                                 // STATE
+			info,                                    // INFO
+		}
+		if more, err := iterFunc(0, record); !more || err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func buildResultFieldsForStatementsSummary() (rfs []*field.ResultField) {
+	tbName := tableStatementsSummary
+	rfs = append(rfs, buildResultField(tbName, "SCHEMA_NAME", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "DIGEST", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "DIGEST_TEXT", mysql.TypeBlob, 65535))
+	rfs = append(rfs, buildResultField(tbName, "COUNT_STAR", mysql.TypeLonglong, 20))
+	rfs = append(rfs, buildResultField(tbName, "SUM_TIMER_WAIT", mysql.TypeLonglong, 20))
+	rfs = append(rfs, buildResultField(tbName, "MIN_TIMER_WAIT", mysql.TypeLonglong, 20))
+	rfs = append(rfs, buildResultField(tbName, "AVG_TIMER_WAIT", mysql.TypeLonglong, 20))
+	rfs = append(rfs, buildResultField(tbName, "MAX_TIMER_WAIT", mysql.TypeLonglong, 20))
+	rfs = append(rfs, buildResultField(tbName, "SUM_ERRORS", mysql.TypeLonglong, 20))
+	rfs = append(rfs, buildResultField(tbName, "SUM_ROWS_SENT", mysql.TypeLonglong, 20))
+	rfs = append(rfs, buildResultField(tbName, "SUM_ROWS_EXAMINED", mysql.TypeLonglong, 20))
+	rfs = append(rfs, buildResultField(tbName, "FIRST_SEEN", mysql.TypeDatetime, 19))
+	rfs = append(rfs, buildResultField(tbName, "LAST_SEEN", mysql.TypeDatetime, 19))
+	rfs = append(rfs, buildResultField(tbName, "PLAN_DIGEST", mysql.TypeVarchar, 64))
+	return rfs
+}
+
+// doStatementsSummary returns a row for every statement summary of the domain. The table is laid out like
+// performance_schema.events_statements_summary_by_digest with the columns the summaries record and PLAN_DIGEST
+// at the end, it is in information_schema as there is no performance_schema. The timers are in picoseconds.
+// The sessions without the SELECT privilege on the system database see no summaries.
+func (isp *InfoSchemaPlan) doStatementsSummary(ctx context.Context, is infoschema.InfoSchema, iterFunc plan.RowIterFunc) error {
+	do := sessionctx.GetDomain(ctx)
+	privs, err := do.PrivilegeHandle().Get(ctx, is)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if name, _ := restrictedAccount(ctx, privs); len(name) > 0 {
+		return nil
+	}
+	picoseconds := func(d time.Duration) int64 {
+		return int64(d) * 1000
+	}
+	datetime := func(t time.Time) mysql.Time {
+		return mysql.Time{Time: t, Type: mysql.TypeDatetime, Fsp: mysql.DefaultFsp}
+	}
+	for _, s := range do.StmtSummaries() {
+		var schemaName, planDigest interface{}
+		if len(s.SchemaName) > 0 {
+			schemaName = s.SchemaName
+		}
+		if len(s.PlanDigest) > 0 {
+			planDigest = s.PlanDigest
+		}
+		record := []interface{}{
+			schemaName,                                         // SCHEMA_NAME
+			s.Digest,                                           // DIGEST
+			s.DigestText,                                       // DIGEST_TEXT
+			s.Count,                                            // COUNT_STAR
+			picoseconds(s.SumLatency),                          // SUM_TIMER_WAIT
+			picoseconds(s.MinLatency),                          // MIN_TIMER_WAIT
+			picoseconds(s.SumLatency / time.Duration(s.Count)), // AVG_TIMER_WAIT
+			picoseconds(s.MaxLatency),                          // MAX_TIMER_WAIT
+			s.Errors,                                           // SUM_ERRORS
+			s.SumRowsSent,                                      // SUM_ROWS_SENT
+			s.SumRowsExamined,                                  // SUM_ROWS_EXAMINED
+			datetime(s.FirstSeen),                              // FIRST_SEEN
+			datetime(s.LastSeen),                               // LAST_SEEN
+			planDigest,                                         // PLAN_DIGEST
+		}
+		if more, err := iterFunc(0, record); !more || err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func buildResultFieldsForFiles() (rfs []*field.ResultField) {
+	tbName := tableFiles
+	rfs = append(rfs, buildResultField(tbName, "FILE_ID", mysql.TypeLonglong, 4))
+	rfs = append(rfs, buildResultField(tbName, "FILE_NAME", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "FILE_TYPE", mysql.TypeVarchar, 20))
+	rfs = append(rfs, buildResultField(tbName, "TABLESPACE_NAME", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "TABLE_CATALOG", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "TABLE_SCHEMA", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "TABLE_NAME", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "LOGFILE_GROUP_NAME", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "LOGFILE_GROUP_NUMBER", mysql.TypeLonglong, 21))
+	rfs = append(rfs, buildResultField(tbName, "ENGINE", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "FULLTEXT_KEYS", mysql.TypeVarchar, 64))
+	rfs = append(rfs, buildResultField(tbName, "DELETED_ROWS", mysql.TypeLonglong, 4))
+	rfs = append(rfs, buildResultField(tbName, "UPDATE_COUNT", mysql.TypeLonglong, 4))
+	rfs = append(rfs, buildResultField(tbName, "FREE_EXTENTS", mysql.TypeLonglong, 21))
+	rfs = append(rfs, buildResultField(tbName, "TOTAL_EXTENTS", mysql.TypeLonglong, 21))
+	rfs = append(rfs, buildResultField(tbName, "EXTENT_SIZE", mysql.TypeLonglong, 21))
+	rfs = append(rfs, buildResultField(tbName, "INITIAL_SIZE", mysql.TypeLonglong, 21))
+	rfs = append(rfs, buildResultField(tbName, "MAXIMUM_SIZE", mysql.TypeLonglong, 21))
+	rfs = append(rfs, buildResultField(tbName, "AUTOEXTEND_SIZE", mysql.TypeLonglong, 21))
+	rfs = append(rfs, buildResultField(tbName, "CREATION_TIME", mysql.TypeDatetime, 19))
+	rfs = append(rfs, buildResultField(tbName, "LAST_UPDATE_TIME", mysql.TypeDatetime, 19))
+	rfs = append(rfs, buildResultField(tbName, "LAST_ACCESS_TIME", mysql.TypeDatetime, 19))
+	rfs = append(rfs, buildResultField(tbName, "RECOVER_TIME", mysql.TypeLonglong, 4))
+	rfs = append(rfs, buildResultField(tbName, "TRANSACTION_COUNTER", mysql.TypeLonglong, 4))
+	rfs = append(rfs, buildResultField(tbName, "VERSION", mysql.TypeLonglong, 21))
+	rfs = append(rfs, buildResultField(tbName, "ROW_FORMAT", mysql.TypeVarchar, 10))
+	rfs = append(rfs, buildResultField(tbName, "TABLE_ROWS", mysql.TypeLonglong, 21))
+	rfs = append(rfs, buildResultField(tbName, "AVG_ROW_LENGTH", mysql.TypeLonglong, 21))
+	rfs = append(rfs, buildResultField(tbName, "DATA_LENGTH", mysql.TypeLonglong, 21))
+	rfs = append(rfs, buildResultField(tbName, "MAX_DATA_LENGTH", mysql.TypeLonglong, 21))
+	rfs = append(rfs, buildResultField(tbName, "INDEX_LENGTH", mysql.TypeLonglong, 21))
+	rfs = append(rfs, buildResultField(tbName, "DATA_FREE", mysql.TypeLonglong, 21))
+	rfs = append(rfs, buildResultField(tbName, "CREATE_TIME", mysql.TypeDatetime, 19))
+	rfs = append(rfs, buildResultField(tbName, "UPDATE_TIME", mysql.TypeDatetime, 19))
+	rfs = append(rfs, buildResultField(tbName, "CHECK_TIME", mysql.TypeDatetime, 19))
+	rfs = append(rfs, buildResultField(tbName, "CHECKSUM", mysql.TypeLonglong, 21))
+	rfs = append(rfs, buildResultField(tbName, "STATUS", mysql.TypeVarchar, 20))
+	rfs = append(rfs, buildResultField(tbName, "EXTRA", mysql.TypeVarchar, 255))
+	return rfs
+}
+
+// schemas returns the schemas and the tables to scan, they are the ones with the names required by the filters.
+func (isp *InfoSchemaPlan) schemas(is infoschema.InfoSchema) []*model.DBInfo {
+	var schemas []*model.DBInfo
+	for _, schema := range is.AllSchemas() {
+		if len(isp.schemaName) > 0 && schema.Name.L != isp.schemaName {
+			continue
+		}
+		if len(isp.tableName) > 0 {
+			filtered := *schema
+			filtered.Tables = nil
+			for _, table := range schema.Tables {
+				if table.Name.L == isp.tableName {
+					filtered.Tables = append(filtered.Tables, table)
+				}
+			}
+			schema = &filtered
+		}
+		schemas = append(schemas, schema)
+	}
+	return schemas
+}
+
 // Do implements plan.Plan Do interface, constructs result data.
 func (isp *InfoSchemaPlan) Do(ctx context.Context, iterFunc plan.RowIterFunc) error {
+	if len(isp.filters) > 0 {
+		// Check the rows by the filters, the schemas and tables are still narrowed by their names.
+		src := *isp
+		src.filters = nil
+		expr := isp.filters[0]
+		for _, e := range isp.filters[1:] {
+			expr = expressions.NewBinaryOperation(opcode.AndAnd, expr, e)
+		}
+		return (&FilterDefaultPlan{Plan: &src, Expr: expr}).Do(ctx, iterFunc)
+	}
+
 	is := sessionctx.GetDomain(ctx).InfoSchema()
-	schemas := is.AllSchemas()
+	schemas := isp.schemas(is)
 	switch isp.TableName {
 	case tableSchemata:
-		return isp.doSchemata(is.AllSchemaNames(), iterFunc)
+		var names []string
+		for _, schema := range schemas {
+			names = append(names, schema.Name.O)
+		}
+		if len(isp.schemaName) == 0 || isp.schemaName == strings.ToLower(infoschema.Name) {
+			// information_schema is not in the schemas of is.
+			for _, name := range is.AllSchemaNames() {
+				if strings.EqualFold(name, infoschema.Name) {
+					names = append(names, name)
+				}
+			}
+		}
+		return isp.doSchemata(names, iterFunc)
 	case tableTables:
-		return isp.doTables(schemas, iterFunc)
+		return isp.doTables(ctx, is, schemas, iterFunc)
 	case tableColumns:
-		return isp.doColumns(schemas, iterFunc)
+		return isp.doColumns(ctx, is, schemas, iterFunc)
 	case tableStatistics:
 		return isp.doStatistics(is, schemas, iterFunc)
 	case tableCharacterSets:
 		return isp.doCharacterSets(iterFunc)
+	case tableUserPrivileges, tableSchemaPrivileges, tableTablePrivileges, tableColumnPrivileges:
+		return isp.doPrivileges(ctx, is, iterFunc)
+	case tableCollations:
+		return isp.doCollations(iterFunc)
+	case tableCollationCharacterSetApplicability:
+		return isp.doCollationCharacterSetApplicability(iterFunc)
+	case tableKeyColumnUsage:
+		return isp.doKeyColumnUsage(schemas, iterFunc)
+	case tableTableConstraints:
+		return isp.doTableConstraints(schemas, iterFunc)
+	case tableEngines:
+		return isp.doEngines(iterFunc)
+	case tableGlobalVariables, tableSessionVariables:
+		return isp.doVariables(ctx, iterFunc)
+	case tableProcessList:
+		return isp.doProcessList(ctx, is, iterFunc)
+	case tableStatementsSummary:
+		return isp.doStatementsSummary(ctx, is, iterFunc)
 	}
+	// There are no foreign keys and tablespace files.
 	return nil
 }
 
@@ -363,8 +1082,35 @@ func (isp *InfoSchemaPlan) Do(ctx context.Context, iterFunc plan.RowIterFunc) er
 func (isp *InfoSchemaPlan) Explain(w format.Formatter) {}
 
 // Filter implements plan.Plan Filter interface.
+// The conditions on the columns of the table are kept in the plan and checked in Do. If a condition
+// requires the schema name or the table name to be a value, only the schemas or tables with it are scanned.
 func (isp *InfoSchemaPlan) Filter(ctx context.Context, expr expression.Expression) (p plan.Plan, filtered bool, err error) {
-	return isp, false, nil
+	colNames := expressions.MentionedColumns(expr)
+	if len(colNames) == 0 || !field.ContainAllFieldNames(colNames, isp.GetFields(), field.DefaultFieldFlag) {
+		return isp, false, nil
+	}
+
+	n := *isp
+	n.filters = append(append([]expression.Expression(nil), isp.filters...), expr)
+	if x, ok := expr.(*expressions.BinaryOperation); ok && x.Op == opcode.EQ {
+		ok, name, val, err := x.IsIdentRelOpVal()
+		if err != nil {
+			return nil, false, errors.Trace(err)
+		}
+		if ok && val != nil {
+			str, err := types.ToString(val)
+			if err != nil {
+				return nil, false, errors.Trace(err)
+			}
+			switch strings.ToUpper(name) {
+			case "SCHEMA_NAME", "TABLE_SCHEMA", "CONSTRAINT_SCHEMA":
+				n.schemaName = strings.ToLower(str)
+			case "TABLE_NAME":
+				n.tableName = strings.ToLower(str)
+			}
+		}
+	}
+	return &n, true, nil
 }
 
 // GetFields implements plan.Plan GetFields interface, simulates MySQL's output.
@@ -380,6 +1126,36 @@ func (isp *InfoSchemaPlan) GetFields() []*field.ResultField {
 		return statisticsFields
 	case tableCharacterSets:
 		return characterSetsFields
+	case tableUserPrivileges:
+		return userPrivilegesFields
+	case tableSchemaPrivileges:
+		return schemaPrivilegesFields
+	case tableTablePrivileges:
+		return tablePrivilegesFields
+	case tableColumnPrivileges:
+		return columnPrivilegesFields
+	case tableCollations:
+		return collationsFields
+	case tableCollationCharacterSetApplicability:
+		return collationCharacterSetApplicabilityFields
+	case tableKeyColumnUsage:
+		return keyColumnUsageFields
+	case tableTableConstraints:
+		return tableConstraintsFields
+	case tableReferentialConstraints:
+		return referentialConstraintsFields
+	case tableEngines:
+		return enginesFields
+	case tableGlobalVariables:
+		return globalVariablesFields
+	case tableSessionVariables:
+		return sessionVariablesFields
+	case tableProcessList:
+		return processListFields
+	case tableFiles:
+		return filesFields
+	case tableStatementsSummary:
+		return statementsSummaryFields
 	}
 	return nil
 }
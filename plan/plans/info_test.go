@@ -82,8 +82,103 @@ func (p *testInfoSchemaSuit) TestInfoSchema(c *C) {
 	c.Assert(cnt, Greater, 0)
 	cnt = mustQuery(c, testDB, "select * from information_schema.columns")
 	c.Assert(cnt, Greater, 0)
-	cnt = mustQuery(c, testDB, "select * from information_schema.statistics")
+	cnt = mustQuery(c, testDB, "select * from information_schema.statistics where table_schema = 'test'")
 	c.Assert(cnt, Equals, 0)
 	cnt = mustQuery(c, testDB, "select * from information_schema.character_sets")
 	c.Assert(cnt, Greater, 0)
 }
+
+func mustQueryRows(c *C, currDB *sql.DB, s string) [][]string {
+	r, err := currDB.Query(s)
+	c.Assert(err, IsNil)
+	defer r.Close()
+	cols, err := r.Columns()
+	c.Assert(err, IsNil)
+	var rows [][]string
+	for r.Next() {
+		row := make([]sql.NullString, len(cols))
+		dest := make([]interface{}, len(cols))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+		c.Assert(r.Scan(dest...), IsNil)
+		strs := make([]string, len(cols))
+		for i, v := range row {
+			strs[i] = v.String
+		}
+		rows = append(rows, strs)
+	}
+	c.Assert(r.Err(), IsNil)
+	return rows
+}
+
+func (p *testInfoSchemaSuit) TestInfoSchemaTables(c *C) {
+	testDB, err := sql.Open(alloydb.DriverName, alloydb.EngineGoLevelDBMemory+"test_info")
+	c.Assert(err, IsNil)
+	mustExec(c, testDB, "create table t (id int primary key, c int, d int, unique index uc (c), index idx_d (d))")
+	mustExec(c, testDB, "insert t values (1, 1, 1), (2, 2, 2), (3, 3, 3)")
+
+	rows := mustQueryRows(c, testDB, "select table_rows, data_length > 0, index_length > 0 from information_schema.tables where table_schema = 'test_info' and table_name = 't'")
+	c.Assert(rows, DeepEquals, [][]string{{"3", "1", "1"}})
+	// The conditions on other columns are kept.
+	rows = mustQueryRows(c, testDB, "select table_name from information_schema.tables where table_schema = 'test_info' and table_rows > 3")
+	c.Assert(rows, HasLen, 0)
+	rows = mustQueryRows(c, testDB, "select schema_name from information_schema.schemata where schema_name = 'TEST_INFO'")
+	c.Assert(rows, DeepEquals, [][]string{{"test_info"}})
+	rows = mustQueryRows(c, testDB, "select column_name from information_schema.columns where table_schema = 'test_info' and table_name = 't' and column_name <> 'id'")
+	c.Assert(rows, DeepEquals, [][]string{{"c"}, {"d"}})
+
+	// The charsets and collations are the ones of the tables and columns.
+	mustExec(c, testDB, "create table t2 (a varchar(10) character set latin1, b varchar(10) collate utf8_bin, c blob)")
+	rows = mustQueryRows(c, testDB, "select table_collation from information_schema.tables where table_schema = 'test_info' and table_name = 't2'")
+	c.Assert(rows, DeepEquals, [][]string{{"utf8_general_ci"}})
+	rows = mustQueryRows(c, testDB, "select column_name, character_set_name, collation_name, collation_name is null from information_schema.columns where table_schema = 'test_info' and column_name in ('id', 'a', 'b', 'c') order by table_name, column_name")
+	c.Assert(rows, DeepEquals, [][]string{{"c", "", "", "1"}, {"id", "", "", "1"}, {"a", "latin1", "latin1_swedish_ci", "0"}, {"b", "utf8", "utf8_bin", "0"}, {"c", "", "", "1"}})
+
+	rows = mustQueryRows(c, testDB, "select constraint_name, column_name, ordinal_position from information_schema.key_column_usage where table_schema = 'test_info'")
+	c.Assert(rows, DeepEquals, [][]string{{"PRIMARY", "id", "1"}, {"uc", "c", "1"}})
+	rows = mustQueryRows(c, testDB, "select constraint_name, constraint_type from information_schema.table_constraints where table_name = 't' and table_schema = 'test_info'")
+	c.Assert(rows, DeepEquals, [][]string{{"PRIMARY", "PRIMARY KEY"}, {"uc", "UNIQUE"}})
+	c.Assert(mustQueryRows(c, testDB, "select * from information_schema.referential_constraints"), HasLen, 0)
+	c.Assert(mustQueryRows(c, testDB, "select * from information_schema.files"), HasLen, 0)
+
+	rows = mustQueryRows(c, testDB, "select character_set_name, is_default from information_schema.collations where collation_name = 'utf8_bin'")
+	c.Assert(rows, DeepEquals, [][]string{{"utf8", ""}})
+	rows = mustQueryRows(c, testDB, "select count(*) from information_schema.collation_character_set_applicability where character_set_name = 'latin1'")
+	c.Assert(rows[0][0], Not(Equals), "0")
+	rows = mustQueryRows(c, testDB, "select engine, support from information_schema.engines")
+	c.Assert(rows, DeepEquals, [][]string{{"InnoDB", "DEFAULT"}})
+
+	rows = mustQueryRows(c, testDB, "select variable_value from information_schema.global_variables where variable_name = 'max_connections'")
+	c.Assert(rows, DeepEquals, [][]string{{"151"}})
+	// The session only variables are not global.
+	rows = mustQueryRows(c, testDB, "select variable_value from information_schema.global_variables where variable_name = 'rand_seed2'")
+	c.Assert(rows, HasLen, 0)
+	rows = mustQueryRows(c, testDB, "select variable_value from information_schema.session_variables where variable_name = 'autocommit'")
+	c.Assert(rows, DeepEquals, [][]string{{"ON"}})
+
+	// The session running the query is in the process list.
+	rows = mustQueryRows(c, testDB, "select db, command, info from information_schema.processlist where info like '%processlist%'")
+	c.Assert(rows, DeepEquals, [][]string{{"test_info", "Query", "select db, command, info from information_schema.processlist where info like '%processlist%'"}})
+}
+
+func (p *testInfoSchemaSuit) TestStatementsSummary(c *C) {
+	testDB, err := sql.Open(alloydb.DriverName, alloydb.EngineGoLevelDBMemory+"test_stmt_summary")
+	c.Assert(err, IsNil)
+	mustExec(c, testDB, "create table t (id int primary key, c int)")
+	mustExec(c, testDB, "insert into t values (1, 10), (2, 20), (3, 30)")
+
+	// The statements differing only in literals have the same summary.
+	c.Assert(mustQueryRows(c, testDB, "select c from t where id = 1"), DeepEquals, [][]string{{"10"}})
+	c.Assert(mustQueryRows(c, testDB, "SELECT c FROM t WHERE id = 2"), DeepEquals, [][]string{{"20"}})
+	_, err = testDB.Query("select nosuch from t where id = 3")
+	c.Assert(err, NotNil)
+
+	rows := mustQueryRows(c, testDB, `select schema_name, count_star, sum_errors, sum_rows_sent, plan_digest is not null,
+		min_timer_wait <= max_timer_wait, first_seen <= last_seen
+		from information_schema.events_statements_summary_by_digest where digest_text = 'select c from t where id = ?'`)
+	c.Assert(rows, DeepEquals, [][]string{{"test_stmt_summary", "2", "0", "2", "1", "1", "1"}})
+	rows = mustQueryRows(c, testDB, `select count_star, sum_errors from information_schema.events_statements_summary_by_digest
+		where digest_text = 'select nosuch from t where id = ?'`)
+	c.Assert(rows, DeepEquals, [][]string{{"1", "1"}})
+}
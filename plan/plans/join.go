@@ -19,6 +19,7 @@ import (
 	"github.com/Dong-Chan/alloydb/expression/expressions"
 	"github.com/Dong-Chan/alloydb/field"
 	"github.com/Dong-Chan/alloydb/plan"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
 	"github.com/Dong-Chan/alloydb/util/format"
 )
 
@@ -152,11 +153,23 @@ func (r *JoinPlan) Do(ctx context.Context, f plan.RowIterFunc) error {
 	}
 }
 
+// newEvalArgs returns the eval args to evaluate the ON condition with the collations of the fields.
+func (r *JoinPlan) newEvalArgs() map[interface{}]interface{} {
+	m := map[interface{}]interface{}{}
+	m[expressions.ExprEvalCollationFunc] = func(name string) string {
+		return getIdentCollation(name, r.Fields, field.DefaultFieldFlag)
+	}
+	return m
+}
+
 func (r *JoinPlan) doCrossJoin(ctx context.Context, f plan.RowIterFunc) error {
 	return r.Left.Do(ctx, func(rid interface{}, in []interface{}) (more bool, err error) {
 		leftRow := appendRow(nil, in)
-		m := map[interface{}]interface{}{}
+		m := r.newEvalArgs()
 		if err := r.Right.Do(ctx, func(rid interface{}, in []interface{}) (more bool, err error) {
+			if err := variable.CheckKilled(ctx); err != nil {
+				return false, err
+			}
 			row := appendRow(leftRow, in)
 			if r.On != nil {
 				m[expressions.ExprEvalIdentFunc] = func(name string) (interface{}, error) {
@@ -186,8 +199,11 @@ func (r *JoinPlan) doLeftJoin(ctx context.Context, f plan.RowIterFunc) error {
 	return r.Left.Do(ctx, func(rid interface{}, in []interface{}) (more bool, err error) {
 		leftRow := appendRow(nil, in)
 		matched := false
-		m := map[interface{}]interface{}{}
+		m := r.newEvalArgs()
 		if err := r.Right.Do(ctx, func(rid interface{}, in []interface{}) (more bool, err error) {
+			if err := variable.CheckKilled(ctx); err != nil {
+				return false, err
+			}
 			row := appendRow(leftRow, in)
 
 			m[expressions.ExprEvalIdentFunc] = func(name string) (interface{}, error) {
@@ -224,8 +240,11 @@ func (r *JoinPlan) doRightJoin(ctx context.Context, f plan.RowIterFunc) error {
 	return r.Right.Do(ctx, func(rid interface{}, in []interface{}) (more bool, err error) {
 		rightRow := appendRow(nil, in)
 		matched := false
-		m := map[interface{}]interface{}{}
+		m := r.newEvalArgs()
 		if err := r.Left.Do(ctx, func(rid interface{}, in []interface{}) (more bool, err error) {
+			if err := variable.CheckKilled(ctx); err != nil {
+				return false, err
+			}
 			row := appendRow(in, rightRow)
 
 			m[expressions.ExprEvalIdentFunc] = func(name string) (interface{}, error) {
@@ -270,8 +289,11 @@ func (r *JoinPlan) doFullJoin(ctx context.Context, f plan.RowIterFunc) error {
 	return r.Right.Do(ctx, func(rid interface{}, in []interface{}) (more bool, err error) {
 		rightRow := appendRow(nil, in)
 		matched := false
-		m := map[interface{}]interface{}{}
+		m := r.newEvalArgs()
 		if err := r.Left.Do(ctx, func(rid interface{}, in []interface{}) (more bool, err error) {
+			if err := variable.CheckKilled(ctx); err != nil {
+				return false, err
+			}
 			row := appendRow(in, rightRow)
 
 			m[expressions.ExprEvalIdentFunc] = func(name string) (interface{}, error) {
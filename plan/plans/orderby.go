@@ -14,16 +14,22 @@
 package plans
 
 import (
+	"container/heap"
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 
 	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/column"
 	"github.com/Dong-Chan/alloydb/context"
 	"github.com/Dong-Chan/alloydb/expression"
 	"github.com/Dong-Chan/alloydb/expression/expressions"
 	"github.com/Dong-Chan/alloydb/field"
+	"github.com/Dong-Chan/alloydb/kv/memkv"
+	"github.com/Dong-Chan/alloydb/parser/coldef"
 	"github.com/Dong-Chan/alloydb/plan"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
 	"github.com/Dong-Chan/alloydb/util/format"
 	"github.com/Dong-Chan/alloydb/util/types"
 )
@@ -32,11 +38,15 @@ var _ plan.Plan = (*OrderByDefaultPlan)(nil)
 
 // OrderByDefaultPlan handles ORDER BY statement, it uses an array to store
 // results temporarily, and sorts them by given expression.
+// If Limit is not 0, only the first Limit rows are needed, and a bounded heap
+// is used to keep them instead of the whole result set.
+// If the results exceed the memory quota, an external merge sort is used.
 type OrderByDefaultPlan struct {
 	*SelectList
-	By   []expression.Expression
-	Ascs []bool
-	Src  plan.Plan
+	By    []expression.Expression
+	Ascs  []bool
+	Src   plan.Plan
+	Limit uint64
 }
 
 // Explain implements plan.Plan Explain interface.
@@ -53,6 +63,9 @@ func (r *OrderByDefaultPlan) Explain(w format.Formatter) {
 		items[i] = fmt.Sprintf(" %s %s", v, order)
 	}
 	w.Format("%s", strings.Join(items, ","))
+	if r.Limit > 0 {
+		w.Format("\n│Keep top %d records", r.Limit)
+	}
 	w.Format("\n└Output field names %v\n", field.RFQNames(r.ResultFields))
 }
 
@@ -98,9 +111,13 @@ func (t *orderByTable) Swap(i, j int) {
 
 // Less implements sort.Interface Less interface.
 func (t *orderByTable) Less(i, j int) bool {
+	return t.lessRow(t.Rows[i], t.Rows[j])
+}
+
+func (t *orderByTable) lessRow(a, b *orderByRow) bool {
 	for index, asc := range t.Ascs {
-		v1 := t.Rows[i].Key[index]
-		v2 := t.Rows[j].Key[index]
+		v1 := a.Key[index]
+		v2 := b.Key[index]
 
 		ret := types.Compare(v1, v2)
 		if !asc {
@@ -117,13 +134,79 @@ func (t *orderByTable) Less(i, j int) bool {
 	return false
 }
 
+// topNTable keeps the first N rows of an orderByTable.
+// It is a heap whose root is the greatest row, so the root is replaced
+// when a less row arrives and the table is full.
+type topNTable struct {
+	*orderByTable
+	N uint64
+}
+
+// Less implements heap.Interface Less interface, the greatest row is on the top.
+func (t *topNTable) Less(i, j int) bool {
+	return t.lessRow(t.Rows[j], t.Rows[i])
+}
+
+// Push implements heap.Interface Push interface.
+func (t *topNTable) Push(x interface{}) {
+	t.Rows = append(t.Rows, x.(*orderByRow))
+}
+
+// Pop implements heap.Interface Pop interface.
+func (t *topNTable) Pop() interface{} {
+	n := len(t.Rows)
+	row := t.Rows[n-1]
+	t.Rows = t.Rows[:n-1]
+	return row
+}
+
+func (t *topNTable) add(row *orderByRow) {
+	if uint64(len(t.Rows)) < t.N {
+		heap.Push(t, row)
+		return
+	}
+
+	// Replace the greatest row on the top if the new row is less than it.
+	if t.lessRow(row, t.Rows[0]) {
+		t.Rows[0] = row
+		heap.Fix(t, 0)
+	}
+}
+
 // Do implements plan.Plan Do interface, all records are added into an
 // in-memory array, and sorted in ASC/DESC order.
-func (r *OrderByDefaultPlan) Do(ctx context.Context, f plan.RowIterFunc) error {
+// If Limit is set, only the first Limit records are kept in a heap
+// during the iteration.
+// If the records exceed the memory quota of the statement, the sorted array
+// is saved to disk as a run, and all the runs are merged at last.
+func (r *OrderByDefaultPlan) Do(ctx context.Context, f plan.RowIterFunc) (err error) {
 	t := &orderByTable{Ascs: r.Ascs}
+	var topN *topNTable
+	if r.Limit > 0 {
+		topN = &topNTable{orderByTable: t, N: r.Limit}
+	}
+
+	var (
+		quota = variable.GetMemQuotaQuery(ctx)
+		size  int64
+		runs  []memkv.Temp
+	)
+	defer func() {
+		for _, run := range runs {
+			if derr := run.Drop(); derr != nil && err == nil {
+				err = derr
+			}
+		}
+	}()
 
 	m := map[interface{}]interface{}{}
-	err := r.Src.Do(ctx, func(rid interface{}, in []interface{}) (bool, error) {
+	m[expressions.ExprEvalCollationFunc] = func(name string) string {
+		return getIdentCollation(name, r.ResultFields, field.CheckFieldFlag)
+	}
+	err = r.Src.Do(ctx, func(rid interface{}, in []interface{}) (bool, error) {
+		if err := variable.CheckKilled(ctx); err != nil {
+			return false, err
+		}
 		m[expressions.ExprEvalIdentFunc] = func(name string) (interface{}, error) {
 			return getIdentValue(name, r.ResultFields, in, field.CheckFieldFlag)
 		}
@@ -162,10 +245,32 @@ func (r *OrderByDefaultPlan) Do(ctx context.Context, f plan.RowIterFunc) error {
 				}
 			}
 
+			// The strings are sorted by the sort keys of their collation.
+			val = expressions.SortKey(by, m, val)
+
 			row.Key = append(row.Key, val)
 		}
 
+		if topN != nil {
+			// topN has a bounded size, so it is never spilled.
+			topN.add(row)
+			return true, nil
+		}
+
 		t.Rows = append(t.Rows, row)
+		if quota <= 0 {
+			return true, nil
+		}
+
+		size += types.EstimateSize(row.Key) + types.EstimateSize(row.Row)
+		if size > quota {
+			run, err := t.spill()
+			if err != nil {
+				return false, err
+			}
+			runs = append(runs, run)
+			size = 0
+		}
 		return true, nil
 	})
 	if err != nil {
@@ -174,6 +279,10 @@ func (r *OrderByDefaultPlan) Do(ctx context.Context, f plan.RowIterFunc) error {
 
 	sort.Sort(t)
 
+	if len(runs) > 0 {
+		return t.merge(runs, f)
+	}
+
 	var more bool
 	for _, row := range t.Rows {
 		if more, err = f(nil, row.Row); !more || err != nil {
@@ -182,3 +291,219 @@ func (r *OrderByDefaultPlan) Do(ctx context.Context, f plan.RowIterFunc) error {
 	}
 	return types.EOFAsNil(err)
 }
+
+// spill sorts the rows and saves them to a temporary kv on disk as a sorted run,
+// the rows are saved with their sequence numbers as keys, and then cleared.
+func (t *orderByTable) spill() (memkv.Temp, error) {
+	sort.Sort(t)
+
+	run, err := memkv.CreateDiskTemp(true)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	for i, row := range t.Rows {
+		v := make([]interface{}, 0, len(row.Key)+len(row.Row))
+		v = append(append(v, row.Key...), row.Row...)
+		if err = run.Set([]interface{}{int64(i)}, v); err != nil {
+			run.Drop()
+			return nil, errors.Trace(err)
+		}
+	}
+
+	t.Rows = nil
+	return run, nil
+}
+
+// merge merges the sorted runs on disk and the sorted rows in memory,
+// and calls f with the merged rows in order.
+func (t *orderByTable) merge(runs []memkv.Temp, f plan.RowIterFunc) error {
+	h := &mergeHeap{orderByTable: t}
+	if err := h.add(&memRowSource{rows: t.Rows}); err != nil {
+		return errors.Trace(err)
+	}
+	for _, run := range runs {
+		it, err := run.SeekFirst()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err = h.add(&diskRowSource{it: it, keyLen: len(t.Ascs)}); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	for len(h.items) > 0 {
+		item := h.items[0]
+		more, err := f(nil, item.row.Row)
+		if !more || err != nil {
+			return types.EOFAsNil(err)
+		}
+
+		// Move to the next row of the source.
+		if item.row, err = item.src.next(); err == io.EOF {
+			heap.Pop(h)
+		} else if err != nil {
+			return errors.Trace(err)
+		} else {
+			heap.Fix(h, 0)
+		}
+	}
+	return nil
+}
+
+// rowSource provides sorted rows for merging, io.EOF is returned at the end.
+type rowSource interface {
+	next() (*orderByRow, error)
+}
+
+type memRowSource struct {
+	rows []*orderByRow
+}
+
+func (s *memRowSource) next() (*orderByRow, error) {
+	if len(s.rows) == 0 {
+		return nil, io.EOF
+	}
+	row := s.rows[0]
+	s.rows = s.rows[1:]
+	return row, nil
+}
+
+type diskRowSource struct {
+	it interface {
+		Next() (k, v []interface{}, err error)
+	}
+	keyLen int
+}
+
+func (s *diskRowSource) next() (*orderByRow, error) {
+	_, v, err := s.it.Next()
+	if err != nil {
+		return nil, err
+	}
+	return &orderByRow{Key: v[:s.keyLen], Row: v[s.keyLen:]}, nil
+}
+
+type mergeItem struct {
+	row *orderByRow
+	src rowSource
+}
+
+// mergeHeap is a heap of the current rows of the sources, the least row is on the top.
+type mergeHeap struct {
+	*orderByTable
+	items []*mergeItem
+}
+
+// Len implements heap.Interface Len interface.
+func (h *mergeHeap) Len() int {
+	return len(h.items)
+}
+
+// Swap implements heap.Interface Swap interface.
+func (h *mergeHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+}
+
+// Less implements heap.Interface Less interface.
+func (h *mergeHeap) Less(i, j int) bool {
+	return h.lessRow(h.items[i].row, h.items[j].row)
+}
+
+// Push implements heap.Interface Push interface.
+func (h *mergeHeap) Push(x interface{}) {
+	h.items = append(h.items, x.(*mergeItem))
+}
+
+// Pop implements heap.Interface Pop interface.
+func (h *mergeHeap) Pop() interface{} {
+	n := len(h.items)
+	item := h.items[n-1]
+	h.items = h.items[:n-1]
+	return item
+}
+
+func (h *mergeHeap) add(src rowSource) error {
+	row, err := src.next()
+	if err == io.EOF {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	heap.Push(h, &mergeItem{row: row, src: src})
+	return nil
+}
+
+// UseIndexOrder tries to read the rows of src in the order of the index on column name,
+// so the sort for "ORDER BY name" can be eliminated. Only plans which keep the order
+// of their source rows are walked through, and the underlying table plan is replaced
+// by an index plan, or the underlying index plan is reused if it is on the same column.
+// It returns the new plan and true if the order is guaranteed by an index.
+func UseIndexOrder(src plan.Plan, name string, asc bool) (plan.Plan, bool) {
+	switch x := src.(type) {
+	case *SelectFieldsDefaultPlan:
+		p, ok := UseIndexOrder(x.Src, name, asc)
+		if ok {
+			x.Src = p
+		}
+		return x, ok
+	case *SelectLockPlan:
+		if x.Lock == coldef.SelectLockForUpdate {
+			// The index plan doesn't output row keys, so rows can't be locked.
+			return x, false
+		}
+		p, ok := UseIndexOrder(x.Src, name, asc)
+		if ok {
+			x.Src = p
+		}
+		return x, ok
+	case *FilterDefaultPlan:
+		p, ok := UseIndexOrder(x.Plan, name, asc)
+		if ok {
+			x.Plan = p
+		}
+		return x, ok
+	case *JoinPlan:
+		if x.Right != nil {
+			return x, false
+		}
+		// The table may have an alias name, so resolve the column here.
+		indices := field.GetResultFieldIndex(name, x.Fields, field.DefaultFieldFlag)
+		if len(indices) != 1 {
+			return x, false
+		}
+		p, ok := UseIndexOrder(x.Left, x.Fields[indices[0]].ColumnInfo.Name.L, asc)
+		if ok {
+			x.Left = p
+		}
+		return x, ok
+	case *TableDefaultPlan:
+		indices := field.GetResultFieldIndex(name, x.Fields, field.DefaultFieldFlag)
+		if len(indices) != 1 {
+			return x, false
+		}
+		colName := x.Fields[indices[0]].ColumnInfo.Name.L
+		ix := x.T.FindIndexByColName(colName)
+		if ix == nil {
+			return x, false
+		}
+		return &indexPlan{
+			src:     x.T,
+			colName: colName,
+			idxName: ix.Name.O,
+			idx:     column.BaseIndex(ix.X),
+			// The whole index including NULL values, NULL is the smallest value.
+			spans: []*indexSpan{{lowVal: nil, highVal: maxVal}},
+			desc:  !asc,
+		}, true
+	case *indexPlan:
+		fields := x.GetFields()
+		indices := field.GetResultFieldIndex(name, fields, field.DefaultFieldFlag)
+		if len(indices) != 1 || fields[indices[0]].ColumnInfo.Name.L != x.colName {
+			return x, false
+		}
+		x.desc = !asc
+		return x, true
+	}
+	return src, false
+}
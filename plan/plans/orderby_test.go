@@ -14,11 +14,15 @@
 package plans
 
 import (
+	"fmt"
+
 	"github.com/ngaut/log"
 	. "github.com/pingcap/check"
 	"github.com/Dong-Chan/alloydb/expression"
 	"github.com/Dong-Chan/alloydb/expression/expressions"
 	"github.com/Dong-Chan/alloydb/model"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/util/mock"
 )
 
 type testOrderBySuit struct {
@@ -65,3 +69,91 @@ func (t *testOrderBySuit) TestOrderBy(c *C) {
 		log.Error(err)
 	}
 }
+
+func (t *testOrderBySuit) TestOrderByLimit(c *C) {
+	tblPlan := &testTablePlan{t.data, []string{"id", "name"}}
+
+	pln := &OrderByDefaultPlan{
+		SelectList: &SelectList{
+			HiddenFieldOffset: len(tblPlan.GetFields()),
+			ResultFields:      tblPlan.GetFields(),
+		},
+		Src: tblPlan,
+		By: []expression.Expression{
+			&expressions.Ident{
+				model.NewCIStr("name"),
+			},
+		},
+		Ascs:  []bool{false},
+		Limit: 3,
+	}
+
+	var names []string
+	err := pln.Do(nil, func(id interface{}, data []interface{}) (bool, error) {
+		names = append(names, data[1].(string))
+		return true, nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(names, DeepEquals, []string{"60", "40", "30"})
+
+	// limit is larger than the row count.
+	pln.Ascs = []bool{true}
+	pln.Limit = 10
+	names = names[:0]
+	err = pln.Do(nil, func(id interface{}, data []interface{}) (bool, error) {
+		names = append(names, data[1].(string))
+		return true, nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(names, DeepEquals, []string{"10", "20", "30", "40", "60"})
+}
+
+func (t *testOrderBySuit) TestOrderBySpill(c *C) {
+	var rows []*testRowData
+	for i := 0; i < 100; i++ {
+		id := int64(i * 37 % 100)
+		rows = append(rows, &testRowData{int64(i), []interface{}{id, fmt.Sprintf("name%d", id)}})
+	}
+	tblPlan := &testTablePlan{rows, []string{"id", "name"}}
+
+	pln := &OrderByDefaultPlan{
+		SelectList: &SelectList{
+			HiddenFieldOffset: len(tblPlan.GetFields()),
+			ResultFields:      tblPlan.GetFields(),
+		},
+		Src: tblPlan,
+		By: []expression.Expression{
+			&expressions.Ident{
+				model.NewCIStr("id"),
+			},
+		},
+		Ascs: []bool{false},
+	}
+
+	// The rows are saved in several runs on disk.
+	ctx := mock.NewContext()
+	variable.BindSessionVars(ctx)
+	variable.GetSessionVars(ctx).Systems[variable.MemQuotaQuery] = "1024"
+
+	var ids []int64
+	err := pln.Do(ctx, func(id interface{}, data []interface{}) (bool, error) {
+		c.Assert(data[1], Equals, fmt.Sprintf("name%d", data[0]))
+		ids = append(ids, data[0].(int64))
+		return true, nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(ids, HasLen, 100)
+	for i, id := range ids {
+		c.Assert(id, Equals, int64(99-i))
+	}
+
+	// Stop in the middle of merging.
+	ids = ids[:0]
+	pln.Ascs = []bool{true}
+	err = pln.Do(ctx, func(id interface{}, data []interface{}) (bool, error) {
+		ids = append(ids, data[0].(int64))
+		return len(ids) < 3, nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(ids, DeepEquals, []int64{0, 1, 2})
+}
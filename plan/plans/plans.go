@@ -37,11 +37,13 @@ var (
 )
 
 func isTableOrIndex(p plan.Plan) bool {
-	switch p.(type) {
+	switch x := p.(type) {
 	case
 		*indexPlan,
 		*TableDefaultPlan:
 		return true
+	case *AnalyzePlan:
+		return isTableOrIndex(x.Plan)
 	default:
 		return false
 	}
@@ -56,6 +58,26 @@ func getIdentValue(name string, fields []*field.ResultField, row []interface{},
 	return row[index], nil
 }
 
+// getIdentCollation returns the collation of the field name, or "" if the field is not found.
+func getIdentCollation(name string, fields []*field.ResultField, flag uint32) string {
+	indices := field.GetResultFieldIndex(name, fields, flag)
+	if len(indices) == 0 {
+		return ""
+	}
+	return fields[indices[0]].Collate
+}
+
+// canRangeIndex reports whether the comparison with column c can be done by an index range.
+// Enum and set values are compared by name but indexed by number, and a bit value may be
+// clipped by casting, so these columns are not filtered by index ranges.
+func canRangeIndex(c *column.Col) bool {
+	switch c.Tp {
+	case mysql.TypeEnum, mysql.TypeSet, mysql.TypeBit:
+		return false
+	}
+	return true
+}
+
 // This is not used, should be removed???
 type selectIndexDefaultPlan struct {
 	nm string
@@ -170,6 +192,15 @@ func setResultFieldInfo(fields []*field.ResultField, values []interface{}) error
 				rf.Col.Tp = mysql.TypeDuration
 			case mysql.Decimal:
 				rf.Col.Tp = mysql.TypeDecimal
+			case mysql.Enum:
+				rf.Col.Tp = mysql.TypeEnum
+			case mysql.Set:
+				rf.Col.Tp = mysql.TypeSet
+			case mysql.Bit:
+				rf.Col.Tp = mysql.TypeBit
+				rf.Col.Flen = v.Width
+			case mysql.JSON:
+				rf.Col.Tp = mysql.TypeJSON
 			default:
 				return errors.Errorf("Unknown type %T", c)
 			}
@@ -15,6 +15,7 @@ package plans
 
 import (
 	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/context"
 	"github.com/Dong-Chan/alloydb/expression"
 	"github.com/Dong-Chan/alloydb/expression/expressions"
 	"github.com/Dong-Chan/alloydb/field"
@@ -130,7 +131,8 @@ func (s *SelectList) CloneHiddenField(name string, tableFields []*field.ResultFi
 
 // ResolveSelectList gets fields and result fields from selectFields and srcFields,
 // including field validity check and wildcard field processing.
-func ResolveSelectList(selectFields []*field.Field, srcFields []*field.ResultField) (*SelectList, error) {
+// If the select is a subquery, the fields can reference the columns of its outer query.
+func ResolveSelectList(ctx context.Context, selectFields []*field.Field, srcFields []*field.ResultField) (*SelectList, error) {
 	selectList := &SelectList{
 		Fields:       make([]*field.Field, 0, len(selectFields)),
 		ResultFields: make([]*field.ResultField, 0, len(selectFields)),
@@ -167,11 +169,11 @@ func ResolveSelectList(selectFields []*field.Field, srcFields []*field.ResultFie
 		}
 
 		var result *field.ResultField
-		if err = field.CheckAllFieldNames(names, srcFields, field.DefaultFieldFlag); err != nil {
+		if err = field.CheckAllFieldNames(innerNames(ctx, names, srcFields), srcFields, field.DefaultFieldFlag); err != nil {
 			return nil, errors.Trace(err)
 		}
 
-		if _, ok := v.Expr.(*expressions.Ident); ok {
+		if _, ok := v.Expr.(*expressions.Ident); ok && field.ContainFieldName(name, srcFields, field.DefaultFieldFlag) {
 			// Field is ident.
 			if result, err = field.CloneFieldByName(name, srcFields, field.DefaultFieldFlag); err != nil {
 				return nil, errors.Trace(err)
@@ -184,6 +186,7 @@ func ResolveSelectList(selectFields []*field.Field, srcFields []*field.ResultFie
 		} else {
 			// The field is not an ident, maybe binary expression,
 			// like `select c1 + c2`, or `select c1 + 10`, etc.
+			// Or it is an ident of the outer query.
 			result = &field.ResultField{Name: v.Name}
 		}
 
@@ -199,3 +202,17 @@ func ResolveSelectList(selectFields []*field.Field, srcFields []*field.ResultFie
 
 	return selectList, nil
 }
+
+// innerNames returns the names not referencing the outer query columns of a subquery.
+func innerNames(ctx context.Context, names []string, srcFields []*field.ResultField) []string {
+	var inner []string
+	for _, name := range names {
+		if !field.ContainFieldName(name, srcFields, field.DefaultFieldFlag) {
+			if _, ok := expressions.OuterIdentValue(ctx, name); ok {
+				continue
+			}
+		}
+		inner = append(inner, name)
+	}
+	return inner
+}
@@ -0,0 +1,163 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plans
+
+import (
+	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/expression/expressions"
+	"github.com/Dong-Chan/alloydb/field"
+	"github.com/Dong-Chan/alloydb/kv/memkv"
+	"github.com/Dong-Chan/alloydb/plan"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/util/charset"
+	"github.com/Dong-Chan/alloydb/util/format"
+)
+
+var (
+	_ plan.Plan = (*SemiJoinPlan)(nil)
+)
+
+// SemiJoinPlan filters the rows of Src by whether they have matched rows in Inner.
+// It is the decorrelated plan for "[NOT] EXISTS (select ...)" and "expr [NOT] IN (select ...)" in where,
+// so the subquery is executed only once instead of once for every row of Src.
+// The rows of Inner are the keys to match, they are saved in a temporary table,
+// and a row of Src is matched if the values of OuterKeys for it are in the table.
+// The strings in the keys are matched by the sort keys of their collations.
+type SemiJoinPlan struct {
+	Src   plan.Plan
+	Inner plan.Plan
+	// OuterKeys are evaluated with the rows of Src, and compared with the rows of Inner.
+	OuterKeys []expression.Expression
+	// Collations are the collations to compare the keys, "" means the key is compared
+	// without a collation.
+	Collations []string
+	// Anti is true, output the rows without matched rows, like "NOT EXISTS".
+	Anti bool
+	// NullAware is true for "NOT IN", NULL in keys makes the result NULL,
+	// so the row is not output unless Inner is empty.
+	NullAware bool
+	// Expr is the original subquery expression.
+	Expr expression.Expression
+}
+
+// Explain implements plan.Plan Explain interface.
+func (r *SemiJoinPlan) Explain(w format.Formatter) {
+	r.Src.Explain(w)
+	r.Inner.Explain(w)
+	if r.Anti {
+		w.Format("┌Anti semi join on %v\n", r.Expr)
+	} else {
+		w.Format("┌Semi join on %v\n", r.Expr)
+	}
+	w.Format("└Output field names %v\n", field.RFQNames(r.GetFields()))
+}
+
+// Filter implements plan.Plan Filter interface.
+func (r *SemiJoinPlan) Filter(ctx context.Context, expr expression.Expression) (plan.Plan, bool, error) {
+	return r, false, nil
+}
+
+// GetFields implements plan.Plan GetFields interface.
+func (r *SemiJoinPlan) GetFields() []*field.ResultField {
+	return r.Src.GetFields()
+}
+
+// Do implements plan.Plan Do interface.
+func (r *SemiJoinPlan) Do(ctx context.Context, f plan.RowIterFunc) (err error) {
+	t, err := memkv.CreateTempWithQuota(true, variable.GetMemQuotaQuery(ctx))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if derr := t.Drop(); derr != nil && err == nil {
+			err = errors.Trace(derr)
+		}
+	}()
+
+	innerEmpty, innerNull := true, false
+	err = r.Inner.Do(ctx, func(id interface{}, data []interface{}) (bool, error) {
+		innerEmpty = false
+		for _, v := range data {
+			if v == nil {
+				// NULL never equals to any key.
+				innerNull = true
+				return true, nil
+			}
+		}
+		return true, errors.Trace(t.Set(r.collatedKeys(data, nil), []interface{}{true}))
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if r.NullAware && innerNull {
+		// expr NOT IN (..., NULL) is false or NULL.
+		return nil
+	}
+
+	fields := r.Src.GetFields()
+	m := map[interface{}]interface{}{}
+	keys := make([]interface{}, len(r.OuterKeys))
+	return r.Src.Do(ctx, func(rid interface{}, data []interface{}) (bool, error) {
+		m[expressions.ExprEvalIdentFunc] = func(name string) (interface{}, error) {
+			return getIdentValue(name, fields, data, field.DefaultFieldFlag)
+		}
+
+		hasNull := false
+		for i, e := range r.OuterKeys {
+			v, err := e.Eval(ctx, m)
+			if err != nil {
+				return false, errors.Trace(err)
+			}
+			keys[i] = v
+			hasNull = hasNull || v == nil
+		}
+
+		if hasNull && r.NullAware && !innerEmpty {
+			// NULL NOT IN (non empty set) is NULL.
+			return true, nil
+		}
+
+		matched := false
+		if !hasNull {
+			v, err := t.Get(r.collatedKeys(keys, keys))
+			if err != nil {
+				return false, errors.Trace(err)
+			}
+			matched = len(v) > 0
+		}
+
+		if matched == r.Anti {
+			return true, nil
+		}
+		return f(rid, data)
+	})
+}
+
+// collatedKeys returns the keys with the strings replaced by the sort keys of r.Collations,
+// the result is saved in buf if it is not nil.
+func (r *SemiJoinPlan) collatedKeys(keys []interface{}, buf []interface{}) []interface{} {
+	if buf == nil {
+		buf = make([]interface{}, len(keys))
+	}
+	for i, v := range keys {
+		if str, ok := v.(string); ok && i < len(r.Collations) && r.Collations[i] != "" {
+			v = charset.GetCollator(r.Collations[i]).Key(str)
+		}
+		buf[i] = v
+	}
+	return buf
+}
@@ -0,0 +1,79 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plans
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/expression/expressions"
+	"github.com/Dong-Chan/alloydb/model"
+)
+
+type testSemiJoinSuite struct{}
+
+var _ = Suite(&testSemiJoinSuite{})
+
+func (t *testSemiJoinSuite) TestSemiJoin(c *C) {
+	src := &testTablePlan{[]*testRowData{
+		&testRowData{1, []interface{}{int64(1), "a"}},
+		&testRowData{2, []interface{}{int64(2), "b"}},
+		&testRowData{3, []interface{}{nil, "c"}},
+		&testRowData{4, []interface{}{int64(4), "d"}},
+	}, []string{"id", "name"}}
+
+	tbl := []struct {
+		inner     []interface{}
+		anti      bool
+		nullAware bool
+		names     []string
+	}{
+		// exists
+		{[]interface{}{int64(1), int64(4), int64(5)}, false, false, []string{"a", "d"}},
+		// not exists
+		{[]interface{}{int64(1), int64(4), int64(5)}, true, false, []string{"b", "c"}},
+		{[]interface{}{int64(1), nil}, true, false, []string{"b", "c", "d"}},
+		// in
+		{[]interface{}{int64(2), nil}, false, false, []string{"b"}},
+		// not in
+		{[]interface{}{int64(1), int64(4)}, true, true, []string{"b"}},
+		{[]interface{}{int64(1), nil}, true, true, nil},
+		{nil, true, true, []string{"a", "b", "c", "d"}},
+	}
+
+	for _, ca := range tbl {
+		var rows []*testRowData
+		for i, v := range ca.inner {
+			rows = append(rows, &testRowData{int64(i), []interface{}{v}})
+		}
+
+		p := &SemiJoinPlan{
+			Src:   src,
+			Inner: &testTablePlan{rows, []string{"c"}},
+			OuterKeys: []expression.Expression{
+				&expressions.Ident{CIStr: model.NewCIStr("id")},
+			},
+			Anti:      ca.anti,
+			NullAware: ca.nullAware,
+		}
+		c.Assert(p.GetFields(), HasLen, 2)
+
+		var names []string
+		err := p.Do(nil, func(id interface{}, data []interface{}) (bool, error) {
+			names = append(names, data[1].(string))
+			return true, nil
+		})
+		c.Assert(err, IsNil)
+		c.Assert(names, DeepEquals, ca.names, Commentf("%v", ca))
+	}
+}
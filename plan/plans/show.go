@@ -14,21 +14,28 @@
 package plans
 
 import (
+	"bytes"
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/Dong-Chan/alloydb/column"
 	"github.com/Dong-Chan/alloydb/context"
 	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/expression/expressions"
 	"github.com/Dong-Chan/alloydb/field"
 	"github.com/Dong-Chan/alloydb/model"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
 	"github.com/Dong-Chan/alloydb/plan"
 	"github.com/Dong-Chan/alloydb/sessionctx"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
 	"github.com/Dong-Chan/alloydb/stmt"
+	"github.com/Dong-Chan/alloydb/table"
 	"github.com/Dong-Chan/alloydb/util/charset"
 	"github.com/Dong-Chan/alloydb/util/format"
+	"github.com/Dong-Chan/alloydb/util/types"
 )
 
 var (
@@ -43,6 +50,11 @@ type ShowPlan struct {
 	ColumnName string
 	Flag       int
 	Full       bool
+	User       string // The account in the user@host form.
+	// GlobalScope is set for SHOW GLOBAL VARIABLES and SHOW GLOBAL STATUS.
+	GlobalScope bool
+
+	CountWarnings bool
 }
 
 func (s *ShowPlan) isColOK(c *column.Col) bool {
@@ -80,11 +92,17 @@ func (s *ShowPlan) Do(ctx context.Context, f plan.RowIterFunc) (err error) {
 		if !is.SchemaExists(dbName) {
 			return errors.Errorf("Can not find DB: %s", dbName)
 		}
+		filter, err := newPrivFilter(ctx, is)
+		if err != nil {
+			return errors.Trace(err)
+		}
 
 		// sort for tables
 		var tableNames []string
 		for _, v := range is.SchemaTables(dbName) {
-			tableNames = append(tableNames, v.TableName().L)
+			if filter.tableVisible(dbName.L, v.TableName().L) {
+				tableNames = append(tableNames, v.TableName().L)
+			}
 		}
 
 		sort.Strings(tableNames)
@@ -101,10 +119,14 @@ func (s *ShowPlan) Do(ctx context.Context, f plan.RowIterFunc) (err error) {
 		if err != nil {
 			return errors.Errorf("Can not find table: %s", s.TableName)
 		}
+		filter, err := newPrivFilter(ctx, is)
+		if err != nil {
+			return errors.Trace(err)
+		}
 		cols := tb.Cols()
 
 		for _, col := range cols {
-			if !s.isColOK(col) {
+			if !s.isColOK(col) || !filter.columnVisible(dbName.L, tbName.L, col.Name.L) {
 				continue
 			}
 
@@ -135,8 +157,10 @@ func (s *ShowPlan) Do(ctx context.Context, f plan.RowIterFunc) (err error) {
 				})
 			}
 		}
-	case stmt.ShowWarnings:
-		// empty result
+	case stmt.ShowWarnings, stmt.ShowErrors:
+		s.fetchWarnings(ctx, f)
+	case stmt.ShowGrants:
+		return s.fetchGrants(ctx, f)
 	case stmt.ShowCharset:
 		// See: http://dev.mysql.com/doc/refman/5.7/en/show-character-set.html
 		descs := charset.GetAllCharsets()
@@ -144,10 +168,427 @@ func (s *ShowPlan) Do(ctx context.Context, f plan.RowIterFunc) (err error) {
 			row := []interface{}{desc.Name, desc.Desc, desc.DefaultCollation, desc.Maxlen}
 			f(0, row)
 		}
+	case stmt.ShowCollation:
+		return s.fetchCollation(f)
+	case stmt.ShowCreateTable:
+		return s.fetchCreateTable(ctx, f)
+	case stmt.ShowCreateDatabase:
+		return s.fetchCreateDatabase(ctx, f)
+	case stmt.ShowIndex:
+		return s.fetchIndex(ctx, f)
+	case stmt.ShowTableStatus:
+		return s.fetchTableStatus(ctx, f)
+	case stmt.ShowVariables:
+		return s.fetchVariables(ctx, f)
+	case stmt.ShowStatus:
+		return s.fetchStatus(ctx, f)
+	case stmt.ShowProcessList:
+		return s.fetchProcessList(ctx, f)
+	}
+	return nil
+}
+
+func (s *ShowPlan) getTable(ctx context.Context) (table.Table, error) {
+	is := sessionctx.GetDomain(ctx).InfoSchema()
+	tb, err := is.TableByName(model.NewCIStr(s.DBName), model.NewCIStr(s.TableName))
+	if err != nil {
+		return nil, mysql.NewDefaultError(mysql.ErNoSuchTable, s.DBName, s.TableName)
+	}
+	return tb, nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/show-collation.html
+func (s *ShowPlan) fetchCollation(f plan.RowIterFunc) error {
+	var charsets []string
+	for _, desc := range charset.GetAllCharsets() {
+		charsets = append(charsets, desc.Name)
+	}
+	for _, c := range charset.GetCollations() {
+		supported := false
+		for _, name := range charsets {
+			if c.CharsetName == name {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			continue
+		}
+		isDefault := ""
+		if c.IsDefault {
+			isDefault = "Yes"
+		}
+		row := []interface{}{c.Name, c.CharsetName, c.ID, isDefault, "Yes", 1}
+		if more, err := f(0, row); !more || err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tableCollation returns the collation of the table, it is the default collation of the charset
+// of the table if no collation is specified.
+func tableCollation(tbInfo *model.TableInfo) string {
+	if len(tbInfo.Collate) > 0 {
+		return tbInfo.Collate
+	}
+	if len(tbInfo.Charset) > 0 {
+		if co, err := charset.GetDefaultCollation(tbInfo.Charset); err == nil {
+			return co
+		}
+	}
+	return mysql.DefaultCollationName
+}
+
+// quoteReplacer escapes the value in a quoted string.
+var quoteReplacer = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+// writeColumnDef writes the definition of col in the CREATE TABLE statement to buf.
+func writeColumnDef(buf *bytes.Buffer, col *column.Col) {
+	// The type is like `VARCHAR (10)` in the column description.
+	tp := strings.Replace(column.NewColDesc(col).Type, " (", "(", 1)
+	fmt.Fprintf(buf, "  `%s` %s", col.Name.O, tp)
+	notNull := mysql.HasNotNullFlag(col.Flag)
+	if notNull {
+		buf.WriteString(" NOT NULL")
+	}
+	if mysql.HasAutoIncrementFlag(col.Flag) {
+		buf.WriteString(" AUTO_INCREMENT")
+	} else if !mysql.HasNoDefaultValueFlag(col.Flag) {
+		switch col.DefaultValue {
+		case nil:
+			if !notNull {
+				buf.WriteString(" DEFAULT NULL")
+			}
+		case expressions.CurrentTimestamp:
+			buf.WriteString(" DEFAULT CURRENT_TIMESTAMP")
+		default:
+			fmt.Fprintf(buf, " DEFAULT '%s'", quoteReplacer.Replace(fmt.Sprint(col.DefaultValue)))
+		}
+	}
+	if mysql.HasOnUpdateNowFlag(col.Flag) {
+		buf.WriteString(" ON UPDATE CURRENT_TIMESTAMP")
+	}
+}
+
+// writeIndexDef writes the definition of index in the CREATE TABLE statement to buf.
+func writeIndexDef(buf *bytes.Buffer, index *model.IndexInfo) {
+	switch {
+	case index.Primary:
+		buf.WriteString("  PRIMARY KEY ")
+	case index.Unique:
+		fmt.Fprintf(buf, "  UNIQUE KEY `%s` ", index.Name.O)
+	default:
+		fmt.Fprintf(buf, "  KEY `%s` ", index.Name.O)
+	}
+	var cols []string
+	for _, key := range index.Columns {
+		col := fmt.Sprintf("`%s`", key.Name.O)
+		if key.Length != types.UnspecifiedLength {
+			col = fmt.Sprintf("%s(%d)", col, key.Length)
+		}
+		cols = append(cols, col)
+	}
+	fmt.Fprintf(buf, "(%s)", strings.Join(cols, ","))
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/show-create-table.html
+func (s *ShowPlan) fetchCreateTable(ctx context.Context, f plan.RowIterFunc) error {
+	tb, err := s.getTable(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tbInfo := tb.Meta()
+	var defs []string
+	for _, col := range tb.Cols() {
+		var buf bytes.Buffer
+		writeColumnDef(&buf, col)
+		defs = append(defs, buf.String())
+	}
+	for _, index := range orderedIndices(tbInfo) {
+		var buf bytes.Buffer
+		writeIndexDef(&buf, index)
+		defs = append(defs, buf.String())
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "CREATE TABLE `%s` (\n", tbInfo.Name.O)
+	buf.WriteString(strings.Join(defs, ",\n"))
+	buf.WriteString("\n) ENGINE=InnoDB")
+	cs := tbInfo.Charset
+	if len(cs) == 0 {
+		cs = mysql.DefaultCharset
+	}
+	fmt.Fprintf(&buf, " DEFAULT CHARSET=%s", cs)
+	if len(tbInfo.Collate) > 0 {
+		fmt.Fprintf(&buf, " COLLATE=%s", tbInfo.Collate)
+	}
+	_, err = f(0, []interface{}{tbInfo.Name.O, buf.String()})
+	return err
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/show-create-database.html
+func (s *ShowPlan) fetchCreateDatabase(ctx context.Context, f plan.RowIterFunc) error {
+	is := sessionctx.GetDomain(ctx).InfoSchema()
+	dbInfo, ok := is.SchemaByName(model.NewCIStr(s.DBName))
+	if !ok {
+		return mysql.NewDefaultError(mysql.ErBadDbError, s.DBName)
+	}
+	cs := dbInfo.Charset
+	if len(cs) == 0 {
+		cs = mysql.DefaultCharset
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "CREATE DATABASE `%s` /*!40100 DEFAULT CHARACTER SET %s", dbInfo.Name.O, cs)
+	if len(dbInfo.Collate) > 0 {
+		fmt.Fprintf(&buf, " COLLATE %s", dbInfo.Collate)
+	}
+	buf.WriteString(" */")
+	_, err := f(0, []interface{}{dbInfo.Name.O, buf.String()})
+	return err
+}
+
+// orderedIndices returns the indices of the table, the primary key comes first as MySQL does.
+func orderedIndices(tbInfo *model.TableInfo) []*model.IndexInfo {
+	var indices []*model.IndexInfo
+	for _, index := range tbInfo.Indices {
+		if index.Primary {
+			indices = append([]*model.IndexInfo{index}, indices...)
+		} else {
+			indices = append(indices, index)
+		}
+	}
+	return indices
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/show-index.html
+func (s *ShowPlan) fetchIndex(ctx context.Context, f plan.RowIterFunc) error {
+	tb, err := s.getTable(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cols := tb.Cols()
+	for _, index := range orderedIndices(tb.Meta()) {
+		nonUnique := 1
+		if index.Unique || index.Primary {
+			nonUnique = 0
+		}
+		for i, key := range index.Columns {
+			var subPart interface{}
+			if key.Length != types.UnspecifiedLength {
+				subPart = key.Length
+			}
+			null := "YES"
+			if mysql.HasNotNullFlag(cols[key.Offset].Flag) {
+				null = ""
+			}
+			row := []interface{}{
+				tb.TableName().O, // Table
+				nonUnique,        // Non_unique
+				index.Name.O,     // Key_name
+				i + 1,            // Seq_in_index
+				key.Name.O,       // Column_name
+				"A",              // Collation
+				0,                // Cardinality
+				subPart,          // Sub_part
+				nil,              // Packed
+				null,             // Null
+				"BTREE",          // Index_type
+				"",               // Comment
+				"",               // Index_comment
+			}
+			if more, err := f(0, row); !more || err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/show-table-status.html
+func (s *ShowPlan) fetchTableStatus(ctx context.Context, f plan.RowIterFunc) error {
+	is := sessionctx.GetDomain(ctx).InfoSchema()
+	dbName := model.NewCIStr(s.DBName)
+	if !is.SchemaExists(dbName) {
+		return mysql.NewDefaultError(mysql.ErBadDbError, s.DBName)
+	}
+	txn, err := ctx.GetTxn(false)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	filter, err := newPrivFilter(ctx, is)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tables := is.SchemaTables(dbName)
+	sort.Sort(tablesByName(tables))
+	for _, tb := range tables {
+		if !filter.tableVisible(dbName.L, tb.TableName().L) {
+			continue
+		}
+		rows, dataLength, indexLength, err := tableStats(txn, tb)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		var avgRowLength uint64
+		if rows > 0 {
+			avgRowLength = dataLength / rows
+		}
+		row := []interface{}{
+			tb.TableName().O,          // Name
+			"InnoDB",                  // Engine
+			uint64(10),                // Version
+			"Compact",                 // Row_format
+			rows,                      // Rows
+			avgRowLength,              // Avg_row_length
+			dataLength,                // Data_length
+			uint64(0),                 // Max_data_length
+			indexLength,               // Index_length
+			uint64(0),                 // Data_free
+			nil,                       // Auto_increment
+			nil,                       // Create_time
+			nil,                       // Update_time
+			nil,                       // Check_time
+			tableCollation(tb.Meta()), // Collation
+			nil,                       // Checksum
+			"",                        // Create_options
+			"",                        // Comment
+		}
+		if more, err := f(0, row); !more || err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+type tablesByName []table.Table
+
+func (t tablesByName) Len() int           { return len(t) }
+func (t tablesByName) Less(i, j int) bool { return t[i].TableName().L < t[j].TableName().L }
+func (t tablesByName) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/show-variables.html
+func (s *ShowPlan) fetchVariables(ctx context.Context, f plan.RowIterFunc) error {
+	names, values, err := sysVarValues(ctx, !s.GlobalScope)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for i, name := range names {
+		if more, err := f(0, []interface{}{name, values[i]}); !more || err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/show-status.html
+func (s *ShowPlan) fetchStatus(ctx context.Context, f plan.RowIterFunc) error {
+	statusVars, err := variable.GetStatusVars(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var names []string
+	for name, v := range statusVars {
+		if s.GlobalScope && v.Scope == variable.ScopeSession {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if more, err := f(0, []interface{}{name, fmt.Sprint(statusVars[name].Value)}); !more || err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// See: https://dev.mysql.com/doc/refman/5.7/en/show-processlist.html
+func (s *ShowPlan) fetchProcessList(ctx context.Context, f plan.RowIterFunc) error {
+	processes, err := visibleProcesses(ctx, sessionctx.GetDomain(ctx).InfoSchema())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, p := range processes {
+		var dbName, info interface{}
+		if len(p.DB) > 0 {
+			dbName = p.DB
+		}
+		if len(p.Info) > 0 {
+			// Only the first 100 characters of the statement are shown without FULL.
+			if len(p.Info) > 100 && !s.Full {
+				info = p.Info[:100]
+			} else {
+				info = p.Info
+			}
+		}
+		row := []interface{}{
+			p.ID,
+			p.User,
+			p.Host,
+			dbName,
+			p.Command,
+			int64(time.Since(p.Time) / time.Second),
+			p.State,
+			info,
+		}
+		if more, err := f(0, row); !more || err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ShowPlan) fetchWarnings(ctx context.Context, f plan.RowIterFunc) {
+	sessionVars := variable.GetSessionVars(ctx)
+	if s.CountWarnings {
+		if s.Target == stmt.ShowErrors {
+			f(0, []interface{}{sessionVars.ErrorCount()})
+		} else {
+			f(0, []interface{}{sessionVars.WarningCount()})
+		}
+		return
+	}
+
+	for _, w := range sessionVars.Warnings() {
+		if s.Target == stmt.ShowErrors && w.Level != variable.WarnLevelError {
+			continue
+		}
+		f(0, []interface{}{w.Level, int64(w.Code), w.Message})
+	}
+}
+
+func (s *ShowPlan) fetchGrants(ctx context.Context, f plan.RowIterFunc) error {
+	i := strings.LastIndex(s.User, "@")
+	if i < 0 {
+		return mysql.NewDefaultError(mysql.ErNonexistingGrant, s.User, "")
+	}
+	name, host := s.User[:i], s.User[i+1:]
+
+	do := sessionctx.GetDomain(ctx)
+	privs, err := do.PrivilegeHandle().Get(ctx, do.InfoSchema())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !privs.UserExists(name, host) {
+		return mysql.NewDefaultError(mysql.ErNonexistingGrant, name, host)
+	}
+	for _, g := range privs.ShowGrants(name, host) {
+		f(0, []interface{}{g})
+	}
+	return nil
+}
+
+func (s *ShowPlan) warningFieldNames() []string {
+	if !s.CountWarnings {
+		return []string{"Level", "Code", "Message"}
+	}
+	if s.Target == stmt.ShowErrors {
+		return []string{"@@session.error_count"}
+	}
+	return []string{"@@session.warning_count"}
+}
+
 // Explain implements plan.Plan Explain interface.
 func (s *ShowPlan) Explain(w format.Formatter) {
 	// TODO: finish this
@@ -166,10 +607,29 @@ func (s *ShowPlan) GetFields() []*field.ResultField {
 		names = []string{fmt.Sprintf("Tables_in_%s", s.DBName)}
 	case stmt.ShowColumns:
 		names = column.ColDescFieldNames(s.Full)
-	case stmt.ShowWarnings:
-		names = []string{"Level", "Code", "Message"}
+	case stmt.ShowWarnings, stmt.ShowErrors:
+		names = s.warningFieldNames()
+	case stmt.ShowGrants:
+		names = []string{fmt.Sprintf("Grants for %s", s.User)}
 	case stmt.ShowCharset:
 		names = []string{"Charset", "Description", "Default collation", "Maxlen"}
+	case stmt.ShowCollation:
+		names = []string{"Collation", "Charset", "Id", "Default", "Compiled", "Sortlen"}
+	case stmt.ShowCreateTable:
+		names = []string{"Table", "Create Table"}
+	case stmt.ShowCreateDatabase:
+		names = []string{"Database", "Create Database"}
+	case stmt.ShowIndex:
+		names = []string{"Table", "Non_unique", "Key_name", "Seq_in_index", "Column_name", "Collation",
+			"Cardinality", "Sub_part", "Packed", "Null", "Index_type", "Comment", "Index_comment"}
+	case stmt.ShowTableStatus:
+		names = []string{"Name", "Engine", "Version", "Row_format", "Rows", "Avg_row_length", "Data_length",
+			"Max_data_length", "Index_length", "Data_free", "Auto_increment", "Create_time", "Update_time",
+			"Check_time", "Collation", "Checksum", "Create_options", "Comment"}
+	case stmt.ShowVariables, stmt.ShowStatus:
+		names = []string{"Variable_name", "Value"}
+	case stmt.ShowProcessList:
+		names = []string{"Id", "User", "Host", "db", "Command", "Time", "State", "Info"}
 	}
 
 	fields := make([]*field.ResultField, 0, len(names))
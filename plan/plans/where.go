@@ -45,6 +45,9 @@ func (r *FilterDefaultPlan) Explain(w format.Formatter) {
 func (r *FilterDefaultPlan) Do(ctx context.Context, f plan.RowIterFunc) (err error) {
 	m := map[interface{}]interface{}{}
 	fields := r.GetFields()
+	m[expressions.ExprEvalCollationFunc] = func(name string) string {
+		return getIdentCollation(name, fields, field.DefaultFieldFlag)
+	}
 	return r.Plan.Do(ctx, func(rid interface{}, data []interface{}) (bool, error) {
 		m[expressions.ExprEvalIdentFunc] = func(name string) (interface{}, error) {
 			return getIdentValue(name, fields, data, field.DefaultFieldFlag)
@@ -0,0 +1,595 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plans
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/expression/expressions"
+	"github.com/Dong-Chan/alloydb/field"
+	"github.com/Dong-Chan/alloydb/plan"
+	"github.com/Dong-Chan/alloydb/util/format"
+	"github.com/Dong-Chan/alloydb/util/types"
+)
+
+var (
+	_ plan.Plan = (*WindowPlan)(nil)
+)
+
+// WindowPlan computes the window functions in select list with all the rows of Src,
+// the arguments and windows of the window functions are hidden fields evaluated by Src,
+// see rsets.WindowRset.
+// After the window function values are computed, the fields containing window functions
+// are evaluated again, and the rows are output in the order of Src.
+type WindowPlan struct {
+	*SelectList
+	Src plan.Plan
+	// Hidden maps the columns and aggregate functions out of window functions
+	// to the indices of hidden fields.
+	Hidden map[string]int
+}
+
+// Explain implements plan.Plan Explain interface.
+func (r *WindowPlan) Explain(w format.Formatter) {
+	r.Src.Explain(w)
+	w.Format("┌Compute window functions")
+	for _, f := range r.windowFuncs() {
+		w.Format(" %s,", f)
+	}
+	w.Format("\n└Output field names %v\n", field.RFQNames(r.ResultFields))
+}
+
+// Filter implements plan.Plan Filter interface.
+func (r *WindowPlan) Filter(ctx context.Context, expr expression.Expression) (plan.Plan, bool, error) {
+	return r, false, nil
+}
+
+// GetFields implements plan.Plan GetFields interface.
+func (r *WindowPlan) GetFields() []*field.ResultField {
+	return r.ResultFields
+}
+
+func (r *WindowPlan) windowFuncs() []*expressions.WindowFuncExpr {
+	var funcs []*expressions.WindowFuncExpr
+	for _, f := range r.Fields {
+		funcs = append(funcs, expressions.MentionedWindowFuncs(f.Expr)...)
+	}
+	return funcs
+}
+
+// Do implements plan.Plan Do interface.
+// All the rows of Src are buffered, for every window function, the rows are
+// sorted by partition by and order by values, and the function is computed
+// for every partition.
+func (r *WindowPlan) Do(ctx context.Context, f plan.RowIterFunc) error {
+	var rows [][]interface{}
+	err := r.Src.Do(ctx, func(rid interface{}, in []interface{}) (bool, error) {
+		rows = append(rows, in)
+		return true, nil
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	m := map[interface{}]interface{}{}
+	for _, wf := range r.windowFuncs() {
+		values, err := r.compute(ctx, wf, rows)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		m[wf] = values
+	}
+
+	for i, row := range rows {
+		if err := r.evalWindowFields(ctx, m, i, row); err != nil {
+			return errors.Trace(err)
+		}
+
+		if more, err := f(nil, row); !more || err != nil {
+			return types.EOFAsNil(err)
+		}
+	}
+	return nil
+}
+
+// evalWindowFields evaluates the fields containing window functions for the i-th row.
+func (r *WindowPlan) evalWindowFields(ctx context.Context, values map[interface{}]interface{}, i int, row []interface{}) error {
+	m := r.evalArgs(row)
+	for k, v := range values {
+		m[k] = v.([]interface{})[i]
+	}
+
+	for j, fld := range r.Fields {
+		if !expressions.ContainWindowFunc(fld.Expr) {
+			continue
+		}
+
+		// the aggregate functions out of window functions are computed in hidden fields by group by.
+		for _, call := range expressions.MentionedAggregateFuncs(fld.Expr) {
+			index, ok := r.Hidden[call.String()]
+			if !ok {
+				return errors.Errorf("unknown aggregate function %s", call)
+			}
+			m[expressions.ExprAggDone] = true
+			m[call] = &windowAggregateResult{v: row[index]}
+		}
+
+		var err error
+		if row[j], err = fld.Expr.Eval(ctx, m); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// evalArgs returns the eval args to evaluate expressions with the row.
+func (r *WindowPlan) evalArgs(row []interface{}) map[interface{}]interface{} {
+	m := map[interface{}]interface{}{}
+	m[expressions.ExprEvalPositionFunc] = func(position int) (interface{}, error) {
+		// position is in [1, len(fields)]
+		return row[position-1], nil
+	}
+	m[expressions.ExprEvalIdentFunc] = func(name string) (interface{}, error) {
+		if index, ok := r.Hidden[name]; ok {
+			return row[index], nil
+		}
+		return nil, errors.Errorf("unknown field %s", name)
+	}
+	return m
+}
+
+func (r *WindowPlan) evalList(ctx context.Context, list []expression.Expression, row []interface{}) ([]interface{}, error) {
+	m := r.evalArgs(row)
+	values := make([]interface{}, len(list))
+	for i, e := range list {
+		v, err := e.Eval(ctx, m)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// windowRow is a row of a window function, Key is the partition by values and order by values.
+type windowRow struct {
+	Index int
+	Key   []interface{}
+	Args  []interface{}
+}
+
+type windowTable struct {
+	Rows []*windowRow
+	// Ascs are the orders of Key, the partition by values are always in ascending order.
+	Ascs []bool
+}
+
+// Len implements sort.Interface Len interface.
+func (t *windowTable) Len() int {
+	return len(t.Rows)
+}
+
+// Swap implements sort.Interface Swap interface.
+func (t *windowTable) Swap(i, j int) {
+	t.Rows[i], t.Rows[j] = t.Rows[j], t.Rows[i]
+}
+
+// Less implements sort.Interface Less interface.
+func (t *windowTable) Less(i, j int) bool {
+	return t.compare(t.Rows[i].Key, t.Rows[j].Key, 0) < 0
+}
+
+// compare compares the keys from the offset.
+func (t *windowTable) compare(a, b []interface{}, offset int) int {
+	for i := offset; i < len(t.Ascs); i++ {
+		ret := types.Compare(a[i], b[i])
+		if !t.Ascs[i] {
+			ret = -ret
+		}
+		if ret != 0 {
+			return ret
+		}
+	}
+	return 0
+}
+
+// windowFrame computes the frame of the rows in a partition.
+type windowFrame struct {
+	*expressions.WindowSpec
+	Rows []*windowRow
+	// Offset is the offset of order by values in the keys.
+	Offset int
+	// Table is the sorted table of the rows.
+	Table *windowTable
+
+	// peerStarts and peerEnds are the ranges of peers of the rows.
+	peerStarts []int
+	peerEnds   []int
+}
+
+func newWindowFrame(spec *expressions.WindowSpec, rows []*windowRow, t *windowTable, offset int) *windowFrame {
+	p := &windowFrame{
+		WindowSpec: spec,
+		Rows:       rows,
+		Offset:     offset,
+		Table:      t,
+		peerStarts: make([]int, len(rows)),
+		peerEnds:   make([]int, len(rows)),
+	}
+
+	for start := 0; start < len(rows); {
+		end := start + 1
+		for end < len(rows) && t.compare(rows[start].Key, rows[end].Key, offset) == 0 {
+			end++
+		}
+		for k := start; k < end; k++ {
+			p.peerStarts[k], p.peerEnds[k] = start, end
+		}
+		start = end
+	}
+	return p
+}
+
+// peers returns the range of the rows which have the same order by values as the k-th row.
+func (p *windowFrame) peers(k int) (int, int) {
+	return p.peerStarts[k], p.peerEnds[k]
+}
+
+// bounds returns the frame [start, end) of the k-th row.
+func (p *windowFrame) bounds(k int) (int, int, error) {
+	n := len(p.Rows)
+	frame := p.Frame
+	if frame == nil {
+		if len(p.OrderBy) == 0 {
+			return 0, n, nil
+		}
+
+		// the default frame is RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW,
+		// which includes the peers of the current row.
+		_, end := p.peers(k)
+		return 0, end, nil
+	}
+
+	start, err := p.bound(k, frame.Start, true)
+	if err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	end, err := p.bound(k, frame.End, false)
+	if err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+
+	start, end = clamp(start, 0, n), clamp(end, 0, n)
+	if start > end {
+		start = end
+	}
+	return start, end, nil
+}
+
+func clamp(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// bound returns the position of the frame start or the exclusive frame end of the k-th row.
+func (p *windowFrame) bound(k int, b expressions.FrameBound, isStart bool) (int, error) {
+	switch b.Type {
+	case expressions.UnboundedPreceding:
+		return 0, nil
+	case expressions.UnboundedFollowing:
+		return len(p.Rows), nil
+	case expressions.CurrentRow:
+		if p.Frame.Unit == expressions.FrameRows {
+			if isStart {
+				return k, nil
+			}
+			return k + 1, nil
+		}
+
+		start, end := p.peers(k)
+		if isStart {
+			return start, nil
+		}
+		return end, nil
+	}
+
+	offset, err := b.Offset()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if b.Type == expressions.Preceding {
+		offset = -offset
+	}
+
+	if p.Frame.Unit == expressions.FrameRows {
+		pos := k + int(offset)
+		if !isStart {
+			pos++
+		}
+		return pos, nil
+	}
+	return p.rangeBound(k, offset, isStart)
+}
+
+// rangeBound returns the bound of RANGE N PRECEDING or RANGE N FOLLOWING,
+// the offset is added to the order by value of the k-th row in the order direction,
+// NULL values are the peers of each other.
+func (p *windowFrame) rangeBound(k int, offset float64, isStart bool) (int, error) {
+	index := p.Offset
+	asc := p.Table.Ascs[index]
+	// the NULL values are at the beginning in ascending order and at the end in descending order.
+	nullStart, nullEnd := 0, 0
+	if asc {
+		for nullEnd < len(p.Rows) && p.Rows[nullEnd].Key[index] == nil {
+			nullEnd++
+		}
+	} else {
+		nullStart, nullEnd = len(p.Rows), len(p.Rows)
+		for nullStart > 0 && p.Rows[nullStart-1].Key[index] == nil {
+			nullStart--
+		}
+	}
+
+	if p.Rows[k].Key[index] == nil {
+		if isStart {
+			return nullStart, nil
+		}
+		return nullEnd, nil
+	}
+
+	// the rows with not NULL values.
+	start, end := nullEnd, len(p.Rows)
+	if !asc {
+		start, end = 0, nullStart
+	}
+
+	signed := func(i int) (float64, error) {
+		v, err := types.ToFloat64(p.Rows[i].Key[index])
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		if !asc {
+			v = -v
+		}
+		return v, nil
+	}
+
+	v, err := signed(k)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	target := v + offset
+
+	var serr error
+	pos := start + sort.Search(end-start, func(i int) bool {
+		x, err := signed(start + i)
+		if err != nil {
+			serr = err
+			return true
+		}
+		if isStart {
+			return x >= target
+		}
+		return x > target
+	})
+	return pos, errors.Trace(serr)
+}
+
+// compute returns the values of the window function for the rows.
+func (r *WindowPlan) compute(ctx context.Context, wf *expressions.WindowFuncExpr, rows [][]interface{}) ([]interface{}, error) {
+	spec := wf.Spec
+	keys := append([]expression.Expression{}, spec.PartitionBy...)
+	t := &windowTable{Rows: make([]*windowRow, len(rows))}
+	for range spec.PartitionBy {
+		t.Ascs = append(t.Ascs, true)
+	}
+	for _, item := range spec.OrderBy {
+		keys = append(keys, item.Expr)
+		t.Ascs = append(t.Ascs, item.Asc)
+	}
+
+	for i, row := range rows {
+		key, err := r.evalList(ctx, keys, row)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		args, err := r.evalList(ctx, wf.Args, row)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		t.Rows[i] = &windowRow{Index: i, Key: key, Args: args}
+	}
+
+	// the peers keep the order of Src.
+	sort.Stable(t)
+
+	values := make([]interface{}, len(rows))
+	offset := len(spec.PartitionBy)
+	for start := 0; start < len(t.Rows); {
+		end := start + 1
+		for end < len(t.Rows) && types.Collators[true](t.Rows[start].Key[:offset], t.Rows[end].Key[:offset]) == 0 {
+			end++
+		}
+
+		p := newWindowFrame(spec, t.Rows[start:end], t, offset)
+		if err := computePartition(wf, p, values); err != nil {
+			return nil, errors.Trace(err)
+		}
+		start = end
+	}
+	return values, nil
+}
+
+// computePartition computes the window function for the rows of a partition, the values are saved
+// with the row index.
+func computePartition(wf *expressions.WindowFuncExpr, p *windowFrame, values []interface{}) error {
+	rows := p.Rows
+	switch strings.ToLower(wf.F) {
+	case "row_number":
+		for k, row := range rows {
+			values[row.Index] = int64(k + 1)
+		}
+	case "rank":
+		for k, row := range rows {
+			start, _ := p.peers(k)
+			values[row.Index] = int64(start + 1)
+		}
+	case "dense_rank":
+		rank := int64(0)
+		for k, row := range rows {
+			if start, _ := p.peers(k); start == k {
+				rank++
+			}
+			values[row.Index] = rank
+		}
+	case "ntile":
+		return computeNtile(p, values)
+	case "lag", "lead":
+		for k, row := range rows {
+			offset := int64(1)
+			if len(row.Args) > 1 {
+				if row.Args[1] == nil {
+					return errors.Errorf("invalid offset NULL of %s", wf.F)
+				}
+				var err error
+				if offset, err = types.ToInt64(row.Args[1]); err != nil || offset < 0 {
+					return errors.Errorf("invalid offset %v of %s", row.Args[1], wf.F)
+				}
+			}
+			if strings.EqualFold(wf.F, "lag") {
+				offset = -offset
+			}
+
+			if i := int64(k) + offset; i >= 0 && i < int64(len(rows)) {
+				values[row.Index] = rows[i].Args[0]
+			} else if len(row.Args) > 2 {
+				values[row.Index] = row.Args[2]
+			}
+		}
+	case "first_value", "last_value":
+		for k, row := range rows {
+			start, end, err := p.bounds(k)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if start == end {
+				continue
+			}
+			if strings.EqualFold(wf.F, "first_value") {
+				values[row.Index] = rows[start].Args[0]
+			} else {
+				values[row.Index] = rows[end-1].Args[0]
+			}
+		}
+	default:
+		return computeAggregate(wf, p, values)
+	}
+	return nil
+}
+
+// computeNtile divides the rows of partition into n buckets, the sizes of the first buckets
+// are one more than others if the rows can not be divided evenly.
+func computeNtile(p *windowFrame, values []interface{}) error {
+	rows := p.Rows
+	arg := rows[0].Args[0]
+	n, err := types.ToInt64(arg)
+	if arg == nil || err != nil || n <= 0 {
+		return errors.Errorf("invalid argument %v for NTILE, it must be a positive integer", arg)
+	}
+
+	size := int64(len(rows))
+	q, m := size/n, size%n
+	for k, row := range rows {
+		i := int64(k)
+		if i < m*(q+1) {
+			values[row.Index] = i/(q+1) + 1
+		} else {
+			values[row.Index] = m + (i-m*(q+1))/q + 1
+		}
+	}
+	return nil
+}
+
+// computeAggregate computes the aggregate function with the rows in the frame.
+// If the frame starts from the partition start, the accumulator is updated with
+// the new rows in the frame, otherwise it is computed with all the rows in the frame.
+func computeAggregate(wf *expressions.WindowFuncExpr, p *windowFrame, values []interface{}) error {
+	call := &expressions.Call{F: wf.F}
+	var (
+		agg    expressions.AggregateFunc
+		cursor int
+		err    error
+	)
+	for k, row := range p.Rows {
+		start, end, err1 := p.bounds(k)
+		if err1 != nil {
+			return errors.Trace(err1)
+		}
+
+		if agg == nil || start != 0 || end < cursor {
+			if agg, err = expressions.NewAggregateFunc(call, nil); err != nil {
+				return errors.Trace(err)
+			}
+			cursor = start
+		}
+
+		for ; cursor < end; cursor++ {
+			if err = agg.Update(p.Rows[cursor].Args); err != nil {
+				return errors.Trace(err)
+			}
+		}
+
+		if values[row.Index], err = agg.Result(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// windowAggregateResult is the result of an aggregate function computed by group by,
+// see WindowPlan.evalWindowFields.
+type windowAggregateResult struct {
+	v interface{}
+}
+
+func (a *windowAggregateResult) Update(args []interface{}) error {
+	return errors.Errorf("can not update computed aggregate result")
+}
+
+func (a *windowAggregateResult) Partial() ([]interface{}, error) {
+	return []interface{}{a.v}, nil
+}
+
+func (a *windowAggregateResult) Merge(partial []interface{}) error {
+	return errors.Errorf("can not merge computed aggregate result")
+}
+
+func (a *windowAggregateResult) Result() (interface{}, error) {
+	return a.v, nil
+}
+
+// String implements fmt.Stringer interface. Just for debugging.
+func (a *windowAggregateResult) String() string {
+	return fmt.Sprintf("[windowAggregateResult](%v)", a.v)
+}
@@ -0,0 +1,127 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plans
+
+import (
+	"fmt"
+
+	. "github.com/pingcap/check"
+	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/expression/expressions"
+	"github.com/Dong-Chan/alloydb/field"
+	"github.com/Dong-Chan/alloydb/model"
+)
+
+type testWindowPlan struct{}
+
+var _ = Suite(&testWindowPlan{})
+
+// the rows are `id, <window functions>, grp, val`, grp and val are the hidden fields.
+var windowTestData = []*testRowData{
+	&testRowData{1, []interface{}{1, nil, "a", 10}},
+	&testRowData{2, []interface{}{2, nil, "b", 20}},
+	&testRowData{3, []interface{}{3, nil, "a", 30}},
+	&testRowData{4, []interface{}{4, nil, "b", 40}},
+}
+
+func (t *testWindowPlan) newWindowPlan(c *C, f string, args []expression.Expression, spec *expressions.WindowSpec) *WindowPlan {
+	// the rows are modified by the plan.
+	var rows []*testRowData
+	for _, r := range windowTestData {
+		rows = append(rows, &testRowData{r.id, append([]interface{}{}, r.data...)})
+	}
+
+	wf, err := expressions.NewWindowFunc(f, args, spec)
+	c.Assert(err, IsNil)
+
+	selectList := &SelectList{
+		Fields: []*field.Field{
+			{Expr: &expressions.Ident{CIStr: model.NewCIStr("id")}, Name: "id"},
+			{Expr: wf, Name: wf.String()},
+			{Expr: &expressions.Ident{CIStr: model.NewCIStr("grp")}, Name: "grp"},
+			{Expr: &expressions.Ident{CIStr: model.NewCIStr("val")}, Name: "val"},
+		},
+		ResultFields:      []*field.ResultField{{Name: "id"}, {Name: wf.String()}, {}, {}},
+		HiddenFieldOffset: 2,
+	}
+	return &WindowPlan{
+		SelectList: selectList,
+		Src:        &testTablePlan{rows, []string{"id", wf.String(), "", ""}},
+		Hidden:     map[string]int{"grp": 2, "val": 3},
+	}
+}
+
+func (t *testWindowPlan) TestWindow(c *C) {
+	id := &expressions.Position{N: 1, Name: "id"}
+	grp := &expressions.Position{N: 3, Name: "grp"}
+	val := &expressions.Position{N: 4, Name: "val"}
+	byID := []*expressions.WindowOrderByItem{{Expr: id, Asc: true}}
+
+	cases := []struct {
+		f      string
+		args   []expression.Expression
+		spec   *expressions.WindowSpec
+		expect []string
+	}{
+		{"row_number", nil, &expressions.WindowSpec{PartitionBy: []expression.Expression{grp}, OrderBy: byID},
+			[]string{"1", "1", "2", "2"}},
+		{"rank", nil, &expressions.WindowSpec{OrderBy: []*expressions.WindowOrderByItem{{Expr: grp, Asc: true}}},
+			[]string{"1", "3", "1", "3"}},
+		{"dense_rank", nil, &expressions.WindowSpec{OrderBy: []*expressions.WindowOrderByItem{{Expr: grp, Asc: false}}},
+			[]string{"2", "1", "2", "1"}},
+		{"ntile", []expression.Expression{expressions.Value{3}}, &expressions.WindowSpec{OrderBy: byID},
+			[]string{"1", "1", "2", "3"}},
+		{"lead", []expression.Expression{val, expressions.Value{2}}, &expressions.WindowSpec{OrderBy: byID},
+			[]string{"30", "40", "<nil>", "<nil>"}},
+		{"first_value", []expression.Expression{val}, &expressions.WindowSpec{PartitionBy: []expression.Expression{grp}, OrderBy: byID},
+			[]string{"10", "20", "10", "20"}},
+		{"sum", []expression.Expression{val}, &expressions.WindowSpec{OrderBy: byID, Frame: &expressions.WindowFrame{
+			Unit:  expressions.FrameRows,
+			Start: expressions.FrameBound{Type: expressions.Preceding, Expr: expressions.Value{int64(1)}},
+			End:   expressions.FrameBound{Type: expressions.CurrentRow},
+		}}, []string{"10", "30", "50", "70"}},
+		{"count", []expression.Expression{val}, &expressions.WindowSpec{
+			OrderBy: []*expressions.WindowOrderByItem{{Expr: val, Asc: false}},
+			Frame: &expressions.WindowFrame{
+				Unit:  expressions.FrameRange,
+				Start: expressions.FrameBound{Type: expressions.Preceding, Expr: expressions.Value{int64(10)}},
+				End:   expressions.FrameBound{Type: expressions.Following, Expr: expressions.Value{int64(10)}},
+			}}, []string{"2", "3", "3", "2"}},
+		{"max", []expression.Expression{val}, &expressions.WindowSpec{PartitionBy: []expression.Expression{grp}},
+			[]string{"30", "40", "30", "40"}},
+	}
+
+	for _, ca := range cases {
+		p := t.newWindowPlan(c, ca.f, ca.args, ca.spec)
+		var got []string
+		err := p.Do(nil, func(id interface{}, data []interface{}) (bool, error) {
+			got = append(got, fmt.Sprintf("%v", data[1]))
+			return true, nil
+		})
+		c.Assert(err, IsNil)
+		c.Assert(got, DeepEquals, ca.expect, Commentf("%s", p.Fields[1].Expr))
+	}
+
+	// ntile argument must be positive.
+	p := t.newWindowPlan(c, "ntile", []expression.Expression{expressions.Value{0}}, &expressions.WindowSpec{})
+	err := p.Do(nil, func(id interface{}, data []interface{}) (bool, error) {
+		return true, nil
+	})
+	c.Assert(err, NotNil)
+
+	c.Assert(p.GetFields(), HasLen, 4)
+	_, filtered, err := p.Filter(nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(filtered, IsFalse)
+}
@@ -0,0 +1,514 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alloydb
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/column"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/expression/expressions"
+	"github.com/Dong-Chan/alloydb/infoschema"
+	"github.com/Dong-Chan/alloydb/model"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/privilege/privileges"
+	"github.com/Dong-Chan/alloydb/rset/rsets"
+	"github.com/Dong-Chan/alloydb/sessionctx"
+	"github.com/Dong-Chan/alloydb/sessionctx/db"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/stmt"
+	"github.com/Dong-Chan/alloydb/stmt/stmts"
+	"github.com/Dong-Chan/alloydb/table"
+)
+
+// privRequest is a privilege a statement requires. It is a global privilege if db is empty,
+// a database privilege if table is empty, otherwise a table privilege.
+type privRequest struct {
+	db    string
+	table string
+	priv  mysql.PrivilegeType
+	// columns are the columns the privilege is required on, the privilege can be granted on each of them
+	// instead of the table. If columns is empty, a privilege on any column of the table is enough.
+	columns []string
+	// anyPriv is set if any privilege on the table or its columns is enough, it is required by the SHOW statements.
+	anyPriv bool
+}
+
+// tableRef is a table in the FROM clause, name is the alias or the table name the columns can be qualified with.
+type tableRef struct {
+	ident table.Ident
+	name  string
+}
+
+// privCollector collects the privileges a statement requires.
+type privCollector struct {
+	ctx  context.Context
+	is   infoschema.InfoSchema
+	ctes map[string]bool
+	reqs []*privRequest
+}
+
+// checkPrivileges checks whether the session user has the privileges to execute s.
+// The sessions without a user are not checked.
+func checkPrivileges(ctx context.Context, s stmt.Statement) error {
+	account := variable.GetSessionVars(ctx).User
+	if len(account) == 0 {
+		return nil
+	}
+	i := strings.LastIndex(account, "@")
+	user, host := account[:i], account[i+1:]
+
+	do := sessionctx.GetDomain(ctx)
+	c := &privCollector{ctx: ctx, is: do.InfoSchema(), ctes: map[string]bool{}}
+	if err := c.collect(s); err != nil {
+		return errors.Trace(err)
+	}
+	if len(c.reqs) == 0 {
+		return nil
+	}
+
+	privs, err := do.PrivilegeHandle().Get(ctx, c.is)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, r := range c.reqs {
+		if err = r.verify(privs, user, host); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// verify returns an access denied error if the account user@host doesn't have the privileges of r.
+func (r *privRequest) verify(privs *privileges.MySQLPrivilege, user, host string) error {
+	if r.anyPriv {
+		if !privs.TableVisible(user, host, r.db, r.table) {
+			return mysql.NewDefaultError(mysql.ErTableaccessDeniedError, "SHOW", user, host, r.table)
+		}
+		return nil
+	}
+	for _, p := range r.priv.Privileges() {
+		switch {
+		case len(r.db) == 0:
+			if !privs.RequestVerification(user, host, "", "", p) {
+				return mysql.NewDefaultError(mysql.ErSpecificAccessDeniedError, p.String())
+			}
+		case len(r.table) == 0:
+			if !privs.RequestVerification(user, host, r.db, "", p) {
+				return mysql.NewDefaultError(mysql.ErDbaccessDeniedError, user, host, r.db)
+			}
+		case !privs.ColumnVerification(user, host, r.db, r.table, r.columns, p):
+			// The missing column is reported if the privilege is granted on some columns of the table.
+			if p&mysql.AllColumnPrivs != 0 && privs.ColumnVerification(user, host, r.db, r.table, nil, p) {
+				for _, col := range r.columns {
+					if !privs.ColumnVerification(user, host, r.db, r.table, []string{col}, p) {
+						return mysql.NewDefaultError(mysql.ErColumnaccessDeniedError, p.String(), user, host, col, r.table)
+					}
+				}
+			}
+			return mysql.NewDefaultError(mysql.ErTableaccessDeniedError, p.String(), user, host, r.table)
+		}
+	}
+	return nil
+}
+
+func (c *privCollector) add(db, table string, priv mysql.PrivilegeType, columns []string) {
+	c.reqs = append(c.reqs, &privRequest{db: strings.ToLower(db), table: strings.ToLower(table), priv: priv, columns: columns})
+}
+
+// table returns the table of ident, it is nil if the table doesn't exist. ok is false for the common table
+// expressions and the tables of information_schema, they don't require privileges.
+func (c *privCollector) table(ident table.Ident) (t table.Table, ok bool) {
+	if len(ident.Schema.O) == 0 && c.ctes[ident.Name.L] {
+		return nil, false
+	}
+	full := ident.Full(c.ctx)
+	if strings.EqualFold(full.Schema.O, infoschema.Name) {
+		return nil, false
+	}
+	t, _ = c.is.TableByName(full.Schema, full.Name)
+	return t, true
+}
+
+// addTable adds the privilege priv required on the table of ref, names are the column names the statement
+// mentions. The columns of the table in names are the columns priv is required on, names qualified with
+// other tables are skipped, `*` means all the columns. If onlyColumns is true, priv is required only if
+// the statement mentions some columns of the table.
+func (c *privCollector) addTable(ref *tableRef, priv mysql.PrivilegeType, names []string, onlyColumns bool) {
+	t, ok := c.table(ref.ident)
+	if !ok {
+		return
+	}
+	var cols []*column.Col
+	if t != nil {
+		cols = t.Cols()
+	}
+	var columns []string
+	seen := map[string]bool{}
+	for _, name := range names {
+		parts := strings.Split(strings.ToLower(name), ".")
+		col := parts[len(parts)-1]
+		if len(parts) > 1 && parts[len(parts)-2] != ref.name {
+			continue
+		}
+		var found []string
+		if col == "*" {
+			for _, c := range cols {
+				found = append(found, c.Name.L)
+			}
+		} else if column.FindCol(cols, col) != nil {
+			found = []string{col}
+		}
+		for _, col := range found {
+			if !seen[col] {
+				seen[col] = true
+				columns = append(columns, col)
+			}
+		}
+	}
+	if onlyColumns && len(columns) == 0 {
+		return
+	}
+	full := ref.ident.Full(c.ctx)
+	c.add(full.Schema.O, full.Name.O, priv, columns)
+}
+
+// mentionedNames returns the column names mentioned in exprs, and collects the privileges of the subqueries in them.
+func (c *privCollector) mentionedNames(exprs []expression.Expression) ([]string, error) {
+	var names []string
+	for _, e := range exprs {
+		if e == nil {
+			continue
+		}
+		names = append(names, expressions.MentionedColumns(e)...)
+		for _, s := range expressions.MentionedSubQueries(e) {
+			if err := c.collect(s); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+	}
+	return names, nil
+}
+
+// with makes the common table expressions of w visible to the statement collected in f.
+func (c *privCollector) with(w *stmts.WithClause, f func() error) error {
+	if w == nil {
+		return f()
+	}
+	old := c.ctes
+	c.ctes = make(map[string]bool, len(old)+len(w.CTEs))
+	for name := range old {
+		c.ctes[name] = true
+	}
+	defer func() { c.ctes = old }()
+
+	for _, e := range w.CTEs {
+		if w.Recursive {
+			c.ctes[e.Name.L] = true
+		}
+		if err := c.collect(e.Query); err != nil {
+			return errors.Trace(err)
+		}
+		c.ctes[e.Name.L] = true
+	}
+	return f()
+}
+
+// sources returns the tables in the join, the subqueries in it are collected, the ON conditions are added to exprs.
+func (c *privCollector) sources(node interface{}, refs []*tableRef, exprs *[]expression.Expression) ([]*tableRef, error) {
+	switch x := node.(type) {
+	case *rsets.JoinRset:
+		if x == nil {
+			return refs, nil
+		}
+		if x.On != nil {
+			*exprs = append(*exprs, x.On)
+		}
+		refs, err := c.sources(x.Left, refs, exprs)
+		if err != nil || x.Right == nil {
+			return refs, errors.Trace(err)
+		}
+		return c.sources(x.Right, refs, exprs)
+	case *rsets.TableSource:
+		switch src := x.Source.(type) {
+		case table.Ident:
+			name := x.Name
+			if len(name) == 0 {
+				name = src.Name.O
+			}
+			return append(refs, &tableRef{ident: src, name: strings.ToLower(name)}), nil
+		case stmt.Statement:
+			return refs, errors.Trace(c.collect(src))
+		}
+	}
+	return refs, nil
+}
+
+func (c *privCollector) selectStmt(s *stmts.SelectStmt) error {
+	return c.with(s.With, func() error {
+		var exprs []expression.Expression
+		for _, f := range s.Fields {
+			exprs = append(exprs, f.Expr)
+		}
+		if s.Where != nil {
+			exprs = append(exprs, s.Where.Expr)
+		}
+		if s.GroupBy != nil {
+			exprs = append(exprs, s.GroupBy.By...)
+		}
+		if s.Having != nil {
+			exprs = append(exprs, s.Having.Expr)
+		}
+		if s.OrderBy != nil {
+			for _, item := range s.OrderBy.By {
+				exprs = append(exprs, item.Expr)
+			}
+		}
+		refs, err := c.sources(s.From, nil, &exprs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		names, err := c.mentionedNames(exprs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, ref := range refs {
+			c.addTable(ref, mysql.SelectPriv, names, false)
+		}
+		return nil
+	})
+}
+
+func (c *privCollector) insertStmt(s *stmts.InsertIntoStmt) error {
+	ref := &tableRef{ident: s.TableIdent, name: s.TableIdent.Name.L}
+	var exprs []expression.Expression
+	for _, list := range s.Lists {
+		exprs = append(exprs, list...)
+	}
+	names := s.ColNames
+	for _, a := range s.Setlist {
+		names = append(names, a.ColName)
+		exprs = append(exprs, a.Expr)
+	}
+	if len(names) == 0 {
+		names = []string{"*"}
+	}
+	c.addTable(ref, mysql.InsertPriv, names, false)
+
+	if len(s.OnDuplicate) > 0 {
+		var cols []string
+		for _, a := range s.OnDuplicate {
+			cols = append(cols, a.ColName)
+			exprs = append(exprs, a.Expr)
+		}
+		c.addTable(ref, mysql.UpdatePriv, cols, false)
+	}
+	if _, err := c.mentionedNames(exprs); err != nil {
+		return errors.Trace(err)
+	}
+	if s.Sel != nil {
+		return errors.Trace(c.collect(s.Sel))
+	}
+	return nil
+}
+
+func (c *privCollector) updateStmt(s *stmts.UpdateStmt) error {
+	return c.with(s.With, func() error {
+		ref := &tableRef{ident: s.TableIdent, name: s.TableIdent.Name.L}
+		exprs := []expression.Expression{s.Where}
+		var cols []string
+		for _, a := range s.List {
+			cols = append(cols, a.ColName)
+			exprs = append(exprs, a.Expr)
+		}
+		c.addTable(ref, mysql.UpdatePriv, cols, false)
+
+		names, err := c.mentionedNames(exprs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.addTable(ref, mysql.SelectPriv, names, true)
+		return nil
+	})
+}
+
+func (c *privCollector) deleteStmt(s *stmts.DeleteStmt) error {
+	return c.with(s.With, func() error {
+		exprs := []expression.Expression{s.Where}
+		if !s.MultiTable {
+			ref := &tableRef{ident: s.TableIdent, name: s.TableIdent.Name.L}
+			c.addTable(ref, mysql.DeletePriv, nil, false)
+			names, err := c.mentionedNames(exprs)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			c.addTable(ref, mysql.SelectPriv, names, true)
+			return nil
+		}
+
+		refs, err := c.sources(s.Refs, nil, &exprs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, ident := range s.TableIdents {
+			ref := &tableRef{ident: ident, name: ident.Name.L}
+			// The tables to delete from can be the aliases in the table references.
+			for _, r := range refs {
+				if len(ident.Schema.O) == 0 && r.name == ident.Name.L {
+					ref = r
+					break
+				}
+			}
+			c.addTable(ref, mysql.DeletePriv, nil, false)
+		}
+		names, err := c.mentionedNames(exprs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, ref := range refs {
+			c.addTable(ref, mysql.SelectPriv, names, true)
+		}
+		return nil
+	})
+}
+
+// grantStmt adds the GRANT OPTION privilege and the privileges granted or revoked on the level.
+func (c *privCollector) grantStmt(elems []*stmts.PrivElem, level *stmts.GrantLevel) {
+	dbName, tableName := level.DBName, level.TableName
+	if level.Level != stmts.GrantLevelGlobal && len(dbName) == 0 {
+		dbName = db.GetCurrentSchema(c.ctx)
+	}
+	all := mysql.AllTablePrivs
+	switch {
+	case level.Level == stmts.GrantLevelGlobal || len(dbName) == 0:
+		dbName, tableName, all = "", "", mysql.AllGlobalPrivs
+	case level.Level == stmts.GrantLevelDB:
+		all = mysql.AllDBPrivs
+	}
+	privs := mysql.GrantPriv
+	for _, e := range elems {
+		if e.All {
+			privs |= all
+		} else {
+			privs |= e.Priv
+		}
+	}
+	c.add(dbName, tableName, privs, nil)
+}
+
+// showStmt collects the privileges of s. The statements showing a table require a privilege on it,
+// the tables and columns in the other statements are filtered by the privileges when they are shown.
+func (c *privCollector) showStmt(s *stmts.ShowStmt) {
+	switch s.Target {
+	case stmt.ShowGrants:
+		if len(s.User) > 0 && !strings.EqualFold(s.User, variable.GetSessionVars(c.ctx).User) {
+			c.add(mysql.SystemDB, "", mysql.SelectPriv, nil)
+		}
+	case stmt.ShowCreateTable, stmt.ShowColumns, stmt.ShowIndex:
+		ident := s.TableIdent
+		if len(s.DBName) > 0 {
+			ident.Schema = model.NewCIStr(s.DBName)
+		}
+		if _, ok := c.table(ident); !ok {
+			return
+		}
+		full := ident.Full(c.ctx)
+		c.reqs = append(c.reqs, &privRequest{db: full.Schema.L, table: full.Name.L, anyPriv: true})
+	}
+}
+
+func (c *privCollector) collect(s stmt.Statement) error {
+	switch x := s.(type) {
+	case *stmts.SelectStmt:
+		return errors.Trace(c.selectStmt(x))
+	case *stmts.UnionStmt:
+		return c.with(x.With, func() error {
+			for _, s := range x.Selects {
+				if err := c.selectStmt(s); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			return nil
+		})
+	case *stmts.InsertIntoStmt:
+		return errors.Trace(c.insertStmt(x))
+	case *stmts.UpdateStmt:
+		return errors.Trace(c.updateStmt(x))
+	case *stmts.DeleteStmt:
+		return errors.Trace(c.deleteStmt(x))
+	case *stmts.SetStmt:
+		var exprs []expression.Expression
+		for _, v := range x.Variables {
+			exprs = append(exprs, v.Value)
+			if v.IsGlobal {
+				c.add("", "", mysql.SuperPriv, nil)
+			}
+		}
+		_, err := c.mentionedNames(exprs)
+		return errors.Trace(err)
+	case *stmts.ExplainStmt:
+		return errors.Trace(c.collect(x.S))
+	case *stmts.ExecuteStmt:
+		ps, err := x.Prepared(c.ctx)
+		if err != nil {
+			// The statement fails with the same error.
+			return nil
+		}
+		return errors.Trace(c.collect(ps.SQLStmt))
+	case *stmts.CreateDatabaseStmt:
+		c.add(x.Name, "", mysql.CreatePriv, nil)
+	case *stmts.DropDatabaseStmt:
+		c.add(x.Name, "", mysql.DropPriv, nil)
+	case *stmts.CreateTableStmt:
+		full := x.Ident.Full(c.ctx)
+		c.add(full.Schema.O, full.Name.O, mysql.CreatePriv, nil)
+	case *stmts.DropTableStmt:
+		for _, ident := range x.TableIdents {
+			full := ident.Full(c.ctx)
+			c.add(full.Schema.O, full.Name.O, mysql.DropPriv, nil)
+		}
+	case *stmts.TruncateTableStmt:
+		full := x.TableIdent.Full(c.ctx)
+		c.add(full.Schema.O, full.Name.O, mysql.DropPriv, nil)
+	case *stmts.AlterTableStmt:
+		full := x.Ident.Full(c.ctx)
+		c.add(full.Schema.O, full.Name.O, mysql.AlterPriv, nil)
+	case *stmts.CreateIndexStmt:
+		full := x.TableIdent.Full(c.ctx)
+		c.add(full.Schema.O, full.Name.O, mysql.IndexPriv, nil)
+	case *stmts.DropIndexStmt:
+		if len(x.TableIdent.Name.O) == 0 {
+			// Without ON, the index may be on any table.
+			c.add("", "", mysql.IndexPriv, nil)
+			break
+		}
+		full := x.TableIdent.Full(c.ctx)
+		c.add(full.Schema.O, full.Name.O, mysql.IndexPriv, nil)
+	case *stmts.GrantStmt:
+		c.grantStmt(x.Privs, x.Level)
+	case *stmts.RevokeStmt:
+		c.grantStmt(x.Privs, x.Level)
+	case *stmts.CreateUserStmt, *stmts.AlterUserStmt, *stmts.DropUserStmt:
+		c.add("", "", mysql.CreateUserPriv, nil)
+	case *stmts.SetPwdStmt:
+		if len(x.User) > 0 && !strings.EqualFold(x.User, variable.GetSessionVars(c.ctx).User) {
+			c.add("", "", mysql.CreateUserPriv, nil)
+		}
+	case *stmts.ShowStmt:
+		c.showStmt(x)
+	}
+	return nil
+}
@@ -0,0 +1,380 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/column"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/infoschema"
+	"github.com/Dong-Chan/alloydb/kv"
+	"github.com/Dong-Chan/alloydb/meta"
+	"github.com/Dong-Chan/alloydb/model"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/util/auth"
+	"github.com/Dong-Chan/alloydb/util/types"
+)
+
+// userRecord is a row of mysql.user.
+type userRecord struct {
+	Host  string
+	User  string
+	Privs mysql.PrivilegeType
+}
+
+// dbRecord is a row of mysql.db.
+type dbRecord struct {
+	Host  string
+	DB    string
+	User  string
+	Privs mysql.PrivilegeType
+}
+
+// tablesPrivRecord is a row of mysql.tables_priv.
+type tablesPrivRecord struct {
+	Host       string
+	DB         string
+	User       string
+	TableName  string
+	TablePriv  mysql.PrivilegeType
+	ColumnPriv mysql.PrivilegeType
+}
+
+// columnsPrivRecord is a row of mysql.columns_priv.
+type columnsPrivRecord struct {
+	Host       string
+	DB         string
+	User       string
+	TableName  string
+	ColumnName string
+	ColumnPriv mysql.PrivilegeType
+}
+
+// MySQLPrivilege is the in-memory copy of the privilege tables in the system database.
+// The rows of an account are found by the user name and the host pattern of the account,
+// database, table and column names are case insensitive.
+type MySQLPrivilege struct {
+	User        []userRecord
+	DB          []dbRecord
+	TablesPriv  []tablesPrivRecord
+	ColumnsPriv []columnsPrivRecord
+}
+
+// record is a row of a privilege table, the values are indexed by lower case column names.
+type record map[string]interface{}
+
+func (r record) str(name string) string {
+	s, _ := types.ToString(r[strings.ToLower(name)])
+	return s
+}
+
+// privs returns the privileges in the *_priv columns of mysql.user and mysql.db.
+func (r record) privs() mysql.PrivilegeType {
+	var privs mysql.PrivilegeType
+	for _, p := range (mysql.AllGlobalPrivs | mysql.GrantPriv).Privileges() {
+		if v, ok := r[strings.ToLower(p.Column())]; ok && strings.EqualFold(fmt.Sprint(v), "Y") {
+			privs |= p
+		}
+	}
+	return privs
+}
+
+// loadTable calls fn for every row of the system table name.
+func loadTable(ctx context.Context, is infoschema.InfoSchema, name string, fn func(r record)) error {
+	t, err := is.TableByName(model.NewCIStr(mysql.SystemDB), model.NewCIStr(name))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return t.IterRecords(ctx, t.FirstKey(), t.Cols(), func(h int64, data []interface{}, cols []*column.Col) (bool, error) {
+		r := make(record, len(cols))
+		for i, col := range cols {
+			r[col.Name.L] = data[i]
+		}
+		fn(r)
+		return true, nil
+	})
+}
+
+// LoadAll loads the privilege tables with the transaction of ctx.
+func (p *MySQLPrivilege) LoadAll(ctx context.Context, is infoschema.InfoSchema) error {
+	err := loadTable(ctx, is, mysql.UserTable, func(r record) {
+		p.User = append(p.User, userRecord{Host: r.str("Host"), User: r.str("User"), Privs: r.privs()})
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = loadTable(ctx, is, mysql.DBTable, func(r record) {
+		p.DB = append(p.DB, dbRecord{Host: r.str("Host"), DB: r.str("DB"), User: r.str("User"), Privs: r.privs()})
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = loadTable(ctx, is, mysql.TablePrivTable, func(r record) {
+		p.TablesPriv = append(p.TablesPriv, tablesPrivRecord{
+			Host:       r.str("Host"),
+			DB:         r.str("DB"),
+			User:       r.str("User"),
+			TableName:  r.str("Table_name"),
+			TablePriv:  mysql.PrivilegesFromSetString(r.str("Table_priv")),
+			ColumnPriv: mysql.PrivilegesFromSetString(r.str("Column_priv")),
+		})
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = loadTable(ctx, is, mysql.ColumnPrivTable, func(r record) {
+		p.ColumnsPriv = append(p.ColumnsPriv, columnsPrivRecord{
+			Host:       r.str("Host"),
+			DB:         r.str("DB"),
+			User:       r.str("User"),
+			TableName:  r.str("Table_name"),
+			ColumnName: r.str("Column_name"),
+			ColumnPriv: mysql.PrivilegesFromSetString(r.str("Column_priv")),
+		})
+	})
+	return errors.Trace(err)
+}
+
+// UserExists checks whether the account user@host exists.
+func (p *MySQLPrivilege) UserExists(user, host string) bool {
+	for _, r := range p.User {
+		if r.User == user && strings.EqualFold(r.Host, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// GlobalPrivs returns the privileges of the account user@host granted on *.*.
+func (p *MySQLPrivilege) GlobalPrivs(user, host string) mysql.PrivilegeType {
+	for _, r := range p.User {
+		if r.User == user && strings.EqualFold(r.Host, host) {
+			return r.Privs
+		}
+	}
+	return 0
+}
+
+// DBPrivs returns the privileges of the account user@host granted on db.*.
+func (p *MySQLPrivilege) DBPrivs(user, host, db string) mysql.PrivilegeType {
+	for _, r := range p.DB {
+		if r.User == user && strings.EqualFold(r.Host, host) && strings.EqualFold(r.DB, db) {
+			return r.Privs
+		}
+	}
+	return 0
+}
+
+// TablePrivs returns the privileges of the account user@host granted on db.table.
+func (p *MySQLPrivilege) TablePrivs(user, host, db, table string) mysql.PrivilegeType {
+	for _, r := range p.TablesPriv {
+		if r.User == user && strings.EqualFold(r.Host, host) && strings.EqualFold(r.DB, db) &&
+			strings.EqualFold(r.TableName, table) {
+			return r.TablePriv
+		}
+	}
+	return 0
+}
+
+// ColumnPrivs returns the privileges of the account user@host granted on the column of db.table.
+func (p *MySQLPrivilege) ColumnPrivs(user, host, db, table, column string) mysql.PrivilegeType {
+	for _, r := range p.ColumnsPriv {
+		if r.User == user && strings.EqualFold(r.Host, host) && strings.EqualFold(r.DB, db) &&
+			strings.EqualFold(r.TableName, table) && strings.EqualFold(r.ColumnName, column) {
+			return r.ColumnPriv
+		}
+	}
+	return 0
+}
+
+// anyColumnPrivs returns the union of the privileges of the account user@host granted on the columns of db.table.
+func (p *MySQLPrivilege) anyColumnPrivs(user, host, db, table string) mysql.PrivilegeType {
+	var privs mysql.PrivilegeType
+	for _, r := range p.ColumnsPriv {
+		if r.User == user && strings.EqualFold(r.Host, host) && strings.EqualFold(r.DB, db) &&
+			strings.EqualFold(r.TableName, table) {
+			privs |= r.ColumnPriv
+		}
+	}
+	return privs
+}
+
+// RequestVerification checks whether the account user@host has all the privileges of priv,
+// granted on *.*, db.* or db.table. If table is empty, only the global and database levels are checked.
+func (p *MySQLPrivilege) RequestVerification(user, host, db, table string, priv mysql.PrivilegeType) bool {
+	privs := p.GlobalPrivs(user, host)
+	if len(db) > 0 {
+		privs |= p.DBPrivs(user, host, db)
+	}
+	if len(table) > 0 {
+		privs |= p.TablePrivs(user, host, db, table)
+	}
+	return privs&priv == priv
+}
+
+// ColumnVerification checks whether the account user@host has the privileges of priv on all the
+// columns of db.table, either granted on the table or a higher level, or granted on each column.
+// If columns is empty, a column privilege on any column of the table is enough.
+func (p *MySQLPrivilege) ColumnVerification(user, host, db, table string, columns []string, priv mysql.PrivilegeType) bool {
+	if p.RequestVerification(user, host, db, table, priv) {
+		return true
+	}
+	if priv&^mysql.AllColumnPrivs != 0 {
+		return false
+	}
+	if len(columns) == 0 {
+		return p.anyColumnPrivs(user, host, db, table)&priv == priv
+	}
+	for _, col := range columns {
+		if p.ColumnPrivs(user, host, db, table, col)&priv != priv {
+			return false
+		}
+	}
+	return true
+}
+
+// TableVisible checks whether the account user@host has any privilege on db.table or its columns,
+// the table is shown to it in SHOW statements and information_schema.
+func (p *MySQLPrivilege) TableVisible(user, host, db, table string) bool {
+	privs := p.GlobalPrivs(user, host) | p.DBPrivs(user, host, db) | p.TablePrivs(user, host, db, table) |
+		p.anyColumnPrivs(user, host, db, table)
+	return privs&mysql.AllTablePrivs != 0
+}
+
+// ColumnVisible checks whether the account user@host has any privilege on the column of db.table,
+// either granted on the column or a higher level.
+func (p *MySQLPrivilege) ColumnVisible(user, host, db, table, column string) bool {
+	privs := p.GlobalPrivs(user, host) | p.DBPrivs(user, host, db) | p.TablePrivs(user, host, db, table) |
+		p.ColumnPrivs(user, host, db, table, column)
+	return privs&mysql.AllTablePrivs != 0
+}
+
+// showPrivs formats privs for SHOW GRANTS, all is ALL PRIVILEGES of the level.
+func showPrivs(privs, all mysql.PrivilegeType) string {
+	privs &^= mysql.GrantPriv
+	switch privs {
+	case 0:
+		return "USAGE"
+	case all:
+		return "ALL PRIVILEGES"
+	}
+	return privs.String()
+}
+
+// ShowGrants returns the GRANT statements for the privileges of the account user@host,
+// the global privileges come first, then the database and table privileges.
+func (p *MySQLPrivilege) ShowGrants(user, host string) []string {
+	account := auth.FormatUser(user, host)
+	grant := func(privs string, level string, withGrant bool) string {
+		s := fmt.Sprintf("GRANT %s ON %s TO %s", privs, level, account)
+		if withGrant {
+			s += " WITH GRANT OPTION"
+		}
+		return s
+	}
+
+	privs := p.GlobalPrivs(user, host)
+	grants := []string{grant(showPrivs(privs, mysql.AllGlobalPrivs), "*.*", privs&mysql.GrantPriv != 0)}
+	for _, r := range p.DB {
+		if r.User != user || !strings.EqualFold(r.Host, host) || r.Privs == 0 {
+			continue
+		}
+		level := fmt.Sprintf("`%s`.*", r.DB)
+		grants = append(grants, grant(showPrivs(r.Privs, mysql.AllDBPrivs), level, r.Privs&mysql.GrantPriv != 0))
+	}
+	for _, r := range p.TablesPriv {
+		if r.User != user || !strings.EqualFold(r.Host, host) || r.TablePriv|r.ColumnPriv == 0 {
+			continue
+		}
+		var items []string
+		if r.TablePriv&^mysql.GrantPriv == mysql.AllTablePrivs {
+			items = append(items, "ALL PRIVILEGES")
+		} else if r.TablePriv&^mysql.GrantPriv != 0 {
+			items = append(items, (r.TablePriv &^ mysql.GrantPriv).String())
+		}
+		for _, priv := range r.ColumnPriv.Privileges() {
+			var cols []string
+			for _, c := range p.ColumnsPriv {
+				if c.User == user && strings.EqualFold(c.Host, host) && strings.EqualFold(c.DB, r.DB) &&
+					strings.EqualFold(c.TableName, r.TableName) && c.ColumnPriv&priv != 0 {
+					cols = append(cols, fmt.Sprintf("`%s`", c.ColumnName))
+				}
+			}
+			if len(cols) > 0 {
+				items = append(items, fmt.Sprintf("%s (%s)", priv, strings.Join(cols, ", ")))
+			}
+		}
+		if len(items) == 0 {
+			items = append(items, "USAGE")
+		}
+		level := fmt.Sprintf("`%s`.`%s`", r.DB, r.TableName)
+		grants = append(grants, grant(strings.Join(items, ", "), level, r.TablePriv&mysql.GrantPriv != 0))
+	}
+	return grants
+}
+
+// Handle caches the privileges of a store. The cache is reloaded when the privilege version
+// in meta changes, the statements changing the privilege tables increase the version.
+type Handle struct {
+	store   kv.Storage
+	mu      sync.Mutex
+	version int64
+	priv    *MySQLPrivilege
+}
+
+// NewHandle creates a Handle for store.
+func NewHandle(store kv.Storage) *Handle {
+	return &Handle{store: store}
+}
+
+// snapshotContext reads the privilege tables in its own transaction instead of the transaction of the session.
+type snapshotContext struct {
+	context.Context
+	txn kv.Transaction
+}
+
+// GetTxn implements the context.Context GetTxn interface.
+func (c *snapshotContext) GetTxn(forceNew bool) (kv.Transaction, error) {
+	return c.txn, nil
+}
+
+// Get returns the privileges committed in the store, is is the schema to find the privilege tables.
+// The privileges are read in a new transaction, the changes are seen by all the sessions at once.
+func (h *Handle) Get(ctx context.Context, is infoschema.InfoSchema) (*MySQLPrivilege, error) {
+	txn, err := h.store.Begin()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer txn.Rollback()
+	ver, err := meta.GetPrivilegeVersion(txn)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.priv != nil && h.version == ver {
+		return h.priv, nil
+	}
+	p := &MySQLPrivilege{}
+	if err = p.LoadAll(&snapshotContext{Context: ctx, txn: txn}, is); err != nil {
+		return nil, errors.Trace(err)
+	}
+	h.priv, h.version = p, ver
+	return p, nil
+}
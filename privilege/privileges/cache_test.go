@@ -0,0 +1,139 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testCacheSuite{})
+
+type testCacheSuite struct {
+}
+
+func newTestPrivilege() *MySQLPrivilege {
+	return &MySQLPrivilege{
+		User: []userRecord{
+			{Host: "%", User: "root", Privs: mysql.AllGlobalPrivs | mysql.GrantPriv},
+			{Host: "%", User: "test", Privs: mysql.CreatePriv},
+			{Host: "10.0.0.%", User: "test"},
+		},
+		DB: []dbRecord{
+			{Host: "%", DB: "db1", User: "test", Privs: mysql.SelectPriv | mysql.InsertPriv | mysql.GrantPriv},
+		},
+		TablesPriv: []tablesPrivRecord{
+			{Host: "%", DB: "db2", User: "test", TableName: "t", TablePriv: mysql.DeletePriv, ColumnPriv: mysql.SelectPriv | mysql.UpdatePriv},
+		},
+		ColumnsPriv: []columnsPrivRecord{
+			{Host: "%", DB: "db2", User: "test", TableName: "t", ColumnName: "a", ColumnPriv: mysql.SelectPriv},
+			{Host: "%", DB: "db2", User: "test", TableName: "t", ColumnName: "b", ColumnPriv: mysql.SelectPriv | mysql.UpdatePriv},
+		},
+	}
+}
+
+func (s *testCacheSuite) TestRequestVerification(c *C) {
+	p := newTestPrivilege()
+	tbl := []struct {
+		user  string
+		host  string
+		db    string
+		table string
+		priv  mysql.PrivilegeType
+		ok    bool
+	}{
+		{"root", "%", "", "", mysql.CreateUserPriv, true},
+		{"root", "%", "db1", "t", mysql.DropPriv | mysql.GrantPriv, true},
+		{"test", "%", "", "", mysql.CreatePriv, true},
+		{"test", "%", "", "", mysql.DropPriv, false},
+		{"test", "%", "db1", "", mysql.SelectPriv | mysql.CreatePriv, true},
+		{"test", "%", "DB1", "t", mysql.InsertPriv, true},
+		{"test", "%", "db1", "", mysql.UpdatePriv, false},
+		{"test", "%", "db2", "", mysql.DeletePriv, false},
+		{"test", "%", "db2", "T", mysql.DeletePriv, true},
+		{"test", "%", "db2", "t", mysql.SelectPriv, false},
+		{"test", "10.0.0.%", "db1", "t", mysql.SelectPriv, false},
+		{"nobody", "%", "db1", "t", mysql.SelectPriv, false},
+	}
+	for _, t := range tbl {
+		c.Assert(p.RequestVerification(t.user, t.host, t.db, t.table, t.priv), Equals, t.ok, Commentf("%v", t))
+	}
+}
+
+func (s *testCacheSuite) TestColumnVerification(c *C) {
+	p := newTestPrivilege()
+	tbl := []struct {
+		table   string
+		columns []string
+		priv    mysql.PrivilegeType
+		ok      bool
+	}{
+		{"t", []string{"a", "b"}, mysql.SelectPriv, true},
+		{"t", []string{"A"}, mysql.SelectPriv, true},
+		{"t", []string{"a", "c"}, mysql.SelectPriv, false},
+		{"t", []string{"b"}, mysql.UpdatePriv, true},
+		{"t", []string{"a"}, mysql.UpdatePriv, false},
+		{"t", nil, mysql.UpdatePriv, true},
+		{"t", nil, mysql.InsertPriv, false},
+		{"t", []string{"a"}, mysql.DeletePriv, true},
+		{"t1", nil, mysql.SelectPriv, false},
+	}
+	for _, t := range tbl {
+		c.Assert(p.ColumnVerification("test", "%", "db2", t.table, t.columns, t.priv), Equals, t.ok, Commentf("%v", t))
+	}
+}
+
+func (s *testCacheSuite) TestVisible(c *C) {
+	p := &MySQLPrivilege{
+		User: []userRecord{
+			{Host: "%", User: "test", Privs: mysql.CreateUserPriv},
+		},
+		TablesPriv: []tablesPrivRecord{
+			{Host: "%", DB: "db", User: "test", TableName: "t1", TablePriv: mysql.AlterPriv},
+			{Host: "%", DB: "db", User: "test", TableName: "t2", ColumnPriv: mysql.SelectPriv},
+		},
+		ColumnsPriv: []columnsPrivRecord{
+			{Host: "%", DB: "db", User: "test", TableName: "t2", ColumnName: "a", ColumnPriv: mysql.SelectPriv},
+		},
+	}
+	c.Assert(p.TableVisible("test", "%", "db", "t1"), IsTrue)
+	c.Assert(p.TableVisible("test", "%", "db", "T2"), IsTrue)
+	c.Assert(p.TableVisible("test", "%", "db", "t3"), IsFalse)
+	c.Assert(p.ColumnVisible("test", "%", "db", "t1", "a"), IsTrue)
+	c.Assert(p.ColumnVisible("test", "%", "db", "t2", "A"), IsTrue)
+	c.Assert(p.ColumnVisible("test", "%", "db", "t2", "b"), IsFalse)
+}
+
+func (s *testCacheSuite) TestShowGrants(c *C) {
+	p := newTestPrivilege()
+	c.Assert(p.ShowGrants("root", "%"), DeepEquals, []string{
+		"GRANT ALL PRIVILEGES ON *.* TO 'root'@'%' WITH GRANT OPTION",
+	})
+	c.Assert(p.ShowGrants("test", "%"), DeepEquals, []string{
+		"GRANT CREATE ON *.* TO 'test'@'%'",
+		"GRANT SELECT, INSERT ON `db1`.* TO 'test'@'%' WITH GRANT OPTION",
+		"GRANT DELETE, SELECT (`a`, `b`), UPDATE (`b`) ON `db2`.`t` TO 'test'@'%'",
+	})
+	c.Assert(p.ShowGrants("test", "10.0.0.%"), DeepEquals, []string{
+		"GRANT USAGE ON *.* TO 'test'@'10.0.0.%'",
+	})
+	c.Assert(p.UserExists("test", "10.0.0.%"), IsTrue)
+	c.Assert(p.UserExists("test", "10.0.1.%"), IsFalse)
+}
@@ -21,8 +21,10 @@ import (
 	"github.com/Dong-Chan/alloydb/expression"
 	"github.com/Dong-Chan/alloydb/expression/expressions"
 	"github.com/Dong-Chan/alloydb/field"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
 	"github.com/Dong-Chan/alloydb/plan"
 	"github.com/Dong-Chan/alloydb/plan/plans"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
 )
 
 var (
@@ -130,7 +132,105 @@ func (r *GroupByRset) Plan(ctx context.Context) (plan.Plan, error) {
 		}
 	}
 
-	return &plans.GroupByDefaultPlan{By: r.By, Src: r.Src, SelectList: r.SelectList}, nil
+	if variable.GetSQLMode(ctx).Has(mysql.ModeOnlyFullGroupBy) {
+		if err := r.checkOnlyFullGroupBy(srcFields); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	p := &plans.GroupByDefaultPlan{By: r.By, Src: r.Src, SelectList: r.SelectList}
+	if name := r.groupByColumn(srcFields); name != "" {
+		// if the rows can be read in the order of the group by column,
+		// we can aggregate them group by group.
+		p.Src, p.Streamed = plans.UseIndexOrder(r.Src, name, true)
+	}
+	return p, nil
+}
+
+// checkOnlyFullGroupBy checks the select fields for ONLY_FULL_GROUP_BY sql mode,
+// the columns outside aggregate functions must be group by items.
+// Without group by items, the select fields can't mix aggregate functions and columns.
+func (r *GroupByRset) checkOnlyFullGroupBy(srcFields []*field.ResultField) error {
+	fields := r.SelectList.Fields[0:r.SelectList.HiddenFieldOffset]
+	groupedFields := map[int]bool{}
+	var groupedColumns []string
+	for _, e := range r.By {
+		switch x := e.(type) {
+		case *expressions.Position:
+			groupedFields[x.N-1] = true
+		case *expressions.Ident:
+			groupedColumns = append(groupedColumns, x.L)
+			// group by item may be an alias name, like `select c1 + 1 as a from t group by a`.
+			for _, index := range field.GetFieldIndex(x.L, fields, field.CheckFieldFlag) {
+				groupedFields[index] = true
+			}
+		default:
+			for i, f := range fields {
+				if f.Expr.String() == e.String() {
+					groupedFields[i] = true
+				}
+			}
+		}
+	}
+
+	for index := range groupedFields {
+		if index >= 0 && index < len(fields) {
+			if x, ok := fields[index].Expr.(*expressions.Ident); ok {
+				groupedColumns = append(groupedColumns, x.L)
+			}
+		}
+	}
+
+	for i, f := range fields {
+		if groupedFields[i] {
+			continue
+		}
+
+		for _, name := range expressions.MentionedColumnsOutsideAggregate(f.Expr) {
+			// columns of outer query are constant here.
+			if !field.ContainFieldName(name, srcFields, field.DefaultFieldFlag) {
+				continue
+			}
+
+			if len(r.By) == 0 {
+				return mysql.NewDefaultError(mysql.ErMixOfGroupFuncAndFields)
+			}
+
+			if !containColumn(groupedColumns, name) {
+				return mysql.NewDefaultError(mysql.ErWrongFieldWithGroup, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// containColumn checks whether name is in names, a qualified name like `t.c1` matches `c1`.
+func containColumn(names []string, name string) bool {
+	for _, v := range names {
+		if v == name || strings.HasSuffix(v, "."+name) || strings.HasSuffix(name, "."+v) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupByColumn returns the column name if the group by item is a single column of Src.
+func (r *GroupByRset) groupByColumn(srcFields []*field.ResultField) string {
+	if len(r.By) != 1 {
+		return ""
+	}
+
+	ident, ok := r.By[0].(*expressions.Ident)
+	if !ok {
+		return ""
+	}
+
+	// group by items are evaluated with the Src fields first.
+	if len(field.GetResultFieldIndex(ident.L, srcFields, field.DefaultFieldFlag)) != 1 {
+		return ""
+	}
+	return ident.L
 }
 
 func (r *GroupByRset) String() string {
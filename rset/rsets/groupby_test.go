@@ -14,13 +14,17 @@
 package rsets
 
 import (
+	"github.com/juju/errors"
 	. "github.com/pingcap/check"
 	"github.com/Dong-Chan/alloydb/expression"
 	"github.com/Dong-Chan/alloydb/expression/expressions"
 	"github.com/Dong-Chan/alloydb/field"
 	"github.com/Dong-Chan/alloydb/model"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
 	"github.com/Dong-Chan/alloydb/parser/opcode"
 	"github.com/Dong-Chan/alloydb/plan/plans"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/util/mock"
 )
 
 var _ = Suite(&testGroupByRsetSuite{})
@@ -126,6 +130,69 @@ func (s *testGroupByRsetSuite) TestGroupByRsetPlan(c *C) {
 	c.Assert(err, NotNil)
 }
 
+func (s *testGroupByRsetSuite) TestOnlyFullGroupBy(c *C) {
+	ctx := mock.NewContext()
+	variable.BindSessionVars(ctx)
+	variable.GetSessionVars(ctx).SetSQLMode(mysql.ModeOnlyFullGroupBy)
+
+	newRset := func(by []expression.Expression, exprs ...expression.Expression) *GroupByRset {
+		tblPlan := newTestTablePlan(testData, []string{"id", "name"})
+		fields := make([]*field.Field, len(exprs))
+		for i, e := range exprs {
+			fields[i] = &field.Field{Expr: e, Name: e.String()}
+		}
+		selectList := &plans.SelectList{
+			HiddenFieldOffset: len(fields),
+			ResultFields:      tblPlan.GetFields(),
+			Fields:            fields,
+		}
+		return &GroupByRset{Src: tblPlan, SelectList: selectList, By: by}
+	}
+
+	id := &expressions.Ident{CIStr: model.NewCIStr("id")}
+	name := &expressions.Ident{CIStr: model.NewCIStr("name")}
+	count, err := expressions.NewCall("count", []expression.Expression{id}, false)
+	c.Assert(err, IsNil)
+	plus := expressions.NewBinaryOperation(opcode.Plus, id, expressions.Value{1})
+
+	tbl := []struct {
+		by    []expression.Expression
+		exprs []expression.Expression
+		code  uint16
+	}{
+		// `select name, count(id) from t group by name`
+		{[]expression.Expression{name}, []expression.Expression{name, count}, 0},
+		// `select id + 1, count(id) from t group by id`
+		{[]expression.Expression{id}, []expression.Expression{plus, count}, 0},
+		// `select id + 1 from t group by id + 1`
+		{[]expression.Expression{plus}, []expression.Expression{plus}, 0},
+		// `select id + 1, count(id) from t group by 1`
+		{[]expression.Expression{expressions.Value{int64(1)}}, []expression.Expression{plus, count}, 0},
+		// `select id, name from t group by name`
+		{[]expression.Expression{name}, []expression.Expression{id, name}, mysql.ErWrongFieldWithGroup},
+		// `select id + 1 from t group by name`
+		{[]expression.Expression{name}, []expression.Expression{plus}, mysql.ErWrongFieldWithGroup},
+		// `select name, count(id) from t`
+		{nil, []expression.Expression{name, count}, mysql.ErMixOfGroupFuncAndFields},
+		// `select count(id) from t`
+		{nil, []expression.Expression{count}, 0},
+	}
+
+	for _, t := range tbl {
+		_, err = newRset(t.by, t.exprs...).Plan(ctx)
+		if t.code == 0 {
+			c.Assert(err, IsNil)
+		} else {
+			c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, t.code)
+		}
+	}
+
+	// Without ONLY_FULL_GROUP_BY, `select id, name from t group by name` is valid.
+	variable.GetSessionVars(ctx).SetSQLMode(mysql.ModeNone)
+	_, err = newRset([]expression.Expression{name}, id, name).Plan(ctx)
+	c.Assert(err, IsNil)
+}
+
 func (s *testGroupByRsetSuite) TestGroupByHasAmbiguousField(c *C) {
 	fld := &field.Field{Expr: expressions.Value{Val: 1}}
 
@@ -165,6 +165,18 @@ func (r *JoinRset) buildSourcePlan(ctx context.Context, t *TableSource) (plan.Pl
 	)
 	switch s := t.Source.(type) {
 	case table.Ident:
+		if cte := r.lookupCTE(ctx, s); cte != nil {
+			src = plans.NewCTEPlan(cte)
+			if t.Name == "" {
+				name := strings.ToLower(cte.Name)
+				if r.tableNames[name] {
+					return nil, nil, errors.Errorf("%s: duplicate name %s", r.String(), s)
+				}
+				r.tableNames[name] = true
+			}
+			break
+		}
+
 		fullIdent := s.Full(ctx)
 		tr, err = newTableRset(fullIdent.Schema.O, fullIdent.Name.O)
 		if err != nil {
@@ -217,6 +229,14 @@ func (r *JoinRset) buildSourcePlan(ctx context.Context, t *TableSource) (plan.Pl
 	return p, fields, nil
 }
 
+// lookupCTE returns the CTE referenced by ident, a table qualified with the schema is never a CTE.
+func (r *JoinRset) lookupCTE(ctx context.Context, ident table.Ident) *plans.CTE {
+	if ident.Schema.O != "" {
+		return nil
+	}
+	return plans.LookupCTE(ctx, ident.Name.O)
+}
+
 // Plan gets JoinPlan.
 func (r *JoinRset) Plan(ctx context.Context) (plan.Plan, error) {
 	r.tableNames = make(map[string]bool)
@@ -49,6 +49,22 @@ type LimitRset struct {
 
 // Plan gets LimitDefaultPlan.
 func (r *LimitRset) Plan(ctx context.Context) (plan.Plan, error) {
+	// ORDER BY with LIMIT only needs to keep the first offset + limit rows sorted.
+	src := r.Src
+	var offset uint64
+	if x, ok := src.(*plans.OffsetDefaultPlan); ok {
+		offset = x.Count
+		src = x.Src
+	}
+	if x, ok := src.(*plans.OrderByDefaultPlan); ok && r.Count > 0 {
+		n := r.Count + offset
+		if n < r.Count {
+			// overflow, keep all rows.
+			n = 0
+		}
+		x.Limit = n
+	}
+
 	return &plans.LimitDefaultPlan{Count: r.Count, Src: r.Src, Fields: r.Src.GetFields()}, nil
 }
 
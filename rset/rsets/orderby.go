@@ -152,5 +152,43 @@ func (r *OrderByRset) Plan(ctx context.Context) (plan.Plan, error) {
 		return r.Src, nil
 	}
 
+	// if the rows can be read in the order of an index, no sort is needed.
+	if name, ok := r.orderByColumn(by); ok {
+		if p, ok := plans.UseIndexOrder(r.Src, name, ascs[0]); ok {
+			return p, nil
+		}
+	}
+
 	return &plans.OrderByDefaultPlan{By: by, Ascs: ascs, Src: r.Src, SelectList: r.SelectList}, nil
 }
+
+// orderByColumn returns the column name if the only order by item refers to
+// a select field which is a plain column, e.g. `select c1 as a from t order by a`.
+func (r *OrderByRset) orderByColumn(by []expression.Expression) (string, bool) {
+	if len(by) != 1 || r.SelectList == nil {
+		return "", false
+	}
+
+	var index int
+	switch x := by[0].(type) {
+	case *expressions.Ident:
+		indices := field.GetResultFieldIndex(x.L, r.SelectList.ResultFields, field.CheckFieldFlag)
+		if len(indices) == 0 {
+			return "", false
+		}
+		index = indices[0]
+	case *expressions.Position:
+		index = x.N - 1
+	default:
+		return "", false
+	}
+
+	if index < 0 || index >= len(r.SelectList.Fields) {
+		return "", false
+	}
+	ident, ok := r.SelectList.Fields[index].Expr.(*expressions.Ident)
+	if !ok {
+		return "", false
+	}
+	return ident.L, true
+}
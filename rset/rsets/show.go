@@ -15,9 +15,14 @@ package rsets
 
 import (
 	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/expression/expressions"
+	"github.com/Dong-Chan/alloydb/model"
 	"github.com/Dong-Chan/alloydb/plan"
 	"github.com/Dong-Chan/alloydb/plan/plans"
 	"github.com/Dong-Chan/alloydb/sessionctx/db"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/stmt"
 )
 
 var (
@@ -32,18 +37,42 @@ type ShowRset struct {
 	ColumnName string
 	Flag       int
 	Full       bool
+	User       string
+
+	CountWarnings bool
+	GlobalScope   bool
+	Pattern       *expressions.PatternLike
+	Where         expression.Expression
 }
 
-// Plan gets ShowPlan.
+// Plan gets ShowPlan, the ShowPlan is filtered by FilterDefaultPlan if there is a LIKE or WHERE clause.
 func (r *ShowRset) Plan(ctx context.Context) (plan.Plan, error) {
-	return &plans.ShowPlan{
-		Target:     r.Target,
-		DBName:     r.getDBName(ctx),
-		TableName:  r.TableName,
-		ColumnName: r.ColumnName,
-		Flag:       r.Flag,
-		Full:       r.Full,
-	}, nil
+	p := &plans.ShowPlan{
+		Target:      r.Target,
+		DBName:      r.getDBName(ctx),
+		TableName:   r.TableName,
+		ColumnName:  r.ColumnName,
+		Flag:        r.Flag,
+		Full:        r.Full,
+		User:        r.getUser(ctx),
+		GlobalScope: r.GlobalScope,
+
+		CountWarnings: r.CountWarnings,
+	}
+	switch {
+	case r.Pattern != nil:
+		// The pattern matches the first column, like Database for SHOW DATABASES.
+		name := p.GetFields()[0].Name
+		pattern := &expressions.PatternLike{
+			Expr:    &expressions.Ident{CIStr: model.NewCIStr(name)},
+			Pattern: r.Pattern.Pattern,
+			Not:     r.Pattern.Not,
+		}
+		return &plans.FilterDefaultPlan{Plan: p, Expr: pattern}, nil
+	case r.Where != nil:
+		return &plans.FilterDefaultPlan{Plan: p, Expr: r.Where}, nil
+	}
+	return p, nil
 }
 
 func (r *ShowRset) getDBName(ctx context.Context) string {
@@ -54,3 +83,12 @@ func (r *ShowRset) getDBName(ctx context.Context) string {
 	// if r.DBName is empty, we should use current db name if possible.
 	return db.GetCurrentSchema(ctx)
 }
+
+func (r *ShowRset) getUser(ctx context.Context) string {
+	if r.Target != stmt.ShowGrants || len(r.User) > 0 {
+		return r.User
+	}
+
+	// if r.User is empty, we should use the current user.
+	return variable.GetSessionVars(ctx).User
+}
@@ -0,0 +1,198 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rsets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/expression/expressions"
+	"github.com/Dong-Chan/alloydb/field"
+	"github.com/Dong-Chan/alloydb/model"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/plan"
+	"github.com/Dong-Chan/alloydb/plan/plans"
+)
+
+var (
+	_ plan.Planner = (*WindowRset)(nil)
+)
+
+// WindowRset is record set for window functions in select list and order by clause,
+// Windows are the named windows defined in WINDOW clause.
+type WindowRset struct {
+	Src        plan.Plan
+	SelectList *plans.SelectList
+	Windows    []*expressions.WindowSpec
+
+	// hidden maps the expressions to the indices of hidden fields.
+	hidden map[string]int
+}
+
+// CheckAndUpdateSelectList checks window functions validity and set hidden fields to selectList.
+// The window functions are computed after group by and having, with all the rows,
+// so their arguments, partition by and order by expressions are added to hidden fields,
+// and replaced with position expressions to fetch the values later.
+// The window functions in order by clause are added to hidden fields too.
+func (r *WindowRset) CheckAndUpdateSelectList(selectList *plans.SelectList, orderBy []OrderByItem, tableFields []*field.ResultField) error {
+	windows := make(map[string]*expressions.WindowSpec, len(r.Windows))
+	for _, w := range r.Windows {
+		name := strings.ToLower(w.Name)
+		if _, ok := windows[name]; ok {
+			return errors.Trace(mysql.NewDefaultError(mysql.ErWindowDuplicateName, w.Name))
+		}
+		windows[name] = w
+	}
+
+	for _, w := range r.Windows {
+		if err := w.Resolve(windows); err != nil {
+			return errors.Trace(err)
+		}
+		if err := w.Check(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	for i, v := range orderBy {
+		if !expressions.ContainWindowFunc(v.Expr) {
+			continue
+		}
+
+		name := v.Expr.String()
+		selectList.AddField(&field.Field{Expr: v.Expr, Name: name}, nil)
+		orderBy[i].Expr = &expressions.Position{N: len(selectList.Fields), Name: name}
+	}
+
+	var err error
+	r.hidden = map[string]int{}
+	for _, f := range selectList.Fields {
+		funcs := expressions.MentionedWindowFuncs(f.Expr)
+		if len(funcs) == 0 {
+			continue
+		}
+
+		// window functions can not be used in aggregate functions, like sum(row_number() over ()).
+		for _, call := range expressions.MentionedAggregateFuncs(f.Expr) {
+			if expressions.ContainWindowFunc(call) {
+				return errors.Trace(mysql.NewDefaultError(mysql.ErWindowInvalidWindowFuncUse, funcs[0].F))
+			}
+		}
+
+		for _, wf := range funcs {
+			if err := wf.Spec.Resolve(windows); err != nil {
+				return errors.Trace(err)
+			}
+			if err := wf.Spec.Check(); err != nil {
+				return errors.Trace(err)
+			}
+
+			for i, e := range wf.Args {
+				if wf.Args[i], err = r.hiddenExpr(selectList, e); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			for i, e := range wf.Spec.PartitionBy {
+				if wf.Spec.PartitionBy[i], err = r.hiddenExpr(selectList, e); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			for _, item := range wf.Spec.OrderBy {
+				if item.Expr, err = r.hiddenExpr(selectList, item.Expr); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		}
+
+		// the field will be evaluated again with the window function values, the aggregate functions
+		// and columns out of the window functions are added to hidden fields too, like count(*)
+		// in `count(*) - lag(count(*)) over (order by c1)`, it is computed by group by.
+		for _, call := range expressions.MentionedAggregateFuncs(f.Expr) {
+			if _, err := r.hiddenExpr(selectList, call); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		for _, name := range expressions.MentionedColumns(f.Expr) {
+			if _, ok := r.hidden[name]; !ok {
+				r.addHiddenField(selectList, name, &expressions.Ident{CIStr: model.NewCIStr(name)})
+			}
+		}
+	}
+
+	return nil
+}
+
+// hiddenExpr adds expression e to hidden fields if it is not static,
+// and returns the position expression to fetch its value.
+func (r *WindowRset) hiddenExpr(selectList *plans.SelectList, e expression.Expression) (expression.Expression, error) {
+	// window functions can not be nested.
+	if funcs := expressions.MentionedWindowFuncs(e); len(funcs) > 0 {
+		return nil, errors.Trace(mysql.NewDefaultError(mysql.ErWindowInvalidWindowFuncUse, funcs[0].F))
+	}
+
+	if _, ok := e.(*expressions.Position); ok || e.IsStatic() {
+		return e, nil
+	}
+
+	name := e.String()
+	i, ok := r.hidden[name]
+	if !ok {
+		i = r.addHiddenField(selectList, name, e)
+	}
+	return &expressions.Position{N: i + 1, Name: name}, nil
+}
+
+// addHiddenField adds the hidden field and returns its index. The field has no result field name,
+// so it can not be referenced by name and makes the select fields ambiguous.
+func (r *WindowRset) addHiddenField(selectList *plans.SelectList, name string, e expression.Expression) int {
+	selectList.AddField(&field.Field{Expr: e, Name: name}, &field.ResultField{})
+	r.hidden[name] = len(selectList.Fields) - 1
+	return r.hidden[name]
+}
+
+// CheckNoWindowFunc checks the expressions don't contain window functions,
+// window functions can only be used in select list and order by clause.
+func CheckNoWindowFunc(exprs ...expression.Expression) error {
+	for _, e := range exprs {
+		if e == nil {
+			continue
+		}
+		if funcs := expressions.MentionedWindowFuncs(e); len(funcs) > 0 {
+			return errors.Trace(mysql.NewDefaultError(mysql.ErWindowInvalidWindowFuncUse, funcs[0].F))
+		}
+	}
+	return nil
+}
+
+// Plan gets WindowPlan.
+// If there is no window function in select list, then gets SrcPlan.
+func (r *WindowRset) Plan(ctx context.Context) (plan.Plan, error) {
+	for _, f := range r.SelectList.Fields {
+		if expressions.ContainWindowFunc(f.Expr) {
+			return &plans.WindowPlan{Src: r.Src, SelectList: r.SelectList, Hidden: r.hidden}, nil
+		}
+	}
+	return r.Src, nil
+}
+
+// String implements fmt.Stringer interface.
+func (r *WindowRset) String() string {
+	a := make([]string, len(r.Windows))
+	for i, w := range r.Windows {
+		a[i] = fmt.Sprintf("%s AS %s", w.Name, w)
+	}
+	return strings.Join(a, ", ")
+}
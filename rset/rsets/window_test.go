@@ -0,0 +1,143 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rsets
+
+import (
+	"github.com/juju/errors"
+	. "github.com/pingcap/check"
+	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/expression/expressions"
+	"github.com/Dong-Chan/alloydb/field"
+	"github.com/Dong-Chan/alloydb/model"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/parser/opcode"
+	"github.com/Dong-Chan/alloydb/plan/plans"
+)
+
+var _ = Suite(&testWindowRsetSuite{})
+
+type testWindowRsetSuite struct {
+	r *WindowRset
+}
+
+func (s *testWindowRsetSuite) SetUpSuite(c *C) {
+	names := []string{"id", "name"}
+	tblPlan := newTestTablePlan(testData, names)
+
+	s.r = &WindowRset{Src: tblPlan}
+}
+
+func (s *testWindowRsetSuite) newSelectList(exprs ...expression.Expression) *plans.SelectList {
+	selectList := &plans.SelectList{}
+	for _, e := range exprs {
+		selectList.AddField(&field.Field{Expr: e, Name: e.String()}, nil)
+	}
+	selectList.HiddenFieldOffset = len(selectList.Fields)
+	return selectList
+}
+
+func (s *testWindowRsetSuite) TestWindowRsetCheckAndUpdateSelectList(c *C) {
+	resultFields := s.r.Src.GetFields()
+	id := &expressions.Ident{CIStr: model.NewCIStr("id")}
+	name := &expressions.Ident{CIStr: model.NewCIStr("name")}
+
+	// `select id, row_number() over (w order by id) from t window w as (partition by name)`
+	s.r.Windows = []*expressions.WindowSpec{{Name: "w", PartitionBy: []expression.Expression{name}}}
+	wf, err := expressions.NewWindowFunc("row_number", nil, &expressions.WindowSpec{
+		Ref:     "w",
+		OrderBy: []*expressions.WindowOrderByItem{{Expr: id, Asc: true}},
+	})
+	c.Assert(err, IsNil)
+
+	selectList := s.newSelectList(id, wf)
+	err = s.r.CheckAndUpdateSelectList(selectList, nil, resultFields)
+	c.Assert(err, IsNil)
+	c.Assert(wf.Spec.Ref, Equals, "")
+	c.Assert(selectList.Fields, HasLen, 4)
+	c.Assert(wf.Spec.PartitionBy[0], DeepEquals, &expressions.Position{N: 3, Name: "name"})
+	c.Assert(wf.Spec.OrderBy[0].Expr, DeepEquals, &expressions.Position{N: 4, Name: "id"})
+	// the named window is not changed.
+	c.Assert(s.r.Windows[0].PartitionBy[0], Equals, name)
+
+	// the hidden fields can not be referenced by name.
+	c.Assert(field.CheckAmbiguousField("id", selectList.ResultFields, field.DefaultFieldFlag), IsNil)
+
+	// `select id from t order by sum(id) over ()`
+	wf, err = expressions.NewWindowFunc("sum", []expression.Expression{id}, &expressions.WindowSpec{})
+	c.Assert(err, IsNil)
+
+	selectList = s.newSelectList(id)
+	orderBy := []OrderByItem{{Expr: wf, Asc: true}}
+	s.r.Windows = nil
+	err = s.r.CheckAndUpdateSelectList(selectList, orderBy, resultFields)
+	c.Assert(err, IsNil)
+	c.Assert(orderBy[0].Expr, DeepEquals, &expressions.Position{N: 2, Name: wf.String()})
+	c.Assert(selectList.Fields, HasLen, 3)
+
+	// `select row_number() over w from t`
+	wf, err = expressions.NewWindowFunc("row_number", nil, &expressions.WindowSpec{Ref: "w"})
+	c.Assert(err, IsNil)
+
+	err = s.r.CheckAndUpdateSelectList(s.newSelectList(wf), nil, resultFields)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErWindowNoSuchWindow))
+
+	// `select id from t window w as (), w as ()`
+	s.r.Windows = []*expressions.WindowSpec{{Name: "w"}, {Name: "W"}}
+	err = s.r.CheckAndUpdateSelectList(s.newSelectList(id), nil, resultFields)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErWindowDuplicateName))
+
+	// `select row_number() over (order by rank() over ()) from t`
+	rank, err := expressions.NewWindowFunc("rank", nil, &expressions.WindowSpec{})
+	c.Assert(err, IsNil)
+	wf, err = expressions.NewWindowFunc("row_number", nil, &expressions.WindowSpec{
+		OrderBy: []*expressions.WindowOrderByItem{{Expr: rank, Asc: true}},
+	})
+	c.Assert(err, IsNil)
+
+	s.r.Windows = nil
+	err = s.r.CheckAndUpdateSelectList(s.newSelectList(wf), nil, resultFields)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErWindowInvalidWindowFuncUse))
+}
+
+func (s *testWindowRsetSuite) TestCheckNoWindowFunc(c *C) {
+	wf, err := expressions.NewWindowFunc("row_number", nil, &expressions.WindowSpec{})
+	c.Assert(err, IsNil)
+
+	c.Assert(CheckNoWindowFunc(nil, expressions.Value{1}), IsNil)
+
+	err = CheckNoWindowFunc(expressions.NewBinaryOperation(opcode.GT, wf, expressions.Value{1}))
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErWindowInvalidWindowFuncUse))
+}
+
+func (s *testWindowRsetSuite) TestWindowRsetPlan(c *C) {
+	id := &expressions.Ident{CIStr: model.NewCIStr("id")}
+	s.r.SelectList = s.newSelectList(id)
+	p, err := s.r.Plan(nil)
+	c.Assert(err, IsNil)
+	c.Assert(p, Equals, s.r.Src)
+
+	wf, err := expressions.NewWindowFunc("row_number", nil, &expressions.WindowSpec{})
+	c.Assert(err, IsNil)
+	s.r.SelectList = s.newSelectList(id, wf)
+	p, err = s.r.Plan(nil)
+	c.Assert(err, IsNil)
+
+	_, ok := p.(*plans.WindowPlan)
+	c.Assert(ok, IsTrue)
+}
+
+func (s *testWindowRsetSuite) TestWindowRsetString(c *C) {
+	s.r.Windows = []*expressions.WindowSpec{{Name: "w", PartitionBy: []expression.Expression{expressions.Value{1}}}}
+	c.Assert(s.r.String(), Equals, "w AS (PARTITION BY 1)")
+}
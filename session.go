@@ -15,11 +15,15 @@ package alloydb
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/juju/errors"
 	"github.com/ngaut/log"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/domain"
 	"github.com/Dong-Chan/alloydb/field"
 	"github.com/Dong-Chan/alloydb/kv"
 	mysql "github.com/Dong-Chan/alloydb/mysqldef"
@@ -27,6 +31,10 @@ import (
 	"github.com/Dong-Chan/alloydb/sessionctx"
 	"github.com/Dong-Chan/alloydb/sessionctx/db"
 	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/stmt"
+	"github.com/Dong-Chan/alloydb/stmt/stmts"
+	"github.com/Dong-Chan/alloydb/util/auth"
+	"github.com/Dong-Chan/alloydb/util/sqlexec"
 )
 
 // Session context
@@ -44,11 +52,15 @@ type Session interface {
 	ExecutePreparedStmt(stmtID uint32, param ...interface{}) (rset.Recordset, error)
 	DropPreparedStmt(stmtID uint32) error
 	SetClientCapability(uint32) // Set client capability flags
+	// Auth checks the scrambled password a client sent for the account user@host
+	// in the handshake with salt, and makes the matched account the session user.
+	Auth(user, host string, scramble, salt []byte) error
 	Close() error
 }
 
 var (
-	_         Session = (*session)(nil)
+	_         Session                       = (*session)(nil)
+	_         sqlexec.RestrictedSQLExecutor = (*session)(nil)
 	sessionID int64
 )
 
@@ -60,6 +72,146 @@ type session struct {
 	values map[fmt.Stringer]interface{}
 	store  kv.Storage
 	sid    int64
+
+	// vars is the session variables bound to s, it is kept for Kill which is called from other sessions.
+	vars *variable.SessionVars
+	// connKilled is set by KILL CONNECTION, it is accessed atomically.
+	connKilled uint32
+	// stmtTimer interrupts the last SELECT statement when max_execution_time is exceeded.
+	stmtTimer *time.Timer
+	// txnStart is when the last transaction began, it is written to the slow log.
+	txnStart time.Time
+
+	// mu protects the process state, it is read by other sessions for the process list and KILL.
+	mu struct {
+		sync.Mutex
+		db       string
+		command  string
+		start    time.Time
+		stmtText string
+		// running is set while s runs a statement or fetches its rows.
+		running bool
+	}
+}
+
+// ProcessInfo implements the domain.Process ProcessInfo interface.
+func (s *session) ProcessInfo() *domain.ProcessInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info := &domain.ProcessInfo{
+		ID:      s.sid,
+		DB:      s.mu.db,
+		Command: s.mu.command,
+		Time:    s.mu.start,
+		Info:    s.mu.stmtText,
+	}
+	if i := strings.LastIndex(s.userName, "@"); i >= 0 {
+		info.User, info.Host = s.userName[:i], s.userName[i+1:]
+	}
+	if info.Command == "Query" {
+		info.State = "executing"
+	}
+	return info
+}
+
+// setProcessInfo sets the state of s for the process list, the statement text is empty if s is idle.
+func (s *session) setProcessInfo(stmtText string) {
+	command := "Query"
+	if len(stmtText) == 0 {
+		command = "Sleep"
+	}
+	dbName := db.GetCurrentSchema(s)
+	s.mu.Lock()
+	s.mu.db = dbName
+	s.mu.command = command
+	s.mu.start = time.Now()
+	s.mu.stmtText = stmtText
+	s.mu.Unlock()
+}
+
+// Kill implements the domain.Process Kill interface.
+// KILL CONNECTION rolls back the transaction of s, at once if s is idle or when its running statement
+// is interrupted, and no more statements of s can be executed.
+func (s *session) Kill(query bool) {
+	if query {
+		atomic.StoreUint32(&s.vars.Killed, variable.KilledByQuery)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	atomic.StoreUint32(&s.connKilled, 1)
+	atomic.StoreUint32(&s.vars.Killed, variable.KilledByQuery)
+	if !s.mu.running {
+		s.rollbackKilled()
+	}
+}
+
+// rollbackKilled rolls back the transaction of s killed by KILL CONNECTION, s.mu must be held.
+func (s *session) rollbackKilled() {
+	if err := s.FinishTxn(true); err != nil {
+		log.Warnf("session %d rollback killed transaction error: %v", s.sid, err)
+	}
+}
+
+// checkConnKilled returns an error if s is killed by KILL CONNECTION, no more statements can be executed.
+func (s *session) checkConnKilled() error {
+	if atomic.LoadUint32(&s.connKilled) == 0 {
+		return nil
+	}
+	return mysql.NewDefaultError(mysql.ErQueryInterrupted)
+}
+
+// startStmt starts a new statement st of s, it clears the kill flag of the last statement and starts
+// the timer of max_execution_time if st is a SELECT statement. The timer runs until the statement
+// is finished, which may be after its rows are fetched, or until the next statement starts.
+func (s *session) startStmt(st stmt.Statement) *time.Timer {
+	if s.stmtTimer != nil {
+		s.stmtTimer.Stop()
+		s.stmtTimer = nil
+	}
+	s.mu.Lock()
+	if atomic.LoadUint32(&s.connKilled) == 0 {
+		atomic.StoreUint32(&s.vars.Killed, variable.NotKilled)
+	}
+	s.mu.Unlock()
+
+	switch st.(type) {
+	case *stmts.SelectStmt, *stmts.UnionStmt:
+	default:
+		return nil
+	}
+	if d := variable.GetMaxExecutionTime(s); d > 0 {
+		vars := s.vars
+		s.stmtTimer = time.AfterFunc(d, func() {
+			atomic.CompareAndSwapUint32(&vars.Killed, variable.NotKilled, variable.KilledByTimeout)
+		})
+	}
+	return s.stmtTimer
+}
+
+// beginStmt marks s as running the statement while it is executed or its rows are fetched,
+// it fails if s is killed by KILL CONNECTION.
+func (s *session) beginStmt(stmtText string) error {
+	s.mu.Lock()
+	if atomic.LoadUint32(&s.connKilled) != 0 {
+		s.mu.Unlock()
+		return mysql.NewDefaultError(mysql.ErQueryInterrupted)
+	}
+	s.mu.running = true
+	s.mu.Unlock()
+	s.setProcessInfo(stmtText)
+	return nil
+}
+
+// endStmt marks s as idle, the transaction is rolled back if s is killed by KILL CONNECTION meanwhile.
+func (s *session) endStmt() {
+	s.mu.Lock()
+	s.mu.running = false
+	if atomic.LoadUint32(&s.connKilled) != 0 {
+		s.rollbackKilled()
+	}
+	s.mu.Unlock()
+	s.setProcessInfo("")
 }
 
 func (s *session) Status() uint16 {
@@ -117,29 +269,228 @@ func (s *session) String() string {
 }
 
 func (s *session) Execute(sql string) ([]rset.Recordset, error) {
-	stmts, err := Compile(sql)
+	stmts, err := CompileWithSQLMode(sql, variable.GetSQLMode(s))
 	if err != nil {
 		log.Errorf("Compile sql error: %s - %s", sql, err)
 		return nil, errors.Trace(err)
 	}
 
-	var rs []rset.Recordset
+	if err = s.checkConnKilled(); err != nil {
+		return nil, errors.Trace(err)
+	}
 
+	var rs []rset.Recordset
 	for _, si := range stmts {
-		r, err := runStmt(s, si)
+		e := s.newStmtExec(si.OriginText(), si)
+		var r rset.Recordset
+		if err = s.beginStmt(si.OriginText()); err == nil {
+			r, err = runStmt(s, si)
+			s.endStmt()
+		}
+		e.run(r, err)
 		if err != nil {
 			log.Warnf("session:%v, err:%v", s, err)
 			return nil, errors.Trace(err)
 		}
 
 		if r != nil {
-			rs = append(rs, r)
+			rs = append(rs, &processRecordset{Recordset: r, s: s, stmtText: si.OriginText(), exec: e})
 		}
 	}
 
 	return rs, nil
 }
 
+// processRecordset marks the session as running the statement while the rows are fetched,
+// because the statement is executed lazily when its rows are fetched. The execution of the
+// statement is finished when the rows are fetched for the first time.
+type processRecordset struct {
+	rset.Recordset
+	s        *session
+	stmtText string
+	exec     *stmtExec
+}
+
+func (r *processRecordset) Do(f func(data []interface{}) (more bool, err error)) error {
+	start := time.Now()
+	if err := r.s.beginStmt(r.stmtText); err != nil {
+		r.exec.fetch(start, 0, err)
+		return errors.Trace(err)
+	}
+	defer r.s.endStmt()
+	var rows uint64
+	err := r.Recordset.Do(func(data []interface{}) (bool, error) {
+		rows++
+		return f(data)
+	})
+	r.exec.fetch(start, rows, err)
+	return err
+}
+
+func (r *processRecordset) FirstRow() (row []interface{}, err error) {
+	start := time.Now()
+	if err = r.s.beginStmt(r.stmtText); err != nil {
+		r.exec.fetch(start, 0, err)
+		return nil, errors.Trace(err)
+	}
+	defer r.s.endStmt()
+	row, err = r.Recordset.FirstRow()
+	var rows uint64
+	if row != nil {
+		rows = 1
+	}
+	r.exec.fetch(start, rows, err)
+	return row, err
+}
+
+func (r *processRecordset) Rows(limit, offset int) (rows [][]interface{}, err error) {
+	start := time.Now()
+	if err = r.s.beginStmt(r.stmtText); err != nil {
+		r.exec.fetch(start, 0, err)
+		return nil, errors.Trace(err)
+	}
+	defer r.s.endStmt()
+	rows, err = r.Recordset.Rows(limit, offset)
+	r.exec.fetch(start, uint64(len(rows)), err)
+	return rows, err
+}
+
+// processStats provides the status variables of the sessions in the domain.
+type processStats struct{}
+
+// Stats implements the variable.Statistics Stats interface.
+func (p processStats) Stats(ctx context.Context) (map[string]*variable.StatusVal, error) {
+	threads := int64(len(sessionctx.GetDomain(ctx).ProcessList()))
+	return map[string]*variable.StatusVal{
+		"Threads_connected": {variable.ScopeGlobal, threads},
+	}, nil
+}
+
+// ExecRestrictedSQL implements the sqlexec.RestrictedSQLExecutor interface.
+func (s *session) ExecRestrictedSQL(ctx context.Context, sql string) (rset.Recordset, error) {
+	stmts, err := Compile(sql)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(stmts) != 1 {
+		return nil, errors.Errorf("restricted sql should have only one statement: %s", sql)
+	}
+	rs, err := stmts[0].Exec(ctx)
+	return rs, errors.Trace(err)
+}
+
+func (s *session) Auth(user, host string, scramble, salt []byte) error {
+	pwd, account, err := s.getPassword(user, host)
+	// The account table is only read, don't keep the transaction.
+	s.FinishTxn(true)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(account) == 0 || !auth.CheckScrambledPassword(salt, pwd, scramble) {
+		usingPwd := "NO"
+		if len(scramble) > 0 {
+			usingPwd = "YES"
+		}
+		return mysql.NewDefaultError(mysql.ErAccessDeniedError, user, host, usingPwd)
+	}
+
+	s.userName = user + "@" + host
+	variable.GetSessionVars(s).User = account
+	return nil
+}
+
+// GetAllSysVars implements the variable.GlobalVarAccessor GetAllSysVars interface.
+func (s *session) GetAllSysVars(ctx context.Context) (map[string]string, error) {
+	rs, err := s.ExecRestrictedSQL(ctx, `SELECT VARIABLE_NAME, VARIABLE_VALUE FROM mysql.global_variables`)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	values := make(map[string]string)
+	err = rs.Do(func(data []interface{}) (bool, error) {
+		name, _ := data[0].(string)
+		value, _ := data[1].(string)
+		values[name] = value
+		return true, nil
+	})
+	return values, errors.Trace(err)
+}
+
+// GetGlobalSysVar implements the variable.GlobalVarAccessor GetGlobalSysVar interface.
+func (s *session) GetGlobalSysVar(ctx context.Context, name string) (string, error) {
+	rs, err := s.ExecRestrictedSQL(ctx, fmt.Sprintf(`SELECT VARIABLE_VALUE FROM mysql.global_variables WHERE VARIABLE_NAME = %q`, name))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	row, err := rs.FirstRow()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if row == nil {
+		return variable.GetSysVar(name).Value, nil
+	}
+	value, _ := row[0].(string)
+	return value, nil
+}
+
+// SetGlobalSysVar implements the variable.GlobalVarAccessor SetGlobalSysVar interface.
+func (s *session) SetGlobalSysVar(ctx context.Context, name string, value string) error {
+	_, err := s.ExecRestrictedSQL(ctx, fmt.Sprintf(`INSERT INTO mysql.global_variables VALUES (%q, %q) ON DUPLICATE KEY UPDATE VARIABLE_VALUE = %q`,
+		name, value, value))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if sysVar := variable.GetSysVar(name); sysVar != nil && sysVar.Scope&variable.ScopeSession == 0 {
+		s.vars.Globals[name] = value
+	}
+	return nil
+}
+
+// loadGlobalVars sets the session values of the system variables to their global values.
+func (s *session) loadGlobalVars() error {
+	values, err := s.GetAllSysVars(s)
+	// The global variables are only read, don't keep the transaction.
+	s.FinishTxn(true)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	vars := variable.GetSessionVars(s)
+	for name, value := range values {
+		sysVar := variable.GetSysVar(name)
+		if sysVar == nil {
+			continue
+		}
+		if sysVar.Scope&variable.ScopeSession == 0 {
+			vars.Globals[name] = value
+			continue
+		}
+		if err = vars.SetSystemVar(name, value); err != nil {
+			log.Warnf("invalid global value %q of system variable %s: %v", value, name, err)
+		}
+	}
+	return nil
+}
+
+// getPassword finds the most specific account in mysql.user that the client user@host
+// can be authenticated as, it returns the password hash and the account in the user@host form.
+// The account is empty if no account matches.
+func (s *session) getPassword(user, host string) (pwd string, account string, err error) {
+	rs, err := s.ExecRestrictedSQL(s, fmt.Sprintf(`SELECT Host, Password FROM mysql.user WHERE User = %q`, user))
+	if err != nil {
+		return "", "", errors.Trace(err)
+	}
+	rank := -1
+	err = rs.Do(func(data []interface{}) (bool, error) {
+		pattern, _ := data[0].(string)
+		if r := auth.HostPatternRank(pattern); r > rank && auth.MatchHost(pattern, host) {
+			rank = r
+			pwd, _ = data[1].(string)
+			account = user + "@" + pattern
+		}
+		return true, nil
+	})
+	return pwd, account, errors.Trace(err)
+}
+
 // For execute prepare statement in binary protocol
 func (s *session) PrepareStmt(sql string) (stmtID uint32, paramCount int, fields []*field.ResultField, err error) {
 	return prepareStmt(s, sql)
@@ -165,12 +516,31 @@ func (s *session) ExecutePreparedStmt(stmtID uint32, args ...interface{}) (rset.
 	if err != nil {
 		return nil, err
 	}
+	if err = s.checkConnKilled(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var (
+		stmtText string
+		st       stmt.Statement
+	)
+	if ps, err := (&stmts.ExecuteStmt{ID: stmtID}).Prepared(s); err == nil {
+		stmtText, st = ps.SQLText, ps.SQLStmt
+	}
 	//convert args to param
-	rs, err := executePreparedStmt(s, stmtID, args...)
+	e := s.newStmtExec(stmtText, st)
+	var rs rset.Recordset
+	if err = s.beginStmt(stmtText); err == nil {
+		rs, err = executePreparedStmt(s, stmtID, args...)
+		s.endStmt()
+	}
+	e.run(rs, err)
 	if err != nil {
 		return nil, err
 	}
-	return rs, nil
+	if rs == nil {
+		return nil, nil
+	}
+	return &processRecordset{Recordset: rs, s: s, stmtText: stmtText, exec: e}, nil
 }
 
 func (s *session) DropPreparedStmt(stmtID uint32) error {
@@ -183,7 +553,7 @@ func (s *session) DropPreparedStmt(stmtID uint32) error {
 func (s *session) GetTxn(forceNew bool) (kv.Transaction, error) {
 	var err error
 	if s.txn == nil {
-		s.txn, err = s.store.Begin()
+		s.txn, err = s.beginTxn()
 		if err != nil {
 			return nil, err
 		}
@@ -196,7 +566,7 @@ func (s *session) GetTxn(forceNew bool) (kv.Transaction, error) {
 		if err != nil {
 			return nil, err
 		}
-		s.txn, err = s.store.Begin()
+		s.txn, err = s.beginTxn()
 		if err != nil {
 			return nil, err
 		}
@@ -205,6 +575,55 @@ func (s *session) GetTxn(forceNew bool) (kv.Transaction, error) {
 	return s.txn, nil
 }
 
+// beginTxn begins a transaction of the store, the reads of the transaction are counted in the session variables.
+func (s *session) beginTxn() (kv.Transaction, error) {
+	txn, err := s.store.Begin()
+	if err != nil {
+		return nil, err
+	}
+	s.txnStart = time.Now()
+	return &readCountTxn{Transaction: txn, vars: s.vars}, nil
+}
+
+// readCountTxn counts the reads of the transaction in vars.KVReads.
+type readCountTxn struct {
+	kv.Transaction
+	vars *variable.SessionVars
+}
+
+// Get implements the kv.Transaction Get interface.
+func (txn *readCountTxn) Get(k []byte) ([]byte, error) {
+	txn.vars.KVReads++
+	return txn.Transaction.Get(k)
+}
+
+// Seek implements the kv.Transaction Seek interface.
+func (txn *readCountTxn) Seek(k []byte, fnKeyCmp func(key []byte) bool) (kv.Iterator, error) {
+	txn.vars.KVReads++
+	it, err := txn.Transaction.Seek(k, fnKeyCmp)
+	if err != nil {
+		return nil, err
+	}
+	return &readCountIter{Iterator: it, vars: txn.vars}, nil
+}
+
+// readCountIter counts the steps of the iterator in vars.KVReads.
+type readCountIter struct {
+	kv.Iterator
+	vars *variable.SessionVars
+}
+
+// Next implements the kv.Iterator Next interface.
+func (it *readCountIter) Next(fn kv.FnKeyCmp) (kv.Iterator, error) {
+	it.vars.KVReads++
+	next, err := it.Iterator.Next(fn)
+	if err != nil {
+		return nil, err
+	}
+	it.Iterator = next
+	return it, nil
+}
+
 func (s *session) SetValue(key fmt.Stringer, value interface{}) {
 	s.values[key] = value
 }
@@ -220,6 +639,9 @@ func (s *session) ClearValue(key fmt.Stringer) {
 
 // Close function does some clean work when session end.
 func (s *session) Close() error {
+	sessionctx.GetDomain(s).RemoveProcess(s.sid)
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.FinishTxn(true)
 }
 
@@ -237,6 +659,18 @@ func CreateSession(store kv.Storage) (Session, error) {
 	sessionctx.BindDomain(s, domain)
 
 	variable.BindSessionVars(s)
-	variable.GetSessionVars(s).SetStatus(mysql.ServerStatusAutocommit)
+	s.vars = variable.GetSessionVars(s)
+	s.vars.SetStatus(mysql.ServerStatusAutocommit)
+
+	variable.BindGlobalVarAccessor(s, s)
+
+	if err = bootstrap(s); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = s.loadGlobalVars(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	s.setProcessInfo("")
+	domain.AddProcess(s.sid, s)
 	return s, nil
 }
@@ -0,0 +1,74 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+import (
+	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/context"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+)
+
+// GlobalVarAccessor reads and writes the global system variables, they are stored in the
+// system database so that they are kept after restart and shared by all the servers.
+type GlobalVarAccessor interface {
+	// GetAllSysVars gets the global values of all the system variables in the store.
+	GetAllSysVars(ctx context.Context) (map[string]string, error)
+	// GetGlobalSysVar gets the global value of the system variable name,
+	// it is the default value if the variable is not in the store.
+	GetGlobalSysVar(ctx context.Context, name string) (string, error)
+	// SetGlobalSysVar sets the global value of the system variable name.
+	SetGlobalSysVar(ctx context.Context, name string, value string) error
+}
+
+// globalVarAccessorKeyType is a dummy type to avoid naming collision in context.
+type globalVarAccessorKeyType int
+
+// String defines a Stringer function for debugging and pretty printing.
+func (k globalVarAccessorKeyType) String() string {
+	return "global_var_accessor"
+}
+
+const accessorKey globalVarAccessorKeyType = 0
+
+// BindGlobalVarAccessor binds global var accessor to context.
+func BindGlobalVarAccessor(ctx context.Context, accessor GlobalVarAccessor) {
+	ctx.SetValue(accessorKey, accessor)
+}
+
+// GetGlobalVarAccessor gets accessor from ctx, it is nil if no accessor is bound.
+func GetGlobalVarAccessor(ctx context.Context) GlobalVarAccessor {
+	v, ok := ctx.Value(accessorKey).(GlobalVarAccessor)
+	if !ok {
+		return nil
+	}
+	return v
+}
+
+// GetGlobalSysVar gets the global value of the system variable name for the session bound to ctx.
+// The default value is used if no accessor is bound to ctx.
+func GetGlobalSysVar(ctx context.Context, name string) (string, error) {
+	sysVar := GetSysVar(name)
+	if sysVar == nil {
+		return "", mysql.NewDefaultError(mysql.ErUnknownSystemVariable, name)
+	}
+	if sysVar.Scope == ScopeNone || ctx == nil {
+		return sysVar.Value, nil
+	}
+	accessor := GetGlobalVarAccessor(ctx)
+	if accessor == nil {
+		return sysVar.Value, nil
+	}
+	value, err := accessor.GetGlobalSysVar(ctx, sysVar.Name)
+	return value, errors.Trace(err)
+}
@@ -14,6 +14,12 @@
 package variable
 
 import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/errors"
 	"github.com/Dong-Chan/alloydb/context"
 	mysql "github.com/Dong-Chan/alloydb/mysqldef"
 	"github.com/Dong-Chan/alloydb/stmt"
@@ -25,6 +31,9 @@ type SessionVars struct {
 	Users map[string]string
 	// system variables
 	Systems map[string]string
+	// Globals is the values of the global only system variables, they are loaded when the session
+	// is created and changed by SET GLOBAL of the session.
+	Globals map[string]string
 	// prepared statement
 	PreparedStmts map[string]stmt.Statement
 	// prepared statement auto increament id
@@ -38,11 +47,92 @@ type SessionVars struct {
 	// Client Capability
 	ClientCapability uint32 // Client capability
 
+	// User is the account of the current session in the user@host form, it is
+	// the mysql.user row matched when the session is authenticated.
+	User string
+
 	// Disable autocommit
 	DisableAutocommit bool
 
 	// Found rows
 	FoundRows uint64
+
+	// SQLMode is the parsed sql_mode of current session, it is valid only if
+	// sql_mode is set in Systems.
+	SQLMode mysql.SQLMode
+
+	// TimeZone is the location of time_zone of current session, it is valid only if
+	// time_zone is set in Systems.
+	TimeZone *time.Location
+
+	// IgnoreErrors is set while executing INSERT IGNORE or UPDATE IGNORE,
+	// the errors which can be ignored are turned into warnings.
+	IgnoreErrors bool
+
+	// Killed is the reason the running statement is interrupted, it is set by KILL and by the timer
+	// of max_execution_time from other goroutines, so it is accessed atomically.
+	Killed uint32
+
+	// KVReads is the number of the reads of the KV store by the session, the Get and Seek
+	// calls and the iterator steps are counted. EXPLAIN ANALYZE reports the reads of each plan.
+	KVReads uint64
+
+	// RowsExamined is the number of the rows read from the tables by the session.
+	RowsExamined uint64
+
+	// warnings of the last statement
+	stmtWarnings statementWarnings
+}
+
+// The values of SessionVars.Killed.
+const (
+	// NotKilled means the statement is not interrupted.
+	NotKilled uint32 = iota
+	// KilledByQuery means the statement is interrupted by KILL QUERY or KILL CONNECTION.
+	KilledByQuery
+	// KilledByTimeout means the statement runs longer than max_execution_time.
+	KilledByTimeout
+)
+
+// CheckKilled returns an error if the running statement of the session bound to ctx is interrupted.
+// The plans check it for every row, so the statement stops soon after it is killed.
+func CheckKilled(ctx context.Context) error {
+	if ctx == nil {
+		return nil
+	}
+	vars := GetSessionVars(ctx)
+	if vars == nil {
+		return nil
+	}
+	switch atomic.LoadUint32(&vars.Killed) {
+	case KilledByQuery:
+		return mysql.NewDefaultError(mysql.ErQueryInterrupted)
+	case KilledByTimeout:
+		return mysql.NewDefaultError(mysql.ErQueryTimeout)
+	}
+	return nil
+}
+
+// GetKVReads returns the number of the reads of the KV store by the session bound to ctx,
+// it is 0 if there is no session.
+func GetKVReads(ctx context.Context) uint64 {
+	if ctx == nil {
+		return 0
+	}
+	if vars := GetSessionVars(ctx); vars != nil {
+		return vars.KVReads
+	}
+	return 0
+}
+
+// AddRowsExamined adds a row read from a table to the rows examined by the session bound to ctx.
+func AddRowsExamined(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+	if vars := GetSessionVars(ctx); vars != nil {
+		vars.RowsExamined++
+	}
 }
 
 // sessionVarsKeyType is a dummy type to avoid naming collision in context.
@@ -60,6 +150,7 @@ func BindSessionVars(ctx context.Context) {
 	v := &SessionVars{
 		Users:         make(map[string]string),
 		Systems:       make(map[string]string),
+		Globals:       make(map[string]string),
 		PreparedStmts: make(map[string]stmt.Statement),
 	}
 
@@ -118,3 +209,139 @@ func IsAutocommit(ctx context.Context) bool {
 	}
 	return false
 }
+
+// GetMemQuotaQuery gets the memory quota of a statement in bytes, 0 means no quota.
+// The session value is used if it is set, otherwise the global value is used.
+func GetMemQuotaQuery(ctx context.Context) int64 {
+	quota, err := strconv.ParseInt(getSystemValue(ctx, MemQuotaQuery), 10, 64)
+	if err != nil || quota < 0 {
+		return 0
+	}
+	return quota
+}
+
+// GetCTEMaxRecursionDepth gets the maximum number of iterations of a recursive common table expression.
+// The session value is used if it is set, otherwise the global value is used.
+func GetCTEMaxRecursionDepth(ctx context.Context) int64 {
+	depth, err := strconv.ParseInt(getSystemValue(ctx, CTEMaxRecursionDepth), 10, 64)
+	if err != nil || depth < 0 {
+		return 0
+	}
+	return depth
+}
+
+// GetMaxExecutionTime gets the maximum execution time of a statement, 0 means no limit.
+// The session value is used if it is set, otherwise the global value is used.
+func GetMaxExecutionTime(ctx context.Context) time.Duration {
+	ms, err := strconv.ParseInt(getSystemValue(ctx, MaxExecutionTime), 10, 64)
+	if err != nil || ms < 0 {
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// GetSlowQueryLog gets the file of the slow log and the minimum execution time of the statements
+// written to it, the file is empty if the slow log is disabled.
+// The session values are used if they are set, otherwise the global values are used.
+func GetSlowQueryLog(ctx context.Context) (file string, longQueryTime time.Duration) {
+	if on := getSystemValue(ctx, SlowQueryLog); !strings.EqualFold(on, "ON") && on != "1" {
+		return "", 0
+	}
+	seconds, err := strconv.ParseFloat(getSystemValue(ctx, LongQueryTime), 64)
+	if err != nil || seconds < 0 {
+		seconds, _ = strconv.ParseFloat(GetSysVar(LongQueryTime).Value, 64)
+	}
+	return getSystemValue(ctx, SlowQueryLogFile), time.Duration(seconds * float64(time.Second))
+}
+
+// GetSQLMode gets the sql_mode of current session.
+// The session value is used if it is set, otherwise the global value is used.
+// If the global value can't be parsed, strict mode is used.
+// Strict mode is turned off while executing a statement with IGNORE modifier.
+func GetSQLMode(ctx context.Context) mysql.SQLMode {
+	mode, err := mysql.GetSQLMode(GetSysVar(SQLModeVar).Value)
+	if err != nil {
+		mode = mysql.ModeStrictTransTables
+	}
+	if ctx == nil {
+		return mode
+	}
+
+	if vars := GetSessionVars(ctx); vars != nil {
+		if _, ok := vars.Systems[SQLModeVar]; ok {
+			mode = vars.SQLMode
+		}
+		if vars.IgnoreErrors {
+			mode &^= mysql.ModeStrictTransTables | mysql.ModeStrictAllTables
+		}
+	}
+	return mode
+}
+
+// SetSQLMode sets the sql_mode of current session.
+func (s *SessionVars) SetSQLMode(mode mysql.SQLMode) {
+	s.SQLMode = mode
+	s.Systems[SQLModeVar] = mode.String()
+}
+
+// GetTimeZone gets the location of time_zone of the session bound to ctx.
+// The session value is used if it is set, otherwise the global value is used.
+// If the global value can't be parsed, the system time zone is used.
+func GetTimeZone(ctx context.Context) *time.Location {
+	if ctx != nil {
+		vars := GetSessionVars(ctx)
+		if vars != nil && vars.TimeZone != nil {
+			if _, ok := vars.Systems[TimeZone]; ok {
+				return vars.TimeZone
+			}
+		}
+	}
+
+	loc, err := mysql.ParseTimeZone(GetSysVar(TimeZone).Value)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// SetTimeZone sets the time_zone of current session, loc is the location parsed from name.
+func (s *SessionVars) SetTimeZone(name string, loc *time.Location) {
+	s.TimeZone = loc
+	s.Systems[TimeZone] = name
+}
+
+func getSystemValue(ctx context.Context, name string) string {
+	if ctx != nil {
+		if vars := GetSessionVars(ctx); vars != nil {
+			if v, ok := vars.Systems[name]; ok {
+				return v
+			}
+			if v, ok := vars.Globals[name]; ok {
+				return v
+			}
+		}
+	}
+	return GetSysVar(name).Value
+}
+
+// SetSystemVar sets the session value of the system variable name, sql_mode and
+// time_zone are parsed for the session.
+func (s *SessionVars) SetSystemVar(name string, value string) error {
+	switch name {
+	case SQLModeVar:
+		mode, err := mysql.GetSQLMode(value)
+		if err != nil {
+			return mysql.NewDefaultError(mysql.ErWrongValueForVar, name, value)
+		}
+		s.SetSQLMode(mode)
+	case TimeZone:
+		loc, err := mysql.ParseTimeZone(value)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		s.SetTimeZone(value, loc)
+	default:
+		s.Systems[name] = value
+	}
+	return nil
+}
@@ -14,7 +14,10 @@
 package variable
 
 import (
+	"time"
+
 	. "github.com/pingcap/check"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
 	"github.com/Dong-Chan/alloydb/util/mock"
 )
 
@@ -47,3 +50,66 @@ func (*testSessionSuite) TestSession(c *C) {
 	v.SetLastInsertID(uint64(1))
 	c.Assert(v.LastInsertID, Equals, uint64(1))
 }
+
+func (*testSessionSuite) TestMemQuotaQuery(c *C) {
+	c.Assert(GetMemQuotaQuery(nil), Equals, int64(1<<30))
+
+	ctx := mock.NewContext()
+	c.Assert(GetMemQuotaQuery(ctx), Equals, int64(1<<30))
+
+	BindSessionVars(ctx)
+	v := GetSessionVars(ctx)
+	v.Systems[MemQuotaQuery] = "1024"
+	c.Assert(GetMemQuotaQuery(ctx), Equals, int64(1024))
+
+	v.Systems[MemQuotaQuery] = "abc"
+	c.Assert(GetMemQuotaQuery(ctx), Equals, int64(0))
+}
+
+func (*testSessionSuite) TestCTEMaxRecursionDepth(c *C) {
+	c.Assert(GetCTEMaxRecursionDepth(nil), Equals, int64(1000))
+
+	ctx := mock.NewContext()
+	BindSessionVars(ctx)
+	v := GetSessionVars(ctx)
+	v.Systems[CTEMaxRecursionDepth] = "10"
+	c.Assert(GetCTEMaxRecursionDepth(ctx), Equals, int64(10))
+
+	v.Systems[CTEMaxRecursionDepth] = "-1"
+	c.Assert(GetCTEMaxRecursionDepth(ctx), Equals, int64(0))
+}
+
+func (*testSessionSuite) TestSQLMode(c *C) {
+	defaultMode := mysql.ModeStrictTransTables | mysql.ModeNoEngineSubstitution
+	c.Assert(GetSQLMode(nil), Equals, defaultMode)
+
+	ctx := mock.NewContext()
+	BindSessionVars(ctx)
+	c.Assert(GetSQLMode(ctx), Equals, defaultMode)
+
+	v := GetSessionVars(ctx)
+	v.SetSQLMode(mysql.ModeANSIQuotes)
+	c.Assert(GetSQLMode(ctx), Equals, mysql.ModeANSIQuotes)
+	c.Assert(v.Systems[SQLModeVar], Equals, "ANSI_QUOTES")
+
+	v.SetSQLMode(mysql.ModeNone)
+	c.Assert(GetSQLMode(ctx), Equals, mysql.ModeNone)
+	c.Assert(GetSQLMode(nil), Equals, defaultMode)
+}
+
+func (*testSessionSuite) TestTimeZone(c *C) {
+	c.Assert(GetTimeZone(nil), Equals, time.Local)
+
+	ctx := mock.NewContext()
+	BindSessionVars(ctx)
+	v := GetSessionVars(ctx)
+	c.Assert(GetTimeZone(ctx), Equals, time.Local)
+
+	loc, err := mysql.ParseTimeZone("+08:00")
+	c.Assert(err, IsNil)
+	v.SetTimeZone("+08:00", loc)
+	c.Assert(GetTimeZone(ctx), Equals, loc)
+	c.Assert(v.Systems[TimeZone], Equals, "+08:00")
+
+	c.Assert(GetSysVar(SystemTimeZone).Value, Not(Equals), "")
+}
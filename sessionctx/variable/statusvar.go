@@ -0,0 +1,79 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/context"
+)
+
+// StatusVal is the value of a status variable with its scope.
+type StatusVal struct {
+	Scope ScopeFlag
+	Value interface{}
+}
+
+// Statistics provides status variables, the providers are registered by RegisterStatistics.
+type Statistics interface {
+	// Stats returns the status variables for the session bound to ctx.
+	Stats(ctx context.Context) (map[string]*StatusVal, error)
+}
+
+var statistics struct {
+	mu    sync.RWMutex
+	items []Statistics
+}
+
+// RegisterStatistics registers the provider of status variables.
+func RegisterStatistics(s Statistics) {
+	statistics.mu.Lock()
+	statistics.items = append(statistics.items, s)
+	statistics.mu.Unlock()
+}
+
+// GetStatusVars returns the status variables of all the registered providers.
+func GetStatusVars(ctx context.Context) (map[string]*StatusVal, error) {
+	statistics.mu.RLock()
+	defer statistics.mu.RUnlock()
+
+	statusVars := make(map[string]*StatusVal)
+	for _, s := range statistics.items {
+		vals, err := s.Stats(ctx)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for name, val := range vals {
+			statusVars[name] = val
+		}
+	}
+	return statusVars, nil
+}
+
+var startTime = time.Now()
+
+type serverStats struct{}
+
+// Stats implements the Statistics Stats interface.
+func (s serverStats) Stats(ctx context.Context) (map[string]*StatusVal, error) {
+	return map[string]*StatusVal{
+		"Uptime": {ScopeGlobal, int64(time.Since(startTime) / time.Second)},
+	}, nil
+}
+
+func init() {
+	RegisterStatistics(serverStats{})
+}
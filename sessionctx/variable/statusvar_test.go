@@ -0,0 +1,48 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/util/mock"
+)
+
+var _ = Suite(&testStatusVarSuite{})
+
+type testStatusVarSuite struct {
+}
+
+type testStatistics struct{}
+
+func (testStatistics) Stats(ctx context.Context) (map[string]*StatusVal, error) {
+	return map[string]*StatusVal{
+		"test_session": {ScopeSession, GetSessionVars(ctx).WarningCount()},
+	}, nil
+}
+
+func (*testStatusVarSuite) TestStatusVars(c *C) {
+	ctx := mock.NewContext()
+	BindSessionVars(ctx)
+	vars, err := GetStatusVars(ctx)
+	c.Assert(err, IsNil)
+	c.Assert(vars["Uptime"].Scope, Equals, ScopeGlobal)
+	c.Assert(vars["test_session"], IsNil)
+
+	RegisterStatistics(testStatistics{})
+	vars, err = GetStatusVars(ctx)
+	c.Assert(err, IsNil)
+	c.Assert(vars["Uptime"], NotNil)
+	c.Assert(vars["test_session"].Value, Equals, uint64(0))
+}
@@ -13,7 +13,14 @@
 
 package variable
 
-import "strings"
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+)
 
 // ScopeFlag is for system variable whether can be changed in global/session dynamically or not.
 type ScopeFlag uint8
@@ -27,6 +34,20 @@ const (
 	ScopeSession
 )
 
+// SysVarType is the type of the value of a system variable.
+type SysVarType uint8
+
+const (
+	// TypeStr means the value can be any string, it is not validated.
+	TypeStr SysVarType = iota
+	// TypeInt means the value is an integer between MinValue and MaxValue.
+	TypeInt
+	// TypeBool means the value is ON or OFF.
+	TypeBool
+	// TypeEnum means the value is one of PossibleValues.
+	TypeEnum
+)
+
 // SysVar is for system variable
 type SysVar struct {
 	// Scope is for whether can be changed or not
@@ -37,14 +58,99 @@ type SysVar struct {
 
 	// Variable value
 	Value string
+
+	// Type is the type of the value, the value is validated by it when the variable is set.
+	Type SysVarType
+	// MinValue and MaxValue are the bounds of a TypeInt variable.
+	MinValue int64
+	MaxValue int64
+	// PossibleValues are the values of a TypeEnum variable.
+	PossibleValues []string
+}
+
+// Validate checks value for the type of v and returns it in the normal form, that is ON or OFF
+// for a TypeBool variable and the upper case value for a TypeEnum variable.
+// A TypeInt value out of the bounds is clamped, truncated is true then.
+func (v *SysVar) Validate(value string) (normalized string, truncated bool, err error) {
+	switch v.Type {
+	case TypeInt:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", false, mysql.NewDefaultError(mysql.ErWrongTypeForVar, v.Name)
+		}
+		if i < v.MinValue {
+			i, truncated = v.MinValue, true
+		} else if i > v.MaxValue {
+			i, truncated = v.MaxValue, true
+		}
+		return strconv.FormatInt(i, 10), truncated, nil
+	case TypeBool:
+		switch strings.ToUpper(value) {
+		case "ON", "1", "TRUE":
+			return "ON", false, nil
+		case "OFF", "0", "FALSE":
+			return "OFF", false, nil
+		}
+	case TypeEnum:
+		for _, p := range v.PossibleValues {
+			if strings.EqualFold(p, value) {
+				return p, false, nil
+			}
+		}
+	default:
+		return value, false, nil
+	}
+	return "", false, mysql.NewDefaultError(mysql.ErWrongValueForVar, v.Name, value)
 }
 
-// Global sys vars map
+// MemQuotaQuery is the name of the system variable for the memory quota of a statement in bytes.
+// If the rows kept by sort, group by or distinct exceed the quota, they are spilled to disk.
+// 0 means no quota.
+const MemQuotaQuery = "alloydb_mem_quota_query"
+
+// CTEMaxRecursionDepth is the name of the system variable for the maximum number of iterations
+// of a recursive common table expression.
+const CTEMaxRecursionDepth = "cte_max_recursion_depth"
+
+// MaxExecutionTime is the name of the system variable for the maximum execution time of a statement
+// in milliseconds, the statement is interrupted when it runs longer. 0 means no limit.
+const MaxExecutionTime = "max_execution_time"
+
+// SlowQueryLog, LongQueryTime and SlowQueryLogFile are the names of the system variables of the slow log,
+// the statements running longer than long_query_time seconds are written to slow_query_log_file
+// if slow_query_log is ON.
+const (
+	SlowQueryLog     = "slow_query_log"
+	LongQueryTime    = "long_query_time"
+	SlowQueryLogFile = "slow_query_log_file"
+)
+
+// SQLModeVar is the name of the sql_mode system variable.
+const SQLModeVar = "sql_mode"
+
+// MaxErrorCount is the name of the system variable for the maximum number of
+// warnings and errors kept for SHOW WARNINGS and SHOW ERRORS.
+const MaxErrorCount = "max_error_count"
+
+// WarningCount and ErrorCount are the names of the read only system variables
+// for the number of warnings and errors generated by the last statement.
+const (
+	WarningCount = "warning_count"
+	ErrorCount   = "error_count"
+)
+
+// TimeZone is the name of the system variable for the time zone of the session,
+// SystemTimeZone is the name of the read only system variable for the time zone of the server.
+const (
+	TimeZone       = "time_zone"
+	SystemTimeZone = "system_time_zone"
+)
+
+// SysVars are the definitions of the system variables.
 var SysVars map[string]*SysVar
 
-// GetSysVar returns the sysvar value for the given name
-// TODO: later we will get global sys vars from KV storage,
-// so this function will be removed later, maybe.
+// GetSysVar returns the definition of the system variable name, its Value is the default value.
+// The global values are in the store, use GetGlobalSysVar to get them.
 func GetSysVar(name string) *SysVar {
 	name = strings.ToLower(name)
 	return SysVars[name]
@@ -53,12 +159,53 @@ func GetSysVar(name string) *SysVar {
 func init() {
 	SysVars = make(map[string]*SysVar)
 	for _, v := range defaultSysVars {
-		SysVars[v.Name] = v
+		SysVars[v.Name] = &SysVar{Scope: v.Scope, Name: v.Name, Value: v.Value}
 	}
+	for _, t := range typedSysVars {
+		v := SysVars[t.Name]
+		v.Type, v.MinValue, v.MaxValue, v.PossibleValues = t.Type, t.MinValue, t.MaxValue, t.PossibleValues
+	}
+
+	// The system time zone is the zone of the server.
+	SysVars[SystemTimeZone].Value, _ = time.Now().Zone()
+}
+
+// typedSysVars are the types of the system variables whose values are validated,
+// the other variables are TypeStr.
+var typedSysVars = []*SysVar{
+	{Name: "autocommit", Type: TypeBool},
+	{Name: "foreign_key_checks", Type: TypeBool},
+	{Name: "unique_checks", Type: TypeBool},
+	{Name: "sql_safe_updates", Type: TypeBool},
+	{Name: "tx_read_only", Type: TypeBool},
+	{Name: "read_only", Type: TypeBool},
+	{Name: "general_log", Type: TypeBool},
+	{Name: SlowQueryLog, Type: TypeBool},
+	{Name: "tx_isolation", Type: TypeEnum, PossibleValues: []string{"READ-UNCOMMITTED", "READ-COMMITTED", "REPEATABLE-READ", "SERIALIZABLE"}},
+	{Name: "max_connections", Type: TypeInt, MinValue: 1, MaxValue: 100000},
+	{Name: "max_allowed_packet", Type: TypeInt, MinValue: 1024, MaxValue: 1073741824},
+	{Name: "wait_timeout", Type: TypeInt, MinValue: 1, MaxValue: 31536000},
+	{Name: "interactive_timeout", Type: TypeInt, MinValue: 1, MaxValue: 31536000},
+	{Name: "auto_increment_increment", Type: TypeInt, MinValue: 1, MaxValue: 65535},
+	{Name: "auto_increment_offset", Type: TypeInt, MinValue: 1, MaxValue: 65535},
+	{Name: "div_precision_increment", Type: TypeInt, MinValue: 0, MaxValue: 30},
+	{Name: "default_week_format", Type: TypeInt, MinValue: 0, MaxValue: 7},
+	{Name: "group_concat_max_len", Type: TypeInt, MinValue: 4, MaxValue: math.MaxInt64},
+	{Name: MaxErrorCount, Type: TypeInt, MinValue: 0, MaxValue: 65535},
+	{Name: MemQuotaQuery, Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt64},
+	{Name: CTEMaxRecursionDepth, Type: TypeInt, MinValue: 0, MaxValue: math.MaxUint32},
+	{Name: MaxExecutionTime, Type: TypeInt, MinValue: 0, MaxValue: math.MaxUint32},
+}
+
+// sysVarDefault is the scope and the default value of a system variable.
+type sysVarDefault struct {
+	Scope ScopeFlag
+	Name  string
+	Value string
 }
 
 // we only support MySQL now
-var defaultSysVars = []*SysVar{
+var defaultSysVars = []*sysVarDefault{
 	{ScopeGlobal, "gtid_mode", "OFF"},
 	{ScopeGlobal, "flush_time", "0"},
 	{ScopeSession, "pseudo_slave_mode", ""},
@@ -99,7 +246,7 @@ var defaultSysVars = []*SysVar{
 	{ScopeNone, "skip_name_resolve", "OFF"},
 	{ScopeNone, "performance_schema_max_file_handles", "32768"},
 	{ScopeSession, "transaction_allow_batching", ""},
-	{ScopeGlobal | ScopeSession, "sql_mode", "NO_ENGINE_SUBSTITUTION"},
+	{ScopeGlobal | ScopeSession, SQLModeVar, "STRICT_TRANS_TABLES,NO_ENGINE_SUBSTITUTION"},
 	{ScopeNone, "performance_schema_max_statement_classes", "168"},
 	{ScopeGlobal, "server_id", "0"},
 	{ScopeGlobal, "innodb_flushing_avg_loops", "30"},
@@ -150,7 +297,7 @@ var defaultSysVars = []*SysVar{
 	{ScopeGlobal, "rpl_semi_sync_master_trace_level", ""},
 	{ScopeGlobal | ScopeSession, "max_insert_delayed_threads", "20"},
 	{ScopeNone, "performance_schema_session_connect_attrs_size", "512"},
-	{ScopeGlobal | ScopeSession, "time_zone", "SYSTEM"},
+	{ScopeGlobal | ScopeSession, TimeZone, "SYSTEM"},
 	{ScopeGlobal, "innodb_max_dirty_pages_pct", "75"},
 	{ScopeGlobal, "innodb_file_per_table", "ON"},
 	{ScopeGlobal, "innodb_log_compressed_pages", "ON"},
@@ -159,7 +306,7 @@ var defaultSysVars = []*SysVar{
 	{ScopeNone, "skip_networking", "OFF"},
 	{ScopeGlobal, "innodb_monitor_reset", ""},
 	{ScopeNone, "have_ssl", "DISABLED"},
-	{ScopeNone, "system_time_zone", "CST"},
+	{ScopeNone, SystemTimeZone, "CST"},
 	{ScopeGlobal, "innodb_print_all_deadlocks", "OFF"},
 	{ScopeNone, "innodb_autoinc_lock_mode", "1"},
 	{ScopeGlobal, "slave_net_timeout", "3600"},
@@ -218,7 +365,9 @@ var defaultSysVars = []*SysVar{
 	{ScopeNone, "innodb_undo_tablespaces", "0"},
 	{ScopeGlobal, "innodb_status_output_locks", "OFF"},
 	{ScopeNone, "performance_schema_accounts_size", "100"},
-	{ScopeGlobal | ScopeSession, "max_error_count", "64"},
+	{ScopeGlobal | ScopeSession, MaxErrorCount, "64"},
+	{ScopeNone, WarningCount, "0"},
+	{ScopeNone, ErrorCount, "0"},
 	{ScopeGlobal, "max_write_lock_count", "18446744073709551615"},
 	{ScopeNone, "performance_schema_max_socket_instances", "322"},
 	{ScopeNone, "performance_schema_max_table_instances", "12500"},
@@ -326,7 +475,7 @@ var defaultSysVars = []*SysVar{
 	{ScopeGlobal, "log_error_verbosity", ""},
 	{ScopeNone, "performance_schema_hosts_size", "100"},
 	{ScopeGlobal, "innodb_replication_delay", "0"},
-	{ScopeGlobal, "slow_query_log", "OFF"},
+	{ScopeGlobal, SlowQueryLog, "OFF"},
 	{ScopeSession, "debug_sync", ""},
 	{ScopeGlobal, "innodb_stats_auto_recalc", "ON"},
 	{ScopeGlobal, "timed_mutexes", "OFF"},
@@ -388,7 +537,7 @@ var defaultSysVars = []*SysVar{
 	{ScopeGlobal, "executed_gtids_compression_period", ""},
 	{ScopeNone, "time_format", "%H:%i:%s"},
 	{ScopeGlobal | ScopeSession, "old_alter_table", "OFF"},
-	{ScopeGlobal | ScopeSession, "long_query_time", "10.000000"},
+	{ScopeGlobal | ScopeSession, LongQueryTime, "10.000000"},
 	{ScopeNone, "innodb_use_native_aio", "OFF"},
 	{ScopeGlobal, "log_throttle_queries_not_using_indexes", "0"},
 	{ScopeNone, "locked_in_memory", "OFF"},
@@ -417,7 +566,7 @@ var defaultSysVars = []*SysVar{
 	{ScopeGlobal | ScopeSession, "max_sp_recursion_depth", "0"},
 	{ScopeNone, "ignore_builtin_innodb", "OFF"},
 	{ScopeGlobal, "rpl_semi_sync_master_enabled", ""},
-	{ScopeGlobal, "slow_query_log_file", "/usr/local/mysql/data/localhost-slow.log"},
+	{ScopeGlobal, SlowQueryLogFile, "alloydb-slow.log"},
 	{ScopeGlobal, "innodb_thread_sleep_delay", "10000"},
 	{ScopeNone, "license", "GPL"},
 	{ScopeGlobal, "innodb_ft_aux_table", ""},
@@ -550,6 +699,7 @@ var defaultSysVars = []*SysVar{
 	{ScopeGlobal, "innodb_buffer_pool_dump_pct", ""},
 	{ScopeGlobal | ScopeSession, "lc_time_names", "en_US"},
 	{ScopeGlobal | ScopeSession, "max_statement_time", ""},
+	{ScopeGlobal | ScopeSession, MaxExecutionTime, "0"},
 	{ScopeGlobal | ScopeSession, "end_markers_in_json", "OFF"},
 	{ScopeGlobal, "avoid_temporal_upgrade", "OFF"},
 	{ScopeGlobal, "key_cache_age_threshold", "300"},
@@ -558,4 +708,7 @@ var defaultSysVars = []*SysVar{
 	{ScopeGlobal | ScopeSession, "min_examined_row_limit", "0"},
 	{ScopeGlobal, "sync_frm", "ON"},
 	{ScopeGlobal, "innodb_online_alter_log_max_size", "134217728"},
+	// alloydb specific system variables.
+	{ScopeGlobal | ScopeSession, MemQuotaQuery, "1073741824"},
+	{ScopeGlobal | ScopeSession, CTEMaxRecursionDepth, "1000"},
 }
@@ -16,7 +16,9 @@ package variable
 import (
 	"testing"
 
+	"github.com/juju/errors"
 	. "github.com/pingcap/check"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
 )
 
 func TestT(t *testing.T) {
@@ -35,3 +37,34 @@ func (*testSysVarSuite) TestSysVar(c *C) {
 	f = GetSysVar("wrong-var-name")
 	c.Assert(f, IsNil)
 }
+
+func (*testSysVarSuite) TestValidate(c *C) {
+	tbl := []struct {
+		name       string
+		value      string
+		normalized string
+		truncated  bool
+		code       int
+	}{
+		{"autocommit", "1", "ON", false, 0},
+		{"autocommit", "off", "OFF", false, 0},
+		{"autocommit", "2", "", false, mysql.ErWrongValueForVar},
+		{"tx_isolation", "read-committed", "READ-COMMITTED", false, 0},
+		{"tx_isolation", "abc", "", false, mysql.ErWrongValueForVar},
+		{"max_connections", "100", "100", false, 0},
+		{"max_connections", "0", "1", true, 0},
+		{"max_connections", "1000000", "100000", true, 0},
+		{"max_connections", "abc", "", false, mysql.ErWrongTypeForVar},
+		{"character_set_client", "abc", "abc", false, 0},
+	}
+	for _, t := range tbl {
+		normalized, truncated, err := GetSysVar(t.name).Validate(t.value)
+		if t.code != 0 {
+			c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(t.code), Commentf("%v", t))
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert(normalized, Equals, t.normalized)
+		c.Assert(truncated, Equals, t.truncated)
+	}
+}
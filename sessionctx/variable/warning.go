@@ -0,0 +1,134 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+import (
+	"strconv"
+
+	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/context"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+)
+
+// Warning levels.
+const (
+	WarnLevelNote    = "Note"
+	WarnLevelWarning = "Warning"
+	WarnLevelError   = "Error"
+)
+
+// Warning is a note, warning or error generated by a statement, they are shown by SHOW WARNINGS.
+type Warning struct {
+	Level   string
+	Code    uint16
+	Message string
+}
+
+// newWarning creates a Warning from err, the code and message of a mysql error are used.
+func newWarning(level string, err error) *Warning {
+	if e, ok := errors.Cause(err).(*mysql.SQLError); ok {
+		return &Warning{Level: level, Code: e.Code, Message: e.Message}
+	}
+	return &Warning{Level: level, Code: mysql.ErUnknownError, Message: err.Error()}
+}
+
+// statementWarnings is the warning list of the last statement.
+type statementWarnings struct {
+	// warnings keeps at most max_error_count warnings.
+	warnings []*Warning
+	// warningCount and errorCount count all the warnings and errors, including the ones not kept.
+	warningCount uint64
+	errorCount   uint64
+	// stale is set if the warnings are of an earlier statement,
+	// they are cleared when the current statement appends a new one.
+	stale bool
+}
+
+// ResetWarnings is called before executing a statement to clear the warnings of the last statement.
+// If keep is true, like SHOW WARNINGS and the statements using no tables, the warnings are kept
+// until the statement generates a new one.
+func (s *SessionVars) ResetWarnings(keep bool) {
+	if keep {
+		s.stmtWarnings.stale = true
+		return
+	}
+	s.stmtWarnings = statementWarnings{}
+}
+
+// AppendWarning appends a warning with level to the warning list of current statement.
+func (s *SessionVars) AppendWarning(level string, err error) {
+	if s.stmtWarnings.stale {
+		s.stmtWarnings = statementWarnings{}
+	}
+
+	w := &s.stmtWarnings
+	w.warningCount++
+	if level == WarnLevelError {
+		w.errorCount++
+	}
+	if len(w.warnings) < s.maxErrorCount() {
+		w.warnings = append(w.warnings, newWarning(level, err))
+	}
+}
+
+// Warnings returns the warnings of the last statement.
+func (s *SessionVars) Warnings() []*Warning {
+	return s.stmtWarnings.warnings
+}
+
+// WarningCount returns the number of the notes, warnings and errors of the last statement.
+func (s *SessionVars) WarningCount() uint64 {
+	return s.stmtWarnings.warningCount
+}
+
+// ErrorCount returns the number of the errors of the last statement.
+func (s *SessionVars) ErrorCount() uint64 {
+	return s.stmtWarnings.errorCount
+}
+
+func (s *SessionVars) maxErrorCount() int {
+	v, ok := s.Systems[MaxErrorCount]
+	if !ok {
+		v = GetSysVar(MaxErrorCount).Value
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// AppendWarning appends a warning to the session bound to ctx, it does nothing if there is no session.
+func AppendWarning(ctx context.Context, err error) {
+	appendWarning(ctx, WarnLevelWarning, err)
+}
+
+// AppendNote appends a note to the session bound to ctx, it does nothing if there is no session.
+func AppendNote(ctx context.Context, err error) {
+	appendWarning(ctx, WarnLevelNote, err)
+}
+
+// AppendError appends an error to the session bound to ctx, it does nothing if there is no session.
+func AppendError(ctx context.Context, err error) {
+	appendWarning(ctx, WarnLevelError, err)
+}
+
+func appendWarning(ctx context.Context, level string, err error) {
+	if ctx == nil {
+		return
+	}
+	if vars := GetSessionVars(ctx); vars != nil {
+		vars.AppendWarning(level, err)
+	}
+}
@@ -0,0 +1,75 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+import (
+	"github.com/juju/errors"
+	. "github.com/pingcap/check"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/util/mock"
+)
+
+var _ = Suite(&testWarningSuite{})
+
+type testWarningSuite struct {
+}
+
+func (*testWarningSuite) TestWarnings(c *C) {
+	// No session is bound, appending does nothing.
+	AppendWarning(nil, errors.New("no session"))
+	ctx := mock.NewContext()
+	AppendWarning(ctx, errors.New("no session"))
+
+	BindSessionVars(ctx)
+	v := GetSessionVars(ctx)
+	c.Assert(v.Warnings(), HasLen, 0)
+
+	AppendWarning(ctx, mysql.NewDefaultError(mysql.ErTruncatedWrongValue, "INTEGER", "abc"))
+	AppendNote(ctx, errors.New("a note"))
+	AppendError(ctx, errors.Trace(mysql.NewDefaultError(mysql.ErDivisionByZero)))
+
+	ws := v.Warnings()
+	c.Assert(ws, HasLen, 3)
+	c.Assert(ws[0].Level, Equals, WarnLevelWarning)
+	c.Assert(ws[0].Code, Equals, uint16(mysql.ErTruncatedWrongValue))
+	c.Assert(ws[0].Message, Equals, "Truncated incorrect INTEGER value: 'abc'")
+	c.Assert(ws[1].Level, Equals, WarnLevelNote)
+	c.Assert(ws[1].Code, Equals, uint16(mysql.ErUnknownError))
+	c.Assert(ws[1].Message, Equals, "a note")
+	c.Assert(ws[2].Level, Equals, WarnLevelError)
+	c.Assert(ws[2].Code, Equals, uint16(mysql.ErDivisionByZero))
+	c.Assert(v.WarningCount(), Equals, uint64(3))
+	c.Assert(v.ErrorCount(), Equals, uint64(1))
+
+	// The warnings are kept until a new one is appended.
+	v.ResetWarnings(true)
+	c.Assert(v.Warnings(), HasLen, 3)
+	c.Assert(v.WarningCount(), Equals, uint64(3))
+	AppendNote(ctx, errors.New("a new note"))
+	c.Assert(v.Warnings(), HasLen, 1)
+	c.Assert(v.WarningCount(), Equals, uint64(1))
+	c.Assert(v.ErrorCount(), Equals, uint64(0))
+
+	v.ResetWarnings(false)
+	c.Assert(v.Warnings(), HasLen, 0)
+	c.Assert(v.WarningCount(), Equals, uint64(0))
+
+	// At most max_error_count warnings are kept, but all of them are counted.
+	v.Systems[MaxErrorCount] = "2"
+	for i := 0; i < 5; i++ {
+		AppendWarning(ctx, errors.New("warning"))
+	}
+	c.Assert(v.Warnings(), HasLen, 2)
+	c.Assert(v.WarningCount(), Equals, uint64(5))
+}
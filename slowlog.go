@@ -0,0 +1,168 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alloydb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ngaut/log"
+	"github.com/Dong-Chan/alloydb/domain"
+	"github.com/Dong-Chan/alloydb/parser"
+	"github.com/Dong-Chan/alloydb/plan/plans"
+	"github.com/Dong-Chan/alloydb/rset"
+	"github.com/Dong-Chan/alloydb/rset/rsets"
+	"github.com/Dong-Chan/alloydb/sessionctx"
+	"github.com/Dong-Chan/alloydb/sessionctx/db"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/stmt"
+)
+
+// slowLogTimeFormat is the format of the times in the slow log.
+const slowLogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+// slowLogMu serializes the writes to the slow log files.
+var slowLogMu sync.Mutex
+
+// stmtExec is an execution of a statement of a session. The statement is timed from runStmt
+// until its rows are fetched, then it is added to the statement summaries of the domain and
+// written to the slow log if it takes longer than long_query_time.
+type stmtExec struct {
+	s          *session
+	text       string
+	stmtType   string
+	schemaName string
+	start      time.Time
+	// latency is the time spent in runStmt and fetching the rows.
+	latency time.Duration
+	// rowsExamined is the number of the rows examined by the session before the statement.
+	rowsExamined uint64
+	planDigest   string
+	finished     bool
+	// timer is the timer of max_execution_time of the statement, it is nil if there is no limit.
+	timer *time.Timer
+}
+
+func (s *session) newStmtExec(text string, st stmt.Statement) *stmtExec {
+	return &stmtExec{
+		s:            s,
+		text:         text,
+		stmtType:     stmtType(st),
+		schemaName:   db.GetCurrentSchema(s),
+		start:        time.Now(),
+		rowsExamined: s.vars.RowsExamined,
+		timer:        s.startStmt(st),
+	}
+}
+
+// run records the result of runStmt, the execution is finished unless the rows of rs are fetched later.
+func (e *stmtExec) run(rs rset.Recordset, err error) {
+	e.latency = time.Since(e.start)
+	switch x := rs.(type) {
+	case rsets.Recordset:
+		e.planDigest = plans.PlanDigest(x.Plan)
+	case *rsets.Recordset:
+		e.planDigest = plans.PlanDigest(x.Plan)
+	}
+	if rs == nil || err != nil {
+		e.finish(0, err)
+	}
+}
+
+// fetch records the rows fetched since start, the execution is finished by the first fetch.
+func (e *stmtExec) fetch(start time.Time, rowsSent uint64, err error) {
+	if e.finished {
+		return
+	}
+	e.latency += time.Since(start)
+	e.finish(rowsSent, err)
+}
+
+func (e *stmtExec) finish(rowsSent uint64, err error) {
+	e.finished = true
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+	stmtCounter.WithLabelValues(e.stmtType).Inc()
+	stmtDuration.WithLabelValues(e.stmtType).Observe(e.latency.Seconds())
+	if err != nil {
+		stmtErrorCounter.WithLabelValues(e.stmtType).Inc()
+	}
+	if len(e.text) == 0 {
+		return
+	}
+	s := e.s
+	text := parser.Normalize(e.text)
+	digest := sha256.Sum256([]byte(text))
+	exec := &domain.StmtExec{
+		SchemaName:   e.schemaName,
+		Digest:       hex.EncodeToString(digest[:]),
+		DigestText:   text,
+		PlanDigest:   e.planDigest,
+		Latency:      e.latency,
+		RowsSent:     rowsSent,
+		RowsExamined: s.vars.RowsExamined - e.rowsExamined,
+		Failed:       err != nil,
+		Time:         time.Now(),
+	}
+	sessionctx.GetDomain(s).AddStmtExec(exec)
+
+	file, longQueryTime := variable.GetSlowQueryLog(s)
+	if len(file) == 0 || exec.Latency < longQueryTime {
+		return
+	}
+	var txnStart time.Time
+	if s.txn != nil || !s.txnStart.Before(e.start) {
+		// The statement runs in a transaction began before or by it.
+		txnStart = s.txnStart
+	}
+	if err := writeSlowLog(file, s, exec, txnStart); err != nil {
+		log.Warnf("write slow log %s error: %v", file, err)
+	}
+}
+
+// writeSlowLog appends the entry of exec of session s to the slow log file.
+func writeSlowLog(file string, s *session, exec *domain.StmtExec, txnStart time.Time) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Time: %s\n", exec.Time.Format(slowLogTimeFormat))
+	fmt.Fprintf(&buf, "# User@Host: %s  Id: %d\n", s.userName, s.sid)
+	fmt.Fprintf(&buf, "# Schema: %s\n", exec.SchemaName)
+	if !txnStart.IsZero() {
+		fmt.Fprintf(&buf, "# Txn_start_time: %s\n", txnStart.Format(slowLogTimeFormat))
+	}
+	fmt.Fprintf(&buf, "# Query_time: %.6f  Rows_sent: %d  Rows_examined: %d  Succ: %t\n",
+		exec.Latency.Seconds(), exec.RowsSent, exec.RowsExamined, !exec.Failed)
+	fmt.Fprintf(&buf, "# Digest: %s\n", exec.Digest)
+	if len(exec.PlanDigest) != 0 {
+		fmt.Fprintf(&buf, "# Plan_digest: %s\n", exec.PlanDigest)
+	}
+	fmt.Fprintf(&buf, "%s;\n", exec.DigestText)
+
+	slowLogMu.Lock()
+	defer slowLogMu.Unlock()
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(buf.Bytes())
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -50,6 +50,16 @@ const (
 	ShowColumns
 	ShowWarnings
 	ShowCharset
+	ShowErrors
+	ShowGrants
+	ShowCollation
+	ShowCreateTable
+	ShowCreateDatabase
+	ShowIndex
+	ShowTableStatus
+	ShowVariables
+	ShowStatus
+	ShowProcessList
 )
 
 // A dummy type to avoid naming collision in context.
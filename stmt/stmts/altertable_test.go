@@ -39,4 +39,11 @@ func (s *testStmtSuite) TestAlterTable(c *C) {
 	c.Assert(mf.Len(), Greater, 0)
 
 	mustExec(c, s.testDB, testSQL)
+
+	// The rows written before the column is added have its default value.
+	mustExec(c, s.testDB, "insert t values (1, 1); alter table t add column c3 int default 3;")
+	var c3 int
+	err = s.testDB.QueryRow("select c3 from t where c1 = 1").Scan(&c3)
+	c.Assert(err, IsNil)
+	c.Assert(c3, Equals, 3)
 }
@@ -49,6 +49,7 @@ type DeleteStmt struct {
 	BeforeFrom  bool
 	TableIdents []table.Ident
 	Refs        *rsets.JoinRset
+	With        *WithClause
 
 	Text string
 }
@@ -132,6 +133,7 @@ func (s *DeleteStmt) hitWhere(ctx context.Context, t table.Table, data []interfa
 	for _, col := range t.Cols() {
 		m[col.Name.L] = data[col.Offset]
 	}
+	m[expressions.ExprEvalCollationFunc] = colCollationFunc(t.Cols())
 
 	ok, err := expressions.EvalBoolExpr(ctx, s.Where, m)
 	if err != nil {
@@ -199,6 +201,14 @@ func (s *DeleteStmt) tryDeleteUsingIndex(ctx context.Context, t table.Table) (bo
 
 // Exec implements the stmt.Statement Exec interface.
 func (s *DeleteStmt) Exec(ctx context.Context) (_ rset.Recordset, err error) {
+	if s.With != nil {
+		release, err := s.With.exec(ctx)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		defer release()
+	}
+
 	if s.MultiTable {
 		return s.execMultiTable(ctx)
 	}
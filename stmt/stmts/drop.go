@@ -124,6 +124,8 @@ func (s *DropTableStmt) Exec(ctx context.Context) (rset.Recordset, error) {
 type DropIndexStmt struct {
 	IfExists  bool
 	IndexName string
+	// TableIdent is the table in DROP INDEX ... ON tbl, it is empty if ON is omitted.
+	TableIdent table.Ident
 
 	Text string
 }
@@ -14,7 +14,11 @@
 package stmts
 
 import (
+	"strings"
+
 	"github.com/Dong-Chan/alloydb/context"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/plan"
 	"github.com/Dong-Chan/alloydb/plan/plans"
 	"github.com/Dong-Chan/alloydb/rset"
 	"github.com/Dong-Chan/alloydb/rset/rsets"
@@ -29,6 +33,11 @@ var _ stmt.Statement = (*ExplainStmt)(nil)
 // See: https://dev.mysql.com/doc/refman/5.7/en/explain.html
 type ExplainStmt struct {
 	S stmt.Statement
+	// Analyze is set for EXPLAIN ANALYZE, the query is executed and the runtime
+	// statistics of its plans are explained.
+	Analyze bool
+	// Format is the FORMAT option, TRADITIONAL or JSON.
+	Format string
 
 	Text string
 }
@@ -68,9 +77,27 @@ func (s *ExplainStmt) SetText(text string) {
 
 // Exec implements the stmt.Statement Exec interface.
 func (s *ExplainStmt) Exec(ctx context.Context) (_ rset.Recordset, err error) {
-	if v, ok := s.S.(*ShowStmt); ok {
-		return v.Exec(ctx)
+	p := &plans.ExplainDefaultPlan{S: s.S, Analyze: s.Analyze}
+	switch strings.ToLower(s.Format) {
+	case "", "traditional":
+	case "json":
+		p.JSON = true
+	default:
+		return nil, mysql.NewDefaultError(mysql.ErUnknownExplainFormat, s.Format)
+	}
+
+	if !p.Analyze && !p.JSON {
+		if v, ok := s.S.(*ShowStmt); ok {
+			return v.Exec(ctx)
+		}
+	} else if _, ok := s.S.(plan.Planner); !ok {
+		// Only the queries have plans.
+		name := "EXPLAIN FORMAT=JSON"
+		if p.Analyze {
+			name = "EXPLAIN ANALYZE"
+		}
+		return nil, mysql.NewDefaultError(mysql.ErNotSupportedYet, name+" of statements other than queries")
 	}
 
-	return rsets.Recordset{Ctx: ctx, Plan: &plans.ExplainDefaultPlan{S: s.S}}, nil
+	return rsets.Recordset{Ctx: ctx, Plan: p}, nil
 }
@@ -16,6 +16,7 @@ package stmts_test
 import (
 	. "github.com/pingcap/check"
 	"github.com/Dong-Chan/alloydb"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
 	"github.com/Dong-Chan/alloydb/stmt/stmts"
 )
 
@@ -63,4 +64,12 @@ func (s *testStmtSuite) TestExplain(c *C) {
 
 	_, err = testStmt.Exec(nil)
 	c.Assert(err, IsNil)
+
+	testStmt = &stmts.ExplainStmt{S: &stmts.DoStmt{}, Analyze: true}
+	_, err = testStmt.Exec(nil)
+	c.Assert(err.(*mysql.SQLError).Code, Equals, uint16(mysql.ErNotSupportedYet))
+
+	testStmt = &stmts.ExplainStmt{S: &stmts.SelectStmt{}, Format: "xml"}
+	_, err = testStmt.Exec(nil)
+	c.Assert(err.(*mysql.SQLError).Code, Equals, uint16(mysql.ErUnknownExplainFormat))
 }
@@ -0,0 +1,535 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmts
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/column"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/meta"
+	"github.com/Dong-Chan/alloydb/model"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/rset"
+	"github.com/Dong-Chan/alloydb/sessionctx"
+	"github.com/Dong-Chan/alloydb/sessionctx/db"
+	"github.com/Dong-Chan/alloydb/stmt"
+	"github.com/Dong-Chan/alloydb/util/format"
+	"github.com/Dong-Chan/alloydb/util/types"
+)
+
+var (
+	_ stmt.Statement = (*GrantStmt)(nil)
+	_ stmt.Statement = (*RevokeStmt)(nil)
+)
+
+// PrivElem is a privilege in GRANT and REVOKE, with the columns it is granted on.
+type PrivElem struct {
+	// All is true for ALL [PRIVILEGES], Priv is 0 for USAGE.
+	All  bool
+	Priv mysql.PrivilegeType
+	Cols []string
+}
+
+// Grant levels.
+const (
+	GrantLevelGlobal = iota + 1
+	GrantLevelDB
+	GrantLevelTable
+)
+
+// GrantLevel is the ON clause of GRANT and REVOKE. For the `*` level, Level is GrantLevelDB and
+// DBName is empty, it is the current database or the global level if there is no current database.
+type GrantLevel struct {
+	Level     int
+	DBName    string
+	TableName string
+}
+
+// String implements fmt.Stringer interface.
+func (l *GrantLevel) String() string {
+	switch l.Level {
+	case GrantLevelGlobal:
+		return "*.*"
+	case GrantLevelDB:
+		if len(l.DBName) == 0 {
+			return "*"
+		}
+		return l.DBName + ".*"
+	}
+	if len(l.DBName) == 0 {
+		return l.TableName
+	}
+	return l.DBName + "." + l.TableName
+}
+
+// resolve fills the current database in l, the names are lower case in the privilege tables.
+func (l *GrantLevel) resolve(ctx context.Context) (*GrantLevel, error) {
+	r := &GrantLevel{Level: l.Level, DBName: strings.ToLower(l.DBName), TableName: strings.ToLower(l.TableName)}
+	if r.Level == GrantLevelGlobal || len(r.DBName) > 0 {
+		return r, nil
+	}
+	r.DBName = strings.ToLower(db.GetCurrentSchema(ctx))
+	if len(r.DBName) > 0 {
+		return r, nil
+	}
+	if r.Level == GrantLevelDB {
+		r.Level = GrantLevelGlobal
+		return r, nil
+	}
+	return nil, mysql.NewDefaultError(mysql.ErNoDbError)
+}
+
+// allPrivs returns the privileges of ALL PRIVILEGES on the level.
+func (l *GrantLevel) allPrivs() mysql.PrivilegeType {
+	switch l.Level {
+	case GrantLevelGlobal:
+		return mysql.AllGlobalPrivs
+	case GrantLevelDB:
+		return mysql.AllDBPrivs
+	}
+	return mysql.AllTablePrivs
+}
+
+// privileges returns the privileges of elems on the resolved level l, and the column privileges
+// by lower case column names in cols order. The table and the columns must exist.
+func (l *GrantLevel) privileges(ctx context.Context, elems []*PrivElem) (privs mysql.PrivilegeType, cols []string, colPrivs map[string]mysql.PrivilegeType, err error) {
+	var tableCols []*column.Col
+	if l.Level == GrantLevelTable {
+		is := sessionctx.GetDomain(ctx).InfoSchema()
+		t, err := is.TableByName(model.NewCIStr(l.DBName), model.NewCIStr(l.TableName))
+		if err != nil {
+			return 0, nil, nil, mysql.NewDefaultError(mysql.ErNoSuchTable, l.DBName, l.TableName)
+		}
+		tableCols = t.Cols()
+	}
+
+	colPrivs = make(map[string]mysql.PrivilegeType)
+	for _, e := range elems {
+		if e.All {
+			privs |= l.allPrivs()
+			continue
+		}
+		if len(e.Cols) == 0 {
+			if e.Priv&^(l.allPrivs()|mysql.GrantPriv) != 0 {
+				return 0, nil, nil, mysql.NewDefaultError(mysql.ErIllegalGrantForTable)
+			}
+			privs |= e.Priv
+			continue
+		}
+		if l.Level != GrantLevelTable || e.Priv&^mysql.AllColumnPrivs != 0 {
+			return 0, nil, nil, mysql.NewDefaultError(mysql.ErIllegalGrantForTable)
+		}
+		for _, name := range e.Cols {
+			name = strings.ToLower(name)
+			if column.FindCol(tableCols, name) == nil {
+				return 0, nil, nil, mysql.NewDefaultError(mysql.ErBadFieldError, name, l.TableName)
+			}
+			if _, ok := colPrivs[name]; !ok {
+				cols = append(cols, name)
+			}
+			colPrivs[name] |= e.Priv
+		}
+	}
+	return privs, cols, colPrivs, nil
+}
+
+// privAssignments formats the *_priv columns of mysql.user or mysql.db for privs, value is "Y" or "N".
+func privAssignments(privs mysql.PrivilegeType, value string) string {
+	var list []string
+	for _, p := range privs.Privileges() {
+		list = append(list, fmt.Sprintf("%s = %q", p.Column(), value))
+	}
+	return strings.Join(list, ", ")
+}
+
+// dbPrivs reads the privileges of the account name@host on the database dbName.
+func dbPrivs(ctx context.Context, name, host, dbName string) (privs mysql.PrivilegeType, exists bool, err error) {
+	all := (mysql.AllDBPrivs | mysql.GrantPriv).Privileges()
+	var cols []string
+	for _, p := range all {
+		cols = append(cols, p.Column())
+	}
+	rs, err := execRestrictedSQL(ctx, `SELECT %s FROM mysql.db WHERE User = %q AND Host = %q AND DB = %q`,
+		strings.Join(cols, ", "), name, host, dbName)
+	if err != nil {
+		return 0, false, errors.Trace(err)
+	}
+	row, err := rs.FirstRow()
+	if err != nil || row == nil {
+		return 0, false, errors.Trace(err)
+	}
+	for i, p := range all {
+		if v, _ := types.ToString(row[i]); v == "Y" {
+			privs |= p
+		}
+	}
+	return privs, true, nil
+}
+
+// setDBPrivs writes the privileges of the account name@host on the database dbName,
+// the row is removed if there is no privilege.
+func setDBPrivs(ctx context.Context, name, host, dbName string, privs mysql.PrivilegeType, exists bool) error {
+	var err error
+	switch {
+	case privs == 0:
+		_, err = execRestrictedSQL(ctx, `DELETE FROM mysql.db WHERE User = %q AND Host = %q AND DB = %q`, name, host, dbName)
+	case !exists:
+		_, err = execRestrictedSQL(ctx, `INSERT INTO mysql.db (Host, DB, User) VALUES (%q, %q, %q)`, host, dbName, name)
+		if err == nil {
+			err = setDBPrivs(ctx, name, host, dbName, privs, true)
+		}
+	default:
+		all := mysql.AllDBPrivs | mysql.GrantPriv
+		assignments := privAssignments(privs, "Y")
+		if revoked := all &^ privs; revoked != 0 {
+			assignments += ", " + privAssignments(revoked, "N")
+		}
+		_, err = execRestrictedSQL(ctx, `UPDATE mysql.db SET %s WHERE User = %q AND Host = %q AND DB = %q`,
+			assignments, name, host, dbName)
+	}
+	return errors.Trace(err)
+}
+
+// tablePrivs reads the table privileges and the privileges on the columns of the account name@host on dbName.tableName.
+func tablePrivs(ctx context.Context, name, host, dbName, tableName string) (privs mysql.PrivilegeType, colPrivs map[string]mysql.PrivilegeType, exists bool, err error) {
+	rs, err := execRestrictedSQL(ctx, `SELECT Table_priv FROM mysql.tables_priv WHERE User = %q AND Host = %q AND DB = %q AND Table_name = %q`,
+		name, host, dbName, tableName)
+	if err != nil {
+		return 0, nil, false, errors.Trace(err)
+	}
+	row, err := rs.FirstRow()
+	if err != nil {
+		return 0, nil, false, errors.Trace(err)
+	}
+	if row != nil {
+		exists = true
+		v, _ := types.ToString(row[0])
+		privs = mysql.PrivilegesFromSetString(v)
+	}
+
+	rs, err = execRestrictedSQL(ctx, `SELECT Column_name, Column_priv FROM mysql.columns_priv WHERE User = %q AND Host = %q AND DB = %q AND Table_name = %q`,
+		name, host, dbName, tableName)
+	if err != nil {
+		return 0, nil, false, errors.Trace(err)
+	}
+	colPrivs = make(map[string]mysql.PrivilegeType)
+	err = rs.Do(func(data []interface{}) (bool, error) {
+		col, _ := types.ToString(data[0])
+		v, _ := types.ToString(data[1])
+		colPrivs[strings.ToLower(col)] = mysql.PrivilegesFromSetString(v)
+		return true, nil
+	})
+	return privs, colPrivs, exists, errors.Trace(err)
+}
+
+// setTablePrivs writes the table privileges of the account name@host on dbName.tableName and the column privileges
+// in cols, the rows without privileges are removed. oldColPrivs are the column privileges before the change.
+func setTablePrivs(ctx context.Context, name, host, dbName, tableName string, privs mysql.PrivilegeType, exists bool,
+	cols []string, oldColPrivs, colPrivs map[string]mysql.PrivilegeType) error {
+	var colUnion mysql.PrivilegeType
+	for _, p := range colPrivs {
+		colUnion |= p
+	}
+	for _, col := range cols {
+		old, ok := oldColPrivs[col]
+		p := colPrivs[col]
+		var err error
+		switch {
+		case p == old && ok:
+			continue
+		case p == 0:
+			_, err = execRestrictedSQL(ctx, `DELETE FROM mysql.columns_priv WHERE User = %q AND Host = %q AND DB = %q AND Table_name = %q AND Column_name = %q`,
+				name, host, dbName, tableName, col)
+		case ok:
+			_, err = execRestrictedSQL(ctx, `UPDATE mysql.columns_priv SET Column_priv = %q WHERE User = %q AND Host = %q AND DB = %q AND Table_name = %q AND Column_name = %q`,
+				p.SetString(), name, host, dbName, tableName, col)
+		default:
+			_, err = execRestrictedSQL(ctx, `INSERT INTO mysql.columns_priv (Host, DB, User, Table_name, Column_name, Column_priv) VALUES (%q, %q, %q, %q, %q, %q)`,
+				host, dbName, name, tableName, col, p.SetString())
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	var err error
+	switch {
+	case privs == 0 && colUnion == 0:
+		_, err = execRestrictedSQL(ctx, `DELETE FROM mysql.tables_priv WHERE User = %q AND Host = %q AND DB = %q AND Table_name = %q`,
+			name, host, dbName, tableName)
+	case exists:
+		_, err = execRestrictedSQL(ctx, `UPDATE mysql.tables_priv SET Grantor = %q, Table_priv = %q, Column_priv = %q WHERE User = %q AND Host = %q AND DB = %q AND Table_name = %q`,
+			currentUser(ctx), privs.SetString(), colUnion.SetString(), name, host, dbName, tableName)
+	default:
+		_, err = execRestrictedSQL(ctx, `INSERT INTO mysql.tables_priv (Host, DB, User, Table_name, Grantor, Table_priv, Column_priv) VALUES (%q, %q, %q, %q, %q, %q, %q)`,
+			host, dbName, name, tableName, currentUser(ctx), privs.SetString(), colUnion.SetString())
+	}
+	return errors.Trace(err)
+}
+
+// changePrivs reads the privileges of the account name@host on the level l and returns the function writing
+// the privileges after granting or revoking privs and colPrivs. The function is called after all the accounts
+// of a statement are checked, so a failed statement changes nothing.
+func (l *GrantLevel) changePrivs(ctx context.Context, name, host string, privs mysql.PrivilegeType, cols []string,
+	colPrivs map[string]mysql.PrivilegeType, revoke bool) (func() error, error) {
+	switch l.Level {
+	case GrantLevelGlobal:
+		value := "Y"
+		if revoke {
+			value = "N"
+		}
+		return func() error {
+			if privs == 0 {
+				return nil
+			}
+			_, err := execRestrictedSQL(ctx, `UPDATE mysql.user SET %s WHERE User = %q AND Host = %q`,
+				privAssignments(privs, value), name, host)
+			return errors.Trace(err)
+		}, nil
+	case GrantLevelDB:
+		old, exists, err := dbPrivs(ctx, name, host, l.DBName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if revoke && !exists {
+			return nil, mysql.NewDefaultError(mysql.ErNonexistingGrant, name, host)
+		}
+		p := old | privs
+		if revoke {
+			p = old &^ privs
+		}
+		return func() error {
+			return setDBPrivs(ctx, name, host, l.DBName, p, exists)
+		}, nil
+	}
+
+	old, oldColPrivs, exists, err := tablePrivs(ctx, name, host, l.DBName, l.TableName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	newColPrivs := make(map[string]mysql.PrivilegeType, len(oldColPrivs))
+	for col, p := range oldColPrivs {
+		newColPrivs[col] = p
+	}
+	p := old | privs
+	if revoke {
+		if !exists {
+			return nil, mysql.NewDefaultError(mysql.ErNonexistingTableGrant, name, host, l.TableName)
+		}
+		p = old &^ privs
+		// Revoking a table privilege revokes it on all the columns too.
+		if privs&mysql.AllColumnPrivs != 0 {
+			for col := range oldColPrivs {
+				if _, ok := colPrivs[col]; !ok {
+					cols = append(cols, col)
+				}
+			}
+		}
+	}
+	for _, col := range cols {
+		if revoke {
+			if _, ok := oldColPrivs[col]; !ok && colPrivs[col] != 0 {
+				return nil, mysql.NewDefaultError(mysql.ErNonexistingTableGrant, name, host, l.TableName)
+			}
+			newColPrivs[col] &^= colPrivs[col] | privs
+		} else {
+			newColPrivs[col] |= colPrivs[col]
+		}
+	}
+	for col, p := range newColPrivs {
+		if p == 0 {
+			delete(newColPrivs, col)
+		}
+	}
+	return func() error {
+		return setTablePrivs(ctx, name, host, l.DBName, l.TableName, p, exists, cols, oldColPrivs, newColPrivs)
+	}, nil
+}
+
+// incPrivilegeVersion makes the cached privileges of all the sessions reload after the transaction of ctx commits.
+func incPrivilegeVersion(ctx context.Context) error {
+	txn, err := ctx.GetTxn(false)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = meta.IncPrivilegeVersion(txn)
+	return errors.Trace(err)
+}
+
+// dropPrivileges removes the privileges of the account name@host on databases, tables and columns.
+func dropPrivileges(ctx context.Context, name, host string) error {
+	for _, t := range []string{mysql.DBTable, mysql.TablePrivTable, mysql.ColumnPrivTable} {
+		_, err := execRestrictedSQL(ctx, `DELETE FROM %s.%s WHERE User = %q AND Host = %q`, mysql.SystemDB, t, name, host)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// GrantStmt grants privileges to accounts, the accounts are created if they don't exist and have IDENTIFIED BY clauses.
+// See: https://dev.mysql.com/doc/refman/5.7/en/grant.html
+type GrantStmt struct {
+	Privs     []*PrivElem
+	Level     *GrantLevel
+	Users     []*UserSpecification
+	WithGrant bool
+
+	Text string
+}
+
+// Explain implements the stmt.Statement Explain interface.
+func (s *GrantStmt) Explain(ctx context.Context, w format.Formatter) {
+	w.Format("%s\n", s.Text)
+}
+
+// IsDDL implements the stmt.Statement IsDDL interface.
+func (s *GrantStmt) IsDDL() bool {
+	return true
+}
+
+// OriginText implements the stmt.Statement OriginText interface.
+func (s *GrantStmt) OriginText() string {
+	return s.Text
+}
+
+// SetText implements the stmt.Statement SetText interface.
+func (s *GrantStmt) SetText(text string) {
+	s.Text = text
+}
+
+// Exec implements the stmt.Statement Exec interface.
+func (s *GrantStmt) Exec(ctx context.Context) (rset.Recordset, error) {
+	level, err := s.Level.resolve(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	privs, cols, colPrivs, err := level.privileges(ctx, s.Privs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if s.WithGrant {
+		privs |= mysql.GrantPriv
+	}
+
+	var writes []func() error
+	for _, spec := range s.Users {
+		name, host := splitUser(spec.User)
+		exists, err := userExists(ctx, name, host)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !exists && spec.AuthOpt == nil {
+			return nil, mysql.NewDefaultError(mysql.ErCantCreateUserWithGrant)
+		}
+		pwd, err := spec.AuthOpt.encodePassword()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		write, err := level.changePrivs(ctx, name, host, privs, cols, colPrivs, false)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		setPwd := spec.AuthOpt != nil
+		writes = append(writes, func() error {
+			var err error
+			if !exists {
+				_, err = execRestrictedSQL(ctx, `INSERT INTO mysql.user (Host, User, Password) VALUES (%q, %q, %q)`, host, name, pwd)
+			} else if setPwd {
+				err = setPassword(ctx, name, host, pwd)
+			}
+			if err != nil {
+				return errors.Trace(err)
+			}
+			return write()
+		})
+	}
+
+	for _, write := range writes {
+		if err = write(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return nil, errors.Trace(incPrivilegeVersion(ctx))
+}
+
+// RevokeStmt revokes privileges from accounts.
+// See: https://dev.mysql.com/doc/refman/5.7/en/revoke.html
+type RevokeStmt struct {
+	Privs []*PrivElem
+	Level *GrantLevel
+	// Users are the accounts in the user@host form.
+	Users []string
+
+	Text string
+}
+
+// Explain implements the stmt.Statement Explain interface.
+func (s *RevokeStmt) Explain(ctx context.Context, w format.Formatter) {
+	w.Format("%s\n", s.Text)
+}
+
+// IsDDL implements the stmt.Statement IsDDL interface.
+func (s *RevokeStmt) IsDDL() bool {
+	return true
+}
+
+// OriginText implements the stmt.Statement OriginText interface.
+func (s *RevokeStmt) OriginText() string {
+	return s.Text
+}
+
+// SetText implements the stmt.Statement SetText interface.
+func (s *RevokeStmt) SetText(text string) {
+	s.Text = text
+}
+
+// Exec implements the stmt.Statement Exec interface.
+func (s *RevokeStmt) Exec(ctx context.Context) (rset.Recordset, error) {
+	level, err := s.Level.resolve(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	privs, cols, colPrivs, err := level.privileges(ctx, s.Privs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var writes []func() error
+	for _, user := range s.Users {
+		name, host := splitUser(user)
+		exists, err := userExists(ctx, name, host)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !exists {
+			return nil, mysql.NewDefaultError(mysql.ErNonexistingGrant, name, host)
+		}
+		write, err := level.changePrivs(ctx, name, host, privs, cols, colPrivs, true)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		writes = append(writes, write)
+	}
+
+	for _, write := range writes {
+		if err = write(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return nil, errors.Trace(incPrivilegeVersion(ctx))
+}
@@ -0,0 +1,137 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmts_test
+
+import (
+	"github.com/juju/errors"
+	. "github.com/pingcap/check"
+	"github.com/Dong-Chan/alloydb"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/stmt/stmts"
+)
+
+func (s *testStmtSuite) queryTablePriv(c *C, name, host, tableName string) (tablePriv, columnPriv string) {
+	err := s.testDB.QueryRow(`SELECT Table_priv, Column_priv FROM mysql.tables_priv WHERE User = ? AND Host = ? AND DB = ? AND Table_name = ?`,
+		name, host, s.dbName, tableName).Scan(&tablePriv, &columnPriv)
+	c.Assert(err, IsNil)
+	return
+}
+
+func (s *testStmtSuite) queryDBPriv(c *C, name, host, column string) string {
+	var priv string
+	err := s.testDB.QueryRow(`SELECT `+column+` FROM mysql.db WHERE User = ? AND Host = ? AND DB = ?`, name, host, s.dbName).Scan(&priv)
+	c.Assert(err, IsNil)
+	return priv
+}
+
+func (s *testStmtSuite) TestGrantStmt(c *C) {
+	mustExec(c, s.testDB, s.createTableSql)
+
+	testSQL := `GRANT SELECT (id, name), INSERT ON test TO 'test'@'localhost' IDENTIFIED BY '123' WITH GRANT OPTION;`
+	stmtList, err := alloydb.Compile(testSQL)
+	c.Assert(err, IsNil)
+	c.Assert(stmtList, HasLen, 1)
+
+	testStmt, ok := stmtList[0].(*stmts.GrantStmt)
+	c.Assert(ok, IsTrue)
+	c.Assert(testStmt.IsDDL(), IsTrue)
+	c.Assert(len(testStmt.OriginText()), Greater, 0)
+	c.Assert(testStmt.Privs, HasLen, 2)
+	c.Assert(testStmt.Privs[0].Priv, Equals, mysql.SelectPriv)
+	c.Assert(testStmt.Privs[0].Cols, DeepEquals, []string{"id", "name"})
+	c.Assert(testStmt.Level.Level, Equals, stmts.GrantLevelTable)
+	c.Assert(testStmt.Users, HasLen, 1)
+	c.Assert(testStmt.WithGrant, IsTrue)
+
+	mf := newMockFormatter()
+	testStmt.Explain(nil, mf)
+	c.Assert(mf.Len(), Greater, 0)
+
+	// The account is created by GRANT with IDENTIFIED BY.
+	mustExec(c, s.testDB, testSQL)
+	c.Assert(s.userExists(c, "test", "localhost"), IsTrue)
+	tablePriv, columnPriv := s.queryTablePriv(c, "test", "localhost", "test")
+	c.Assert(tablePriv, Equals, "Insert,Grant")
+	c.Assert(columnPriv, Equals, "Select")
+
+	// Privileges are added to the existing grant.
+	mustExec(c, s.testDB, `GRANT UPDATE ON test.test TO 'test'@'localhost'`)
+	tablePriv, _ = s.queryTablePriv(c, "test", "localhost", "test")
+	c.Assert(tablePriv, Equals, "Insert,Update,Grant")
+
+	mustExec(c, s.testDB, `GRANT SELECT, DROP ON test.* TO 'test'@'localhost'`)
+	c.Assert(s.queryDBPriv(c, "test", "localhost", "Select_priv"), Equals, "Y")
+	c.Assert(s.queryDBPriv(c, "test", "localhost", "Drop_priv"), Equals, "Y")
+	c.Assert(s.queryDBPriv(c, "test", "localhost", "Insert_priv"), Equals, "N")
+
+	_, err = s.testDB.Exec(`GRANT SELECT ON test TO 'test1'`)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErCantCreateUserWithGrant))
+	c.Assert(s.userExists(c, "test1", "%"), IsFalse)
+
+	_, err = s.testDB.Exec(`GRANT SELECT ON not_exist TO 'test'@'localhost'`)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErNoSuchTable))
+
+	_, err = s.testDB.Exec(`GRANT SELECT (not_exist) ON test TO 'test'@'localhost'`)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErBadFieldError))
+
+	_, err = s.testDB.Exec(`GRANT CREATE USER ON test TO 'test'@'localhost'`)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErIllegalGrantForTable))
+
+	mustExec(c, s.testDB, `DROP USER 'test'@'localhost'`)
+}
+
+func (s *testStmtSuite) TestRevokeStmt(c *C) {
+	mustExec(c, s.testDB, s.createTableSql)
+	mustExec(c, s.testDB, `GRANT SELECT (name), INSERT, UPDATE ON test TO 'test' IDENTIFIED BY '123'`)
+
+	testSQL := `REVOKE INSERT ON test FROM 'test';`
+	stmtList, err := alloydb.Compile(testSQL)
+	c.Assert(err, IsNil)
+	c.Assert(stmtList, HasLen, 1)
+
+	testStmt, ok := stmtList[0].(*stmts.RevokeStmt)
+	c.Assert(ok, IsTrue)
+	c.Assert(testStmt.IsDDL(), IsTrue)
+	c.Assert(len(testStmt.OriginText()), Greater, 0)
+	c.Assert(testStmt.Users, DeepEquals, []string{"test@%"})
+
+	mf := newMockFormatter()
+	testStmt.Explain(nil, mf)
+	c.Assert(mf.Len(), Greater, 0)
+
+	mustExec(c, s.testDB, testSQL)
+	tablePriv, columnPriv := s.queryTablePriv(c, "test", "%", "test")
+	c.Assert(tablePriv, Equals, "Update")
+	c.Assert(columnPriv, Equals, "Select")
+
+	// Revoking on the table also revokes on its columns.
+	mustExec(c, s.testDB, `REVOKE SELECT ON test FROM 'test'`)
+	_, columnPriv = s.queryTablePriv(c, "test", "%", "test")
+	c.Assert(columnPriv, Equals, "")
+
+	_, err = s.testDB.Exec(`REVOKE SELECT ON test.* FROM 'test'`)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErNonexistingGrant))
+
+	_, err = s.testDB.Exec(`REVOKE DELETE ON test1 FROM 'test'`)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErNonexistingTableGrant))
+
+	_, err = s.testDB.Exec(`REVOKE SELECT ON test FROM 'test1'`)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErNonexistingGrant))
+
+	mustExec(c, s.testDB, `DROP USER 'test'`)
+	var cnt int
+	err = s.testDB.QueryRow(`SELECT COUNT(*) FROM mysql.tables_priv WHERE User = 'test'`).Scan(&cnt)
+	c.Assert(err, IsNil)
+	c.Assert(cnt, Equals, 0)
+}
@@ -50,6 +50,7 @@ type InsertIntoStmt struct {
 	TableIdent  table.Ident
 	Setlist     []*expressions.Assignment
 	Priority    int
+	Ignore      bool
 	OnDuplicate []expressions.Assignment
 
 	Text string
@@ -122,6 +123,16 @@ func (s *InsertIntoStmt) execSelect(t table.Table, cols []*column.Col, ctx conte
 	for i, r := range bufRecords {
 		variable.GetSessionVars(ctx).SetLastInsertID(lastInsertIds[i])
 
+		if s.Ignore {
+			dup, err := ignoreDuplicate(ctx, t, 0, r)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if dup {
+				continue
+			}
+		}
+
 		if _, err = t.AddRecord(ctx, r); err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -178,6 +189,16 @@ func (s *InsertIntoStmt) getColumns(tableCols []*column.Col) ([]*column.Col, err
 
 // Exec implements the stmt.Statement Exec interface.
 func (s *InsertIntoStmt) Exec(ctx context.Context) (_ rset.Recordset, err error) {
+	if s.Ignore {
+		// With IGNORE, the errors of invalid values are downgraded to warnings
+		// and the rows with duplicate keys are skipped.
+		sessionVars := variable.GetSessionVars(ctx)
+		sessionVars.IgnoreErrors = true
+		defer func() {
+			sessionVars.IgnoreErrors = false
+		}()
+	}
+
 	t, err := getTable(ctx, s.TableIdent)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -276,6 +297,15 @@ func (s *InsertIntoStmt) Exec(ctx context.Context) (_ rset.Recordset, err error)
 		// `t(id int AUTO_INCREMENT, c1 int, PRIMARY KEY (id))`
 		// `insert t (c1) values(1),(2),(3);`
 		// Last insert id will be 1, not 3.
+		if s.Ignore && len(s.OnDuplicate) == 0 {
+			dup, err := ignoreDuplicate(ctx, t, 0, r)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if dup {
+				continue
+			}
+		}
 		h, err := t.AddRecord(ctx, r)
 		if err == nil {
 			continue
@@ -0,0 +1,89 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmts
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/context"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/rset"
+	"github.com/Dong-Chan/alloydb/sessionctx"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/stmt"
+	"github.com/Dong-Chan/alloydb/util/format"
+)
+
+var _ stmt.Statement = (*KillStmt)(nil)
+
+// KillStmt is a statement to interrupt the running statement of a session, or to close the session.
+// See: https://dev.mysql.com/doc/refman/5.7/en/kill.html
+type KillStmt struct {
+	// Query is set for KILL QUERY, only the running statement of the session is interrupted.
+	Query        bool
+	ConnectionID uint64
+
+	Text string
+}
+
+// Explain implements the stmt.Statement Explain interface.
+func (s *KillStmt) Explain(ctx context.Context, w format.Formatter) {
+	w.Format("%s\n", s.Text)
+}
+
+// IsDDL implements the stmt.Statement IsDDL interface.
+func (s *KillStmt) IsDDL() bool {
+	return false
+}
+
+// OriginText implements the stmt.Statement OriginText interface.
+func (s *KillStmt) OriginText() string {
+	return s.Text
+}
+
+// SetText implements the stmt.Statement SetText interface.
+func (s *KillStmt) SetText(text string) {
+	s.Text = text
+}
+
+// Exec implements the stmt.Statement Exec interface.
+// The sessions without the SUPER privilege can only kill the sessions of their own user.
+// The statements check the kill flag while iterating rows, so the killed statement stops at the next row.
+func (s *KillStmt) Exec(ctx context.Context) (_ rset.Recordset, err error) {
+	do := sessionctx.GetDomain(ctx)
+	id := int64(s.ConnectionID)
+	p, ok := do.GetProcess(id)
+	if !ok {
+		return nil, mysql.NewDefaultError(mysql.ErNoSuchThread, s.ConnectionID)
+	}
+
+	if account := variable.GetSessionVars(ctx).User; len(account) > 0 {
+		i := strings.LastIndex(account, "@")
+		name, host := account[:i], account[i+1:]
+		privs, err := do.PrivilegeHandle().Get(ctx, do.InfoSchema())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !privs.RequestVerification(name, host, "", "", mysql.SuperPriv) && p.ProcessInfo().User != name {
+			return nil, mysql.NewDefaultError(mysql.ErKillDeniedError, s.ConnectionID)
+		}
+	}
+
+	p.Kill(s.Query)
+	if !s.Query {
+		do.RemoveProcess(id)
+	}
+	return nil, nil
+}
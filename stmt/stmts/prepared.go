@@ -206,9 +206,8 @@ func (s *ExecuteStmt) SetText(text string) {
 	s.Text = text
 }
 
-// Exec implements the stmt.Statement Exec interface.
-func (s *ExecuteStmt) Exec(ctx context.Context) (_ rset.Recordset, err error) {
-	// Get prepared statement.
+// Prepared gets the prepared statement to execute.
+func (s *ExecuteStmt) Prepared(ctx context.Context) (*PreparedStmt, error) {
 	vars := variable.GetSessionVars(ctx)
 	if len(s.Name) == 0 {
 		s.Name = getPreparedStmtIDKey(s.ID)
@@ -221,6 +220,15 @@ func (s *ExecuteStmt) Exec(ctx context.Context) (_ rset.Recordset, err error) {
 	if !ok {
 		return nil, errors.Errorf("Statement %s is not PreparedStmt, but %T", s.Name, vs)
 	}
+	return ps, nil
+}
+
+// Exec implements the stmt.Statement Exec interface.
+func (s *ExecuteStmt) Exec(ctx context.Context) (_ rset.Recordset, err error) {
+	ps, err := s.Prepared(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	// Fill param markers.
 	if len(s.UsingVars) != len(ps.Params) {
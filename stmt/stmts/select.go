@@ -18,8 +18,10 @@ import (
 	"github.com/ngaut/log"
 	"github.com/Dong-Chan/alloydb/context"
 	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/expression/expressions"
 	"github.com/Dong-Chan/alloydb/field"
 	"github.com/Dong-Chan/alloydb/parser/coldef"
+	"github.com/Dong-Chan/alloydb/parser/opcode"
 	"github.com/Dong-Chan/alloydb/plan"
 	"github.com/Dong-Chan/alloydb/plan/plans"
 	"github.com/Dong-Chan/alloydb/rset"
@@ -43,8 +45,10 @@ type SelectStmt struct {
 	Offset   *rsets.OffsetRset
 	OrderBy  *rsets.OrderByRset
 	Where    *rsets.WhereRset
+	Windows  []*expressions.WindowSpec
 	// TODO: rename Lock
 	Lock coldef.LockType
+	With *WithClause
 
 	Text string
 }
@@ -77,13 +81,24 @@ func (s *SelectStmt) SetText(text string) {
 
 // Plan implements the plan.Planner interface.
 // The whole phase for select is
-// `from -> where -> lock -> group by -> having -> select fields -> distinct -> order by -> limit -> final`
+// `from -> where -> lock -> group by -> having -> select fields -> window -> distinct -> order by -> limit -> final`
 func (s *SelectStmt) Plan(ctx context.Context) (plan.Plan, error) {
+	if s.With != nil {
+		return s.With.plan(ctx, s.plan)
+	}
+	return s.plan(ctx)
+}
+
+func (s *SelectStmt) plan(ctx context.Context) (plan.Plan, error) {
 	var (
 		r   plan.Plan
 		err error
 	)
 
+	if err = s.checkWindowFuncUse(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	if s.From != nil {
 		r, err = s.From.Plan(ctx)
 		if err != nil {
@@ -100,9 +115,23 @@ func (s *SelectStmt) Plan(ctx context.Context) (plan.Plan, error) {
 	}
 
 	if w := s.Where; w != nil {
-		r, err = (&rsets.WhereRset{Expr: w.Expr, Src: r}).Plan(ctx)
+		// The subqueries in where are planned as semi joins if possible,
+		// they are applied after the other conditions.
+		expr, semiJoins, err := planSemiJoins(ctx, w.Expr, r.GetFields())
 		if err != nil {
-			return nil, err
+			return nil, errors.Trace(err)
+		}
+
+		if expr != nil {
+			r, err = (&rsets.WhereRset{Expr: expr, Src: r}).Plan(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, sj := range semiJoins {
+			sj.Src = r
+			r = sj
 		}
 	}
 	lock := s.Lock
@@ -119,7 +148,7 @@ func (s *SelectStmt) Plan(ctx context.Context) (plan.Plan, error) {
 	}
 
 	// Get select list for futher field values evaluation.
-	selectList, err := plans.ResolveSelectList(s.Fields, r.GetFields())
+	selectList, err := plans.ResolveSelectList(ctx, s.Fields, r.GetFields())
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -129,6 +158,17 @@ func (s *SelectStmt) Plan(ctx context.Context) (plan.Plan, error) {
 		groupBy = s.GroupBy.By
 	}
 
+	// window functions are computed with all the rows after having,
+	// their arguments and windows will be added to hidden fields.
+	window := &rsets.WindowRset{Windows: s.Windows, SelectList: selectList}
+	var orderBy []rsets.OrderByItem
+	if s.OrderBy != nil {
+		orderBy = s.OrderBy.By
+	}
+	if err = window.CheckAndUpdateSelectList(selectList, orderBy, r.GetFields()); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	if s.Having != nil {
 		// `having` may contain aggregate functions, and we will add this to hidden fields.
 		if err = s.Having.CheckAndUpdateSelectList(selectList, groupBy, r.GetFields()); err != nil {
@@ -166,6 +206,11 @@ func (s *SelectStmt) Plan(ctx context.Context) (plan.Plan, error) {
 		}
 	}
 
+	window.Src = r
+	if r, err = window.Plan(ctx); err != nil {
+		return nil, err
+	}
+
 	if s.Distinct {
 		if r, err = (&rsets.DistinctRset{Src: r,
 			SelectList: selectList}).Plan(ctx); err != nil {
@@ -200,6 +245,21 @@ func (s *SelectStmt) Plan(ctx context.Context) (plan.Plan, error) {
 	return r, nil
 }
 
+// checkWindowFuncUse checks window functions are not used in where, group by and having clauses.
+func (s *SelectStmt) checkWindowFuncUse() error {
+	var exprs []expression.Expression
+	if s.Where != nil {
+		exprs = append(exprs, s.Where.Expr)
+	}
+	if s.GroupBy != nil {
+		exprs = append(exprs, s.GroupBy.By...)
+	}
+	if s.Having != nil {
+		exprs = append(exprs, s.Having.Expr)
+	}
+	return rsets.CheckNoWindowFunc(exprs...)
+}
+
 // Exec implements the stmt.Statement Exec interface.
 func (s *SelectStmt) Exec(ctx context.Context) (rs rset.Recordset, err error) {
 	log.Info("SelectStmt trx:")
@@ -210,3 +270,240 @@ func (s *SelectStmt) Exec(ctx context.Context) (rs rset.Recordset, err error) {
 
 	return rsets.Recordset{ctx, r}, nil
 }
+
+// planSemiJoins splits the where expression by AND, and plans the conditions like
+// "[NOT] EXISTS (select ...)" and "expr [NOT] IN (select ...)" as semi joins if possible.
+// It returns the other conditions and the semi joins.
+func planSemiJoins(ctx context.Context, where expression.Expression, outerFields []*field.ResultField) (expression.Expression, []*plans.SemiJoinPlan, error) {
+	var (
+		conds     []expression.Expression
+		semiJoins []*plans.SemiJoinPlan
+	)
+	for _, e := range splitConjuncts(where) {
+		sj, err := planSemiJoin(ctx, e, outerFields)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+
+		if sj != nil {
+			semiJoins = append(semiJoins, sj)
+		} else {
+			conds = append(conds, e)
+		}
+	}
+
+	if len(semiJoins) == 0 {
+		return where, nil, nil
+	}
+	return joinConjuncts(conds), semiJoins, nil
+}
+
+// planSemiJoin returns nil if the condition e can't be planned as a semi join.
+func planSemiJoin(ctx context.Context, e expression.Expression, outerFields []*field.ResultField) (*plans.SemiJoinPlan, error) {
+	switch x := e.(type) {
+	case *expressions.ExistsSubQuery:
+		return planExistsSemiJoin(ctx, x, false, outerFields)
+	case *expressions.UnaryOperation:
+		if es, ok := expressions.Expr(x.V).(*expressions.ExistsSubQuery); ok && x.Op == opcode.Not {
+			sj, err := planExistsSemiJoin(ctx, es, true, outerFields)
+			if sj != nil {
+				sj.Expr = x
+			}
+			return sj, errors.Trace(err)
+		}
+	case *expressions.PatternIn:
+		if x.Sel != nil {
+			return planInSemiJoin(ctx, x, outerFields)
+		}
+	}
+	return nil, nil
+}
+
+// planExistsSemiJoin plans "[NOT] EXISTS (select ... from t where t.c = outer.c and ...)",
+// the subquery must be correlated only by equal conditions.
+func planExistsSemiJoin(ctx context.Context, x *expressions.ExistsSubQuery, anti bool, outerFields []*field.ResultField) (*plans.SemiJoinPlan, error) {
+	sel, ok := x.Sel.Stmt.(*SelectStmt)
+	if !ok || x.Sel.Value != nil || rsets.HasAggFields(sel.Fields) {
+		return nil, nil
+	}
+
+	d, err := decorrelate(ctx, sel, outerFields)
+	if err != nil || d == nil || len(d.outerKeys) == 0 {
+		// An uncorrelated subquery is executed only once, there is no need to use semi join.
+		return nil, errors.Trace(err)
+	}
+
+	return d.plan(ctx, &plans.SemiJoinPlan{Anti: anti, Expr: x})
+}
+
+// planInSemiJoin plans "expr [NOT] IN (select c from t where ...)",
+// NOT IN can be planned only if the subquery is not correlated.
+func planInSemiJoin(ctx context.Context, x *expressions.PatternIn, outerFields []*field.ResultField) (*plans.SemiJoinPlan, error) {
+	sel, ok := x.Sel.(*SelectStmt)
+	if !ok || len(sel.Fields) != 1 || rsets.HasAggFields(sel.Fields) {
+		return nil, nil
+	}
+
+	if expressions.ContainSubQuery(x.Expr) || !field.ContainAllFieldNames(expressions.MentionedColumns(x.Expr), outerFields, field.DefaultFieldFlag) {
+		return nil, nil
+	}
+
+	d, err := decorrelate(ctx, sel, outerFields)
+	if err != nil || d == nil {
+		return nil, errors.Trace(err)
+	}
+
+	inner := sel.Fields[0].Expr
+	if !d.isInner(inner) || (x.Not && len(d.outerKeys) > 0) {
+		return nil, nil
+	}
+
+	// Like IN, the collation of the left expression is used, the values of the subquery have none.
+	m := map[interface{}]interface{}{expressions.ExprEvalCollationFunc: fieldsCollationFunc(outerFields)}
+	d.innerKeys = append(d.innerKeys, inner)
+	d.outerKeys = append(d.outerKeys, x.Expr)
+	d.collations = append(d.collations, expressions.Collation(x.Expr, m))
+	return d.plan(ctx, &plans.SemiJoinPlan{Anti: x.Not, NullAware: x.Not, Expr: x})
+}
+
+// decorrelated is a subquery whose where conditions are split into the conditions for the inner table
+// and the equal conditions between the inner and outer columns.
+type decorrelated struct {
+	sel         *SelectStmt
+	innerFields []*field.ResultField
+	conds       []expression.Expression
+	innerKeys   []expression.Expression
+	outerKeys   []expression.Expression
+	// collations are the collations to compare the inner and outer keys.
+	collations []string
+}
+
+// decorrelate returns nil if sel is not a simple select, or it references the outer columns
+// not in an equal condition.
+func decorrelate(ctx context.Context, sel *SelectStmt, outerFields []*field.ResultField) (*decorrelated, error) {
+	if sel.From == nil || sel.GroupBy != nil || sel.Having != nil || sel.Limit != nil || sel.Offset != nil {
+		return nil, nil
+	}
+
+	src, err := sel.From.Plan(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	d := &decorrelated{sel: sel, innerFields: src.GetFields()}
+	if sel.Where == nil {
+		return d, nil
+	}
+
+	// The column in both the inner and outer tables references the inner one.
+	m := map[interface{}]interface{}{expressions.ExprEvalCollationFunc: fieldsCollationFunc(d.innerFields, outerFields)}
+
+	for _, e := range splitConjuncts(sel.Where.Expr) {
+		if d.isInner(e) {
+			d.conds = append(d.conds, e)
+			continue
+		}
+
+		b, ok := e.(*expressions.BinaryOperation)
+		if !ok || b.Op != opcode.EQ {
+			return nil, nil
+		}
+
+		l, r := expressions.Expr(b.L), expressions.Expr(b.R)
+		switch {
+		case d.isInner(l) && d.isOuter(r, outerFields):
+			d.innerKeys = append(d.innerKeys, l)
+			d.outerKeys = append(d.outerKeys, r)
+		case d.isOuter(l, outerFields) && d.isInner(r):
+			d.innerKeys = append(d.innerKeys, r)
+			d.outerKeys = append(d.outerKeys, l)
+		default:
+			return nil, nil
+		}
+		d.collations = append(d.collations, expressions.CompareCollation(l, r, m))
+	}
+	return d, nil
+}
+
+// fieldsCollationFunc returns the function to get the collation of a column in the lists of fields,
+// it is saved by expressions.ExprEvalCollationFunc. The first list having the column is used.
+func fieldsCollationFunc(lists ...[]*field.ResultField) func(string) string {
+	return func(name string) string {
+		for _, fields := range lists {
+			if indices := field.GetResultFieldIndex(name, fields, field.DefaultFieldFlag); len(indices) > 0 {
+				return fields[indices[0]].Collate
+			}
+		}
+		return ""
+	}
+}
+
+// isInner checks whether e only references the inner columns.
+func (d *decorrelated) isInner(e expression.Expression) bool {
+	if expressions.ContainSubQuery(e) || expressions.ContainAggregateFunc(e) {
+		return false
+	}
+
+	return field.ContainAllFieldNames(expressions.MentionedColumns(e), d.innerFields, field.DefaultFieldFlag)
+}
+
+// isOuter checks whether e references the outer columns only.
+func (d *decorrelated) isOuter(e expression.Expression, outerFields []*field.ResultField) bool {
+	if expressions.ContainSubQuery(e) || expressions.ContainAggregateFunc(e) {
+		return false
+	}
+
+	names := expressions.MentionedColumns(e)
+	if len(names) == 0 {
+		return false
+	}
+
+	for _, name := range names {
+		// The column in both the inner and outer tables references the inner one.
+		if field.ContainFieldName(name, d.innerFields, field.DefaultFieldFlag) {
+			return false
+		}
+	}
+	return field.ContainAllFieldNames(names, outerFields, field.DefaultFieldFlag)
+}
+
+// plan plans the subquery which selects the inner keys, and sets it as the inner plan of sj.
+func (d *decorrelated) plan(ctx context.Context, sj *plans.SemiJoinPlan) (*plans.SemiJoinPlan, error) {
+	inner := &SelectStmt{From: d.sel.From}
+	for _, e := range d.innerKeys {
+		inner.Fields = append(inner.Fields, &field.Field{Expr: e, Name: e.String()})
+	}
+	if where := joinConjuncts(d.conds); where != nil {
+		inner.Where = &rsets.WhereRset{Expr: where}
+	}
+
+	p, err := inner.Plan(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	sj.Inner, sj.OuterKeys, sj.Collations = p, d.outerKeys, d.collations
+	return sj, nil
+}
+
+// splitConjuncts splits expression e by AND.
+func splitConjuncts(e expression.Expression) []expression.Expression {
+	e = expressions.Expr(e)
+	if b, ok := e.(*expressions.BinaryOperation); ok && b.Op == opcode.AndAnd {
+		return append(splitConjuncts(b.L), splitConjuncts(b.R)...)
+	}
+	return []expression.Expression{e}
+}
+
+// joinConjuncts joins the expressions by AND, it returns nil for empty list.
+func joinConjuncts(list []expression.Expression) expression.Expression {
+	var e expression.Expression
+	for _, x := range list {
+		if e == nil {
+			e = x
+		} else {
+			e = expressions.NewBinaryOperation(opcode.AndAnd, e, x)
+		}
+	}
+	return e
+}
@@ -45,6 +45,21 @@ func (s *testStmtSuite) TestSelectExplain(c *C) {
 	}
 }
 
+func (s *testStmtSuite) TestSelectGroupByExplain(c *C) {
+	s.fillData(s.testDB, c)
+
+	strs := s.queryStrings(s.testDB, "explain select id, count(*) from test group by id;", c)
+	// Must aggregate the rows in the order of index
+	if strings.Index(strings.Join(strs, "\n"), "Aggregate the ordered rows") < 0 {
+		c.Fatalf("Should aggregate the ordered rows")
+	}
+
+	strs = s.queryStrings(s.testDB, "explain select name, count(*) from test group by name;", c)
+	if strings.Index(strings.Join(strs, "\n"), "Aggregate the ordered rows") >= 0 {
+		c.Fatalf("Should not aggregate the ordered rows")
+	}
+}
+
 func (s *testStmtSuite) TestSelectOrderBy(c *C) {
 	s.fillData(s.testDB, c)
 
@@ -22,9 +22,11 @@ import (
 	"github.com/Dong-Chan/alloydb/context"
 	"github.com/Dong-Chan/alloydb/expression"
 	"github.com/Dong-Chan/alloydb/expression/expressions"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
 	"github.com/Dong-Chan/alloydb/rset"
 	"github.com/Dong-Chan/alloydb/sessionctx/variable"
 	"github.com/Dong-Chan/alloydb/stmt"
+	"github.com/Dong-Chan/alloydb/util/auth"
 	"github.com/Dong-Chan/alloydb/util/format"
 )
 
@@ -96,6 +98,7 @@ func (s *SetStmt) SetText(text string) {
 }
 
 // Exec implements the stmt.Statement Exec interface.
+// A global value is saved in the store, it is used by the sessions created later.
 func (s *SetStmt) Exec(ctx context.Context) (_ rset.Recordset, err error) {
 	log.Debug("Set sys/user variables")
 
@@ -104,64 +107,99 @@ func (s *SetStmt) Exec(ctx context.Context) (_ rset.Recordset, err error) {
 	for _, v := range s.Variables {
 		// Variable is case insensitive, we use lower case.
 		name := strings.ToLower(v.Name)
+		value, err := v.getValue(ctx)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 		if !v.IsSystem {
 			// User variable.
-			value, err := v.getValue(ctx)
-			if err != nil {
-				return nil, errors.Trace(err)
-			}
-
 			if value == nil {
 				delete(sessionVars.Users, name)
 			} else {
 				sessionVars.Users[name] = fmt.Sprintf("%v", value)
 			}
-			return nil, nil
+			continue
 		}
 
-		// TODO: should get global sys var from db.
 		sysVar := variable.GetSysVar(name)
 		if sysVar == nil {
-			return nil, errors.Errorf("Unknown system variable '%s'", name)
+			return nil, mysql.NewDefaultError(mysql.ErUnknownSystemVariable, name)
 		}
 		if sysVar.Scope == variable.ScopeNone {
-			return nil, errors.Errorf("Variable '%s' is a read only variable", name)
+			return nil, mysql.NewDefaultError(mysql.ErIncorrectGlobalLocalVar, name, "read only")
+		}
+		if v.IsGlobal && sysVar.Scope&variable.ScopeGlobal == 0 {
+			return nil, mysql.NewDefaultError(mysql.ErLocalVariable, name)
+		}
+		if !v.IsGlobal && sysVar.Scope&variable.ScopeSession == 0 {
+			return nil, mysql.NewDefaultError(mysql.ErGlobalVariable, name)
 		}
 
-		if v.IsGlobal {
-			if sysVar.Scope&variable.ScopeGlobal > 0 {
-				value, err := v.getValue(ctx)
-				if err != nil {
-					return nil, errors.Trace(err)
-				}
-				if value == nil {
-					sysVar.Value = ""
-				} else {
-					// TODO: set global variables in db, now we only change memory global sys var map.
-					// TODO: check sys variable type if possible.
-					sysVar.Value = fmt.Sprintf("%v", value)
-				}
-				return nil, nil
-			}
-			return nil, errors.Errorf("Variable '%s' is a SESSION variable and can't be used with SET GLOBAL", name)
+		str, err := validateSysVar(ctx, sysVar, value)
+		if err != nil {
+			return nil, errors.Trace(err)
 		}
-		if sysVar.Scope&variable.ScopeSession > 0 {
-			if value, err := v.getValue(ctx); err != nil {
+		if !v.IsGlobal {
+			if err = sessionVars.SetSystemVar(name, str); err != nil {
 				return nil, errors.Trace(err)
-			} else if value == nil {
-				sysVar.Value = ""
-			} else {
-				// TODO: check sys variable type if possible.
-				sessionVars.Systems[name] = fmt.Sprintf("%v", value)
 			}
-			return nil, nil
+			continue
+		}
+		accessor := variable.GetGlobalVarAccessor(ctx)
+		if accessor == nil {
+			return nil, errors.Errorf("can not set global variable in context %T", ctx)
+		}
+		if err = accessor.SetGlobalSysVar(ctx, name, str); err != nil {
+			return nil, errors.Trace(err)
 		}
-		return nil, errors.Errorf("Variable '%s' is a GLOBAL variable and should be set with SET GLOBAL", name)
 	}
 
 	return nil, nil
 }
 
+// validateSysVar checks value for sysVar and returns it in the normal form.
+// NULL is only allowed for a variable without type, it sets the variable to the empty string.
+func validateSysVar(ctx context.Context, sysVar *variable.SysVar, value interface{}) (string, error) {
+	if value == nil {
+		if sysVar.Type != variable.TypeStr {
+			return "", mysql.NewDefaultError(mysql.ErWrongValueForVar, sysVar.Name, "NULL")
+		}
+		return "", nil
+	}
+	str := fmt.Sprintf("%v", value)
+	switch sysVar.Name {
+	case variable.SQLModeVar:
+		mode, err := getSQLMode(sysVar.Name, str)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		return mode.String(), nil
+	case variable.TimeZone:
+		if _, err := mysql.ParseTimeZone(str); err != nil {
+			return "", errors.Trace(err)
+		}
+		return str, nil
+	}
+	normalized, truncated, err := sysVar.Validate(str)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if truncated {
+		variable.AppendWarning(ctx, mysql.NewDefaultError(mysql.ErTruncatedWrongValue, sysVar.Name, str))
+	}
+	return normalized, nil
+}
+
+// getSQLMode parses the value of sql_mode, the names of the modes must be valid.
+func getSQLMode(name string, value interface{}) (mysql.SQLMode, error) {
+	str := fmt.Sprintf("%v", value)
+	mode, err := mysql.GetSQLMode(str)
+	if err != nil {
+		return mysql.ModeNone, mysql.NewDefaultError(mysql.ErWrongValueForVar, name, str)
+	}
+	return mode, nil
+}
+
 // SetCharsetStmt is a statement to assign values to character and collation variables.
 // See: https://dev.mysql.com/doc/refman/5.7/en/set-statement.html
 type SetCharsetStmt struct {
@@ -202,7 +240,9 @@ func (s *SetCharsetStmt) Exec(ctx context.Context) (_ rset.Recordset, err error)
 // SetPwdStmt is a statement to assign a password to user account.
 // See: https://dev.mysql.com/doc/refman/5.7/en/set-password.html
 type SetPwdStmt struct {
-	User     string
+	// User is the account in the user@host form, it is empty for the current user.
+	User string
+	// Password is the plain password.
 	Password string
 
 	Text string
@@ -230,6 +270,22 @@ func (s *SetPwdStmt) SetText(text string) {
 
 // Exec implements the stmt.Statement Exec interface.
 func (s *SetPwdStmt) Exec(ctx context.Context) (_ rset.Recordset, err error) {
-	// TODO: finish this
-	return nil, nil
+	user := s.User
+	if len(user) == 0 {
+		user = currentUser(ctx)
+		if len(user) == 0 {
+			return nil, mysql.NewDefaultError(mysql.ErPasswordAnonymousUser)
+		}
+	}
+
+	name, host := splitUser(user)
+	exists, err := userExists(ctx, name, host)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !exists {
+		return nil, mysql.NewDefaultError(mysql.ErPasswordNoMatch)
+	}
+	err = setPassword(ctx, name, host, auth.EncodePassword(s.Password))
+	return nil, errors.Trace(err)
 }
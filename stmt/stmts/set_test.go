@@ -14,9 +14,12 @@
 package stmts_test
 
 import (
+	"github.com/juju/errors"
 	. "github.com/pingcap/check"
 	"github.com/Dong-Chan/alloydb"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
 	"github.com/Dong-Chan/alloydb/stmt/stmts"
+	"github.com/Dong-Chan/alloydb/util/auth"
 )
 
 func (s *testStmtSuite) TestSet(c *C) {
@@ -62,8 +65,8 @@ func (s *testStmtSuite) TestSet(c *C) {
 	testStmt.Explain(nil, mf)
 	c.Assert(mf.Len(), Greater, 0)
 
-	testSQL = "SET @@global.autocommit = null;"
-	mustExec(c, s.testDB, testSQL)
+	_, err = s.testDB.Exec("SET @@global.autocommit = null;")
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErWrongValueForVar))
 
 	testSQL = "SET @@autocommit = 1;"
 	mustExec(c, s.testDB, testSQL)
@@ -83,8 +86,8 @@ func (s *testStmtSuite) TestSet(c *C) {
 	testStmt.Explain(nil, mf)
 	c.Assert(mf.Len(), Greater, 0)
 
-	testSQL = "SET @@autocommit = null;"
-	mustExec(c, s.testDB, testSQL)
+	_, err = s.testDB.Exec("SET @@autocommit = null;")
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErWrongValueForVar))
 
 	errTestSql := "SET @@date_format = 1;"
 	tx := mustBegin(c, s.testDB)
@@ -115,6 +118,33 @@ func (s *testStmtSuite) TestSet(c *C) {
 	_, err = tx.Exec(errTestSql)
 	c.Assert(err, NotNil)
 	tx.Rollback()
+
+	tbl := []struct {
+		sql  string
+		code int
+	}{
+		{"SET @@global.timestamp = 1", mysql.ErLocalVariable},
+		{"SET @@max_connections = 1", mysql.ErGlobalVariable},
+		{"SET @@warning_count = 1", mysql.ErIncorrectGlobalLocalVar},
+		{"SET @@global.xxx = 1", mysql.ErUnknownSystemVariable},
+		{"SET @@global.max_connections = 'abc'", mysql.ErWrongTypeForVar},
+		{"SET @@tx_isolation = 'abc'", mysql.ErWrongValueForVar},
+		{"SET @@global.autocommit = 2", mysql.ErWrongValueForVar},
+	}
+	for _, t := range tbl {
+		_, err = s.testDB.Exec(t.sql)
+		c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(t.code), Commentf("%s", t.sql))
+	}
+
+	// All the variables are set.
+	tx = mustBegin(c, s.testDB)
+	_, err = tx.Exec("SET @a = 1, @@tx_isolation = 'read-committed', @b = 2")
+	c.Assert(err, IsNil)
+	var a, b, isolation string
+	err = tx.QueryRow("SELECT @a, @@tx_isolation, @b").Scan(&a, &isolation, &b)
+	c.Assert(err, IsNil)
+	tx.Rollback()
+	c.Assert([]string{a, isolation, b}, DeepEquals, []string{"1", "READ-COMMITTED", "2"})
 }
 
 func (s *testStmtSuite) TestSetCharsetStmt(c *C) {
@@ -139,21 +169,33 @@ func (s *testStmtSuite) TestSetCharsetStmt(c *C) {
 }
 
 func (s *testStmtSuite) TestSetPwdStmt(c *C) {
-	// Mock SetPwdStmt.
-	testStmt := &stmts.SetPwdStmt{
-		User:     "user",
-		Password: "password",
-	}
+	testSQL := `SET PASSWORD FOR 'pwduser'@'localhost' = PASSWORD('password');`
+	stmtList, err := alloydb.Compile(testSQL)
+	c.Assert(err, IsNil)
+	c.Assert(stmtList, HasLen, 1)
 
-	testStmt.SetText(`SET PASSWORD FOR 'user' = PASSWORD('password');`)
+	testStmt, ok := stmtList[0].(*stmts.SetPwdStmt)
+	c.Assert(ok, IsTrue)
+	c.Assert(testStmt.User, Equals, "pwduser@localhost")
+	c.Assert(testStmt.Password, Equals, "password")
 
 	c.Assert(testStmt.IsDDL(), IsFalse)
 	c.Assert(len(testStmt.OriginText()), Greater, 0)
 
-	_, err := testStmt.Exec(nil)
-	c.Assert(err, IsNil)
-
 	mf := newMockFormatter()
 	testStmt.Explain(nil, mf)
 	c.Assert(mf.Len(), Greater, 0)
+
+	mustExec(c, s.testDB, `CREATE USER 'pwduser'@'localhost'`)
+	mustExec(c, s.testDB, testSQL)
+	c.Assert(s.queryPassword(c, "pwduser", "localhost"), Equals, auth.EncodePassword("password"))
+	mustExec(c, s.testDB, `SET PASSWORD FOR 'pwduser'@'localhost' = ''`)
+	c.Assert(s.queryPassword(c, "pwduser", "localhost"), Equals, "")
+
+	// The account must exist, and an unauthenticated session has no account.
+	_, err = s.testDB.Exec(`SET PASSWORD FOR 'pwduser' = 'password'`)
+	c.Assert(err, NotNil)
+	_, err = s.testDB.Exec(`SET PASSWORD = 'password'`)
+	c.Assert(err, NotNil)
+	mustExec(c, s.testDB, `DROP USER 'pwduser'@'localhost'`)
 }
@@ -16,6 +16,8 @@ package stmts
 import (
 	"github.com/ngaut/log"
 	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/expression"
+	"github.com/Dong-Chan/alloydb/expression/expressions"
 	"github.com/Dong-Chan/alloydb/rset"
 	"github.com/Dong-Chan/alloydb/rset/rsets"
 	"github.com/Dong-Chan/alloydb/stmt"
@@ -32,8 +34,17 @@ type ShowStmt struct {
 	DBName     string
 	TableIdent table.Ident // Used for showing columns.
 	ColumnName string      // Used for `desc table column`.
+	User       string      // Used for `show grants for user`, in the user@host form.
 	Flag       int         // Some flag parsed from sql, such as FULL.
 	Full       bool
+	// CountWarnings is set for SHOW COUNT(*) WARNINGS and SHOW COUNT(*) ERRORS.
+	CountWarnings bool
+	// GlobalScope is set for SHOW GLOBAL VARIABLES and SHOW GLOBAL STATUS.
+	GlobalScope bool
+	// Pattern is the LIKE clause, its Expr is the first column of the result.
+	Pattern *expressions.PatternLike
+	// Where is the WHERE clause.
+	Where expression.Expression
 
 	Text string
 }
@@ -62,13 +73,24 @@ func (s *ShowStmt) SetText(text string) {
 func (s *ShowStmt) Exec(ctx context.Context) (_ rset.Recordset, err error) {
 	// TODO: finish this
 	log.Debug("Exec Show Stmt")
+	dbName := s.DBName
+	if len(dbName) == 0 {
+		// Like `show index from db.t`.
+		dbName = s.TableIdent.Schema.O
+	}
 	sr := &rsets.ShowRset{
 		Target:     s.Target,
-		DBName:     s.DBName,
+		DBName:     dbName,
 		TableName:  s.TableIdent.Name.O,
 		ColumnName: s.ColumnName,
 		Flag:       s.Flag,
 		Full:       s.Full,
+		User:       s.User,
+
+		CountWarnings: s.CountWarnings,
+		GlobalScope:   s.GlobalScope,
+		Pattern:       s.Pattern,
+		Where:         s.Where,
 	}
 
 	r, err := sr.Plan(ctx)
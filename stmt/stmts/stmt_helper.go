@@ -14,13 +14,18 @@
 package stmts
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/juju/errors"
 	"github.com/Dong-Chan/alloydb/column"
 	"github.com/Dong-Chan/alloydb/context"
 	"github.com/Dong-Chan/alloydb/expression/expressions"
 	mysql "github.com/Dong-Chan/alloydb/mysqldef"
 	"github.com/Dong-Chan/alloydb/sessionctx"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
 	"github.com/Dong-Chan/alloydb/table"
+	"github.com/Dong-Chan/alloydb/util/types"
 )
 
 func getDefaultValue(ctx context.Context, c *column.Col) (interface{}, bool, error) {
@@ -46,7 +51,71 @@ func getDefaultValue(ctx context.Context, c *column.Col) (interface{}, bool, err
 	return c.DefaultValue, true, nil
 }
 
+// colCollationFunc returns the function to get the collations of the columns cols by name,
+// it is saved by expressions.ExprEvalCollationFunc to compare the column values.
+func colCollationFunc(cols []*column.Col) func(string) string {
+	return func(name string) string {
+		if col := column.FindCol(cols, name); col != nil {
+			return col.Collate
+		}
+		return ""
+	}
+}
+
 func getTable(ctx context.Context, tableIdent table.Ident) (table.Table, error) {
 	full := tableIdent.Full(ctx)
 	return sessionctx.GetDomain(ctx).InfoSchema().TableByName(full.Schema, full.Name)
 }
+
+// ignoreDuplicate is used by INSERT IGNORE and UPDATE IGNORE, it checks whether row has the same
+// unique index values as a row other than the row of handle h, 0 for a new row. If so, a duplicate
+// entry warning is appended and true is returned, the caller should skip the row.
+func ignoreDuplicate(ctx context.Context, t table.Table, h int64, row []interface{}) (bool, error) {
+	txn, err := ctx.GetTxn(false)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+
+	for _, idx := range t.Indices() {
+		if idx == nil || !idx.Unique {
+			continue
+		}
+
+		vals, err := idx.FetchValues(row)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+
+		iter, hit, err := idx.X.Seek(txn, vals)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if !hit {
+			iter.Close()
+			continue
+		}
+
+		_, dupHandle, err := iter.Next()
+		iter.Close()
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if dupHandle == h {
+			continue
+		}
+
+		strs := make([]string, 0, len(vals))
+		for _, v := range vals {
+			s, err := types.ToString(v)
+			if err != nil {
+				s = fmt.Sprint(v)
+			}
+			strs = append(strs, s)
+		}
+		msg := fmt.Sprintf("Duplicate entry '%s' for key '%s'", strings.Join(strs, "-"), idx.Name.O)
+		variable.AppendWarning(ctx, mysql.NewError(mysql.ErDupEntry, msg))
+		return true, nil
+	}
+
+	return false, nil
+}
@@ -29,6 +29,7 @@ var _ stmt.Statement = (*UnionStmt)(nil)
 type UnionStmt struct {
 	Distincts []bool
 	Selects   []*SelectStmt
+	With      *WithClause
 
 	Text string
 }
@@ -55,6 +56,13 @@ func (s *UnionStmt) SetText(text string) {
 
 // Plan implements the plan.Planner interface.
 func (s *UnionStmt) Plan(ctx context.Context) (plan.Plan, error) {
+	if s.With != nil {
+		return s.With.plan(ctx, s.plan)
+	}
+	return s.plan(ctx)
+}
+
+func (s *UnionStmt) plan(ctx context.Context) (plan.Plan, error) {
 	var r plan.Plan
 	var err error
 	srcs := make([]plan.Plan, 0, len(s.Selects))
@@ -44,6 +44,7 @@ type UpdateStmt struct {
 	Limit       *rsets.LimitRset
 	LowPriority bool
 	Ignore      bool
+	With        *WithClause
 
 	Text string
 }
@@ -101,6 +102,7 @@ func (s *UpdateStmt) hitWhere(ctx context.Context, t table.Table, data []interfa
 	for _, col := range t.Cols() {
 		m[col.Name.L] = data[col.Offset]
 	}
+	m[expressions.ExprEvalCollationFunc] = colCollationFunc(t.Cols())
 
 	ok, err := expressions.EvalBoolExpr(ctx, s.Where, m)
 	if err != nil {
@@ -134,6 +136,7 @@ func updateRecord(ctx context.Context, h int64, data []interface{}, t table.Tabl
 	for _, col := range t.Cols() {
 		m[col.Name.L] = data[col.Offset]
 	}
+	m[expressions.ExprEvalCollationFunc] = colCollationFunc(t.Cols())
 
 	if insertData != nil {
 		m[expressions.ExprEvalValuesFunc] = func(name string) (interface{}, error) {
@@ -180,6 +183,13 @@ func updateRecord(ctx context.Context, h int64, data []interface{}, t table.Tabl
 		return nil
 	}
 
+	if variable.GetSessionVars(ctx).IgnoreErrors {
+		dup, err := ignoreDuplicate(ctx, t, h, data)
+		if err != nil || dup {
+			return errors.Trace(err)
+		}
+	}
+
 	// Update record to new value and update index.
 	err := t.UpdateRecord(ctx, h, oldData, data, touched)
 	if err != nil {
@@ -250,6 +260,24 @@ func (s *UpdateStmt) tryUpdateUsingIndex(ctx context.Context, t table.Table) (bo
 
 // Exec implements the stmt.Statement Exec interface.
 func (s *UpdateStmt) Exec(ctx context.Context) (_ rset.Recordset, err error) {
+	if s.Ignore {
+		// With IGNORE, the errors of invalid values are downgraded to warnings
+		// and the rows which would cause duplicate keys are not updated.
+		sessionVars := variable.GetSessionVars(ctx)
+		sessionVars.IgnoreErrors = true
+		defer func() {
+			sessionVars.IgnoreErrors = false
+		}()
+	}
+
+	if s.With != nil {
+		release, err := s.With.exec(ctx)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		defer release()
+	}
+
 	t, err := getTable(ctx, s.TableIdent)
 	if err != nil {
 		return nil, err
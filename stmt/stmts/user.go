@@ -0,0 +1,304 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmts
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/Dong-Chan/alloydb/context"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/rset"
+	"github.com/Dong-Chan/alloydb/sessionctx/variable"
+	"github.com/Dong-Chan/alloydb/stmt"
+	"github.com/Dong-Chan/alloydb/util/auth"
+	"github.com/Dong-Chan/alloydb/util/format"
+	"github.com/Dong-Chan/alloydb/util/sqlexec"
+)
+
+var (
+	_ stmt.Statement = (*CreateUserStmt)(nil)
+	_ stmt.Statement = (*AlterUserStmt)(nil)
+	_ stmt.Statement = (*DropUserStmt)(nil)
+)
+
+// AuthOption is the IDENTIFIED BY clause of a user specification.
+type AuthOption struct {
+	// ByAuthString is true for IDENTIFIED BY 'auth_string', AuthString is the plain password.
+	ByAuthString bool
+	AuthString   string
+	// HashString is the password hash of IDENTIFIED BY PASSWORD 'hash_string'.
+	HashString string
+}
+
+// encodePassword returns the password hash stored in mysql.user.
+func (o *AuthOption) encodePassword() (string, error) {
+	if o == nil {
+		return "", nil
+	}
+	if o.ByAuthString {
+		return auth.EncodePassword(o.AuthString), nil
+	}
+	if len(o.HashString) == 0 {
+		return "", nil
+	}
+	if _, err := auth.DecodePassword(o.HashString); err != nil {
+		return "", mysql.NewDefaultError(mysql.ErPasswordFormat)
+	}
+	return strings.ToUpper(o.HashString), nil
+}
+
+// UserSpecification is an account with its authentication option in CREATE USER and ALTER USER.
+type UserSpecification struct {
+	// User is the account in the user@host form.
+	User    string
+	AuthOpt *AuthOption
+}
+
+// splitUser splits an account in the user@host form, the host part is case insensitive.
+func splitUser(user string) (name, host string) {
+	i := strings.LastIndex(user, "@")
+	if i < 0 {
+		return user, "%"
+	}
+	return user[:i], strings.ToLower(user[i+1:])
+}
+
+// execRestrictedSQL formats sql with args and executes it with the RestrictedSQLExecutor of ctx.
+// String arguments should be formatted with %q.
+func execRestrictedSQL(ctx context.Context, sql string, args ...interface{}) (rset.Recordset, error) {
+	exec, ok := ctx.(sqlexec.RestrictedSQLExecutor)
+	if !ok {
+		return nil, errors.Errorf("can not execute restricted sql in context %T", ctx)
+	}
+	rs, err := exec.ExecRestrictedSQL(ctx, fmt.Sprintf(sql, args...))
+	return rs, errors.Trace(err)
+}
+
+// userExists checks whether the account name@host is in mysql.user.
+func userExists(ctx context.Context, name, host string) (bool, error) {
+	rs, err := execRestrictedSQL(ctx, `SELECT User FROM mysql.user WHERE User = %q AND Host = %q`, name, host)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	row, err := rs.FirstRow()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return row != nil, nil
+}
+
+// setPassword updates the password hash of the account name@host.
+func setPassword(ctx context.Context, name, host, pwd string) error {
+	_, err := execRestrictedSQL(ctx, `UPDATE mysql.user SET Password = %q WHERE User = %q AND Host = %q`, pwd, name, host)
+	return errors.Trace(err)
+}
+
+// CreateUserStmt creates user accounts.
+// See: https://dev.mysql.com/doc/refman/5.7/en/create-user.html
+type CreateUserStmt struct {
+	IfNotExists bool
+	Specs       []*UserSpecification
+
+	Text string
+}
+
+// Explain implements the stmt.Statement Explain interface.
+func (s *CreateUserStmt) Explain(ctx context.Context, w format.Formatter) {
+	w.Format("%s\n", s.Text)
+}
+
+// IsDDL implements the stmt.Statement IsDDL interface.
+func (s *CreateUserStmt) IsDDL() bool {
+	return true
+}
+
+// OriginText implements the stmt.Statement OriginText interface.
+func (s *CreateUserStmt) OriginText() string {
+	return s.Text
+}
+
+// SetText implements the stmt.Statement SetText interface.
+func (s *CreateUserStmt) SetText(text string) {
+	s.Text = text
+}
+
+// Exec implements the stmt.Statement Exec interface.
+// No account is created if any of the accounts exists already.
+func (s *CreateUserStmt) Exec(ctx context.Context) (rset.Recordset, error) {
+	var values, failed []string
+	for _, spec := range s.Specs {
+		name, host := splitUser(spec.User)
+		exists, err := userExists(ctx, name, host)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if exists {
+			if !s.IfNotExists {
+				failed = append(failed, auth.FormatUser(name, host))
+			}
+			continue
+		}
+		pwd, err := spec.AuthOpt.encodePassword()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		values = append(values, fmt.Sprintf("(%q, %q, %q)", host, name, pwd))
+	}
+	if len(failed) > 0 {
+		return nil, mysql.NewDefaultError(mysql.ErCannotUser, "CREATE USER", strings.Join(failed, ","))
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	_, err := execRestrictedSQL(ctx, `INSERT INTO mysql.user (Host, User, Password) VALUES %s`, strings.Join(values, ", "))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return nil, errors.Trace(incPrivilegeVersion(ctx))
+}
+
+// AlterUserStmt modifies user accounts.
+// See: https://dev.mysql.com/doc/refman/5.7/en/alter-user.html
+type AlterUserStmt struct {
+	IfExists bool
+	Specs    []*UserSpecification
+
+	Text string
+}
+
+// Explain implements the stmt.Statement Explain interface.
+func (s *AlterUserStmt) Explain(ctx context.Context, w format.Formatter) {
+	w.Format("%s\n", s.Text)
+}
+
+// IsDDL implements the stmt.Statement IsDDL interface.
+func (s *AlterUserStmt) IsDDL() bool {
+	return true
+}
+
+// OriginText implements the stmt.Statement OriginText interface.
+func (s *AlterUserStmt) OriginText() string {
+	return s.Text
+}
+
+// SetText implements the stmt.Statement SetText interface.
+func (s *AlterUserStmt) SetText(text string) {
+	s.Text = text
+}
+
+// Exec implements the stmt.Statement Exec interface.
+func (s *AlterUserStmt) Exec(ctx context.Context) (rset.Recordset, error) {
+	var failed []string
+	pwds := make([]string, len(s.Specs))
+	for i, spec := range s.Specs {
+		name, host := splitUser(spec.User)
+		exists, err := userExists(ctx, name, host)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !exists {
+			if !s.IfExists {
+				failed = append(failed, auth.FormatUser(name, host))
+			}
+			continue
+		}
+		pwds[i], err = spec.AuthOpt.encodePassword()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if len(failed) > 0 {
+		return nil, mysql.NewDefaultError(mysql.ErCannotUser, "ALTER USER", strings.Join(failed, ","))
+	}
+
+	for i, spec := range s.Specs {
+		if spec.AuthOpt == nil {
+			continue
+		}
+		name, host := splitUser(spec.User)
+		if err := setPassword(ctx, name, host, pwds[i]); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return nil, nil
+}
+
+// DropUserStmt removes user accounts and their privileges.
+// See: https://dev.mysql.com/doc/refman/5.7/en/drop-user.html
+type DropUserStmt struct {
+	IfExists bool
+	// Users are the accounts in the user@host form.
+	Users []string
+
+	Text string
+}
+
+// Explain implements the stmt.Statement Explain interface.
+func (s *DropUserStmt) Explain(ctx context.Context, w format.Formatter) {
+	w.Format("%s\n", s.Text)
+}
+
+// IsDDL implements the stmt.Statement IsDDL interface.
+func (s *DropUserStmt) IsDDL() bool {
+	return true
+}
+
+// OriginText implements the stmt.Statement OriginText interface.
+func (s *DropUserStmt) OriginText() string {
+	return s.Text
+}
+
+// SetText implements the stmt.Statement SetText interface.
+func (s *DropUserStmt) SetText(text string) {
+	s.Text = text
+}
+
+// Exec implements the stmt.Statement Exec interface.
+// No account is dropped if any of the accounts doesn't exist.
+func (s *DropUserStmt) Exec(ctx context.Context) (rset.Recordset, error) {
+	var failed []string
+	for _, user := range s.Users {
+		name, host := splitUser(user)
+		exists, err := userExists(ctx, name, host)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !exists && !s.IfExists {
+			failed = append(failed, auth.FormatUser(name, host))
+		}
+	}
+	if len(failed) > 0 {
+		return nil, mysql.NewDefaultError(mysql.ErCannotUser, "DROP USER", strings.Join(failed, ","))
+	}
+
+	for _, user := range s.Users {
+		name, host := splitUser(user)
+		_, err := execRestrictedSQL(ctx, `DELETE FROM mysql.user WHERE User = %q AND Host = %q`, name, host)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err = dropPrivileges(ctx, name, host); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return nil, errors.Trace(incPrivilegeVersion(ctx))
+}
+
+// currentUser returns the account of the session, it is empty if the session is not authenticated.
+func currentUser(ctx context.Context) string {
+	return variable.GetSessionVars(ctx).User
+}
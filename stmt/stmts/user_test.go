@@ -0,0 +1,133 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmts_test
+
+import (
+	"github.com/juju/errors"
+	. "github.com/pingcap/check"
+	"github.com/Dong-Chan/alloydb"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/stmt/stmts"
+	"github.com/Dong-Chan/alloydb/util/auth"
+)
+
+func (s *testStmtSuite) queryPassword(c *C, name, host string) string {
+	var pwd string
+	err := s.testDB.QueryRow(`SELECT Password FROM mysql.user WHERE User = ? AND Host = ?`, name, host).Scan(&pwd)
+	c.Assert(err, IsNil)
+	return pwd
+}
+
+func (s *testStmtSuite) userExists(c *C, name, host string) bool {
+	var cnt int
+	err := s.testDB.QueryRow(`SELECT COUNT(*) FROM mysql.user WHERE User = ? AND Host = ?`, name, host).Scan(&cnt)
+	c.Assert(err, IsNil)
+	return cnt > 0
+}
+
+func (s *testStmtSuite) TestCreateUserStmt(c *C) {
+	testSQL := `CREATE USER IF NOT EXISTS 'test'@'localhost' IDENTIFIED BY '123', test@'%', 'test'@'192.168.1.1' IDENTIFIED BY PASSWORD '*23AE809DDACAF96AF0FD78ED04B6A265E05AA257';`
+	stmtList, err := alloydb.Compile(testSQL)
+	c.Assert(err, IsNil)
+	c.Assert(stmtList, HasLen, 1)
+
+	testStmt, ok := stmtList[0].(*stmts.CreateUserStmt)
+	c.Assert(ok, IsTrue)
+	c.Assert(testStmt.IsDDL(), IsTrue)
+	c.Assert(len(testStmt.OriginText()), Greater, 0)
+	c.Assert(testStmt.IfNotExists, IsTrue)
+	c.Assert(testStmt.Specs, HasLen, 3)
+	c.Assert(testStmt.Specs[0].User, Equals, "test@localhost")
+	c.Assert(testStmt.Specs[1].User, Equals, "test@%")
+	c.Assert(testStmt.Specs[1].AuthOpt, IsNil)
+
+	mf := newMockFormatter()
+	testStmt.Explain(nil, mf)
+	c.Assert(mf.Len(), Greater, 0)
+
+	mustExec(c, s.testDB, testSQL)
+	c.Assert(s.queryPassword(c, "test", "localhost"), Equals, auth.EncodePassword("123"))
+	c.Assert(s.queryPassword(c, "test", "%"), Equals, "")
+	c.Assert(s.queryPassword(c, "test", "192.168.1.1"), Equals, auth.EncodePassword("123"))
+
+	// IF NOT EXISTS skips the existing accounts.
+	mustExec(c, s.testDB, `CREATE USER IF NOT EXISTS 'test'@'localhost' IDENTIFIED BY 'xxx', 'test1'`)
+	c.Assert(s.queryPassword(c, "test", "localhost"), Equals, auth.EncodePassword("123"))
+	c.Assert(s.userExists(c, "test1", "%"), IsTrue)
+
+	// Nothing is created if any account exists.
+	_, err = s.testDB.Exec(`CREATE USER 'test2', 'test'@'LocalHost'`)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErCannotUser))
+	c.Assert(s.userExists(c, "test2", "%"), IsFalse)
+
+	_, err = s.testDB.Exec(`CREATE USER 'test2' IDENTIFIED BY PASSWORD 'abc'`)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErPasswordFormat))
+
+	mustExec(c, s.testDB, `DROP USER 'test'@'localhost', 'test', 'test'@'192.168.1.1', 'test1'`)
+}
+
+func (s *testStmtSuite) TestAlterUserStmt(c *C) {
+	testSQL := `ALTER USER IF EXISTS 'test'@'localhost' IDENTIFIED BY 'abc', 'test1' IDENTIFIED BY 'abc';`
+	stmtList, err := alloydb.Compile(testSQL)
+	c.Assert(err, IsNil)
+	c.Assert(stmtList, HasLen, 1)
+
+	testStmt, ok := stmtList[0].(*stmts.AlterUserStmt)
+	c.Assert(ok, IsTrue)
+	c.Assert(testStmt.IsDDL(), IsTrue)
+	c.Assert(len(testStmt.OriginText()), Greater, 0)
+
+	mf := newMockFormatter()
+	testStmt.Explain(nil, mf)
+	c.Assert(mf.Len(), Greater, 0)
+
+	mustExec(c, s.testDB, `CREATE USER 'test'@'localhost' IDENTIFIED BY '123'`)
+	mustExec(c, s.testDB, testSQL)
+	c.Assert(s.queryPassword(c, "test", "localhost"), Equals, auth.EncodePassword("abc"))
+	c.Assert(s.userExists(c, "test1", "%"), IsFalse)
+
+	_, err = s.testDB.Exec(`ALTER USER 'test'@'localhost' IDENTIFIED BY 'xyz', 'test1' IDENTIFIED BY 'xyz'`)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErCannotUser))
+	c.Assert(s.queryPassword(c, "test", "localhost"), Equals, auth.EncodePassword("abc"))
+
+	mustExec(c, s.testDB, `DROP USER 'test'@'localhost'`)
+}
+
+func (s *testStmtSuite) TestDropUserStmt(c *C) {
+	testSQL := `DROP USER IF EXISTS 'test'@'localhost', 'test1';`
+	stmtList, err := alloydb.Compile(testSQL)
+	c.Assert(err, IsNil)
+	c.Assert(stmtList, HasLen, 1)
+
+	testStmt, ok := stmtList[0].(*stmts.DropUserStmt)
+	c.Assert(ok, IsTrue)
+	c.Assert(testStmt.IsDDL(), IsTrue)
+	c.Assert(len(testStmt.OriginText()), Greater, 0)
+	c.Assert(testStmt.Users, DeepEquals, []string{"test@localhost", "test1@%"})
+
+	mf := newMockFormatter()
+	testStmt.Explain(nil, mf)
+	c.Assert(mf.Len(), Greater, 0)
+
+	mustExec(c, s.testDB, `CREATE USER 'test'@'localhost'`)
+	mustExec(c, s.testDB, testSQL)
+	c.Assert(s.userExists(c, "test", "localhost"), IsFalse)
+
+	// Nothing is dropped if any account doesn't exist.
+	mustExec(c, s.testDB, `CREATE USER 'test'@'localhost'`)
+	_, err = s.testDB.Exec(`DROP USER 'test'@'localhost', 'test1'`)
+	c.Assert(errors.Cause(err).(*mysql.SQLError).Code, Equals, uint16(mysql.ErCannotUser))
+	c.Assert(s.userExists(c, "test", "localhost"), IsTrue)
+	mustExec(c, s.testDB, `DROP USER 'test'@'localhost'`)
+}
@@ -0,0 +1,186 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmts
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/ngaut/log"
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/field"
+	"github.com/Dong-Chan/alloydb/model"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+	"github.com/Dong-Chan/alloydb/plan"
+	"github.com/Dong-Chan/alloydb/plan/plans"
+	"github.com/Dong-Chan/alloydb/stmt"
+)
+
+// CommonTableExpr is a common table expression, like "name (c1, c2) AS (SELECT ...)".
+type CommonTableExpr struct {
+	Name     model.CIStr
+	ColNames []string
+	// Query is a SelectStmt or a UnionStmt.
+	Query stmt.Statement
+}
+
+// WithClause is the WITH clause of SELECT, UNION, UPDATE and DELETE statements,
+// the common table expressions defined in it can be referenced by name as tables in the statement.
+// A common table expression can reference the ones defined before it,
+// and itself too if Recursive is true.
+// See: https://dev.mysql.com/doc/refman/8.0/en/with.html
+type WithClause struct {
+	Recursive bool
+	CTEs      []*CommonTableExpr
+}
+
+// plan plans the statement with the CTEs, f plans the statement body.
+func (w *WithClause) plan(ctx context.Context, f func(context.Context) (plan.Plan, error)) (plan.Plan, error) {
+	scope, unbind, err := w.bind(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer unbind()
+
+	p, err := f(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &plans.WithPlan{Src: p, Scope: scope}, nil
+}
+
+// exec binds the CTEs to ctx for executing a statement,
+// the returned function must be called after the statement is executed.
+func (w *WithClause) exec(ctx context.Context) (release func(), err error) {
+	scope, unbind, err := w.bind(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return func() {
+		unbind()
+		if err := scope.Close(); err != nil {
+			log.Errorf("close CTEs err %v", err)
+		}
+	}, nil
+}
+
+// bind plans the CTEs and binds them to ctx.
+func (w *WithClause) bind(ctx context.Context) (*plans.CTEScope, func(), error) {
+	scope := &plans.CTEScope{}
+	unbind := scope.Bind(ctx)
+	for _, e := range w.CTEs {
+		for _, c := range scope.CTEs {
+			if strings.EqualFold(c.Name, e.Name.O) {
+				unbind()
+				return nil, nil, errors.Trace(mysql.NewDefaultError(mysql.ErNonuniqTable, e.Name.O))
+			}
+		}
+
+		c, err := w.planCTE(ctx, scope, e)
+		if err != nil {
+			unbind()
+			return nil, nil, errors.Trace(err)
+		}
+		if !w.Recursive {
+			scope.CTEs = append(scope.CTEs, c)
+		}
+	}
+	return scope, unbind, nil
+}
+
+func (w *WithClause) planCTE(ctx context.Context, scope *plans.CTEScope, e *CommonTableExpr) (*plans.CTE, error) {
+	c := &plans.CTE{Name: e.Name.O}
+	if !w.Recursive {
+		p, err := e.Query.(plan.Planner).Plan(ctx)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		c.Seeds = []plan.Plan{p}
+		c.Fields, err = cteFields(p, e.ColNames)
+		return c, errors.Trace(err)
+	}
+
+	var (
+		selects   []*SelectStmt
+		distincts []bool
+	)
+	switch x := e.Query.(type) {
+	case *SelectStmt:
+		selects = []*SelectStmt{x}
+	case *UnionStmt:
+		selects = x.Selects
+		distincts = x.Distincts
+	default:
+		return nil, errors.Errorf("invalid common table expression query %T", e.Query)
+	}
+
+	// The CTE is visible in its own query blocks, the blocks referencing it are recursive.
+	scope.CTEs = append(scope.CTEs, c)
+	for i, s := range selects {
+		c.Referenced = false
+		p, err := s.Plan(ctx)
+		switch {
+		case !c.Referenced:
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if len(c.Recursives) > 0 {
+				return nil, errors.Trace(mysql.NewDefaultError(mysql.ErCteRecursiveRequiresNonrecursiveFirst, c.Name))
+			}
+			c.Seeds = append(c.Seeds, p)
+			if c.Fields == nil {
+				if c.Fields, err = cteFields(p, e.ColNames); err != nil {
+					return nil, errors.Trace(err)
+				}
+			}
+		case len(selects) == 1:
+			return nil, errors.Trace(mysql.NewDefaultError(mysql.ErCteRecursiveRequiresUnion, c.Name))
+		case len(c.Seeds) == 0:
+			return nil, errors.Trace(mysql.NewDefaultError(mysql.ErCteRecursiveRequiresNonrecursiveFirst, c.Name))
+		default:
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			c.Recursives = append(c.Recursives, p)
+		}
+
+		// The rows are deduplicated if any query blocks are combined with UNION DISTINCT.
+		if i > 0 && distincts[i-1] {
+			c.Distinct = true
+		}
+	}
+	return c, nil
+}
+
+// cteFields returns the fields of a CTE, they are renamed by colNames if it is not empty.
+func cteFields(p plan.Plan, colNames []string) ([]*field.ResultField, error) {
+	srcFields := p.GetFields()
+	if len(colNames) > 0 && len(colNames) != len(srcFields) {
+		return nil, errors.Trace(mysql.NewDefaultError(mysql.ErViewWrongList))
+	}
+
+	fields := make([]*field.ResultField, 0, len(srcFields))
+	for i, f := range srcFields {
+		nf := f.Clone()
+		if len(colNames) > 0 {
+			nf.Name = colNames[i]
+			nf.ColumnInfo.Name = model.NewCIStr(colNames[i])
+		}
+		nf.DBName = ""
+		nf.OrgTableName = ""
+		fields = append(fields, nf)
+	}
+	return fields, nil
+}
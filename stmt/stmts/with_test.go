@@ -0,0 +1,69 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmts_test
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/Dong-Chan/alloydb"
+	"github.com/Dong-Chan/alloydb/stmt/stmts"
+)
+
+func (s *testStmtSuite) TestWith(c *C) {
+	testSQL := `drop table if exists with_test; create table with_test(id int, parent int);
+    insert with_test values (1, null), (2, 1), (3, 2), (4, 1);`
+	mustExec(c, s.testDB, testSQL)
+
+	testSQL = `with recursive tree (id) as (select id from with_test where id = 2
+    union all select with_test.id from with_test, tree where with_test.parent = tree.id) select id from tree;`
+	stmtList, err := alloydb.Compile(testSQL)
+	c.Assert(err, IsNil)
+	c.Assert(stmtList, HasLen, 1)
+
+	testStmt, ok := stmtList[0].(*stmts.SelectStmt)
+	c.Assert(ok, IsTrue)
+	c.Assert(testStmt.With, NotNil)
+	c.Assert(testStmt.With.Recursive, IsTrue)
+	c.Assert(testStmt.With.CTEs, HasLen, 1)
+	c.Assert(testStmt.With.CTEs[0].Name.L, Equals, "tree")
+	c.Assert(testStmt.With.CTEs[0].ColNames, DeepEquals, []string{"id"})
+
+	tx := mustBegin(c, s.testDB)
+	rows, err := tx.Query(testSQL)
+	c.Assert(err, IsNil)
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		rows.Scan(&id)
+		ids = append(ids, id)
+	}
+	c.Assert(ids, DeepEquals, []int{2, 3})
+
+	rows.Close()
+	mustCommit(c, tx)
+
+	testSQL = `with t as (select id from with_test where parent = 1) delete from with_test where id in (select id from t);`
+	mustExec(c, s.testDB, testSQL)
+
+	tx = mustBegin(c, s.testDB)
+	rows, err = tx.Query("select count(*) from with_test")
+	c.Assert(err, IsNil)
+	for rows.Next() {
+		var cnt int
+		rows.Scan(&cnt)
+		c.Assert(cnt, Equals, 2)
+	}
+	rows.Close()
+	mustCommit(c, tx)
+}
@@ -0,0 +1,31 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localstore
+
+import (
+	"github.com/Dong-Chan/alloydb/util/metrics"
+)
+
+var (
+	kvDuration = metrics.NewHistogramVec("alloydb_localstore_kv_duration_seconds",
+		"The latency of the Get, Seek and Commit operations of the transactions.", metrics.DefBuckets, "type")
+	txnCounter = metrics.NewCounterVec("alloydb_localstore_txns_total",
+		"The number of the finished transactions by result, commit, rollback or failed.", "result")
+	txnConflictCounter = metrics.NewCounterVec("alloydb_localstore_txn_conflicts_total",
+		"The number of the commits failed by conflicts, condition_not_match or lock_conflict.", "type")
+)
+
+func init() {
+	metrics.MustRegister(kvDuration, txnCounter, txnConflictCounter)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/ngaut/log"
 	"github.com/Dong-Chan/alloydb/kv"
+	"github.com/Dong-Chan/alloydb/util/errors2"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 )
 
@@ -106,6 +107,7 @@ func (txn *dbTxn) String() string {
 
 func (txn *dbTxn) Get(k []byte) ([]byte, error) {
 	log.Debugf("get key:%s, txn:%d", k, txn.tID)
+	defer kvDuration.WithLabelValues("get").ObserveSince(time.Now())
 	k = kv.EncodeKey(k)
 	val, err := txn.UnionStore.Get(k)
 	if kv.IsErrNotFound(err) {
@@ -136,6 +138,7 @@ func (txn *dbTxn) Set(k []byte, data []byte) error {
 
 func (txn *dbTxn) Seek(k []byte, fnKeyCmp func([]byte) bool) (kv.Iterator, error) {
 	log.Debugf("seek %s txn:%d", k, txn.tID)
+	defer kvDuration.WithLabelValues("seek").ObserveSince(time.Now())
 	k = kv.EncodeKey(k)
 
 	iter, err := txn.UnionStore.Seek(k, txn)
@@ -215,7 +218,20 @@ func (txn *dbTxn) Commit() error {
 		txn.close()
 	}()
 
-	return txn.doCommit()
+	start := time.Now()
+	err := txn.doCommit()
+	kvDuration.WithLabelValues("commit").ObserveSince(start)
+	switch {
+	case err == nil:
+		txnCounter.WithLabelValues("commit").Inc()
+		return nil
+	case errors2.ErrorEqual(err, kv.ErrConditionNotMatch):
+		txnConflictCounter.WithLabelValues("condition_not_match").Inc()
+	case errors2.ErrorEqual(err, kv.ErrLockConflict):
+		txnConflictCounter.WithLabelValues("lock_conflict").Inc()
+	}
+	txnCounter.WithLabelValues("failed").Inc()
+	return err
 }
 
 func (txn *dbTxn) close() error {
@@ -230,6 +246,7 @@ func (txn *dbTxn) Rollback() error {
 		return ErrInvalidTxn
 	}
 	log.Warnf("Rollback txn %d", txn.tID)
+	txnCounter.WithLabelValues("rollback").Inc()
 	return txn.close()
 }
 
@@ -58,9 +58,14 @@ func TableFromMeta(dbname string, alloc autoid.Allocator, tblInfo *model.TableIn
 	}
 
 	for _, idxInfo := range tblInfo.Indices {
+		var cols []*column.Col
+		for _, ic := range idxInfo.Columns {
+			cols = append(cols, t.Columns[ic.Offset])
+		}
+		x := kv.NewKVIndex(t.indexPrefix, idxInfo.Name.L, idxInfo.Unique)
 		idx := &column.IndexedCol{
 			IndexInfo: *idxInfo,
-			X:         kv.NewKVIndex(t.indexPrefix, idxInfo.Name.L, idxInfo.Unique),
+			X:         column.NewCollatedIndex(x, cols),
 		}
 		t.AddIndex(idx)
 	}
@@ -170,6 +175,23 @@ func (t *Table) unflatten(rec interface{}, col *column.Col) (interface{}, error)
 		return mysql.Duration{Duration: time.Duration(rec.(int64)), Fsp: col.Decimal}, nil
 	case mysql.TypeNewDecimal, mysql.TypeDecimal:
 		return mysql.ParseDecimal(rec.(string))
+	case mysql.TypeEnum:
+		if v := rec.(uint64); v != 0 {
+			return mysql.ParseEnumValue(col.Elems, v)
+		}
+		// The special error value.
+		return mysql.Enum{}, nil
+	case mysql.TypeSet:
+		return mysql.ParseSetValue(col.Elems, rec.(uint64))
+	case mysql.TypeBit:
+		return mysql.Bit{Value: rec.(uint64), Width: col.Flen}, nil
+	case mysql.TypeJSON:
+		var j mysql.JSON
+		err := j.UnmarshalBinary(rec.([]byte))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return j, nil
 	}
 	log.Error(string(col.Tp), rec, reflect.TypeOf(rec))
 	return nil, nil
@@ -185,6 +207,16 @@ func (t *Table) flatten(data interface{}) (interface{}, error) {
 		return int64(x.Duration), nil
 	case mysql.Decimal:
 		return x.String(), nil
+	case mysql.Enum:
+		// ENUM, SET and BIT values are saved as their numbers.
+		return x.Value, nil
+	case mysql.Set:
+		return x.Value, nil
+	case mysql.Bit:
+		return x.Value, nil
+	case mysql.JSON:
+		// JSON values are saved in the binary encoding.
+		return x.MarshalBinary()
 	default:
 		return data, nil
 	}
@@ -407,9 +439,19 @@ func (t *Table) RowWithCols(ctx context.Context, h int64, cols []*column.Col) ([
 	}
 	// use the length of t.Cols() for alignment
 	v := make([]interface{}, len(t.Cols()))
+	var loc *time.Location
 	for _, c := range cols {
 		k := t.RecordKey(h, c)
 		data, err := txn.Get([]byte(k))
+		if kv.IsErrNotFound(err) {
+			// The column is added by ALTER TABLE after the row is written, the row has the default value.
+			if _, lerr := txn.Get([]byte(t.RecordKey(h, nil))); lerr == nil {
+				if v[c.Offset], err = c.CastValue(ctx, c.DefaultValue); err != nil {
+					return nil, errors.Trace(err)
+				}
+				continue
+			}
+		}
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -418,6 +460,15 @@ func (t *Table) RowWithCols(ctx context.Context, h int64, cols []*column.Col) ([
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+
+		// Timestamp values are stored in UTC and shown in the time zone of the session.
+		if ts, ok := val.(mysql.Time); ok && c.Tp == mysql.TypeTimestamp && !ts.IsZero() {
+			if loc == nil {
+				loc = variable.GetTimeZone(ctx)
+			}
+			ts.Time = ts.Time.In(loc)
+			val = ts
+		}
 		v[c.Offset] = val
 	}
 	return v, nil
@@ -99,6 +99,32 @@ func (ts *testSuite) TestBasic(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (ts *testSuite) TestCollatedIndex(c *C) {
+	_, err := ts.se.Execute("CREATE TABLE test.t (a int primary key, b varchar(255) unique, c varchar(255) collate utf8_bin unique)")
+	c.Assert(err, IsNil)
+	ctx := ts.se.(context.Context)
+	dom := sessionctx.GetDomain(ctx)
+	tb, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+
+	rid, err := tb.AddRecord(ctx, []interface{}{1, "abc", "abc"})
+	c.Assert(err, IsNil)
+	// b is case insensitive, c is case sensitive.
+	_, err = tb.AddRecord(ctx, []interface{}{2, "ABC", "xyz"})
+	c.Assert(err, NotNil)
+	_, err = tb.AddRecord(ctx, []interface{}{3, "xyz", "ABC"})
+	c.Assert(err, IsNil)
+
+	// The index entry of "abc" is removed with the row.
+	c.Assert(tb.RemoveRowAllIndex(ctx, rid, []interface{}{1, "abc", "abc"}), IsNil)
+	c.Assert(tb.RemoveRow(ctx, rid), IsNil)
+	_, err = tb.AddRecord(ctx, []interface{}{4, "ABC", "abc"})
+	c.Assert(err, IsNil)
+
+	_, err = ts.se.Execute("drop table test.t")
+	c.Assert(err, IsNil)
+}
+
 func (ts *testSuite) TestTypes(c *C) {
 	_, err := ts.se.Execute("CREATE TABLE test.t (c1 tinyint, c2 smallint, c3 int, c4 bigint, c5 text, c6 blob, c7 varchar(64), c8 time, c9 timestamp not null default CURRENT_TIMESTAMP, c10 decimal)")
 	c.Assert(err, IsNil)
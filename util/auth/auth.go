@@ -0,0 +1,138 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// PasswordHashLen is the length of a mysql_native_password hash, like
+// "*6BB4837EB74329105EE4568DDA7DC67ED2CA2AD9".
+const PasswordHashLen = 41
+
+// EncodePassword returns the mysql_native_password hash of pwd, it is the
+// string stored in mysql.user. An empty password is stored as an empty string.
+// See: https://dev.mysql.com/doc/internals/en/secure-password-authentication.html
+func EncodePassword(pwd string) string {
+	if len(pwd) == 0 {
+		return ""
+	}
+	hash := sha1Hash(sha1Hash([]byte(pwd)))
+	return "*" + strings.ToUpper(hex.EncodeToString(hash))
+}
+
+// DecodePassword returns the SHA1(SHA1(password)) bytes of a hash made by EncodePassword.
+func DecodePassword(pwd string) ([]byte, error) {
+	if len(pwd) != PasswordHashLen || pwd[0] != '*' {
+		return nil, errors.Errorf("invalid password hash %q", pwd)
+	}
+	b, err := hex.DecodeString(pwd[1:])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return b, nil
+}
+
+// ScramblePassword computes the auth response a client sends for password
+// with the salt received in the handshake, it is
+// SHA1(password) XOR SHA1(salt + SHA1(SHA1(password))).
+func ScramblePassword(salt []byte, password string) []byte {
+	if len(password) == 0 {
+		return nil
+	}
+	stage1 := sha1Hash([]byte(password))
+	stage2 := sha1Hash(stage1)
+	scramble := sha1Hash(append(append([]byte{}, salt...), stage2...))
+	for i := range scramble {
+		scramble[i] ^= stage1[i]
+	}
+	return scramble
+}
+
+// CheckScrambledPassword checks the auth response of a client against the
+// hash stored in mysql.user.
+func CheckScrambledPassword(salt []byte, hpwd string, auth []byte) bool {
+	if len(hpwd) == 0 {
+		return len(auth) == 0
+	}
+	stage2, err := DecodePassword(hpwd)
+	if err != nil || len(auth) != sha1.Size {
+		return false
+	}
+	// Recover SHA1(password) from the scramble and check that hashing it again
+	// gives the stored hash.
+	stage1 := sha1Hash(append(append([]byte{}, salt...), stage2...))
+	for i := range stage1 {
+		stage1[i] ^= auth[i]
+	}
+	return bytes.Equal(sha1Hash(stage1), stage2)
+}
+
+func sha1Hash(b []byte) []byte {
+	h := sha1.Sum(b)
+	return h[:]
+}
+
+// MatchHost reports whether host matches the host part of an account, the
+// pattern may contain the wildcards '%' and '_' as in LIKE. Host names are
+// compared case insensitively.
+func MatchHost(pattern, host string) bool {
+	return matchHost(strings.ToLower(pattern), strings.ToLower(host))
+}
+
+func matchHost(pattern, host string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '%':
+			for i := len(host); i >= 0; i-- {
+				if matchHost(pattern[1:], host[i:]) {
+					return true
+				}
+			}
+			return false
+		case '_':
+			if len(host) == 0 {
+				return false
+			}
+		default:
+			if len(host) == 0 || host[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern, host = pattern[1:], host[1:]
+	}
+	return len(host) == 0
+}
+
+// HostPatternRank gives the order in which account host patterns are tried,
+// an account with a greater rank is more specific. Literal host names come
+// first, then patterns with a longer literal prefix, "%" is the last.
+func HostPatternRank(pattern string) int {
+	i := strings.IndexAny(pattern, "%_")
+	if i < 0 {
+		return len(pattern) + 1<<16
+	}
+	return i
+}
+
+// FormatUser formats an account like 'root'@'%' for messages.
+func FormatUser(name, host string) string {
+	return fmt.Sprintf("'%s'@'%s'", name, host)
+}
@@ -0,0 +1,87 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testAuthSuite{})
+
+type testAuthSuite struct {
+}
+
+func (s *testAuthSuite) TestEncodePassword(c *C) {
+	c.Assert(EncodePassword(""), Equals, "")
+	pwd := EncodePassword("123")
+	c.Assert(pwd, Equals, "*23AE809DDACAF96AF0FD78ED04B6A265E05AA257")
+	c.Assert(pwd, HasLen, PasswordHashLen)
+
+	b, err := DecodePassword(pwd)
+	c.Assert(err, IsNil)
+	c.Assert(b, HasLen, 20)
+
+	_, err = DecodePassword("123")
+	c.Assert(err, NotNil)
+	_, err = DecodePassword("*23AE809DDACAF96AF0FD78ED04B6A265E05AA25G")
+	c.Assert(err, NotNil)
+}
+
+func (s *testAuthSuite) TestCheckScrambledPassword(c *C) {
+	salt := []byte("01234567890123456789")
+	hpwd := EncodePassword("abc")
+	c.Assert(CheckScrambledPassword(salt, hpwd, ScramblePassword(salt, "abc")), IsTrue)
+	c.Assert(CheckScrambledPassword(salt, hpwd, ScramblePassword(salt, "abd")), IsFalse)
+	c.Assert(CheckScrambledPassword([]byte("x"), hpwd, ScramblePassword(salt, "abc")), IsFalse)
+	c.Assert(CheckScrambledPassword(salt, hpwd, nil), IsFalse)
+
+	// Empty password.
+	c.Assert(CheckScrambledPassword(salt, "", ScramblePassword(salt, "")), IsTrue)
+	c.Assert(CheckScrambledPassword(salt, "", ScramblePassword(salt, "abc")), IsFalse)
+	c.Assert(CheckScrambledPassword(salt, "invalid", []byte("x")), IsFalse)
+}
+
+func (s *testAuthSuite) TestMatchHost(c *C) {
+	tbl := []struct {
+		Pattern string
+		Host    string
+		Expect  bool
+	}{
+		{"%", "", true},
+		{"%", "10.0.0.1", true},
+		{"localhost", "LocalHost", true},
+		{"localhost", "localhost1", false},
+		{"10.0.0.%", "10.0.0.12", true},
+		{"10.0.0.%", "10.0.1.12", false},
+		{"10.0.0._", "10.0.0.1", true},
+		{"10.0.0._", "10.0.0.12", false},
+		{"%.example.com", "db.example.com", true},
+		{"%.example.com", "example.com", false},
+	}
+
+	for _, t := range tbl {
+		c.Assert(MatchHost(t.Pattern, t.Host), Equals, t.Expect, Commentf("%s %s", t.Pattern, t.Host))
+	}
+
+	c.Assert(HostPatternRank("localhost"), Greater, HostPatternRank("10.0.0.%"))
+	c.Assert(HostPatternRank("10.0.0.%"), Greater, HostPatternRank("10.%"))
+	c.Assert(HostPatternRank("10.%"), Greater, HostPatternRank("%"))
+	c.Assert(FormatUser("root", "%"), Equals, "'root'@'%'")
+}
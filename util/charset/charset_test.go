@@ -47,3 +47,63 @@ func (s *testCharsetSuite) TestGetAllCharsets(c *C) {
 	descs := GetAllCharsets()
 	c.Assert(len(descs), Equals, len(charsetInfos)-1)
 }
+
+func (s *testCharsetSuite) TestCollator(c *C) {
+	tbl := []struct {
+		Collation string
+		A         string
+		B         string
+		Ret       int
+	}{
+		{"binary", "a", "A", 1},
+		{"binary", "a", "a ", -1},
+		{"utf8_bin", "a", "A", 1},
+		{"utf8_bin", "a", "a  ", 0},
+		{"utf8_general_ci", "abc", "ABC", 0},
+		{"utf8_general_ci", "abc", "ABC  ", 0},
+		{"utf8_general_ci", "résumé", "RESUME", 0},
+		{"utf8_general_ci", "a", "b", -1},
+		{"utf8_general_ci", "ab", "A", 1},
+		{"utf8_general_ci", "ß", "s", 0},
+		{"utf8_general_ci", "😀", "😃", 0},
+		{"utf8_unicode_ci", "Straße", "STRASSE", 0},
+		{"utf8_unicode_ci", "Æsir", "aesir", 0},
+		{"utf8_unicode_ci", "ß", "s", 1},
+		{"latin1_swedish_ci", "Hello", "hello", 0},
+		{"unknown", "a", "A", 1},
+	}
+
+	for _, t := range tbl {
+		coll := GetCollator(t.Collation)
+		c.Assert(coll.Compare(t.A, t.B), Equals, t.Ret, Commentf("%v", t))
+		// The sort keys compare bytewise in the same order.
+		ret := 0
+		if ka, kb := coll.Key(t.A), coll.Key(t.B); ka < kb {
+			ret = -1
+		} else if ka > kb {
+			ret = 1
+		}
+		c.Assert(ret, Equals, t.Ret, Commentf("%v", t))
+	}
+
+	c.Assert(IsBinaryCollation("binary"), IsTrue)
+	c.Assert(IsBinaryCollation("utf8_bin"), IsFalse)
+	c.Assert(IsBinaryCollation("utf8_general_ci"), IsFalse)
+}
+
+func (s *testCharsetSuite) TestGetCollation(c *C) {
+	coll, err := GetCollationByName("UTF8_BIN")
+	c.Assert(err, IsNil)
+	c.Assert(coll.CharsetName, Equals, "utf8")
+	_, err = GetCollationByName("utf8_invalid_ci")
+	c.Assert(err, NotNil)
+
+	name, err := GetDefaultCollation("utf8")
+	c.Assert(err, IsNil)
+	c.Assert(name, Equals, "utf8_general_ci")
+	name, err = GetDefaultCollation("binary")
+	c.Assert(err, IsNil)
+	c.Assert(name, Equals, "binary")
+	_, err = GetDefaultCollation("invalid")
+	c.Assert(err, NotNil)
+}
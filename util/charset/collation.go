@@ -0,0 +1,166 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package charset
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/juju/errors"
+)
+
+// Collator compares strings and generates sort keys by a collation.
+type Collator interface {
+	// Compare returns an integer comparing the strings a and b by the collation.
+	Compare(a, b string) int
+	// Key returns the sort key of str, the sort keys compare bytewise
+	// in the same order as the strings compare by the collation.
+	Key(str string) string
+}
+
+var (
+	binCollator       = &binaryCollator{}
+	binPaddingColl    = &binaryCollator{padding: true}
+	generalCICollator = &ciCollator{}
+	unicodeCICollator = &ciCollator{expand: true}
+)
+
+// GetCollator returns the collator of the collation name.
+// The binary collator is returned for the binary and unknown collations.
+func GetCollator(name string) Collator {
+	name = strings.ToLower(name)
+	switch {
+	case name == "" || name == CharsetBin:
+		return binCollator
+	case strings.HasSuffix(name, "_bin") || strings.HasSuffix(name, "_cs"):
+		return binPaddingColl
+	case strings.Contains(name, "_unicode_"):
+		return unicodeCICollator
+	case strings.HasSuffix(name, "_ci"):
+		if strings.HasPrefix(name, "utf8") && !strings.Contains(name, "_general_") {
+			// The language specific utf8 collations are based on the unicode collation.
+			return unicodeCICollator
+		}
+		return generalCICollator
+	default:
+		return binCollator
+	}
+}
+
+// IsBinaryCollation returns whether the collation compares strings bytewise without padding.
+func IsBinaryCollation(name string) bool {
+	return GetCollator(name) == binCollator
+}
+
+// GetCollations returns all the collations ordered by ID.
+func GetCollations() []*Collation {
+	return collations
+}
+
+// GetCollationByName returns the collation with the name.
+func GetCollationByName(name string) (*Collation, error) {
+	name = strings.ToLower(name)
+	for _, c := range collations {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+	return nil, errors.Errorf("Unknown collation: '%s'", name)
+}
+
+// GetDefaultCollation returns the default collation name of the charset.
+func GetDefaultCollation(cs string) (string, error) {
+	cs = strings.ToLower(cs)
+	if cs == CharsetBin {
+		return CharsetBin, nil
+	}
+	c, ok := charsets[cs]
+	if !ok {
+		return "", errors.Errorf("Unknown charset %s", cs)
+	}
+	return c.DefaultCollation.Name, nil
+}
+
+// binaryCollator compares strings bytewise, the trailing spaces are ignored if padding is set,
+// like the PAD SPACE collations of MySQL.
+type binaryCollator struct {
+	padding bool
+}
+
+func (c *binaryCollator) Compare(a, b string) int {
+	return strings.Compare(c.Key(a), c.Key(b))
+}
+
+func (c *binaryCollator) Key(str string) string {
+	if c.padding {
+		return strings.TrimRight(str, " ")
+	}
+	return str
+}
+
+// ciCollator is a case and accent insensitive collator.
+// Every character is mapped to a weight, which is the upper case of its base letter.
+// The characters out of the basic multilingual plane are all the same.
+// If expand is set, the ligatures like 'ß' and 'æ' are expanded to multiple characters,
+// like the unicode collations of MySQL, otherwise they are single letters like the general collations.
+type ciCollator struct {
+	expand bool
+}
+
+func (c *ciCollator) Compare(a, b string) int {
+	return strings.Compare(c.Key(a), c.Key(b))
+}
+
+// Key returns the weights of the characters, every weight is encoded in 2 bytes in big endian order.
+func (c *ciCollator) Key(str string) string {
+	str = strings.TrimRight(str, " ")
+	key := make([]byte, 0, len(str)*2)
+	for _, r := range str {
+		if c.expand {
+			if e, ok := expansions[r]; ok {
+				for _, w := range e {
+					key = append(key, byte(w>>8), byte(w))
+				}
+				continue
+			}
+		}
+		w := weight(r)
+		key = append(key, byte(w>>8), byte(w))
+	}
+	return string(key)
+}
+
+// latin1Letters maps the characters from U+00C0 to U+00FF to their base letters.
+const latin1Letters = "AAAAAAÆCEEEEIIIIÐNOOOOO×ØUUUUYÞSAAAAAAÆCEEEEIIIIÐNOOOOO÷ØUUUUYÞY"
+
+var latin1Bases = []rune(latin1Letters)
+
+// expansions are the characters which are expanded to multiple letters in the unicode collations.
+var expansions = map[rune][]rune{
+	'ß': {'S', 'S'},
+	'Æ': {'A', 'E'},
+	'æ': {'A', 'E'},
+	'Œ': {'O', 'E'},
+	'œ': {'O', 'E'},
+}
+
+func weight(r rune) rune {
+	switch {
+	case r > 0xFFFF:
+		return 0xFFFD
+	case r >= 0xC0 && r <= 0xFF:
+		r = latin1Bases[r-0xC0]
+	}
+	return unicode.ToUpper(r)
+}
@@ -98,8 +98,30 @@ func EncodeKey(args ...interface{}) ([]byte, error) {
 			b = EncodeBytes(b, v)
 			format = append(format, formatBytesFlag)
 		case mysql.Time:
+			if v.Type == mysql.TypeTimestamp {
+				// Timestamp values may be in different time zones, encode them in UTC.
+				v.Time = v.Time.UTC()
+			}
 			b = EncodeBytes(b, []byte(v.String()))
 			format = append(format, formatStringFlag)
+		case mysql.Enum:
+			// The ENUM, SET and BIT values are ordered by their numbers.
+			b = EncodeUint(b, v.Value)
+			format = append(format, formatUintFlag)
+		case mysql.Set:
+			b = EncodeUint(b, v.Value)
+			format = append(format, formatUintFlag)
+		case mysql.Bit:
+			b = EncodeUint(b, v.Value)
+			format = append(format, formatUintFlag)
+		case mysql.JSON:
+			// JSON values can't be indexed, the key is only used to find the equal values.
+			j, err := v.MarshalBinary()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			b = EncodeBytes(b, j)
+			format = append(format, formatBytesFlag)
 		case nil:
 			// We will 0x00, 0x00 for nil.
 			// The []byte{} will be encoded as 0x00, 0x01.
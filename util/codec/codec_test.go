@@ -17,8 +17,10 @@ import (
 	"bytes"
 	"math"
 	"testing"
+	"time"
 
 	. "github.com/pingcap/check"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
 )
 
 func TestT(t *testing.T) {
@@ -122,6 +124,16 @@ func (s *testCodecSuite) TestCodecKeyCompare(c *C) {
 			[]interface{}{2.12, "abcd", "af"},
 			1,
 		},
+		{
+			[]interface{}{mysql.Enum{Name: "b", Value: 1}},
+			[]interface{}{mysql.Enum{Name: "a", Value: 2}},
+			-1,
+		},
+		{
+			[]interface{}{mysql.Bit{Value: 5, Width: 8}},
+			[]interface{}{mysql.Bit{Value: 4, Width: 8}},
+			1,
+		},
 		{
 			[]interface{}{[]byte{0x01, 0x00}, []byte{0xFF}},
 			[]interface{}{[]byte{0x01, 0x00, 0xFF}},
@@ -407,3 +419,58 @@ func (s *testCodecSuite) TestBytes(c *C) {
 		c.Assert(ret, Equals, -t.Ret)
 	}
 }
+
+func (s *testCodecSuite) TestCodecKeyTimestamp(c *C) {
+	tm, err := mysql.ParseTime("2011-11-10 11:11:11", mysql.TypeTimestamp, 0)
+	c.Assert(err, IsNil)
+
+	// The same timestamp in different time zones has the same key.
+	tm2 := tm
+	tm2.Time = tm.Time.In(time.FixedZone("+08:00", 8*3600))
+	b1, err := EncodeKey(tm)
+	c.Assert(err, IsNil)
+	b2, err := EncodeKey(tm2)
+	c.Assert(err, IsNil)
+	c.Assert(b1, DeepEquals, b2)
+
+	args, err := DecodeKey(b1)
+	c.Assert(err, IsNil)
+	c.Assert(args[0], Equals, tm.Time.UTC().Format(mysql.TimeFormat))
+}
+
+func (s *testCodecSuite) TestCodecValue(c *C) {
+	tm, err := mysql.ParseTime("2011-11-10 11:11:11.999999", mysql.TypeDatetime, 6)
+	c.Assert(err, IsNil)
+	d, err := mysql.ParseDuration("12:34:56.5", 1)
+	c.Assert(err, IsNil)
+	dec, err := mysql.ParseDecimal("-12.340")
+	c.Assert(err, IsNil)
+	j, err := mysql.ParseJSON(`{"a": [1, 2.5, "b"]}`)
+	c.Assert(err, IsNil)
+
+	input := []interface{}{nil, int8(-1), int64(1), uint16(2), uint64(3), float32(1.5), float64(3.15),
+		true, false, "", "abc\x00", []byte{0xFF, 0x00}, tm, d,
+		mysql.Enum{Name: "a", Value: 1}, mysql.Set{Name: "a,b", Value: 3}, mysql.Bit{Value: 5, Width: 4}, j}
+	expect := []interface{}{nil, int64(-1), int64(1), uint64(2), uint64(3), float32(1.5), float64(3.15),
+		true, false, "", "abc\x00", []byte{0xFF, 0x00}, tm, d,
+		mysql.Enum{Name: "a", Value: 1}, mysql.Set{Name: "a,b", Value: 3}, mysql.Bit{Value: 5, Width: 4}, j}
+
+	b, err := EncodeValue(nil, input...)
+	c.Assert(err, IsNil)
+	b, err = EncodeValue(b, dec)
+	c.Assert(err, IsNil)
+	args, err := DecodeValue(b)
+	c.Assert(err, IsNil)
+	c.Assert(args[:len(expect)], DeepEquals, expect)
+	c.Assert(args[len(expect)].(mysql.Decimal).String(), Equals, dec.String())
+
+	args, err = DecodeValue(nil)
+	c.Assert(err, IsNil)
+	c.Assert(args, HasLen, 0)
+
+	_, err = EncodeValue(nil, struct{}{})
+	c.Assert(err, NotNil)
+
+	_, err = DecodeValue([]byte{0xFF})
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,261 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	mysql "github.com/Dong-Chan/alloydb/mysqldef"
+)
+
+const (
+	valueNilFlag byte = iota
+	valueIntFlag
+	valueUintFlag
+	valueFloat32Flag
+	valueFloat64Flag
+	valueBoolFlag
+	valueStringFlag
+	valueBytesFlag
+	valueTimeFlag
+	valueDurationFlag
+	valueDecimalFlag
+	valueEnumFlag
+	valueSetFlag
+	valueBitFlag
+	valueJSONFlag
+)
+
+// EncodeValue appends the encoded args to slice b and returns the appended slice.
+// Unlike EncodeKey, the encoded value can't be compared lexicographically,
+// but it keeps the type of every arg, so it can be used to save rows temporarily.
+// All signed integers are decoded as int64 and all unsigned integers are decoded as uint64.
+func EncodeValue(b []byte, args ...interface{}) ([]byte, error) {
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case nil:
+			b = append(b, valueNilFlag)
+		case int:
+			b = EncodeInt(append(b, valueIntFlag), int64(v))
+		case int8:
+			b = EncodeInt(append(b, valueIntFlag), int64(v))
+		case int16:
+			b = EncodeInt(append(b, valueIntFlag), int64(v))
+		case int32:
+			b = EncodeInt(append(b, valueIntFlag), int64(v))
+		case int64:
+			b = EncodeInt(append(b, valueIntFlag), v)
+		case uint:
+			b = EncodeUint(append(b, valueUintFlag), uint64(v))
+		case uint8:
+			b = EncodeUint(append(b, valueUintFlag), uint64(v))
+		case uint16:
+			b = EncodeUint(append(b, valueUintFlag), uint64(v))
+		case uint32:
+			b = EncodeUint(append(b, valueUintFlag), uint64(v))
+		case uint64:
+			b = EncodeUint(append(b, valueUintFlag), v)
+		case float32:
+			b = EncodeFloat(append(b, valueFloat32Flag), float64(v))
+		case float64:
+			b = EncodeFloat(append(b, valueFloat64Flag), v)
+		case bool:
+			if v {
+				b = append(b, valueBoolFlag, 1)
+			} else {
+				b = append(b, valueBoolFlag, 0)
+			}
+		case string:
+			b = EncodeBytes(append(b, valueStringFlag), []byte(v))
+		case []byte:
+			b = EncodeBytes(append(b, valueBytesFlag), v)
+		case mysql.Time:
+			t, err := v.Time.MarshalBinary()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			b = EncodeBytes(append(b, valueTimeFlag), t)
+			b = EncodeUint(b, uint64(v.Type))
+			b = EncodeInt(b, int64(v.Fsp))
+		case mysql.Duration:
+			b = EncodeInt(append(b, valueDurationFlag), int64(v.Duration))
+			b = EncodeInt(b, int64(v.Fsp))
+		case mysql.Decimal:
+			b = EncodeBytes(append(b, valueDecimalFlag), []byte(v.String()))
+		case mysql.Enum:
+			b = EncodeBytes(append(b, valueEnumFlag), []byte(v.Name))
+			b = EncodeUint(b, v.Value)
+		case mysql.Set:
+			b = EncodeBytes(append(b, valueSetFlag), []byte(v.Name))
+			b = EncodeUint(b, v.Value)
+		case mysql.Bit:
+			b = EncodeUint(append(b, valueBitFlag), v.Value)
+			b = EncodeInt(b, int64(v.Width))
+		case mysql.JSON:
+			j, err := v.MarshalBinary()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			b = EncodeBytes(append(b, valueJSONFlag), j)
+		default:
+			return nil, errors.Errorf("unsupport encode type %T", arg)
+		}
+	}
+	return b, nil
+}
+
+// DecodeValue decodes values from a byte slice generated with EncodeValue before.
+func DecodeValue(b []byte) ([]interface{}, error) {
+	var (
+		v   []interface{}
+		err error
+	)
+	for len(b) > 0 {
+		var val interface{}
+		flag := b[0]
+		b = b[1:]
+		switch flag {
+		case valueNilFlag:
+		case valueIntFlag:
+			b, val, err = DecodeInt(b)
+		case valueUintFlag:
+			b, val, err = DecodeUint(b)
+		case valueFloat32Flag:
+			var f float64
+			b, f, err = DecodeFloat(b)
+			val = float32(f)
+		case valueFloat64Flag:
+			b, val, err = DecodeFloat(b)
+		case valueBoolFlag:
+			if len(b) < 1 {
+				return nil, errors.Errorf("malformed encoded bool")
+			}
+			val, b = b[0] == 1, b[1:]
+		case valueStringFlag:
+			var r []byte
+			b, r, err = DecodeBytes(b)
+			val = string(r)
+		case valueBytesFlag:
+			// Copy the bytes, so the decoded value doesn't share memory with b.
+			var r []byte
+			b, r, err = DecodeBytes(b)
+			val = append([]byte{}, r...)
+		case valueTimeFlag:
+			val, b, err = decodeTime(b)
+		case valueDurationFlag:
+			val, b, err = decodeDuration(b)
+		case valueDecimalFlag:
+			var r []byte
+			if b, r, err = DecodeBytes(b); err == nil {
+				val, err = mysql.ParseDecimal(string(r))
+			}
+		case valueEnumFlag:
+			var e mysql.Enum
+			e.Name, e.Value, b, err = decodeNameValue(b)
+			val = e
+		case valueSetFlag:
+			var s mysql.Set
+			s.Name, s.Value, b, err = decodeNameValue(b)
+			val = s
+		case valueBitFlag:
+			val, b, err = decodeBit(b)
+		case valueJSONFlag:
+			var (
+				r []byte
+				j mysql.JSON
+			)
+			if b, r, err = DecodeBytes(b); err == nil {
+				err = j.UnmarshalBinary(r)
+				val = j
+			}
+		default:
+			return nil, errors.Errorf("invalid encoded value flag %v", flag)
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		v = append(v, val)
+	}
+	return v, nil
+}
+
+func decodeTime(b []byte) (mysql.Time, []byte, error) {
+	var (
+		t   mysql.Time
+		r   []byte
+		tp  uint64
+		fsp int64
+		err error
+	)
+	if b, r, err = DecodeBytes(b); err != nil {
+		return t, nil, errors.Trace(err)
+	}
+	if err = t.Time.UnmarshalBinary(r); err != nil {
+		return t, nil, errors.Trace(err)
+	}
+	if b, tp, err = DecodeUint(b); err != nil {
+		return t, nil, errors.Trace(err)
+	}
+	if b, fsp, err = DecodeInt(b); err != nil {
+		return t, nil, errors.Trace(err)
+	}
+	t.Type, t.Fsp = uint8(tp), int(fsp)
+	return t, b, nil
+}
+
+func decodeDuration(b []byte) (mysql.Duration, []byte, error) {
+	var (
+		d, fsp int64
+		err    error
+	)
+	if b, d, err = DecodeInt(b); err != nil {
+		return mysql.Duration{}, nil, errors.Trace(err)
+	}
+	if b, fsp, err = DecodeInt(b); err != nil {
+		return mysql.Duration{}, nil, errors.Trace(err)
+	}
+	return mysql.Duration{Duration: time.Duration(d), Fsp: int(fsp)}, b, nil
+}
+
+// decodeNameValue decodes the name and the number of an ENUM or SET value.
+func decodeNameValue(b []byte) (string, uint64, []byte, error) {
+	var (
+		r   []byte
+		v   uint64
+		err error
+	)
+	if b, r, err = DecodeBytes(b); err != nil {
+		return "", 0, nil, errors.Trace(err)
+	}
+	if b, v, err = DecodeUint(b); err != nil {
+		return "", 0, nil, errors.Trace(err)
+	}
+	return string(r), v, b, nil
+}
+
+func decodeBit(b []byte) (mysql.Bit, []byte, error) {
+	var (
+		v     uint64
+		width int64
+		err   error
+	)
+	if b, v, err = DecodeUint(b); err != nil {
+		return mysql.Bit{}, nil, errors.Trace(err)
+	}
+	if b, width, err = DecodeInt(b); err != nil {
+		return mysql.Bit{}, nil, errors.Trace(err)
+	}
+	return mysql.Bit{Value: v, Width: int(width)}, b, nil
+}
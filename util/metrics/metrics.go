@@ -0,0 +1,345 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics provides the counters and histograms of the engine, the registered metrics
+// are exported in the Prometheus text format.
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// DefBuckets are the default upper bounds in seconds of the buckets of the latency histograms.
+var DefBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Metric is a counter or histogram which can be registered.
+type Metric interface {
+	metricFamily() *family
+}
+
+// child is a metric of a family with the same label values.
+type child interface {
+	// writeText writes the samples of the child, labels is the formatted label pairs of the child.
+	writeText(w io.Writer, name string, labels []string)
+}
+
+// family is the metrics of the same name, they differ in the label values.
+type family struct {
+	name     string
+	help     string
+	typ      string
+	labels   []string
+	newChild func() child
+
+	mu       sync.Mutex
+	children map[string]child
+	// values is the label values of the children.
+	values map[string][]string
+}
+
+func newFamily(name, help, typ string, labels []string, newChild func() child) *family {
+	f := &family{
+		name:     name,
+		help:     help,
+		typ:      typ,
+		labels:   labels,
+		newChild: newChild,
+		children: make(map[string]child),
+		values:   make(map[string][]string),
+	}
+	if len(labels) == 0 {
+		// The only child is exported even if it is never changed.
+		f.withLabelValues(nil)
+	}
+	return f
+}
+
+func (f *family) metricFamily() *family {
+	return f
+}
+
+// withLabelValues returns the child with the label values, it is created for the first time.
+func (f *family) withLabelValues(values []string) child {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metric %s has %d labels, but %d values are given", f.name, len(f.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, ok := f.children[key]
+	if !ok {
+		c = f.newChild()
+		f.children[key] = c
+		f.values[key] = append([]string(nil), values...)
+	}
+	return c
+}
+
+func (f *family) writeText(w io.Writer) {
+	f.mu.Lock()
+	keys := make([]string, 0, len(f.children))
+	for key := range f.children {
+		keys = append(keys, key)
+	}
+	f.mu.Unlock()
+	sort.Strings(keys)
+
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.typ)
+	for _, key := range keys {
+		f.mu.Lock()
+		c, values := f.children[key], f.values[key]
+		f.mu.Unlock()
+		labels := make([]string, len(values))
+		for i, v := range values {
+			labels[i] = labelPair(f.labels[i], v)
+		}
+		c.writeText(w, f.name, labels)
+	}
+}
+
+// Counter is a value which only goes up, like the number of the executed statements.
+type Counter struct {
+	*family
+	v uint64
+}
+
+// NewCounter returns a counter without labels.
+func NewCounter(name, help string) *Counter {
+	c := &Counter{}
+	c.family = newFamily(name, help, "counter", nil, func() child { return c })
+	return c
+}
+
+// Inc increases c by 1.
+func (c *Counter) Inc() {
+	atomic.AddUint64(&c.v, 1)
+}
+
+// Add increases c by n.
+func (c *Counter) Add(n uint64) {
+	atomic.AddUint64(&c.v, n)
+}
+
+// Value returns the value of c.
+func (c *Counter) Value() uint64 {
+	return atomic.LoadUint64(&c.v)
+}
+
+func (c *Counter) writeText(w io.Writer, name string, labels []string) {
+	fmt.Fprintf(w, "%s%s %d\n", name, formatLabels(labels), c.Value())
+}
+
+// CounterVec is the counters of the same name partitioned by the label values.
+type CounterVec struct {
+	*family
+}
+
+// NewCounterVec returns a counter vector with the label names.
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{family: newFamily(name, help, "counter", labels, func() child { return &Counter{} })}
+}
+
+// WithLabelValues returns the counter with the label values, the number of the values must be
+// the number of the labels of v.
+func (v *CounterVec) WithLabelValues(values ...string) *Counter {
+	return v.withLabelValues(values).(*Counter)
+}
+
+// Histogram counts the observed values in buckets, like the latencies of the statements.
+type Histogram struct {
+	*family
+	// upperBounds is the sorted upper bounds of the buckets, the +Inf bucket is implicit.
+	upperBounds []float64
+
+	mu     sync.Mutex
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	upperBounds := append([]float64(nil), buckets...)
+	sort.Float64s(upperBounds)
+	return &Histogram{upperBounds: upperBounds, counts: make([]uint64, len(upperBounds))}
+}
+
+// NewHistogram returns a histogram without labels, buckets are the upper bounds of its buckets.
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	h := newHistogram(buckets)
+	h.family = newFamily(name, help, "histogram", nil, func() child { return h })
+	return h
+}
+
+// Observe adds v to h.
+func (h *Histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.upperBounds, v)
+	h.mu.Lock()
+	if i < len(h.counts) {
+		h.counts[i]++
+	}
+	h.count++
+	h.sum += v
+	h.mu.Unlock()
+}
+
+// ObserveSince adds the seconds elapsed since start to h.
+func (h *Histogram) ObserveSince(start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+func (h *Histogram) writeText(w io.Writer, name string, labels []string) {
+	h.mu.Lock()
+	counts := append([]uint64(nil), h.counts...)
+	count, sum := h.count, h.sum
+	h.mu.Unlock()
+
+	var cumulative uint64
+	for i, upperBound := range h.upperBounds {
+		cumulative += counts[i]
+		le := labelPair("le", formatFloat(upperBound))
+		fmt.Fprintf(w, "%s_bucket%s %d\n", name, formatLabels(append(labels[:len(labels):len(labels)], le)), cumulative)
+	}
+	le := labelPair("le", formatFloat(math.Inf(1)))
+	fmt.Fprintf(w, "%s_bucket%s %d\n", name, formatLabels(append(labels[:len(labels):len(labels)], le)), count)
+	fmt.Fprintf(w, "%s_sum%s %s\n", name, formatLabels(labels), formatFloat(sum))
+	fmt.Fprintf(w, "%s_count%s %d\n", name, formatLabels(labels), count)
+}
+
+// HistogramVec is the histograms of the same name partitioned by the label values.
+type HistogramVec struct {
+	*family
+}
+
+// NewHistogramVec returns a histogram vector with the label names, buckets are the upper bounds
+// of the buckets of the histograms.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return &HistogramVec{family: newFamily(name, help, "histogram", labels, func() child { return newHistogram(buckets) })}
+}
+
+// WithLabelValues returns the histogram with the label values, the number of the values must be
+// the number of the labels of v.
+func (v *HistogramVec) WithLabelValues(values ...string) *Histogram {
+	return v.withLabelValues(values).(*Histogram)
+}
+
+// Registry is a set of metrics with distinct names.
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// DefaultRegistry is the registry of the metrics of the engine, it is exported by Handler.
+var DefaultRegistry = NewRegistry()
+
+// Register adds m to r, it fails if a metric with the same name is registered.
+func (r *Registry) Register(m Metric) error {
+	f := m.metricFamily()
+	if f == nil {
+		// The children of the vectors have no families.
+		return errors.New("metric without name can't be registered")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.families[f.name]; ok {
+		return errors.Errorf("metric %s is already registered", f.name)
+	}
+	r.families[f.name] = f
+	return nil
+}
+
+// WriteText writes the metrics of r in the Prometheus text format, ordered by their names.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	families := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		families = append(families, f)
+	}
+	r.mu.Unlock()
+	sort.Sort(byName(families))
+
+	var buf bytes.Buffer
+	for _, f := range families {
+		f.writeText(&buf)
+	}
+	_, err := w.Write(buf.Bytes())
+	return errors.Trace(err)
+}
+
+// MustRegister adds the metrics to DefaultRegistry, it panics if any of them fails.
+func MustRegister(ms ...Metric) {
+	for _, m := range ms {
+		if err := DefaultRegistry.Register(m); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Handler returns the HTTP handler exporting the metrics of DefaultRegistry.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := DefaultRegistry.WriteText(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+type byName []*family
+
+func (s byName) Len() int           { return len(s) }
+func (s byName) Less(i, j int) bool { return s[i].name < s[j].name }
+func (s byName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func formatLabels(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(labels, ",") + "}"
+}
+
+func labelPair(name, value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+	return fmt.Sprintf(`%s="%s"`, name, value)
+}
+
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,93 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/pingcap/check"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testMetricsSuite{})
+
+type testMetricsSuite struct {
+}
+
+func (s *testMetricsSuite) TestWriteText(c *C) {
+	r := NewRegistry()
+	counter := NewCounter("test_counter_total", "A counter.")
+	counterVec := NewCounterVec("test_labeled_total", "A counter\nwith labels.", "type", "result")
+	histogram := NewHistogram("test_duration_seconds", "A histogram.", []float64{1, 0.1})
+	histogramVec := NewHistogramVec("test_labeled_duration_seconds", "A histogram with labels.", []float64{1}, "type")
+	for _, m := range []Metric{counter, counterVec, histogram, histogramVec} {
+		c.Assert(r.Register(m), IsNil)
+	}
+	c.Assert(r.Register(NewCounter("test_counter_total", "")), NotNil)
+	c.Assert(r.Register(NewCounterVec("test_vec_total", "", "type").WithLabelValues("a")), NotNil)
+
+	counter.Inc()
+	counter.Add(2)
+	c.Assert(counter.Value(), Equals, uint64(3))
+	counterVec.WithLabelValues("select", "ok").Inc()
+	counterVec.WithLabelValues("insert", `"quoted"`).Add(5)
+	c.Assert(counterVec.WithLabelValues("select", "ok").Value(), Equals, uint64(1))
+	for _, v := range []float64{0.05, 0.1, 0.5, 2} {
+		histogram.Observe(v)
+	}
+	histogramVec.WithLabelValues("get").Observe(0.5)
+
+	var buf bytes.Buffer
+	c.Assert(r.WriteText(&buf), IsNil)
+	c.Assert(buf.String(), Equals, `# HELP test_counter_total A counter.
+# TYPE test_counter_total counter
+test_counter_total 3
+# HELP test_duration_seconds A histogram.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{le="0.1"} 2
+test_duration_seconds_bucket{le="1"} 3
+test_duration_seconds_bucket{le="+Inf"} 4
+test_duration_seconds_sum 2.65
+test_duration_seconds_count 4
+# HELP test_labeled_duration_seconds A histogram with labels.
+# TYPE test_labeled_duration_seconds histogram
+test_labeled_duration_seconds_bucket{type="get",le="1"} 1
+test_labeled_duration_seconds_bucket{type="get",le="+Inf"} 1
+test_labeled_duration_seconds_sum{type="get"} 0.5
+test_labeled_duration_seconds_count{type="get"} 1
+# HELP test_labeled_total A counter\nwith labels.
+# TYPE test_labeled_total counter
+test_labeled_total{type="insert",result="\"quoted\""} 5
+test_labeled_total{type="select",result="ok"} 1
+`)
+
+	c.Assert(func() { counterVec.WithLabelValues("select") }, PanicMatches, ".*2 labels.*")
+}
+
+func (s *testMetricsSuite) TestHandler(c *C) {
+	counter := NewCounter("test_handler_total", "A counter of the default registry.")
+	MustRegister(counter)
+	counter.Inc()
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	c.Assert(w.Code, Equals, 200)
+	c.Assert(strings.Contains(w.Body.String(), "\ntest_handler_total 1\n"), IsTrue)
+}
@@ -0,0 +1,29 @@
+//
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlexec
+
+import (
+	"github.com/Dong-Chan/alloydb/context"
+	"github.com/Dong-Chan/alloydb/rset"
+)
+
+// RestrictedSQLExecutor executes SQL statements on behalf of the server itself.
+// Account management statements use it to read and write system tables such as
+// mysql.user, for example CREATE USER checks whether the account exists and then
+// inserts a row.
+// The statement runs in the current transaction of ctx, without privilege checks,
+// and only one statement is allowed in sql.
+type RestrictedSQLExecutor interface {
+	ExecRestrictedSQL(ctx context.Context, sql string) (rset.Recordset, error)
+}
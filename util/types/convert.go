@@ -283,6 +283,15 @@ func ToInt64(value interface{}) (int64, error) {
 		return v.ToNumber().Round(0).IntPart(), nil
 	case mysql.Decimal:
 		return v.Round(0).IntPart(), nil
+	case mysql.Enum:
+		return int64(v.Value), nil
+	case mysql.Set:
+		return int64(v.Value), nil
+	case mysql.Bit:
+		return int64(v.Value), nil
+	case mysql.JSON:
+		f, _ := v.ToNumber()
+		return int64(RoundFloat(f)), nil
 	default:
 		return 0, errors.Errorf("cannot convert %v(type %T) to int64", value, value)
 	}
@@ -333,6 +342,15 @@ func ToFloat64(value interface{}) (float64, error) {
 	case mysql.Decimal:
 		vv, _ := v.Float64()
 		return vv, nil
+	case mysql.Enum:
+		return v.ToNumber(), nil
+	case mysql.Set:
+		return v.ToNumber(), nil
+	case mysql.Bit:
+		return v.ToNumber(), nil
+	case mysql.JSON:
+		f, _ := v.ToNumber()
+		return f, nil
 	default:
 		return 0, errors.Errorf("cannot convert %v(type %T) to float64", value, value)
 	}
@@ -349,6 +367,15 @@ func ToDecimal(value interface{}) (mysql.Decimal, error) {
 		return mysql.ConvertToDecimal(0)
 	case []byte:
 		return mysql.ConvertToDecimal(string(v))
+	case mysql.Enum:
+		return mysql.NewDecimalFromUint(v.Value, 0), nil
+	case mysql.Set:
+		return mysql.NewDecimalFromUint(v.Value, 0), nil
+	case mysql.Bit:
+		return mysql.NewDecimalFromUint(v.Value, 0), nil
+	case mysql.JSON:
+		f, _ := v.ToNumber()
+		return mysql.NewDecimalFromFloat(f), nil
 	default:
 		return mysql.ConvertToDecimal(value)
 	}
@@ -396,6 +423,14 @@ func ToString(value interface{}) (string, error) {
 		return v.String(), nil
 	case mysql.Decimal:
 		return v.String(), nil
+	case mysql.Enum:
+		return v.String(), nil
+	case mysql.Set:
+		return v.String(), nil
+	case mysql.Bit:
+		return v.ToString(), nil
+	case mysql.JSON:
+		return v.String(), nil
 	default:
 		return "", errors.Errorf("cannot convert %v(type %T) to string", value, value)
 	}
@@ -459,6 +494,15 @@ func ToBool(value interface{}) (int8, error) {
 	case mysql.Decimal:
 		vv, _ := v.Float64()
 		isZero = (vv == 0)
+	case mysql.Enum:
+		isZero = (v.Value == 0)
+	case mysql.Set:
+		isZero = (v.Value == 0)
+	case mysql.Bit:
+		isZero = (v.Value == 0)
+	case mysql.JSON:
+		f, _ := v.ToNumber()
+		isZero = (f == 0)
 	default:
 		return 0, errors.Errorf("cannot convert %v(type %T) to bool", value, value)
 	}
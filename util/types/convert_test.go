@@ -208,6 +208,9 @@ func (s *testTypeConvertSuite) TestConvertToInt64(c *C) {
 	v, err := Convert(3.1415926, ft)
 	c.Assert(err, IsNil)
 	testToInt64(c, v, int64(3))
+	testToInt64(c, mysql.Enum{Name: "a", Value: 2}, int64(2))
+	testToInt64(c, mysql.Set{Name: "a,b", Value: 3}, int64(3))
+	testToInt64(c, mysql.Bit{Value: 5, Width: 3}, int64(5))
 
 	_, err = ToInt64(&invalidMockType{})
 	c.Assert(err, NotNil)
@@ -252,6 +255,12 @@ func (s *testTypeConvertSuite) TestConvertToFloat64(c *C) {
 	v, err := Convert(3.1415926, ft)
 	c.Assert(err, IsNil)
 	testToFloat64(c, v, float64(3.14159))
+	testToFloat64(c, mysql.Enum{Name: "a", Value: 2}, float64(2))
+	testToFloat64(c, mysql.Set{Name: "a,b", Value: 3}, float64(3))
+	testToFloat64(c, mysql.Bit{Value: 5, Width: 3}, float64(5))
+	j, err := mysql.ParseJSON(`2.5`)
+	c.Assert(err, IsNil)
+	testToFloat64(c, j, float64(2.5))
 
 	_, err = ToFloat64(&invalidMockType{})
 	c.Assert(err, NotNil)
@@ -294,6 +303,12 @@ func (s *testTypeConvertSuite) TestConvertToString(c *C) {
 	v, err := Convert(3.1415926, ft)
 	c.Assert(err, IsNil)
 	testToString(c, v, "3.14159")
+	testToString(c, mysql.Enum{Name: "a", Value: 2}, "a")
+	testToString(c, mysql.Set{Name: "a,b", Value: 3}, "a,b")
+	testToString(c, mysql.Bit{Value: 0x4142, Width: 16}, "AB")
+	j, err := mysql.ParseJSON(`{"b":[1,"a"],"a":null}`)
+	c.Assert(err, IsNil)
+	testToString(c, j, `{"a": null, "b": [1, "a"]}`)
 
 	_, err = ToString(&invalidMockType{})
 	c.Assert(err, NotNil)
@@ -61,6 +61,7 @@ var type2Str = map[byte]string{
 	mysql.TypeFloat:      "FLOAT",
 	mysql.TypeGeometry:   "GEOMETRY",
 	mysql.TypeInt24:      "MEDIUMINT",
+	mysql.TypeJSON:       "JSON",
 	mysql.TypeLong:       "INT",
 	mysql.TypeLonglong:   "BIGINT",
 	mysql.TypeLongBlob:   "LONGTEXT",
@@ -142,6 +143,14 @@ func TypeToStr(tp byte, binary bool) string {
 		return "date"
 	case mysql.TypeTimestamp:
 		return "timestamp"
+	case mysql.TypeEnum:
+		return "enum"
+	case mysql.TypeSet:
+		return "set"
+	case mysql.TypeBit:
+		return "bit"
+	case mysql.TypeJSON:
+		return "json"
 	default:
 		log.Errorf("unkown type %d, binary %v", tp, binary)
 	}
@@ -309,6 +318,8 @@ func Compare(a, b interface{}) int {
 			}
 
 			return 1
+		case []byte:
+			return bytes.Compare([]byte(x), y)
 		default:
 			panic("should never happen")
 		}
@@ -318,6 +329,8 @@ func Compare(a, b interface{}) int {
 			return 1
 		case []byte:
 			return bytes.Compare(x, y)
+		case string:
+			return bytes.Compare(x, []byte(y))
 		default:
 			panic("should never happen")
 		}
@@ -360,11 +373,87 @@ func Compare(a, b interface{}) int {
 			panic(fmt.Sprintf("should never happen, err: %v", err))
 		}
 		return x.Cmp(y)
+	case mysql.Enum, mysql.Set, mysql.Bit:
+		return compareEnumLikeWith(a, b)
+	case mysql.JSON:
+		if b == nil {
+			return 1
+		}
+		y, err := mysql.CreateJSON(b)
+		if err != nil {
+			panic(fmt.Sprintf("should never happen, err: %v", err))
+		}
+		return mysql.CompareJSON(x, y)
 	default:
 		panic("should never happen")
 	}
 }
 
+// enumLikeValue returns the number of an ENUM, SET or BIT value v.
+func enumLikeValue(v interface{}) (uint64, bool) {
+	switch x := v.(type) {
+	case mysql.Enum:
+		return x.Value, true
+	case mysql.Set:
+		return x.Value, true
+	case mysql.Bit:
+		return x.Value, true
+	}
+	return 0, false
+}
+
+// compareEnumLikeWith compares an ENUM, SET or BIT value a with b.
+// The values are ordered by their numbers, like the ENUM values are ordered by their index numbers,
+// but they are compared with a string by their string values.
+func compareEnumLikeWith(a, b interface{}) int {
+	x, _ := enumLikeValue(a)
+	switch y := b.(type) {
+	case nil:
+		return 1
+	case string:
+		s, _ := ToString(a)
+		return CompareString(s, y)
+	case uint8, uint16, uint32, uint64, uint:
+		return compareUint64With(x, b)
+	case int8, int16, int32, int64, int:
+		n, _ := ToInt64(y)
+		return -CompareInteger(n, x)
+	}
+	if y, ok := enumLikeValue(b); ok {
+		return CompareUint64(x, y)
+	}
+	panic("should never happen")
+}
+
+// convertEnumLike converts an ENUM, SET or BIT value v to be compared or computed with other.
+// It is the string value if other is a string, or both are ENUM or SET values, like MySQL does,
+// otherwise it is the number.
+func convertEnumLike(v, other interface{}) interface{} {
+	n, ok := enumLikeValue(v)
+	if !ok {
+		return v
+	}
+	switch other.(type) {
+	case string:
+		s, _ := ToString(v)
+		return s
+	case mysql.Enum, mysql.Set:
+		if _, ok := v.(mysql.Bit); !ok {
+			return v.(fmt.Stringer).String()
+		}
+	}
+	return n
+}
+
+// convertBinary converts the binary string v to a string, the string compares byte by byte
+// and is converted to a number like the binary string.
+func convertBinary(v interface{}) interface{} {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return v
+}
+
 // TODO: collate should return errors from Compare.
 func collate(x, y []interface{}) (r int) {
 	nx, ny := len(x), len(y)
@@ -410,9 +499,9 @@ func IsOrderedType(v interface{}) (y interface{}, r bool, err error) {
 	case float32, float64,
 		int, int8, int16, int32, int64,
 		uint, uint8, uint16, uint32, uint64,
-		string, mysql.Decimal:
+		string, []byte, mysql.Decimal:
 		return v, true, nil
-	case mysql.Time, mysql.Duration:
+	case mysql.Time, mysql.Duration, mysql.Enum, mysql.Set, mysql.Bit, mysql.JSON:
 		return x, true, nil
 	}
 
@@ -447,7 +536,10 @@ func Clone(from interface{}) (interface{}, error) {
 		return x, nil
 	case mysql.Duration:
 		return x, nil
-	case mysql.Decimal:
+	case mysql.Decimal, mysql.Enum, mysql.Set, mysql.Bit:
+		return x, nil
+	case mysql.JSON:
+		// JSON value is never modified.
 		return x, nil
 	default:
 		log.Error(reflect.TypeOf(from))
@@ -455,6 +547,38 @@ func Clone(from interface{}) (interface{}, error) {
 	}
 }
 
+// EstimateSize returns the approximate memory size of v in bytes.
+// It is used to track the memory usage of the rows kept temporarily, e.g. by sort and group by.
+func EstimateSize(v interface{}) int64 {
+	// Every value is saved in an interface, which has two words.
+	const interfaceSize = 16
+	switch x := v.(type) {
+	case string:
+		return interfaceSize + 16 + int64(len(x))
+	case []byte:
+		return interfaceSize + 24 + int64(cap(x))
+	case []interface{}:
+		size := int64(interfaceSize + 24)
+		for _, vv := range x {
+			size += EstimateSize(vv)
+		}
+		return size
+	case mysql.Time:
+		return interfaceSize + 40
+	case mysql.Decimal:
+		return interfaceSize + 48
+	case mysql.Enum:
+		return interfaceSize + 24 + int64(len(x.Name))
+	case mysql.Set:
+		return interfaceSize + 24 + int64(len(x.Name))
+	case mysql.JSON:
+		b, _ := x.MarshalBinary()
+		return interfaceSize + 16 + 2*int64(len(b))
+	default:
+		return interfaceSize + 8
+	}
+}
+
 func convergeType(a interface{}, hasDecimal, hasFloat *bool) (x interface{}) {
 	x = a
 	switch v := a.(type) {
@@ -499,11 +623,15 @@ func convergeType(a interface{}, hasDecimal, hasFloat *bool) (x interface{}) {
 }
 
 // Coerce changes type.
+// The binary strings are converted to strings, the ENUM, SET and BIT values are converted
+// to strings or numbers first.
 // If a or b is Decimal, changes the both to Decimal.
 // If a or b is Float, changes the both to Float.
 func Coerce(a, b interface{}) (x, y interface{}) {
 	var hasDecimal bool
 	var hasFloat bool
+	a, b = convertBinary(a), convertBinary(b)
+	a, b = convertEnumLike(a, b), convertEnumLike(b, a)
 	x = convergeType(a, &hasDecimal, &hasFloat)
 	y = convergeType(b, &hasDecimal, &hasFloat)
 	if hasDecimal {
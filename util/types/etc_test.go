@@ -161,6 +161,8 @@ func (s *testTypeEtcSuite) TestCompare(c *C) {
 
 	checkCompare(c, []byte(""), nil, 1)
 	checkCompare(c, []byte(""), []byte("sff"), -1)
+	checkCompare(c, []byte("abc"), "abd", -1)
+	checkCompare(c, "abc", []byte("abc"), 0)
 
 	checkCompare(c, mysql.Time{}, nil, 1)
 	checkCompare(c, mysql.Time{}, mysql.Time{time.Now(), 1, 3}, -1)
@@ -172,6 +174,22 @@ func (s *testTypeEtcSuite) TestCompare(c *C) {
 	checkCompare(c, mysql.Duration{time.Duration(34), 2}, mysql.Duration{time.Duration(34), 2}, 0)
 
 	checkCompare(c, mysql.Decimal{}, mysql.Decimal{}, 0)
+
+	checkCompare(c, mysql.Enum{Name: "b", Value: 1}, nil, 1)
+	checkCompare(c, mysql.Enum{Name: "b", Value: 1}, mysql.Enum{Name: "a", Value: 2}, -1)
+	checkCompare(c, mysql.Enum{Name: "b", Value: 1}, "a", 1)
+	checkCompare(c, mysql.Enum{Name: "b", Value: 1}, 1, 0)
+	checkCompare(c, mysql.Set{Name: "a,b", Value: 3}, "a,b", 0)
+	checkCompare(c, mysql.Set{Name: "a,b", Value: 3}, uint64(4), -1)
+	checkCompare(c, mysql.Bit{Value: 65, Width: 8}, "A", 0)
+	checkCompare(c, mysql.Bit{Value: 65, Width: 8}, 64, 1)
+
+	j, err := mysql.ParseJSON(`"abc"`)
+	c.Assert(err, IsNil)
+	checkCompare(c, j, nil, 1)
+	checkCompare(c, j, "abc", 0)
+	checkCompare(c, j, int64(100), 1)
+	checkCompare(c, j, j, 0)
 }
 
 func checkCollate(c *C, x, y []interface{}, expect int) {
@@ -273,6 +291,13 @@ func (s *testTypeEtcSuite) TestCoerce(c *C) {
 	checkCoerce(c, mysql.NewDecimalFromInt(1, 0), false)
 	checkCoerce(c, float32(3.4), mysql.NewDecimalFromUint(1, 0))
 	checkCoerce(c, int32(43), 3.235)
+	checkCoerce(c, mysql.Enum{Name: "a", Value: 1}, int64(1))
+	checkCoerce(c, mysql.Bit{Value: 1, Width: 1}, 1.5)
+
+	// The binary strings are coerced to strings.
+	x, y := Coerce([]byte("abc"), int64(1))
+	c.Assert(x, Equals, "abc")
+	c.Assert(y, Equals, int64(1))
 }
 
 func (s *testTypeEtcSuite) TestIsOrderedType(c *C) {
@@ -289,6 +314,9 @@ func (s *testTypeEtcSuite) TestIsOrderedType(c *C) {
 	_, r, err = IsOrderedType(mysql.Duration{time.Duration(0), 0})
 	c.Assert(err, IsNil)
 	c.Assert(r, IsTrue)
+	_, r, err = IsOrderedType([]byte("abc"))
+	c.Assert(err, IsNil)
+	c.Assert(r, IsTrue)
 }
 
 func (s *testTypeEtcSuite) TestMaxFloat(c *C) {
@@ -345,3 +373,14 @@ func (s *testTypeEtcSuite) TestTruncate(c *C) {
 		c.Assert(f, Equals, t.Expect)
 	}
 }
+
+func (s *testTypeEtcSuite) TestEstimateSize(c *C) {
+	c.Assert(EstimateSize(nil), Equals, EstimateSize(int64(1)))
+	c.Assert(EstimateSize("abcd"), Greater, EstimateSize(""))
+	c.Assert(EstimateSize([]byte("abcd")), Greater, EstimateSize([]byte{}))
+
+	row := []interface{}{int64(1), "abc", mysql.Decimal{}}
+	size := EstimateSize(row)
+	c.Assert(size, Greater, EstimateSize(row[0])+EstimateSize(row[1])+EstimateSize(row[2]))
+	c.Assert(EstimateSize(append(row, "def")), Greater, size)
+}
@@ -34,6 +34,8 @@ type FieldType struct {
 	Decimal int
 	Charset string
 	Collate string
+	// Elems is the element list for enum and set type.
+	Elems []string
 }
 
 // NewFieldType returns a FieldType,
@@ -51,7 +53,9 @@ func NewFieldType(tp byte) *FieldType {
 func (ft *FieldType) String() string {
 	ts := FieldTypeToStr(ft.Tp, ft.Charset)
 	ans := []string{ts}
-	if ft.Flen != UnspecifiedLength {
+	if ft.Tp == mysql.TypeEnum || ft.Tp == mysql.TypeSet {
+		ans = append(ans, ElemsToStr(ft.Elems))
+	} else if ft.Flen != UnspecifiedLength {
 		if ft.Decimal == UnspecifiedLength {
 			ans = append(ans, fmt.Sprintf("(%d)", ft.Flen))
 		} else {
@@ -70,12 +74,22 @@ func (ft *FieldType) String() string {
 		ans = append(ans, "BINARY")
 	}
 	if ft.Charset != "" && ft.Charset != charset.CharsetBin &&
-		(IsTypeChar(ft.Tp) || IsTypeBlob(ft.Tp)) {
+		(IsTypeChar(ft.Tp) || IsTypeBlob(ft.Tp) || ft.Tp == mysql.TypeEnum || ft.Tp == mysql.TypeSet) {
 		ans = append(ans, fmt.Sprintf("CHARACTER SET %s", ft.Charset))
 	}
 	if ft.Collate != "" && ft.Collate != charset.CharsetBin &&
-		(IsTypeChar(ft.Tp) || IsTypeBlob(ft.Tp)) {
+		(IsTypeChar(ft.Tp) || IsTypeBlob(ft.Tp) || ft.Tp == mysql.TypeEnum || ft.Tp == mysql.TypeSet) {
 		ans = append(ans, fmt.Sprintf("COLLATE %s", ft.Collate))
 	}
 	return strings.Join(ans, " ")
 }
+
+// ElemsToStr returns the element list of an enum or set type, like ('a','b').
+// The single quotes in the elements are doubled.
+func ElemsToStr(elems []string) string {
+	strs := make([]string, len(elems))
+	for i, e := range elems {
+		strs[i] = "'" + strings.Replace(e, "'", "''", -1) + "'"
+	}
+	return "(" + strings.Join(strs, ",") + ")"
+}
@@ -49,4 +49,17 @@ func (s *testFieldTypeSuite) TestFieldType(c *C) {
 	ft.Flen = 10
 	ft.Flag |= mysql.BinaryFlag
 	c.Assert(ft.String(), Equals, "VARCHAR (10) BINARY")
+
+	ft = NewFieldType(mysql.TypeEnum)
+	ft.Elems = []string{"a", "b'c"}
+	c.Assert(ft.String(), Equals, "ENUM ('a','b''c')")
+
+	ft = NewFieldType(mysql.TypeSet)
+	ft.Elems = []string{"x", "y"}
+	ft.Charset = "utf8"
+	c.Assert(ft.String(), Equals, "SET ('x','y') CHARACTER SET utf8")
+
+	ft = NewFieldType(mysql.TypeBit)
+	ft.Flen = 8
+	c.Assert(ft.String(), Equals, "BIT (8)")
 }